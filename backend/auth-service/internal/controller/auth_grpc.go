@@ -2,6 +2,8 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	pb "github.com/jaliks17/ffffforum/backend/proto"
 
@@ -63,6 +65,9 @@ func (c *AuthGRPCController) SignIn(
 	}
 
 	session, err := c.authUC.Login(ctx, loginReq)
+	if errors.Is(err, usecase.ErrUserSuspended) {
+		return nil, status.Error(codes.PermissionDenied, "user is suspended")
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "login failed: %v", err)
 	}
@@ -109,9 +114,14 @@ func (c *AuthGRPCController) ValidateSession(
 		return nil, status.Error(codes.InvalidArgument, "invalid token claims")
 	}
 
+	userID := int64(claims["user_id"].(float64))
+	if err := c.checkSuspension(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	return &pb.ValidateSessionResponse{
 		Valid:    true,
-		UserId:   int64(claims["user_id"].(float64)),
+		UserId:   userID,
 		UserRole: claims["role"].(string),
 	}, nil
 }
@@ -127,6 +137,9 @@ func (c *AuthGRPCController) Login(ctx context.Context, req *pb.LoginRequest) (*
 	}
 
 	session, err := c.authUC.Login(ctx, loginReq)
+	if errors.Is(err, usecase.ErrUserSuspended) {
+		return nil, status.Error(codes.PermissionDenied, "user is suspended")
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "login failed: %v", err)
 	}
@@ -174,9 +187,14 @@ func (c *AuthGRPCController) ValidateToken(ctx context.Context, req *pb.Validate
 		return nil, status.Error(codes.InvalidArgument, "invalid token claims")
 	}
 
+	userID := int64(claims["user_id"].(float64))
+	if err := c.checkSuspension(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	return &pb.ValidateSessionResponse{
 		Valid:    true,
-		UserId:   int64(claims["user_id"].(float64)),
+		UserId:   userID,
 		UserRole: claims["role"].(string),
 	}, nil
 }
@@ -212,6 +230,86 @@ func (c *AuthGRPCController) Logout(ctx context.Context, req *pb.LogoutRequest)
 	}, nil
 }
 
+func (c *AuthGRPCController) GetUsersByUsernames(
+	ctx context.Context,
+	req *pb.GetUsersByUsernamesRequest,
+) (*pb.GetUsersByUsernamesResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	users, err := c.authUC.GetUsersByUsernames(ctx, req.Usernames)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "lookup failed: %v", err)
+	}
+
+	resp := &pb.GetUsersByUsernamesResponse{Users: make([]*pb.User, 0, len(users))}
+	for i := range users {
+		resp.Users = append(resp.Users, convertUserToProto(&users[i]))
+	}
+
+	return resp, nil
+}
+
+// SuspendUser блокирует пользователя или снимает блокировку. Права проверяются по токену модератора.
+func (c *AuthGRPCController) SuspendUser(
+	ctx context.Context,
+	req *pb.SuspendUserRequest,
+) (*pb.SuspendUserResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	token, err := c.authUC.ValidateToken(req.Token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid token claims")
+	}
+	actorID := int64(claims["user_id"].(float64))
+	if err := c.checkSuspension(ctx, actorID); err != nil {
+		return nil, err
+	}
+
+	var until time.Time
+	if req.Until != nil {
+		until = req.Until.AsTime()
+	}
+
+	suspension, err := c.authUC.SuspendUser(ctx, actorID, claims["role"].(string), req.UserId, until, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrForbidden):
+			return nil, status.Error(codes.PermissionDenied, "permission denied")
+		case errors.Is(err, usecase.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		default:
+			return nil, status.Errorf(codes.Internal, "suspend failed: %v", err)
+		}
+	}
+
+	resp := &pb.SuspendUserResponse{UserId: suspension.UserID}
+	if suspension.Until != nil {
+		resp.SuspendedUntil = timestamppb.New(*suspension.Until)
+	}
+	return resp, nil
+}
+
+// checkSuspension не пропускает токены заблокированных пользователей, даже если срок токена не истек
+func (c *AuthGRPCController) checkSuspension(ctx context.Context, userID int64) error {
+	err := c.authUC.CheckSuspension(ctx, userID)
+	if errors.Is(err, usecase.ErrUserSuspended) {
+		return status.Error(codes.Unauthenticated, "user is suspended")
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "suspension check failed: %v", err)
+	}
+	return nil
+}
+
 func convertUserToProto(user *entity.User) *pb.User {
 	if user == nil {
 		return nil
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/jaliks17/ffffforum/backend/auth-service/internal/entity"
 	"github.com/jaliks17/ffffforum/backend/auth-service/internal/usecase"
@@ -11,6 +12,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestAuthGRPCController_SignUp(t *testing.T) {
@@ -249,10 +253,30 @@ func TestAuthGRPCController_ValidateSession(t *testing.T) {
 					},
 				}
 				mockUC.On("ValidateToken", "valid-token").Return(token, nil)
+				mockUC.On("CheckSuspension", mock.Anything, int64(1)).Return(nil)
 			},
 			expectedError: false,
 			expectedValid: true,
 		},
+		{
+			name: "suspended user",
+			req: &pb.ValidateSessionRequest{
+				Token: "suspended-token",
+			},
+			mockSetup: func() {
+				token := &jwt.Token{
+					Claims: jwt.MapClaims{
+						"user_id":  float64(2),
+						"username": "banned",
+						"role":     "user",
+					},
+				}
+				mockUC.On("ValidateToken", "suspended-token").Return(token, nil)
+				mockUC.On("CheckSuspension", mock.Anything, int64(2)).Return(usecase.ErrUserSuspended)
+			},
+			expectedError: true,
+			expectedValid: false,
+		},
 		{
 			name:           "empty request",
 			req:            nil,
@@ -468,10 +492,30 @@ func TestAuthGRPCController_ValidateToken(t *testing.T) {
 					},
 				}
 				mockUC.On("ValidateToken", "valid-token").Return(token, nil)
+				mockUC.On("CheckSuspension", mock.Anything, int64(1)).Return(nil)
 			},
 			expectedError: false,
 			expectedValid: true,
 		},
+		{
+			name: "suspended user",
+			req: &pb.ValidateTokenRequest{
+				Token: "suspended-token",
+			},
+			mockSetup: func() {
+				token := &jwt.Token{
+					Claims: jwt.MapClaims{
+						"user_id":  float64(2),
+						"username": "banned",
+						"role":     "user",
+					},
+				}
+				mockUC.On("ValidateToken", "suspended-token").Return(token, nil)
+				mockUC.On("CheckSuspension", mock.Anything, int64(2)).Return(usecase.ErrUserSuspended)
+			},
+			expectedError: true,
+			expectedValid: false,
+		},
 		{
 			name:           "empty request",
 			req:            nil,
@@ -632,3 +676,71 @@ func TestAuthGRPCController_Logout(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthGRPCController_GetUsersByUsernames(t *testing.T) {
+	mockUC := new(MockAuthUseCase)
+	ctrl := NewAuthGRPCController(mockUC)
+
+	t.Run("users resolved", func(t *testing.T) {
+		mockUC.On("GetUsersByUsernames", mock.Anything, []string{"alice", "ghost"}).
+			Return([]entity.User{{ID: 1, Username: "alice", Role: "user"}}, nil).Once()
+
+		resp, err := ctrl.GetUsersByUsernames(context.Background(), &pb.GetUsersByUsernamesRequest{Usernames: []string{"alice", "ghost"}})
+		assert.NoError(t, err)
+		if assert.Len(t, resp.Users, 1) {
+			assert.Equal(t, int64(1), resp.Users[0].Id)
+			assert.Equal(t, "alice", resp.Users[0].Username)
+		}
+	})
+
+	t.Run("empty request", func(t *testing.T) {
+		resp, err := ctrl.GetUsersByUsernames(context.Background(), nil)
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("usecase error", func(t *testing.T) {
+		mockUC.On("GetUsersByUsernames", mock.Anything, []string{"alice"}).Return(nil, assert.AnError).Once()
+
+		resp, err := ctrl.GetUsersByUsernames(context.Background(), &pb.GetUsersByUsernamesRequest{Usernames: []string{"alice"}})
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestAuthGRPCController_SuspendUser(t *testing.T) {
+	mockUC := new(MockAuthUseCase)
+	ctrl := NewAuthGRPCController(mockUC)
+
+	token := &jwt.Token{Claims: jwt.MapClaims{"user_id": float64(1), "username": "mod", "role": "moderator"}}
+	mockUC.On("ValidateToken", "mod-token").Return(token, nil)
+	mockUC.On("CheckSuspension", mock.Anything, int64(1)).Return(nil)
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("user suspended", func(t *testing.T) {
+		mockUC.On("SuspendUser", mock.Anything, int64(1), "moderator", int64(5), until, "spam").
+			Return(&entity.Suspension{UserID: 5, Until: &until, Reason: "spam"}, nil).Once()
+
+		resp, err := ctrl.SuspendUser(context.Background(), &pb.SuspendUserRequest{
+			Token: "mod-token", UserId: 5, Until: timestamppb.New(until), Reason: "spam",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), resp.UserId)
+		assert.True(t, resp.SuspendedUntil.AsTime().Equal(until))
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		mockUC.On("SuspendUser", mock.Anything, int64(1), "moderator", int64(9), time.Time{}, "").
+			Return(nil, usecase.ErrForbidden).Once()
+
+		resp, err := ctrl.SuspendUser(context.Background(), &pb.SuspendUserRequest{Token: "mod-token", UserId: 9})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Nil(t, resp)
+	})
+
+	t.Run("empty request", func(t *testing.T) {
+		resp, err := ctrl.SuspendUser(context.Background(), nil)
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -75,6 +76,7 @@ func (c *AuthHTTPController) SignUp(ctx *gin.Context) {
 // @Success 200 {object} map[string]interface{} "access_token, refresh_token, user"
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
 // @Router /api/v1/auth/signin [post]
 func (c *AuthHTTPController) SignIn(ctx *gin.Context) {
 	var req SignInRequest
@@ -89,6 +91,10 @@ func (c *AuthHTTPController) SignIn(ctx *gin.Context) {
 	}
 
 	session, err := c.authUC.Login(ctx.Request.Context(), loginReq)
+	if errors.Is(err, usecase.ErrUserSuspended) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "user is suspended"})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/jaliks17/ffffforum/backend/auth-service/internal/entity"
 	"github.com/jaliks17/ffffforum/backend/auth-service/internal/usecase"
@@ -56,6 +57,27 @@ func (m *MockAuthUseCase) GetUserByID(ctx context.Context, id int64) (*entity.Us
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockAuthUseCase) GetUsersByUsernames(ctx context.Context, usernames []string) ([]entity.User, error) {
+	args := m.Called(ctx, usernames)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.User), args.Error(1)
+}
+
+func (m *MockAuthUseCase) SuspendUser(ctx context.Context, actorID int64, actorRole string, userID int64, until time.Time, reason string) (*entity.Suspension, error) {
+	args := m.Called(ctx, actorID, actorRole, userID, until, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Suspension), args.Error(1)
+}
+
+func (m *MockAuthUseCase) CheckSuspension(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
 func (m *MockAuthUseCase) ValidateToken(token string) (*jwt.Token, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	"github.com/jaliks17/ffffforum/backend/auth-service/internal/entity"
 	"github.com/jaliks17/ffffforum/backend/auth-service/internal/usecase"
@@ -29,6 +30,18 @@ func (m *AuthServiceMock) GetUserByID(ctx context.Context, id int64) (*entity.Us
 	return nil, nil
 }
 
+func (m *AuthServiceMock) GetUsersByUsernames(ctx context.Context, usernames []string) ([]entity.User, error) {
+	return nil, nil
+}
+
+func (m *AuthServiceMock) SuspendUser(ctx context.Context, actorID int64, actorRole string, userID int64, until time.Time, reason string) (*entity.Suspension, error) {
+	return nil, nil
+}
+
+func (m *AuthServiceMock) CheckSuspension(ctx context.Context, userID int64) error {
+	return nil
+}
+
 func (m *AuthServiceMock) ValidateToken(token string) (*jwt.Token, error) {
 	return nil, nil
 }
@@ -16,10 +16,23 @@ type User struct {
 }
 
 const (
-	RoleAdmin Role = "admin"
-	RoleUser  Role = "user"
+	RoleAdmin     Role = "admin"
+	RoleModerator Role = "moderator"
+	RoleUser      Role = "user"
 )
 
+// Suspension - блокировка пользователя модератором. Until = nil - блокировки нет.
+type Suspension struct {
+	UserID int64      `json:"user_id" db:"id"`
+	Until  *time.Time `json:"suspended_until,omitempty" db:"suspended_until"`
+	Reason string     `json:"reason,omitempty" db:"suspension_reason"`
+}
+
+// Active сообщает, действует ли блокировка в момент now
+func (s *Suspension) Active(now time.Time) bool {
+	return s != nil && s.Until != nil && s.Until.After(now)
+}
+
 type UserRegister struct {
 	Username string `json:"username" binding:"required,min=3"`
 	Password string `json:"password" binding:"required,min=8"`
@@ -8,6 +8,7 @@ import (
 
 	"github.com/jaliks17/ffffforum/backend/auth-service/internal/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type IUserRepository interface {
@@ -15,6 +16,9 @@ type IUserRepository interface {
 	GetByID(ctx context.Context, id int64) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+	GetByUsernames(ctx context.Context, usernames []string) ([]entity.User, error)
+	GetSuspension(ctx context.Context, userID int64) (*entity.Suspension, error)
+	SetSuspension(ctx context.Context, userID int64, until *time.Time, reason string) error
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id int64) error
 }
@@ -101,6 +105,71 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*e
 	return &user, nil
 }
 
+// GetByUsernames возвращает найденных пользователей; отсутствующие имена просто пропускаются
+func (r *UserRepository) GetByUsernames(ctx context.Context, usernames []string) ([]entity.User, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, username, role, created_at, updated_at
+		FROM users
+		WHERE username = ANY($1)
+	`
+
+	var users []entity.User
+	if err := r.db.SelectContext(ctx, &users, query, pq.Array(usernames)); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetSuspension возвращает состояние блокировки пользователя; nil, если пользователь не найден
+func (r *UserRepository) GetSuspension(ctx context.Context, userID int64) (*entity.Suspension, error) {
+	query := `
+		SELECT id, suspended_until, suspension_reason
+		FROM users
+		WHERE id = $1
+	`
+
+	var suspension entity.Suspension
+	err := r.db.GetContext(ctx, &suspension, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &suspension, nil
+}
+
+// SetSuspension блокирует пользователя до until; until = nil снимает блокировку.
+// Возвращает sql.ErrNoRows, если пользователь не найден.
+func (r *UserRepository) SetSuspension(ctx context.Context, userID int64, until *time.Time, reason string) error {
+	query := `
+		UPDATE users
+		SET suspended_until = $2, suspension_reason = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, until, reason)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
 	query := `
 		UPDATE users
@@ -398,3 +398,91 @@ func TestUserRepository_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepository_GetByUsernames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(sqlx.NewDb(db, "sqlmock"))
+
+	t.Run("users found", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "username", "role", "created_at", "updated_at"}).
+			AddRow(1, "alice", "user", time.Now(), time.Now()).
+			AddRow(2, "bob", "admin", time.Now(), time.Now())
+		mock.ExpectQuery("SELECT id, username, role, created_at, updated_at FROM users WHERE username = ANY\\(\\$1\\)").
+			WillReturnRows(rows)
+
+		got, err := repo.GetByUsernames(context.Background(), []string{"alice", "bob", "ghost"})
+		assert.NoError(t, err)
+		if assert.Len(t, got, 2) {
+			assert.Equal(t, "alice", got[0].Username)
+			assert.Equal(t, "admin", got[1].Role)
+			assert.Empty(t, got[1].Password)
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		got, err := repo.GetByUsernames(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, username, role").WillReturnError(assert.AnError)
+
+		got, err := repo.GetByUsernames(context.Background(), []string{"alice"})
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Suspension(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(sqlx.NewDb(db, "sqlmock"))
+	until := time.Now().Add(time.Hour)
+
+	t.Run("set suspension", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users\\s+SET suspended_until = \\$2, suspension_reason = \\$3").
+			WithArgs(int64(5), &until, "spam").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.SetSuspension(context.Background(), 5, &until, "spam"))
+	})
+
+	t.Run("set suspension for missing user", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users").
+			WithArgs(int64(404), nil, "").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.SetSuspension(context.Background(), 404, nil, ""), sql.ErrNoRows)
+	})
+
+	t.Run("get suspension", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, suspended_until, suspension_reason\\s+FROM users").
+			WithArgs(int64(5)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "suspended_until", "suspension_reason"}).AddRow(5, until, "spam"))
+
+		got, err := repo.GetSuspension(context.Background(), 5)
+		assert.NoError(t, err)
+		assert.Equal(t, "spam", got.Reason)
+		assert.True(t, got.Active(time.Now()))
+	})
+
+	t.Run("get suspension for missing user", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, suspended_until").
+			WithArgs(int64(404)).
+			WillReturnError(sql.ErrNoRows)
+
+		got, err := repo.GetSuspension(context.Background(), 404)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -23,14 +23,22 @@ var (
 	ErrUserExists        = errors.New("пользователь уже существует")
 	ErrInvalidUsername   = errors.New("неверный формат имени пользователя")
 	ErrInvalidPassword   = errors.New("пароль должен содержать минимум 6 символов")
+	ErrUserSuspended     = errors.New("пользователь заблокирован")
+	ErrForbidden         = errors.New("недостаточно прав")
 )
 
 var usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9_]{3,}$`)
 
+// MaxUsernamesLookup ограничивает число имен в одном запросе GetUsersByUsernames
+const MaxUsernamesLookup = 50
+
 type IAuthUseCase interface {
 	Register(ctx context.Context, input entity.UserRegister) (*entity.User, error)
 	Login(ctx context.Context, input entity.UserLogin) (*entity.TokenResponse, error)
 	GetUserByID(ctx context.Context, id int64) (*entity.User, error)
+	GetUsersByUsernames(ctx context.Context, usernames []string) ([]entity.User, error)
+	SuspendUser(ctx context.Context, actorID int64, actorRole string, userID int64, until time.Time, reason string) (*entity.Suspension, error)
+	CheckSuspension(ctx context.Context, userID int64) error
 	ValidateToken(token string) (*jwt.Token, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*entity.TokenResponse, error)
 	Logout(ctx context.Context, token string) error
@@ -140,6 +148,11 @@ func (uc *AuthUseCase) Login(ctx context.Context, input entity.UserLogin) (*enti
 		return nil, ErrInvalidCredentials // Возвращаем ошибку неверных учетных данных
 	}
 
+	if err := uc.CheckSuspension(ctx, user.ID); err != nil {
+		uc.logger.Warn("Login failed: user is suspended", zap.String("username", input.Username))
+		return nil, err
+	}
+
 	// Логирование после успешного сравнения паролей
 	uc.logger.Debug("Login: password comparison successful, proceeding to token generation",
 		zap.String("username", input.Username))
@@ -215,3 +228,89 @@ func (uc *AuthUseCase) GetUserByID(ctx context.Context, id int64) (*entity.User,
 
 	return user, nil
 }
+
+// GetUsersByUsernames разрешает имена пользователей (например, из @упоминаний).
+// Имена, не подходящие под usernameRegex, и дубликаты отбрасываются до обращения к базе.
+func (uc *AuthUseCase) GetUsersByUsernames(ctx context.Context, usernames []string) ([]entity.User, error) {
+	seen := make(map[string]struct{}, len(usernames))
+	valid := make([]string, 0, len(usernames))
+	for _, name := range usernames {
+		if !usernameRegex.MatchString(name) {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		valid = append(valid, name)
+		if len(valid) == MaxUsernamesLookup {
+			break
+		}
+	}
+	if len(valid) == 0 {
+		return nil, nil
+	}
+
+	users, err := uc.userRepo.GetByUsernames(ctx, valid)
+	if err != nil {
+		uc.logger.Error("GetUsersByUsernames failed: repository error", zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+
+	return users, nil
+}
+
+// SuspendUser блокирует пользователя userID до until. Блокировать могут модераторы и администраторы;
+// администраторов и самого себя заблокировать нельзя. Нулевой или прошедший until снимает блокировку.
+func (uc *AuthUseCase) SuspendUser(ctx context.Context, actorID int64, actorRole string, userID int64, until time.Time, reason string) (*entity.Suspension, error) {
+	if actorRole != string(entity.RoleModerator) && actorRole != string(entity.RoleAdmin) {
+		return nil, ErrForbidden
+	}
+	if actorID == userID {
+		return nil, ErrForbidden
+	}
+
+	target, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		uc.logger.Error("SuspendUser failed: repository error", zap.Error(err), zap.Int64("user_id", userID))
+		return nil, errors.New("internal server error")
+	}
+	if target == nil {
+		return nil, ErrUserNotFound
+	}
+	if target.Role == string(entity.RoleAdmin) {
+		return nil, ErrForbidden
+	}
+
+	suspension := &entity.Suspension{UserID: userID}
+	if until.After(time.Now()) {
+		suspension.Until = &until
+		suspension.Reason = reason
+	}
+
+	if err := uc.userRepo.SetSuspension(ctx, userID, suspension.Until, suspension.Reason); err != nil {
+		uc.logger.Error("SuspendUser failed: repository error", zap.Error(err), zap.Int64("user_id", userID))
+		return nil, errors.New("internal server error")
+	}
+
+	uc.logger.Info("User suspension updated",
+		zap.Int64("user_id", userID),
+		zap.Int64("actor_id", actorID),
+		zap.Bool("suspended", suspension.Until != nil))
+
+	return suspension, nil
+}
+
+// CheckSuspension возвращает ErrUserSuspended, если пользователь сейчас заблокирован.
+// Проверяется при входе и при каждой проверке токена, поэтому блокировка действует сразу.
+func (uc *AuthUseCase) CheckSuspension(ctx context.Context, userID int64) error {
+	suspension, err := uc.userRepo.GetSuspension(ctx, userID)
+	if err != nil {
+		uc.logger.Error("CheckSuspension failed: repository error", zap.Error(err), zap.Int64("user_id", userID))
+		return errors.New("internal server error")
+	}
+	if suspension.Active(time.Now()) {
+		return ErrUserSuspended
+	}
+	return nil
+}
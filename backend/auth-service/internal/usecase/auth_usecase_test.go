@@ -48,6 +48,27 @@ func (m *MockUserRepository) GetByUsername(ctx context.Context, username string)
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByUsernames(ctx context.Context, usernames []string) ([]entity.User, error) {
+	args := m.Called(ctx, usernames)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetSuspension(ctx context.Context, userID int64) (*entity.Suspension, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Suspension), args.Error(1)
+}
+
+func (m *MockUserRepository) SetSuspension(ctx context.Context, userID int64, until *time.Time, reason string) error {
+	args := m.Called(ctx, userID, until, reason)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
@@ -192,10 +213,30 @@ func TestLogin(t *testing.T) {
 					Username: "testuser",
 					Password: string(hashedPassword),
 					Role:     "user",
-				}, nil)
+				}, nil).Once()
+				mockUserRepo.On("GetSuspension", mock.Anything, int64(1)).Return(&entity.Suspension{UserID: 1}, nil).Once()
 			},
 			expectedError: nil,
 		},
+		{
+			name: "suspended user",
+			input: entity.UserLogin{
+				Username: "banned",
+				Password: "password123",
+			},
+			mockSetup: func() {
+				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+				mockUserRepo.On("GetByUsername", mock.Anything, "banned").Return(&entity.User{
+					ID:       2,
+					Username: "banned",
+					Password: string(hashedPassword),
+					Role:     "user",
+				}, nil)
+				until := time.Now().Add(time.Hour)
+				mockUserRepo.On("GetSuspension", mock.Anything, int64(2)).Return(&entity.Suspension{UserID: 2, Until: &until}, nil)
+			},
+			expectedError: ErrUserSuspended,
+		},
 		{
 			name: "user not found",
 			input: entity.UserLogin{
@@ -264,6 +305,7 @@ func TestValidateToken(t *testing.T) {
 		Password: string(hashedPassword),
 		Role:     "user",
 	}, nil)
+	mockUserRepo.On("GetSuspension", mock.Anything, int64(1)).Return(nil, nil)
 	validToken, _ := uc.Login(context.Background(), entity.UserLogin{
 		Username: "testuser",
 		Password: "password123",
@@ -450,3 +492,109 @@ func TestGetUserByID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUsersByUsernames(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	logger, _ := logger.NewLogger("info")
+	uc := NewAuthUseCase(mockUserRepo, mockSessionRepo, &config.AuthConfig{Secret: "test-secret"}, logger)
+
+	t.Run("invalid and duplicate names are dropped", func(t *testing.T) {
+		mockUserRepo.ExpectedCalls = nil
+		mockUserRepo.On("GetByUsernames", mock.Anything, []string{"alice", "bob_2"}).
+			Return([]entity.User{{ID: 1, Username: "alice"}, {ID: 2, Username: "bob_2"}}, nil)
+
+		users, err := uc.GetUsersByUsernames(context.Background(), []string{"alice", "x", "bob_2", "alice", "bad-name"})
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("nothing valid skips repository", func(t *testing.T) {
+		mockUserRepo.ExpectedCalls = nil
+		mockUserRepo.Calls = nil
+
+		users, err := uc.GetUsersByUsernames(context.Background(), []string{"ab", "@@"})
+		assert.NoError(t, err)
+		assert.Nil(t, users)
+		mockUserRepo.AssertNotCalled(t, "GetByUsernames", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockUserRepo.ExpectedCalls = nil
+		mockUserRepo.On("GetByUsernames", mock.Anything, []string{"alice"}).Return(nil, assert.AnError)
+
+		users, err := uc.GetUsersByUsernames(context.Background(), []string{"alice"})
+		assert.Error(t, err)
+		assert.Nil(t, users)
+	})
+}
+
+func TestSuspendUser(t *testing.T) {
+	logger, _ := logger.NewLogger("info")
+	until := time.Now().Add(72 * time.Hour)
+
+	t.Run("moderator suspends user", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := NewAuthUseCase(mockUserRepo, new(MockSessionRepository), &config.AuthConfig{}, logger)
+		mockUserRepo.On("GetByID", mock.Anything, int64(5)).Return(&entity.User{ID: 5, Role: "user"}, nil)
+		mockUserRepo.On("SetSuspension", mock.Anything, int64(5), &until, "spam").Return(nil)
+
+		suspension, err := uc.SuspendUser(context.Background(), 1, "moderator", 5, until, "spam")
+		assert.NoError(t, err)
+		assert.True(t, suspension.Active(time.Now()))
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("past until lifts suspension", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := NewAuthUseCase(mockUserRepo, new(MockSessionRepository), &config.AuthConfig{}, logger)
+		mockUserRepo.On("GetByID", mock.Anything, int64(5)).Return(&entity.User{ID: 5, Role: "user"}, nil)
+		mockUserRepo.On("SetSuspension", mock.Anything, int64(5), (*time.Time)(nil), "").Return(nil)
+
+		suspension, err := uc.SuspendUser(context.Background(), 1, "admin", 5, time.Time{}, "appeal accepted")
+		assert.NoError(t, err)
+		assert.Nil(t, suspension.Until)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := NewAuthUseCase(mockUserRepo, new(MockSessionRepository), &config.AuthConfig{}, logger)
+		mockUserRepo.On("GetByID", mock.Anything, int64(9)).Return(&entity.User{ID: 9, Role: "admin"}, nil)
+
+		_, err := uc.SuspendUser(context.Background(), 1, "user", 5, until, "")
+		assert.ErrorIs(t, err, ErrForbidden)
+		_, err = uc.SuspendUser(context.Background(), 1, "moderator", 1, until, "")
+		assert.ErrorIs(t, err, ErrForbidden)
+		_, err = uc.SuspendUser(context.Background(), 1, "moderator", 9, until, "")
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := NewAuthUseCase(mockUserRepo, new(MockSessionRepository), &config.AuthConfig{}, logger)
+		mockUserRepo.On("GetByID", mock.Anything, int64(404)).Return(nil, nil)
+
+		_, err := uc.SuspendUser(context.Background(), 1, "moderator", 404, until, "")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestCheckSuspension(t *testing.T) {
+	logger, _ := logger.NewLogger("info")
+	mockUserRepo := new(MockUserRepository)
+	uc := NewAuthUseCase(mockUserRepo, new(MockSessionRepository), &config.AuthConfig{}, logger)
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	mockUserRepo.On("GetSuspension", mock.Anything, int64(1)).Return(&entity.Suspension{UserID: 1, Until: &future}, nil)
+	mockUserRepo.On("GetSuspension", mock.Anything, int64(2)).Return(&entity.Suspension{UserID: 2, Until: &past}, nil)
+	mockUserRepo.On("GetSuspension", mock.Anything, int64(3)).Return(nil, nil)
+	mockUserRepo.On("GetSuspension", mock.Anything, int64(4)).Return(nil, errors.New("db down"))
+
+	assert.ErrorIs(t, uc.CheckSuspension(context.Background(), 1), ErrUserSuspended)
+	assert.NoError(t, uc.CheckSuspension(context.Background(), 2))
+	assert.NoError(t, uc.CheckSuspension(context.Background(), 3))
+	assert.Error(t, uc.CheckSuspension(context.Background(), 4))
+}
@@ -0,0 +1,8 @@
+CREATE TABLE chat_message_mentions (
+    message_id INT NOT NULL,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (message_id, user_id),
+    FOREIGN KEY (message_id) REFERENCES chat_messages(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_chat_message_mentions_user_id ON chat_message_mentions(user_id);
@@ -0,0 +1,7 @@
+CREATE TABLE chat_message_attachments (
+    message_id INT NOT NULL,
+    attachment_id BIGINT NOT NULL,
+    position SMALLINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (message_id, attachment_id),
+    FOREIGN KEY (message_id) REFERENCES chat_messages(id) ON DELETE CASCADE
+);
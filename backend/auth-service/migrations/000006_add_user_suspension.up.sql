@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN suspended_until TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN suspension_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS room_id;
+DROP TABLE IF EXISTS chat_room_members;
+DROP TABLE IF EXISTS chat_rooms;
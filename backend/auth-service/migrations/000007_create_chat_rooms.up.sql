@@ -0,0 +1,27 @@
+CREATE TABLE chat_rooms (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    is_private BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Общая комната: в ней остается вся переписка, накопленная до появления комнат
+INSERT INTO chat_rooms (id, name) VALUES (1, 'general');
+SELECT setval(pg_get_serial_sequence('chat_rooms', 'id'), 1);
+
+CREATE TABLE chat_room_members (
+    room_id INT NOT NULL,
+    user_id BIGINT NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member')),
+    joined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (room_id, user_id),
+    FOREIGN KEY (room_id) REFERENCES chat_rooms(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_chat_room_members_user_id ON chat_room_members(user_id);
+
+ALTER TABLE chat_messages
+    ADD COLUMN room_id INT NOT NULL DEFAULT 1 REFERENCES chat_rooms(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_chat_messages_room_id ON chat_messages(room_id, timestamp);
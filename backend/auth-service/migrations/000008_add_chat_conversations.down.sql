@@ -0,0 +1,5 @@
+ALTER TABLE chat_room_members DROP COLUMN IF EXISTS last_read_message_id;
+DELETE FROM chat_rooms WHERE kind <> 'room';
+ALTER TABLE chat_rooms
+    DROP COLUMN IF EXISTS direct_key,
+    DROP COLUMN IF EXISTS kind;
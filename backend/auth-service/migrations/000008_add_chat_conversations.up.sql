@@ -0,0 +1,9 @@
+-- Личные (direct) и групповые (group) переписки хранятся как приватные комнаты.
+-- direct_key ("{меньший id}:{больший id}") не дает завести две переписки одной пары.
+ALTER TABLE chat_rooms
+    ADD COLUMN kind VARCHAR(10) NOT NULL DEFAULT 'room' CHECK (kind IN ('room', 'direct', 'group')),
+    ADD COLUMN direct_key VARCHAR(50) UNIQUE;
+
+-- Последнее прочитанное сообщение участника, по нему считаются непрочитанные
+ALTER TABLE chat_room_members
+    ADD COLUMN last_read_message_id INT NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- События backplane chat-service, которые не помещаются в NOTIFY (предел 8000 байт).
+-- Уведомление несет только id строки; записи старше минуты удаляются при публикации.
+CREATE UNLOGGED TABLE IF NOT EXISTS chat_backplane_events (
+    id BIGSERIAL PRIMARY KEY,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_backplane_events_created_at ON chat_backplane_events(created_at);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_chat_messages_client_msg_id;
+DROP INDEX IF EXISTS idx_chat_messages_room_seq;
+
+ALTER TABLE chat_messages
+    DROP COLUMN IF EXISTS client_msg_id,
+    DROP COLUMN IF EXISTS seq;
+
+ALTER TABLE chat_rooms
+    DROP COLUMN IF EXISTS last_seq;
@@ -0,0 +1,29 @@
+-- Порядковый номер сообщения в комнате. Номер выдается из chat_rooms.last_seq в транзакции
+-- сохранения, так что внутри комнаты он строго возрастает; по нему клиент после
+-- переподключения запрашивает пропущенные сообщения.
+ALTER TABLE chat_rooms
+    ADD COLUMN last_seq BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE chat_messages
+    ADD COLUMN seq BIGINT,
+    ADD COLUMN client_msg_id VARCHAR(64);
+
+UPDATE chat_messages m
+SET seq = numbered.seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY room_id ORDER BY timestamp, id) AS seq
+    FROM chat_messages
+) numbered
+WHERE m.id = numbered.id;
+
+UPDATE chat_rooms r
+SET last_seq = COALESCE((SELECT MAX(seq) FROM chat_messages WHERE room_id = r.id), 0);
+
+ALTER TABLE chat_messages
+    ALTER COLUMN seq SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_chat_messages_room_seq ON chat_messages(room_id, seq);
+
+-- Ключ идемпотентности от клиента: повтор отправки возвращает уже сохраненное сообщение
+CREATE UNIQUE INDEX idx_chat_messages_client_msg_id ON chat_messages(user_id, client_msg_id)
+    WHERE client_msg_id IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Время последнего отключения пользователя от чата. Статус online/away хранится только
+-- в памяти хабов; в базу попадает момент, когда закрылось соединение.
+CREATE TABLE chat_user_presence (
+    user_id BIGINT PRIMARY KEY,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,9 @@
+ALTER TABLE chat_room_members
+    ADD COLUMN IF NOT EXISTS last_read_message_id INT NOT NULL DEFAULT 0;
+
+UPDATE chat_room_members m
+SET last_read_message_id = p.last_read_message_id
+FROM chat_read_positions p
+WHERE p.room_id = m.room_id AND p.user_id = m.user_id;
+
+DROP TABLE IF EXISTS chat_read_positions;
@@ -0,0 +1,26 @@
+-- Позиция прочтения пользователя в комнате: последний прочитанный seq. Хранится отдельно
+-- от chat_room_members, потому что в общей комнате записей о членстве нет.
+CREATE TABLE chat_read_positions (
+    room_id INT NOT NULL REFERENCES chat_rooms(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    last_read_seq BIGINT NOT NULL DEFAULT 0,
+    last_read_message_id INT NOT NULL DEFAULT 0,
+    read_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (room_id, user_id)
+);
+
+CREATE INDEX idx_chat_read_positions_user_id ON chat_read_positions(user_id);
+
+-- Переносим позиции, отмеченные в переписках по ID сообщения
+INSERT INTO chat_read_positions (room_id, user_id, last_read_seq, last_read_message_id)
+SELECT m.room_id, m.user_id, last.seq, last.id
+FROM chat_room_members m
+JOIN LATERAL (
+    SELECT id, seq FROM chat_messages
+    WHERE room_id = m.room_id AND id <= m.last_read_message_id
+    ORDER BY seq DESC LIMIT 1
+) last ON TRUE
+WHERE m.last_read_message_id > 0;
+
+ALTER TABLE chat_room_members
+    DROP COLUMN last_read_message_id;
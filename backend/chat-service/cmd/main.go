@@ -3,6 +3,8 @@ package main
 import (
 	"database/sql"
 	"log"
+	"net"
+	"os"
 	"time"
 
 	pb "github.com/jaliks17/ffffforum/backend/proto"
@@ -11,6 +13,9 @@ import (
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/handler"
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/attachments"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/contentfilter"
+	myWeb "github.com/jaliks17/ffffforum/backend/chat-service/pkg/websocket"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -62,11 +67,45 @@ func main() {
 	// Создание клиента Auth Service
 	authClient := pb.NewAuthServiceClient(authConn)
 
+	// Общий секрет для /internal и запросов к forum-service; без него сервис не запускается
+	internalToken := os.Getenv("INTERNAL_TOKEN")
+	if internalToken == "" {
+		log.Fatal("INTERNAL_TOKEN is required")
+	}
+
+	// Фильтр контента forum-service (запрещенные слова, ссылки, дубликаты, спам)
+	filterURL := os.Getenv("FORUM_FILTER_URL")
+	if filterURL == "" {
+		filterURL = "http://localhost:8080/internal/content"
+	}
+	filter := contentfilter.NewClient(filterURL, internalToken)
+
+	// Вложения сообщений загружаются в forum-service; форум проверяет их владельца
+	attachmentsURL := os.Getenv("FORUM_ATTACHMENTS_URL")
+	if attachmentsURL == "" {
+		attachmentsURL = "http://localhost:8080/internal/attachments/claim"
+	}
+	attachmentClient := attachments.NewClient(attachmentsURL, internalToken)
+
 	repo := repository.NewMessageRepository(db)
-	uc := usecase.NewMessageUseCase(repo)
+	uc := usecase.NewMessageUseCase(repo, authClient, filter, attachmentClient)
 	h := handler.NewMessageHandler(uc, authClient) // Передача authClient в обработчик
-
-	go h.HandleMessages()
+	// При нескольких экземплярах сервиса хабы обмениваются событиями через LISTEN/NOTIFY
+	if os.Getenv("CHAT_BACKPLANE") == "postgres" {
+		backplane, err := myWeb.NewPostgresBackplane(db, connStr, os.Getenv("CHAT_BACKPLANE_CHANNEL"))
+		if err != nil {
+			log.Fatalf("Failed to start backplane: %v", err)
+		}
+		defer backplane.Close()
+		h.Hub.Stop()
+		h.Hub = myWeb.NewHub(myWeb.HubConfig{Backplane: backplane})
+		log.Printf("Chat hub %s joined the PostgreSQL backplane", h.Hub.ID)
+	}
+	roomRepo := repository.NewRoomRepository(db)
+	h.Rooms = usecase.NewRoomUseCase(roomRepo)
+	h.Conversations = usecase.NewConversationUseCase(repository.NewConversationRepository(db), authClient)
+	h.Presence = usecase.NewPresenceUseCase(repository.NewPresenceRepository(db))
+	h.Reads = usecase.NewReadUseCase(repository.NewReadRepository(db), roomRepo)
 
 	// Горутина для удаления старых сообщений каждые 24 часа
 	go func() {
@@ -84,10 +123,17 @@ func main() {
 		}
 	}()
 
+	// gRPC API чата для других сервисов; история отдается теми же страницами, что и REST
+	grpcAddr := os.Getenv("CHAT_GRPC_ADDR")
+	if grpcAddr == "" {
+		grpcAddr = ":50052"
+	}
+	go startGRPCServer(grpcAddr, handler.NewChatGRPCServer(uc))
+
 	r := gin.Default()
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Upgrade", "Connection"},
 		ExposeHeaders:    []string{"Content-Length", "Upgrade", "Connection"},
 		AllowCredentials: true,
@@ -126,8 +172,65 @@ func main() {
 		// @Success 200 {array} models.Message
 		// @Router /api/v1/messages [get]
 		api.GET("/messages", h.GetMessages)
+		// JSON Schema протокола WebSocket (подпротокол chat.v1)
+		api.GET("/ws/schema", h.GetProtocolSchema)
+
+		// Комнаты: публичные и приватные (по приглашению), членство и роли
+		api.GET("/rooms", h.GetRooms)
+		api.POST("/rooms", h.CreateRoom)
+		api.GET("/rooms/:id/messages", h.GetRoomMessages)
+		api.POST("/rooms/:id/join", h.JoinRoom)
+		api.POST("/rooms/:id/leave", h.LeaveRoom)
+		api.GET("/rooms/:id/members", h.GetRoomMembers)
+		api.POST("/rooms/:id/members", h.InviteRoomMember)
+		api.DELETE("/rooms/:id/members/:user_id", h.RemoveRoomMember)
+		api.PATCH("/rooms/:id/members/:user_id", h.UpdateRoomMemberRole)
+		// Позиции прочтения: отметка о прочтении и кто что прочитал
+		api.POST("/rooms/:id/read", h.MarkRoomRead)
+		api.GET("/rooms/:id/reads", h.GetRoomReads)
+		// Счетчики непрочитанных по всем комнатам пользователя
+		api.GET("/unread", h.GetUnreadCounts)
+
+		// Личные и групповые переписки
+		api.GET("/conversations", h.GetConversations)
+		api.POST("/conversations", h.CreateConversation)
+		api.GET("/conversations/:id/messages", h.GetConversationMessages)
+		api.POST("/conversations/:id/read", h.MarkConversationRead)
+
+		// Статус пользователей (online, away, offline) и время последнего визита
+		api.GET("/presence", h.GetPresence)
+	}
+
+	// Endpoint-ы для других сервисов, доступные только с общим секретом INTERNAL_TOKEN
+	internal := r.Group("/internal", handler.RequireInternalToken(internalToken))
+	{
+		// Доставка событий от других сервисов (уведомления и результаты опросов форума)
+		internal.POST("/push", h.PushEvent)
+		internal.POST("/publish", h.PublishEvent)
+		// Модерация сообщений по жалобам из форума
+		internal.GET("/messages/:id", h.GetInternalMessage)
+		internal.DELETE("/messages/:id", h.DeleteInternalMessage)
+		// Доступ к файлам, отправленным в комнаты, для forum-service
+		internal.GET("/rooms/:id/members/:user_id", h.GetInternalRoomMember)
+		// Состояние WebSocket-соединений для мониторинга
+		internal.GET("/metrics", h.GetMetrics)
 	}
 
 	log.Println("Listening on :8082...")
 	log.Fatal(r.Run(":8082"))
-}
\ No newline at end of file
+}
+
+func startGRPCServer(addr string, server pb.ChatServiceServer) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", addr, err)
+	}
+
+	s := grpc.NewServer()
+	pb.RegisterChatServiceServer(s, server)
+
+	log.Printf("gRPC listening on %s...", addr)
+	if err := s.Serve(lis); err != nil {
+		log.Fatalf("Failed to serve gRPC: %v", err)
+	}
+}
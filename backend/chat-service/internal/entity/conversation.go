@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxConversationParticipants - сколько пользователей может быть в групповой переписке, включая создателя
+const MaxConversationParticipants = 10
+
+// Conversation - личная или групповая переписка. Хранится как приватная комната,
+// поэтому сообщения в нее отправляются по WebSocket с room_id = ID переписки.
+type Conversation struct {
+	ID           int       `json:"id" example:"5"`
+	Kind         string    `json:"kind" example:"direct"`
+	Name         string    `json:"name,omitempty" example:"weekend plans"`
+	Participants []int64   `json:"participants" example:"1,7"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-10-27T10:00:00Z"`
+	// LastMessage - последнее сообщение для превью в списке переписок
+	LastMessage *Message `json:"last_message,omitempty"`
+	UnreadCount int      `json:"unread_count" example:"2"`
+}
+
+// CreateConversationRequest - запрос на создание переписки. Один собеседник - личная
+// переписка (повторный запрос вернет существующую), несколько - групповая.
+type CreateConversationRequest struct {
+	UserIDs []int64 `json:"user_ids" binding:"required" example:"7"`
+	Name    string  `json:"name" example:"weekend plans"`
+}
+
+// MarkReadRequest - отметка о прочтении комнаты или переписки до сообщения MessageID
+// включительно (0 - до последнего сообщения)
+type MarkReadRequest struct {
+	MessageID int `json:"message_id" example:"42"`
+}
+
+// DirectKey - ключ личной переписки пары пользователей, не зависящий от порядка ID
+func DirectKey(a, b int64) string {
+	if a > b {
+		a, b = b, a
+	}
+	return fmt.Sprintf("%d:%d", a, b)
+}
@@ -1,11 +1,104 @@
 package entity
 
-import "time"
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+)
 
 type Message struct {
-	ID         int       `json:"id" example:"1" db:"id"`
-	UserID     int       `json:"user_id" example:"123" db:"user_id"`
-	Username   string    `json:"username" example:"john_doe" db:"username"`
-	Message    string    `json:"message" example:"Hello, world!" db:"content"`
-	Timestamp  time.Time `json:"timestamp" example:"2023-10-27T10:00:00Z" db:"timestamp"`
-}
\ No newline at end of file
+	ID int `json:"id" example:"1" db:"id"`
+	// RoomID - комната, в которую отправлено сообщение (GeneralRoomID, если клиент ее не указал)
+	RoomID int `json:"room_id" example:"1" db:"room_id"`
+	// Seq - порядковый номер сообщения в комнате, без пропусков и повторов
+	Seq       int64     `json:"seq" example:"17" db:"seq"`
+	UserID    int       `json:"user_id" example:"123" db:"user_id"`
+	Username  string    `json:"username" example:"john_doe" db:"username"`
+	Message   string    `json:"message" example:"Hello, world!" db:"content"`
+	Timestamp time.Time `json:"timestamp" example:"2023-10-27T10:00:00Z" db:"timestamp"`
+	// Mentions - ID пользователей, упомянутых в сообщении через @username
+	Mentions []int64 `json:"mentions,omitempty" db:"-"`
+	// AttachmentIDs - вложения, загруженные через forum-service (/api/v1/attachments/{id})
+	AttachmentIDs []int64 `json:"attachment_ids,omitempty" db:"-"`
+	// ClientMsgID - ключ идемпотентности от клиента: повтор отправки с тем же ключом
+	// возвращает уже сохраненное сообщение
+	ClientMsgID string `json:"client_msg_id,omitempty" db:"client_msg_id"`
+}
+
+// MaxMessageAttachments - сколько вложений можно прикрепить к одному сообщению
+const MaxMessageAttachments = 10
+
+// MessageQuery задает страницу истории комнаты. Before, After и Around - ID сообщений
+// этой комнаты, задается не больше одного. Before и After не включают само сообщение,
+// Around включает и делит страницу поровну между более старыми и более новыми. Без
+// курсора возвращаются последние Limit сообщений, Offset отсчитывается от самого нового.
+type MessageQuery struct {
+	RoomID int
+	Before int
+	After  int
+	Around int
+	Limit  int
+	Offset int
+}
+
+// MessagePage - страница истории в хронологическом порядке
+type MessagePage struct {
+	Messages []Message `json:"messages"`
+	// Total - всего сообщений в комнате
+	Total int `json:"total" example:"120"`
+}
+
+// MentionEvent отправляется по WebSocket упомянутому пользователю
+type MentionEvent struct {
+	Type      string `json:"type" example:"mention"`
+	MessageID int    `json:"message_id" example:"1"`
+	UserID    int    `json:"user_id" example:"123"`
+	Username  string `json:"username" example:"john_doe"`
+	Message   string `json:"message" example:"Hello, @alice!"`
+}
+
+// MessageRejectedEvent отправляется автору по WebSocket, если фильтр контента не пропустил сообщение
+type MessageRejectedEvent struct {
+	Type   string `json:"type" example:"message_rejected"`
+	Reason string `json:"reason" example:"message rejected by content filter: banned word"`
+}
+
+// ReplayEvent отправляется по WebSocket в ответ на resume: сообщения комнаты,
+// пропущенные клиентом, по возрастанию seq
+type ReplayEvent struct {
+	Type     string    `json:"type" example:"replay"`
+	RoomID   int       `json:"room_id" example:"1"`
+	Messages []Message `json:"messages"`
+	// Truncated - пропущенных сообщений больше, чем вернул повтор; остальное грузится через REST
+	Truncated bool `json:"truncated,omitempty" example:"false"`
+}
+
+// PushRequest - запрос на доставку события пользователю от другого сервиса
+type PushRequest struct {
+	UserID int64           `json:"user_id" binding:"required" example:"123"`
+	Event  json.RawMessage `json:"event" binding:"required" swaggertype:"object"`
+}
+
+// PushResponse сообщает, был ли пользователь в сети в момент доставки
+type PushResponse struct {
+	Online bool `json:"online" example:"true"`
+}
+
+// topicPattern - темы, на которые клиент может подписаться по WebSocket: "poll:{post_id}"
+var topicPattern = regexp.MustCompile(`^poll:[1-9][0-9]{0,18}$`)
+
+// IsValidTopic сообщает, поддерживается ли тема подписки
+func IsValidTopic(topic string) bool {
+	return topicPattern.MatchString(topic)
+}
+
+// PublishRequest - запрос другого сервиса на рассылку события подписчикам темы
+type PublishRequest struct {
+	Topic string          `json:"topic" binding:"required" example:"poll:12"`
+	Event json.RawMessage `json:"event" binding:"required" swaggertype:"object"`
+}
+
+// PublishResponse сообщает, скольким соединениям будет доставлено событие
+type PublishResponse struct {
+	Subscribers int `json:"subscribers" example:"3"`
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"regexp"
+	"time"
+)
+
+// Статусы пользователя; совпадают со статусами хаба pkg/websocket
+const (
+	PresenceOnline  = "online"
+	PresenceAway    = "away"
+	PresenceOffline = "offline"
+)
+
+// MaxPresenceUsers - сколько пользователей можно запросить в GET /presence за раз
+const MaxPresenceUsers = 100
+
+const (
+	// TypingThrottle - не чаще этого интервала сервер рассылает начало набора одного
+	// пользователя в одной комнате. Пока пользователь печатает, клиент повторяет typing
+	// с этим интервалом.
+	TypingThrottle = 3 * time.Second
+	// TypingTimeout - через сколько без повтора клиент скрывает индикатор набора,
+	// если не пришло событие с typing=false (например, соединение оборвалось)
+	TypingTimeout = 10 * time.Second
+)
+
+// UserPresence - статус пользователя: online, away или offline. LastSeen известен
+// для offline пользователей, которые хотя бы раз подключались к чату.
+type UserPresence struct {
+	UserID   int64      `json:"user_id" example:"123"`
+	Status   string     `json:"status" example:"offline"`
+	LastSeen *time.Time `json:"last_seen,omitempty" example:"2023-10-27T10:00:00Z"`
+}
+
+// TypingEvent рассылается участникам комнаты, когда пользователь начинает или
+// заканчивает набирать сообщение
+type TypingEvent struct {
+	Type     string `json:"type" example:"typing"`
+	RoomID   int    `json:"room_id" example:"1"`
+	UserID   int64  `json:"user_id" example:"123"`
+	Username string `json:"username" example:"john_doe"`
+	Typing   bool   `json:"typing" example:"true"`
+}
+
+// presenceTopicPattern - темы статуса пользователя: "presence:{user_id}"
+var presenceTopicPattern = regexp.MustCompile(`^presence:[1-9][0-9]{0,18}$`)
+
+// IsPresenceTopic сообщает, является ли тема подпиской на статус пользователя. Такие
+// темы наполняет сам хаб, другие сервисы публиковать в них не могут.
+func IsPresenceTopic(topic string) bool {
+	return presenceTopicPattern.MatchString(topic)
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// MaxUnreadCount - предел счетчика непрочитанных: дальше сообщения не считаются,
+// клиент показывает "999+"
+const MaxUnreadCount = 1000
+
+// ReadPosition - последнее сообщение комнаты, прочитанное пользователем
+type ReadPosition struct {
+	RoomID            int       `json:"room_id" example:"1"`
+	UserID            int64     `json:"user_id" example:"123"`
+	LastReadSeq       int64     `json:"last_read_seq" example:"41"`
+	LastReadMessageID int       `json:"last_read_message_id" example:"42"`
+	ReadAt            time.Time `json:"read_at" example:"2023-10-27T10:00:00Z"`
+}
+
+// UnreadCount - число чужих сообщений комнаты после позиции прочтения пользователя,
+// не больше MaxUnreadCount
+type UnreadCount struct {
+	RoomID      int   `json:"room_id" example:"1"`
+	Unread      int   `json:"unread" example:"3"`
+	LastReadSeq int64 `json:"last_read_seq" example:"41"`
+	LastSeq     int64 `json:"last_seq" example:"44"`
+}
+
+// ReadEvent рассылается соединениям комнаты, когда пользователь сдвинул позицию прочтения:
+// собеседники видят отметку о прочтении, другие соединения пользователя сбрасывают счетчик
+type ReadEvent struct {
+	Type      string    `json:"type" example:"read"`
+	RoomID    int       `json:"room_id" example:"1"`
+	UserID    int64     `json:"user_id" example:"123"`
+	MessageID int       `json:"message_id" example:"42"`
+	Seq       int64     `json:"seq" example:"41"`
+	ReadAt    time.Time `json:"read_at" example:"2023-10-27T10:00:00Z"`
+}
+
+// UnreadEvent - ответ на запрос "unread" старого формата WebSocket
+type UnreadEvent struct {
+	Type  string        `json:"type" example:"unread"`
+	Rooms []UnreadCount `json:"rooms"`
+}
@@ -0,0 +1,66 @@
+package entity
+
+import "time"
+
+// GeneralRoomID - общая публичная комната. В ней состоят все пользователи без записи в
+// chat_room_members, туда же попадают сообщения клиентов, не указавших комнату.
+const GeneralRoomID = 1
+
+// Роли участников комнаты
+const (
+	RoomRoleOwner  = "owner"
+	RoomRoleAdmin  = "admin"
+	RoomRoleMember = "member"
+)
+
+// Виды комнат: обычная комната, личная переписка двух пользователей и групповая переписка
+const (
+	RoomKindRoom   = "room"
+	RoomKindDirect = "direct"
+	RoomKindGroup  = "group"
+)
+
+// MaxRoomNameLength - максимальная длина названия комнаты
+const MaxRoomNameLength = 100
+
+type Room struct {
+	ID        int       `json:"id" example:"2" db:"id"`
+	Name      string    `json:"name" example:"gophers" db:"name"`
+	IsPrivate bool      `json:"is_private" example:"false" db:"is_private"`
+	Kind      string    `json:"kind" example:"room" db:"kind"`
+	CreatedBy int64     `json:"created_by" example:"123" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" example:"2023-10-27T10:00:00Z" db:"created_at"`
+	// Role - роль текущего пользователя в комнате; пусто, если он в ней не состоит
+	Role string `json:"role,omitempty" example:"owner" db:"-"`
+}
+
+type RoomMember struct {
+	RoomID   int       `json:"room_id" example:"2" db:"room_id"`
+	UserID   int64     `json:"user_id" example:"123" db:"user_id"`
+	Role     string    `json:"role" example:"member" db:"role"`
+	JoinedAt time.Time `json:"joined_at" example:"2023-10-27T10:00:00Z" db:"joined_at"`
+}
+
+// CreateRoomRequest - запрос на создание комнаты
+type CreateRoomRequest struct {
+	Name      string `json:"name" binding:"required" example:"gophers"`
+	IsPrivate bool   `json:"is_private" example:"false"`
+}
+
+// InviteMemberRequest - приглашение пользователя в комнату
+type InviteMemberRequest struct {
+	UserID int64 `json:"user_id" binding:"required" example:"123"`
+}
+
+// UpdateMemberRoleRequest - смена роли участника комнаты
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required" example:"admin"`
+}
+
+// RoomEvent отправляется по WebSocket в ответ на join/leave и при изменении членства
+// ("joined", "left", "room_error")
+type RoomEvent struct {
+	Type   string `json:"type" example:"joined"`
+	RoomID int    `json:"room_id" example:"2"`
+	Error  string `json:"error,omitempty" example:"room not found"`
+}
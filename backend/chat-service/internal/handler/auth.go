@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalTokenHeader - заголовок с секретом для внутренних запросов между сервисами
+const InternalTokenHeader = "X-Internal-Token"
+
+// RequireInternalToken закрывает группу /internal общим секретом сервисов.
+// С пустым token отклоняются все запросы.
+func RequireInternalToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := c.GetHeader(InternalTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid internal token"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// bearerToken извлекает токен из заголовка Authorization.
+// Если заголовок отсутствует, отвечает 401 и возвращает false.
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return "", false
+	}
+	return strings.TrimPrefix(authHeader, "Bearer "), true
+}
+
+// authenticate проверяет сессию через Auth Service и возвращает ID пользователя.
+// При ошибке отвечает 401 и возвращает false.
+func (h *MessageHandler) authenticate(c *gin.Context) (int64, bool) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return 0, false
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := h.AuthClient.ValidateSession(ctx, &pb.ValidateSessionRequest{Token: token})
+	if err != nil || resp == nil || !resp.GetValid() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return 0, false
+	}
+	return resp.GetUserId(), true
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ChatGRPCServer - gRPC API чата для других сервисов. История общей комнаты отдается
+// теми же страницами, что и GET /api/v1/messages?limit=&offset=.
+type ChatGRPCServer struct {
+	pb.UnimplementedChatServiceServer
+	uc usecase.MessageUseCase
+}
+
+func NewChatGRPCServer(uc usecase.MessageUseCase) *ChatGRPCServer {
+	return &ChatGRPCServer{uc: uc}
+}
+
+// GetMessages возвращает limit сообщений общей комнаты, пропустив offset самых новых,
+// в хронологическом порядке; total - всего сообщений в комнате
+func (s *ChatGRPCServer) GetMessages(ctx context.Context, req *pb.GetMessagesRequest) (*pb.GetMessagesResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	page, err := s.uc.GetMessages(entity.MessageQuery{
+		RoomID: entity.GeneralRoomID,
+		Limit:  int(req.Limit),
+		Offset: int(req.Offset),
+	})
+	if errors.Is(err, usecase.ErrInvalidHistoryQuery) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get messages: %v", err)
+	}
+
+	resp := &pb.GetMessagesResponse{
+		Messages: make([]*pb.ChatMessage, 0, len(page.Messages)),
+		Total:    int32(page.Total),
+	}
+	for _, msg := range page.Messages {
+		resp.Messages = append(resp.Messages, &pb.ChatMessage{
+			Id:        int64(msg.ID),
+			UserId:    int64(msg.UserID),
+			Username:  msg.Username,
+			Content:   msg.Message,
+			CreatedAt: msg.Timestamp.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
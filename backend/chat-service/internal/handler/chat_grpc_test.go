@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChatGRPCServer_GetMessages(t *testing.T) {
+	sentAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	uc := new(MockMessageUseCase)
+	// limit и offset значат то же, что в GET /api/v1/messages
+	uc.On("GetMessages", entity.MessageQuery{RoomID: entity.GeneralRoomID, Limit: 2, Offset: 10}).
+		Return(&entity.MessagePage{Messages: []entity.Message{
+			{ID: 7, UserID: 3, Username: "alice", Message: "hi", Timestamp: sentAt},
+			{ID: 8, UserID: 4, Username: "bob", Message: "hello", Timestamp: sentAt},
+		}, Total: 30}, nil).Once()
+	uc.On("GetMessages", entity.MessageQuery{RoomID: entity.GeneralRoomID}).Return(nil, errors.New("db error")).Once()
+	server := NewChatGRPCServer(uc)
+
+	resp, err := server.GetMessages(context.Background(), &pb.GetMessagesRequest{Limit: 2, Offset: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int32(30), resp.Total)
+	require.Len(t, resp.Messages, 2)
+	assert.Equal(t, int64(7), resp.Messages[0].Id)
+	assert.Equal(t, "alice", resp.Messages[0].Username)
+	assert.Equal(t, "hi", resp.Messages[0].Content)
+	assert.Equal(t, "2024-05-01T12:00:00Z", resp.Messages[0].CreatedAt)
+
+	_, err = server.GetMessages(context.Background(), &pb.GetMessagesRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	_, err = server.GetMessages(context.Background(), nil)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	uc.AssertExpectations(t)
+}
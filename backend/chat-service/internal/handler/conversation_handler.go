@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetConversations godoc
+// @Summary List conversations
+// @Description Returns the user's direct and group conversations with the last message preview and unread count, most recent first
+// @Tags conversations
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} entity.Conversation
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/conversations [get]
+func (h *MessageHandler) GetConversations(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	conversations, err := h.Conversations.ListConversations(userID)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	if conversations == nil {
+		conversations = []entity.Conversation{}
+	}
+	c.JSON(http.StatusOK, conversations)
+}
+
+// CreateConversation godoc
+// @Summary Start a conversation
+// @Description Starts a direct conversation with one user (returns the existing one if it already exists) or a group conversation with several users. Messages are sent over WebSocket with room_id set to the conversation ID
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body entity.CreateConversationRequest true "Participants"
+// @Success 201 {object} entity.Conversation
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/conversations [post]
+func (h *MessageHandler) CreateConversation(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	var req entity.CreateConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	conv, err := h.Conversations.CreateConversation(userID, req.UserIDs, req.Name)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	// Подключаем к переписке соединения участников, которые сейчас в сети
+	for _, participant := range conv.Participants {
+		h.syncRoomConnection(participant, conv.ID, true)
+	}
+	c.JSON(http.StatusCreated, conv)
+}
+
+// GetConversationMessages godoc
+// @Summary Get conversation history
+// @Description Returns a page of conversation messages and marks it as read up to the last message of the page. Only participants have access
+// @Tags conversations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Conversation ID"
+// @Param before query int false "Messages older than this message ID"
+// @Param after query int false "Messages newer than this message ID"
+// @Param around query int false "Messages around this message ID, including it"
+// @Param limit query int false "Page size" default(50)
+// @Param offset query int false "Offset from the newest message, without a cursor" default(0)
+// @Success 200 {array} entity.Message
+// @Header 200 {integer} X-Total-Count "Total messages in the conversation"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/conversations/{id}/messages [get]
+func (h *MessageHandler) GetConversationMessages(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	convID, _, ok := roomParams(c, false)
+	if !ok {
+		return
+	}
+	q, ok := historyQuery(c, convID)
+	if !ok {
+		return
+	}
+	if err := h.checkRoomAccess(convID, userID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+
+	page, ok := h.getHistory(c, q)
+	if !ok {
+		return
+	}
+	// Прочитанным считается все до последнего сообщения страницы; позиция не сдвигается назад
+	if n := len(page.Messages); n > 0 {
+		if _, err := h.markRead(userID, convID, page.Messages[n-1].ID); err != nil {
+			respondRoomError(c, err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, page.Messages)
+}
+
+// MarkConversationRead godoc
+// @Summary Mark a conversation as read
+// @Description Moves the user's read position forward to message_id (or to the latest message when omitted) and sends a read receipt to the other participants
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Conversation ID"
+// @Param request body entity.MarkReadRequest false "Last read message"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/conversations/{id}/read [post]
+func (h *MessageHandler) MarkConversationRead(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	convID, _, ok := roomParams(c, false)
+	if !ok {
+		return
+	}
+	var req entity.MarkReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+
+	if _, err := h.markRead(userID, convID, req.MessageID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Marked as read"})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockConversationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockConversationUseCase) CreateConversation(userID int64, participantIDs []int64, name string) (*entity.Conversation, error) {
+	args := m.Called(userID, participantIDs, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Conversation), args.Error(1)
+}
+
+func (m *MockConversationUseCase) ListConversations(userID int64) ([]entity.Conversation, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Conversation), args.Error(1)
+}
+
+func TestMessageHandler_ConversationEndpoints(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	rooms := new(MockRoomUseCase)
+	conversations := new(MockConversationUseCase)
+	reads := new(MockReadUseCase)
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.MatchedBy(func(req *proto.ValidateSessionRequest) bool {
+		return req.Token == "token"
+	})).Return(&proto.ValidateSessionResponse{Valid: true, UserId: 5, UserRole: "user"}, nil)
+
+	handler := NewMessageHandler(uc, authClient)
+	handler.Rooms = rooms
+	handler.Conversations = conversations
+	handler.Reads = reads
+
+	router := gin.Default()
+	router.GET("/conversations", handler.GetConversations)
+	router.POST("/conversations", handler.CreateConversation)
+	router.GET("/conversations/:id/messages", handler.GetConversationMessages)
+	router.POST("/conversations/:id/read", handler.MarkConversationRead)
+
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	conversations.On("ListConversations", int64(5)).Return(nil, nil).Once()
+	conversations.On("CreateConversation", int64(5), []int64{7}, "").
+		Return(&entity.Conversation{ID: 4, Kind: entity.RoomKindDirect, Participants: []int64{5, 7}}, nil).Once()
+	conversations.On("CreateConversation", int64(5), []int64{5}, "").Return(nil, usecase.ErrInvalidConversation).Once()
+	rooms.On("CheckAccess", 4, int64(5)).Return(nil).Times(3)
+	rooms.On("CheckAccess", 6, int64(5)).Return(usecase.ErrRoomForbidden).Once()
+	uc.On("GetMessages", entity.MessageQuery{RoomID: 4}).
+		Return(&entity.MessagePage{Messages: []entity.Message{{ID: 12, RoomID: 4, Message: "hi"}}, Total: 1}, nil).Once()
+	// Чтение истории и явная отметка сдвигают позицию до сообщения 12
+	reads.On("MarkRead", 4, int64(5), 12).Return(&entity.ReadPosition{RoomID: 4, UserID: 5, LastReadSeq: 3, LastReadMessageID: 12}, true, nil).Once()
+	reads.On("MarkRead", 4, int64(5), 12).Return(&entity.ReadPosition{RoomID: 4, UserID: 5, LastReadSeq: 3, LastReadMessageID: 12}, false, nil).Once()
+
+	w := call("GET", "/conversations", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+
+	w = call("POST", "/conversations", `{"user_ids":[7]}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"kind":"direct"`)
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/conversations", `{"user_ids":[5]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/conversations", `{}`).Code)
+
+	w = call("GET", "/conversations/4/messages", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"room_id":4`)
+	assert.Equal(t, http.StatusForbidden, call("GET", "/conversations/6/messages", "").Code)
+
+	assert.Equal(t, http.StatusOK, call("POST", "/conversations/4/read", `{"message_id":12}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/conversations/4/read", `{"message_id":"x"}`).Code)
+
+	conversations.AssertExpectations(t)
+	reads.AssertExpectations(t)
+	rooms.AssertExpectations(t)
+	uc.AssertExpectations(t)
+}
@@ -5,14 +5,19 @@ import (
 	"context"
 
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
 	myWeb "github.com/jaliks17/ffffforum/backend/chat-service/pkg/websocket"
 
 	pb "github.com/jaliks17/ffffforum/backend/proto"
 
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -22,10 +27,24 @@ import (
 type MessageHandler struct {
 	Uc usecase.MessageUseCase
 	AuthClient pb.AuthServiceClient
+	// Rooms - комнаты и членство в них; при nil доступна только общая комната
+	Rooms usecase.RoomUseCase
+	// Conversations - личные и групповые переписки; нужен вместе с Rooms
+	Conversations usecase.ConversationUseCase
+	// Hub - соединения WebSocket и рассылка по ним
+	Hub *myWeb.Hub
+	// Presence - время последнего визита пользователей; при nil оно не сохраняется
+	Presence usecase.PresenceUseCase
+	// Reads - позиции прочтения и счетчики непрочитанных
+	Reads usecase.ReadUseCase
+
+	typingLimiter typingLimiter
 }
 
+// NewMessageHandler создает обработчик с собственным хабом соединений; хаб с другими
+// настройками можно подставить в поле Hub до запуска сервера.
 func NewMessageHandler(uc usecase.MessageUseCase, authClient pb.AuthServiceClient) *MessageHandler {
-	return &MessageHandler{Uc: uc, AuthClient: authClient}
+	return &MessageHandler{Uc: uc, AuthClient: authClient, Hub: myWeb.NewHub(myWeb.HubConfig{})}
 }
 
 // Define a struct for the incoming message format from the frontend
@@ -34,6 +53,22 @@ type incomingMessage struct {
 	Message string `json:"message"`
 	Timestamp string `json:"timestamp"`
 	Username string `json:"username"`
+	// AttachmentIDs - ID вложений, заранее загруженных через POST /api/v1/attachments форума
+	AttachmentIDs []int64 `json:"attachment_ids"`
+	// Topic - тема для сообщений subscribe/unsubscribe, например "poll:12"
+	Topic string `json:"topic"`
+	// RoomID - комната для сообщений message/join/leave/resume; 0 означает общую комнату
+	RoomID int `json:"room_id"`
+	// IdempotencyKey - ключ сообщения message: повтор с тем же ключом не создает второе сообщение
+	IdempotencyKey string `json:"idempotency_key"`
+	// LastSeq - для resume: seq последнего полученного сообщения комнаты
+	LastSeq int64 `json:"last_seq"`
+	// Status - для presence: "online" или "away"
+	Status string `json:"status"`
+	// Typing - для typing: true, пока пользователь набирает сообщение
+	Typing bool `json:"typing"`
+	// MessageID - для read: последнее прочитанное сообщение; 0 - последнее в комнате
+	MessageID int `json:"message_id"`
 }
 
 func (h *MessageHandler) HandleConnections(c *gin.Context) {
@@ -48,88 +83,52 @@ func (h *MessageHandler) HandleConnections(c *gin.Context) {
 			log.Printf("Failed to upgrade connection: %v", err)
 			return
 		}
+
+		log.Printf("WebSocket connection upgraded successfully for demo user")
+		client, err := h.Hub.Register(ws, 1)
+		if err != nil {
+			log.Printf("Failed to add demo client to pool: %v", err)
+			rejectConnection(ws)
+			return
+		}
 		defer func() {
-			log.Printf("WebSocket connection closed and cleaning up for demo user")
-			myWeb.CloseConnection(ws)
+			h.Hub.Unregister(client)
 			log.Printf("WebSocket connection closed and cleaned up complete for demo user")
 		}()
-
-		log.Printf("WebSocket connection upgraded successfully for demo user")
-		if !myWeb.AddClient(ws, 1) {
-			log.Printf("Failed to add client to pool or client already exists")
+		h.Hub.JoinRoom(client, entity.GeneralRoomID)
+		if client.Protocol != "" {
+			h.serveSession(ws, newSession(client, 1, "Demo User"))
 			return
 		}
 
-		// Создаем канал для сигнала завершения
-		done := make(chan struct{})
-
-		// Запускаем горутину для отправки пингов
-		go func() {
-			ticker := time.NewTicker(30 * time.Second)
-			defer ticker.Stop()
-
-			for {
-				select {
-				case <-ticker.C:
-					if err := ws.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(10*time.Second)); err != nil {
-						log.Printf("Error sending ping to demo user: %v", err)
-						select {
-						case <-done:
-						default:
-							close(done)
-						}
-						return
-					}
-				case <-done:
-					log.Printf("Ping goroutine for demo user stopping")
-					return
+		for {
+			var incMsg incomingMessage
+			err := ws.ReadJSON(&incMsg)
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					log.Printf("Client closed connection normally")
+				} else {
+					log.Printf("Error reading message from demo user: %v", err)
 				}
+				return
 			}
-		}()
 
-		// Горутина для чтения сообщений
-		go func() {
-			defer func() {
-				log.Printf("Message reading goroutine for demo user stopping")
-				select {
-				case <-done:
-				default:
-					close(done)
-				}
-				myWeb.CloseConnection(ws)
-			}()
-
-			for {
-				var incMsg incomingMessage
-				err := ws.ReadJSON(&incMsg)
-				if err != nil {
-					if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-						log.Printf("Client closed connection normally")
-					} else {
-						log.Printf("Error reading message from demo user: %v", err)
-					}
-					return
-				}
-
-				// Создаем entity.Message для сохранения и рассылки
-				msg := &entity.Message{
-					UserID:   1,
-					Username: "Demo User",
-					Message:  incMsg.Message,
-				}
-
-				log.Printf("Received and parsed message from demo user: %+v", msg)
-				if err := h.Uc.SaveMessage(msg); err != nil {
-					log.Printf("Error saving message from demo user: %v", err)
-					continue
-				}
-				myWeb.Broadcast <- *msg
+			// Создаем entity.Message для сохранения и рассылки
+			msg := &entity.Message{
+				RoomID:        entity.GeneralRoomID,
+				UserID:        1,
+				Username:      "Demo User",
+				Message:       incMsg.Message,
+				AttachmentIDs: incMsg.AttachmentIDs,
 			}
-		}()
 
-		<-done
-		log.Printf("HandleConnections for demo user finished")
-		return
+			log.Printf("Received and parsed message from demo user: %+v", msg)
+			if err := h.Uc.SaveMessage(msg); err != nil {
+				log.Printf("Error saving message from demo user: %v", err)
+				continue
+			}
+			h.broadcast(*msg)
+		}
 	}
 
 	// Валидация токена с помощью Auth Service
@@ -175,151 +174,375 @@ func (h *MessageHandler) HandleConnections(c *gin.Context) {
 		log.Printf("Failed to upgrade connection: %v", err)
 		return
 	}
+	log.Printf("WebSocket connection upgraded successfully for user %d", userID)
+
+	// Добавляем клиента в хаб; остальные соединения пользователя остаются открытыми
+	client, err := h.Hub.Register(ws, int(userID))
+	if err != nil {
+		log.Printf("Failed to add client to pool for user %d: %v", userID, err)
+		rejectConnection(ws)
+		return
+	}
 	defer func() {
-		log.Printf("WebSocket connection closed and cleaning up for user %d", userID)
-		if ws != nil {
-			myWeb.CloseConnection(ws)
-		}
+		h.Hub.Unregister(client)
+		h.recordLastSeen(userID)
 		log.Printf("WebSocket connection closed and cleaned up complete for user %d", userID)
 	}()
+	h.joinUserRooms(client, userID)
 
-	log.Printf("WebSocket connection upgraded successfully for user %d", userID)
-	
-	// Добавляем клиента в пул
-	if !myWeb.AddClient(ws, int(userID)) {
-		log.Printf("Failed to add client to pool for user %d", userID)
-		ws.Close()
-		c.AbortWithStatus(http.StatusInternalServerError)
+	// Клиент выбрал версионированный протокол через Sec-WebSocket-Protocol
+	if client.Protocol != "" {
+		h.serveSession(ws, newSession(client, userID, username))
 		return
 	}
 
-	// Создаем канал для сигнала завершения
-	done := make(chan struct{})
-
-	// Запускаем горутину для отправки пингов
-	go func() {
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				if err := ws.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(10*time.Second)); err != nil {
-					log.Printf("Error sending ping to user %d: %v", userID, err)
-					select {
-					case <-done:
-					default:
-						close(done)
-					}
-					return
-				}
-			case <-done:
-				log.Printf("Ping goroutine for user %d stopping", userID)
-				return
+	// Читаем сообщения в этой горутине; пишет в соединение только writePump хаба,
+	// он же отправляет ping
+	for {
+		messageType, p, err := ws.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("Client %d closed connection normally", userID)
+			} else {
+				log.Printf("Error reading message from user %d: %v", userID, err)
 			}
+			return
 		}
-	}()
+		// Любое сообщение от клиента продлевает таймаут чтения
+		ws.SetReadDeadline(time.Now().Add(myWeb.PongWait))
 
-	// Горутина для чтения сообщений
-	go func() {
-		defer func() {
-			log.Printf("Message reading goroutine for user %d stopping", userID)
-			select {
-			case <-done:
-			default:
-				close(done)
-			}
-			myWeb.CloseConnection(ws)
-		}()
+		// Only process text messages
+		if messageType != websocket.TextMessage {
+			log.Printf("Received non-text message from user %d. Type: %d", userID, messageType)
+			continue
+		}
 
-		log.Printf("Starting message reading goroutine for user %d", userID)
+		var incMsg incomingMessage
+		if err := json.Unmarshal(p, &incMsg); err != nil {
+			log.Printf("Error unmarshalling message from user %d: %v", userID, err)
+			continue
+		}
 
-		for {
-			// Устанавливаем таймаут на чтение следующего сообщения
-			ws.SetReadDeadline(time.Now().Add(120 * time.Second))
-			// Устанавливаем таймаут на запись сообщения
-			ws.SetWriteDeadline(time.Now().Add(30 * time.Second))
+		log.Printf("Received valid message from user %d: %+v", userID, incMsg)
 
-			log.Printf("Waiting to read message from user %d", userID)
+		// Любое сообщение, кроме сообщения о статусе, означает, что пользователь активен
+		if incMsg.Type != "presence" {
+			h.Hub.Touch(client)
+		}
 
-			messageType, p, err := ws.ReadMessage()
+		// Обработка сообщения в зависимости от типа
+		switch incMsg.Type {
+		case "message":
+			roomID := roomOrGeneral(incMsg.RoomID)
+			msg := &entity.Message{
+				RoomID:        roomID,
+				UserID:        int(userID),
+				Username:      username,
+				Message:       incMsg.Message,
+				AttachmentIDs: incMsg.AttachmentIDs,
+				ClientMsgID:   incMsg.IdempotencyKey,
+			}
+			err := h.postMessage(msg)
+			switch {
+			case err == nil:
+				h.broadcast(*msg)
+			case errors.Is(err, repository.ErrDuplicateMessage):
+				// Повтор после обрыва: сообщение уже разослано
+				log.Printf("User %d resent message %d, skipping broadcast", userID, msg.ID)
+			case errors.Is(err, usecase.ErrMessageRejected), errors.Is(err, usecase.ErrInvalidAttachments):
+				h.Hub.SendToClient(client, entity.MessageRejectedEvent{Type: "message_rejected", Reason: err.Error()})
+			case errorCode(err) != protocol.CodeInternal:
+				// Нет доступа к комнате; сбой сохранения только записывается в лог
+				h.Hub.SendToClient(client, entity.RoomEvent{Type: "room_error", RoomID: roomID, Error: err.Error()})
+			}
+		case "subscribe":
+			h.subscribe(client, userID, incMsg.Topic)
+		case "unsubscribe":
+			h.Hub.Unsubscribe(client, incMsg.Topic)
+		case "join":
+			roomID := roomOrGeneral(incMsg.RoomID)
+			h.sendRoomError(client, roomID, h.joinRoom(userID, roomID))
+		case "leave":
+			roomID := roomOrGeneral(incMsg.RoomID)
+			h.sendRoomError(client, roomID, h.leaveRoom(userID, roomID))
+		case "resume":
+			roomID := roomOrGeneral(incMsg.RoomID)
+			if result := h.replay(client, userID, protocol.RoomCursor{RoomID: roomID, LastSeq: incMsg.LastSeq}); result.Error != "" {
+				h.Hub.SendToClient(client, entity.RoomEvent{Type: "room_error", RoomID: roomID, Error: result.Error})
+			}
+		case "typing":
+			roomID := roomOrGeneral(incMsg.RoomID)
+			h.sendRoomError(client, roomID, h.typing(client, userID, username, roomID, incMsg.Typing))
+		case "presence":
+			if err := h.setStatus(client, incMsg.Status); err != nil {
+				log.Printf("User %d sent invalid status %q", userID, incMsg.Status)
+			}
+		case "read":
+			roomID := roomOrGeneral(incMsg.RoomID)
+			if _, err := h.markRead(userID, roomID, incMsg.MessageID); err != nil {
+				h.sendRoomError(client, roomID, err)
+			}
+		case "unread":
+			counts, err := h.unreadCounts(userID)
 			if err != nil {
-				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-					log.Printf("Client %d closed connection normally", userID)
-				} else {
-					log.Printf("Error reading message from user %d: %v", userID, err)
-				}
-				return
+				log.Printf("Failed to count unread messages of user %d: %v", userID, err)
+				continue
 			}
+			h.Hub.SendToClient(client, entity.UnreadEvent{Type: "unread", Rooms: counts})
+		default:
+			log.Printf("Received unknown message type from user %d: %s", userID, incMsg.Type)
+		}
+	}
+}
 
-			// Only process text messages
-			if messageType == websocket.TextMessage {
-				var incMsg incomingMessage
-				err = json.Unmarshal(p, &incMsg)
-				if err != nil {
-					log.Printf("Error unmarshalling message from user %d: %v", userID, err)
-					continue
-				}
+// postMessage проверяет доступ к комнате и сохраняет сообщение пользователя. Если
+// сообщение с тем же ClientMsgID уже сохранено, msg заполняется им и возвращается
+// repository.ErrDuplicateMessage.
+func (h *MessageHandler) postMessage(msg *entity.Message) error {
+	userID := int64(msg.UserID)
+	if err := h.checkRoomAccess(msg.RoomID, userID); err != nil {
+		log.Printf("User %d cannot post to room %d: %v", userID, msg.RoomID, err)
+		return err
+	}
+	if len(msg.ClientMsgID) > protocol.MaxIDLength {
+		return fmt.Errorf("%w: idempotency key must be at most %d characters", errBadRequest, protocol.MaxIDLength)
+	}
+	msg.Timestamp = time.Now()
+	err := h.Uc.SaveMessage(msg)
+	if err != nil && !errors.Is(err, usecase.ErrMessageRejected) && !errors.Is(err, usecase.ErrInvalidAttachments) &&
+		!errors.Is(err, repository.ErrDuplicateMessage) {
+		log.Printf("Error saving message for user %d: %v", userID, err)
+	}
+	return err
+}
 
-				log.Printf("Received valid message from user %d: %+v", userID, incMsg)
-
-				// Обработка сообщения в зависимости от типа
-				switch incMsg.Type {
-				case "message":
-					msg := &entity.Message{
-						UserID:    int(userID),
-						Username:  username,
-						Message:   incMsg.Message,
-						Timestamp: time.Now(),
-					}
-
-					if err := h.Uc.SaveMessage(msg); err != nil {
-						log.Printf("Error saving message for user %d: %v", userID, err)
-						continue
-					}
-
-					myWeb.Broadcast <- *msg
-				default:
-					log.Printf("Received unknown message type from user %d: %s", userID, incMsg.Type)
-				}
-			} else {
-				log.Printf("Received non-text message from user %d. Type: %d", userID, messageType)
-			}
-		}
-	}()
+// replay отправляет соединению одним событием replay сообщения комнаты, пропущенные
+// после cursor.LastSeq. Ошибка доступа или чтения возвращается кодом в RoomResume.Error.
+func (h *MessageHandler) replay(client *myWeb.Client, userID int64, cursor protocol.RoomCursor) protocol.RoomResume {
+	result := protocol.RoomResume{RoomID: cursor.RoomID}
+	if err := h.checkRoomAccess(cursor.RoomID, userID); err != nil {
+		result.Error = errorCode(err)
+		return result
+	}
+	messages, err := h.Uc.GetRoomMessagesAfter(cursor.RoomID, cursor.LastSeq, protocol.MaxReplay+1)
+	if err != nil {
+		log.Printf("Error loading messages of room %d after seq %d: %v", cursor.RoomID, cursor.LastSeq, err)
+		result.Error = protocol.CodeInternal
+		return result
+	}
+	if len(messages) > protocol.MaxReplay {
+		messages = messages[:protocol.MaxReplay]
+		result.Truncated = true
+	}
+	if messages == nil {
+		messages = []entity.Message{}
+	}
+	h.Hub.SendToClient(client, entity.ReplayEvent{Type: "replay", RoomID: cursor.RoomID, Messages: messages, Truncated: result.Truncated})
+	result.Replayed = len(messages)
+	return result
+}
+
+// subscribe подписывает соединение на тему, например "poll:12" или "presence:7"
+func (h *MessageHandler) subscribe(client *myWeb.Client, userID int64, topic string) error {
+	if !entity.IsValidTopic(topic) && !entity.IsPresenceTopic(topic) {
+		log.Printf("User %d tried to subscribe to unknown topic %q", userID, topic)
+		return fmt.Errorf("%w: unknown topic %q", errBadRequest, topic)
+	}
+	if !h.Hub.Subscribe(client, topic) {
+		log.Printf("User %d reached the subscription limit", userID)
+		return errTooManySubscriptions
+	}
+	return nil
+}
 
-	<-done
-	log.Printf("HandleConnections for user %d finished", userID)
+// broadcast рассылает сохраненное сообщение соединениям его комнаты и уведомляет
+// упомянутых пользователей
+func (h *MessageHandler) broadcast(msg entity.Message) {
+	msg.RoomID = roomOrGeneral(msg.RoomID)
+	recipients := h.Hub.BroadcastRoom(msg.RoomID, msg)
+	log.Printf("Broadcast message %d to %d connections in room %d", msg.ID, recipients, msg.RoomID)
+	h.notifyMentions(msg)
 }
 
-func (h *MessageHandler) HandleMessages() {
-	for {
-		msg := <-myWeb.Broadcast
-		log.Printf("Broadcasting message: %+v", msg)
-		for client := range myWeb.Clients {
-			if err := myWeb.SendMessage(client, msg); err != nil {
-				log.Printf("Error broadcasting message: %v", err)
-				myWeb.CloseConnection(client)
-			}
-		}
+// rejectConnection закрывает соединение, которое хаб не принял
+func rejectConnection(ws *websocket.Conn) {
+	ws.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "Too many connections"),
+		time.Now().Add(time.Second))
+	ws.Close()
+}
+
+// notifyMentions отправляет упомянутым пользователям, находящимся в сети, событие "mention".
+// Уведомление получают только соединения, подключенные к комнате, иначе текст приватной
+// комнаты попал бы к посторонним.
+func (h *MessageHandler) notifyMentions(msg entity.Message) {
+	event := entity.MentionEvent{
+		Type:      "mention",
+		MessageID: msg.ID,
+		UserID:    msg.UserID,
+		Username:  msg.Username,
+		Message:   msg.Message,
+	}
+	for _, userID := range msg.Mentions {
+		h.Hub.SendToUserInRoom(int(userID), msg.RoomID, event)
 	}
 }
 
+// PushEvent godoc
+// @Summary Push an event to a user
+// @Description Internal endpoint used by other services to deliver an event to a connected user over WebSocket
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param X-Internal-Token header string true "Shared internal token"
+// @Param request body entity.PushRequest true "Recipient and event"
+// @Success 200 {object} entity.PushResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Router /internal/push [post]
+func (h *MessageHandler) PushEvent(c *gin.Context) {
+	var req entity.PushRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	// Пользователь может быть подключен к другому экземпляру: событие дойдет через Backplane
+	if !h.Hub.IsOnline(int(req.UserID)) {
+		c.JSON(http.StatusOK, entity.PushResponse{Online: false})
+		return
+	}
+	h.Hub.SendToUser(int(req.UserID), req.Event)
+	c.JSON(http.StatusOK, entity.PushResponse{Online: true})
+}
+
+// PublishEvent godoc
+// @Summary Publish an event to topic subscribers
+// @Description Internal endpoint used by other services to deliver an event to every WebSocket connection subscribed to the topic. Clients subscribe by sending {"type":"subscribe","topic":"poll:12"}
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param X-Internal-Token header string true "Shared internal token"
+// @Param request body entity.PublishRequest true "Topic and event"
+// @Success 200 {object} entity.PublishResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Router /internal/publish [post]
+func (h *MessageHandler) PublishEvent(c *gin.Context) {
+	var req entity.PublishRequest
+	if err := c.ShouldBindJSON(&req); err != nil || !entity.IsValidTopic(req.Topic) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	subscribers := h.Hub.Publish(req.Topic, req.Event)
+	c.JSON(http.StatusOK, entity.PublishResponse{Subscribers: subscribers})
+}
+
+// GetMetrics godoc
+// @Summary WebSocket hub metrics
+// @Description Internal endpoint with the number of open connections, online users, active rooms and topics, and delivery counters including slow consumers disconnected for not reading their queue
+// @Tags internal
+// @Produce json
+// @Param X-Internal-Token header string true "Shared internal token"
+// @Success 200 {object} websocket.Stats
+// @Failure 401 {object} entity.ErrorResponse
+// @Router /internal/metrics [get]
+func (h *MessageHandler) GetMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Hub.Stats())
+}
+
+// GetInternalMessage godoc
+// @Summary Get a chat message
+// @Description Internal endpoint used by the forum moderation queue to look up a reported chat message
+// @Tags internal
+// @Produce json
+// @Param X-Internal-Token header string true "Shared internal token"
+// @Param id path int true "Message ID"
+// @Success 200 {object} entity.Message
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /internal/messages/{id} [get]
+func (h *MessageHandler) GetInternalMessage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	msg, err := h.Uc.GetMessage(id)
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, msg)
+}
+
+// DeleteInternalMessage godoc
+// @Summary Delete a chat message
+// @Description Internal endpoint used by forum moderators to remove a reported chat message
+// @Tags internal
+// @Produce json
+// @Param X-Internal-Token header string true "Shared internal token"
+// @Param id path int true "Message ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /internal/messages/{id} [delete]
+func (h *MessageHandler) DeleteInternalMessage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	err = h.Uc.DeleteMessage(id)
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Message deleted"})
+}
+
 // GetMessages godoc
 // @Summary Get chat messages history
-// @Description Get a list of recent chat messages
+// @Description Get a page of the general room history in chronological order. Without a cursor returns the latest messages; offset counts back from the newest one
 // @Tags messages
 // @Accept json
 // @Produce json
+// @Param before query int false "Messages older than this message ID"
+// @Param after query int false "Messages newer than this message ID"
+// @Param around query int false "Messages around this message ID, including it"
+// @Param limit query int false "Page size" default(50)
+// @Param offset query int false "Offset from the newest message, without a cursor" default(0)
 // @Success 200 {array} entity.Message "List of messages"
+// @Header 200 {integer} X-Total-Count "Total messages in the room"
+// @Failure 400 {object} entity.ErrorResponse "Invalid query"
+// @Failure 404 {object} entity.ErrorResponse "Cursor message not found"
 // @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
 // @Router /api/v1/messages [get]
 func (h *MessageHandler) GetMessages(c *gin.Context) {
-	messages, err := h.Uc.GetMessages()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	q, ok := historyQuery(c, entity.GeneralRoomID)
+	if !ok {
 		return
 	}
+	page, ok := h.getHistory(c, q)
+	if !ok {
+		return
+	}
+	messages := page.Messages
 	// Временно преобразуем UserID и Username для соответствия фронтенду, ожидающему author_id и author_name
 	// TODO: Обновить фронтенд для использования UserID и Username
 	formattedMessages := make([]map[string]interface{}, len(messages))
@@ -330,7 +553,49 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 			"author_name": msg.Username,
 			"message":    msg.Message,
 			"timestamp":  msg.Timestamp,
+			"attachment_ids": msg.AttachmentIDs,
 		}
 	}
 	c.JSON(http.StatusOK, formattedMessages)
-}
\ No newline at end of file
+}
+
+// historyQuery читает параметры страницы истории: before, after, around, limit и offset
+func historyQuery(c *gin.Context, roomID int) (entity.MessageQuery, bool) {
+	q := entity.MessageQuery{RoomID: roomID}
+	params := []struct {
+		name  string
+		value *int
+	}{{"before", &q.Before}, {"after", &q.After}, {"around", &q.Around}, {"limit", &q.Limit}, {"offset", &q.Offset}}
+	for _, p := range params {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + p.name})
+			return q, false
+		}
+		*p.value = n
+	}
+	return q, true
+}
+
+// getHistory загружает страницу истории и передает общее число сообщений комнаты
+// в заголовке X-Total-Count; при ошибке отвечает сам
+func (h *MessageHandler) getHistory(c *gin.Context, q entity.MessageQuery) (*entity.MessagePage, bool) {
+	page, err := h.Uc.GetMessages(q)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidHistoryQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	case errors.Is(err, repository.ErrMessageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	c.Header("X-Total-Count", strconv.Itoa(page.Total))
+	return page, true
+}
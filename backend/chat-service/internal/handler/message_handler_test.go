@@ -6,10 +6,13 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
 	myWeb "github.com/jaliks17/ffffforum/backend/chat-service/pkg/websocket"
 	"github.com/jaliks17/ffffforum/backend/proto"
 
@@ -33,14 +36,35 @@ func (m *MockMessageUseCase) SaveMessage(msg *entity.Message) error {
 	return args.Error(0)
 }
 
-func (m *MockMessageUseCase) GetMessages() ([]entity.Message, error) {
-	args := m.Called()
+func (m *MockMessageUseCase) GetMessages(q entity.MessageQuery) (*entity.MessagePage, error) {
+	args := m.Called(q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.MessagePage), args.Error(1)
+}
+
+func (m *MockMessageUseCase) GetRoomMessagesAfter(roomID int, afterSeq int64, limit int) ([]entity.Message, error) {
+	args := m.Called(roomID, afterSeq, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]entity.Message), args.Error(1)
 }
 
+func (m *MockMessageUseCase) GetMessage(id int) (*entity.Message, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageUseCase) DeleteMessage(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockMessageUseCase) DeleteOldMessages(before time.Time) error {
 	args := m.Called(before)
 	return args.Error(0)
@@ -91,13 +115,23 @@ func (m *MockAuthServiceClient) ValidateSession(ctx context.Context, in *proto.V
 	return args.Get(0).(*proto.ValidateSessionResponse), args.Error(1)
 }
 
+func (m *MockAuthServiceClient) GetUsersByUsernames(ctx context.Context, in *proto.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*proto.GetUsersByUsernamesResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*proto.GetUsersByUsernamesResponse), args.Error(1)
+}
+
+func (m *MockAuthServiceClient) SuspendUser(ctx context.Context, in *proto.SuspendUserRequest, opts ...grpc.CallOption) (*proto.SuspendUserResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*proto.SuspendUserResponse), args.Error(1)
+}
+
 func TestMessageHandler_GetMessages(t *testing.T) {
 	uc := new(MockMessageUseCase)
 	authClient := new(MockAuthServiceClient)
 
-	uc.On("GetMessages").Return([]entity.Message{
+	uc.On("GetMessages", entity.MessageQuery{RoomID: entity.GeneralRoomID}).Return(&entity.MessagePage{Messages: []entity.Message{
 		{ID: 1, UserID: 1, Username: "testuser", Message: "Hello, World!"},
-	}, nil)
+	}, Total: 12}, nil)
 
 	handler := NewMessageHandler(uc, authClient)
 
@@ -111,6 +145,7 @@ func TestMessageHandler_GetMessages(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "12", w.Header().Get("X-Total-Count"))
 	var resp []map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Equal(t, 1, len(resp))
@@ -164,28 +199,33 @@ func TestMessageHandler_HandleConnections(t *testing.T) {
 	authClient.AssertExpectations(t)
 }
 
-func TestMessageHandler_HandleMessages(t *testing.T) {
-	// Создаем мок usecase
+func TestMessageHandler_Broadcast(t *testing.T) {
 	uc := new(MockMessageUseCase)
 	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: true, UserId: 1, UserRole: "testrole"}, nil).Once()
+	authClient.On("GetUserProfile", mock.Anything, mock.Anything).
+		Return(&proto.GetUserProfileResponse{User: &proto.User{Id: 1, Username: "testuser"}}, nil).Once()
 
-	// Создаем MessageHandler
 	handler := NewMessageHandler(uc, authClient)
 
-	// Создаем канал для тестирования
-	broadcast := make(chan entity.Message)
-	myWeb.Broadcast = broadcast
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	server := httptest.NewServer(router)
+	defer server.Close()
 
-	// Запускаем горутину для обработки сообщений
-	go handler.HandleMessages()
+	ws := dialAs(t, handler.Hub, server, "valid_token", 1)
+	defer ws.Close()
 
-	// Отправляем сообщение в канал
-	msg := entity.Message{UserID: 1, Username: "testuser", Message: "Hello, World!"}
-	broadcast <- msg
+	// Сообщение без комнаты уходит в общую комнату
+	handler.broadcast(entity.Message{ID: 1, UserID: 1, Username: "testuser", Message: "Hello, World!"})
 
-	// Проверяем, что сообщение было отправлено всем клиентам
-	// (здесь можно добавить проверку для клиентов, если они есть)
-	uc.AssertExpectations(t)
+	var msg entity.Message
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	assert.NoError(t, ws.ReadJSON(&msg))
+	assert.Equal(t, "Hello, World!", msg.Message)
+	assert.Equal(t, entity.GeneralRoomID, msg.RoomID)
+	assert.Equal(t, uint64(1), handler.Hub.Stats().Accepted)
 }
 
 func TestMessageHandler_HandleConnections_DemoToken(t *testing.T) {
@@ -238,7 +278,7 @@ func TestMessageHandler_HandleConnections_DemoToken(t *testing.T) {
 func TestMessageHandler_GetMessages_Error(t *testing.T) {
 	uc := new(MockMessageUseCase)
 	authClient := new(MockAuthServiceClient)
-	uc.On("GetMessages").Return(nil, errors.New("database error"))
+	uc.On("GetMessages", mock.Anything).Return(nil, errors.New("database error"))
 
 	handler := NewMessageHandler(uc, authClient)
 	router := gin.Default()
@@ -252,6 +292,38 @@ func TestMessageHandler_GetMessages_Error(t *testing.T) {
 	uc.AssertExpectations(t)
 }
 
+func TestMessageHandler_GetMessages_Pagination(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	uc.On("GetMessages", entity.MessageQuery{RoomID: entity.GeneralRoomID, Before: 40, Limit: 20}).
+		Return(&entity.MessagePage{Messages: []entity.Message{{ID: 39}}, Total: 40}, nil)
+	uc.On("GetMessages", entity.MessageQuery{RoomID: entity.GeneralRoomID, Limit: 20, Offset: 20}).
+		Return(&entity.MessagePage{Messages: []entity.Message{{ID: 20}}, Total: 40}, nil)
+	uc.On("GetMessages", entity.MessageQuery{RoomID: entity.GeneralRoomID, Before: 1, After: 2}).
+		Return(nil, usecase.ErrInvalidHistoryQuery)
+	uc.On("GetMessages", entity.MessageQuery{RoomID: entity.GeneralRoomID, Around: 99}).
+		Return(nil, repository.ErrMessageNotFound)
+
+	handler := NewMessageHandler(uc, new(MockAuthServiceClient))
+	router := gin.Default()
+	router.GET("/messages", handler.GetMessages)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/messages?"+query, nil))
+		return w
+	}
+
+	w := get("before=40&limit=20")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":39`)
+	w = get("limit=20&offset=20")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "40", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, http.StatusBadRequest, get("before=1&after=2").Code)
+	assert.Equal(t, http.StatusBadRequest, get("limit=ten").Code)
+	assert.Equal(t, http.StatusNotFound, get("around=99").Code)
+	uc.AssertExpectations(t)
+}
+
 func TestMessageHandler_HandleConnections_InvalidToken(t *testing.T) {
 	uc := new(MockMessageUseCase)
 	authClient := new(MockAuthServiceClient)
@@ -517,4 +589,314 @@ func TestMessageHandler_HandleConnections_SaveMessageError(t *testing.T) {
 
 	uc.AssertExpectations(t)
 	authClient.AssertExpectations(t)
-}
\ No newline at end of file
+}
+func TestMessageHandler_HandleMessages_Mentions(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: true, UserId: 7, UserRole: "user"}, nil).Once()
+	authClient.On("GetUserProfile", mock.Anything, mock.Anything).
+		Return(&proto.GetUserProfileResponse{User: &proto.User{Id: 7, Username: "alice"}}, nil).Once()
+
+	handler := NewMessageHandler(uc, authClient)
+
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[4:]+"/ws?token=alice_token", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+
+	// Ждем, пока соединение попадет в пул
+	assert.Eventually(t, func() bool { return handler.Hub.IsOnline(7) }, time.Second, 10*time.Millisecond)
+
+	handler.broadcast(entity.Message{ID: 3, UserID: 1, Username: "bob", Message: "hi @alice", Mentions: []int64{7}})
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	var msg entity.Message
+	assert.NoError(t, ws.ReadJSON(&msg))
+	assert.Equal(t, []int64{7}, msg.Mentions)
+
+	var event entity.MentionEvent
+	assert.NoError(t, ws.ReadJSON(&event))
+	assert.Equal(t, "mention", event.Type)
+	assert.Equal(t, 3, event.MessageID)
+	assert.Equal(t, "bob", event.Username)
+}
+
+func TestMessageHandler_PushEvent(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: true, UserId: 8, UserRole: "user"}, nil).Once()
+	authClient.On("GetUserProfile", mock.Anything, mock.Anything).
+		Return(&proto.GetUserProfileResponse{User: &proto.User{Id: 8, Username: "carol"}}, nil).Once()
+
+	handler := NewMessageHandler(uc, authClient)
+
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	router.POST("/internal/push", RequireInternalToken("secret"), handler.PushEvent)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	push := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/internal/push", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set(InternalTokenHeader, token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	event := `{"user_id":8,"event":{"type":"notification","unread_count":2}}`
+
+	w := push("wrong", event)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = push("secret", `{"event":{}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Пользователь не в сети
+	w = push("secret", event)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"online":false}`, w.Body.String())
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[4:]+"/ws?token=carol_token", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+	assert.Eventually(t, func() bool { return handler.Hub.IsOnline(8) }, time.Second, 10*time.Millisecond)
+
+	w = push("secret", event)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"online":true}`, w.Body.String())
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	var got map[string]interface{}
+	assert.NoError(t, ws.ReadJSON(&got))
+	assert.Equal(t, "notification", got["type"])
+	assert.Equal(t, float64(2), got["unread_count"])
+}
+
+func TestMessageHandler_PublishEvent(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: true, UserId: 9, UserRole: "user"}, nil).Once()
+	authClient.On("GetUserProfile", mock.Anything, mock.Anything).
+		Return(&proto.GetUserProfileResponse{User: &proto.User{Id: 9, Username: "dave"}}, nil).Once()
+
+	handler := NewMessageHandler(uc, authClient)
+
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	router.POST("/internal/publish", RequireInternalToken("secret"), handler.PublishEvent)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	publish := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/internal/publish", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(InternalTokenHeader, "secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	event := `{"topic":"poll:3","event":{"type":"poll_results","poll":{"total_voters":4}}}`
+
+	w := publish(`{"topic":"users:1","event":{}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Подписчиков пока нет
+	w = publish(event)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"subscribers":0}`, w.Body.String())
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[4:]+"/ws?token=dave_token", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.Close()
+
+	assert.NoError(t, ws.WriteJSON(map[string]string{"type": "subscribe", "topic": "poll:3"}))
+	assert.Eventually(t, func() bool { return handler.Hub.Subscribers("poll:3") == 1 }, time.Second, 10*time.Millisecond)
+
+	w = publish(event)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"subscribers":1}`, w.Body.String())
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	var got map[string]interface{}
+	assert.NoError(t, ws.ReadJSON(&got))
+	assert.Equal(t, "poll_results", got["type"])
+
+	assert.NoError(t, ws.WriteJSON(map[string]string{"type": "unsubscribe", "topic": "poll:3"}))
+	assert.Eventually(t, func() bool { return handler.Hub.Subscribers("poll:3") == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestMessageHandler_InternalMessages(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	handler := NewMessageHandler(uc, new(MockAuthServiceClient))
+
+	router := gin.Default()
+	internal := router.Group("/internal", RequireInternalToken("secret"))
+	internal.GET("/messages/:id", handler.GetInternalMessage)
+	internal.DELETE("/messages/:id", handler.DeleteInternalMessage)
+
+	call := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set(InternalTokenHeader, token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	uc.On("GetMessage", 3).Return(&entity.Message{ID: 3, UserID: 7, Message: "spam"}, nil).Once()
+	uc.On("GetMessage", 4).Return(nil, repository.ErrMessageNotFound).Once()
+	uc.On("DeleteMessage", 3).Return(nil).Once()
+	uc.On("DeleteMessage", 4).Return(repository.ErrMessageNotFound).Once()
+
+	assert.Equal(t, http.StatusUnauthorized, call("GET", "/internal/messages/3", "").Code)
+	assert.Equal(t, http.StatusBadRequest, call("GET", "/internal/messages/abc", "secret").Code)
+
+	w := call("GET", "/internal/messages/3", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var msg entity.Message
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &msg))
+	assert.Equal(t, 7, msg.UserID)
+
+	assert.Equal(t, http.StatusNotFound, call("GET", "/internal/messages/4", "secret").Code)
+	assert.Equal(t, http.StatusUnauthorized, call("DELETE", "/internal/messages/3", "wrong").Code)
+	assert.Equal(t, http.StatusOK, call("DELETE", "/internal/messages/3", "secret").Code)
+	assert.Equal(t, http.StatusNotFound, call("DELETE", "/internal/messages/4", "secret").Code)
+
+	// Без настроенного секрета внутренние endpoint-ы закрыты
+	closed := gin.New()
+	closed.GET("/internal/messages/:id", RequireInternalToken(""), handler.GetInternalMessage)
+	w = httptest.NewRecorder()
+	closed.ServeHTTP(w, httptest.NewRequest("GET", "/internal/messages/3", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestMessageHandler_MultipleConnections(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	rooms := new(MockRoomUseCase)
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: true, UserId: 24, UserRole: "user"}, nil)
+	authClient.On("GetUserProfile", mock.Anything, mock.Anything).
+		Return(&proto.GetUserProfileResponse{User: &proto.User{Id: 24, Username: "hank"}}, nil)
+	rooms.On("UserRoomIDs", int64(24)).Return(nil, nil)
+	rooms.On("JoinRoom", 5, int64(24)).Return(nil).Once()
+	rooms.On("JoinRoom", 6, int64(24)).Return(usecase.ErrRoomForbidden).Once()
+
+	handler := NewMessageHandler(uc, authClient)
+	handler.Rooms = rooms
+
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// Вторая вкладка не выбивает первую
+	desktop := dialAs(t, handler.Hub, server, "hank_token", 24)
+	defer desktop.Close()
+	phone := dialAs(t, handler.Hub, server, "hank_token", 24)
+	defer phone.Close()
+	clients := handler.Hub.UserClients(24)
+	assert.Len(t, clients, 2)
+	assert.NotEqual(t, clients[0].ID, clients[1].ID)
+
+	read := func(ws *websocket.Conn) entity.RoomEvent {
+		var event entity.RoomEvent
+		ws.SetReadDeadline(time.Now().Add(time.Second))
+		assert.NoError(t, ws.ReadJSON(&event))
+		return event
+	}
+
+	// Вступление в комнату из одной вкладки подключает к ней все соединения пользователя
+	assert.NoError(t, desktop.WriteJSON(map[string]interface{}{"type": "join", "room_id": 5}))
+	assert.Equal(t, entity.RoomEvent{Type: "joined", RoomID: 5}, read(desktop))
+	assert.Equal(t, entity.RoomEvent{Type: "joined", RoomID: 5}, read(phone))
+	for _, client := range handler.Hub.UserClients(24) {
+		assert.True(t, handler.Hub.InRoom(client, 5))
+	}
+
+	// Ошибку получает только соединение, приславшее запрос
+	assert.NoError(t, phone.WriteJSON(map[string]interface{}{"type": "join", "room_id": 6}))
+	assert.Equal(t, "room_error", read(phone).Type)
+
+	assert.Equal(t, 2, handler.Hub.SendToUser(24, entity.RoomEvent{Type: "ping", RoomID: 1}))
+	assert.Equal(t, "ping", read(desktop).Type)
+	assert.Equal(t, "ping", read(phone).Type)
+
+	// После закрытия одной вкладки пользователь остается в сети
+	desktop.Close()
+	assert.Eventually(t, func() bool { return handler.Hub.ConnectionCount(24) == 1 }, time.Second, 10*time.Millisecond)
+	assert.True(t, handler.Hub.IsOnline(24))
+	rooms.AssertExpectations(t)
+}
+
+func TestMessageHandler_TwoInstances(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	uc.On("SaveMessage", mock.Anything).Return(nil)
+	authClient := new(MockAuthServiceClient)
+	for _, u := range []struct {
+		token string
+		id    int64
+		name  string
+	}{{"ivy_token", 31, "ivy"}, {"jack_token", 32, "jack"}} {
+		authClient.On("ValidateSession", mock.Anything, mock.MatchedBy(func(req *proto.ValidateSessionRequest) bool {
+			return req.Token == u.token
+		})).Return(&proto.ValidateSessionResponse{Valid: true, UserId: u.id, UserRole: "user"}, nil)
+		authClient.On("GetUserProfile", mock.Anything, mock.MatchedBy(func(req *proto.GetUserProfileRequest) bool {
+			return req.UserId == u.id
+		})).Return(&proto.GetUserProfileResponse{User: &proto.User{Id: u.id, Username: u.name}}, nil)
+	}
+
+	// Два экземпляра сервиса с общим backplane
+	backplane := myWeb.NewMemoryBackplane()
+	servers := make([]*httptest.Server, 2)
+	handlers := make([]*MessageHandler, 2)
+	for i := range handlers {
+		handlers[i] = NewMessageHandler(uc, authClient)
+		handlers[i].Hub.Stop()
+		handlers[i].Hub = myWeb.NewHub(myWeb.HubConfig{Backplane: backplane})
+		defer handlers[i].Hub.Stop()
+
+		router := gin.Default()
+		router.GET("/ws", handlers[i].HandleConnections)
+		servers[i] = httptest.NewServer(router)
+		defer servers[i].Close()
+	}
+
+	ivy := dialAs(t, handlers[0].Hub, servers[0], "ivy_token", 31)
+	defer ivy.Close()
+	jack := dialAs(t, handlers[1].Hub, servers[1], "jack_token", 32)
+	defer jack.Close()
+	assert.Eventually(t, func() bool { return handlers[0].Hub.IsOnline(32) }, time.Second, 10*time.Millisecond)
+
+	// Сообщение, отправленное через первый экземпляр, доходит до клиента второго
+	assert.NoError(t, ivy.WriteJSON(incomingMessage{Type: "message", Message: "Hello from the other side"}))
+	for _, ws := range []*websocket.Conn{ivy, jack} {
+		var msg entity.Message
+		ws.SetReadDeadline(time.Now().Add(time.Second))
+		assert.NoError(t, ws.ReadJSON(&msg))
+		assert.Equal(t, "Hello from the other side", msg.Message)
+		assert.Equal(t, "ivy", msg.Username)
+	}
+	uc.AssertExpectations(t)
+}
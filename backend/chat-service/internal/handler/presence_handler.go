@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+	myWeb "github.com/jaliks17/ffffforum/backend/chat-service/pkg/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+// typingPruneSize - при таком числе записей typingLimiter удаляет устаревшие
+const typingPruneSize = 1024
+
+type typingKey struct {
+	userID int64
+	roomID int
+}
+
+// typingLimiter ограничивает рассылку индикатора набора: начало набора пользователя
+// в комнате уходит не чаще entity.TypingThrottle, а конец - только если было начало.
+// Нулевое значение готово к работе.
+type typingLimiter struct {
+	mu      sync.Mutex
+	started map[typingKey]time.Time
+}
+
+func (l *typingLimiter) allow(key typingKey, typing bool, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !typing {
+		if _, ok := l.started[key]; !ok {
+			return false
+		}
+		delete(l.started, key)
+		return true
+	}
+
+	if l.started == nil {
+		l.started = make(map[typingKey]time.Time)
+	}
+	if last, ok := l.started[key]; ok && now.Sub(last) < entity.TypingThrottle {
+		return false
+	}
+	if len(l.started) >= typingPruneSize {
+		// Клиенты уже скрыли индикаторы, которые не повторялись дольше TypingTimeout
+		for k, last := range l.started {
+			if now.Sub(last) >= entity.TypingTimeout {
+				delete(l.started, k)
+			}
+		}
+	}
+	l.started[key] = now
+	return true
+}
+
+// typing рассылает участникам комнаты, что пользователь начал или закончил набирать
+// сообщение. Сообщить о наборе можно только в комнату, к которой подключено соединение.
+func (h *MessageHandler) typing(client *myWeb.Client, userID int64, username string, roomID int, typing bool) error {
+	roomID = roomOrGeneral(roomID)
+	if !h.Hub.InRoom(client, roomID) {
+		return fmt.Errorf("%w: not joined to room %d", usecase.ErrRoomForbidden, roomID)
+	}
+	if !h.typingLimiter.allow(typingKey{userID: userID, roomID: roomID}, typing, time.Now()) {
+		return nil
+	}
+	h.Hub.BroadcastRoom(roomID, entity.TypingEvent{Type: "typing", RoomID: roomID, UserID: userID, Username: username, Typing: typing})
+	return nil
+}
+
+// setStatus применяет статус, о котором сообщил клиент: online или away
+func (h *MessageHandler) setStatus(client *myWeb.Client, status string) error {
+	switch status {
+	case entity.PresenceOnline:
+		h.Hub.SetAway(client, false)
+	case entity.PresenceAway:
+		h.Hub.SetAway(client, true)
+	default:
+		return fmt.Errorf("%w: status must be %q or %q", errBadRequest, entity.PresenceOnline, entity.PresenceAway)
+	}
+	return nil
+}
+
+// recordLastSeen сохраняет время отключения пользователя
+func (h *MessageHandler) recordLastSeen(userID int64) {
+	if h.Presence == nil {
+		return
+	}
+	if err := h.Presence.RecordLastSeen(userID); err != nil {
+		log.Printf("Failed to record last seen of user %d: %v", userID, err)
+	}
+}
+
+// parseUserIDs разбирает список ID через запятую
+func parseUserIDs(value string) ([]int64, error) {
+	var userIDs []int64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		userID, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || userID <= 0 {
+			return nil, usecase.ErrInvalidPresenceQuery
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, nil
+}
+
+// GetPresence godoc
+// @Summary Get user presence
+// @Description Returns online, away or offline status of each user, with the last time offline users were connected to the chat
+// @Tags presence
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_ids query string true "Comma-separated user IDs, at most 100"
+// @Success 200 {array} entity.UserPresence
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/presence [get]
+func (h *MessageHandler) GetPresence(c *gin.Context) {
+	if _, ok := h.authenticate(c); !ok {
+		return
+	}
+	userIDs, err := parseUserIDs(c.Query("user_ids"))
+	if err == nil && (len(userIDs) == 0 || len(userIDs) > entity.MaxPresenceUsers) {
+		err = usecase.ErrInvalidPresenceQuery
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("user_ids must list 1 to %d user IDs", entity.MaxPresenceUsers)})
+		return
+	}
+
+	ids := make([]int, len(userIDs))
+	for i, userID := range userIDs {
+		ids[i] = int(userID)
+	}
+	statuses := make(map[int64]string, len(userIDs))
+	for userID, status := range h.Hub.Presence(ids) {
+		statuses[int64(userID)] = status
+	}
+
+	presence, err := h.Presence.GetPresence(userIDs, statuses)
+	if errors.Is(err, usecase.ErrInvalidPresenceQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get presence: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get presence"})
+		return
+	}
+	c.JSON(http.StatusOK, presence)
+}
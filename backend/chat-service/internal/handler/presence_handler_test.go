@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/chatclient"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
+	"github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPresenceUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPresenceUseCase) RecordLastSeen(userID int64) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockPresenceUseCase) GetPresence(userIDs []int64, statuses map[int64]string) ([]entity.UserPresence, error) {
+	args := m.Called(userIDs, statuses)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserPresence), args.Error(1)
+}
+
+// presenceServer запускает обработчик, в котором пользователь 25 ("kate") состоит только
+// в общей комнате
+func presenceServer(t *testing.T, presence *MockPresenceUseCase) *httptest.Server {
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: true, UserId: 25, UserRole: "user"}, nil)
+	authClient.On("GetUserProfile", mock.Anything, mock.Anything).
+		Return(&proto.GetUserProfileResponse{User: &proto.User{Id: 25, Username: "kate"}}, nil)
+	rooms := new(MockRoomUseCase)
+	rooms.On("UserRoomIDs", int64(25)).Return(nil, nil)
+
+	handler := NewMessageHandler(new(MockMessageUseCase), authClient)
+	handler.Rooms = rooms
+	handler.Presence = presence
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	router.GET("/api/v1/presence", handler.GetPresence)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMessageHandler_GetPresence(t *testing.T) {
+	presence := new(MockPresenceUseCase)
+	disconnected := make(chan struct{}, 1)
+	presence.On("RecordLastSeen", int64(25)).Run(func(mock.Arguments) { disconnected <- struct{}{} }).Return(nil)
+	server := presenceServer(t, presence)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	client, err := chatclient.Dial(ctx, "ws"+server.URL[4:]+"/ws", "kate_token")
+	require.NoError(t, err)
+	defer client.Close()
+	// Подтверждение запроса означает, что соединение уже зарегистрировано в хабе
+	require.NoError(t, client.Subscribe(ctx, "presence:7"))
+
+	get := func(query string, auth bool) *http.Response {
+		req, err := http.NewRequest("GET", server.URL+"/api/v1/presence"+query, nil)
+		require.NoError(t, err)
+		if auth {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	seen := time.Date(2023, 10, 27, 10, 0, 0, 0, time.UTC)
+	presence.On("GetPresence", []int64{25, 7}, map[int64]string{25: entity.PresenceOnline, 7: entity.PresenceOffline}).
+		Return([]entity.UserPresence{{UserID: 25, Status: entity.PresenceOnline}, {UserID: 7, Status: entity.PresenceOffline, LastSeen: &seen}}, nil).Once()
+	presence.On("GetPresence", []int64{8}, mock.Anything).Return(nil, errors.New("database error")).Once()
+
+	assert.Equal(t, http.StatusOK, get("?user_ids=25,7", true).StatusCode)
+	assert.Equal(t, http.StatusInternalServerError, get("?user_ids=8", true).StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, get("?user_ids=25", false).StatusCode)
+
+	tooMany := make([]string, entity.MaxPresenceUsers+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprint(i + 1)
+	}
+	for _, query := range []string{"", "?user_ids=", "?user_ids=abc", "?user_ids=0", "?user_ids=" + strings.Join(tooMany, ",")} {
+		assert.Equal(t, http.StatusBadRequest, get(query, true).StatusCode, query)
+	}
+
+	// Отключение сохраняет время последнего визита
+	client.Close()
+	select {
+	case <-disconnected:
+	case <-ctx.Done():
+		t.Fatal("last seen was not recorded")
+	}
+	presence.AssertExpectations(t)
+}
+
+func TestMessageHandler_ProtocolTypingAndStatus(t *testing.T) {
+	presence := new(MockPresenceUseCase)
+	presence.On("RecordLastSeen", int64(25)).Return(nil)
+	server := presenceServer(t, presence)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	dial := func() *chatclient.Client {
+		client, err := chatclient.Dial(ctx, "ws"+server.URL[4:]+"/ws", "kate_token")
+		require.NoError(t, err)
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+	typist, watcher := dial(), dial()
+
+	readTyping := func(client *chatclient.Client) string {
+		select {
+		case env := <-client.Events():
+			assert.Equal(t, protocol.OpEvent, env.Op)
+			return string(env.Payload)
+		case <-ctx.Done():
+			t.Fatal("typing event was not delivered")
+			return ""
+		}
+	}
+
+	require.NoError(t, typist.Typing(ctx, 0, true))
+	assert.JSONEq(t, `{"type":"typing","room_id":1,"user_id":25,"username":"kate","typing":true}`, readTyping(watcher))
+	readTyping(typist)
+
+	// Повтор в пределах TypingThrottle подтверждается, но не рассылается
+	require.NoError(t, typist.Typing(ctx, 0, true))
+	require.NoError(t, typist.Typing(ctx, 0, false))
+	assert.JSONEq(t, `{"type":"typing","room_id":1,"user_id":25,"username":"kate","typing":false}`, readTyping(watcher))
+	readTyping(typist)
+	// Конец набора без начала не рассылается
+	require.NoError(t, typist.Typing(ctx, 0, false))
+
+	var protoErr *protocol.Error
+	err := typist.Typing(ctx, 6, true)
+	require.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, protocol.CodeForbidden, protoErr.Code)
+
+	err = typist.SetStatus(ctx, "busy")
+	require.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, protocol.CodeBadRequest, protoErr.Code)
+	assert.NoError(t, typist.SetStatus(ctx, entity.PresenceAway))
+	assert.NoError(t, watcher.Subscribe(ctx, "presence:25"))
+
+	assert.Empty(t, watcher.Events())
+	assert.Empty(t, typist.Events())
+}
+
+func TestTypingLimiter(t *testing.T) {
+	var limiter typingLimiter
+	key := typingKey{userID: 1, roomID: 2}
+	now := time.Now()
+
+	assert.False(t, limiter.allow(key, false, now))
+	assert.True(t, limiter.allow(key, true, now))
+	assert.False(t, limiter.allow(key, true, now.Add(time.Second)))
+	assert.True(t, limiter.allow(key, true, now.Add(entity.TypingThrottle)))
+	assert.True(t, limiter.allow(key, false, now.Add(entity.TypingThrottle)))
+	assert.False(t, limiter.allow(key, false, now.Add(entity.TypingThrottle)))
+
+	// Устаревшие записи удаляются, когда их становится много
+	for i := 0; i < typingPruneSize; i++ {
+		limiter.allow(typingKey{userID: int64(i + 10), roomID: 2}, true, now)
+	}
+	assert.True(t, limiter.allow(key, true, now.Add(entity.TypingTimeout)))
+	assert.Len(t, limiter.started, 1)
+}
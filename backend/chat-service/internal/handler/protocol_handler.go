@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
+	myWeb "github.com/jaliks17/ffffforum/backend/chat-service/pkg/websocket"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// recentRequests - сколько ответов на последние запросы помнит соединение, чтобы
+// повтор запроса с тем же id не выполнялся второй раз
+const recentRequests = 256
+
+var (
+	errBadRequest           = errors.New("invalid request")
+	errUnknownOp            = errors.New("unknown op")
+	errTooManySubscriptions = errors.New("subscription limit reached")
+)
+
+// session - соединение, работающее по протоколу protocol.Subprotocol
+type session struct {
+	client   *myWeb.Client
+	userID   int64
+	username string
+
+	// responses - ответы по id запроса, order - порядок их поступления для вытеснения
+	responses map[string]protocol.Envelope
+	order     []string
+}
+
+func newSession(client *myWeb.Client, userID int64, username string) *session {
+	return &session{
+		client:    client,
+		userID:    userID,
+		username:  username,
+		responses: make(map[string]protocol.Envelope),
+	}
+}
+
+// remember сохраняет ответ на запрос id, вытесняя самый старый
+func (s *session) remember(id string, resp protocol.Envelope) {
+	if len(s.order) >= recentRequests {
+		delete(s.responses, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.responses[id] = resp
+	s.order = append(s.order, id)
+}
+
+// serveSession читает кадры protocol.Envelope, пока клиент не отключится. Каждый запрос
+// получает ack или error с его id.
+func (h *MessageHandler) serveSession(ws *websocket.Conn, s *session) {
+	for {
+		messageType, p, err := ws.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("Client %d closed connection normally", s.userID)
+			} else {
+				log.Printf("Error reading message from user %d: %v", s.userID, err)
+			}
+			return
+		}
+		ws.SetReadDeadline(time.Now().Add(myWeb.PongWait))
+
+		if messageType != websocket.TextMessage {
+			h.Hub.SendEnvelope(s.client, protocol.Fail("", protocol.CodeBadRequest, "only text frames are supported"))
+			continue
+		}
+		h.handleEnvelope(s, p)
+	}
+}
+
+// handleEnvelope выполняет один запрос клиента. Повтор уже обработанного id получает
+// прежний ответ; внутренние ошибки не запоминаются, чтобы повтор мог пройти.
+func (h *MessageHandler) handleEnvelope(s *session, data []byte) {
+	var env protocol.Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		h.Hub.SendEnvelope(s.client, protocol.Fail("", protocol.CodeBadRequest, "invalid frame"))
+		return
+	}
+	if env.ID == "" || len(env.ID) > protocol.MaxIDLength {
+		h.Hub.SendEnvelope(s.client, protocol.Fail("", protocol.CodeBadRequest,
+			fmt.Sprintf("request id must be 1 to %d characters", protocol.MaxIDLength)))
+		return
+	}
+	// Любой запрос, кроме сообщения о статусе, означает, что пользователь активен
+	if env.Op != protocol.OpPresence {
+		h.Hub.Touch(s.client)
+	}
+	if resp, ok := s.responses[env.ID]; ok {
+		h.Hub.SendEnvelope(s.client, resp)
+		return
+	}
+
+	ack, msg, err := h.execute(s, env)
+	resp := protocol.Ack(env.ID, ack)
+	if err != nil {
+		resp = errorFrame(env.ID, err)
+	}
+	if err == nil || errorCode(err) != protocol.CodeInternal {
+		s.remember(env.ID, resp)
+	}
+	// Подтверждение уходит раньше рассылки: клиент успевает связать свой id
+	// с message_id до того, как получит сообщение
+	h.Hub.SendEnvelope(s.client, resp)
+	if msg != nil {
+		h.broadcast(*msg)
+	}
+}
+
+// execute возвращает payload подтверждения и сохраненное сообщение, если запрос его создал
+func (h *MessageHandler) execute(s *session, env protocol.Envelope) (interface{}, *entity.Message, error) {
+	switch env.Op {
+	case protocol.OpSend:
+		var p protocol.SendPayload
+		if err := decodePayload(env, &p); err != nil {
+			return nil, nil, err
+		}
+		msg := &entity.Message{
+			RoomID:        roomOrGeneral(p.RoomID),
+			UserID:        int(s.userID),
+			Username:      s.username,
+			Message:       p.Message,
+			AttachmentIDs: p.AttachmentIDs,
+			ClientMsgID:   p.IdempotencyKey,
+		}
+		err := h.postMessage(msg)
+		if errors.Is(err, repository.ErrDuplicateMessage) {
+			// Сообщение уже сохранено и разослано по первому запросу
+			return protocol.AckPayload{MessageID: msg.ID, RoomID: msg.RoomID, Seq: msg.Seq, Timestamp: msg.Timestamp, Duplicate: true}, nil, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return protocol.AckPayload{MessageID: msg.ID, RoomID: msg.RoomID, Seq: msg.Seq, Timestamp: msg.Timestamp}, msg, nil
+	case protocol.OpJoin, protocol.OpLeave:
+		var p protocol.RoomPayload
+		if err := decodePayload(env, &p); err != nil {
+			return nil, nil, err
+		}
+		if env.Op == protocol.OpJoin {
+			return nil, nil, h.joinRoom(s.userID, roomOrGeneral(p.RoomID))
+		}
+		return nil, nil, h.leaveRoom(s.userID, roomOrGeneral(p.RoomID))
+	case protocol.OpSubscribe, protocol.OpUnsubscribe:
+		var p protocol.TopicPayload
+		if err := decodePayload(env, &p); err != nil {
+			return nil, nil, err
+		}
+		if env.Op == protocol.OpSubscribe {
+			return nil, nil, h.subscribe(s.client, s.userID, p.Topic)
+		}
+		h.Hub.Unsubscribe(s.client, p.Topic)
+		return nil, nil, nil
+	case protocol.OpResume:
+		var p protocol.ResumePayload
+		if err := decodePayload(env, &p); err != nil {
+			return nil, nil, err
+		}
+		if len(p.Rooms) > protocol.MaxResumeRooms {
+			return nil, nil, fmt.Errorf("%w: at most %d rooms per resume", errBadRequest, protocol.MaxResumeRooms)
+		}
+		// События replay уходят до ack: получив ack, клиент знает, что повтор закончен
+		ack := protocol.ResumeAckPayload{Rooms: make([]protocol.RoomResume, 0, len(p.Rooms))}
+		for _, cursor := range p.Rooms {
+			cursor.RoomID = roomOrGeneral(cursor.RoomID)
+			ack.Rooms = append(ack.Rooms, h.replay(s.client, s.userID, cursor))
+		}
+		return ack, nil, nil
+	case protocol.OpTyping:
+		var p protocol.TypingPayload
+		if err := decodePayload(env, &p); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, h.typing(s.client, s.userID, s.username, p.RoomID, p.Typing)
+	case protocol.OpPresence:
+		var p protocol.PresencePayload
+		if err := decodePayload(env, &p); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, h.setStatus(s.client, p.Status)
+	case protocol.OpRead:
+		var p protocol.ReadPayload
+		if err := decodePayload(env, &p); err != nil {
+			return nil, nil, err
+		}
+		if _, err := h.markRead(s.userID, p.RoomID, p.MessageID); err != nil {
+			return nil, nil, err
+		}
+		count, err := h.roomUnread(s.userID, p.RoomID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return count, nil, nil
+	case protocol.OpUnread:
+		counts, err := h.unreadCounts(s.userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		ack := protocol.UnreadAckPayload{Unread: make([]protocol.RoomUnread, 0, len(counts))}
+		for _, count := range counts {
+			ack.Unread = append(ack.Unread, toRoomUnread(count))
+		}
+		return ack, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("%w %q", errUnknownOp, env.Op)
+	}
+}
+
+func decodePayload(env protocol.Envelope, v interface{}) error {
+	if len(env.Payload) == 0 {
+		return fmt.Errorf("%w: payload is required for %s", errBadRequest, env.Op)
+	}
+	if err := json.Unmarshal(env.Payload, v); err != nil {
+		return fmt.Errorf("%w: invalid %s payload", errBadRequest, env.Op)
+	}
+	return nil
+}
+
+// errorCode переводит ошибку запроса в код протокола
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, errBadRequest), errors.Is(err, usecase.ErrInvalidRoom), errors.Is(err, usecase.ErrInvalidReadPosition),
+		errors.Is(err, usecase.ErrInvalidAttachments):
+		return protocol.CodeBadRequest
+	case errors.Is(err, errUnknownOp):
+		return protocol.CodeUnknownOp
+	case errors.Is(err, usecase.ErrRoomForbidden):
+		return protocol.CodeForbidden
+	case errors.Is(err, repository.ErrRoomNotFound), errors.Is(err, repository.ErrMemberNotFound),
+		errors.Is(err, repository.ErrMessageNotFound):
+		return protocol.CodeNotFound
+	case errors.Is(err, usecase.ErrMessageRejected):
+		return protocol.CodeRejected
+	case errors.Is(err, errTooManySubscriptions):
+		return protocol.CodeLimit
+	default:
+		return protocol.CodeInternal
+	}
+}
+
+// errorFrame строит кадр ошибки; подробности внутренних ошибок клиенту не отдаются
+func errorFrame(id string, err error) protocol.Envelope {
+	code := errorCode(err)
+	message := err.Error()
+	if code == protocol.CodeInternal {
+		message = "internal error"
+	}
+	return protocol.Fail(id, code, message)
+}
+
+// GetProtocolSchema godoc
+// @Summary WebSocket protocol schema
+// @Description JSON Schema of the frames used by WebSocket clients that negotiate the chat.v1 subprotocol via Sec-WebSocket-Protocol
+// @Tags messages
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/ws/schema [get]
+func (h *MessageHandler) GetProtocolSchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/schema+json", protocol.Schema)
+}
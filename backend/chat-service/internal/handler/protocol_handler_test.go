@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/chatclient"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
+	"github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// protocolServer запускает обработчик, в котором пользователь 25 ("kate") может писать
+// в общую комнату, но не в комнату 6
+func protocolServer(t *testing.T, uc *MockMessageUseCase) (*MessageHandler, *httptest.Server) {
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: true, UserId: 25, UserRole: "user"}, nil)
+	authClient.On("GetUserProfile", mock.Anything, mock.Anything).
+		Return(&proto.GetUserProfileResponse{User: &proto.User{Id: 25, Username: "kate"}}, nil)
+	rooms := new(MockRoomUseCase)
+	rooms.On("UserRoomIDs", int64(25)).Return(nil, nil)
+	rooms.On("CheckAccess", 6, int64(25)).Return(usecase.ErrRoomForbidden)
+
+	handler := NewMessageHandler(uc, authClient)
+	handler.Rooms = rooms
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	router.GET("/api/v1/ws/schema", handler.GetProtocolSchema)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return handler, server
+}
+
+func TestMessageHandler_Protocol(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	uc.On("SaveMessage", mock.MatchedBy(func(msg *entity.Message) bool { return msg.Message == "Hello" })).
+		Run(func(args mock.Arguments) { args.Get(0).(*entity.Message).ID = 42 }).
+		Return(nil).Once()
+	uc.On("SaveMessage", mock.MatchedBy(func(msg *entity.Message) bool { return msg.Message == "spam" })).
+		Return(usecase.ErrMessageRejected)
+	_, server := protocolServer(t, uc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	client, err := chatclient.Dial(ctx, "ws"+server.URL[4:]+"/ws", "kate_token")
+	require.NoError(t, err)
+	defer client.Close()
+
+	ack, err := client.SendWithID(ctx, "req-1", protocol.SendPayload{Message: "Hello"})
+	require.NoError(t, err)
+	assert.Equal(t, 42, ack.MessageID)
+	assert.Equal(t, entity.GeneralRoomID, ack.RoomID)
+
+	// Рассылка приходит в конверте
+	env := <-client.Events()
+	assert.Equal(t, protocol.OpMessage, env.Op)
+	var msg entity.Message
+	require.NoError(t, json.Unmarshal(env.Payload, &msg))
+	assert.Equal(t, 42, msg.ID)
+	assert.Equal(t, "kate", msg.Username)
+
+	// Повтор того же id не сохраняет сообщение второй раз
+	retry, err := client.SendWithID(ctx, "req-1", protocol.SendPayload{Message: "Hello"})
+	require.NoError(t, err)
+	assert.Equal(t, ack, retry)
+
+	var protoErr *protocol.Error
+	_, err = client.Send(ctx, 6, "Hello")
+	require.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, protocol.CodeForbidden, protoErr.Code)
+
+	_, err = client.Send(ctx, 0, "spam")
+	require.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, protocol.CodeRejected, protoErr.Code)
+
+	err = client.Subscribe(ctx, "poll:abc")
+	require.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, protocol.CodeBadRequest, protoErr.Code)
+	assert.NoError(t, client.Subscribe(ctx, "poll:3"))
+
+	assert.Empty(t, client.Events())
+	uc.AssertExpectations(t)
+}
+
+func TestMessageHandler_ProtocolErrors(t *testing.T) {
+	_, server := protocolServer(t, new(MockMessageUseCase))
+
+	dialer := websocket.Dialer{Subprotocols: protocol.Subprotocols}
+	ws, _, err := dialer.Dial("ws"+server.URL[4:]+"/ws?token=kate_token", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+	assert.Equal(t, protocol.Subprotocol, ws.Subprotocol())
+
+	read := func() (protocol.Envelope, protocol.ErrorPayload) {
+		var env protocol.Envelope
+		var payload protocol.ErrorPayload
+		ws.SetReadDeadline(time.Now().Add(time.Second))
+		require.NoError(t, ws.ReadJSON(&env))
+		json.Unmarshal(env.Payload, &payload)
+		return env, payload
+	}
+
+	for _, tc := range []struct {
+		frame string
+		id    string
+		code  string
+	}{
+		{`not json`, "", protocol.CodeBadRequest},
+		{`{"op":"send","payload":{"message":"no id"}}`, "", protocol.CodeBadRequest},
+		{`{"op":"dance","id":"a"}`, "a", protocol.CodeUnknownOp},
+		{`{"op":"join","id":"b"}`, "b", protocol.CodeBadRequest},
+		{`{"op":"send","id":"c","payload":[]}`, "c", protocol.CodeBadRequest},
+	} {
+		require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte(tc.frame)))
+		env, payload := read()
+		assert.Equal(t, protocol.OpError, env.Op, tc.frame)
+		assert.Equal(t, tc.id, env.ID, tc.frame)
+		assert.Equal(t, tc.code, payload.Code, tc.frame)
+	}
+
+	// join подтверждается ack без payload; событие joined приходит всем соединениям
+	// пользователя в конверте event
+	require.NoError(t, ws.WriteJSON(protocol.Envelope{Op: protocol.OpJoin, ID: "d", Payload: json.RawMessage(`{"room_id":1}`)}))
+	frames := map[string]protocol.Envelope{}
+	for i := 0; i < 2; i++ {
+		env, _ := read()
+		frames[env.Op] = env
+	}
+	assert.Equal(t, protocol.Envelope{Op: protocol.OpAck, ID: "d"}, frames[protocol.OpAck])
+	assert.JSONEq(t, `{"type":"joined","room_id":1}`, string(frames[protocol.OpEvent].Payload))
+}
+
+func TestMessageHandler_ProtocolResume(t *testing.T) {
+	missed := make([]entity.Message, protocol.MaxReplay+1)
+	for i := range missed {
+		missed[i] = entity.Message{ID: 100 + i, RoomID: entity.GeneralRoomID, Seq: int64(4 + i)}
+	}
+	sentAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	uc := new(MockMessageUseCase)
+	uc.On("SaveMessage", mock.MatchedBy(func(msg *entity.Message) bool { return msg.ClientMsgID == "key-1" })).
+		Run(func(args mock.Arguments) {
+			msg := args.Get(0).(*entity.Message)
+			msg.ID, msg.Seq, msg.Timestamp = 42, 5, sentAt
+		}).
+		Return(repository.ErrDuplicateMessage)
+	uc.On("GetRoomMessagesAfter", entity.GeneralRoomID, int64(3), protocol.MaxReplay+1).Return(missed, nil)
+	handler, server := protocolServer(t, uc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	client, err := chatclient.Dial(ctx, "ws"+server.URL[4:]+"/ws", "kate_token")
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Сообщение с этим ключом уже сохранено до обрыва: ack без повторной рассылки
+	ack, err := client.SendWithID(ctx, "key-1", protocol.SendPayload{Message: "Hello"})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.AckPayload{MessageID: 42, RoomID: entity.GeneralRoomID, Seq: 5, Timestamp: sentAt, Duplicate: true}, ack)
+	assert.Empty(t, client.Events())
+
+	var protoErr *protocol.Error
+	_, err = client.SendWithID(ctx, "key-2", protocol.SendPayload{Message: "Hello", IdempotencyKey: strings.Repeat("k", protocol.MaxIDLength+1)})
+	require.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, protocol.CodeBadRequest, protoErr.Code)
+
+	resumed, err := client.Resume(ctx, []protocol.RoomCursor{{RoomID: 0, LastSeq: 3}, {RoomID: 6}})
+	require.NoError(t, err)
+	assert.Equal(t, []protocol.RoomResume{
+		{RoomID: entity.GeneralRoomID, Replayed: protocol.MaxReplay, Truncated: true},
+		{RoomID: 6, Error: protocol.CodeForbidden},
+	}, resumed.Rooms)
+
+	// Повтор пришел одним событием до ack
+	require.Len(t, client.Events(), 1)
+	env := <-client.Events()
+	assert.Equal(t, protocol.OpEvent, env.Op)
+	var replay entity.ReplayEvent
+	require.NoError(t, json.Unmarshal(env.Payload, &replay))
+	assert.Equal(t, "replay", replay.Type)
+	assert.True(t, replay.Truncated)
+	require.Len(t, replay.Messages, protocol.MaxReplay)
+	assert.Equal(t, int64(4), replay.Messages[0].Seq)
+
+	_, err = client.Resume(ctx, make([]protocol.RoomCursor, protocol.MaxResumeRooms+1))
+	require.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, protocol.CodeBadRequest, protoErr.Code)
+
+	// Старый клиент повторяет одну комнату за запрос
+	legacy := dialAs(t, handler.Hub, server, "kate_token", 25)
+	defer legacy.Close()
+	require.NoError(t, legacy.WriteJSON(incomingMessage{Type: "resume", RoomID: 6}))
+	var roomErr entity.RoomEvent
+	legacy.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, legacy.ReadJSON(&roomErr))
+	assert.Equal(t, entity.RoomEvent{Type: "room_error", RoomID: 6, Error: protocol.CodeForbidden}, roomErr)
+
+	require.NoError(t, legacy.WriteJSON(incomingMessage{Type: "resume", LastSeq: 3}))
+	require.NoError(t, legacy.ReadJSON(&replay))
+	assert.Equal(t, entity.GeneralRoomID, replay.RoomID)
+	assert.Len(t, replay.Messages, protocol.MaxReplay)
+	uc.AssertExpectations(t)
+}
+
+func TestMessageHandler_ProtocolAndLegacyClients(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	uc.On("SaveMessage", mock.Anything).Return(nil)
+	handler, server := protocolServer(t, uc)
+
+	legacy := dialAs(t, handler.Hub, server, "kate_token", 25)
+	defer legacy.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	client, err := chatclient.Dial(ctx, "ws"+server.URL[4:]+"/ws", "kate_token")
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Старый клиент пишет в прежнем формате, новый получает сообщение в конверте
+	require.NoError(t, legacy.WriteJSON(incomingMessage{Type: "message", Message: "from legacy"}))
+	var msg entity.Message
+	legacy.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, legacy.ReadJSON(&msg))
+	assert.Equal(t, "from legacy", msg.Message)
+
+	env := <-client.Events()
+	assert.Equal(t, protocol.OpMessage, env.Op)
+	require.NoError(t, json.Unmarshal(env.Payload, &msg))
+	assert.Equal(t, "from legacy", msg.Message)
+
+	resp, err := http.Get(server.URL + "/api/v1/ws/schema")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/schema+json", resp.Header.Get("Content-Type"))
+}
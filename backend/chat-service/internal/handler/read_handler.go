@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errReadsDisabled возвращается, если обработчик запущен без Reads
+var errReadsDisabled = errors.New("read positions are not available")
+
+// markRead сдвигает позицию прочтения пользователя и, если она изменилась, рассылает
+// событие read соединениям комнаты: собеседникам это отметка о прочтении, другим
+// соединениям пользователя - сигнал сбросить счетчик
+func (h *MessageHandler) markRead(userID int64, roomID, messageID int) (*entity.ReadPosition, error) {
+	if h.Reads == nil {
+		return nil, errReadsDisabled
+	}
+	roomID = roomOrGeneral(roomID)
+	if err := h.checkRoomAccess(roomID, userID); err != nil {
+		return nil, err
+	}
+	pos, moved, err := h.Reads.MarkRead(roomID, userID, messageID)
+	if err != nil {
+		log.Printf("User %d failed to mark room %d as read: %v", userID, roomID, err)
+		return nil, err
+	}
+	if moved {
+		h.Hub.BroadcastRoom(roomID, entity.ReadEvent{
+			Type:      "read",
+			RoomID:    roomID,
+			UserID:    userID,
+			MessageID: pos.LastReadMessageID,
+			Seq:       pos.LastReadSeq,
+			ReadAt:    pos.ReadAt,
+		})
+	}
+	return pos, nil
+}
+
+// roomUnread возвращает счетчик комнаты после отметки о прочтении
+func (h *MessageHandler) roomUnread(userID int64, roomID int) (protocol.RoomUnread, error) {
+	count, err := h.Reads.RoomUnreadCount(roomOrGeneral(roomID), userID)
+	if err != nil {
+		return protocol.RoomUnread{}, err
+	}
+	return toRoomUnread(count), nil
+}
+
+// unreadCounts возвращает счетчики общей комнаты и всех комнат пользователя
+func (h *MessageHandler) unreadCounts(userID int64) ([]entity.UnreadCount, error) {
+	if h.Reads == nil {
+		return nil, errReadsDisabled
+	}
+	return h.Reads.UnreadCounts(userID)
+}
+
+func toRoomUnread(count entity.UnreadCount) protocol.RoomUnread {
+	return protocol.RoomUnread{RoomID: count.RoomID, Unread: count.Unread, LastReadSeq: count.LastReadSeq, LastSeq: count.LastSeq}
+}
+
+// GetUnreadCounts godoc
+// @Summary Get unread counters
+// @Description Returns the read position and the number of unread messages from other users in the general room and every room and conversation of the user. Counters stop at 1000
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} entity.UnreadCount
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/unread [get]
+func (h *MessageHandler) GetUnreadCounts(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	counts, err := h.unreadCounts(userID)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, counts)
+}
+
+// MarkRoomRead godoc
+// @Summary Mark a room as read
+// @Description Moves the user's read position in the room (or conversation) forward to message_id, or to the latest message when omitted, and sends a read receipt to the room. Returns the updated counter
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Param request body entity.MarkReadRequest false "Last read message"
+// @Success 200 {object} entity.UnreadCount
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms/{id}/read [post]
+func (h *MessageHandler) MarkRoomRead(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	roomID, _, ok := roomParams(c, false)
+	if !ok {
+		return
+	}
+	var req entity.MarkReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+
+	if _, err := h.markRead(userID, roomID, req.MessageID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	count, err := h.Reads.RoomUnreadCount(roomID, userID)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, count)
+}
+
+// GetRoomReads godoc
+// @Summary Get read receipts of a room
+// @Description Returns the read position of every user who has read the room, to show who has seen which message. Only users with access to the room can see it
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Success 200 {array} entity.ReadPosition
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms/{id}/reads [get]
+func (h *MessageHandler) GetRoomReads(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	roomID, _, ok := roomParams(c, false)
+	if !ok {
+		return
+	}
+	if err := h.checkRoomAccess(roomID, userID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+
+	positions, err := h.Reads.ReadPositions(roomID)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	if positions == nil {
+		positions = []entity.ReadPosition{}
+	}
+	c.JSON(http.StatusOK, positions)
+}
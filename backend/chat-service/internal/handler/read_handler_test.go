@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/chatclient"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
+	"github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReadUseCase struct {
+	mock.Mock
+}
+
+func (m *MockReadUseCase) MarkRead(roomID int, userID int64, messageID int) (*entity.ReadPosition, bool, error) {
+	args := m.Called(roomID, userID, messageID)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*entity.ReadPosition), args.Bool(1), args.Error(2)
+}
+
+func (m *MockReadUseCase) ReadPositions(roomID int) ([]entity.ReadPosition, error) {
+	args := m.Called(roomID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ReadPosition), args.Error(1)
+}
+
+func (m *MockReadUseCase) UnreadCounts(userID int64) ([]entity.UnreadCount, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UnreadCount), args.Error(1)
+}
+
+func (m *MockReadUseCase) RoomUnreadCount(roomID int, userID int64) (entity.UnreadCount, error) {
+	args := m.Called(roomID, userID)
+	return args.Get(0).(entity.UnreadCount), args.Error(1)
+}
+
+func TestMessageHandler_ReadEndpoints(t *testing.T) {
+	rooms := new(MockRoomUseCase)
+	reads := new(MockReadUseCase)
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: true, UserId: 5, UserRole: "user"}, nil)
+
+	handler := NewMessageHandler(new(MockMessageUseCase), authClient)
+	handler.Rooms = rooms
+	handler.Reads = reads
+
+	router := gin.Default()
+	router.GET("/unread", handler.GetUnreadCounts)
+	router.POST("/rooms/:id/read", handler.MarkRoomRead)
+	router.GET("/rooms/:id/reads", handler.GetRoomReads)
+
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	reads.On("UnreadCounts", int64(5)).Return([]entity.UnreadCount{{RoomID: 1, Unread: 3, LastReadSeq: 40, LastSeq: 44}}, nil).Once()
+	w := call("GET", "/unread", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"room_id":1,"unread":3,"last_read_seq":40,"last_seq":44}]`, w.Body.String())
+
+	rooms.On("CheckAccess", 4, int64(5)).Return(nil)
+	rooms.On("CheckAccess", 6, int64(5)).Return(usecase.ErrRoomForbidden)
+	reads.On("MarkRead", 4, int64(5), 0).Return(&entity.ReadPosition{RoomID: 4, UserID: 5, LastReadSeq: 9, LastReadMessageID: 30}, true, nil).Once()
+	reads.On("MarkRead", 4, int64(5), 99).Return(nil, false, repository.ErrMessageNotFound).Once()
+	reads.On("MarkRead", 4, int64(5), -1).Return(nil, false, usecase.ErrInvalidReadPosition).Once()
+	reads.On("RoomUnreadCount", 4, int64(5)).Return(entity.UnreadCount{RoomID: 4, LastReadSeq: 9, LastSeq: 9}, nil).Once()
+
+	w = call("POST", "/rooms/4/read", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"room_id":4,"unread":0,"last_read_seq":9,"last_seq":9}`, w.Body.String())
+	assert.Equal(t, http.StatusNotFound, call("POST", "/rooms/4/read", `{"message_id":99}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/rooms/4/read", `{"message_id":-1}`).Code)
+	assert.Equal(t, http.StatusForbidden, call("POST", "/rooms/6/read", "").Code)
+
+	reads.On("ReadPositions", 4).Return(nil, nil).Once()
+	w = call("GET", "/rooms/4/reads", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+	assert.Equal(t, http.StatusForbidden, call("GET", "/rooms/6/reads", "").Code)
+
+	reads.On("UnreadCounts", int64(5)).Return(nil, errors.New("database error")).Once()
+	assert.Equal(t, http.StatusInternalServerError, call("GET", "/unread", "").Code)
+	reads.AssertExpectations(t)
+}
+
+// readServer запускает обработчик, в котором kate (25) и bob (26) состоят только в общей комнате,
+// а комната 6 им недоступна
+func readServer(t *testing.T, reads *MockReadUseCase) *httptest.Server {
+	authClient := new(MockAuthServiceClient)
+	for token, user := range map[string]*proto.User{"kate_token": {Id: 25, Username: "kate"}, "bob_token": {Id: 26, Username: "bob"}} {
+		token, user := token, user
+		authClient.On("ValidateSession", mock.Anything, mock.MatchedBy(func(req *proto.ValidateSessionRequest) bool { return req.Token == token })).
+			Return(&proto.ValidateSessionResponse{Valid: true, UserId: user.Id, UserRole: "user"}, nil)
+		authClient.On("GetUserProfile", mock.Anything, mock.MatchedBy(func(req *proto.GetUserProfileRequest) bool { return req.UserId == user.Id })).
+			Return(&proto.GetUserProfileResponse{User: user}, nil)
+	}
+	rooms := new(MockRoomUseCase)
+	rooms.On("UserRoomIDs", mock.Anything).Return(nil, nil)
+	rooms.On("CheckAccess", 6, mock.Anything).Return(usecase.ErrRoomForbidden)
+
+	handler := NewMessageHandler(new(MockMessageUseCase), authClient)
+	handler.Rooms = rooms
+	handler.Reads = reads
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMessageHandler_ProtocolReadReceipts(t *testing.T) {
+	reads := new(MockReadUseCase)
+	readAt := time.Date(2023, 10, 27, 10, 0, 0, 0, time.UTC)
+	reads.On("MarkRead", entity.GeneralRoomID, int64(25), 42).
+		Return(&entity.ReadPosition{RoomID: 1, UserID: 25, LastReadSeq: 41, LastReadMessageID: 42, ReadAt: readAt}, true, nil).Once()
+	reads.On("MarkRead", entity.GeneralRoomID, int64(25), 40).
+		Return(&entity.ReadPosition{RoomID: 1, UserID: 25, LastReadSeq: 41, LastReadMessageID: 42, ReadAt: readAt}, false, nil).Once()
+	reads.On("RoomUnreadCount", entity.GeneralRoomID, int64(25)).
+		Return(entity.UnreadCount{RoomID: 1, Unread: 2, LastReadSeq: 41, LastSeq: 43}, nil)
+	reads.On("UnreadCounts", int64(25)).Return([]entity.UnreadCount{{RoomID: 1, Unread: 2, LastReadSeq: 41, LastSeq: 43}}, nil)
+	reads.On("UnreadCounts", int64(26)).Return([]entity.UnreadCount{{RoomID: 1, Unread: 7, LastSeq: 43}}, nil)
+	server := readServer(t, reads)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	kate, err := chatclient.Dial(ctx, "ws"+server.URL[4:]+"/ws", "kate_token")
+	require.NoError(t, err)
+	defer kate.Close()
+	bob, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[4:]+"/ws?token=bob_token", nil)
+	require.NoError(t, err)
+	defer bob.Close()
+
+	// Клиент старого формата запрашивает счетчики; ответ также подтверждает, что bob подключен
+	require.NoError(t, bob.WriteJSON(map[string]string{"type": "unread"}))
+	bob.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := bob.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"unread","rooms":[{"room_id":1,"unread":7,"last_read_seq":0,"last_seq":43}]}`, string(data))
+
+	ack, err := kate.MarkRead(ctx, 0, 42)
+	require.NoError(t, err)
+	assert.Equal(t, protocol.RoomUnread{RoomID: 1, Unread: 2, LastReadSeq: 41, LastSeq: 43}, ack)
+
+	// Собеседник получает отметку о прочтении, другие соединения kate - тоже
+	receipt := `{"type":"read","room_id":1,"user_id":25,"message_id":42,"seq":41,"read_at":"2023-10-27T10:00:00Z"}`
+	_, data, err = bob.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, receipt, string(data))
+	env := <-kate.Events()
+	assert.Equal(t, protocol.OpEvent, env.Op)
+	assert.JSONEq(t, receipt, string(env.Payload))
+
+	// Позиция не сдвинулась назад - отметка не рассылается
+	_, err = kate.MarkRead(ctx, 0, 40)
+	require.NoError(t, err)
+
+	unread, err := kate.Unread(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []protocol.RoomUnread{ack}, unread)
+	assert.Empty(t, kate.Events())
+
+	var protoErr *protocol.Error
+	_, err = kate.MarkRead(ctx, 6, 0)
+	require.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, protocol.CodeForbidden, protoErr.Code)
+	reads.AssertExpectations(t)
+}
@@ -0,0 +1,485 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+	myWeb "github.com/jaliks17/ffffforum/backend/chat-service/pkg/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+// roomOrGeneral подставляет общую комнату, если клиент не указал комнату
+func roomOrGeneral(roomID int) int {
+	if roomID <= 0 {
+		return entity.GeneralRoomID
+	}
+	return roomID
+}
+
+// checkRoomAccess проверяет, может ли пользователь писать в комнату
+func (h *MessageHandler) checkRoomAccess(roomID int, userID int64) error {
+	if roomID == entity.GeneralRoomID {
+		return nil
+	}
+	if h.Rooms == nil {
+		return repository.ErrRoomNotFound
+	}
+	return h.Rooms.CheckAccess(roomID, userID)
+}
+
+// joinUserRooms подключает новое соединение к общей комнате и ко всем комнатам пользователя.
+// Сбой базы не мешает подключению: пользователь останется только в общей комнате.
+func (h *MessageHandler) joinUserRooms(client *myWeb.Client, userID int64) {
+	h.Hub.JoinRoom(client, entity.GeneralRoomID)
+	if h.Rooms == nil {
+		return
+	}
+	roomIDs, err := h.Rooms.UserRoomIDs(userID)
+	if err != nil {
+		log.Printf("Failed to load rooms of user %d: %v", userID, err)
+		return
+	}
+	for _, roomID := range roomIDs {
+		h.Hub.JoinRoom(client, roomID)
+	}
+}
+
+// joinRoom вступает в комнату и подключает к ней все соединения пользователя
+func (h *MessageHandler) joinRoom(userID int64, roomID int) error {
+	err := repository.ErrRoomNotFound
+	if roomID == entity.GeneralRoomID {
+		err = nil
+	} else if h.Rooms != nil {
+		err = h.Rooms.JoinRoom(roomID, userID)
+	}
+	if err != nil {
+		log.Printf("User %d failed to join room %d: %v", userID, roomID, err)
+		return err
+	}
+	h.syncRoomConnection(userID, roomID, true)
+	return nil
+}
+
+// leaveRoom выходит из комнаты и отключает от нее все соединения пользователя
+func (h *MessageHandler) leaveRoom(userID int64, roomID int) error {
+	err := repository.ErrRoomNotFound
+	if h.Rooms != nil {
+		err = h.Rooms.LeaveRoom(roomID, userID)
+	}
+	if err != nil {
+		log.Printf("User %d failed to leave room %d: %v", userID, roomID, err)
+		return err
+	}
+	h.syncRoomConnection(userID, roomID, false)
+	return nil
+}
+
+// sendRoomError сообщает об ошибке комнаты в старом формате ({"type":"room_error"}) только
+// соединению, приславшему запрос
+func (h *MessageHandler) sendRoomError(client *myWeb.Client, roomID int, err error) {
+	if err != nil {
+		h.Hub.SendToClient(client, entity.RoomEvent{Type: "room_error", RoomID: roomID, Error: err.Error()})
+	}
+}
+
+// syncRoomConnection подключает или отключает все соединения пользователя после изменения
+// членства (в том числе на других экземплярах сервиса) и сообщает ему об этом
+func (h *MessageHandler) syncRoomConnection(userID int64, roomID int, joined bool) {
+	if !h.Hub.IsOnline(int(userID)) {
+		return
+	}
+	event := entity.RoomEvent{Type: "joined", RoomID: roomID}
+	if joined {
+		h.Hub.JoinUserRoom(int(userID), roomID)
+	} else {
+		h.Hub.LeaveUserRoom(int(userID), roomID)
+		event.Type = "left"
+	}
+	h.Hub.SendToUser(int(userID), event)
+}
+
+// respondRoomError переводит ошибки комнат и переписок в HTTP-ответ
+func respondRoomError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrRoomNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+	case errors.Is(err, repository.ErrMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room member not found"})
+	case errors.Is(err, usecase.ErrRoomForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrConversationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Conversation not found"})
+	case errors.Is(err, repository.ErrMessageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+	case errors.Is(err, usecase.ErrInvalidRoom), errors.Is(err, usecase.ErrInvalidConversation),
+		errors.Is(err, usecase.ErrInvalidReadPosition):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// roomParams разбирает :id (и :user_id, если withUser) из пути
+func roomParams(c *gin.Context, withUser bool) (int, int64, bool) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || roomID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return 0, 0, false
+	}
+	if !withUser {
+		return roomID, 0, true
+	}
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, 0, false
+	}
+	return roomID, userID, true
+}
+
+// GetRooms godoc
+// @Summary List chat rooms
+// @Description Returns public rooms and private rooms the user is a member of, with the user's role
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} entity.Room
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms [get]
+func (h *MessageHandler) GetRooms(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	rooms, err := h.Rooms.ListRooms(userID)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	if rooms == nil {
+		rooms = []entity.Room{}
+	}
+	c.JSON(http.StatusOK, rooms)
+}
+
+// CreateRoom godoc
+// @Summary Create a chat room
+// @Description Creates a public or private (invite-only) room. The creator becomes its owner
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body entity.CreateRoomRequest true "Room"
+// @Success 201 {object} entity.Room
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms [post]
+func (h *MessageHandler) CreateRoom(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	var req entity.CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	room, err := h.Rooms.CreateRoom(userID, req.Name, req.IsPrivate)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	h.syncRoomConnection(userID, room.ID, true)
+	c.JSON(http.StatusCreated, room)
+}
+
+// GetRoomMessages godoc
+// @Summary Get room history
+// @Description Returns a page of room messages in chronological order. Rooms other than general require membership
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Param before query int false "Messages older than this message ID"
+// @Param after query int false "Messages newer than this message ID"
+// @Param around query int false "Messages around this message ID, including it"
+// @Param limit query int false "Page size" default(50)
+// @Param offset query int false "Offset from the newest message, without a cursor" default(0)
+// @Success 200 {array} entity.Message
+// @Header 200 {integer} X-Total-Count "Total messages in the room"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms/{id}/messages [get]
+func (h *MessageHandler) GetRoomMessages(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	roomID, _, ok := roomParams(c, false)
+	if !ok {
+		return
+	}
+	q, ok := historyQuery(c, roomID)
+	if !ok {
+		return
+	}
+	if err := h.checkRoomAccess(roomID, userID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+
+	page, ok := h.getHistory(c, q)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, page.Messages)
+}
+
+// GetRoomMembers godoc
+// @Summary List room members
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Success 200 {array} entity.RoomMember
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms/{id}/members [get]
+func (h *MessageHandler) GetRoomMembers(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	roomID, _, ok := roomParams(c, false)
+	if !ok {
+		return
+	}
+
+	members, err := h.Rooms.GetMembers(roomID, userID)
+	if err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	if members == nil {
+		members = []entity.RoomMember{}
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// JoinRoom godoc
+// @Summary Join a room
+// @Description Joins a public room. Private rooms can only be joined by invitation
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms/{id}/join [post]
+func (h *MessageHandler) JoinRoom(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	roomID, _, ok := roomParams(c, false)
+	if !ok {
+		return
+	}
+
+	if err := h.Rooms.JoinRoom(roomID, userID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	h.syncRoomConnection(userID, roomID, true)
+	c.JSON(http.StatusOK, gin.H{"message": "Joined room"})
+}
+
+// LeaveRoom godoc
+// @Summary Leave a room
+// @Description The owner cannot leave the room, and nobody can leave the general room
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms/{id}/leave [post]
+func (h *MessageHandler) LeaveRoom(c *gin.Context) {
+	userID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	roomID, _, ok := roomParams(c, false)
+	if !ok {
+		return
+	}
+
+	if err := h.Rooms.LeaveRoom(roomID, userID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	h.syncRoomConnection(userID, roomID, false)
+	c.JSON(http.StatusOK, gin.H{"message": "Left room"})
+}
+
+// InviteRoomMember godoc
+// @Summary Invite a user to a room
+// @Description Owner and admins can add users to the room, including private ones
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Param request body entity.InviteMemberRequest true "User to invite"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms/{id}/members [post]
+func (h *MessageHandler) InviteRoomMember(c *gin.Context) {
+	actorID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	roomID, _, ok := roomParams(c, false)
+	if !ok {
+		return
+	}
+	var req entity.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.UserID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.Rooms.InviteMember(roomID, actorID, req.UserID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	h.syncRoomConnection(req.UserID, roomID, true)
+	c.JSON(http.StatusOK, gin.H{"message": "User invited"})
+}
+
+// RemoveRoomMember godoc
+// @Summary Remove a member from a room
+// @Description Owner can remove anyone but themselves, admins can remove regular members
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms/{id}/members/{user_id} [delete]
+func (h *MessageHandler) RemoveRoomMember(c *gin.Context) {
+	actorID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	roomID, userID, ok := roomParams(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.Rooms.RemoveMember(roomID, actorID, userID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	h.syncRoomConnection(userID, roomID, false)
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// UpdateRoomMemberRole godoc
+// @Summary Change a member's role
+// @Description Only the owner can promote members to admin or demote admins
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Param user_id path int true "User ID"
+// @Param request body entity.UpdateMemberRoleRequest true "New role: admin or member"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/rooms/{id}/members/{user_id} [patch]
+func (h *MessageHandler) UpdateRoomMemberRole(c *gin.Context) {
+	actorID, ok := h.authenticate(c)
+	if !ok {
+		return
+	}
+	roomID, userID, ok := roomParams(c, true)
+	if !ok {
+		return
+	}
+	var req entity.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.Rooms.SetMemberRole(roomID, actorID, userID, req.Role); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated"})
+}
+
+// GetInternalRoomMember godoc
+// @Summary Check room access
+// @Description Internal endpoint used by the forum to decide whether a user may download a file sent to a room. Responds with 200 if the user can read the room, 403 or 404 otherwise
+// @Tags internal
+// @Produce json
+// @Param X-Internal-Token header string true "Shared internal token"
+// @Param id path int true "Room ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /internal/rooms/{id}/members/{user_id} [get]
+func (h *MessageHandler) GetInternalRoomMember(c *gin.Context) {
+	roomID, userID, ok := roomParams(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.checkRoomAccess(roomID, userID); err != nil {
+		respondRoomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member"})
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
+	myWeb "github.com/jaliks17/ffffforum/backend/chat-service/pkg/websocket"
+	"github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRoomUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRoomUseCase) CreateRoom(userID int64, name string, isPrivate bool) (*entity.Room, error) {
+	args := m.Called(userID, name, isPrivate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Room), args.Error(1)
+}
+
+func (m *MockRoomUseCase) ListRooms(userID int64) ([]entity.Room, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Room), args.Error(1)
+}
+
+func (m *MockRoomUseCase) UserRoomIDs(userID int64) ([]int, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockRoomUseCase) CheckAccess(roomID int, userID int64) error {
+	args := m.Called(roomID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoomUseCase) JoinRoom(roomID int, userID int64) error {
+	args := m.Called(roomID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoomUseCase) LeaveRoom(roomID int, userID int64) error {
+	args := m.Called(roomID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoomUseCase) GetMembers(roomID int, userID int64) ([]entity.RoomMember, error) {
+	args := m.Called(roomID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.RoomMember), args.Error(1)
+}
+
+func (m *MockRoomUseCase) InviteMember(roomID int, actorID, userID int64) error {
+	args := m.Called(roomID, actorID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoomUseCase) RemoveMember(roomID int, actorID, userID int64) error {
+	args := m.Called(roomID, actorID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoomUseCase) SetMemberRole(roomID int, actorID, userID int64, role string) error {
+	args := m.Called(roomID, actorID, userID, role)
+	return args.Error(0)
+}
+
+// dialAs подключает пользователя по WebSocket и ждет, пока соединение попадет в пул
+func dialAs(t *testing.T, hub *myWeb.Hub, server *httptest.Server, token string, userID int) *websocket.Conn {
+	before := hub.ConnectionCount(userID)
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[4:]+"/ws?token="+token, nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Eventually(t, func() bool { return hub.ConnectionCount(userID) == before+1 }, time.Second, 10*time.Millisecond)
+	return ws
+}
+
+func TestMessageHandler_RoomBroadcast(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	rooms := new(MockRoomUseCase)
+	authClient := new(MockAuthServiceClient)
+	for _, u := range []struct {
+		token string
+		id    int64
+		name  string
+	}{{"erin_token", 21, "erin"}, {"frank_token", 22, "frank"}} {
+		authClient.On("ValidateSession", mock.Anything, mock.MatchedBy(func(req *proto.ValidateSessionRequest) bool {
+			return req.Token == u.token
+		})).Return(&proto.ValidateSessionResponse{Valid: true, UserId: u.id, UserRole: "user"}, nil).Once()
+		authClient.On("GetUserProfile", mock.Anything, mock.MatchedBy(func(req *proto.GetUserProfileRequest) bool {
+			return req.UserId == u.id
+		})).Return(&proto.GetUserProfileResponse{User: &proto.User{Id: u.id, Username: u.name}}, nil).Once()
+	}
+	rooms.On("UserRoomIDs", int64(21)).Return([]int{4}, nil)
+	rooms.On("UserRoomIDs", int64(22)).Return(nil, nil)
+
+	handler := NewMessageHandler(uc, authClient)
+	handler.Rooms = rooms
+
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	erin := dialAs(t, handler.Hub, server, "erin_token", 21)
+	defer erin.Close()
+	frank := dialAs(t, handler.Hub, server, "frank_token", 22)
+	defer frank.Close()
+
+	handler.broadcast(entity.Message{ID: 1, RoomID: 4, UserID: 21, Username: "erin", Message: "room only"})
+	handler.broadcast(entity.Message{ID: 2, UserID: 21, Username: "erin", Message: "everyone"})
+
+	var msg entity.Message
+	erin.SetReadDeadline(time.Now().Add(time.Second))
+	assert.NoError(t, erin.ReadJSON(&msg))
+	assert.Equal(t, "room only", msg.Message)
+	assert.Equal(t, 4, msg.RoomID)
+
+	// Frank не состоит в комнате 4: первым он получает сообщение общей комнаты
+	frank.SetReadDeadline(time.Now().Add(time.Second))
+	assert.NoError(t, frank.ReadJSON(&msg))
+	assert.Equal(t, "everyone", msg.Message)
+	assert.Equal(t, entity.GeneralRoomID, msg.RoomID)
+}
+
+func TestMessageHandler_RoomJoinLeave(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	rooms := new(MockRoomUseCase)
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: true, UserId: 23, UserRole: "user"}, nil).Once()
+	authClient.On("GetUserProfile", mock.Anything, mock.Anything).
+		Return(&proto.GetUserProfileResponse{User: &proto.User{Id: 23, Username: "gina"}}, nil).Once()
+	rooms.On("UserRoomIDs", int64(23)).Return(nil, nil)
+	rooms.On("JoinRoom", 5, int64(23)).Return(nil).Once()
+	rooms.On("JoinRoom", 6, int64(23)).Return(usecase.ErrRoomForbidden).Once()
+	rooms.On("CheckAccess", 6, int64(23)).Return(usecase.ErrRoomForbidden).Once()
+	rooms.On("LeaveRoom", 5, int64(23)).Return(nil).Once()
+
+	handler := NewMessageHandler(uc, authClient)
+	handler.Rooms = rooms
+
+	router := gin.Default()
+	router.GET("/ws", handler.HandleConnections)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ws := dialAs(t, handler.Hub, server, "gina_token", 23)
+	defer ws.Close()
+	client := handler.Hub.UserClients(23)[0]
+
+	read := func() entity.RoomEvent {
+		var event entity.RoomEvent
+		ws.SetReadDeadline(time.Now().Add(time.Second))
+		assert.NoError(t, ws.ReadJSON(&event))
+		return event
+	}
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "join", "room_id": 5}))
+	assert.Equal(t, entity.RoomEvent{Type: "joined", RoomID: 5}, read())
+	assert.True(t, handler.Hub.InRoom(client, 5))
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "join", "room_id": 6}))
+	event := read()
+	assert.Equal(t, "room_error", event.Type)
+	assert.False(t, handler.Hub.InRoom(client, 6))
+
+	// Писать в комнату, где пользователь не состоит, нельзя
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "message", "room_id": 6, "message": "hi"}))
+	assert.Equal(t, "room_error", read().Type)
+	uc.AssertNotCalled(t, "SaveMessage", mock.Anything)
+
+	assert.NoError(t, ws.WriteJSON(map[string]interface{}{"type": "leave", "room_id": 5}))
+	assert.Equal(t, entity.RoomEvent{Type: "left", RoomID: 5}, read())
+	assert.False(t, handler.Hub.InRoom(client, 5))
+	rooms.AssertExpectations(t)
+}
+
+func TestMessageHandler_RoomEndpoints(t *testing.T) {
+	uc := new(MockMessageUseCase)
+	rooms := new(MockRoomUseCase)
+	authClient := new(MockAuthServiceClient)
+	authClient.On("ValidateSession", mock.Anything, mock.MatchedBy(func(req *proto.ValidateSessionRequest) bool {
+		return req.Token == "token"
+	})).Return(&proto.ValidateSessionResponse{Valid: true, UserId: 5, UserRole: "user"}, nil)
+	authClient.On("ValidateSession", mock.Anything, mock.Anything).
+		Return(&proto.ValidateSessionResponse{Valid: false}, nil)
+
+	handler := NewMessageHandler(uc, authClient)
+	handler.Rooms = rooms
+
+	router := gin.Default()
+	router.GET("/rooms", handler.GetRooms)
+	router.POST("/rooms", handler.CreateRoom)
+	router.GET("/rooms/:id/messages", handler.GetRoomMessages)
+	router.DELETE("/rooms/:id/members/:user_id", handler.RemoveRoomMember)
+	router.PATCH("/rooms/:id/members/:user_id", handler.UpdateRoomMemberRole)
+	router.GET("/internal/rooms/:id/members/:user_id", handler.GetInternalRoomMember)
+
+	call := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	rooms.On("ListRooms", int64(5)).Return([]entity.Room{{ID: 1, Name: "general"}}, nil).Once()
+	rooms.On("CreateRoom", int64(5), "gophers", true).Return(&entity.Room{ID: 2, Name: "gophers", IsPrivate: true, Role: entity.RoomRoleOwner}, nil).Once()
+	rooms.On("CreateRoom", int64(5), "", false).Return(nil, usecase.ErrInvalidRoom).Maybe()
+	rooms.On("CheckAccess", 2, int64(5)).Return(nil).Once()
+	rooms.On("CheckAccess", 3, int64(5)).Return(usecase.ErrRoomForbidden).Once()
+	rooms.On("CheckAccess", 9, int64(5)).Return(repository.ErrRoomNotFound).Once()
+	rooms.On("RemoveMember", 2, int64(5), int64(7)).Return(repository.ErrMemberNotFound).Once()
+	rooms.On("SetMemberRole", 2, int64(5), int64(7), "admin").Return(nil).Once()
+	uc.On("GetMessages", entity.MessageQuery{RoomID: 2, After: 1, Limit: 10}).
+		Return(&entity.MessagePage{Messages: []entity.Message{{ID: 2, RoomID: 2, Message: "hi"}}, Total: 2}, nil).Once()
+	uc.On("GetMessages", entity.MessageQuery{RoomID: entity.GeneralRoomID}).Return(&entity.MessagePage{Messages: []entity.Message{}}, nil).Once()
+
+	assert.Equal(t, http.StatusUnauthorized, call("GET", "/rooms", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, call("GET", "/rooms", "bad", "").Code)
+	assert.Equal(t, http.StatusOK, call("GET", "/rooms", "token", "").Code)
+
+	w := call("POST", "/rooms", "token", `{"name":"gophers","is_private":true}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"role":"owner"`)
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/rooms", "token", `{}`).Code)
+
+	w = call("GET", "/rooms/2/messages?after=1&limit=10", "token", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"room_id":2`)
+	assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+	w = call("GET", "/rooms/1/messages", "token", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+	assert.Equal(t, http.StatusForbidden, call("GET", "/rooms/3/messages", "token", "").Code)
+	assert.Equal(t, http.StatusNotFound, call("GET", "/rooms/9/messages", "token", "").Code)
+	assert.Equal(t, http.StatusBadRequest, call("GET", "/rooms/abc/messages", "token", "").Code)
+
+	assert.Equal(t, http.StatusNotFound, call("DELETE", "/rooms/2/members/7", "token", "").Code)
+	assert.Equal(t, http.StatusOK, call("PATCH", "/rooms/2/members/7", "token", `{"role":"admin"}`).Code)
+
+	// Проверка доступа для файлов сообщений: участник, посторонний и несуществующая комната
+	rooms.On("CheckAccess", 2, int64(7)).Return(nil).Once()
+	rooms.On("CheckAccess", 3, int64(7)).Return(usecase.ErrRoomForbidden).Once()
+	rooms.On("CheckAccess", 9, int64(7)).Return(repository.ErrRoomNotFound).Once()
+	assert.Equal(t, http.StatusOK, call("GET", "/internal/rooms/2/members/7", "", "").Code)
+	assert.Equal(t, http.StatusForbidden, call("GET", "/internal/rooms/3/members/7", "", "").Code)
+	assert.Equal(t, http.StatusNotFound, call("GET", "/internal/rooms/9/members/7", "", "").Code)
+	assert.Equal(t, http.StatusOK, call("GET", "/internal/rooms/1/members/7", "", "").Code)
+
+	rooms.AssertExpectations(t)
+	uc.AssertExpectations(t)
+}
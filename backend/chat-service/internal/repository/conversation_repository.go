@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+var ErrConversationNotFound = errors.New("conversation not found")
+
+type ConversationRepository interface {
+	// CreateConversation создает переписку с участниками. Для личной переписки directKey
+	// не пустой: если переписка этой пары уже есть, возвращается она.
+	CreateConversation(conv *entity.Conversation, createdBy int64, directKey string) error
+	ListConversations(userID int64) ([]entity.Conversation, error)
+}
+
+type conversationRepository struct {
+	db *sql.DB
+}
+
+func NewConversationRepository(db *sql.DB) ConversationRepository {
+	return &conversationRepository{db: db}
+}
+
+func (repo *conversationRepository) CreateConversation(conv *entity.Conversation, createdBy int64, directKey string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Параллельный запрос мог уже создать переписку этой пары: тогда вставка ничего
+	// не вернет, и мы отдаем существующую
+	err = tx.QueryRow(
+		"INSERT INTO chat_rooms (name, is_private, created_by, kind, direct_key) VALUES ($1, TRUE, $2, $3, NULLIF($4, '')) "+
+			"ON CONFLICT (direct_key) DO NOTHING RETURNING id, created_at",
+		conv.Name, createdBy, conv.Kind, directKey,
+	).Scan(&conv.ID, &conv.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) && directKey != "" {
+		tx.Rollback()
+		return repo.getDirect(conv, directKey)
+	}
+	if err != nil {
+		return fmt.Errorf("error creating conversation: %w", err)
+	}
+
+	for _, userID := range conv.Participants {
+		role := entity.RoomRoleMember
+		if conv.Kind == entity.RoomKindGroup && userID == createdBy {
+			role = entity.RoomRoleOwner
+		}
+		if _, err := tx.Exec("INSERT INTO chat_room_members (room_id, user_id, role) VALUES ($1, $2, $3)", conv.ID, userID, role); err != nil {
+			return fmt.Errorf("error adding participant %d: %w", userID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// getDirect заполняет conv данными существующей личной переписки
+func (repo *conversationRepository) getDirect(conv *entity.Conversation, directKey string) error {
+	err := repo.db.QueryRow("SELECT id, created_at FROM chat_rooms WHERE direct_key = $1", directKey).
+		Scan(&conv.ID, &conv.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrConversationNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error getting conversation: %w", err)
+	}
+	return nil
+}
+
+// ListConversations возвращает переписки пользователя с последним сообщением и числом
+// непрочитанных (чужих сообщений после позиции прочтения), новые сверху
+func (repo *conversationRepository) ListConversations(userID int64) ([]entity.Conversation, error) {
+	query := "SELECT r.id, r.kind, r.name, r.created_at, " +
+		"(SELECT array_agg(p.user_id ORDER BY p.user_id) FROM chat_room_members p WHERE p.room_id = r.id) AS participants, " +
+		"lm.id, lm.user_id, lm.username, lm.content, lm.timestamp, " +
+		"(SELECT COUNT(*) FROM (SELECT 1 FROM chat_messages c WHERE c.room_id = r.id AND c.seq > COALESCE(p.last_read_seq, 0) AND c.user_id <> $1 LIMIT $2) u) AS unread_count " +
+		"FROM chat_room_members m JOIN chat_rooms r ON r.id = m.room_id " +
+		"LEFT JOIN chat_read_positions p ON p.room_id = r.id AND p.user_id = $1 " +
+		"LEFT JOIN LATERAL (SELECT id, user_id, username, content, timestamp FROM chat_messages WHERE room_id = r.id ORDER BY id DESC LIMIT 1) lm ON TRUE " +
+		"WHERE m.user_id = $1 AND r.kind IN ('direct', 'group') " +
+		"ORDER BY COALESCE(lm.timestamp, r.created_at) DESC"
+
+	rows, err := repo.db.Query(query, userID, entity.MaxUnreadCount)
+	if err != nil {
+		return nil, fmt.Errorf("error listing conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var conversations []entity.Conversation
+	for rows.Next() {
+		var conv entity.Conversation
+		var (
+			lastID        sql.NullInt64
+			lastUserID    sql.NullInt64
+			lastUsername  sql.NullString
+			lastContent   sql.NullString
+			lastTimestamp sql.NullTime
+		)
+		err := rows.Scan(&conv.ID, &conv.Kind, &conv.Name, &conv.CreatedAt, pq.Array(&conv.Participants),
+			&lastID, &lastUserID, &lastUsername, &lastContent, &lastTimestamp, &conv.UnreadCount)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		if lastID.Valid {
+			conv.LastMessage = &entity.Message{
+				ID:        int(lastID.Int64),
+				RoomID:    conv.ID,
+				UserID:    int(lastUserID.Int64),
+				Username:  lastUsername.String,
+				Message:   lastContent.String,
+				Timestamp: lastTimestamp.Time,
+			}
+		}
+		conversations = append(conversations, conv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return conversations, nil
+}
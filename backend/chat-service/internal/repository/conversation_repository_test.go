@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversationRepository_CreateConversation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewConversationRepository(db)
+	now := time.Now()
+	insertRoom := regexp.QuoteMeta("INSERT INTO chat_rooms (name, is_private, created_by, kind, direct_key) VALUES ($1, TRUE, $2, $3, NULLIF($4, '')) ON CONFLICT (direct_key) DO NOTHING RETURNING id, created_at")
+
+	t.Run("group with owner", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertRoom).
+			WithArgs("plans", int64(5), entity.RoomKindGroup, "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(8, now))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_room_members (room_id, user_id, role) VALUES ($1, $2, $3)")).
+			WithArgs(8, int64(5), entity.RoomRoleOwner).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_room_members (room_id, user_id, role) VALUES ($1, $2, $3)")).
+			WithArgs(8, int64(7), entity.RoomRoleMember).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		conv := &entity.Conversation{Kind: entity.RoomKindGroup, Name: "plans", Participants: []int64{5, 7}}
+		assert.NoError(t, repo.CreateConversation(conv, 5, ""))
+		assert.Equal(t, 8, conv.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("existing direct conversation", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertRoom).
+			WithArgs("", int64(5), entity.RoomKindDirect, "5:7").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+		mock.ExpectRollback()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, created_at FROM chat_rooms WHERE direct_key = $1")).
+			WithArgs("5:7").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(4, now))
+
+		conv := &entity.Conversation{Kind: entity.RoomKindDirect, Participants: []int64{5, 7}}
+		assert.NoError(t, repo.CreateConversation(conv, 5, "5:7"))
+		assert.Equal(t, 4, conv.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConversationRepository_ListConversations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewConversationRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE m.user_id = $1 AND r.kind IN ('direct', 'group')")).
+		WithArgs(int64(5), entity.MaxUnreadCount).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "name", "created_at", "participants", "lm_id", "lm_user_id", "lm_username", "lm_content", "lm_timestamp", "unread_count"}).
+			AddRow(4, "direct", "", now, "{5,7}", 12, 7, "alice", "hi", now, 2).
+			AddRow(8, "group", "plans", now, "{5,7,8}", nil, nil, nil, nil, nil, 0))
+
+	conversations, err := repo.ListConversations(5)
+	assert.NoError(t, err)
+	assert.Len(t, conversations, 2)
+	assert.Equal(t, []int64{5, 7}, conversations[0].Participants)
+	assert.Equal(t, 2, conversations[0].UnreadCount)
+	assert.Equal(t, "hi", conversations[0].LastMessage.Message)
+	assert.Equal(t, 4, conversations[0].LastMessage.RoomID)
+	assert.Nil(t, conversations[1].LastMessage)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
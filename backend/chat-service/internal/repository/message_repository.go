@@ -2,18 +2,39 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+var ErrMessageNotFound = errors.New("message not found")
+
+// ErrDuplicateMessage возвращается SaveMessage, если сообщение с тем же ClientMsgID
+// уже сохранено; msg при этом заполняется сохраненным сообщением
+var ErrDuplicateMessage = errors.New("duplicate message")
+
+// messageSelect - колонки сообщения вместе с вложениями; условие и порядок добавляет запрос
+const messageSelect = "SELECT id, room_id, seq, user_id, username, content as message, timestamp, " +
+	"COALESCE((SELECT array_agg(a.attachment_id ORDER BY a.position) FROM chat_message_attachments a WHERE a.message_id = chat_messages.id), '{}') AS attachment_ids " +
+	"FROM chat_messages "
+
 type MessageRepository interface {
 	SaveMessage(msg *entity.Message) error
-	GetMessages() ([]entity.Message, error)
+	// GetMessages возвращает страницу истории комнаты; курсор из другой комнаты - ErrMessageNotFound
+	GetMessages(q entity.MessageQuery) ([]entity.Message, error)
+	// CountMessages возвращает число сообщений комнаты
+	CountMessages(roomID int) (int, error)
+	// GetRoomMessagesAfter возвращает до limit сообщений комнаты с seq больше afterSeq
+	GetRoomMessagesAfter(roomID int, afterSeq int64, limit int) ([]entity.Message, error)
+	GetMessageByID(id int) (*entity.Message, error)
+	// GetMessageByClientID ищет сообщение пользователя по ключу идемпотентности
+	GetMessageByClientID(userID int, clientMsgID string) (*entity.Message, error)
+	DeleteMessage(id int) error
 	DeleteOldMessages(before time.Time) error
 }
 
@@ -36,19 +57,63 @@ func (repo *messageRepository) SaveMessage(msg *entity.Message) error {
 	}
 	defer tx.Rollback() // Rollback in case of error
 
-	query := "INSERT INTO chat_messages (user_id, username, content, timestamp) VALUES ($1, $2, $3, $4) RETURNING id"
+	if msg.RoomID == 0 {
+		msg.RoomID = entity.GeneralRoomID
+	}
+
+	// Номер в комнате выдается под блокировкой строки комнаты до конца транзакции, поэтому
+	// номера идут в порядке коммитов: клиент, получивший seq N, может запросить все до N
+	var seq int64
+	err = tx.QueryRow("UPDATE chat_rooms SET last_seq = last_seq + 1 WHERE id = $1 RETURNING last_seq", msg.RoomID).Scan(&seq)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRoomNotFound
+	}
+	if err != nil {
+		log.Printf("Error allocating message sequence in transaction: %v", err)
+		return fmt.Errorf("error allocating message sequence: %w", err)
+	}
+
+	query := "INSERT INTO chat_messages (room_id, seq, user_id, username, content, timestamp, client_msg_id) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')) " +
+		"ON CONFLICT (user_id, client_msg_id) WHERE client_msg_id IS NOT NULL DO NOTHING RETURNING id"
 
 	var id int
 	// Use the transaction's QueryRow
-	err = tx.QueryRow(query, msg.UserID, msg.Username, msg.Message, msg.Timestamp).Scan(&id)
+	err = tx.QueryRow(query, msg.RoomID, seq, msg.UserID, msg.Username, msg.Message, msg.Timestamp, msg.ClientMsgID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) && msg.ClientMsgID != "" {
+		// Тот же ключ только что сохранил параллельный запрос; откат возвращает и номер
+		tx.Rollback()
+		existing, lookupErr := repo.GetMessageByClientID(msg.UserID, msg.ClientMsgID)
+		if lookupErr != nil {
+			return lookupErr
+		}
+		*msg = *existing
+		return ErrDuplicateMessage
+	}
 	if err != nil {
 		log.Printf("Error saving message in transaction: %v", err)
 		return fmt.Errorf("error saving message: %w", err)
 	}
 
 	msg.ID = id
+	msg.Seq = seq
 	log.Printf("Message saved successfully in transaction. About to commit. ID: %d", id)
 
+	// Упоминания сохраняются в той же транзакции, что и сообщение
+	for _, userID := range msg.Mentions {
+		if _, err := tx.Exec("INSERT INTO chat_message_mentions (message_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", id, userID); err != nil {
+			log.Printf("Error saving mention of user %d in transaction: %v", userID, err)
+			return fmt.Errorf("error saving mention: %w", err)
+		}
+	}
+
+	// Ссылки на вложения тоже пишутся в транзакции сообщения, порядок сохраняется в position
+	for i, attachmentID := range msg.AttachmentIDs {
+		if _, err := tx.Exec("INSERT INTO chat_message_attachments (message_id, attachment_id, position) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING", id, attachmentID, i); err != nil {
+			log.Printf("Error saving attachment %d in transaction: %v", attachmentID, err)
+			return fmt.Errorf("error saving attachment: %w", err)
+		}
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		log.Printf("CRITICAL ERROR: Failed to commit transaction for message ID %d: %v", id, err)
@@ -60,24 +125,99 @@ func (repo *messageRepository) SaveMessage(msg *entity.Message) error {
 	return nil
 }
 
-func (repo *messageRepository) GetMessages() ([]entity.Message, error) {
-	log.Println("Executing GetMessages query...")
+// GetMessages возвращает страницу истории комнаты в хронологическом порядке. Limit
+// уже проверен usecase-ом.
+func (repo *messageRepository) GetMessages(q entity.MessageQuery) ([]entity.Message, error) {
+	roomID := q.RoomID
+	if roomID == 0 {
+		roomID = entity.GeneralRoomID
+	}
+	log.Printf("Executing GetMessages query for room %d: before=%d after=%d around=%d limit=%d offset=%d",
+		roomID, q.Before, q.After, q.Around, q.Limit, q.Offset)
+
+	var cursor int
+	switch {
+	case q.Before != 0:
+		cursor = q.Before
+	case q.After != 0:
+		cursor = q.After
+	case q.Around != 0:
+		cursor = q.Around
+	default:
+		return repo.queryLatest(messageSelect+"WHERE room_id = $1 ORDER BY seq DESC LIMIT $2 OFFSET $3", roomID, q.Limit, q.Offset)
+	}
+
+	// Курсор - ID сообщения, а порядок в комнате задает seq
+	var seq int64
+	err := repo.db.QueryRow("SELECT seq FROM chat_messages WHERE id = $1 AND room_id = $2", cursor, roomID).Scan(&seq)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting cursor message: %w", err)
+	}
+
+	switch {
+	case q.Before != 0:
+		return repo.queryLatest(messageSelect+"WHERE room_id = $1 AND seq < $2 ORDER BY seq DESC LIMIT $3", roomID, seq, q.Limit)
+	case q.After != 0:
+		return repo.queryMessages(messageSelect+"WHERE room_id = $1 AND seq > $2 ORDER BY seq ASC LIMIT $3", roomID, seq, q.Limit)
+	}
+	older, err := repo.queryLatest(messageSelect+"WHERE room_id = $1 AND seq < $2 ORDER BY seq DESC LIMIT $3", roomID, seq, q.Limit/2)
+	if err != nil {
+		return nil, err
+	}
+	// Если старых сообщений меньше половины, страницу добирают новые
+	newer, err := repo.queryMessages(messageSelect+"WHERE room_id = $1 AND seq >= $2 ORDER BY seq ASC LIMIT $3", roomID, seq, q.Limit-len(older))
+	if err != nil {
+		return nil, err
+	}
+	return append(older, newer...), nil
+}
+
+// CountMessages возвращает число сообщений комнаты
+func (repo *messageRepository) CountMessages(roomID int) (int, error) {
+	var total int
+	if err := repo.db.QueryRow("SELECT COUNT(*) FROM chat_messages WHERE room_id = $1", roomID).Scan(&total); err != nil {
+		return 0, fmt.Errorf("error counting messages: %w", err)
+	}
+	return total, nil
+}
+
+// GetRoomMessagesAfter возвращает сообщения, пропущенные клиентом, в порядке seq
+func (repo *messageRepository) GetRoomMessagesAfter(roomID int, afterSeq int64, limit int) ([]entity.Message, error) {
+	return repo.queryMessages(messageSelect+"WHERE room_id = $1 AND seq > $2 ORDER BY seq ASC LIMIT $3", roomID, afterSeq, limit)
+}
+
+// GetMessageByID возвращает сообщение вместе с вложениями или ErrMessageNotFound
+func (repo *messageRepository) GetMessageByID(id int) (*entity.Message, error) {
+	return repo.getMessage(messageSelect+"WHERE id = $1", id)
+}
 
-	// Remove temporary code for closing and re-opening connection
-	
-	// Add a ping to check connection health
-	if err := repo.db.Ping(); err != nil {
-		log.Printf("Database ping failed in GetMessages: %v", err)
-		return nil, fmt.Errorf("database ping failed: %w", err)
+// GetMessageByClientID возвращает сообщение пользователя с ключом clientMsgID или ErrMessageNotFound
+func (repo *messageRepository) GetMessageByClientID(userID int, clientMsgID string) (*entity.Message, error) {
+	msg, err := repo.getMessage(messageSelect+"WHERE user_id = $1 AND client_msg_id = $2", userID, clientMsgID)
+	if msg != nil {
+		msg.ClientMsgID = clientMsgID
 	}
+	return msg, err
+}
 
-	// Log the query we're about to execute
-	query := "SELECT id, user_id, username, content as message, timestamp FROM chat_messages ORDER BY timestamp ASC"
-	log.Printf("Executing query: %s", query)
+func (repo *messageRepository) getMessage(query string, args ...interface{}) (*entity.Message, error) {
+	var msg entity.Message
+	err := scanMessage(repo.db.QueryRow(query, args...), &msg)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting message: %w", err)
+	}
+	return &msg, nil
+}
 
-	rows, err := repo.db.Query(query)
+func (repo *messageRepository) queryMessages(query string, args ...interface{}) ([]entity.Message, error) {
+	rows, err := repo.db.Query(query, args...)
 	if err != nil {
-		log.Printf("Query error in GetMessages: %v", err)
 		return nil, fmt.Errorf("query error: %w", err)
 	}
 	defer rows.Close()
@@ -85,25 +225,51 @@ func (repo *messageRepository) GetMessages() ([]entity.Message, error) {
 	var messages []entity.Message
 	for rows.Next() {
 		var msg entity.Message
-		log.Println("Scanning message row...")
-		err := rows.Scan(&msg.ID, &msg.UserID, &msg.Username, &msg.Message, &msg.Timestamp)
-		if err != nil {
-			log.Printf("Scan error in GetMessages: %v", err)
+		if err := scanMessage(rows, &msg); err != nil {
 			return nil, fmt.Errorf("scan error: %w", err)
 		}
 		messages = append(messages, msg)
 	}
-	// Check for errors from iterating over rows.
-	if err = rows.Err(); err != nil {
-		log.Printf("Rows iteration error in GetMessages: %v", err)
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows iteration error: %w", err)
 	}
-	log.Printf("Successfully fetched %d messages.", len(messages))
-	// Log the fetched messages before returning
-	log.Printf("Fetched messages content: %+v", messages)
 	return messages, nil
 }
 
+// queryLatest выполняет запрос с ORDER BY seq DESC и возвращает сообщения в хронологическом порядке
+func (repo *messageRepository) queryLatest(query string, args ...interface{}) ([]entity.Message, error) {
+	messages, err := repo.queryMessages(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+// scanMessage читает строку, выбранную через messageSelect
+func scanMessage(row interface{ Scan(...interface{}) error }, msg *entity.Message) error {
+	return row.Scan(&msg.ID, &msg.RoomID, &msg.Seq, &msg.UserID, &msg.Username, &msg.Message, &msg.Timestamp, pq.Array(&msg.AttachmentIDs))
+}
+
+// DeleteMessage удаляет сообщение; упоминания и вложения удаляются каскадно
+func (repo *messageRepository) DeleteMessage(id int) error {
+	result, err := repo.db.Exec("DELETE FROM chat_messages WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("error deleting message: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrMessageNotFound
+	}
+	return nil
+}
+
 func (repo *messageRepository) DeleteOldMessages(before time.Time) error {
 	log.Printf("Deleting messages before: %v", before)
 
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -21,6 +22,13 @@ func TestSaveMessage(t *testing.T) {
 
 	repo := NewMessageRepository(db)
 
+	insertQuery := regexp.QuoteMeta("INSERT INTO chat_messages (room_id, seq, user_id, username, content, timestamp, client_msg_id) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')) ON CONFLICT")
+	expectSeq := func(seq int64) {
+		mock.ExpectQuery(regexp.QuoteMeta("UPDATE chat_rooms SET last_seq = last_seq + 1 WHERE id = $1 RETURNING last_seq")).
+			WithArgs(entity.GeneralRoomID).
+			WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(seq))
+	}
+
 	tests := []struct {
 		name    string
 		msg     entity.Message
@@ -36,8 +44,9 @@ func TestSaveMessage(t *testing.T) {
 			},
 			mock: func() {
 				mock.ExpectBegin()
-				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO chat_messages (user_id, username, content, timestamp) VALUES ($1, $2, $3, $4) RETURNING id")).
-					WithArgs(1, "testuser", "Hello world", sqlmock.AnyArg()).
+				expectSeq(1)
+				mock.ExpectQuery(insertQuery).
+					WithArgs(entity.GeneralRoomID, int64(1), 1, "testuser", "Hello world", sqlmock.AnyArg(), "").
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 				mock.ExpectCommit()
 			},
@@ -52,8 +61,9 @@ func TestSaveMessage(t *testing.T) {
 			},
 			mock: func() {
 				mock.ExpectBegin()
-				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO chat_messages (user_id, username, content, timestamp) VALUES ($1, $2, $3, $4) RETURNING id")).
-					WithArgs(2, "testuser", "Hello world", sqlmock.AnyArg()).
+				expectSeq(1)
+				mock.ExpectQuery(insertQuery).
+					WithArgs(entity.GeneralRoomID, int64(1), 2, "testuser", "Hello world", sqlmock.AnyArg(), "").
 					WillReturnError(errors.New("database error"))
 				mock.ExpectRollback()
 			},
@@ -68,13 +78,116 @@ func TestSaveMessage(t *testing.T) {
 			},
 			mock: func() {
 				mock.ExpectBegin()
-				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO chat_messages (user_id, username, content, timestamp) VALUES ($1, $2, $3, $4) RETURNING id")).
-					WithArgs(3, "", "test", sqlmock.AnyArg()).
+				expectSeq(1)
+				mock.ExpectQuery(insertQuery).
+					WithArgs(entity.GeneralRoomID, int64(1), 3, "", "test", sqlmock.AnyArg(), "").
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 				mock.ExpectCommit()
 			},
 			wantErr: false,
 		},
+		{
+			name: "message with mentions",
+			msg: entity.Message{
+				UserID:   4,
+				Username: "testuser",
+				Message:  "hi @alice",
+				Mentions: []int64{7},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				expectSeq(1)
+				mock.ExpectQuery(insertQuery).
+					WithArgs(entity.GeneralRoomID, int64(1), 4, "testuser", "hi @alice", sqlmock.AnyArg(), "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_message_mentions (message_id, user_id) VALUES ($1, $2)")).
+					WithArgs(5, int64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "message with attachments",
+			msg: entity.Message{
+				UserID:        4,
+				Username:      "testuser",
+				Message:       "see file",
+				AttachmentIDs: []int64{12, 10},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				expectSeq(1)
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO chat_messages")).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_message_attachments (message_id, attachment_id, position) VALUES ($1, $2, $3)")).
+					WithArgs(7, int64(12), 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_message_attachments (message_id, attachment_id, position) VALUES ($1, $2, $3)")).
+					WithArgs(7, int64(10), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "mention save error rolls back",
+			msg: entity.Message{
+				UserID:   4,
+				Username: "testuser",
+				Message:  "hi @alice",
+				Mentions: []int64{7},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				expectSeq(1)
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO chat_messages")).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_message_mentions")).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown room",
+			msg: entity.Message{
+				RoomID:   99,
+				UserID:   4,
+				Username: "testuser",
+				Message:  "hi",
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE chat_rooms SET last_seq")).
+					WithArgs(99).
+					WillReturnRows(sqlmock.NewRows([]string{"last_seq"}))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "client retry saved concurrently",
+			msg: entity.Message{
+				UserID:      4,
+				Username:    "testuser",
+				Message:     "hi",
+				ClientMsgID: "c1",
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				expectSeq(3)
+				mock.ExpectQuery(insertQuery).
+					WithArgs(entity.GeneralRoomID, int64(3), 4, "testuser", "hi", sqlmock.AnyArg(), "c1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+				mock.ExpectQuery(regexp.QuoteMeta("FROM chat_messages WHERE user_id = $1 AND client_msg_id = $2")).
+					WithArgs(4, "c1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "seq", "user_id", "username", "message", "timestamp", "attachment_ids"}).
+						AddRow(8, 1, 2, 4, "testuser", "hi", time.Now(), "{}"))
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,7 +209,7 @@ func TestGetMessages(t *testing.T) {
 
 	repo := NewMessageRepository(db)
 
-	expectedQuery := regexp.QuoteMeta("SELECT id, user_id, username, content as message, timestamp FROM chat_messages ORDER BY timestamp ASC")
+	expectedQuery := regexp.QuoteMeta("SELECT id, room_id, seq, user_id, username, content as message, timestamp, COALESCE((SELECT array_agg(a.attachment_id ORDER BY a.position) FROM chat_message_attachments a") + ".*" + regexp.QuoteMeta("FROM chat_messages WHERE room_id = $1 ORDER BY seq DESC LIMIT $2 OFFSET $3")
 
 	tests := []struct {
 		name    string
@@ -107,15 +220,17 @@ func TestGetMessages(t *testing.T) {
 		{
 			name: "successful get messages",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "user_id", "username", "message", "timestamp"}).
-					AddRow(1, 101, "user1", "message 1", time.Now()).
-					AddRow(2, 102, "user2", "message 2", time.Now())
+				// Последние сообщения выбираются от новых к старым, а возвращаются по порядку
+				rows := sqlmock.NewRows([]string{"id", "room_id", "seq", "user_id", "username", "message", "timestamp", "attachment_ids"}).
+					AddRow(2, 1, 2, 102, "user2", "message 2", time.Now(), "{3,1}").
+					AddRow(1, 1, 1, 101, "user1", "message 1", time.Now(), "{}")
 				mock.ExpectQuery(expectedQuery).
+					WithArgs(entity.GeneralRoomID, 50, 0).
 					WillReturnRows(rows)
 			},
 			want: []entity.Message{
-				{ID: 1, UserID: 101, Username: "user1", Message: "message 1"},
-				{ID: 2, UserID: 102, Username: "user2", Message: "message 2"},
+				{ID: 1, UserID: 101, Username: "user1", Message: "message 1", AttachmentIDs: []int64{}},
+				{ID: 2, UserID: 102, Username: "user2", Message: "message 2", AttachmentIDs: []int64{3, 1}},
 			},
 			wantErr: false,
 		},
@@ -145,7 +260,7 @@ func TestGetMessages(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			messages, err := repo.GetMessages()
+			messages, err := repo.GetMessages(entity.MessageQuery{Limit: 50})
 			assert.Equal(t, tt.wantErr, err != nil)
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -169,6 +284,7 @@ func TestGetMessages(t *testing.T) {
 							UserID:    msg.UserID,
 							Username: msg.Username,
 							Message:  msg.Message,
+							AttachmentIDs: msg.AttachmentIDs,
 						}
 					}
 					assert.Equal(t, tt.want, actualMessagesWithoutTimestamp)
@@ -223,4 +339,180 @@ func TestMessageRepository_DeleteOldMessages(t *testing.T) {
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
-}
\ No newline at end of file
+}
+func TestMessageRepository_GetMessageByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM chat_messages WHERE id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "seq", "user_id", "username", "message", "timestamp", "attachment_ids"}).
+			AddRow(3, 2, 11, 7, "alice", "spam spam", now, "{5}"))
+
+	msg, err := repo.GetMessageByID(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, msg.UserID)
+	assert.Equal(t, 2, msg.RoomID)
+	assert.Equal(t, int64(11), msg.Seq)
+	assert.Equal(t, []int64{5}, msg.AttachmentIDs)
+
+	mock.ExpectQuery(`FROM chat_messages WHERE id = \$1`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetMessageByID(4)
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_GetRoomMessagesAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM chat_messages WHERE room_id = $1 AND seq > $2 ORDER BY seq ASC LIMIT $3")).
+		WithArgs(4, int64(10), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "seq", "user_id", "username", "message", "timestamp", "attachment_ids"}).
+			AddRow(31, 4, 11, 7, "alice", "first", time.Now(), "{}").
+			AddRow(35, 4, 12, 8, "bob", "second", time.Now(), "{}"))
+
+	messages, err := repo.GetRoomMessagesAfter(4, 10, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 2) {
+		assert.Equal(t, int64(11), messages[0].Seq)
+		assert.Equal(t, "second", messages[1].Message)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_GetMessagesCursors(t *testing.T) {
+	columns := []string{"id", "room_id", "seq", "user_id", "username", "message", "timestamp", "attachment_ids"}
+	cursorQuery := regexp.QuoteMeta("SELECT seq FROM chat_messages WHERE id = $1 AND room_id = $2")
+	olderQuery := regexp.QuoteMeta("FROM chat_messages WHERE room_id = $1 AND seq < $2 ORDER BY seq DESC LIMIT $3")
+	newerQuery := regexp.QuoteMeta("FROM chat_messages WHERE room_id = $1 AND seq >= $2 ORDER BY seq ASC LIMIT $3")
+
+	tests := []struct {
+		name    string
+		query   entity.MessageQuery
+		mock    func(mock sqlmock.Sqlmock)
+		wantIDs []int
+		wantErr error
+	}{
+		{
+			name:  "before",
+			query: entity.MessageQuery{RoomID: 4, Before: 40, Limit: 2},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(cursorQuery).WithArgs(40, 4).WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(12))
+				mock.ExpectQuery(olderQuery).WithArgs(4, int64(12), 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(35, 4, 11, 8, "bob", "b", time.Now(), "{}").
+						AddRow(31, 4, 10, 7, "alice", "a", time.Now(), "{}"))
+			},
+			wantIDs: []int{31, 35},
+		},
+		{
+			name:  "after",
+			query: entity.MessageQuery{RoomID: 4, After: 31, Limit: 2},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(cursorQuery).WithArgs(31, 4).WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(10))
+				mock.ExpectQuery(regexp.QuoteMeta("FROM chat_messages WHERE room_id = $1 AND seq > $2 ORDER BY seq ASC LIMIT $3")).
+					WithArgs(4, int64(10), 2).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(35, 4, 11, 8, "bob", "b", time.Now(), "{}"))
+			},
+			wantIDs: []int{35},
+		},
+		{
+			name:  "around fills the page with newer messages",
+			query: entity.MessageQuery{RoomID: 4, Around: 31, Limit: 4},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(cursorQuery).WithArgs(31, 4).WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(2))
+				mock.ExpectQuery(olderQuery).WithArgs(4, int64(2), 2).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(20, 4, 1, 7, "alice", "first", time.Now(), "{}"))
+				mock.ExpectQuery(newerQuery).WithArgs(4, int64(2), 3).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(31, 4, 2, 7, "alice", "a", time.Now(), "{}").
+						AddRow(35, 4, 3, 8, "bob", "b", time.Now(), "{}"))
+			},
+			wantIDs: []int{20, 31, 35},
+		},
+		{
+			name:  "cursor from another room",
+			query: entity.MessageQuery{RoomID: 4, Before: 99, Limit: 2},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(cursorQuery).WithArgs(99, 4).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: ErrMessageNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create mock: %v", err)
+			}
+			defer db.Close()
+			tt.mock(mock)
+
+			messages, err := NewMessageRepository(db).GetMessages(tt.query)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else if assert.NoError(t, err) {
+				ids := make([]int, len(messages))
+				for i, msg := range messages {
+					ids[i] = msg.ID
+				}
+				assert.Equal(t, tt.wantIDs, ids)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMessageRepository_CountMessages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM chat_messages WHERE room_id = $1")).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(120))
+
+	total, err := NewMessageRepository(db).CountMessages(4)
+	assert.NoError(t, err)
+	assert.Equal(t, 120, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_DeleteMessage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM chat_messages WHERE id = $1")).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteMessage(3))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM chat_messages WHERE id = $1")).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteMessage(4), ErrMessageNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
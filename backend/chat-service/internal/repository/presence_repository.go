@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PresenceRepository interface {
+	SetLastSeen(userID int64, at time.Time) error
+	GetLastSeen(userIDs []int64) (map[int64]time.Time, error)
+}
+
+type presenceRepository struct {
+	db *sql.DB
+}
+
+func NewPresenceRepository(db *sql.DB) PresenceRepository {
+	return &presenceRepository{db: db}
+}
+
+// SetLastSeen запоминает, когда пользователь отключился от чата
+func (repo *presenceRepository) SetLastSeen(userID int64, at time.Time) error {
+	_, err := repo.db.Exec(
+		`INSERT INTO chat_user_presence (user_id, last_seen_at) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET last_seen_at = GREATEST(chat_user_presence.last_seen_at, EXCLUDED.last_seen_at)`,
+		userID, at,
+	)
+	if err != nil {
+		return fmt.Errorf("error saving last seen: %w", err)
+	}
+	return nil
+}
+
+// GetLastSeen возвращает время последнего отключения; пользователей, которые
+// ни разу не подключались, в результате нет
+func (repo *presenceRepository) GetLastSeen(userIDs []int64) (map[int64]time.Time, error) {
+	lastSeen := make(map[int64]time.Time, len(userIDs))
+	if len(userIDs) == 0 {
+		return lastSeen, nil
+	}
+
+	rows, err := repo.db.Query("SELECT user_id, last_seen_at FROM chat_user_presence WHERE user_id = ANY($1)", pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error getting last seen: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		var at time.Time
+		if err := rows.Scan(&userID, &at); err != nil {
+			return nil, fmt.Errorf("error scanning last seen: %w", err)
+		}
+		lastSeen[userID] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating last seen: %w", err)
+	}
+	return lastSeen, nil
+}
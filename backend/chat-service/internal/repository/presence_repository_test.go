@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresenceRepository_SetLastSeen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPresenceRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_user_presence (user_id, last_seen_at) VALUES ($1, $2)")).
+		WithArgs(int64(5), now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetLastSeen(5, now))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_user_presence")).
+		WillReturnError(errors.New("database error"))
+	assert.Error(t, repo.SetLastSeen(5, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPresenceRepository_GetLastSeen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPresenceRepository(db)
+	now := time.Now()
+
+	// Пустой список не идет в базу
+	lastSeen, err := repo.GetLastSeen(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, lastSeen)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id, last_seen_at FROM chat_user_presence WHERE user_id = ANY($1)")).
+		WithArgs(pq.Array([]int64{5, 6})).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "last_seen_at"}).AddRow(5, now))
+
+	lastSeen, err = repo.GetLastSeen([]int64{5, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]time.Time{5: now}, lastSeen)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
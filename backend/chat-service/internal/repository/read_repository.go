@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+type ReadRepository interface {
+	// MarkRead сдвигает позицию прочтения до сообщения messageID (0 - до последнего
+	// сообщения комнаты). Возвращает новую позицию и false, если она не сдвинулась.
+	MarkRead(roomID int, userID int64, messageID int) (*entity.ReadPosition, bool, error)
+	GetReadPositions(roomID int) ([]entity.ReadPosition, error)
+	GetUnreadCounts(userID int64, roomIDs []int) ([]entity.UnreadCount, error)
+}
+
+type readRepository struct {
+	db *sql.DB
+}
+
+func NewReadRepository(db *sql.DB) ReadRepository {
+	return &readRepository{db: db}
+}
+
+func (repo *readRepository) MarkRead(roomID int, userID int64, messageID int) (*entity.ReadPosition, bool, error) {
+	pos := &entity.ReadPosition{RoomID: roomID, UserID: userID}
+
+	var row *sql.Row
+	if messageID > 0 {
+		row = repo.db.QueryRow("SELECT id, seq FROM chat_messages WHERE id = $1 AND room_id = $2", messageID, roomID)
+	} else {
+		row = repo.db.QueryRow("SELECT id, seq FROM chat_messages WHERE room_id = $1 ORDER BY seq DESC LIMIT 1", roomID)
+	}
+	err := row.Scan(&pos.LastReadMessageID, &pos.LastReadSeq)
+	if errors.Is(err, sql.ErrNoRows) {
+		if messageID > 0 {
+			return nil, false, ErrMessageNotFound
+		}
+		// В комнате нет сообщений - отмечать нечего
+		return pos, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting read message: %w", err)
+	}
+
+	// Позиция только растет: запрос, пришедший позже с более старым сообщением, ее не сдвигает
+	query := "INSERT INTO chat_read_positions (room_id, user_id, last_read_seq, last_read_message_id) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (room_id, user_id) DO UPDATE SET last_read_seq = EXCLUDED.last_read_seq, " +
+		"last_read_message_id = EXCLUDED.last_read_message_id, read_at = CURRENT_TIMESTAMP " +
+		"WHERE chat_read_positions.last_read_seq < EXCLUDED.last_read_seq " +
+		"RETURNING read_at"
+	err = repo.db.QueryRow(query, roomID, userID, pos.LastReadSeq, pos.LastReadMessageID).Scan(&pos.ReadAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pos, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error saving read position: %w", err)
+	}
+	return pos, true, nil
+}
+
+// GetReadPositions возвращает позиции прочтения всех, кто читал комнату
+func (repo *readRepository) GetReadPositions(roomID int) ([]entity.ReadPosition, error) {
+	rows, err := repo.db.Query(
+		"SELECT room_id, user_id, last_read_seq, last_read_message_id, read_at FROM chat_read_positions WHERE room_id = $1 ORDER BY user_id",
+		roomID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting read positions: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []entity.ReadPosition
+	for rows.Next() {
+		var pos entity.ReadPosition
+		if err := rows.Scan(&pos.RoomID, &pos.UserID, &pos.LastReadSeq, &pos.LastReadMessageID, &pos.ReadAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		positions = append(positions, pos)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return positions, nil
+}
+
+// GetUnreadCounts считает непрочитанные в комнатах. Подсчет идет по индексу (room_id, seq)
+// от позиции прочтения и останавливается на entity.MaxUnreadCount, поэтому не зависит
+// от длины истории.
+func (repo *readRepository) GetUnreadCounts(userID int64, roomIDs []int) ([]entity.UnreadCount, error) {
+	if len(roomIDs) == 0 {
+		return nil, nil
+	}
+	query := "SELECT r.id, COALESCE(p.last_read_seq, 0), r.last_seq, " +
+		"(SELECT COUNT(*) FROM (SELECT 1 FROM chat_messages c WHERE c.room_id = r.id AND c.seq > COALESCE(p.last_read_seq, 0) AND c.user_id <> $1 LIMIT $3) u) " +
+		"FROM chat_rooms r LEFT JOIN chat_read_positions p ON p.room_id = r.id AND p.user_id = $1 " +
+		"WHERE r.id = ANY($2) ORDER BY r.id"
+
+	rows, err := repo.db.Query(query, userID, pq.Array(roomIDs), entity.MaxUnreadCount)
+	if err != nil {
+		return nil, fmt.Errorf("error counting unread messages: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []entity.UnreadCount
+	for rows.Next() {
+		var count entity.UnreadCount
+		if err := rows.Scan(&count.RoomID, &count.LastReadSeq, &count.LastSeq, &count.Unread); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return counts, nil
+}
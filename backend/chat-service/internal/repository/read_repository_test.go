@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRepository_MarkRead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewReadRepository(db)
+	now := time.Now()
+	upsert := regexp.QuoteMeta("INSERT INTO chat_read_positions (room_id, user_id, last_read_seq, last_read_message_id) VALUES ($1, $2, $3, $4)")
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, seq FROM chat_messages WHERE id = $1 AND room_id = $2")).
+		WithArgs(12, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq"}).AddRow(12, 7))
+	mock.ExpectQuery(upsert).
+		WithArgs(3, int64(5), int64(7), 12).
+		WillReturnRows(sqlmock.NewRows([]string{"read_at"}).AddRow(now))
+
+	pos, moved, err := repo.MarkRead(3, 5, 12)
+	assert.NoError(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, &entity.ReadPosition{RoomID: 3, UserID: 5, LastReadSeq: 7, LastReadMessageID: 12, ReadAt: now}, pos)
+
+	// Позиция уже дальше: upsert ничего не возвращает
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, seq FROM chat_messages WHERE room_id = $1 ORDER BY seq DESC LIMIT 1")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq"}).AddRow(12, 7))
+	mock.ExpectQuery(upsert).
+		WillReturnRows(sqlmock.NewRows([]string{"read_at"}))
+	_, moved, err = repo.MarkRead(3, 5, 0)
+	assert.NoError(t, err)
+	assert.False(t, moved)
+
+	// Сообщение из другой комнаты
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, seq FROM chat_messages WHERE id = $1 AND room_id = $2")).
+		WithArgs(40, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq"}))
+	_, _, err = repo.MarkRead(3, 5, 40)
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+
+	// В пустой комнате отмечать нечего
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, seq FROM chat_messages WHERE room_id = $1")).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq"}))
+	_, moved, err = repo.MarkRead(4, 5, 0)
+	assert.NoError(t, err)
+	assert.False(t, moved)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, seq FROM chat_messages")).
+		WillReturnError(errors.New("database error"))
+	_, _, err = repo.MarkRead(3, 5, 12)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadRepository_GetReadPositions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewReadRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM chat_read_positions WHERE room_id = $1 ORDER BY user_id")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"room_id", "user_id", "last_read_seq", "last_read_message_id", "read_at"}).
+			AddRow(3, 5, 7, 12, now).
+			AddRow(3, 7, 2, 4, now))
+
+	positions, err := repo.GetReadPositions(3)
+	assert.NoError(t, err)
+	assert.Len(t, positions, 2)
+	assert.Equal(t, int64(7), positions[1].UserID)
+	assert.Equal(t, 4, positions[1].LastReadMessageID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadRepository_GetUnreadCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewReadRepository(db)
+
+	counts, err := repo.GetUnreadCounts(5, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+
+	mock.ExpectQuery(regexp.QuoteMeta("LEFT JOIN chat_read_positions p ON p.room_id = r.id AND p.user_id = $1 WHERE r.id = ANY($2)")).
+		WithArgs(int64(5), pq.Array([]int{1, 3}), entity.MaxUnreadCount).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_read_seq", "last_seq", "unread"}).
+			AddRow(1, 40, 44, 3).
+			AddRow(3, 0, 0, 0))
+
+	counts, err = repo.GetUnreadCounts(5, []int{1, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.UnreadCount{{RoomID: 1, Unread: 3, LastReadSeq: 40, LastSeq: 44}, {RoomID: 3}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
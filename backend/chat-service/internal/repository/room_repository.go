@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+)
+
+var (
+	ErrRoomNotFound   = errors.New("room not found")
+	ErrMemberNotFound = errors.New("room member not found")
+)
+
+type RoomRepository interface {
+	CreateRoom(room *entity.Room) error
+	GetRoom(id int) (*entity.Room, error)
+	ListRooms(userID int64) ([]entity.Room, error)
+	GetUserRoomIDs(userID int64) ([]int, error)
+	GetMember(roomID int, userID int64) (*entity.RoomMember, error)
+	GetMembers(roomID int) ([]entity.RoomMember, error)
+	AddMember(roomID int, userID int64, role string) error
+	RemoveMember(roomID int, userID int64) error
+	SetMemberRole(roomID int, userID int64, role string) error
+}
+
+type roomRepository struct {
+	db *sql.DB
+}
+
+func NewRoomRepository(db *sql.DB) RoomRepository {
+	return &roomRepository{db: db}
+}
+
+// CreateRoom создает комнату и делает ее создателя владельцем в одной транзакции
+func (repo *roomRepository) CreateRoom(room *entity.Room) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		"INSERT INTO chat_rooms (name, is_private, created_by) VALUES ($1, $2, $3) RETURNING id, created_at",
+		room.Name, room.IsPrivate, room.CreatedBy,
+	).Scan(&room.ID, &room.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating room: %w", err)
+	}
+
+	if _, err := tx.Exec("INSERT INTO chat_room_members (room_id, user_id, role) VALUES ($1, $2, $3)", room.ID, room.CreatedBy, entity.RoomRoleOwner); err != nil {
+		return fmt.Errorf("error adding room owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	room.Kind = entity.RoomKindRoom
+	room.Role = entity.RoomRoleOwner
+	return nil
+}
+
+func (repo *roomRepository) GetRoom(id int) (*entity.Room, error) {
+	var room entity.Room
+	var createdBy sql.NullInt64
+	err := repo.db.QueryRow("SELECT id, name, is_private, kind, created_by, created_at FROM chat_rooms WHERE id = $1", id).
+		Scan(&room.ID, &room.Name, &room.IsPrivate, &room.Kind, &createdBy, &room.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting room: %w", err)
+	}
+	room.CreatedBy = createdBy.Int64
+	return &room, nil
+}
+
+// ListRooms возвращает публичные комнаты и приватные комнаты, в которых состоит пользователь,
+// вместе с его ролью. Личные и групповые переписки сюда не попадают.
+func (repo *roomRepository) ListRooms(userID int64) ([]entity.Room, error) {
+	query := "SELECT r.id, r.name, r.is_private, r.kind, r.created_by, r.created_at, COALESCE(m.role, '') " +
+		"FROM chat_rooms r LEFT JOIN chat_room_members m ON m.room_id = r.id AND m.user_id = $1 " +
+		"WHERE r.kind = 'room' AND (NOT r.is_private OR m.user_id IS NOT NULL) ORDER BY r.id"
+
+	rows, err := repo.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing rooms: %w", err)
+	}
+	defer rows.Close()
+
+	var rooms []entity.Room
+	for rows.Next() {
+		var room entity.Room
+		var createdBy sql.NullInt64
+		if err := rows.Scan(&room.ID, &room.Name, &room.IsPrivate, &room.Kind, &createdBy, &room.CreatedAt, &room.Role); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		room.CreatedBy = createdBy.Int64
+		rooms = append(rooms, room)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return rooms, nil
+}
+
+// GetUserRoomIDs возвращает комнаты, в которых пользователь состоит явно (без общей комнаты)
+func (repo *roomRepository) GetUserRoomIDs(userID int64) ([]int, error) {
+	rows, err := repo.db.Query("SELECT room_id FROM chat_room_members WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user rooms: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (repo *roomRepository) GetMember(roomID int, userID int64) (*entity.RoomMember, error) {
+	var member entity.RoomMember
+	err := repo.db.QueryRow("SELECT room_id, user_id, role, joined_at FROM chat_room_members WHERE room_id = $1 AND user_id = $2", roomID, userID).
+		Scan(&member.RoomID, &member.UserID, &member.Role, &member.JoinedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting room member: %w", err)
+	}
+	return &member, nil
+}
+
+func (repo *roomRepository) GetMembers(roomID int) ([]entity.RoomMember, error) {
+	rows, err := repo.db.Query("SELECT room_id, user_id, role, joined_at FROM chat_room_members WHERE room_id = $1 ORDER BY joined_at", roomID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting room members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []entity.RoomMember
+	for rows.Next() {
+		var member entity.RoomMember
+		if err := rows.Scan(&member.RoomID, &member.UserID, &member.Role, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember добавляет участника; повторное добавление не меняет его роль
+func (repo *roomRepository) AddMember(roomID int, userID int64, role string) error {
+	_, err := repo.db.Exec("INSERT INTO chat_room_members (room_id, user_id, role) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING", roomID, userID, role)
+	if err != nil {
+		return fmt.Errorf("error adding room member: %w", err)
+	}
+	return nil
+}
+
+func (repo *roomRepository) RemoveMember(roomID int, userID int64) error {
+	result, err := repo.db.Exec("DELETE FROM chat_room_members WHERE room_id = $1 AND user_id = $2", roomID, userID)
+	if err != nil {
+		return fmt.Errorf("error removing room member: %w", err)
+	}
+	return memberAffected(result)
+}
+
+func (repo *roomRepository) SetMemberRole(roomID int, userID int64, role string) error {
+	result, err := repo.db.Exec("UPDATE chat_room_members SET role = $3 WHERE room_id = $1 AND user_id = $2", roomID, userID, role)
+	if err != nil {
+		return fmt.Errorf("error updating room member: %w", err)
+	}
+	return memberAffected(result)
+}
+
+// memberAffected возвращает ErrMemberNotFound, если запрос не затронул ни одной строки
+func memberAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
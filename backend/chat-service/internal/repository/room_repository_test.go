@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoomRepository_CreateRoom(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRoomRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO chat_rooms (name, is_private, created_by) VALUES ($1, $2, $3) RETURNING id, created_at")).
+		WithArgs("gophers", true, int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, now))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_room_members (room_id, user_id, role) VALUES ($1, $2, $3)")).
+		WithArgs(2, int64(5), entity.RoomRoleOwner).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	room := &entity.Room{Name: "gophers", IsPrivate: true, CreatedBy: 5}
+	assert.NoError(t, repo.CreateRoom(room))
+	assert.Equal(t, 2, room.ID)
+	assert.Equal(t, entity.RoomRoleOwner, room.Role)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO chat_rooms")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, now))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO chat_room_members")).
+		WillReturnError(errors.New("database error"))
+	mock.ExpectRollback()
+
+	assert.Error(t, repo.CreateRoom(&entity.Room{Name: "broken", CreatedBy: 5}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoomRepository_ListRooms(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRoomRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE r.kind = 'room' AND (NOT r.is_private OR m.user_id IS NOT NULL) ORDER BY r.id")).
+		WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_private", "kind", "created_by", "created_at", "role"}).
+			AddRow(1, "general", false, "room", nil, now, "").
+			AddRow(2, "gophers", true, "room", 5, now, "owner"))
+
+	rooms, err := repo.ListRooms(5)
+	assert.NoError(t, err)
+	assert.Len(t, rooms, 2)
+	assert.Equal(t, int64(0), rooms[0].CreatedBy)
+	assert.Equal(t, "owner", rooms[1].Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoomRepository_Members(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewRoomRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM chat_room_members WHERE room_id = $1 AND user_id = $2")).
+		WithArgs(2, int64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"room_id"}))
+	_, err = repo.GetMember(2, 9)
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM chat_room_members WHERE room_id = $1 AND user_id = $2")).
+		WithArgs(2, int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.RemoveMember(2, 9), ErrMemberNotFound)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE chat_room_members SET role = $3 WHERE room_id = $1 AND user_id = $2")).
+		WithArgs(2, int64(3), entity.RoomRoleAdmin).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetMemberRole(2, 3, entity.RoomRoleAdmin))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
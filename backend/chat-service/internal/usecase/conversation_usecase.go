@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userLookupTimeout ограничивает ожидание Auth Service при проверке участников переписки
+const userLookupTimeout = 2 * time.Second
+
+// ErrInvalidConversation возвращается при некорректном составе или названии переписки
+var ErrInvalidConversation = errors.New("invalid conversation")
+
+type ConversationUseCase interface {
+	CreateConversation(userID int64, participantIDs []int64, name string) (*entity.Conversation, error)
+	ListConversations(userID int64) ([]entity.Conversation, error)
+}
+
+type conversationUseCase struct {
+	repo       repository.ConversationRepository
+	authClient pb.AuthServiceClient
+}
+
+// NewConversationUseCase создает usecase переписок. authClient нужен, чтобы проверить
+// существование собеседников; при nil проверка не выполняется.
+func NewConversationUseCase(repo repository.ConversationRepository, authClient pb.AuthServiceClient) ConversationUseCase {
+	return &conversationUseCase{repo: repo, authClient: authClient}
+}
+
+// CreateConversation создает личную переписку, если указан один собеседник, и групповую,
+// если несколько. Для пары пользователей существует только одна личная переписка.
+func (uc *conversationUseCase) CreateConversation(userID int64, participantIDs []int64, name string) (*entity.Conversation, error) {
+	others := cleanParticipants(userID, participantIDs)
+	if len(others) == 0 || len(others)+1 > entity.MaxConversationParticipants {
+		return nil, ErrInvalidConversation
+	}
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > entity.MaxRoomNameLength {
+		return nil, ErrInvalidConversation
+	}
+	if err := uc.checkUsers(others); err != nil {
+		return nil, err
+	}
+
+	conv := &entity.Conversation{
+		Kind:         entity.RoomKindGroup,
+		Name:         name,
+		Participants: append([]int64{userID}, others...),
+	}
+	directKey := ""
+	if len(others) == 1 {
+		conv.Kind = entity.RoomKindDirect
+		conv.Name = ""
+		directKey = entity.DirectKey(userID, others[0])
+	}
+
+	if err := uc.repo.CreateConversation(conv, userID, directKey); err != nil {
+		return nil, err
+	}
+	return conv, nil
+}
+
+// checkUsers проверяет, что все собеседники существуют
+func (uc *conversationUseCase) checkUsers(userIDs []int64) error {
+	if uc.authClient == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), userLookupTimeout)
+	defer cancel()
+
+	for _, id := range userIDs {
+		_, err := uc.authClient.GetUserProfile(ctx, &pb.GetUserProfileRequest{UserId: id})
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("%w: user %d not found", ErrInvalidConversation, id)
+		}
+		if err != nil {
+			return fmt.Errorf("error checking user %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// cleanParticipants убирает повторы, некорректные ID и самого создателя
+func cleanParticipants(userID int64, ids []int64) []int64 {
+	var cleaned []int64
+	seen := map[int64]bool{userID: true}
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		cleaned = append(cleaned, id)
+	}
+	return cleaned
+}
+
+func (uc *conversationUseCase) ListConversations(userID int64) ([]entity.Conversation, error) {
+	return uc.repo.ListConversations(userID)
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockConversationRepository struct {
+	mock.Mock
+}
+
+func (m *MockConversationRepository) CreateConversation(conv *entity.Conversation, createdBy int64, directKey string) error {
+	args := m.Called(conv, createdBy, directKey)
+	return args.Error(0)
+}
+
+func (m *MockConversationRepository) ListConversations(userID int64) ([]entity.Conversation, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Conversation), args.Error(1)
+}
+
+// profileAuthClient отвечает на GetUserProfile: известны только пользователи из known
+type profileAuthClient struct {
+	pb.AuthServiceClient
+	known map[int64]bool
+	err   error
+}
+
+func (m *profileAuthClient) GetUserProfile(ctx context.Context, in *pb.GetUserProfileRequest, opts ...grpc.CallOption) (*pb.GetUserProfileResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if !m.known[in.UserId] {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &pb.GetUserProfileResponse{User: &pb.User{Id: in.UserId}}, nil
+}
+
+func TestConversationUseCase_CreateConversation(t *testing.T) {
+	auth := &profileAuthClient{known: map[int64]bool{7: true, 8: true}}
+
+	t.Run("direct conversation", func(t *testing.T) {
+		repo := new(MockConversationRepository)
+		repo.On("CreateConversation", mock.MatchedBy(func(c *entity.Conversation) bool {
+			return c.Kind == entity.RoomKindDirect && c.Name == "" && assert.ObjectsAreEqual([]int64{5, 7}, c.Participants)
+		}), int64(5), "5:7").Return(nil).Once()
+
+		conv, err := NewConversationUseCase(repo, auth).CreateConversation(5, []int64{7, 7, 5}, "ignored")
+		assert.NoError(t, err)
+		assert.Equal(t, entity.RoomKindDirect, conv.Kind)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("group conversation", func(t *testing.T) {
+		repo := new(MockConversationRepository)
+		repo.On("CreateConversation", mock.MatchedBy(func(c *entity.Conversation) bool {
+			return c.Kind == entity.RoomKindGroup && c.Name == "plans" && len(c.Participants) == 3
+		}), int64(5), "").Return(nil).Once()
+
+		_, err := NewConversationUseCase(repo, auth).CreateConversation(5, []int64{7, 8}, " plans ")
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("invalid participants", func(t *testing.T) {
+		repo := new(MockConversationRepository)
+		uc := NewConversationUseCase(repo, auth)
+
+		_, err := uc.CreateConversation(5, []int64{5, 0}, "")
+		assert.ErrorIs(t, err, ErrInvalidConversation)
+
+		_, err = uc.CreateConversation(5, []int64{9}, "")
+		assert.ErrorIs(t, err, ErrInvalidConversation)
+
+		many := make([]int64, entity.MaxConversationParticipants)
+		for i := range many {
+			many[i] = int64(100 + i)
+		}
+		_, err = NewConversationUseCase(repo, nil).CreateConversation(5, many, "")
+		assert.ErrorIs(t, err, ErrInvalidConversation)
+		repo.AssertNotCalled(t, "CreateConversation", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("auth service unavailable", func(t *testing.T) {
+		repo := new(MockConversationRepository)
+		_, err := NewConversationUseCase(repo, &profileAuthClient{err: errors.New("unavailable")}).CreateConversation(5, []int64{7}, "")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidConversation)
+	})
+}
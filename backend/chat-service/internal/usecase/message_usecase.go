@@ -1,34 +1,286 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"time"
 
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/attachments"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/contentfilter"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/mention"
+)
+
+// mentionLookupTimeout ограничивает ожидание Auth Service при разборе упоминаний
+const mentionLookupTimeout = 2 * time.Second
+
+// filterTimeout ограничивает ожидание фильтра контента forum-service
+const filterTimeout = 2 * time.Second
+
+// attachmentTimeout ограничивает ожидание проверки вложений forum-service
+const attachmentTimeout = 2 * time.Second
+
+// Размер страницы истории
+const (
+	DefaultHistoryLimit = 50
+	MaxHistoryLimit     = 100
 )
 
+// ErrMessageRejected возвращается, если фильтр контента не пропустил сообщение
+var ErrMessageRejected = errors.New("message rejected by content filter")
+
+// ErrInvalidAttachments возвращается, если вложения сообщения чужие, не существуют или уже использованы
+var ErrInvalidAttachments = errors.New("attachment not found, belongs to another user or is already used")
+
+// ErrInvalidHistoryQuery возвращается, если в запросе истории несколько курсоров или курсор вместе с offset
+var ErrInvalidHistoryQuery = errors.New("invalid history query")
+
+// ContentFilter проверяет текст сообщения перед сохранением и запоминает сохраненный текст
+// для поиска дубликатов
+type ContentFilter interface {
+	Check(ctx context.Context, userID int64, text string) (*contentfilter.Verdict, error)
+	Record(ctx context.Context, userID int64, text string) error
+}
+
+// AttachmentClaimer проверяет владельца вложений в forum-service и занимает их за сообщением
+type AttachmentClaimer interface {
+	Claim(ctx context.Context, ownerID, roomID int64, ids []int64) ([]int64, error)
+}
+
 type MessageUseCase interface {
 	SaveMessage(msg *entity.Message) error
-	GetMessages() ([]entity.Message, error)
+	// GetMessages возвращает страницу истории комнаты и общее число ее сообщений
+	GetMessages(q entity.MessageQuery) (*entity.MessagePage, error)
+	// GetRoomMessagesAfter возвращает до limit сообщений комнаты с seq больше afterSeq
+	GetRoomMessagesAfter(roomID int, afterSeq int64, limit int) ([]entity.Message, error)
+	GetMessage(id int) (*entity.Message, error)
+	DeleteMessage(id int) error
 	DeleteOldMessages(before time.Time) error
 }
 
 type messageUseCase struct {
-	repo repository.MessageRepository
+	repo        repository.MessageRepository
+	authClient  pb.AuthServiceClient
+	filter      ContentFilter
+	attachments AttachmentClaimer
 }
 
-func NewMessageUseCase(repo repository.MessageRepository) MessageUseCase {
-	return &messageUseCase{repo: repo}
+// NewMessageUseCase создает usecase сообщений. authClient нужен для разбора @упоминаний;
+// при nil упоминания не отслеживаются. filter проверяет сообщения фильтром контента;
+// при nil сообщения не проверяются. attachments проверяет и занимает вложения; при nil
+// вложения отбрасываются, так как проверить их владельца нечем.
+func NewMessageUseCase(repo repository.MessageRepository, authClient pb.AuthServiceClient, filter ContentFilter, attachments AttachmentClaimer) MessageUseCase {
+	return &messageUseCase{repo: repo, authClient: authClient, filter: filter, attachments: attachments}
 }
 
+// SaveMessage сохраняет сообщение. Повтор с уже сохраненным ClientMsgID не проходит фильтр
+// и не сохраняется снова: msg заполняется прежним сообщением, возвращается
+// repository.ErrDuplicateMessage.
 func (uc *messageUseCase) SaveMessage(msg *entity.Message) error {
-	return uc.repo.SaveMessage(msg)
+	if msg.ClientMsgID != "" {
+		existing, err := uc.repo.GetMessageByClientID(msg.UserID, msg.ClientMsgID)
+		if err == nil {
+			*msg = *existing
+			return repository.ErrDuplicateMessage
+		}
+		if !errors.Is(err, repository.ErrMessageNotFound) {
+			return err
+		}
+	}
+	// Отпечаток считается по исходному тексту, как и при проверке
+	text := msg.Message
+	if err := uc.checkContent(msg); err != nil {
+		return err
+	}
+	if err := uc.claimAttachments(msg); err != nil {
+		return err
+	}
+	msg.Mentions = uc.resolveMentions(msg)
+	if err := uc.repo.SaveMessage(msg); err != nil {
+		return err
+	}
+	uc.recordContent(msg.UserID, text)
+	return nil
+}
+
+// claimAttachments занимает вложения сообщения в forum-service до сохранения: форум
+// проверяет, что файлы принадлежат автору и еще нигде не использованы. Если сообщение
+// затем не сохранится, файлы останутся занятыми, но владелец может их удалить.
+func (uc *messageUseCase) claimAttachments(msg *entity.Message) error {
+	ids := cleanAttachmentIDs(msg.AttachmentIDs)
+	if len(ids) == 0 || uc.attachments == nil {
+		msg.AttachmentIDs = nil
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), attachmentTimeout)
+	defer cancel()
+
+	claimed, err := uc.attachments.Claim(ctx, int64(msg.UserID), int64(msg.RoomID), ids)
+	if errors.Is(err, attachments.ErrInvalid) {
+		return ErrInvalidAttachments
+	}
+	if err != nil {
+		return err
+	}
+	msg.AttachmentIDs = claimed
+	return nil
 }
 
-func (uc *messageUseCase) GetMessages() ([]entity.Message, error) {
-	return uc.repo.GetMessages()
+// checkContent прогоняет сообщение через фильтр и маскирует запрещенные слова. В чате нет
+// очереди проверки, поэтому отложенное фильтром сообщение отклоняется. Если фильтр недоступен,
+// сообщение публикуется без проверки, чтобы сбой форума не останавливал чат.
+func (uc *messageUseCase) checkContent(msg *entity.Message) error {
+	if uc.filter == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), filterTimeout)
+	defer cancel()
+
+	verdict, err := uc.filter.Check(ctx, int64(msg.UserID), msg.Message)
+	if err != nil {
+		log.Printf("Content filter is unavailable, message from user %d is not checked: %v", msg.UserID, err)
+		return nil
+	}
+
+	switch verdict.Action {
+	case contentfilter.ActionReject, contentfilter.ActionHold:
+		if verdict.Reason == "" {
+			return ErrMessageRejected
+		}
+		return fmt.Errorf("%w: %s", ErrMessageRejected, verdict.Reason)
+	case contentfilter.ActionMask:
+		msg.Message = verdict.Text
+	}
+	return nil
+}
+
+// recordContent сообщает фильтру о сохраненном сообщении. Сбой только логируется: без
+// отпечатка форум лишь не найдет повтор этого сообщения.
+func (uc *messageUseCase) recordContent(userID int, text string) {
+	if uc.filter == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), filterTimeout)
+	defer cancel()
+
+	if err := uc.filter.Record(ctx, int64(userID), text); err != nil {
+		log.Printf("Failed to record message from user %d in content filter: %v", userID, err)
+	}
+}
+
+// resolveMentions возвращает ID существующих пользователей, упомянутых в сообщении.
+// Сбой Auth Service не мешает отправке: сообщение сохраняется без упоминаний.
+func (uc *messageUseCase) resolveMentions(msg *entity.Message) []int64 {
+	if uc.authClient == nil {
+		return nil
+	}
+	names := mention.Parse(msg.Message)
+	if len(names) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mentionLookupTimeout)
+	defer cancel()
+
+	resp, err := uc.authClient.GetUsersByUsernames(ctx, &pb.GetUsersByUsernamesRequest{Usernames: names})
+	if err != nil {
+		log.Printf("Failed to resolve mentions for user %d: %v", msg.UserID, err)
+		return nil
+	}
+
+	var ids []int64
+	for _, user := range resp.GetUsers() {
+		if user.GetId() == int64(msg.UserID) {
+			continue
+		}
+		ids = append(ids, user.GetId())
+	}
+	return ids
+}
+
+// cleanAttachmentIDs убирает повторы и некорректные ID вложений и ограничивает их число.
+// Сами вложения хранит forum-service, чат лишь ссылается на них.
+func cleanAttachmentIDs(ids []int64) []int64 {
+	var cleaned []int64
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		cleaned = append(cleaned, id)
+		if len(cleaned) == entity.MaxMessageAttachments {
+			break
+		}
+	}
+	return cleaned
+}
+
+// GetMessages проверяет запрос истории: ограничивает Limit, отрицательный Offset
+// считает нулевым. HTTP и gRPC получают одну и ту же страницу.
+func (uc *messageUseCase) GetMessages(q entity.MessageQuery) (*entity.MessagePage, error) {
+	if q.RoomID == 0 {
+		q.RoomID = entity.GeneralRoomID
+	}
+	cursors := 0
+	for _, cursor := range []int{q.Before, q.After, q.Around} {
+		if cursor < 0 {
+			return nil, fmt.Errorf("%w: cursor must be a message id", ErrInvalidHistoryQuery)
+		}
+		if cursor > 0 {
+			cursors++
+		}
+	}
+	if cursors > 1 {
+		return nil, fmt.Errorf("%w: only one of before, after and around can be set", ErrInvalidHistoryQuery)
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if cursors > 0 && q.Offset > 0 {
+		return nil, fmt.Errorf("%w: offset cannot be combined with a cursor", ErrInvalidHistoryQuery)
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultHistoryLimit
+	}
+	if q.Limit > MaxHistoryLimit {
+		q.Limit = MaxHistoryLimit
+	}
+
+	messages, err := uc.repo.GetMessages(q)
+	if err != nil {
+		return nil, err
+	}
+	total, err := uc.repo.CountMessages(q.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if messages == nil {
+		messages = []entity.Message{}
+	}
+	return &entity.MessagePage{Messages: messages, Total: total}, nil
+}
+
+func (uc *messageUseCase) GetRoomMessagesAfter(roomID int, afterSeq int64, limit int) ([]entity.Message, error) {
+	return uc.repo.GetRoomMessagesAfter(roomID, afterSeq, limit)
+}
+
+func (uc *messageUseCase) GetMessage(id int) (*entity.Message, error) {
+	return uc.repo.GetMessageByID(id)
+}
+
+func (uc *messageUseCase) DeleteMessage(id int) error {
+	return uc.repo.DeleteMessage(id)
 }
 
 func (uc *messageUseCase) DeleteOldMessages(before time.Time) error {
 	return uc.repo.DeleteOldMessages(before)
-}
\ No newline at end of file
+}
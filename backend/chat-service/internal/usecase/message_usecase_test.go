@@ -1,14 +1,21 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/attachments"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/contentfilter"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
 )
 
 type MockMessageRepository struct {
@@ -20,14 +27,48 @@ func (m *MockMessageRepository) SaveMessage(msg *entity.Message) error {
 	return args.Error(0)
 }
 
-func (m *MockMessageRepository) GetMessages() ([]entity.Message, error) {
-	args := m.Called()
+func (m *MockMessageRepository) GetMessages(q entity.MessageQuery) ([]entity.Message, error) {
+	args := m.Called(q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) CountMessages(roomID int) (int, error) {
+	args := m.Called(roomID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetRoomMessagesAfter(roomID int, afterSeq int64, limit int) ([]entity.Message, error) {
+	args := m.Called(roomID, afterSeq, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]entity.Message), args.Error(1)
 }
 
+func (m *MockMessageRepository) GetMessageByID(id int) (*entity.Message, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetMessageByClientID(userID int, clientMsgID string) (*entity.Message, error) {
+	args := m.Called(userID, clientMsgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) DeleteMessage(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockMessageRepository) DeleteOldMessages(before time.Time) error {
 	args := m.Called(before)
 	return args.Error(0)
@@ -35,7 +76,7 @@ func (m *MockMessageRepository) DeleteOldMessages(before time.Time) error {
 
 func TestMessageUseCase_SaveMessage(t *testing.T) {
 	mockRepo := new(MockMessageRepository)
-	uc := NewMessageUseCase(mockRepo)
+	uc := NewMessageUseCase(mockRepo, nil, nil, nil)
 
 	msg1 := &entity.Message{UserID: 1, Username: "test", Message: "hello"}
 	mockRepo.On("SaveMessage", msg1).Return(nil)
@@ -49,52 +90,75 @@ func TestMessageUseCase_SaveMessage(t *testing.T) {
 }
 
 func TestMessageUseCase_GetMessages(t *testing.T) {
+	latest := entity.MessageQuery{RoomID: entity.GeneralRoomID, Limit: DefaultHistoryLimit}
 
 	tests := []struct {
-		name    string
+		name      string
+		query     entity.MessageQuery
 		mockSetup func(mockRepo *MockMessageRepository)
-		want    []entity.Message
-		wantErr bool
+		want      *entity.MessagePage
+		wantErr   error
 	}{
 		{
 			name: "successful get messages",
 			mockSetup: func(mockRepo *MockMessageRepository) {
 				expected := []entity.Message{{ID: 1, UserID: 1, Username: "user", Message: "test"}}
-				mockRepo.On("GetMessages").Return(expected, nil)
+				mockRepo.On("GetMessages", latest).Return(expected, nil)
+				mockRepo.On("CountMessages", entity.GeneralRoomID).Return(7, nil)
 			},
-			want:    []entity.Message{{ID: 1, UserID: 1, Username: "user", Message: "test"}},
-			wantErr: false,
+			want: &entity.MessagePage{Messages: []entity.Message{{ID: 1, UserID: 1, Username: "user", Message: "test"}}, Total: 7},
 		},
 		{
 			name: "empty result",
 			mockSetup: func(mockRepo *MockMessageRepository) {
-				mockRepo.On("GetMessages").Return([]entity.Message{}, nil)
+				mockRepo.On("GetMessages", latest).Return(nil, nil)
+				mockRepo.On("CountMessages", entity.GeneralRoomID).Return(0, nil)
 			},
-			want:    []entity.Message{},
-			wantErr: false,
+			want: &entity.MessagePage{Messages: []entity.Message{}},
 		},
-		// Добавьте другие тест-кейсы, например, для ошибки репозитория
 		{
-			name: "repository error",
+			name:  "limit and offset are normalized",
+			query: entity.MessageQuery{RoomID: 4, Limit: 1000, Offset: -5},
 			mockSetup: func(mockRepo *MockMessageRepository) {
-				mockRepo.On("GetMessages").Return(nil, errors.New("db error"))
+				mockRepo.On("GetMessages", entity.MessageQuery{RoomID: 4, Limit: MaxHistoryLimit}).Return([]entity.Message{}, nil)
+				mockRepo.On("CountMessages", 4).Return(0, nil)
 			},
-			want:    nil,
-			wantErr: true,
+			want: &entity.MessagePage{Messages: []entity.Message{}},
+		},
+		{
+			name:      "two cursors",
+			query:     entity.MessageQuery{Before: 10, After: 2},
+			mockSetup: func(mockRepo *MockMessageRepository) {},
+			wantErr:   ErrInvalidHistoryQuery,
+		},
+		{
+			name:      "cursor with offset",
+			query:     entity.MessageQuery{Around: 10, Offset: 20},
+			mockSetup: func(mockRepo *MockMessageRepository) {},
+			wantErr:   ErrInvalidHistoryQuery,
+		},
+		{
+			name:  "cursor not found",
+			query: entity.MessageQuery{Before: 10},
+			mockSetup: func(mockRepo *MockMessageRepository) {
+				mockRepo.On("GetMessages", entity.MessageQuery{RoomID: entity.GeneralRoomID, Before: 10, Limit: DefaultHistoryLimit}).
+					Return(nil, repository.ErrMessageNotFound)
+			},
+			wantErr: repository.ErrMessageNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockMessageRepository) // Создаем новый мок для каждого подтеста
-			uc := NewMessageUseCase(mockRepo)
+			uc := NewMessageUseCase(mockRepo, nil, nil, nil)
 
 			tt.mockSetup(mockRepo)
 
-			result, err := uc.GetMessages()
+			result, err := uc.GetMessages(tt.query)
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
@@ -133,7 +197,7 @@ func TestMessageUseCase_DeleteOldMessages(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockMessageRepository)
-			uc := NewMessageUseCase(mockRepo)
+			uc := NewMessageUseCase(mockRepo, nil, nil, nil)
 
 			tt.mockSetup(mockRepo)
 
@@ -144,3 +208,190 @@ func TestMessageUseCase_DeleteOldMessages(t *testing.T) {
 		})
 	}
 }
+
+type mockAuthClient struct {
+	pb.AuthServiceClient
+	users     []*pb.User
+	err       error
+	requested []string
+}
+
+func (m *mockAuthClient) GetUsersByUsernames(ctx context.Context, in *pb.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*pb.GetUsersByUsernamesResponse, error) {
+	m.requested = in.Usernames
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.GetUsersByUsernamesResponse{Users: m.users}, nil
+}
+
+func TestMessageUseCase_SaveMessage_Mentions(t *testing.T) {
+	t.Run("mentions are resolved", func(t *testing.T) {
+		mockRepo := new(MockMessageRepository)
+		auth := &mockAuthClient{users: []*pb.User{{Id: 1, Username: "myself"}, {Id: 7, Username: "alice"}}}
+		uc := NewMessageUseCase(mockRepo, auth, nil, nil)
+
+		msg := &entity.Message{UserID: 1, Username: "myself", Message: "hey @alice and @myself, user@mail.com"}
+		mockRepo.On("SaveMessage", msg).Return(nil)
+
+		assert.NoError(t, uc.SaveMessage(msg))
+		assert.Equal(t, []string{"alice", "myself"}, auth.requested)
+		assert.Equal(t, []int64{7}, msg.Mentions)
+	})
+
+	t.Run("auth failure keeps message", func(t *testing.T) {
+		mockRepo := new(MockMessageRepository)
+		uc := NewMessageUseCase(mockRepo, &mockAuthClient{err: errors.New("unavailable")}, nil, nil)
+
+		msg := &entity.Message{UserID: 1, Message: "hey @alice"}
+		mockRepo.On("SaveMessage", msg).Return(nil)
+
+		assert.NoError(t, uc.SaveMessage(msg))
+		assert.Empty(t, msg.Mentions)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+type mockAttachmentClaimer struct {
+	ownerID int64
+	roomID  int64
+	ids     []int64
+	err     error
+}
+
+func (m *mockAttachmentClaimer) Claim(ctx context.Context, ownerID, roomID int64, ids []int64) ([]int64, error) {
+	m.ownerID, m.roomID, m.ids = ownerID, roomID, ids
+	if m.err != nil {
+		return nil, m.err
+	}
+	return ids, nil
+}
+
+func TestMessageUseCase_SaveMessage_Attachments(t *testing.T) {
+	mockRepo := new(MockMessageRepository)
+	claimer := &mockAttachmentClaimer{}
+	uc := NewMessageUseCase(mockRepo, nil, nil, claimer)
+
+	ids := []int64{5, 0, 5, -1, 7}
+	for i := int64(100); i < 120; i++ {
+		ids = append(ids, i)
+	}
+	msg := &entity.Message{UserID: 1, RoomID: 4, Username: "test", Message: "files", AttachmentIDs: ids}
+	mockRepo.On("SaveMessage", msg).Return(nil).Once()
+
+	assert.NoError(t, uc.SaveMessage(msg))
+	assert.Equal(t, int64(1), claimer.ownerID)
+	assert.Equal(t, int64(4), claimer.roomID)
+	assert.Len(t, claimer.ids, entity.MaxMessageAttachments)
+	assert.Len(t, msg.AttachmentIDs, entity.MaxMessageAttachments)
+	assert.Equal(t, []int64{5, 7, 100}, msg.AttachmentIDs[:3])
+
+	// Чужие или уже использованные вложения отклоняют сообщение целиком
+	claimer.err = attachments.ErrInvalid
+	err := uc.SaveMessage(&entity.Message{UserID: 1, Message: "files", AttachmentIDs: []int64{9}})
+	assert.ErrorIs(t, err, ErrInvalidAttachments)
+
+	// Без клиента форума вложения не принимаются
+	msg = &entity.Message{UserID: 1, Message: "files", AttachmentIDs: []int64{9}}
+	mockRepo.On("SaveMessage", msg).Return(nil).Once()
+	assert.NoError(t, NewMessageUseCase(mockRepo, nil, nil, nil).SaveMessage(msg))
+	assert.Nil(t, msg.AttachmentIDs)
+	mockRepo.AssertExpectations(t)
+}
+
+type mockContentFilter struct {
+	verdict  *contentfilter.Verdict
+	err      error
+	recorded []string
+}
+
+func (m *mockContentFilter) Check(ctx context.Context, userID int64, text string) (*contentfilter.Verdict, error) {
+	return m.verdict, m.err
+}
+
+func (m *mockContentFilter) Record(ctx context.Context, userID int64, text string) error {
+	m.recorded = append(m.recorded, text)
+	return m.err
+}
+
+func TestMessageUseCase_SaveMessage_ContentFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   *mockContentFilter
+		wantErr  bool
+		wantText string
+	}{
+		{"allowed", &mockContentFilter{verdict: &contentfilter.Verdict{Text: "hello casino"}}, false, "hello casino"},
+		{"masked", &mockContentFilter{verdict: &contentfilter.Verdict{Action: contentfilter.ActionMask, Text: "hello ******"}}, false, "hello ******"},
+		{"rejected", &mockContentFilter{verdict: &contentfilter.Verdict{Action: contentfilter.ActionReject, Reason: "banned word"}}, true, ""},
+		{"held is rejected", &mockContentFilter{verdict: &contentfilter.Verdict{Action: contentfilter.ActionHold, Reason: "looks like spam"}}, true, ""},
+		{"filter unavailable", &mockContentFilter{err: errors.New("connection refused")}, false, "hello casino"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockMessageRepository)
+			uc := NewMessageUseCase(mockRepo, nil, tt.filter, nil)
+
+			msg := &entity.Message{UserID: 1, Username: "test", Message: "hello casino"}
+			if !tt.wantErr {
+				mockRepo.On("SaveMessage", msg).Return(nil)
+			}
+
+			err := uc.SaveMessage(msg)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrMessageRejected)
+				mockRepo.AssertNotCalled(t, "SaveMessage", mock.Anything)
+				assert.Empty(t, tt.filter.recorded)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantText, msg.Message)
+			// Сохраненное сообщение запоминается по исходному тексту
+			assert.Equal(t, []string{"hello casino"}, tt.filter.recorded)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageUseCase_SaveMessage_IdempotencyKey(t *testing.T) {
+	saved := &entity.Message{ID: 42, RoomID: 1, Seq: 5, UserID: 1, Username: "test", Message: "hello", ClientMsgID: "key-1"}
+
+	t.Run("first send", func(t *testing.T) {
+		mockRepo := new(MockMessageRepository)
+		filter := &mockContentFilter{verdict: &contentfilter.Verdict{Text: "hello"}}
+		uc := NewMessageUseCase(mockRepo, nil, filter, nil)
+
+		msg := &entity.Message{UserID: 1, Username: "test", Message: "hello", ClientMsgID: "key-1"}
+		mockRepo.On("GetMessageByClientID", 1, "key-1").Return(nil, repository.ErrMessageNotFound)
+		mockRepo.On("SaveMessage", msg).Return(nil)
+
+		assert.NoError(t, uc.SaveMessage(msg))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		mockRepo := new(MockMessageRepository)
+		// Повтор не проверяется фильтром второй раз, иначе был бы отклонен
+		filter := &mockContentFilter{verdict: &contentfilter.Verdict{Action: contentfilter.ActionReject}}
+		uc := NewMessageUseCase(mockRepo, nil, filter, nil)
+
+		msg := &entity.Message{UserID: 1, Username: "test", Message: "hello", ClientMsgID: "key-1"}
+		mockRepo.On("GetMessageByClientID", 1, "key-1").Return(saved, nil)
+
+		err := uc.SaveMessage(msg)
+		assert.ErrorIs(t, err, repository.ErrDuplicateMessage)
+		assert.Equal(t, saved, msg)
+		mockRepo.AssertNotCalled(t, "SaveMessage", mock.Anything)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		mockRepo := new(MockMessageRepository)
+		uc := NewMessageUseCase(mockRepo, nil, nil, nil)
+
+		msg := &entity.Message{UserID: 1, Message: "hello", ClientMsgID: "key-1"}
+		mockRepo.On("GetMessageByClientID", 1, "key-1").Return(nil, errors.New("db error"))
+
+		assert.EqualError(t, uc.SaveMessage(msg), "db error")
+		mockRepo.AssertNotCalled(t, "SaveMessage", mock.Anything)
+	})
+}
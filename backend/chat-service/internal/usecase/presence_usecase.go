@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+)
+
+// ErrInvalidPresenceQuery возвращается, если список пользователей пуст или слишком длинный
+var ErrInvalidPresenceQuery = errors.New("invalid presence query")
+
+type PresenceUseCase interface {
+	RecordLastSeen(userID int64) error
+	GetPresence(userIDs []int64, statuses map[int64]string) ([]entity.UserPresence, error)
+}
+
+type presenceUseCase struct {
+	repo repository.PresenceRepository
+}
+
+func NewPresenceUseCase(repo repository.PresenceRepository) PresenceUseCase {
+	return &presenceUseCase{repo: repo}
+}
+
+// RecordLastSeen сохраняет момент отключения пользователя
+func (uc *presenceUseCase) RecordLastSeen(userID int64) error {
+	return uc.repo.SetLastSeen(userID, time.Now().UTC())
+}
+
+// GetPresence дополняет статусы из хаба временем последнего визита. Пользователи,
+// которых нет в statuses, считаются offline. Повторы в userIDs отбрасываются.
+func (uc *presenceUseCase) GetPresence(userIDs []int64, statuses map[int64]string) ([]entity.UserPresence, error) {
+	if len(userIDs) == 0 || len(userIDs) > entity.MaxPresenceUsers {
+		return nil, ErrInvalidPresenceQuery
+	}
+
+	result := make([]entity.UserPresence, 0, len(userIDs))
+	seen := make(map[int64]bool, len(userIDs))
+	var offline []int64
+	for _, userID := range userIDs {
+		if userID <= 0 {
+			return nil, ErrInvalidPresenceQuery
+		}
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		status := statuses[userID]
+		if status == "" {
+			status = entity.PresenceOffline
+		}
+		if status == entity.PresenceOffline {
+			offline = append(offline, userID)
+		}
+		result = append(result, entity.UserPresence{UserID: userID, Status: status})
+	}
+
+	lastSeen, err := uc.repo.GetLastSeen(offline)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		if at, ok := lastSeen[result[i].UserID]; ok && result[i].Status == entity.PresenceOffline {
+			result[i].LastSeen = &at
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPresenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockPresenceRepository) SetLastSeen(userID int64, at time.Time) error {
+	args := m.Called(userID, at)
+	return args.Error(0)
+}
+
+func (m *MockPresenceRepository) GetLastSeen(userIDs []int64) (map[int64]time.Time, error) {
+	args := m.Called(userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]time.Time), args.Error(1)
+}
+
+func TestPresenceUseCase_GetPresence(t *testing.T) {
+	repo := new(MockPresenceRepository)
+	uc := NewPresenceUseCase(repo)
+	seen := time.Date(2023, 10, 27, 10, 0, 0, 0, time.UTC)
+
+	// Время последнего визита запрашивается только для offline пользователей
+	repo.On("GetLastSeen", []int64{3, 4}).Return(map[int64]time.Time{3: seen}, nil).Once()
+	presence, err := uc.GetPresence([]int64{1, 2, 3, 1, 4}, map[int64]string{1: entity.PresenceOnline, 2: entity.PresenceAway})
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.UserPresence{
+		{UserID: 1, Status: entity.PresenceOnline},
+		{UserID: 2, Status: entity.PresenceAway},
+		{UserID: 3, Status: entity.PresenceOffline, LastSeen: &seen},
+		{UserID: 4, Status: entity.PresenceOffline},
+	}, presence)
+
+	repo.On("GetLastSeen", []int64{5}).Return(nil, errors.New("database error")).Once()
+	_, err = uc.GetPresence([]int64{5}, nil)
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestPresenceUseCase_GetPresence_InvalidQuery(t *testing.T) {
+	uc := NewPresenceUseCase(new(MockPresenceRepository))
+
+	tooMany := make([]int64, entity.MaxPresenceUsers+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+	for _, userIDs := range [][]int64{nil, {0}, {1, -2}, tooMany} {
+		_, err := uc.GetPresence(userIDs, nil)
+		assert.ErrorIs(t, err, ErrInvalidPresenceQuery)
+	}
+}
+
+func TestPresenceUseCase_RecordLastSeen(t *testing.T) {
+	repo := new(MockPresenceRepository)
+	uc := NewPresenceUseCase(repo)
+
+	repo.On("SetLastSeen", int64(7), mock.MatchedBy(func(at time.Time) bool {
+		return time.Since(at) < time.Minute
+	})).Return(nil)
+	assert.NoError(t, uc.RecordLastSeen(7))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+)
+
+// ErrInvalidReadPosition возвращается при отрицательном ID прочитанного сообщения
+var ErrInvalidReadPosition = errors.New("invalid read position")
+
+type ReadUseCase interface {
+	MarkRead(roomID int, userID int64, messageID int) (*entity.ReadPosition, bool, error)
+	ReadPositions(roomID int) ([]entity.ReadPosition, error)
+	UnreadCounts(userID int64) ([]entity.UnreadCount, error)
+	RoomUnreadCount(roomID int, userID int64) (entity.UnreadCount, error)
+}
+
+type readUseCase struct {
+	repo  repository.ReadRepository
+	rooms repository.RoomRepository
+}
+
+func NewReadUseCase(repo repository.ReadRepository, rooms repository.RoomRepository) ReadUseCase {
+	return &readUseCase{repo: repo, rooms: rooms}
+}
+
+// MarkRead сдвигает позицию прочтения вперед до messageID (0 - до последнего сообщения).
+// Второй результат false, если позиция не изменилась. Доступ к комнате проверяет
+// вызывающий код.
+func (uc *readUseCase) MarkRead(roomID int, userID int64, messageID int) (*entity.ReadPosition, bool, error) {
+	if messageID < 0 {
+		return nil, false, ErrInvalidReadPosition
+	}
+	return uc.repo.MarkRead(roomID, userID, messageID)
+}
+
+func (uc *readUseCase) ReadPositions(roomID int) ([]entity.ReadPosition, error) {
+	return uc.repo.GetReadPositions(roomID)
+}
+
+// UnreadCounts возвращает счетчики общей комнаты и всех комнат и переписок пользователя
+func (uc *readUseCase) UnreadCounts(userID int64) ([]entity.UnreadCount, error) {
+	roomIDs, err := uc.rooms.GetUserRoomIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	roomIDs = append([]int{entity.GeneralRoomID}, roomIDs...)
+
+	counts, err := uc.repo.GetUnreadCounts(userID, roomIDs)
+	if err != nil {
+		return nil, err
+	}
+	if counts == nil {
+		counts = []entity.UnreadCount{}
+	}
+	return counts, nil
+}
+
+// RoomUnreadCount возвращает счетчик одной комнаты
+func (uc *readUseCase) RoomUnreadCount(roomID int, userID int64) (entity.UnreadCount, error) {
+	counts, err := uc.repo.GetUnreadCounts(userID, []int{roomID})
+	if err != nil {
+		return entity.UnreadCount{}, err
+	}
+	if len(counts) == 0 {
+		return entity.UnreadCount{}, repository.ErrRoomNotFound
+	}
+	return counts[0], nil
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReadRepository struct {
+	mock.Mock
+}
+
+func (m *MockReadRepository) MarkRead(roomID int, userID int64, messageID int) (*entity.ReadPosition, bool, error) {
+	args := m.Called(roomID, userID, messageID)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*entity.ReadPosition), args.Bool(1), args.Error(2)
+}
+
+func (m *MockReadRepository) GetReadPositions(roomID int) ([]entity.ReadPosition, error) {
+	args := m.Called(roomID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ReadPosition), args.Error(1)
+}
+
+func (m *MockReadRepository) GetUnreadCounts(userID int64, roomIDs []int) ([]entity.UnreadCount, error) {
+	args := m.Called(userID, roomIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UnreadCount), args.Error(1)
+}
+
+func TestReadUseCase_MarkRead(t *testing.T) {
+	repo := new(MockReadRepository)
+	uc := NewReadUseCase(repo, new(MockRoomRepository))
+
+	pos := &entity.ReadPosition{RoomID: 3, UserID: 5, LastReadSeq: 7, LastReadMessageID: 12}
+	repo.On("MarkRead", 3, int64(5), 12).Return(pos, true, nil).Once()
+	repo.On("MarkRead", 3, int64(5), 99).Return(nil, false, repository.ErrMessageNotFound).Once()
+
+	got, moved, err := uc.MarkRead(3, 5, 12)
+	assert.NoError(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, pos, got)
+
+	_, _, err = uc.MarkRead(3, 5, 99)
+	assert.ErrorIs(t, err, repository.ErrMessageNotFound)
+	_, _, err = uc.MarkRead(3, 5, -1)
+	assert.ErrorIs(t, err, ErrInvalidReadPosition)
+	repo.AssertExpectations(t)
+}
+
+func TestReadUseCase_UnreadCounts(t *testing.T) {
+	repo := new(MockReadRepository)
+	rooms := new(MockRoomRepository)
+	uc := NewReadUseCase(repo, rooms)
+
+	// Общая комната считается всегда, хотя записи о членстве в ней нет
+	rooms.On("GetUserRoomIDs", int64(5)).Return([]int{3, 4}, nil).Once()
+	counts := []entity.UnreadCount{{RoomID: 1, Unread: 2}, {RoomID: 3}, {RoomID: 4, Unread: entity.MaxUnreadCount}}
+	repo.On("GetUnreadCounts", int64(5), []int{1, 3, 4}).Return(counts, nil).Once()
+
+	got, err := uc.UnreadCounts(5)
+	assert.NoError(t, err)
+	assert.Equal(t, counts, got)
+
+	rooms.On("GetUserRoomIDs", int64(6)).Return(nil, errors.New("database error")).Once()
+	_, err = uc.UnreadCounts(6)
+	assert.Error(t, err)
+
+	repo.On("GetUnreadCounts", int64(5), []int{9}).Return(nil, nil).Once()
+	_, err = uc.RoomUnreadCount(9, 5)
+	assert.ErrorIs(t, err, repository.ErrRoomNotFound)
+
+	repo.AssertExpectations(t)
+	rooms.AssertExpectations(t)
+}
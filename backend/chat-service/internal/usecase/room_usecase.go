@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+)
+
+var (
+	// ErrRoomForbidden возвращается, если у пользователя нет прав на действие в комнате
+	ErrRoomForbidden = errors.New("not allowed in this room")
+	// ErrInvalidRoom возвращается при некорректном названии комнаты или роли участника
+	ErrInvalidRoom = errors.New("invalid room data")
+)
+
+type RoomUseCase interface {
+	CreateRoom(userID int64, name string, isPrivate bool) (*entity.Room, error)
+	ListRooms(userID int64) ([]entity.Room, error)
+	UserRoomIDs(userID int64) ([]int, error)
+	CheckAccess(roomID int, userID int64) error
+	JoinRoom(roomID int, userID int64) error
+	LeaveRoom(roomID int, userID int64) error
+	GetMembers(roomID int, userID int64) ([]entity.RoomMember, error)
+	InviteMember(roomID int, actorID, userID int64) error
+	RemoveMember(roomID int, actorID, userID int64) error
+	SetMemberRole(roomID int, actorID, userID int64, role string) error
+}
+
+type roomUseCase struct {
+	repo repository.RoomRepository
+}
+
+func NewRoomUseCase(repo repository.RoomRepository) RoomUseCase {
+	return &roomUseCase{repo: repo}
+}
+
+func (uc *roomUseCase) CreateRoom(userID int64, name string, isPrivate bool) (*entity.Room, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > entity.MaxRoomNameLength {
+		return nil, ErrInvalidRoom
+	}
+
+	room := &entity.Room{Name: name, IsPrivate: isPrivate, CreatedBy: userID}
+	if err := uc.repo.CreateRoom(room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+func (uc *roomUseCase) ListRooms(userID int64) ([]entity.Room, error) {
+	return uc.repo.ListRooms(userID)
+}
+
+func (uc *roomUseCase) UserRoomIDs(userID int64) ([]int, error) {
+	return uc.repo.GetUserRoomIDs(userID)
+}
+
+// CheckAccess проверяет, может ли пользователь читать и писать в комнату.
+// В общей комнате состоят все, в остальных нужно членство.
+func (uc *roomUseCase) CheckAccess(roomID int, userID int64) error {
+	if roomID == entity.GeneralRoomID {
+		return nil
+	}
+	if _, err := uc.repo.GetRoom(roomID); err != nil {
+		return err
+	}
+	_, err := uc.repo.GetMember(roomID, userID)
+	if errors.Is(err, repository.ErrMemberNotFound) {
+		return ErrRoomForbidden
+	}
+	return err
+}
+
+// JoinRoom добавляет пользователя в публичную комнату. В приватную можно попасть только
+// по приглашению; для уже приглашенного участника join просто подтверждает членство.
+func (uc *roomUseCase) JoinRoom(roomID int, userID int64) error {
+	if roomID == entity.GeneralRoomID {
+		return nil
+	}
+	room, err := uc.repo.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.repo.GetMember(roomID, userID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, repository.ErrMemberNotFound) {
+		return err
+	}
+	if room.IsPrivate {
+		return ErrRoomForbidden
+	}
+	return uc.repo.AddMember(roomID, userID, entity.RoomRoleMember)
+}
+
+// LeaveRoom удаляет пользователя из комнаты. Владелец не может покинуть свою комнату,
+// а из общей комнаты и личной переписки выйти нельзя.
+func (uc *roomUseCase) LeaveRoom(roomID int, userID int64) error {
+	if roomID == entity.GeneralRoomID {
+		return ErrRoomForbidden
+	}
+	room, err := uc.repo.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+	member, err := uc.repo.GetMember(roomID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role == entity.RoomRoleOwner || room.Kind == entity.RoomKindDirect {
+		return ErrRoomForbidden
+	}
+	return uc.repo.RemoveMember(roomID, userID)
+}
+
+func (uc *roomUseCase) GetMembers(roomID int, userID int64) ([]entity.RoomMember, error) {
+	if roomID == entity.GeneralRoomID {
+		return nil, ErrRoomForbidden
+	}
+	if err := uc.CheckAccess(roomID, userID); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetMembers(roomID)
+}
+
+// InviteMember добавляет пользователя в комнату. Приглашать могут владелец и администраторы.
+func (uc *roomUseCase) InviteMember(roomID int, actorID, userID int64) error {
+	if _, err := uc.manager(roomID, actorID); err != nil {
+		return err
+	}
+	room, err := uc.repo.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+	// Групповая переписка ограничена тем же числом участников, что и при создании
+	if room.Kind == entity.RoomKindGroup {
+		members, err := uc.repo.GetMembers(roomID)
+		if err != nil {
+			return err
+		}
+		for _, m := range members {
+			if m.UserID == userID {
+				return nil
+			}
+		}
+		if len(members)+1 > entity.MaxConversationParticipants {
+			return ErrInvalidRoom
+		}
+	}
+	return uc.repo.AddMember(roomID, userID, entity.RoomRoleMember)
+}
+
+// RemoveMember исключает участника. Владельца исключить нельзя, администратор может
+// исключать только обычных участников.
+func (uc *roomUseCase) RemoveMember(roomID int, actorID, userID int64) error {
+	actor, err := uc.manager(roomID, actorID)
+	if err != nil {
+		return err
+	}
+	target, err := uc.repo.GetMember(roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !outranks(actor.Role, target.Role) {
+		return ErrRoomForbidden
+	}
+	return uc.repo.RemoveMember(roomID, userID)
+}
+
+// SetMemberRole назначает или снимает администратора. Менять роли может только владелец.
+func (uc *roomUseCase) SetMemberRole(roomID int, actorID, userID int64, role string) error {
+	if role != entity.RoomRoleAdmin && role != entity.RoomRoleMember {
+		return ErrInvalidRoom
+	}
+	actor, err := uc.manager(roomID, actorID)
+	if err != nil {
+		return err
+	}
+	if actor.Role != entity.RoomRoleOwner || actorID == userID {
+		return ErrRoomForbidden
+	}
+	return uc.repo.SetMemberRole(roomID, userID, role)
+}
+
+// manager возвращает участника, если он владелец или администратор комнаты
+func (uc *roomUseCase) manager(roomID int, userID int64) (*entity.RoomMember, error) {
+	if roomID == entity.GeneralRoomID {
+		return nil, ErrRoomForbidden
+	}
+	room, err := uc.repo.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+	// Состав личной переписки не меняется
+	if room.Kind == entity.RoomKindDirect {
+		return nil, ErrRoomForbidden
+	}
+	member, err := uc.repo.GetMember(roomID, userID)
+	if errors.Is(err, repository.ErrMemberNotFound) {
+		return nil, ErrRoomForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	if member.Role != entity.RoomRoleOwner && member.Role != entity.RoomRoleAdmin {
+		return nil, ErrRoomForbidden
+	}
+	return member, nil
+}
+
+// outranks сообщает, может ли участник с ролью actor управлять участником с ролью target
+func outranks(actor, target string) bool {
+	rank := map[string]int{entity.RoomRoleMember: 0, entity.RoomRoleAdmin: 1, entity.RoomRoleOwner: 2}
+	return rank[actor] > rank[target]
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRoomRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoomRepository) CreateRoom(room *entity.Room) error {
+	args := m.Called(room)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) GetRoom(id int) (*entity.Room, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Room), args.Error(1)
+}
+
+func (m *MockRoomRepository) ListRooms(userID int64) ([]entity.Room, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Room), args.Error(1)
+}
+
+func (m *MockRoomRepository) GetUserRoomIDs(userID int64) ([]int, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockRoomRepository) GetMember(roomID int, userID int64) (*entity.RoomMember, error) {
+	args := m.Called(roomID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RoomMember), args.Error(1)
+}
+
+func (m *MockRoomRepository) GetMembers(roomID int) ([]entity.RoomMember, error) {
+	args := m.Called(roomID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.RoomMember), args.Error(1)
+}
+
+func (m *MockRoomRepository) AddMember(roomID int, userID int64, role string) error {
+	args := m.Called(roomID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) RemoveMember(roomID int, userID int64) error {
+	args := m.Called(roomID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) SetMemberRole(roomID int, userID int64, role string) error {
+	args := m.Called(roomID, userID, role)
+	return args.Error(0)
+}
+
+func member(roomID int, userID int64, role string) *entity.RoomMember {
+	return &entity.RoomMember{RoomID: roomID, UserID: userID, Role: role}
+}
+
+func TestRoomUseCase_CreateRoom(t *testing.T) {
+	repo := new(MockRoomRepository)
+	uc := NewRoomUseCase(repo)
+
+	_, err := uc.CreateRoom(1, "   ", false)
+	assert.ErrorIs(t, err, ErrInvalidRoom)
+
+	repo.On("CreateRoom", mock.MatchedBy(func(r *entity.Room) bool {
+		return r.Name == "gophers" && r.IsPrivate && r.CreatedBy == 1
+	})).Return(nil).Once()
+	room, err := uc.CreateRoom(1, " gophers ", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "gophers", room.Name)
+	repo.AssertExpectations(t)
+}
+
+func TestRoomUseCase_JoinRoom(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(repo *MockRoomRepository)
+		roomID  int
+		wantErr error
+	}{
+		{
+			name:   "general room is always open",
+			setup:  func(repo *MockRoomRepository) {},
+			roomID: entity.GeneralRoomID,
+		},
+		{
+			name: "public room adds member",
+			setup: func(repo *MockRoomRepository) {
+				repo.On("GetRoom", 2).Return(&entity.Room{ID: 2}, nil)
+				repo.On("GetMember", 2, int64(5)).Return(nil, repository.ErrMemberNotFound)
+				repo.On("AddMember", 2, int64(5), entity.RoomRoleMember).Return(nil).Once()
+			},
+			roomID: 2,
+		},
+		{
+			name: "private room needs invitation",
+			setup: func(repo *MockRoomRepository) {
+				repo.On("GetRoom", 3).Return(&entity.Room{ID: 3, IsPrivate: true}, nil)
+				repo.On("GetMember", 3, int64(5)).Return(nil, repository.ErrMemberNotFound)
+			},
+			roomID:  3,
+			wantErr: ErrRoomForbidden,
+		},
+		{
+			name: "invited member joins private room",
+			setup: func(repo *MockRoomRepository) {
+				repo.On("GetRoom", 3).Return(&entity.Room{ID: 3, IsPrivate: true}, nil)
+				repo.On("GetMember", 3, int64(5)).Return(member(3, 5, entity.RoomRoleMember), nil)
+			},
+			roomID: 3,
+		},
+		{
+			name: "unknown room",
+			setup: func(repo *MockRoomRepository) {
+				repo.On("GetRoom", 9).Return(nil, repository.ErrRoomNotFound)
+			},
+			roomID:  9,
+			wantErr: repository.ErrRoomNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRoomRepository)
+			tt.setup(repo)
+			uc := NewRoomUseCase(repo)
+
+			err := uc.JoinRoom(tt.roomID, 5)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoomUseCase_LeaveRoom(t *testing.T) {
+	repo := new(MockRoomRepository)
+	uc := NewRoomUseCase(repo)
+
+	assert.ErrorIs(t, uc.LeaveRoom(entity.GeneralRoomID, 5), ErrRoomForbidden)
+
+	repo.On("GetRoom", 2).Return(&entity.Room{ID: 2, Kind: entity.RoomKindRoom}, nil)
+
+	repo.On("GetMember", 2, int64(1)).Return(member(2, 1, entity.RoomRoleOwner), nil)
+	assert.ErrorIs(t, uc.LeaveRoom(2, 1), ErrRoomForbidden)
+
+	repo.On("GetMember", 2, int64(5)).Return(member(2, 5, entity.RoomRoleMember), nil)
+	repo.On("RemoveMember", 2, int64(5)).Return(nil).Once()
+	assert.NoError(t, uc.LeaveRoom(2, 5))
+
+	repo.On("GetRoom", 3).Return(&entity.Room{ID: 3, Kind: entity.RoomKindDirect}, nil)
+	repo.On("GetMember", 3, int64(5)).Return(member(3, 5, entity.RoomRoleMember), nil)
+	assert.ErrorIs(t, uc.LeaveRoom(3, 5), ErrRoomForbidden)
+	repo.AssertExpectations(t)
+}
+
+func TestRoomUseCase_CheckAccess(t *testing.T) {
+	repo := new(MockRoomRepository)
+	uc := NewRoomUseCase(repo)
+
+	assert.NoError(t, uc.CheckAccess(entity.GeneralRoomID, 5))
+
+	repo.On("GetRoom", 2).Return(&entity.Room{ID: 2}, nil)
+	repo.On("GetMember", 2, int64(5)).Return(member(2, 5, entity.RoomRoleMember), nil)
+	repo.On("GetMember", 2, int64(6)).Return(nil, repository.ErrMemberNotFound)
+	assert.NoError(t, uc.CheckAccess(2, 5))
+	assert.ErrorIs(t, uc.CheckAccess(2, 6), ErrRoomForbidden)
+}
+
+func TestRoomUseCase_MemberManagement(t *testing.T) {
+	newRepo := func() *MockRoomRepository {
+		repo := new(MockRoomRepository)
+		repo.On("GetRoom", 2).Return(&entity.Room{ID: 2, IsPrivate: true}, nil)
+		repo.On("GetMember", 2, int64(1)).Return(member(2, 1, entity.RoomRoleOwner), nil).Maybe()
+		repo.On("GetMember", 2, int64(2)).Return(member(2, 2, entity.RoomRoleAdmin), nil).Maybe()
+		repo.On("GetMember", 2, int64(3)).Return(member(2, 3, entity.RoomRoleMember), nil).Maybe()
+		return repo
+	}
+
+	t.Run("admin invites", func(t *testing.T) {
+		repo := newRepo()
+		repo.On("AddMember", 2, int64(7), entity.RoomRoleMember).Return(nil).Once()
+		assert.NoError(t, NewRoomUseCase(repo).InviteMember(2, 2, 7))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("member cannot invite", func(t *testing.T) {
+		repo := newRepo()
+		assert.ErrorIs(t, NewRoomUseCase(repo).InviteMember(2, 3, 7), ErrRoomForbidden)
+		repo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin removes member but not another admin", func(t *testing.T) {
+		repo := newRepo()
+		repo.On("RemoveMember", 2, int64(3)).Return(nil).Once()
+		uc := NewRoomUseCase(repo)
+		assert.NoError(t, uc.RemoveMember(2, 2, 3))
+		assert.ErrorIs(t, uc.RemoveMember(2, 2, 1), ErrRoomForbidden)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("direct conversation members are fixed", func(t *testing.T) {
+		repo := new(MockRoomRepository)
+		repo.On("GetRoom", 3).Return(&entity.Room{ID: 3, IsPrivate: true, Kind: entity.RoomKindDirect}, nil)
+		assert.ErrorIs(t, NewRoomUseCase(repo).InviteMember(3, 1, 7), ErrRoomForbidden)
+		repo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("group conversation size is capped", func(t *testing.T) {
+		repo := new(MockRoomRepository)
+		repo.On("GetRoom", 4).Return(&entity.Room{ID: 4, IsPrivate: true, Kind: entity.RoomKindGroup}, nil)
+		repo.On("GetMember", 4, int64(1)).Return(member(4, 1, entity.RoomRoleOwner), nil)
+		full := make([]entity.RoomMember, entity.MaxConversationParticipants)
+		for i := range full {
+			full[i] = entity.RoomMember{RoomID: 4, UserID: int64(i + 1)}
+		}
+		repo.On("GetMembers", 4).Return(full, nil)
+		uc := NewRoomUseCase(repo)
+		assert.ErrorIs(t, uc.InviteMember(4, 1, 42), ErrInvalidRoom)
+		// Повторное приглашение участника не упирается в лимит
+		assert.NoError(t, uc.InviteMember(4, 1, 2))
+		repo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("only owner changes roles", func(t *testing.T) {
+		repo := newRepo()
+		repo.On("SetMemberRole", 2, int64(3), entity.RoomRoleAdmin).Return(nil).Once()
+		uc := NewRoomUseCase(repo)
+		assert.NoError(t, uc.SetMemberRole(2, 1, 3, entity.RoomRoleAdmin))
+		assert.ErrorIs(t, uc.SetMemberRole(2, 2, 3, entity.RoomRoleAdmin), ErrRoomForbidden)
+		assert.ErrorIs(t, uc.SetMemberRole(2, 1, 1, entity.RoomRoleMember), ErrRoomForbidden)
+		assert.ErrorIs(t, uc.SetMemberRole(2, 1, 3, entity.RoomRoleOwner), ErrInvalidRoom)
+		repo.AssertExpectations(t)
+	})
+}
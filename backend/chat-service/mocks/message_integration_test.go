@@ -16,7 +16,6 @@ import (
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/handler"
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/repository"
 	"github.com/jaliks17/ffffforum/backend/chat-service/internal/usecase"
-	myWeb "github.com/jaliks17/ffffforum/backend/chat-service/pkg/websocket"
 
 	"os"
 	"strings"
@@ -82,7 +81,7 @@ func (suite *MessageIntegrationTestSuite) SetupSuite() {
 	}
 
 	suite.repo = repository.NewMessageRepository(suite.db)
-	suite.messageUC = usecase.NewMessageUseCase(suite.repo)
+	suite.messageUC = usecase.NewMessageUseCase(suite.repo, nil, nil, nil)
 }
 
 func (suite *MessageIntegrationTestSuite) TearDownSuite() {
@@ -150,7 +149,7 @@ func (suite *MessageIntegrationTestSuite) TestSaveMessage() {
 				assert.NoError(suite.T(), err)
 			}
 
-			messages, err := suite.repo.GetMessages()
+			messages, err := suite.repo.GetMessages(entity.MessageQuery{Limit: usecase.DefaultHistoryLimit})
 			assert.NoError(suite.T(), err)
 			assert.Len(suite.T(), messages, 1, "Должно быть ровно одно сообщение в базе")
 			assert.Equal(suite.T(), tt.message.Username, messages[0].Username)
@@ -170,9 +169,11 @@ func (suite *MessageIntegrationTestSuite) TestGetMessages() {
 		assert.NoError(suite.T(), err)
 	}
 
-	messages, err := suite.messageUC.GetMessages()
+	page, err := suite.messageUC.GetMessages(entity.MessageQuery{})
 	assert.NoError(suite.T(), err)
+	messages := page.Messages
 	assert.Len(suite.T(), messages, len(messagesToSave))
+	assert.Equal(suite.T(), len(messagesToSave), page.Total)
 
 	for i, msg := range messages {
 		assert.Equal(suite.T(), messagesToSave[i].Username, msg.Username)
@@ -192,8 +193,9 @@ func (suite *MessageIntegrationTestSuite) TestMessageFlow() {
 	err := suite.messageUC.SaveMessage(&testMsg)
 	assert.NoError(suite.T(), err)
 
-	messages, err := suite.messageUC.GetMessages()
+	page, err := suite.messageUC.GetMessages(entity.MessageQuery{})
 	assert.NoError(suite.T(), err)
+	messages := page.Messages
 	assert.Len(suite.T(), messages, 1)
 	assert.Equal(suite.T(), testMsg.Username, messages[0].Username)
 	assert.Equal(suite.T(), testMsg.Message, messages[0].Message)
@@ -207,10 +209,10 @@ func TestGetMessages_EdgeCases(t *testing.T) {
 	repo := repository.NewMessageRepository(db)
 
 	t.Run("empty result", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, user_id, username, content as message, timestamp FROM chat_messages ORDER BY timestamp ASC").
+		mock.ExpectQuery("SELECT id, room_id, seq, user_id, username, content as message, timestamp, .* FROM chat_messages WHERE room_id = \\$1 ORDER BY seq DESC LIMIT \\$2 OFFSET \\$3").
 			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "username", "message", "timestamp"}))
 
-		messages, err := repo.GetMessages()
+		messages, err := repo.GetMessages(entity.MessageQuery{Limit: usecase.DefaultHistoryLimit})
 		require.NoError(t, err)
 		require.Empty(t, messages)
 	})
@@ -218,10 +220,10 @@ func TestGetMessages_EdgeCases(t *testing.T) {
 	t.Run("scan error", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"id", "user_id"}).
 			AddRow(1, 1)
-		mock.ExpectQuery("SELECT id, user_id, username, content as message, timestamp FROM chat_messages ORDER BY timestamp ASC").
+		mock.ExpectQuery("SELECT id, room_id, seq, user_id, username, content as message, timestamp, .* FROM chat_messages WHERE room_id = \\$1 ORDER BY seq DESC LIMIT \\$2 OFFSET \\$3").
 			WillReturnRows(rows)
 
-		_, err := repo.GetMessages()
+		_, err := repo.GetMessages(entity.MessageQuery{Limit: usecase.DefaultHistoryLimit})
 		require.Error(t, err)
 	})
 }
@@ -275,16 +277,26 @@ func (m *mockAuthServiceClient) ValidateSession(ctx context.Context, in *proto.V
 	return args.Get(0).(*proto.ValidateSessionResponse), args.Error(1)
 }
 
+func (m *mockAuthServiceClient) GetUsersByUsernames(ctx context.Context, in *proto.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*proto.GetUsersByUsernamesResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*proto.GetUsersByUsernamesResponse), args.Error(1)
+}
+
+func (m *mockAuthServiceClient) SuspendUser(ctx context.Context, in *proto.SuspendUserRequest, opts ...grpc.CallOption) (*proto.SuspendUserResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*proto.SuspendUserResponse), args.Error(1)
+}
+
 func TestMessageHandler(t *testing.T) {
 	mockUC := &mockMessageUseCase{
 		saveFunc: func(msg *entity.Message) error {
 			return nil
 		},
-		getMessagesFunc: func() ([]entity.Message, error) {
-			return []entity.Message{
+		getMessagesFunc: func(entity.MessageQuery) (*entity.MessagePage, error) {
+			return &entity.MessagePage{Messages: []entity.Message{
 				{ID: 1, UserID: 1, Username: "user1", Message: "Hello"},
 				{ID: 2, UserID: 2, Username: "user2", Message: "Hi there"},
-			}, nil
+			}, Total: 2}, nil
 		},
 	}
 
@@ -309,7 +321,7 @@ func TestMessageHandler(t *testing.T) {
 
 	t.Run("GetMessages database error", func(t *testing.T) {
 		errorUC := &mockMessageUseCase{
-			getMessagesFunc: func() ([]entity.Message, error) {
+			getMessagesFunc: func(entity.MessageQuery) (*entity.MessagePage, error) {
 				return nil, errors.New("database error")
 			},
 		}
@@ -331,8 +343,6 @@ func TestMessageHandler(t *testing.T) {
 		authClient := new(mockAuthServiceClient)
 		h := handler.NewMessageHandler(mockUC, authClient)
 
-		myWeb.Clients = make(map[*websocket.Conn]int)
-
 		authClient.On("ValidateSession", mock.Anything, mock.Anything).Return(&proto.ValidateSessionResponse{Valid: true, UserId: 1, UserRole: "testrole"}, nil).Once()
 		authClient.On("GetUserProfile", mock.Anything, mock.Anything).Return(&proto.GetUserProfileResponse{User: &proto.User{Id: 1, Username: "testuser", Role: "testrole"}}, nil).Once()
 
@@ -348,7 +358,7 @@ func TestMessageHandler(t *testing.T) {
 		require.NoError(t, err)
 		defer ws.Close()
 
-		assert.Equal(t, 1, len(myWeb.Clients))
+		assert.Eventually(t, func() bool { return h.Hub.Stats().Connections == 1 }, time.Second, 10*time.Millisecond)
 
 		testMsg := entity.Message{UserID: 1, Username: "test", Message: "hello"}
 		err = ws.WriteJSON(testMsg)
@@ -357,13 +367,10 @@ func TestMessageHandler(t *testing.T) {
 		authClient.AssertExpectations(t)
 	})
 
-	t.Run("HandleMessages broadcast", func(t *testing.T) {
+	t.Run("Hub broadcast", func(t *testing.T) {
 		authClient := new(mockAuthServiceClient)
 		h := handler.NewMessageHandler(mockUC, authClient)
 
-		myWeb.Clients = make(map[*websocket.Conn]int)
-		myWeb.Broadcast = make(chan entity.Message, 10)
-
 		authClient.On("ValidateSession", mock.Anything, mock.MatchedBy(func(req *proto.ValidateSessionRequest) bool {
 			return req.Token == "token1"
 		})).Return(&proto.ValidateSessionResponse{Valid: true, UserId: 1, UserRole: "testrole"}, nil).Once()
@@ -394,10 +401,10 @@ func TestMessageHandler(t *testing.T) {
 		require.NoError(t, err)
 		defer ws2.Close()
 
-		go h.HandleMessages()
+		assert.Eventually(t, func() bool { return h.Hub.Stats().Connections == 2 }, time.Second, 10*time.Millisecond)
 
 		broadcastMsg := entity.Message{UserID: 0, Username: "system", Message: "broadcast"}
-		myWeb.Broadcast <- broadcastMsg
+		assert.Equal(t, 2, h.Hub.BroadcastRoom(entity.GeneralRoomID, broadcastMsg))
 
 		var msg1, msg2 entity.Message
 		ws1.SetReadDeadline(time.Now().Add(time.Second))
@@ -422,7 +429,7 @@ func TestMessageHandler(t *testing.T) {
 type mockMessageUseCase struct {
 	usecase.MessageUseCase
 	saveFunc        func(*entity.Message) error
-	getMessagesFunc func() ([]entity.Message, error)
+	getMessagesFunc func(entity.MessageQuery) (*entity.MessagePage, error)
 	saveCount       int
 }
 
@@ -434,9 +441,9 @@ func (m *mockMessageUseCase) SaveMessage(msg *entity.Message) error {
 	return nil
 }
 
-func (m *mockMessageUseCase) GetMessages() ([]entity.Message, error) {
+func (m *mockMessageUseCase) GetMessages(q entity.MessageQuery) (*entity.MessagePage, error) {
 	if m.getMessagesFunc != nil {
-		return m.getMessagesFunc()
+		return m.getMessagesFunc(q)
 	}
-	return nil, nil
+	return &entity.MessagePage{Messages: []entity.Message{}}, nil
 }
\ No newline at end of file
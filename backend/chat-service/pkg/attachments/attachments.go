@@ -0,0 +1,79 @@
+// Package attachments занимает файлы forum-service для сообщений чата: форум проверяет,
+// что вложения принадлежат автору и еще нигде не использованы, и отмечает их занятыми.
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TokenHeader - заголовок с общим секретом сервисов, который проверяет forum-service
+const TokenHeader = "X-Internal-Token"
+
+// ErrInvalid возвращается, если форум отклонил список вложений
+var ErrInvalid = errors.New("attachment not found, belongs to another user or is already used")
+
+// Client обращается к /internal/attachments/claim форума
+type Client struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+func NewClient(url, token string) *Client {
+	return &Client{
+		url:        url,
+		token:      token,
+		httpClient: &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+type claimRequest struct {
+	OwnerID       int64   `json:"owner_id"`
+	RoomID        int64   `json:"room_id"`
+	AttachmentIDs []int64 `json:"attachment_ids"`
+}
+
+type claimResponse struct {
+	AttachmentIDs []int64 `json:"attachment_ids"`
+}
+
+// Claim занимает вложения ids пользователя ownerID для сообщения в комнате roomID и возвращает
+// их ID без повторов. Форум запоминает комнату и отдает файлы только ее участникам.
+func (c *Client) Claim(ctx context.Context, ownerID, roomID int64, ids []int64) ([]int64, error) {
+	data, err := json.Marshal(claimRequest{OwnerID: ownerID, RoomID: roomID, AttachmentIDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TokenHeader, c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return nil, ErrInvalid
+	default:
+		return nil, fmt.Errorf("attachment service responded with status %d", resp.StatusCode)
+	}
+	var claimed claimResponse
+	if err := json.NewDecoder(resp.Body).Decode(&claimed); err != nil {
+		return nil, err
+	}
+	return claimed.AttachmentIDs, nil
+}
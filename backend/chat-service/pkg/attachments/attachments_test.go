@@ -0,0 +1,43 @@
+package attachments
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Claim(t *testing.T) {
+	var got claimRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get(TokenHeader))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_ = json.NewEncoder(w).Encode(claimResponse{AttachmentIDs: []int64{5, 7}})
+	}))
+	defer server.Close()
+
+	ids, err := NewClient(server.URL, "secret").Claim(context.Background(), 3, 2, []int64{5, 7, 5})
+	assert.NoError(t, err)
+	assert.Equal(t, claimRequest{OwnerID: 3, RoomID: 2, AttachmentIDs: []int64{5, 7, 5}}, got)
+	assert.Equal(t, []int64{5, 7}, ids)
+}
+
+func TestClient_Claim_Error(t *testing.T) {
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret")
+	_, err := client.Claim(context.Background(), 3, 2, []int64{5})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	status = http.StatusUnauthorized
+	_, err = client.Claim(context.Background(), 3, 2, []int64{5})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalid)
+}
@@ -0,0 +1,287 @@
+// Package chatclient - клиент WebSocket чата по протоколу protocol.Subprotocol
+// для других сервисов, ботов и тестов.
+package chatclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrUnsupportedProtocol возвращается Dial, если сервер не поддерживает версию протокола клиента
+var ErrUnsupportedProtocol = errors.New("server does not support " + protocol.Subprotocol)
+
+// ErrClosed возвращается запросами, если соединение закрыто
+var ErrClosed = errors.New("connection closed")
+
+// EventBuffer - сколько входящих сообщений и событий может ждать чтения из Events
+const EventBuffer = 64
+
+// Client - соединение с чатом. Методы можно вызывать из разных горутин.
+type Client struct {
+	conn *websocket.Conn
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan protocol.Envelope
+	err     error
+
+	events chan protocol.Envelope
+	// done закрывается, когда readLoop завершился; closed - при вызове Close
+	done      chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Dial подключается к endpoint-у /ws, например "ws://localhost:8082/ws", с токеном сессии
+func Dial(ctx context.Context, endpoint, token string) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+
+	dialer := websocket.Dialer{Subprotocols: protocol.Subprotocols}
+	conn, _, err := dialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if conn.Subprotocol() != protocol.Subprotocol {
+		conn.Close()
+		return nil, ErrUnsupportedProtocol
+	}
+
+	c := &Client{
+		conn:    conn,
+		pending: make(map[string]chan protocol.Envelope),
+		events:  make(chan protocol.Envelope, EventBuffer),
+		done:    make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// Events возвращает входящие кадры message и event. Канал закрывается вместе с соединением.
+// Его нужно читать: пока буфер полон, клиент не получает и ответы на запросы.
+func (c *Client) Events() <-chan protocol.Envelope {
+	return c.events
+}
+
+// Send отправляет сообщение в комнату (0 - общая комната) и ждет подтверждения
+func (c *Client) Send(ctx context.Context, roomID int, message string, attachmentIDs ...int64) (protocol.AckPayload, error) {
+	return c.SendWithID(ctx, NewID(), protocol.SendPayload{RoomID: roomID, Message: message, AttachmentIDs: attachmentIDs})
+}
+
+// SendWithID отправляет сообщение с заданным id запроса. Повтор с тем же id после
+// таймаута, в том числе из нового соединения, не создает второе сообщение: id служит
+// idempotency_key, если тот не задан в payload.
+func (c *Client) SendWithID(ctx context.Context, id string, payload protocol.SendPayload) (protocol.AckPayload, error) {
+	var ack protocol.AckPayload
+	if payload.IdempotencyKey == "" {
+		payload.IdempotencyKey = id
+	}
+	resp, err := c.request(ctx, protocol.OpSend, id, payload)
+	if err != nil {
+		return ack, err
+	}
+	if err := json.Unmarshal(resp.Payload, &ack); err != nil {
+		return ack, fmt.Errorf("invalid ack payload: %w", err)
+	}
+	return ack, nil
+}
+
+// Resume запрашивает сообщения, пропущенные с последнего полученного seq каждой комнаты.
+// Они приходят в Events событиями replay до того, как Resume вернется.
+func (c *Client) Resume(ctx context.Context, rooms []protocol.RoomCursor) (protocol.ResumeAckPayload, error) {
+	var ack protocol.ResumeAckPayload
+	resp, err := c.request(ctx, protocol.OpResume, NewID(), protocol.ResumePayload{Rooms: rooms})
+	if err != nil {
+		return ack, err
+	}
+	if err := json.Unmarshal(resp.Payload, &ack); err != nil {
+		return ack, fmt.Errorf("invalid ack payload: %w", err)
+	}
+	return ack, nil
+}
+
+// Join вступает в комнату
+func (c *Client) Join(ctx context.Context, roomID int) error {
+	_, err := c.request(ctx, protocol.OpJoin, NewID(), protocol.RoomPayload{RoomID: roomID})
+	return err
+}
+
+// Leave выходит из комнаты
+func (c *Client) Leave(ctx context.Context, roomID int) error {
+	_, err := c.request(ctx, protocol.OpLeave, NewID(), protocol.RoomPayload{RoomID: roomID})
+	return err
+}
+
+// Subscribe подписывает соединение на тему, например "poll:12"
+func (c *Client) Subscribe(ctx context.Context, topic string) error {
+	_, err := c.request(ctx, protocol.OpSubscribe, NewID(), protocol.TopicPayload{Topic: topic})
+	return err
+}
+
+// Unsubscribe отписывает соединение от темы
+func (c *Client) Unsubscribe(ctx context.Context, topic string) error {
+	_, err := c.request(ctx, protocol.OpUnsubscribe, NewID(), protocol.TopicPayload{Topic: topic})
+	return err
+}
+
+// Typing сообщает участникам комнаты, что пользователь начал (true) или закончил (false)
+// набирать сообщение. Пока пользователь печатает, вызов нужно повторять каждые несколько секунд.
+func (c *Client) Typing(ctx context.Context, roomID int, typing bool) error {
+	_, err := c.request(ctx, protocol.OpTyping, NewID(), protocol.TypingPayload{RoomID: roomID, Typing: typing})
+	return err
+}
+
+// SetStatus сообщает статус пользователя: "online" или "away"
+func (c *Client) SetStatus(ctx context.Context, status string) error {
+	_, err := c.request(ctx, protocol.OpPresence, NewID(), protocol.PresencePayload{Status: status})
+	return err
+}
+
+// MarkRead отмечает комнату прочитанной до сообщения messageID (0 - до последнего)
+// и возвращает ее счетчик непрочитанных
+func (c *Client) MarkRead(ctx context.Context, roomID, messageID int) (protocol.RoomUnread, error) {
+	var ack protocol.RoomUnread
+	resp, err := c.request(ctx, protocol.OpRead, NewID(), protocol.ReadPayload{RoomID: roomID, MessageID: messageID})
+	if err != nil {
+		return ack, err
+	}
+	if err := json.Unmarshal(resp.Payload, &ack); err != nil {
+		return ack, fmt.Errorf("invalid ack payload: %w", err)
+	}
+	return ack, nil
+}
+
+// Unread возвращает счетчики непрочитанных общей комнаты и всех комнат пользователя
+func (c *Client) Unread(ctx context.Context) ([]protocol.RoomUnread, error) {
+	var ack protocol.UnreadAckPayload
+	resp, err := c.request(ctx, protocol.OpUnread, NewID(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(resp.Payload, &ack); err != nil {
+		return nil, fmt.Errorf("invalid ack payload: %w", err)
+	}
+	return ack.Unread, nil
+}
+
+// Close закрывает соединение
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	c.writeMu.Lock()
+	c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	c.writeMu.Unlock()
+	return c.conn.Close()
+}
+
+// Err возвращает причину закрытия соединения
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// request отправляет запрос и ждет ответа с тем же id. Ответ error возвращается
+// как *protocol.Error.
+func (c *Client) request(ctx context.Context, op, id string, payload interface{}) (protocol.Envelope, error) {
+	env, err := protocol.NewEnvelope(op, id, payload)
+	if err != nil {
+		return env, err
+	}
+
+	reply := make(chan protocol.Envelope, 1)
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return env, ErrClosed
+	}
+	c.pending[id] = reply
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	c.writeMu.Lock()
+	err = c.conn.WriteJSON(env)
+	c.writeMu.Unlock()
+	if err != nil {
+		return env, err
+	}
+
+	select {
+	case resp := <-reply:
+		if resp.Op == protocol.OpError {
+			var e protocol.ErrorPayload
+			json.Unmarshal(resp.Payload, &e)
+			return resp, (*protocol.Error)(&e)
+		}
+		return resp, nil
+	case <-c.done:
+		return env, ErrClosed
+	case <-ctx.Done():
+		return env, ctx.Err()
+	}
+}
+
+func (c *Client) readLoop() {
+	defer close(c.events)
+	defer close(c.done)
+
+	for {
+		var env protocol.Envelope
+		if err := c.conn.ReadJSON(&env); err != nil {
+			c.mu.Lock()
+			c.err = err
+			c.mu.Unlock()
+			return
+		}
+
+		if env.Op == protocol.OpAck || env.Op == protocol.OpError {
+			c.mu.Lock()
+			reply, ok := c.pending[env.ID]
+			c.mu.Unlock()
+			if ok {
+				// Буфер на один ответ; повторный ответ на тот же id не нужен
+				select {
+				case reply <- env:
+				default:
+				}
+				continue
+			}
+			// Ошибка без id относится к кадру, который сервер не смог разобрать
+			if env.ID != "" {
+				continue
+			}
+		}
+		select {
+		case c.events <- env:
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// NewID генерирует случайный id запроса
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,131 @@
+package chatclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer подтверждает send и join, отклоняет сообщение "spam", рассылает
+// подтвержденное сообщение обратно, отвечает на resume одним сообщением на комнату,
+// а на read и unread - счетчиками, в которых прочитано все
+func fakeServer(t *testing.T, subprotocols []string) string {
+	upgrader := websocket.Upgrader{Subprotocols: subprotocols}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("token"))
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var env protocol.Envelope
+			if err := conn.ReadJSON(&env); err != nil {
+				return
+			}
+			switch env.Op {
+			case protocol.OpSend:
+				var p protocol.SendPayload
+				json.Unmarshal(env.Payload, &p)
+				if p.Message == "spam" {
+					conn.WriteJSON(protocol.Fail(env.ID, protocol.CodeRejected, "message rejected by content filter"))
+					continue
+				}
+				assert.Equal(t, env.ID, p.IdempotencyKey)
+				conn.WriteJSON(protocol.Ack(env.ID, protocol.AckPayload{MessageID: 7, RoomID: 1, Seq: 3}))
+				conn.WriteMessage(websocket.TextMessage, protocol.Wrap([]byte(`{"id":7,"message":"`+p.Message+`"}`)))
+			case protocol.OpResume:
+				var p protocol.ResumePayload
+				json.Unmarshal(env.Payload, &p)
+				ack := protocol.ResumeAckPayload{}
+				for _, room := range p.Rooms {
+					replay := fmt.Sprintf(`{"type":"replay","room_id":%d,"messages":[{"id":8,"seq":%d}]}`, room.RoomID, room.LastSeq+1)
+					conn.WriteMessage(websocket.TextMessage, protocol.Wrap([]byte(replay)))
+					ack.Rooms = append(ack.Rooms, protocol.RoomResume{RoomID: room.RoomID, Replayed: 1})
+				}
+				conn.WriteJSON(protocol.Ack(env.ID, ack))
+			case protocol.OpRead:
+				var p protocol.ReadPayload
+				json.Unmarshal(env.Payload, &p)
+				conn.WriteJSON(protocol.Ack(env.ID, protocol.RoomUnread{RoomID: p.RoomID, LastReadSeq: 4, LastSeq: 4}))
+			case protocol.OpUnread:
+				conn.WriteJSON(protocol.Ack(env.ID, protocol.UnreadAckPayload{Unread: []protocol.RoomUnread{{RoomID: 1, LastReadSeq: 4, LastSeq: 4}}}))
+			default:
+				conn.WriteJSON(protocol.Ack(env.ID, nil))
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+}
+
+func TestClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, fakeServer(t, protocol.Subprotocols), "secret")
+	require.NoError(t, err)
+	defer client.Close()
+
+	ack, err := client.Send(ctx, 0, "Hello")
+	require.NoError(t, err)
+	assert.Equal(t, 7, ack.MessageID)
+	assert.Equal(t, int64(3), ack.Seq)
+
+	select {
+	case env := <-client.Events():
+		assert.Equal(t, protocol.OpMessage, env.Op)
+		assert.JSONEq(t, `{"id":7,"message":"Hello"}`, string(env.Payload))
+	case <-ctx.Done():
+		t.Fatal("message was not delivered")
+	}
+
+	_, err = client.Send(ctx, 0, "spam")
+	var protoErr *protocol.Error
+	require.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, protocol.CodeRejected, protoErr.Code)
+	assert.Empty(t, client.Events())
+
+	assert.NoError(t, client.Join(ctx, 2))
+	assert.NoError(t, client.Subscribe(ctx, "poll:1"))
+	assert.NoError(t, client.Typing(ctx, 2, true))
+	assert.NoError(t, client.SetStatus(ctx, "away"))
+
+	resumed, err := client.Resume(ctx, []protocol.RoomCursor{{RoomID: 1, LastSeq: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, []protocol.RoomResume{{RoomID: 1, Replayed: 1}}, resumed.Rooms)
+	// Повтор приходит раньше подтверждения resume
+	require.Len(t, client.Events(), 1)
+	env := <-client.Events()
+	assert.Equal(t, protocol.OpEvent, env.Op)
+	assert.JSONEq(t, `{"type":"replay","room_id":1,"messages":[{"id":8,"seq":4}]}`, string(env.Payload))
+
+	read, err := client.MarkRead(ctx, 1, 8)
+	require.NoError(t, err)
+	assert.Equal(t, protocol.RoomUnread{RoomID: 1, LastReadSeq: 4, LastSeq: 4}, read)
+	unread, err := client.Unread(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []protocol.RoomUnread{read}, unread)
+
+	client.Close()
+	_, err = client.Send(ctx, 0, "late")
+	assert.Error(t, err)
+}
+
+func TestDial_UnsupportedProtocol(t *testing.T) {
+	_, err := Dial(context.Background(), fakeServer(t, nil), "secret")
+	assert.ErrorIs(t, err, ErrUnsupportedProtocol)
+}
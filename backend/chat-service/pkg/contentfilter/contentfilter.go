@@ -0,0 +1,101 @@
+// Package contentfilter проверяет сообщения чата фильтром контента forum-service
+// (запрещенные слова, ссылки от новых аккаунтов, дубликаты, классификатор спама).
+package contentfilter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TokenHeader - заголовок с общим секретом сервисов, который проверяет forum-service
+const TokenHeader = "X-Internal-Token"
+
+// Действия фильтра; пустое действие - сообщение публикуется как есть
+const (
+	ActionMask   = "mask"
+	ActionHold   = "hold"
+	ActionReject = "reject"
+)
+
+// Verdict - решение фильтра. Text содержит текст после маскирования запрещенных слов.
+type Verdict struct {
+	Action string `json:"action"`
+	Text   string `json:"text"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Client обращается к /internal/content/* форума
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient создает клиент; baseURL - адрес группы /internal/content форума
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+type checkRequest struct {
+	UserID int64  `json:"user_id"`
+	Kind   string `json:"kind"`
+	Text   string `json:"text"`
+}
+
+// Check проверяет сообщение пользователя userID
+func (c *Client) Check(ctx context.Context, userID int64, text string) (*Verdict, error) {
+	resp, err := c.post(ctx, "/check", checkRequest{UserID: userID, Kind: "message", Text: text})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("content filter responded with status %d", resp.StatusCode)
+	}
+	var verdict Verdict
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return nil, err
+	}
+	return &verdict, nil
+}
+
+// Record сообщает форуму, что сообщение сохранено, чтобы его повторы находились как дубликаты
+func (c *Client) Record(ctx context.Context, userID int64, text string) error {
+	resp, err := c.post(ctx, "/record", checkRequest{UserID: userID, Kind: "message", Text: text})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("content filter responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *Client) post(ctx context.Context, path string, body checkRequest) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set(TokenHeader, c.token)
+	}
+	return c.httpClient.Do(req)
+}
@@ -0,0 +1,52 @@
+package contentfilter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Check(t *testing.T) {
+	var got checkRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/internal/content/check", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get(TokenHeader))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_ = json.NewEncoder(w).Encode(Verdict{Action: ActionMask, Text: "hello ******", Reason: "banned word"})
+	}))
+	defer server.Close()
+
+	verdict, err := NewClient(server.URL+"/internal/content", "secret").Check(context.Background(), 7, "hello casino")
+	assert.NoError(t, err)
+	assert.Equal(t, checkRequest{UserID: 7, Kind: "message", Text: "hello casino"}, got)
+	assert.Equal(t, &Verdict{Action: ActionMask, Text: "hello ******", Reason: "banned word"}, verdict)
+}
+
+func TestClient_Check_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "wrong").Check(context.Background(), 7, "hello")
+	assert.Error(t, err)
+}
+
+func TestClient_Record(t *testing.T) {
+	var got checkRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/internal/content/record", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get(TokenHeader))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL+"/internal/content/", "secret").Record(context.Background(), 7, "join my channel")
+	assert.NoError(t, err)
+	assert.Equal(t, checkRequest{UserID: 7, Kind: "message", Text: "join my channel"}, got)
+}
@@ -0,0 +1,29 @@
+// Package mention извлекает @упоминания пользователей из сообщений чата.
+package mention
+
+import "regexp"
+
+// MaxPerMessage ограничивает число упоминаний в одном сообщении
+const MaxPerMessage = 20
+
+// pattern совпадает с usernameRegex в auth-service (^[a-zA-Z0-9_]{3,}$).
+// Перед @ не должно быть символа имени, иначе это адрес почты (user@example.com).
+var pattern = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_@])@([a-zA-Z0-9_]{3,})`)
+
+// Parse возвращает уникальные имена упомянутых пользователей в порядке появления
+func Parse(text string) []string {
+	var names []string
+	seen := make(map[string]struct{})
+	for _, m := range pattern.FindAllStringSubmatch(text, -1) {
+		name := m[1]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+		if len(names) == MaxPerMessage {
+			break
+		}
+	}
+	return names
+}
@@ -0,0 +1,13 @@
+package mention
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert.Equal(t, []string{"alice", "bob_2"}, Parse("@alice hi, @bob_2 and @alice again"))
+	assert.Nil(t, Parse("mail user@example.com or @ab"))
+	assert.Nil(t, Parse("@@alice"))
+}
@@ -0,0 +1,247 @@
+// Package protocol описывает версионированный протокол WebSocket чата.
+//
+// Клиент выбирает протокол через заголовок Sec-WebSocket-Protocol. Если сервер
+// подтвердил Subprotocol, каждый кадр в обе стороны - это Envelope:
+//
+//	{"op":"send","id":"c1","payload":{"room_id":1,"message":"Hi"}}
+//	{"op":"ack","id":"c1","payload":{"message_id":42,"room_id":1,"timestamp":"..."}}
+//	{"op":"error","id":"c1","payload":{"code":"forbidden","message":"..."}}
+//
+// Запросы клиента несут id, сгенерированный клиентом; ответ (ack или error) приходит
+// с тем же id. Повтор запроса с уже обработанным id не выполняет его второй раз, а
+// возвращает прежний ответ. Клиенты без подпротокола продолжают работать в старом
+// формате {"type":"message",...}. Схема кадров - schema.json (Schema).
+//
+// Сообщения комнаты нумеруются seq без пропусков. После переподключения клиент
+// отправляет resume с последним полученным seq каждой комнаты, получает пропущенные
+// сообщения каждой комнаты одним событием replay, а затем ack. Сообщения, пришедшие
+// и в повторе, и в рассылке, клиент отбрасывает по seq. Повтор send после обрыва не
+// создает второе сообщение, если у него тот же idempotency_key.
+//
+// Статус соединения - online, пока от клиента приходят запросы, и away после нескольких
+// минут без них или после presence со статусом away. Подписчики темы presence:{user_id}
+// получают событие presence при смене статуса пользователя. Операция typing рассылает
+// участникам комнаты событие typing.
+//
+// Операция read сдвигает позицию прочтения комнаты; соединения комнаты получают событие
+// read (отметку о прочтении). Операция unread возвращает счетчики непрочитанных, дальше
+// клиент ведет их сам по приходящим сообщениям и событиям read.
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Subprotocol - текущая версия протокола, значение Sec-WebSocket-Protocol
+const Subprotocol = "chat.v1"
+
+// Subprotocols - версии, которые поддерживает сервер, в порядке предпочтения
+var Subprotocols = []string{Subprotocol}
+
+// Операции клиента
+const (
+	OpSend        = "send"
+	OpJoin        = "join"
+	OpLeave       = "leave"
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+	OpResume      = "resume"
+	OpTyping      = "typing"
+	OpPresence    = "presence"
+	OpRead        = "read"
+	OpUnread      = "unread"
+)
+
+// Операции сервера
+const (
+	OpAck   = "ack"
+	OpError = "error"
+	// OpMessage - новое сообщение в комнате, payload - сообщение чата
+	OpMessage = "message"
+	// OpEvent - прочие события (mention, joined, left, уведомления других сервисов);
+	// вид события - поле type в payload
+	OpEvent = "event"
+)
+
+// Коды ошибок в ErrorPayload
+const (
+	CodeBadRequest = "bad_request"
+	CodeUnknownOp  = "unknown_op"
+	CodeForbidden  = "forbidden"
+	CodeNotFound   = "not_found"
+	CodeRejected   = "rejected"
+	CodeLimit      = "limit_exceeded"
+	CodeInternal   = "internal"
+)
+
+// MaxIDLength - предельная длина id запроса и idempotency_key
+const MaxIDLength = 64
+
+// MaxReplay - сколько пропущенных сообщений одной комнаты повторяет resume. Если
+// пропущено больше, клиент получает truncated и догружает историю через REST.
+const MaxReplay = 200
+
+// MaxResumeRooms - сколько комнат можно перечислить в одном resume
+const MaxResumeRooms = 50
+
+// Envelope - кадр протокола
+type Envelope struct {
+	Op      string          `json:"op"`
+	ID      string          `json:"id,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// SendPayload - payload операции send
+type SendPayload struct {
+	// RoomID - комната; 0 означает общую комнату
+	RoomID  int    `json:"room_id,omitempty"`
+	Message string `json:"message"`
+	// AttachmentIDs - вложения, заранее загруженные через POST /api/v1/attachments форума
+	AttachmentIDs []int64 `json:"attachment_ids,omitempty"`
+	// IdempotencyKey - ключ, уникальный для сообщения пользователя. Повтор send с тем же
+	// ключом, даже из другого соединения, возвращает уже сохраненное сообщение.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// RoomPayload - payload операций join и leave
+type RoomPayload struct {
+	RoomID int `json:"room_id"`
+}
+
+// TopicPayload - payload операций subscribe и unsubscribe
+type TopicPayload struct {
+	Topic string `json:"topic"`
+}
+
+// TypingPayload - payload операции typing. Пока пользователь печатает, клиент повторяет
+// typing=true раз в несколько секунд; сервер рассылает начало набора не чаще раза в 3 секунды.
+type TypingPayload struct {
+	// RoomID - комната; 0 означает общую комнату
+	RoomID int  `json:"room_id,omitempty"`
+	Typing bool `json:"typing"`
+}
+
+// PresencePayload - payload операции presence: статус, о котором сообщает клиент,
+// "online" или "away" (например, вкладка ушла в фон)
+type PresencePayload struct {
+	Status string `json:"status"`
+}
+
+// ReadPayload - payload операции read: сообщение, до которого включительно прочитана
+// комната. MessageID 0 - последнее сообщение комнаты.
+type ReadPayload struct {
+	// RoomID - комната; 0 означает общую комнату
+	RoomID    int `json:"room_id,omitempty"`
+	MessageID int `json:"message_id,omitempty"`
+}
+
+// ResumePayload - payload операции resume
+type ResumePayload struct {
+	Rooms []RoomCursor `json:"rooms"`
+}
+
+// RoomCursor - последнее сообщение комнаты, полученное клиентом (0 - ни одного)
+type RoomCursor struct {
+	RoomID  int   `json:"room_id"`
+	LastSeq int64 `json:"last_seq"`
+}
+
+// AckPayload - payload подтверждения send: сохраненное сообщение. join, leave, subscribe,
+// unsubscribe, typing и presence подтверждаются ack без payload, resume - ResumeAckPayload,
+// read - RoomUnread, unread - UnreadAckPayload.
+type AckPayload struct {
+	MessageID int       `json:"message_id"`
+	RoomID    int       `json:"room_id"`
+	Seq       int64     `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+	// Duplicate - сообщение с этим idempotency_key было сохранено раньше и повторно не рассылается
+	Duplicate bool `json:"duplicate,omitempty"`
+}
+
+// ResumeAckPayload завершает повтор пропущенных сообщений
+type ResumeAckPayload struct {
+	Rooms []RoomResume `json:"rooms"`
+}
+
+// RoomResume - итог повтора по одной комнате
+type RoomResume struct {
+	RoomID int `json:"room_id"`
+	// Replayed - сколько сообщений пришло в событии replay перед ack
+	Replayed int `json:"replayed"`
+	// Truncated - пропущено больше MaxReplay сообщений, отправлены самые ранние
+	Truncated bool `json:"truncated,omitempty"`
+	// Error - код ошибки, если комнату повторить не удалось (например, forbidden)
+	Error string `json:"error,omitempty"`
+}
+
+// RoomUnread - позиция прочтения и число непрочитанных сообщений комнаты; подтверждение
+// read. Unread считает только чужие сообщения и не превышает 1000.
+type RoomUnread struct {
+	RoomID      int   `json:"room_id"`
+	Unread      int   `json:"unread"`
+	LastReadSeq int64 `json:"last_read_seq"`
+	LastSeq     int64 `json:"last_seq"`
+}
+
+// UnreadAckPayload - подтверждение unread: счетчики общей комнаты и всех комнат пользователя
+type UnreadAckPayload struct {
+	Unread []RoomUnread `json:"unread"`
+}
+
+// ErrorPayload - описание ошибки; Code из констант Code*
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error - ошибка, которую сервер вернул на запрос
+type Error ErrorPayload
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// NewEnvelope сериализует payload в кадр
+func NewEnvelope(op, id string, payload interface{}) (Envelope, error) {
+	env := Envelope{Op: op, ID: id}
+	if payload == nil {
+		return env, nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return env, err
+	}
+	env.Payload = data
+	return env, nil
+}
+
+// Ack строит подтверждение запроса id; payload может быть nil
+func Ack(id string, payload interface{}) Envelope {
+	env, _ := NewEnvelope(OpAck, id, payload)
+	return env
+}
+
+// Fail строит кадр ошибки для запроса id; id пуст, если запрос не удалось разобрать
+func Fail(id, code, message string) Envelope {
+	env, _ := NewEnvelope(OpError, id, ErrorPayload{Code: code, Message: message})
+	return env
+}
+
+// Wrap упаковывает кадр старого формата в конверт: объект с полем type становится
+// событием OpEvent, остальное (сообщения чата) - OpMessage
+func Wrap(data []byte) []byte {
+	var probe struct {
+		Type string `json:"type"`
+	}
+	op := OpMessage
+	if json.Unmarshal(data, &probe) == nil && probe.Type != "" {
+		op = OpEvent
+	}
+	wrapped, err := json.Marshal(Envelope{Op: op, Payload: data})
+	if err != nil {
+		return data
+	}
+	return wrapped
+}
@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	var env Envelope
+	require.NoError(t, json.Unmarshal(Wrap([]byte(`{"id":1,"message":"hi"}`)), &env))
+	assert.Equal(t, OpMessage, env.Op)
+	assert.JSONEq(t, `{"id":1,"message":"hi"}`, string(env.Payload))
+
+	require.NoError(t, json.Unmarshal(Wrap([]byte(`{"type":"mention","message_id":1}`)), &env))
+	assert.Equal(t, OpEvent, env.Op)
+	assert.Empty(t, env.ID)
+}
+
+func TestAckAndFail(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err := json.Marshal(Ack("c1", AckPayload{MessageID: 42, RoomID: 1, Seq: 9, Timestamp: ts}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"op":"ack","id":"c1","payload":{"message_id":42,"room_id":1,"seq":9,"timestamp":"2024-01-02T03:04:05Z"}}`, string(data))
+
+	data, err = json.Marshal(Ack("c2", nil))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"op":"ack","id":"c2"}`, string(data))
+
+	data, err = json.Marshal(Fail("c3", CodeForbidden, "not allowed in this room"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"op":"error","id":"c3","payload":{"code":"forbidden","message":"not allowed in this room"}}`, string(data))
+
+	assert.EqualError(t, &Error{Code: CodeRejected, Message: "banned word"}, "rejected: banned word")
+}
+
+// Схема должна перечислять все операции и коды ошибок пакета
+func TestSchema(t *testing.T) {
+	var schema struct {
+		Properties struct {
+			Op struct {
+				Enum []string `json:"enum"`
+			} `json:"op"`
+		} `json:"properties"`
+		Defs struct {
+			Error struct {
+				Properties struct {
+					Code struct {
+						Enum []string `json:"enum"`
+					} `json:"code"`
+				} `json:"properties"`
+			} `json:"error"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(Schema, &schema))
+
+	assert.ElementsMatch(t, []string{OpSend, OpJoin, OpLeave, OpSubscribe, OpUnsubscribe, OpResume, OpTyping, OpPresence, OpRead, OpUnread, OpAck, OpError, OpMessage, OpEvent},
+		schema.Properties.Op.Enum)
+	assert.ElementsMatch(t, []string{CodeBadRequest, CodeUnknownOp, CodeForbidden, CodeNotFound, CodeRejected, CodeLimit, CodeInternal},
+		schema.Defs.Error.Properties.Code.Enum)
+}
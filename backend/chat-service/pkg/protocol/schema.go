@@ -0,0 +1,8 @@
+package protocol
+
+import _ "embed"
+
+// Schema - JSON Schema кадров протокола Subprotocol
+//
+//go:embed schema.json
+var Schema []byte
@@ -0,0 +1,76 @@
+package websocket
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Виды событий, которыми хабы обмениваются через Backplane
+const (
+	EventRoom          = "room"           // сообщение всем соединениям комнаты
+	EventUser          = "user"           // событие всем соединениям пользователя
+	EventUserInRoom    = "user_in_room"   // событие соединениям пользователя, подключенным к комнате
+	EventTopic         = "topic"          // событие подписчикам темы
+	EventJoinUserRoom  = "join_user_room" // подключить соединения пользователя к комнате
+	EventLeaveUserRoom = "leave_user_room"
+	EventOnline        = "online"   // пользователь в сети на экземпляре; Status - online или away
+	EventOffline       = "offline"  // закрылось последнее соединение пользователя на экземпляре
+	EventPresence      = "presence" // полный список пользователей в сети на экземпляре
+)
+
+// Event - событие, которое хаб рассылает остальным экземплярам chat-service.
+// Payload уже сериализован, получатели кладут его в очереди соединений как есть.
+type Event struct {
+	// Origin - идентификатор хаба-отправителя; свои события хаб пропускает
+	Origin  string `json:"origin"`
+	Kind    string `json:"kind"`
+	RoomID  int    `json:"room_id,omitempty"`
+	UserID  int    `json:"user_id,omitempty"`
+	Topic   string `json:"topic,omitempty"`
+	UserIDs []int  `json:"user_ids,omitempty"`
+	// Status - статус пользователя для EventOnline; пусто у экземпляров, которые
+	// не различают away, и означает online
+	Status string `json:"status,omitempty"`
+	// AwayUserIDs - пользователи из UserIDs со статусом away (для EventPresence)
+	AwayUserIDs []int           `json:"away_user_ids,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+}
+
+// Backplane связывает хабы нескольких экземпляров chat-service: событие, опубликованное
+// одним хабом, получают все подписанные хабы, включая отправителя.
+type Backplane interface {
+	Publish(event Event) error
+	// Subscribe регистрирует обработчик входящих событий. Обработчик вызывается
+	// последовательно из одной горутины и не должен надолго блокироваться.
+	Subscribe(handler func(Event)) error
+}
+
+// MemoryBackplane передает события между хабами одного процесса. Подходит для тестов
+// и для запуска нескольких хабов в одном процессе.
+type MemoryBackplane struct {
+	mu       sync.Mutex
+	handlers []func(Event)
+}
+
+func NewMemoryBackplane() *MemoryBackplane {
+	return &MemoryBackplane{}
+}
+
+// Publish синхронно передает событие всем подписчикам
+func (b *MemoryBackplane) Publish(event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+	return nil
+}
+
+func (b *MemoryBackplane) Subscribe(handler func(Event)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+	return nil
+}
@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Client - одно соединение пользователя. Писать в соединение может только writePump,
+// поэтому все исходящие сообщения проходят через очередь send.
+type Client struct {
+	// ID - идентификатор соединения, уникальный в пределах хаба
+	ID     string
+	UserID int
+	// Protocol - подпротокол, согласованный при подключении (protocol.Subprotocol);
+	// пустая строка - старый формат без конвертов
+	Protocol string
+
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+
+	// Поля ниже принадлежат горутине хаба
+	rooms  map[int]bool
+	topics map[string]bool
+	// lastActive - последний кадр от клиента; away - клиент сам сообщил, что отошел
+	lastActive time.Time
+	away       bool
+	// closeCode и closeReason записываются хабом перед закрытием send
+	closeCode   int
+	closeReason string
+}
+
+// writePump - единственная горутина, которая пишет в соединение: сообщения из очереди
+// и ping. Завершается, когда хаб закрывает очередь или запись не удалась.
+func (c *Client) writePump() {
+	ticker := time.NewTicker(c.hub.cfg.PingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(c.hub.cfg.WriteWait))
+			if !ok {
+				// Хаб отключил клиента
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, c.closeReason))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				log.Printf("Error writing to connection %s of user %d: %v", c.ID, c.UserID, err)
+				atomic.AddUint64(&c.hub.writeErrors, 1)
+				c.hub.Unregister(c)
+				return
+			}
+			atomic.AddUint64(&c.hub.sent, 1)
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.hub.cfg.WriteWait)); err != nil {
+				log.Printf("Error sending ping to connection %s of user %d: %v", c.ID, c.UserID, err)
+				c.hub.Unregister(c)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package websocket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// remoteInstance - статусы пользователей в сети на другом экземпляре и время последнего события от него
+type remoteInstance struct {
+	users map[int]string
+	seen  time.Time
+}
+
+// newInstanceID возвращает случайный идентификатор хаба для Backplane
+func newInstanceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// publish ставит событие в очередь публикации. Очередь не блокирует вызывающего:
+// при переполнении событие отбрасывается, его получат только локальные соединения.
+func (h *Hub) publish(ev Event) {
+	if h.outbox == nil {
+		return
+	}
+	ev.Origin = h.ID
+	select {
+	case h.outbox <- ev:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+		log.Printf("Backplane outbox of hub %s is full, dropping %s event", h.ID, ev.Kind)
+	}
+}
+
+// publishLoop публикует события по одному, сохраняя их порядок. После остановки хаба
+// дописывает то, что уже стоит в очереди, включая последний снимок присутствия.
+func (h *Hub) publishLoop() {
+	for {
+		select {
+		case ev := <-h.outbox:
+			h.publishNow(ev)
+		case <-h.stopped:
+			for {
+				select {
+				case ev := <-h.outbox:
+					h.publishNow(ev)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (h *Hub) publishNow(ev Event) {
+	if err := h.cfg.Backplane.Publish(ev); err != nil {
+		atomic.AddUint64(&h.publishErrors, 1)
+		log.Printf("Error publishing %s event to backplane: %v", ev.Kind, err)
+		return
+	}
+	atomic.AddUint64(&h.published, 1)
+}
+
+// receive применяет событие другого экземпляра к локальным соединениям
+func (h *Hub) receive(ev Event) {
+	if ev.Origin == h.ID || ev.Origin == "" {
+		return
+	}
+	atomic.AddUint64(&h.received, 1)
+
+	h.do(func() {
+		inst := h.remote[ev.Origin]
+		if inst == nil {
+			inst = &remoteInstance{users: make(map[int]string)}
+			h.remote[ev.Origin] = inst
+		}
+		inst.seen = time.Now()
+
+		switch ev.Kind {
+		case EventOnline:
+			status := ev.Status
+			if status != StatusAway {
+				status = StatusOnline
+			}
+			inst.users[ev.UserID] = status
+			h.notifyPresence(ev.UserID)
+		case EventOffline:
+			delete(inst.users, ev.UserID)
+			h.notifyPresence(ev.UserID)
+		case EventPresence:
+			previous := inst.users
+			inst.users = make(map[int]string, len(ev.UserIDs))
+			for _, userID := range ev.UserIDs {
+				inst.users[userID] = StatusOnline
+			}
+			for _, userID := range ev.AwayUserIDs {
+				if _, ok := inst.users[userID]; ok {
+					inst.users[userID] = StatusAway
+				}
+			}
+			for userID := range previous {
+				h.notifyPresence(userID)
+			}
+			for userID := range inst.users {
+				h.notifyPresence(userID)
+			}
+		default:
+			h.apply(ev)
+		}
+	})
+}
+
+// remoteStatus возвращает лучший статус пользователя на других экземплярах.
+// Вызывается в горутине хаба.
+func (h *Hub) remoteStatus(userID int) string {
+	status := StatusOffline
+	for _, inst := range h.remote {
+		switch inst.users[userID] {
+		case StatusOnline:
+			return StatusOnline
+		case StatusAway:
+			status = StatusAway
+		}
+	}
+	return status
+}
+
+// announcePresence рассылает список пользователей, подключенных к этому экземпляру.
+// Снимок исправляет расхождения, если отдельные online/offline события потерялись.
+func (h *Hub) announcePresence() {
+	userIDs := make([]int, 0, len(h.local))
+	var away []int
+	for userID, status := range h.local {
+		userIDs = append(userIDs, userID)
+		if status == StatusAway {
+			away = append(away, userID)
+		}
+	}
+	sort.Ints(userIDs)
+	sort.Ints(away)
+	h.publish(Event{Kind: EventPresence, UserIDs: userIDs, AwayUserIDs: away})
+}
+
+// expireRemote забывает экземпляры, от которых давно не было событий
+func (h *Hub) expireRemote() {
+	deadline := time.Now().Add(-3 * h.cfg.PresenceInterval)
+	for origin, inst := range h.remote {
+		if inst.seen.Before(deadline) {
+			log.Printf("Backplane instance %s expired", origin)
+			delete(h.remote, origin)
+			for userID := range inst.users {
+				h.notifyPresence(userID)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_TwoInstancesShareBackplane(t *testing.T) {
+	backplane := NewMemoryBackplane()
+	first := NewHub(HubConfig{Backplane: backplane})
+	defer first.Stop()
+	second := NewHub(HubConfig{Backplane: backplane})
+	server, accepted := testServer(t)
+
+	alice, aliceConn := dial(t, server, accepted)
+	bob, bobConn := dial(t, server, accepted)
+	a, err := first.Register(aliceConn, 1)
+	require.NoError(t, err)
+	b, err := second.Register(bobConn, 2)
+	require.NoError(t, err)
+
+	// Присутствие видно с обоих экземпляров
+	assert.Eventually(t, func() bool { return first.IsOnline(2) && second.IsOnline(1) }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, first.ConnectionCount(2))
+
+	// Членство, измененное на одном экземпляре, применяется к соединениям на другом
+	first.JoinRoom(a, 4)
+	assert.Equal(t, 0, first.JoinUserRoom(2, 4))
+	assert.Eventually(t, func() bool { return second.InRoom(b, 4) }, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 1, first.BroadcastRoom(4, map[string]string{"message": "hi"}))
+	assert.JSONEq(t, `{"message":"hi"}`, readText(t, alice))
+	assert.JSONEq(t, `{"message":"hi"}`, readText(t, bob))
+
+	second.SendToUser(1, map[string]string{"type": "notification"})
+	assert.JSONEq(t, `{"type":"notification"}`, readText(t, alice))
+
+	assert.True(t, second.Subscribe(b, "poll:7"))
+	first.Publish("poll:7", map[string]int{"votes": 3})
+	assert.JSONEq(t, `{"votes":3}`, readText(t, bob))
+
+	// Остановленный экземпляр сразу перестает учитываться в присутствии
+	second.Stop()
+	assert.Eventually(t, func() bool { return !first.IsOnline(2) }, time.Second, 10*time.Millisecond)
+
+	stats := first.Stats()
+	assert.Equal(t, 1, stats.Instances)
+	assert.NotZero(t, stats.EventsPublished)
+	assert.NotZero(t, stats.EventsReceived)
+}
+
+func TestHub_PresenceSnapshotAndExpiry(t *testing.T) {
+	hub := NewHub(HubConfig{Backplane: NewMemoryBackplane(), PresenceInterval: time.Hour})
+	defer hub.Stop()
+
+	hub.receive(Event{Origin: "other", Kind: EventPresence, UserIDs: []int{5, 6}})
+	assert.True(t, hub.IsOnline(5))
+	hub.receive(Event{Origin: "other", Kind: EventOffline, UserID: 5})
+	assert.False(t, hub.IsOnline(5))
+	// Свои события хаб пропускает
+	hub.receive(Event{Origin: hub.ID, Kind: EventOnline, UserID: 7})
+	assert.False(t, hub.IsOnline(7))
+
+	// Экземпляр, который давно молчит, забывается
+	hub.do(func() { hub.remote["other"].seen = time.Now().Add(-4 * time.Hour) })
+	hub.do(hub.expireRemote)
+	assert.False(t, hub.IsOnline(6))
+	assert.Equal(t, 0, hub.Stats().Instances)
+}
@@ -0,0 +1,577 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrTooManyConnections возвращается Register, если у пользователя уже открыто
+// максимально допустимое число соединений
+var ErrTooManyConnections = errors.New("too many connections")
+
+// HubConfig - настройки хаба; нулевые поля заменяются значениями по умолчанию
+type HubConfig struct {
+	// Backplane связывает хаб с хабами других экземпляров; nil - один экземпляр
+	Backplane Backplane
+	// PresenceInterval - как часто хаб проверяет бездействие соединений и рассылает через
+	// Backplane список пользователей в сети
+	PresenceInterval time.Duration
+	// AwayAfter - через сколько без активности соединение считается отошедшим
+	AwayAfter time.Duration
+
+	MaxConnectionsPerUser int
+	MaxSubscriptions      int
+	SendQueueSize         int
+	WriteWait             time.Duration
+	PongWait              time.Duration
+	PingPeriod            time.Duration
+}
+
+// Stats - снимок состояния хаба для мониторинга
+type Stats struct {
+	Connections int `json:"connections"`
+	Users       int `json:"users"`
+	Rooms       int `json:"rooms"`
+	Topics      int `json:"topics"`
+	// Счетчики с момента запуска
+	Accepted      uint64 `json:"accepted_total"`
+	Rejected      uint64 `json:"rejected_total"`
+	MessagesSent  uint64 `json:"messages_sent_total"`
+	SlowConsumers uint64 `json:"slow_consumers_evicted_total"`
+	WriteErrors   uint64 `json:"write_errors_total"`
+	// Backplane: число других экземпляров и счетчики событий
+	Instances        int    `json:"instances"`
+	EventsPublished  uint64 `json:"backplane_published_total"`
+	EventsReceived   uint64 `json:"backplane_received_total"`
+	BackplaneErrors  uint64 `json:"backplane_errors_total"`
+	BackplaneDropped uint64 `json:"backplane_dropped_total"`
+}
+
+// Hub владеет всеми соединениями сервиса: регистрацией, комнатами, подписками на темы
+// и рассылкой. Состояние меняет только горутина run, остальные методы передают ей команды,
+// поэтому внешняя синхронизация не нужна. Отправка не блокирует хаб: сообщение кладется
+// в очередь клиента, а клиента с переполненной очередью хаб отключает.
+//
+// С Backplane рассылки по комнатам, пользователям и темам доходят и до соединений
+// других экземпляров, а IsOnline и Presence учитывают пользователей, подключенных к ним.
+type Hub struct {
+	// ID - идентификатор экземпляра в Backplane
+	ID       string
+	cfg      HubConfig
+	commands chan func()
+	quit     chan struct{}
+	// stopped закрывается, когда горутина run завершилась
+	stopped chan struct{}
+	outbox  chan Event
+
+	// Состояние ниже принадлежит горутине run
+	clients map[*Client]bool
+	users   map[int]map[*Client]bool
+	byID    map[string]*Client
+	rooms   map[int]map[*Client]bool
+	topics  map[string]map[*Client]bool
+	nextID  uint64
+	// remote - пользователи в сети на других экземплярах
+	remote map[string]*remoteInstance
+	// local - статус пользователей по соединениям этого экземпляра, как он опубликован
+	// в Backplane; notified - общий статус, о котором уведомлены подписчики тем присутствия.
+	// Пользователей offline в них нет.
+	local    map[int]string
+	notified map[int]string
+
+	// Счетчики обновляются атомарно из разных горутин
+	accepted      uint64
+	rejected      uint64
+	sent          uint64
+	slowConsumers uint64
+	writeErrors   uint64
+	published     uint64
+	received      uint64
+	publishErrors uint64
+	dropped       uint64
+}
+
+// NewHub создает хаб и запускает его горутину. Остановить хаб можно через Stop.
+func NewHub(cfg HubConfig) *Hub {
+	if cfg.MaxConnectionsPerUser <= 0 {
+		cfg.MaxConnectionsPerUser = MaxConnectionsPerUser
+	}
+	if cfg.MaxSubscriptions <= 0 {
+		cfg.MaxSubscriptions = MaxSubscriptions
+	}
+	if cfg.SendQueueSize <= 0 {
+		cfg.SendQueueSize = SendQueueSize
+	}
+	if cfg.WriteWait <= 0 {
+		cfg.WriteWait = WriteWait
+	}
+	if cfg.PongWait <= 0 {
+		cfg.PongWait = PongWait
+	}
+	if cfg.PingPeriod <= 0 {
+		cfg.PingPeriod = PingPeriod
+	}
+	if cfg.PresenceInterval <= 0 {
+		cfg.PresenceInterval = PresenceInterval
+	}
+	if cfg.AwayAfter <= 0 {
+		cfg.AwayAfter = AwayAfter
+	}
+
+	h := &Hub{
+		ID:       newInstanceID(),
+		cfg:      cfg,
+		commands: make(chan func()),
+		quit:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		clients:  make(map[*Client]bool),
+		users:    make(map[int]map[*Client]bool),
+		byID:     make(map[string]*Client),
+		rooms:    make(map[int]map[*Client]bool),
+		topics:   make(map[string]map[*Client]bool),
+		remote:   make(map[string]*remoteInstance),
+		local:    make(map[int]string),
+		notified: make(map[int]string),
+	}
+	if cfg.Backplane != nil {
+		h.outbox = make(chan Event, OutboxSize)
+		go h.publishLoop()
+		if err := cfg.Backplane.Subscribe(h.receive); err != nil {
+			log.Printf("Hub %s failed to subscribe to backplane: %v", h.ID, err)
+		}
+	}
+	go h.run()
+	return h
+}
+
+func (h *Hub) run() {
+	presence := time.NewTicker(h.cfg.PresenceInterval)
+	defer presence.Stop()
+
+	for {
+		select {
+		case cmd := <-h.commands:
+			cmd()
+		case <-presence.C:
+			// Статус away появляется без действий клиента, поэтому проверяется по таймеру
+			for userID := range h.local {
+				h.refreshLocal(userID)
+			}
+			if h.cfg.Backplane != nil {
+				h.announcePresence()
+				h.expireRemote()
+			}
+		case <-h.quit:
+			for client := range h.clients {
+				h.remove(client, websocket.CloseGoingAway, "Server is shutting down")
+			}
+			// Пустой снимок сразу убирает пользователей этого экземпляра у остальных
+			h.publish(Event{Kind: EventPresence})
+			close(h.stopped)
+			return
+		}
+	}
+}
+
+// do выполняет fn в горутине хаба и ждет завершения. После Stop команды не выполняются.
+func (h *Hub) do(fn func()) bool {
+	done := make(chan struct{})
+	select {
+	case h.commands <- func() { fn(); close(done) }:
+		<-done
+		return true
+	case <-h.quit:
+		return false
+	}
+}
+
+// Stop отключает всех клиентов и останавливает хаб
+func (h *Hub) Stop() {
+	select {
+	case <-h.quit:
+	default:
+		close(h.quit)
+	}
+}
+
+// Register добавляет соединение пользователя и запускает его writePump. После этого
+// писать в conn напрямую нельзя - только через методы хаба. Если при подключении
+// согласован подпротокол, рассылки приходят клиенту в конвертах protocol.Envelope.
+func (h *Hub) Register(conn *websocket.Conn, userID int) (*Client, error) {
+	client := &Client{
+		UserID:     userID,
+		Protocol:   conn.Subprotocol(),
+		hub:        h,
+		conn:       conn,
+		send:       make(chan []byte, h.cfg.SendQueueSize),
+		rooms:      make(map[int]bool),
+		topics:     make(map[string]bool),
+		lastActive: time.Now(),
+	}
+
+	var err error
+	ok := h.do(func() {
+		if len(h.users[userID]) >= h.cfg.MaxConnectionsPerUser {
+			err = ErrTooManyConnections
+			return
+		}
+		h.nextID++
+		client.ID = strconv.FormatUint(h.nextID, 10)
+		h.clients[client] = true
+		if h.users[userID] == nil {
+			h.users[userID] = make(map[*Client]bool)
+		}
+		h.users[userID][client] = true
+		h.byID[client.ID] = client
+		h.refreshLocal(userID)
+	})
+	if !ok {
+		err = errors.New("hub is stopped")
+	}
+	if err != nil {
+		atomic.AddUint64(&h.rejected, 1)
+		return nil, err
+	}
+
+	atomic.AddUint64(&h.accepted, 1)
+	conn.SetReadDeadline(time.Now().Add(h.cfg.PongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(h.cfg.PongWait))
+	})
+	go client.writePump()
+	log.Printf("Client %s registered for user %d", client.ID, userID)
+	return client, nil
+}
+
+// Unregister отключает клиента. Повторный вызов ничего не делает.
+func (h *Hub) Unregister(client *Client) {
+	h.do(func() { h.remove(client, websocket.CloseNormalClosure, "") })
+}
+
+// remove удаляет клиента из всех индексов и закрывает его очередь; writePump
+// отправит close frame и закроет соединение. Вызывается в горутине хаба.
+func (h *Hub) remove(client *Client, code int, reason string) {
+	if !h.clients[client] {
+		return
+	}
+	delete(h.clients, client)
+	delete(h.byID, client.ID)
+	delete(h.users[client.UserID], client)
+	if len(h.users[client.UserID]) == 0 {
+		delete(h.users, client.UserID)
+	}
+	for roomID := range client.rooms {
+		h.leave(client, roomID)
+	}
+	for topic := range client.topics {
+		h.unsubscribe(client, topic)
+	}
+	h.refreshLocal(client.UserID)
+
+	client.closeCode = code
+	client.closeReason = reason
+	close(client.send)
+}
+
+// enqueue кладет сообщение в очередь клиента. Если очередь переполнена, клиент не успевает
+// читать и отключается, чтобы не задерживать остальных. Вызывается в горутине хаба.
+func (h *Hub) enqueue(client *Client, data []byte) bool {
+	select {
+	case client.send <- data:
+		return true
+	default:
+		log.Printf("Connection %s of user %d is too slow, disconnecting", client.ID, client.UserID)
+		atomic.AddUint64(&h.slowConsumers, 1)
+		h.remove(client, websocket.ClosePolicyViolation, "Slow consumer")
+		return false
+	}
+}
+
+// JoinRoom подключает соединение к комнате: после этого оно получает сообщения комнаты.
+// Права на комнату проверяет вызывающий код.
+func (h *Hub) JoinRoom(client *Client, roomID int) {
+	h.do(func() { h.join(client, roomID) })
+}
+
+// LeaveRoom отключает соединение от комнаты
+func (h *Hub) LeaveRoom(client *Client, roomID int) {
+	h.do(func() { h.leave(client, roomID) })
+}
+
+// JoinUserRoom подключает к комнате все соединения пользователя на всех экземплярах.
+// Возвращает число соединений на этом экземпляре.
+func (h *Hub) JoinUserRoom(userID, roomID int) int {
+	ev := Event{Kind: EventJoinUserRoom, UserID: userID, RoomID: roomID}
+	count := h.deliver(ev)
+	h.publish(ev)
+	return count
+}
+
+// LeaveUserRoom отключает от комнаты все соединения пользователя на всех экземплярах.
+// Возвращает число соединений на этом экземпляре.
+func (h *Hub) LeaveUserRoom(userID, roomID int) int {
+	ev := Event{Kind: EventLeaveUserRoom, UserID: userID, RoomID: roomID}
+	count := h.deliver(ev)
+	h.publish(ev)
+	return count
+}
+
+// InRoom сообщает, подключено ли соединение к комнате
+func (h *Hub) InRoom(client *Client, roomID int) bool {
+	in := false
+	h.do(func() { in = client.rooms[roomID] })
+	return in
+}
+
+func (h *Hub) join(client *Client, roomID int) {
+	if !h.clients[client] {
+		return
+	}
+	if h.rooms[roomID] == nil {
+		h.rooms[roomID] = make(map[*Client]bool)
+	}
+	h.rooms[roomID][client] = true
+	client.rooms[roomID] = true
+}
+
+func (h *Hub) leave(client *Client, roomID int) {
+	delete(client.rooms, roomID)
+	delete(h.rooms[roomID], client)
+	if len(h.rooms[roomID]) == 0 {
+		delete(h.rooms, roomID)
+	}
+}
+
+// Subscribe подписывает соединение на тему. Возвращает false, если достигнут лимит подписок.
+func (h *Hub) Subscribe(client *Client, topic string) bool {
+	ok := false
+	h.do(func() {
+		if !h.clients[client] {
+			return
+		}
+		if client.topics[topic] {
+			ok = true
+			return
+		}
+		if len(client.topics) >= h.cfg.MaxSubscriptions {
+			return
+		}
+		if h.topics[topic] == nil {
+			h.topics[topic] = make(map[*Client]bool)
+		}
+		h.topics[topic][client] = true
+		client.topics[topic] = true
+		ok = true
+	})
+	return ok
+}
+
+// Unsubscribe отписывает соединение от темы
+func (h *Hub) Unsubscribe(client *Client, topic string) {
+	h.do(func() { h.unsubscribe(client, topic) })
+}
+
+func (h *Hub) unsubscribe(client *Client, topic string) {
+	delete(client.topics, topic)
+	delete(h.topics[topic], client)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+// Subscribers возвращает число соединений, подписанных на тему
+func (h *Hub) Subscribers(topic string) int {
+	count := 0
+	h.do(func() { count = len(h.topics[topic]) })
+	return count
+}
+
+// IsOnline сообщает, есть ли у пользователя хотя бы одно открытое соединение
+// на этом или другом экземпляре
+func (h *Hub) IsOnline(userID int) bool {
+	online := false
+	h.do(func() { online = len(h.users[userID]) > 0 || h.remoteStatus(userID) != StatusOffline })
+	return online
+}
+
+// ConnectionCount возвращает число открытых соединений пользователя на этом экземпляре
+func (h *Hub) ConnectionCount(userID int) int {
+	count := 0
+	h.do(func() { count = len(h.users[userID]) })
+	return count
+}
+
+// UserClients возвращает открытые соединения пользователя
+func (h *Hub) UserClients(userID int) []*Client {
+	var clients []*Client
+	h.do(func() { clients = members(h.users[userID]) })
+	return clients
+}
+
+// BroadcastRoom отправляет payload всем соединениям комнаты. Возвращает число
+// получателей на этом экземпляре.
+func (h *Hub) BroadcastRoom(roomID int, payload interface{}) int {
+	return h.fanout(Event{Kind: EventRoom, RoomID: roomID}, payload)
+}
+
+// Publish отправляет payload всем подписчикам темы. Возвращает число получателей
+// на этом экземпляре.
+func (h *Hub) Publish(topic string, payload interface{}) int {
+	return h.fanout(Event{Kind: EventTopic, Topic: topic}, payload)
+}
+
+// SendToUser отправляет payload всем соединениям пользователя. Возвращает число
+// получателей на этом экземпляре.
+func (h *Hub) SendToUser(userID int, payload interface{}) int {
+	return h.fanout(Event{Kind: EventUser, UserID: userID}, payload)
+}
+
+// SendToUserInRoom отправляет payload тем соединениям пользователя, которые подключены
+// к комнате (например, упоминание из приватной комнаты)
+func (h *Hub) SendToUserInRoom(userID, roomID int, payload interface{}) int {
+	return h.fanout(Event{Kind: EventUserInRoom, UserID: userID, RoomID: roomID}, payload)
+}
+
+// SendToClient отправляет payload одному соединению, например ответ на его запрос
+func (h *Hub) SendToClient(client *Client, payload interface{}) bool {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error encoding outgoing message: %v", err)
+		return false
+	}
+	delivered := 0
+	h.do(func() {
+		if h.clients[client] {
+			delivered = h.enqueueAll([]*Client{client}, data)
+		}
+	})
+	return delivered > 0
+}
+
+// SendEnvelope отправляет кадр протокола одному соединению как есть, без упаковки:
+// ответы ack и error на запросы клиента
+func (h *Hub) SendEnvelope(client *Client, env protocol.Envelope) bool {
+	data, err := json.Marshal(env)
+	if err != nil {
+		log.Printf("Error encoding outgoing frame: %v", err)
+		return false
+	}
+	ok := false
+	h.do(func() {
+		if h.clients[client] {
+			ok = h.enqueue(client, data)
+		}
+	})
+	return ok
+}
+
+// fanout доставляет событие локальным соединениям и публикует его для остальных экземпляров
+func (h *Hub) fanout(ev Event, payload interface{}) int {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error encoding outgoing message: %v", err)
+		return 0
+	}
+	ev.Payload = data
+	delivered := h.deliver(ev)
+	h.publish(ev)
+	return delivered
+}
+
+// deliver применяет событие к соединениям этого экземпляра и возвращает число затронутых
+func (h *Hub) deliver(ev Event) int {
+	count := 0
+	h.do(func() { count = h.apply(ev) })
+	return count
+}
+
+// apply выполняется в горутине хаба
+func (h *Hub) apply(ev Event) int {
+	var targets []*Client
+	switch ev.Kind {
+	case EventRoom:
+		targets = members(h.rooms[ev.RoomID])
+	case EventTopic:
+		targets = members(h.topics[ev.Topic])
+	case EventUser:
+		targets = members(h.users[ev.UserID])
+	case EventUserInRoom:
+		for client := range h.users[ev.UserID] {
+			if client.rooms[ev.RoomID] {
+				targets = append(targets, client)
+			}
+		}
+	case EventJoinUserRoom, EventLeaveUserRoom:
+		for client := range h.users[ev.UserID] {
+			if ev.Kind == EventJoinUserRoom {
+				h.join(client, ev.RoomID)
+			} else {
+				h.leave(client, ev.RoomID)
+			}
+		}
+		return len(h.users[ev.UserID])
+	default:
+		return 0
+	}
+
+	return h.enqueueAll(targets, ev.Payload)
+}
+
+// enqueueAll кладет сообщение в очереди получателей. Клиентам с подпротоколом оно
+// уходит в конверте, который собирается один раз на всю рассылку. Возвращает число
+// получателей, которым сообщение поставлено в очередь. Вызывается в горутине хаба.
+func (h *Hub) enqueueAll(targets []*Client, data []byte) int {
+	var wrapped []byte
+	delivered := 0
+	for _, client := range targets {
+		frame := data
+		if client.Protocol != "" {
+			if wrapped == nil {
+				wrapped = protocol.Wrap(data)
+			}
+			frame = wrapped
+		}
+		if h.enqueue(client, frame) {
+			delivered++
+		}
+	}
+	return delivered
+}
+
+func members(set map[*Client]bool) []*Client {
+	clients := make([]*Client, 0, len(set))
+	for client := range set {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// Stats возвращает текущее состояние хаба и счетчики
+func (h *Hub) Stats() Stats {
+	var stats Stats
+	h.do(func() {
+		stats.Connections = len(h.clients)
+		stats.Users = len(h.users)
+		stats.Rooms = len(h.rooms)
+		stats.Topics = len(h.topics)
+		stats.Instances = len(h.remote)
+	})
+	stats.Accepted = atomic.LoadUint64(&h.accepted)
+	stats.Rejected = atomic.LoadUint64(&h.rejected)
+	stats.MessagesSent = atomic.LoadUint64(&h.sent)
+	stats.SlowConsumers = atomic.LoadUint64(&h.slowConsumers)
+	stats.WriteErrors = atomic.LoadUint64(&h.writeErrors)
+	stats.EventsPublished = atomic.LoadUint64(&h.published)
+	stats.EventsReceived = atomic.LoadUint64(&h.received)
+	stats.BackplaneErrors = atomic.LoadUint64(&h.publishErrors)
+	stats.BackplaneDropped = atomic.LoadUint64(&h.dropped)
+	return stats
+}
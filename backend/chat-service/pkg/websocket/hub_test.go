@@ -0,0 +1,179 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer принимает WebSocket соединения и отдает их серверную сторону в accepted
+func testServer(t *testing.T) (*httptest.Server, chan *websocket.Conn) {
+	accepted := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrader.Upgrade(w, r, nil)
+		if err == nil {
+			accepted <- conn
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, accepted
+}
+
+// dial открывает соединение и возвращает клиентскую и серверную стороны
+func dial(t *testing.T, server *httptest.Server, accepted chan *websocket.Conn) (*websocket.Conn, *websocket.Conn) {
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client, <-accepted
+}
+
+func readText(t *testing.T, ws *websocket.Conn) string {
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := ws.ReadMessage()
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestHub_MultipleConnectionsPerUser(t *testing.T) {
+	hub := NewHub(HubConfig{})
+	defer hub.Stop()
+	server, accepted := testServer(t)
+
+	desktop, desktopConn := dial(t, server, accepted)
+	phone, phoneConn := dial(t, server, accepted)
+
+	first, err := hub.Register(desktopConn, 7)
+	require.NoError(t, err)
+	second, err := hub.Register(phoneConn, 7)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.Equal(t, 2, hub.ConnectionCount(7))
+
+	assert.Equal(t, 2, hub.SendToUser(7, map[string]string{"type": "notification"}))
+	assert.JSONEq(t, `{"type":"notification"}`, readText(t, desktop))
+	assert.JSONEq(t, `{"type":"notification"}`, readText(t, phone))
+
+	// Ответ на запрос получает только одно соединение
+	assert.True(t, hub.SendToClient(second, map[string]string{"type": "ack"}))
+	assert.JSONEq(t, `{"type":"ack"}`, readText(t, phone))
+
+	hub.Unregister(first)
+	assert.Equal(t, 1, hub.ConnectionCount(7))
+	assert.False(t, hub.SendToClient(first, map[string]string{"type": "ack"}))
+
+	// Хаб закрывает соединение сам
+	desktop.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err = desktop.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+	assert.True(t, hub.IsOnline(7))
+}
+
+func TestHub_ConnectionLimit(t *testing.T) {
+	hub := NewHub(HubConfig{MaxConnectionsPerUser: 2})
+	defer hub.Stop()
+	server, accepted := testServer(t)
+
+	for i := 0; i < 2; i++ {
+		_, conn := dial(t, server, accepted)
+		_, err := hub.Register(conn, 8)
+		require.NoError(t, err)
+	}
+	_, extra := dial(t, server, accepted)
+	_, err := hub.Register(extra, 8)
+	assert.ErrorIs(t, err, ErrTooManyConnections)
+	extra.Close()
+
+	stats := hub.Stats()
+	assert.Equal(t, 2, stats.Connections)
+	assert.Equal(t, 1, stats.Users)
+	assert.Equal(t, uint64(2), stats.Accepted)
+	assert.Equal(t, uint64(1), stats.Rejected)
+}
+
+func TestHub_RoomsAndTopics(t *testing.T) {
+	hub := NewHub(HubConfig{MaxSubscriptions: 1})
+	defer hub.Stop()
+	server, accepted := testServer(t)
+
+	alice, aliceConn := dial(t, server, accepted)
+	_, bobConn := dial(t, server, accepted)
+	a, err := hub.Register(aliceConn, 1)
+	require.NoError(t, err)
+	b, err := hub.Register(bobConn, 2)
+	require.NoError(t, err)
+
+	hub.JoinRoom(a, 4)
+	assert.Equal(t, 1, hub.JoinUserRoom(2, 4))
+	assert.True(t, hub.InRoom(b, 4))
+	assert.Equal(t, 2, hub.BroadcastRoom(4, map[string]int{"room_id": 4}))
+
+	// Упоминание доходит только до соединений, подключенных к комнате
+	assert.Equal(t, 1, hub.SendToUserInRoom(1, 4, map[string]string{"type": "mention"}))
+	assert.Equal(t, 0, hub.SendToUserInRoom(1, 5, map[string]string{"type": "mention"}))
+	assert.JSONEq(t, `{"room_id":4}`, readText(t, alice))
+	assert.JSONEq(t, `{"type":"mention"}`, readText(t, alice))
+
+	assert.True(t, hub.Subscribe(a, "poll:1"))
+	assert.True(t, hub.Subscribe(a, "poll:1"))
+	assert.False(t, hub.Subscribe(a, "poll:2"))
+	assert.Equal(t, 1, hub.Publish("poll:1", map[string]string{"type": "poll_results"}))
+	assert.JSONEq(t, `{"type":"poll_results"}`, readText(t, alice))
+
+	// Отключение убирает соединение из комнат и тем
+	hub.Unregister(a)
+	assert.Equal(t, 0, hub.Subscribers("poll:1"))
+	assert.Equal(t, 1, hub.BroadcastRoom(4, map[string]int{"room_id": 4}))
+	assert.Equal(t, 1, hub.LeaveUserRoom(2, 4))
+	assert.Equal(t, 0, hub.Stats().Rooms)
+}
+
+func TestHub_EvictsSlowConsumer(t *testing.T) {
+	hub := NewHub(HubConfig{})
+	defer hub.Stop()
+
+	// Клиент без writePump: очередь никто не разбирает
+	slow := &Client{ID: "slow", UserID: 3, hub: hub, send: make(chan []byte, 1), rooms: map[int]bool{}, topics: map[string]bool{}}
+	hub.do(func() {
+		hub.clients[slow] = true
+		hub.users[3] = map[*Client]bool{slow: true}
+		hub.join(slow, 1)
+	})
+
+	assert.Equal(t, 1, hub.BroadcastRoom(1, "first"))
+	assert.Equal(t, 0, hub.BroadcastRoom(1, "second"))
+	assert.False(t, hub.IsOnline(3))
+
+	stats := hub.Stats()
+	assert.Equal(t, uint64(1), stats.SlowConsumers)
+	assert.Equal(t, 0, stats.Rooms)
+
+	// Очередь закрыта с причиной отключения; в ней осталось только первое сообщение
+	assert.Equal(t, []byte(`"first"`), <-slow.send)
+	_, open := <-slow.send
+	assert.False(t, open)
+	assert.Equal(t, websocket.ClosePolicyViolation, slow.closeCode)
+}
+
+func TestHub_Stop(t *testing.T) {
+	hub := NewHub(HubConfig{})
+	server, accepted := testServer(t)
+
+	ws, conn := dial(t, server, accepted)
+	_, err := hub.Register(conn, 9)
+	require.NoError(t, err)
+	hub.Stop()
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err = ws.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+
+	_, conn = dial(t, server, accepted)
+	_, err = hub.Register(conn, 9)
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package websocket
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// DefaultBackplaneChannel - канал LISTEN/NOTIFY по умолчанию
+	DefaultBackplaneChannel = "chat_backplane"
+	// maxNotifyPayload - предел полезной нагрузки NOTIFY (8000 байт) с запасом.
+	// Более крупные события кладутся в таблицу chat_backplane_events, а в уведомлении
+	// передается только ссылка на строку.
+	maxNotifyPayload = 7900
+	// eventRefPrefix отмечает уведомление-ссылку: "@<id>"
+	eventRefPrefix = "@"
+	// eventRetention - сколько хранятся крупные события; получатели читают их сразу
+	eventRetention = time.Minute
+)
+
+// PostgresBackplane рассылает события через PostgreSQL LISTEN/NOTIFY, так что хабы
+// нескольких экземпляров chat-service, подключенных к одной базе, видят события друг друга.
+type PostgresBackplane struct {
+	db       *sql.DB
+	channel  string
+	listener *pq.Listener
+
+	mu       sync.Mutex
+	handlers []func(Event)
+}
+
+// NewPostgresBackplane подключается к каналу channel (пустая строка - канал по умолчанию).
+// connStr нужен отдельному соединению LISTEN, публикация идет через db.
+func NewPostgresBackplane(db *sql.DB, connStr, channel string) (*PostgresBackplane, error) {
+	if channel == "" {
+		channel = DefaultBackplaneChannel
+	}
+
+	listener := pq.NewListener(connStr, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Backplane listener event %d: %v", ev, err)
+		}
+	})
+	if err := listener.Listen(channel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("error listening on %s: %w", channel, err)
+	}
+
+	b := &PostgresBackplane{db: db, channel: channel, listener: listener}
+	go b.listen()
+	return b, nil
+}
+
+// Publish отправляет событие через NOTIFY; крупное событие сначала сохраняется в таблицу
+func (b *PostgresBackplane) Publish(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding backplane event: %w", err)
+	}
+
+	payload := string(data)
+	if len(data) > maxNotifyPayload {
+		var id int64
+		err := b.db.QueryRow("INSERT INTO chat_backplane_events (payload) VALUES ($1) RETURNING id", payload).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("error storing backplane event: %w", err)
+		}
+		payload = eventRefPrefix + strconv.FormatInt(id, 10)
+
+		// Заодно убираем события, которые все получатели уже прочитали
+		if _, err := b.db.Exec("DELETE FROM chat_backplane_events WHERE created_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second'", int(eventRetention.Seconds())); err != nil {
+			log.Printf("Error cleaning up backplane events: %v", err)
+		}
+	}
+
+	if _, err := b.db.Exec("SELECT pg_notify($1, $2)", b.channel, payload); err != nil {
+		return fmt.Errorf("error publishing backplane event: %w", err)
+	}
+	return nil
+}
+
+func (b *PostgresBackplane) Subscribe(handler func(Event)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+	return nil
+}
+
+// Close останавливает прослушивание канала
+func (b *PostgresBackplane) Close() error {
+	return b.listener.Close()
+}
+
+func (b *PostgresBackplane) listen() {
+	for n := range b.listener.Notify {
+		// nil приходит после переподключения: уведомления за время разрыва потеряны,
+		// присутствие восстановится со следующим снимком presence
+		if n == nil {
+			log.Printf("Backplane listener reconnected")
+			continue
+		}
+		event, err := b.decode(n.Extra)
+		if err != nil {
+			log.Printf("Error decoding backplane event: %v", err)
+			continue
+		}
+
+		b.mu.Lock()
+		handlers := b.handlers
+		b.mu.Unlock()
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+}
+
+// decode разбирает уведомление; для ссылки "@<id>" событие читается из таблицы
+func (b *PostgresBackplane) decode(payload string) (Event, error) {
+	var event Event
+	if strings.HasPrefix(payload, eventRefPrefix) {
+		id, err := strconv.ParseInt(strings.TrimPrefix(payload, eventRefPrefix), 10, 64)
+		if err != nil {
+			return event, fmt.Errorf("invalid event reference %q", payload)
+		}
+		if err := b.db.QueryRow("SELECT payload FROM chat_backplane_events WHERE id = $1", id).Scan(&payload); err != nil {
+			return event, fmt.Errorf("error loading backplane event %d: %w", id, err)
+		}
+	}
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return event, err
+	}
+	return event, nil
+}
@@ -0,0 +1,70 @@
+package websocket
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresBackplane_Publish(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	b := &PostgresBackplane{db: db, channel: DefaultBackplaneChannel}
+
+	t.Run("small event goes inline", func(t *testing.T) {
+		event := Event{Origin: "a", Kind: EventUser, UserID: 3, Payload: json.RawMessage(`{"type":"ping"}`)}
+		data, _ := json.Marshal(event)
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_notify($1, $2)")).
+			WithArgs(DefaultBackplaneChannel, string(data)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, b.Publish(event))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("large event is stored and referenced", func(t *testing.T) {
+		payload, _ := json.Marshal(strings.Repeat("x", maxNotifyPayload))
+		event := Event{Origin: "a", Kind: EventRoom, RoomID: 1, Payload: payload}
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO chat_backplane_events (payload) VALUES ($1) RETURNING id")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM chat_backplane_events WHERE created_at <")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_notify($1, $2)")).
+			WithArgs(DefaultBackplaneChannel, "@42").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, b.Publish(event))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresBackplane_Decode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	b := &PostgresBackplane{db: db, channel: DefaultBackplaneChannel}
+
+	event, err := b.decode(`{"origin":"a","kind":"online","user_id":5}`)
+	assert.NoError(t, err)
+	assert.Equal(t, Event{Origin: "a", Kind: EventOnline, UserID: 5}, event)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT payload FROM chat_backplane_events WHERE id = $1")).
+		WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"payload"}).AddRow(`{"origin":"a","kind":"room","room_id":1,"payload":"hi"}`))
+	event, err = b.decode("@42")
+	assert.NoError(t, err)
+	assert.Equal(t, EventRoom, event.Kind)
+	assert.JSONEq(t, `"hi"`, string(event.Payload))
+
+	_, err = b.decode("@abc")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
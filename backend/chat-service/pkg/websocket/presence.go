@@ -0,0 +1,160 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+)
+
+// Статусы присутствия пользователя
+const (
+	StatusOnline  = "online"
+	StatusAway    = "away"
+	StatusOffline = "offline"
+)
+
+// PresenceEvent рассылается подписчикам темы PresenceTopic, когда меняется статус пользователя
+type PresenceEvent struct {
+	Type   string `json:"type"`
+	UserID int    `json:"user_id"`
+	Status string `json:"status"`
+	// LastSeen - момент, когда пользователь ушел offline
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+}
+
+// PresenceTopic - тема, на которую подписываются, чтобы следить за статусом пользователя
+func PresenceTopic(userID int) string {
+	return "presence:" + strconv.Itoa(userID)
+}
+
+// Touch отмечает активность соединения: любой кадр клиента, кроме ping/pong.
+// Соединение, которое само сообщило, что отошло, остается away до SetAway(false).
+func (h *Hub) Touch(client *Client) {
+	h.do(func() {
+		if !h.clients[client] {
+			return
+		}
+		client.lastActive = time.Now()
+		h.refreshLocal(client.UserID)
+	})
+}
+
+// SetAway задает статус, о котором сообщил клиент: away=true - пользователь отошел
+// (например, вкладка в фоне), false - вернулся
+func (h *Hub) SetAway(client *Client, away bool) {
+	h.do(func() {
+		if !h.clients[client] {
+			return
+		}
+		client.away = away
+		if !away {
+			client.lastActive = time.Now()
+		}
+		h.refreshLocal(client.UserID)
+	})
+}
+
+// Presence возвращает статусы пользователей с учетом других экземпляров.
+// Пользователи без соединений получают StatusOffline.
+func (h *Hub) Presence(userIDs []int) map[int]string {
+	statuses := make(map[int]string, len(userIDs))
+	ok := h.do(func() {
+		for _, userID := range userIDs {
+			statuses[userID] = h.status(userID)
+		}
+	})
+	if !ok {
+		for _, userID := range userIDs {
+			statuses[userID] = StatusOffline
+		}
+	}
+	return statuses
+}
+
+// localStatus вычисляет статус пользователя по соединениям этого экземпляра:
+// online, если хотя бы одно из них активно. Вызывается в горутине хаба.
+func (h *Hub) localStatus(userID int) string {
+	clients := h.users[userID]
+	if len(clients) == 0 {
+		return StatusOffline
+	}
+	deadline := time.Now().Add(-h.cfg.AwayAfter)
+	for client := range clients {
+		if !client.away && client.lastActive.After(deadline) {
+			return StatusOnline
+		}
+	}
+	return StatusAway
+}
+
+// status объединяет статус на этом экземпляре со статусами на других:
+// online важнее away, away важнее offline. Вызывается в горутине хаба.
+func (h *Hub) status(userID int) string {
+	local := h.localStatus(userID)
+	if local == StatusOnline {
+		return local
+	}
+	remote := h.remoteStatus(userID)
+	if remote != StatusOffline {
+		return remote
+	}
+	return local
+}
+
+// refreshLocal пересчитывает статус пользователя на этом экземпляре и, если он изменился,
+// публикует его в Backplane и уведомляет подписчиков. Вызывается в горутине хаба.
+func (h *Hub) refreshLocal(userID int) {
+	status := h.localStatus(userID)
+	previous, ok := h.local[userID]
+	if !ok {
+		previous = StatusOffline
+	}
+	if status == previous {
+		return
+	}
+
+	if status == StatusOffline {
+		delete(h.local, userID)
+		h.publish(Event{Kind: EventOffline, UserID: userID})
+	} else {
+		h.local[userID] = status
+		h.publish(Event{Kind: EventOnline, UserID: userID, Status: status})
+	}
+	h.notifyPresence(userID)
+}
+
+// notifyPresence отправляет подписчикам PresenceTopic этого экземпляра общий статус
+// пользователя, если он изменился. Каждый экземпляр уведомляет своих подписчиков сам,
+// поэтому событие не публикуется в Backplane. Вызывается в горутине хаба.
+func (h *Hub) notifyPresence(userID int) {
+	status := h.status(userID)
+	previous, ok := h.notified[userID]
+	if !ok {
+		previous = StatusOffline
+	}
+	if status == previous {
+		return
+	}
+	if status == StatusOffline {
+		delete(h.notified, userID)
+	} else {
+		h.notified[userID] = status
+	}
+
+	subscribers := h.topics[PresenceTopic(userID)]
+	if len(subscribers) == 0 {
+		return
+	}
+	event := PresenceEvent{Type: "presence", UserID: userID, Status: status}
+	if status == StatusOffline {
+		now := time.Now().UTC()
+		event.LastSeen = &now
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error encoding presence event: %v", err)
+		return
+	}
+	h.enqueueAll(members(subscribers), data)
+}
@@ -0,0 +1,86 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readPresence(t *testing.T, data string) PresenceEvent {
+	var event PresenceEvent
+	require.NoError(t, json.Unmarshal([]byte(data), &event))
+	return event
+}
+
+func TestHub_PresenceStatuses(t *testing.T) {
+	hub := NewHub(HubConfig{PresenceInterval: time.Hour, AwayAfter: time.Minute})
+	defer hub.Stop()
+	server, accepted := testServer(t)
+
+	watcher, watcherConn := dial(t, server, accepted)
+	_, desktopConn := dial(t, server, accepted)
+	_, phoneConn := dial(t, server, accepted)
+
+	w, err := hub.Register(watcherConn, 1)
+	require.NoError(t, err)
+	require.True(t, hub.Subscribe(w, PresenceTopic(7)))
+	assert.Equal(t, map[int]string{7: StatusOffline}, hub.Presence([]int{7}))
+
+	desktop, err := hub.Register(desktopConn, 7)
+	require.NoError(t, err)
+	event := readPresence(t, readText(t, watcher))
+	assert.Equal(t, "presence", event.Type)
+	assert.Equal(t, 7, event.UserID)
+	assert.Equal(t, StatusOnline, event.Status)
+
+	// Второе соединение статус не меняет, и подписчик ничего не получает
+	phone, err := hub.Register(phoneConn, 7)
+	require.NoError(t, err)
+
+	// Пользователь away, только когда отошли все его соединения
+	hub.SetAway(desktop, true)
+	assert.Equal(t, StatusOnline, hub.Presence([]int{7})[7])
+	hub.do(func() { phone.lastActive = time.Now().Add(-2 * time.Minute) })
+	hub.do(func() { hub.refreshLocal(7) })
+	assert.Equal(t, StatusAway, readPresence(t, readText(t, watcher)).Status)
+	assert.Equal(t, StatusAway, hub.Presence([]int{7})[7])
+
+	// Активность возвращает online
+	hub.Touch(phone)
+	assert.Equal(t, StatusOnline, readPresence(t, readText(t, watcher)).Status)
+
+	hub.Unregister(desktop)
+	hub.Unregister(phone)
+	event = readPresence(t, readText(t, watcher))
+	assert.Equal(t, StatusOffline, event.Status)
+	require.NotNil(t, event.LastSeen)
+	assert.WithinDuration(t, time.Now(), *event.LastSeen, time.Second)
+}
+
+func TestHub_PresenceFromOtherInstances(t *testing.T) {
+	hub := NewHub(HubConfig{Backplane: NewMemoryBackplane(), PresenceInterval: time.Hour})
+	defer hub.Stop()
+	server, accepted := testServer(t)
+
+	watcher, watcherConn := dial(t, server, accepted)
+	w, err := hub.Register(watcherConn, 1)
+	require.NoError(t, err)
+	require.True(t, hub.Subscribe(w, PresenceTopic(5)))
+
+	hub.receive(Event{Origin: "other", Kind: EventPresence, UserIDs: []int{5, 6}, AwayUserIDs: []int{6}})
+	assert.Equal(t, map[int]string{5: StatusOnline, 6: StatusAway}, hub.Presence([]int{5, 6}))
+	assert.Equal(t, StatusOnline, readPresence(t, readText(t, watcher)).Status)
+
+	// Старые экземпляры присылают online без статуса
+	hub.receive(Event{Origin: "old", Kind: EventOnline, UserID: 6})
+	assert.Equal(t, StatusOnline, hub.Presence([]int{6})[6])
+
+	hub.receive(Event{Origin: "other", Kind: EventOnline, UserID: 5, Status: StatusAway})
+	assert.Equal(t, StatusAway, readPresence(t, readText(t, watcher)).Status)
+
+	hub.receive(Event{Origin: "other", Kind: EventOffline, UserID: 5})
+	assert.Equal(t, StatusOffline, readPresence(t, readText(t, watcher)).Status)
+}
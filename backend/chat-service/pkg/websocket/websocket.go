@@ -1,117 +1,49 @@
 package websocket
 
 import (
-	"log"
 	"net/http"
-	"sync"
 	"time"
 
-	"github.com/jaliks17/ffffforum/backend/chat-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/chat-service/pkg/protocol"
 
 	"github.com/gorilla/websocket"
 )
 
-var (
-	Upgrader = websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-		// Настройка таймаутов
-		HandshakeTimeout: 10 * time.Second,
-		// Проверка origin
-		CheckOrigin: func(r *http.Request) bool {
-			return true // В продакшене нужно настроить правильную проверку origin
-		},
-	}
-
-	Clients    = make(map[*websocket.Conn]int)
-	UserConns  = make(map[int]*websocket.Conn) // Map to track user connections
-	// Добавляем обратную карту для быстрого поиска userID по соединению
-	ConnUserIDs = make(map[*websocket.Conn]int) // Map to track userID by connection
-	Broadcast  = make(chan entity.Message)
-	mu         sync.Mutex
-)
-
-// CloseConnection закрывает WebSocket соединение и удаляет клиента из пула
-func CloseConnection(conn *websocket.Conn) {
-	mu.Lock()
-	defer mu.Unlock()
-
-	// Получаем userID по соединению из обратной карты и удаляем запись
-	if userID, exists := ConnUserIDs[conn]; exists {
-		delete(UserConns, userID)
-		delete(ConnUserIDs, conn)
-	}
-
-	// Удаляем из Clients map
-	delete(Clients, conn)
-
-	log.Printf("Client removed from pool. Total clients: %d", len(Clients))
-	// Закрываем само соединение
-	conn.Close()
-}
-
-// GetClientConnection returns the WebSocket connection for a given user ID
-func GetClientConnection(userID int) *websocket.Conn {
-	mu.Lock() // Добавляем блокировку при чтении
-	defer mu.Unlock()
-	return UserConns[userID] // Используем UserConns для прямого поиска
+var Upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Настройка таймаутов
+	HandshakeTimeout: 10 * time.Second,
+	// Версии протокола, которые клиент может выбрать через Sec-WebSocket-Protocol
+	Subprotocols: protocol.Subprotocols,
+	// Проверка origin
+	CheckOrigin: func(r *http.Request) bool {
+		return true // В продакшене нужно настроить правильную проверку origin
+	},
 }
 
-// AddClient добавляет нового клиента в пул
-func AddClient(conn *websocket.Conn, userID int) bool {
-	mu.Lock()
-	defer mu.Unlock()
-
-	// Check if user already has a connection
-	if existingConn, exists := UserConns[userID]; exists {
-		log.Printf("User %d already has an active connection. Closing old connection before adding new one.", userID)
-		// Close the old connection gracefully
-		existingConn.WriteControl(websocket.CloseMessage, 
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, "Connection replaced by new client"), 
-			time.Now().Add(time.Second))
-		existingConn.Close()
-		// Remove old connection from maps
-		delete(Clients, existingConn)
-		delete(UserConns, userID)
-		delete(ConnUserIDs, existingConn)
-	}
-
-	// Add new connection
-	Clients[conn] = userID
-	UserConns[userID] = conn
-	ConnUserIDs[conn] = userID
-	log.Printf("New client added to pool. Total clients: %d", len(Clients))
-	return true
-}
-
-// SendMessage отправляет сообщение конкретному клиенту
-func SendMessage(ws *websocket.Conn, msg entity.Message) error {
-	// Добавляем блокировку при отправке, чтобы избежать состояния гонки при параллельном доступе к соединению
-	// Это может потребовать изменений в Client структуре или Pool логике для более гранулярной блокировки,
-	// но для начала попробуем простую блокировку здесь.
-	// Внимание: Блокировка здесь может вызвать проблемы с производительностью/блокировками,
-	// если SendMessage вызывается часто или из разных горутин без дополнительной синхронизации.
-	// mu.Lock()
-	// defer mu.Unlock()
-	// Рекомендуется управлять Write на каждое соединение в отдельной горутине клиента.
-	return ws.WriteJSON(msg)
-}
-
-// BroadcastMessage отправляет сообщение всем клиентам
-func BroadcastMessage(message entity.Message) {
-	mu.Lock()
-	defer mu.Unlock()
-
-	for client := range Clients {
-		// Отправку сообщения клиенту лучше выполнять в отдельной горутине,
-		// чтобы BroadcastMessage не блокировалась при проблемах с одним клиентом.
-		// Также это позволяет избежать проблем с блокировкой мьютекса `mu` во время SendMessage.
-		go func(c *websocket.Conn) {
-			if err := SendMessage(c, message); err != nil {
-				log.Printf("Error broadcasting message: %v", err)
-				// Если при отправке возникает ошибка, закрываем соединение
-				CloseConnection(c)
-			}
-		}(client) // Запускаем горутину для каждого клиента
-	}
-}
\ No newline at end of file
+const (
+	// MaxSubscriptions - сколько тем может одновременно слушать одно соединение
+	MaxSubscriptions = 50
+	// MaxConnectionsPerUser - сколько соединений одновременно может открыть один пользователь
+	MaxConnectionsPerUser = 10
+	// SendQueueSize - сколько исходящих сообщений может ждать отправки в одном соединении.
+	// Клиент, который не успевает их забирать, отключается.
+	SendQueueSize = 256
+
+	// WriteWait - сколько ждать записи одного сообщения
+	WriteWait = 10 * time.Second
+	// PongWait - сколько ждать pong или любого сообщения от клиента
+	PongWait = 120 * time.Second
+	// PingPeriod - как часто отправлять ping; должен быть меньше PongWait
+	PingPeriod = 30 * time.Second
+
+	// PresenceInterval - как часто хаб рассылает через Backplane список пользователей в сети.
+	// Экземпляр, от которого три интервала не было снимка, считается остановленным.
+	PresenceInterval = 30 * time.Second
+	// OutboxSize - сколько событий может ждать публикации в Backplane
+	OutboxSize = 1024
+	// AwayAfter - через сколько без активности клиента соединение считается отошедшим (away).
+	// Ping/pong активностью не считается: он лишь подтверждает, что соединение живо.
+	AwayAfter = 5 * time.Minute
+)
@@ -16,7 +16,11 @@ import (
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/handler"
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/blobstore"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/chatpush"
 	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/mailer"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/spam"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -40,6 +44,11 @@ func main() {
 
 	// Загрузка конфигурации
 	cfg := config.NewConfig()
+	// Без общего секрета группа /internal отклоняет все запросы, и сервисы не смогут работать вместе
+	if cfg.InternalToken == "" {
+		log.Error("INTERNAL_TOKEN is required")
+		os.Exit(1)
+	}
 
 	// Подключение к PostgreSQL
 	db, err := sqlx.Connect("postgres", cfg.GetDBConnString())
@@ -61,7 +70,7 @@ func main() {
 	// Настройка CORS
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Authorization", "Content-Type"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
@@ -88,10 +97,166 @@ func main() {
 	commentRepo := repository.NewCommentRepository(db)
 	postUsecase := usecase.NewPostUsecase(postRepo, authClient, log)
 	commentUC := usecase.NewCommentUseCase(commentRepo, postRepo, authClient)
+	commentUC.EditWindow = cfg.CommentEditWindow
+	voteRepo := repository.NewVoteRepository(db)
+	voteUC := usecase.NewVoteUsecase(voteRepo, authClient, log)
+	revisionRepo := repository.NewRevisionRepository(db)
+	revisionUC := usecase.NewRevisionUsecase(revisionRepo, postRepo, commentRepo, authClient)
+	trashRepo := repository.NewTrashRepository(db)
+	trashUC := usecase.NewTrashUsecase(trashRepo, authClient, cfg.TrashRetention)
+	chatClient := chatpush.NewClient(cfg.ChatPushURL, cfg.InternalToken)
+	chatClient.PublishURL = cfg.ChatPublishURL
+	chatClient.MessagesURL = cfg.ChatMessagesURL
+	chatClient.RoomsURL = cfg.ChatRoomsURL
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationUC := usecase.NewNotificationUsecase(
+		notificationRepo,
+		postRepo,
+		authClient,
+		chatClient,
+		log,
+	)
+	mentionRepo := repository.NewMentionRepository(db)
+	mentionUC := usecase.NewMentionUsecase(mentionRepo, authClient, notificationUC, log)
+	postUsecase.Mentions = mentionUC
+	commentUC.Mentions = mentionUC
+	commentUC.Notifications = notificationUC
+	voteUC.Notifications = notificationUC
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	subscriptionUC := usecase.NewSubscriptionUsecase(
+		subscriptionRepo,
+		postRepo,
+		authClient,
+		newMailSender(cfg),
+		cfg.PublicURL,
+		log,
+	)
+	postUsecase.Subscriptions = subscriptionUC
+	bookmarkRepo := repository.NewBookmarkRepository(db)
+	bookmarkUC := usecase.NewBookmarkUsecase(bookmarkRepo, postRepo, commentRepo, authClient)
+	tagUC := usecase.NewTagUsecase(repository.NewTagRepository(db), postRepo, authClient)
+	postUsecase.Tags = tagUC
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		log.Error("Failed to init blob store", err)
+		os.Exit(1)
+	}
+	attachmentUC := usecase.NewAttachmentUsecase(repository.NewAttachmentRepository(db), blobStore, authClient, log)
+	attachmentUC.MaxSize = cfg.MaxUploadSize
+	attachmentUC.Rooms = chatClient
+	postUsecase.Attachments = attachmentUC
+	commentUC.Attachments = attachmentUC
+	pollUC := usecase.NewPollUsecase(repository.NewPollRepository(db), postRepo, authClient, chatClient, log)
+	moderationUC := usecase.NewModerationUsecase(repository.NewModerationRepository(db), authClient)
+	reportUC := usecase.NewReportUsecase(repository.NewReportRepository(db), postRepo, commentRepo, authClient, log)
+	reportUC.Chat = chatClient
+	reportUC.Warnings = notificationUC
+	filterUC := usecase.NewContentFilterUsecase(repository.NewContentFilterRepository(db), authClient, log)
+	filterUC.Classifier = spam.NewNaiveBayes()
+	filterUC.NewAccountAge = cfg.FilterNewAccountAge
+	filterUC.NewAccountMaxLinks = int(cfg.FilterNewAccountMaxLinks)
+	filterUC.DuplicateWindow = cfg.FilterDuplicateWindow
+	filterUC.SpamThreshold = cfg.SpamThreshold
+	if samples, err := filterUC.LoadModel(context.Background()); err != nil {
+		log.Error("Failed to load spam samples", err)
+	} else {
+		log.Infof("Spam classifier trained on %d samples", samples)
+	}
+	postUsecase.Filter = filterUC
+	commentUC.Filter = filterUC
+	reportUC.Learning = filterUC
 
 	// Регистрация обработчиков
 	postHandler := handler.NewPostHandler(postUsecase, log)
+	postHandler.Bookmarks = bookmarkUC
 	commentHandler := handler.NewCommentHandler(commentUC)
+	voteHandler := handler.NewVoteHandler(voteUC, log)
+	revisionHandler := handler.NewRevisionHandler(revisionUC, log)
+	trashHandler := handler.NewTrashHandler(trashUC, log)
+	notificationHandler := handler.NewNotificationHandler(notificationUC, log)
+	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionUC, log)
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkUC, log)
+	tagHandler := handler.NewTagHandler(tagUC, log)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentUC, log)
+	pollHandler := handler.NewPollHandler(pollUC, log)
+	moderationHandler := handler.NewModerationHandler(moderationUC, log)
+	reportHandler := handler.NewReportHandler(reportUC, log)
+	filterHandler := handler.NewContentFilterHandler(filterUC, log)
+
+	// Фоновый пересчет рейтинга постов (комментарии учитываются только здесь)
+	go func() {
+		ticker := time.NewTicker(cfg.ScoreRefreshInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := voteUC.RefreshScores(context.Background()); err != nil {
+				log.Error("Failed to refresh post scores", err)
+			}
+		}
+	}()
+
+	// Окончательное удаление объектов, срок хранения которых в корзине истек
+	go func() {
+		ticker := time.NewTicker(cfg.TrashPurgeInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purged, err := trashUC.PurgeExpired(context.Background())
+			if err != nil {
+				log.Error("Failed to purge trash", err)
+				continue
+			}
+			if purged > 0 {
+				log.Infof("Purged %d expired trash items", purged)
+			}
+		}
+	}()
+
+	// Рассылка дайджестов новых комментариев по подпискам
+	go func() {
+		ticker := time.NewTicker(cfg.DigestInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			sent, err := subscriptionUC.SendDigests(context.Background())
+			if err != nil {
+				log.Error("Failed to send digests", err)
+				continue
+			}
+			if sent > 0 {
+				log.Infof("Sent %d digests", sent)
+			}
+		}
+	}()
+
+	// Закрытие опросов с истекшим сроком и рассылка итоговых результатов
+	go func() {
+		ticker := time.NewTicker(cfg.PollCloseInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			closed, err := pollUC.CloseExpiredPolls(context.Background())
+			if err != nil {
+				log.Error("Failed to close expired polls", err)
+				continue
+			}
+			if closed > 0 {
+				log.Infof("Closed %d expired polls", closed)
+			}
+		}
+	}()
+
+	// Удаление отпечатков текста, вышедших за окно поиска дубликатов
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := filterUC.PurgeFingerprints(context.Background()); err != nil {
+				log.Error("Failed to purge content fingerprints", err)
+			}
+		}
+	}()
 
 	// Группировка роутов
 	api := router.Group("/api/v1")
@@ -103,6 +268,20 @@ func main() {
 			posts.GET("", postHandler.GetPosts)
 			posts.DELETE("/:id", postHandler.DeletePost)
 			posts.PUT("/:id", postHandler.UpdatePost)
+			posts.POST("/:id/vote", voteHandler.VotePost)
+			posts.POST("/:id/restore", trashHandler.RestorePost)
+			posts.POST("/:id/subscribe", subscriptionHandler.Subscribe)
+			posts.DELETE("/:id/subscribe", subscriptionHandler.Unsubscribe)
+			posts.POST("/:id/bookmark", bookmarkHandler.BookmarkPost)
+			posts.DELETE("/:id/bookmark", bookmarkHandler.UnbookmarkPost)
+			posts.PUT("/:id/tags", tagHandler.SetPostTags)
+			posts.GET("/:id/poll", pollHandler.GetPoll)
+			posts.POST("/:id/poll/vote", pollHandler.VotePoll)
+			posts.POST("/:id/poll/close", pollHandler.ClosePoll)
+			posts.PATCH("/:id/flags", moderationHandler.SetPostFlags)
+			posts.GET("/:id/revisions", revisionHandler.GetPostRevisions)
+			posts.GET("/:id/revisions/diff", revisionHandler.DiffPostRevisions)
+			posts.POST("/:id/revisions/:revision/restore", revisionHandler.RestorePostRevision)
 		}
 
 		// Роуты для комментариев, привязанных к посту
@@ -112,8 +291,75 @@ func main() {
 			comments.GET("", commentHandler.GetCommentsByPostID)
 		}
 
+		// Правка комментария автором или модератором
+		api.PUT("/comments/:id", commentHandler.UpdateComment)
+		api.DELETE("/comments/:id", commentHandler.DeleteComment)
+		api.POST("/comments/:id/bookmark", bookmarkHandler.BookmarkComment)
+		api.DELETE("/comments/:id/bookmark", bookmarkHandler.UnbookmarkComment)
+
+		// Корзина: удаленные посты и комментарии
+		api.GET("/trash", trashHandler.GetTrash)
+		api.POST("/comments/:id/restore", trashHandler.RestoreComment)
+
+		// Входящие уведомления текущего пользователя
+		api.GET("/notifications", notificationHandler.GetNotifications)
+		api.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+		api.POST("/notifications/:id/read", notificationHandler.MarkRead)
+
+		// Подписки и настройки дайджеста текущего пользователя
+		api.GET("/me/subscriptions", subscriptionHandler.GetSubscriptions)
+		api.GET("/me/digest", subscriptionHandler.GetDigestSettings)
+		api.PUT("/me/digest", subscriptionHandler.UpdateDigestSettings)
+
+		// Закладки текущего пользователя
+		api.GET("/me/bookmarks", bookmarkHandler.GetBookmarks)
+		api.GET("/me/bookmarks/folders", bookmarkHandler.GetFolders)
+
+		// Теги: подсказки для всех, синонимы и слияние - для модераторов
+		api.GET("/tags", tagHandler.SearchTags)
+		api.GET("/tags/:slug", tagHandler.GetTag)
+		api.POST("/tags/:slug/synonyms", tagHandler.AddSynonym)
+		api.DELETE("/tags/:slug/synonyms/:synonym", tagHandler.DeleteSynonym)
+		api.POST("/tags/:slug/merge", tagHandler.MergeTags)
+
+		// Журнал действий модераторов
+		api.GET("/mod/log", moderationHandler.GetModerationLog)
+
+		// Жалобы пользователей и очередь модерации
+		api.POST("/reports", reportHandler.CreateReport)
+		api.GET("/mod/reports", reportHandler.GetReports)
+		api.POST("/mod/reports/:type/:id/resolve", reportHandler.ResolveReports)
+
+		// Правила фильтра контента (только для администраторов)
+		api.GET("/admin/filter/rules", filterHandler.GetRules)
+		api.POST("/admin/filter/rules", filterHandler.CreateRule)
+		api.DELETE("/admin/filter/rules/:id", filterHandler.DeleteRule)
+
+		// Вложения: загрузка до публикации поста или комментария, раздача и удаление
+		api.POST("/attachments", attachmentHandler.UploadAttachment)
+		api.GET("/attachments/:id", attachmentHandler.GetAttachment)
+		api.GET("/attachments/:id/thumbnail", attachmentHandler.GetThumbnail)
+		api.DELETE("/attachments/:id", attachmentHandler.DeleteAttachment)
+
+		// Отписка по ссылке из письма: GET для перехода, POST для one-click (RFC 8058)
+		api.GET("/digest/unsubscribe", subscriptionHandler.UnsubscribeDigest)
+		api.POST("/digest/unsubscribe", subscriptionHandler.UnsubscribeDigest)
+
 		// Роут для лайка комментария
 		api.POST("/comments/:id/like", commentHandler.LikeComment)
+
+		// История правок комментария
+		api.GET("/comments/:id/revisions", revisionHandler.GetCommentRevisions)
+		api.GET("/comments/:id/revisions/diff", revisionHandler.DiffCommentRevisions)
+		api.POST("/comments/:id/revisions/:revision/restore", revisionHandler.RestoreCommentRevision)
+	}
+
+	// Внутренние endpoint-ы для других сервисов
+	internal := router.Group("/internal", handler.RequireInternalToken(cfg.InternalToken))
+	{
+		internal.POST("/attachments/claim", attachmentHandler.ClaimChatAttachments)
+		internal.POST("/content/check", filterHandler.CheckContent)
+		internal.POST("/content/record", filterHandler.RecordContent)
 	}
 
 	// Запуск сервера
@@ -146,6 +392,28 @@ func main() {
 	log.Info("Server stopped")
 }
 
+// newMailSender выбирает транспорт писем по конфигурации
+func newMailSender(cfg *config.Config) mailer.Sender {
+	if cfg.MailDriver == "smtp" {
+		return mailer.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+	return mailer.NewFileSender(cfg.MailDir, cfg.MailFrom)
+}
+
+// newBlobStore выбирает хранилище вложений по конфигурации
+func newBlobStore(cfg *config.Config) (blobstore.BlobStore, error) {
+	if cfg.BlobDriver == "s3" {
+		return blobstore.NewS3Store(blobstore.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		}), nil
+	}
+	return blobstore.NewLocalStore(cfg.BlobDir)
+}
+
 func runMigrations(dbURL, migrationsPath string, log *logger.Logger) error {
 	m, err := migrate.New(
 		"file://"+migrationsPath,
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -11,6 +13,64 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+
+	// ScoreRefreshInterval - период пересчета рейтинга постов фоновой задачей
+	ScoreRefreshInterval time.Duration
+
+	// CommentEditWindow - сколько времени автор может править свой комментарий (0 - без ограничения)
+	CommentEditWindow time.Duration
+
+	// TrashRetention - срок хранения удаленных постов и комментариев в корзине
+	TrashRetention time.Duration
+	// TrashPurgeInterval - период запуска окончательной очистки корзины
+	TrashPurgeInterval time.Duration
+	// PollCloseInterval - как часто фоновая задача закрывает опросы с истекшим сроком
+	PollCloseInterval time.Duration
+
+	// ChatPushURL - внутренний endpoint чат-сервиса для доставки уведомлений по WebSocket
+	ChatPushURL string
+	// ChatPublishURL - внутренний endpoint чат-сервиса для рассылки подписчикам темы (результаты опросов)
+	ChatPublishURL string
+	// ChatMessagesURL - внутренний endpoint чат-сервиса с сообщениями для очереди жалоб
+	ChatMessagesURL string
+	// ChatRoomsURL - внутренний endpoint чат-сервиса для проверки доступа к вложениям сообщений
+	ChatRoomsURL string
+	// InternalToken - общий секрет для внутренних запросов между сервисами; обязателен
+	InternalToken string
+
+	// PublicURL - внешний адрес форума для ссылок в письмах
+	PublicURL string
+	// DigestInterval - как часто фоновая задача проверяет, кому пора отправить дайджест
+	DigestInterval time.Duration
+
+	// MailDriver - транспорт писем: smtp или file (письма сохраняются в MailDir)
+	MailDriver   string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// BlobDriver - хранилище вложений: local (файлы в BlobDir) или s3 (S3-совместимое хранилище)
+	BlobDriver  string
+	BlobDir     string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// MaxUploadSize - максимальный размер загружаемого файла в байтах
+	MaxUploadSize int64
+
+	// FilterNewAccountAge - аккаунт младше этого возраста считается новым и ограничен по числу ссылок
+	FilterNewAccountAge time.Duration
+	// FilterNewAccountMaxLinks - сколько ссылок можно опубликовать в одной записи с нового аккаунта (-1 - без ограничения)
+	FilterNewAccountMaxLinks int64
+	// FilterDuplicateWindow - окно поиска повторно опубликованного текста (0 - дубликаты не ищутся)
+	FilterDuplicateWindow time.Duration
+	// SpamThreshold - вероятность спама, начиная с которой классификатор откладывает контент на проверку
+	SpamThreshold float64
 }
 
 func NewConfig() *Config {
@@ -20,6 +80,43 @@ func NewConfig() *Config {
 		DBUser:     getEnv("DB_USER", "postgres"),
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "forum_service"),
+
+		ScoreRefreshInterval: getDurationEnv("SCORE_REFRESH_INTERVAL", 5*time.Minute),
+		CommentEditWindow:    getDurationEnv("COMMENT_EDIT_WINDOW", 15*time.Minute),
+		TrashRetention:       getDurationEnv("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval:   getDurationEnv("TRASH_PURGE_INTERVAL", time.Hour),
+		PollCloseInterval:    getDurationEnv("POLL_CLOSE_INTERVAL", time.Minute),
+
+		ChatPushURL:     getEnv("CHAT_PUSH_URL", "http://localhost:8082/internal/push"),
+		ChatPublishURL:  getEnv("CHAT_PUBLISH_URL", "http://localhost:8082/internal/publish"),
+		ChatMessagesURL: getEnv("CHAT_MESSAGES_URL", "http://localhost:8082/internal/messages"),
+		ChatRoomsURL:    getEnv("CHAT_ROOMS_URL", "http://localhost:8082/internal/rooms"),
+		InternalToken:   getEnv("INTERNAL_TOKEN", ""),
+
+		PublicURL:      getEnv("PUBLIC_URL", "http://localhost:8080"),
+		DigestInterval: getDurationEnv("DIGEST_INTERVAL", time.Hour),
+
+		MailDriver:   getEnv("MAIL_DRIVER", "file"),
+		MailFrom:     getEnv("MAIL_FROM", "forum@localhost"),
+		MailDir:      getEnv("MAIL_DIR", "./mail"),
+		SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:     getEnv("SMTP_PORT", "25"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		BlobDriver:    getEnv("BLOB_DRIVER", "local"),
+		BlobDir:       getEnv("BLOB_DIR", "./uploads"),
+		S3Endpoint:    getEnv("S3_ENDPOINT", "http://localhost:9000"),
+		S3Region:      getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:      getEnv("S3_BUCKET", "forum-attachments"),
+		S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+		MaxUploadSize: getInt64Env("MAX_UPLOAD_SIZE", 10<<20),
+
+		FilterNewAccountAge:      getDurationEnv("FILTER_NEW_ACCOUNT_AGE", 72*time.Hour),
+		FilterNewAccountMaxLinks: getInt64Env("FILTER_NEW_ACCOUNT_MAX_LINKS", 2),
+		FilterDuplicateWindow:    getDurationEnv("FILTER_DUPLICATE_WINDOW", 10*time.Minute),
+		SpamThreshold:            getFloatEnv("SPAM_THRESHOLD", 0.95),
 	}
 }
 
@@ -33,4 +130,31 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-} 
\ No newline at end of file
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getInt64Env(key string, defaultValue int64) int64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxAttachmentsPerTarget - сколько файлов можно приложить к одному посту, комментарию или сообщению
+	MaxAttachmentsPerTarget = 10
+	MaxAttachmentFilename   = 255
+)
+
+var (
+	ErrAttachmentTooLarge   = errors.New("file is too large")
+	ErrUnsupportedMediaType = errors.New("unsupported file type")
+	ErrTooManyAttachments   = errors.New("too many attachments, at most 10 allowed")
+	ErrInvalidAttachment    = errors.New("attachment not found, belongs to another user or is already used")
+)
+
+// AllowedAttachmentTypes - MIME-типы, определенные по содержимому, которые можно загрузить
+var AllowedAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"application/pdf": true,
+	"application/zip": true,
+	"text/plain":      true,
+}
+
+// AttachmentTarget - к чему привязано вложение
+type AttachmentTarget string
+
+const (
+	AttachmentTargetPost    AttachmentTarget = "post"
+	AttachmentTargetComment AttachmentTarget = "comment"
+)
+
+// Attachment - загруженный файл. Width и Height заполнены только у изображений,
+// ThumbnailURL - только если для файла построена миниатюра. ChatRoomID - комната чата,
+// если файл прикреплен к сообщению.
+type Attachment struct {
+	ID           int64     `json:"id" db:"id" example:"1"`
+	OwnerID      int64     `json:"owner_id" db:"owner_id" example:"2"`
+	PostID       *int64    `json:"post_id,omitempty" db:"post_id" example:"1"`
+	CommentID    *int64    `json:"comment_id,omitempty" db:"comment_id" example:"10"`
+	ChatRoomID   *int64    `json:"-" db:"chat_room_id"`
+	Filename     string    `json:"filename" db:"filename" example:"photo.jpg"`
+	ContentType  string    `json:"content_type" db:"content_type" example:"image/jpeg"`
+	Size         int64     `json:"size" db:"size" example:"204800"`
+	Width        int       `json:"width,omitempty" db:"width" example:"1280"`
+	Height       int       `json:"height,omitempty" db:"height" example:"720"`
+	StorageKey   string    `json:"-" db:"storage_key"`
+	ThumbnailKey string    `json:"-" db:"thumbnail_key"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+	URL          string    `json:"url" db:"-" example:"/api/v1/attachments/1"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty" db:"-" example:"/api/v1/attachments/1/thumbnail"`
+}
+
+// SetURLs заполняет адреса, по которым вложение отдает AttachmentHandler
+func (a *Attachment) SetURLs() {
+	a.URL = "/api/v1/attachments/" + strconv.FormatInt(a.ID, 10)
+	if a.ThumbnailKey != "" {
+		a.ThumbnailURL = a.URL + "/thumbnail"
+	}
+}
+
+// ThumbnailContentType - тип миниатюры: фотографии уменьшаются в JPEG, остальные изображения - в PNG
+func (a *Attachment) ThumbnailContentType() string {
+	if a.ContentType == "image/jpeg" {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// IsImage сообщает, что вложение можно показывать в браузере как картинку
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
+
+// CleanAttachmentFilename оставляет от имени файла только последний компонент пути без
+// управляющих символов и кавычек, чтобы его можно было безопасно отдать в Content-Disposition
+func CleanAttachmentFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	for utf8.RuneCountInString(name) > MaxAttachmentFilename {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
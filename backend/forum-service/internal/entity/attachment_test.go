@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanAttachmentFilename(t *testing.T) {
+	tests := map[string]string{
+		"photo.jpg":              "photo.jpg",
+		"../../etc/passwd":       "passwd",
+		`C:\Users\me\report.pdf`: "report.pdf",
+		"evil\"\r\nname.txt":     "evilname.txt",
+		"":                       "file",
+		"  ":                     "file",
+		"dir/":                   "dir",
+		strings.Repeat("я", 300): strings.Repeat("я", MaxAttachmentFilename),
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, CleanAttachmentFilename(input), input)
+	}
+}
+
+func TestAttachment_SetURLs(t *testing.T) {
+	a := Attachment{ID: 5}
+	a.SetURLs()
+	assert.Equal(t, "/api/v1/attachments/5", a.URL)
+	assert.Empty(t, a.ThumbnailURL)
+
+	a = Attachment{ID: 6, ThumbnailKey: "attachments/x_thumb"}
+	a.SetURLs()
+	assert.Equal(t, "/api/v1/attachments/6/thumbnail", a.ThumbnailURL)
+}
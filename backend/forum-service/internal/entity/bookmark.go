@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+const (
+	MaxBookmarkTags      = 10
+	MaxBookmarkTagLength = 32
+	MaxBookmarkFolder    = 64
+)
+
+var (
+	ErrTooManyBookmarkTags = errors.New("too many tags, at most 10 allowed")
+	ErrBookmarkTagTooLong  = errors.New("tag is too long, at most 32 characters allowed")
+	ErrBookmarkFolderLong  = errors.New("folder name is too long, at most 64 characters allowed")
+)
+
+// BookmarkTarget - что сохранено в закладки
+type BookmarkTarget string
+
+const (
+	BookmarkTargetPost    BookmarkTarget = "post"
+	BookmarkTargetComment BookmarkTarget = "comment"
+)
+
+// Bookmark - сохраненный пост или комментарий. Для комментария PostID указывает на его пост.
+// PostTitle и CommentContent заполняются при выборке списка закладок.
+type Bookmark struct {
+	ID             int64          `json:"id" db:"id" example:"1"`
+	UserID         int64          `json:"user_id" db:"user_id" example:"2"`
+	PostID         int64          `json:"post_id" db:"post_id" example:"1"`
+	CommentID      *int64         `json:"comment_id,omitempty" db:"comment_id" example:"10"`
+	Folder         string         `json:"folder" db:"folder" example:"read later"`
+	Tags           pq.StringArray `json:"tags" db:"tags" swaggertype:"array,string" example:"go,tips"`
+	PostTitle      string         `json:"post_title,omitempty" db:"post_title" example:"My Post Title"`
+	CommentContent string         `json:"comment_content,omitempty" db:"comment_content" example:"Great answer"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// Target возвращает тип сохраненного объекта
+func (b *Bookmark) Target() BookmarkTarget {
+	if b.CommentID != nil {
+		return BookmarkTargetComment
+	}
+	return BookmarkTargetPost
+}
+
+// BookmarkFilter задает выборку закладок; пустые Folder и Tag не ограничивают выборку
+type BookmarkFilter struct {
+	Folder string
+	Tag    string
+	Limit  int
+	Offset int
+}
+
+// BookmarkList - страница закладок вместе с их общим числом по фильтру
+type BookmarkList struct {
+	Bookmarks []Bookmark `json:"bookmarks"`
+	Total     int        `json:"total" example:"42"`
+}
+
+// BookmarkFolder - папка закладок с числом закладок в ней
+type BookmarkFolder struct {
+	Name  string `json:"name" db:"folder" example:"read later"`
+	Count int    `json:"count" db:"count" example:"3"`
+}
+
+// NormalizeBookmarkFolder обрезает пробелы и проверяет длину имени папки
+func NormalizeBookmarkFolder(folder string) (string, error) {
+	folder = strings.TrimSpace(folder)
+	if utf8.RuneCountInString(folder) > MaxBookmarkFolder {
+		return "", ErrBookmarkFolderLong
+	}
+	return folder, nil
+}
+
+// NormalizeBookmarkTags приводит теги к нижнему регистру, убирает пустые и повторы
+func NormalizeBookmarkTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxBookmarkTagLength {
+			return nil, ErrBookmarkTagTooLong
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	if len(result) > MaxBookmarkTags {
+		return nil, ErrTooManyBookmarkTags
+	}
+	return result, nil
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeBookmarkTags(t *testing.T) {
+	tags, err := NormalizeBookmarkTags([]string{" Go ", "go", "", "Tips"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "tips"}, tags)
+
+	_, err = NormalizeBookmarkTags([]string{strings.Repeat("x", MaxBookmarkTagLength+1)})
+	assert.ErrorIs(t, err, ErrBookmarkTagTooLong)
+
+	many := make([]string, MaxBookmarkTags+1)
+	for i := range many {
+		many[i] = strings.Repeat("t", i+1)
+	}
+	_, err = NormalizeBookmarkTags(many)
+	assert.ErrorIs(t, err, ErrTooManyBookmarkTags)
+}
+
+func TestNormalizeBookmarkFolder(t *testing.T) {
+	folder, err := NormalizeBookmarkFolder("  read later ")
+	assert.NoError(t, err)
+	assert.Equal(t, "read later", folder)
+
+	_, err = NormalizeBookmarkFolder(strings.Repeat("ф", MaxBookmarkFolder+1))
+	assert.ErrorIs(t, err, ErrBookmarkFolderLong)
+}
@@ -4,11 +4,39 @@ package entity
 import "time"
 
 type Comment struct {
-	ID         int64     `json:"id" db:"id" example:"1"`
-	AuthorID   int64     `json:"author_id" db:"author_id" example:"1"`
-	PostID     int64     `json:"post_id" db:"post_id" example:"1"`
-	ParentID   *int64    `json:"parent_id" db:"parent_id" example:"1"`
-	Content    string    `json:"content" db:"content" example:"текст комментария"`
-	CreatedAt  time.Time `db:"created_at"`
-	AuthorName string    `json:"author_name" db:"author_name"` // Исправлено db:"-"
+	ID          int64      `json:"id" db:"id" example:"1"`
+	AuthorID    int64      `json:"author_id" db:"author_id" example:"1"`
+	PostID      int64      `json:"post_id" db:"post_id" example:"1"`
+	ParentID    *int64     `json:"parent_id,omitempty" db:"parent_id" example:"1"`
+	Content     string     `json:"content" db:"content" example:"текст комментария"`
+	ContentHTML string     `json:"-" db:"content_html"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	EditedAt    *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	DeletedAt   *time.Time `json:"-" db:"deleted_at"`
+	Deleted     bool       `json:"deleted,omitempty" db:"-"`
+	AuthorName  string     `json:"author_name" db:"author_name"` // Исправлено db:"-"
+	// AttachmentIDs - загруженные автором файлы, которые нужно привязать к новому комментарию
+	AttachmentIDs []int64      `json:"-" db:"-"`
+	Attachments   []Attachment `json:"attachments,omitempty" db:"-"`
+	// Held - комментарий отложен фильтром контента и не виден до решения модератора
+	Held bool `json:"held,omitempty" db:"held"`
+}
+
+// Redact скрывает текст и автора удаленного комментария, оставляя его место в ветке
+func (c *Comment) Redact() {
+	if c.DeletedAt == nil {
+		return
+	}
+	c.Deleted = true
+	c.Content = DeletedPlaceholder
+	c.ContentHTML = DeletedPlaceholder
+	c.AuthorName = DeletedPlaceholder
+	c.AuthorID = 0
+	c.EditedAt = nil
+	c.Attachments = nil
+}
+
+// ApplyFormat заменяет Content представлением в запрошенном формате
+func (c *Comment) ApplyFormat(format ContentFormat) {
+	c.Content = format.Apply(c.Content, c.ContentHTML)
 }
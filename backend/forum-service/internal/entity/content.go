@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"errors"
+	"html"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/markdown"
+)
+
+var ErrInvalidFormat = errors.New("invalid format, expected one of: html, markdown, plain")
+
+// ContentFormat определяет, в каком виде отдавать текст поста или комментария
+type ContentFormat string
+
+const (
+	ContentFormatMarkdown ContentFormat = "markdown"
+	ContentFormatHTML     ContentFormat = "html"
+	ContentFormatPlain    ContentFormat = "plain"
+)
+
+// ParseContentFormat разбирает параметр format; пустое значение - исходный Markdown
+func ParseContentFormat(format string) (ContentFormat, error) {
+	switch f := ContentFormat(format); f {
+	case "":
+		return ContentFormatMarkdown, nil
+	case ContentFormatMarkdown, ContentFormatHTML, ContentFormatPlain:
+		return f, nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
+
+// Apply выбирает представление текста по исходнику и сохраненному HTML.
+// Для записей, созданных до появления HTML, он строится на лету.
+func (f ContentFormat) Apply(source, rendered string) string {
+	if f == ContentFormatMarkdown || f == "" {
+		return source
+	}
+
+	if rendered == "" && source != "" {
+		var err error
+		if rendered, err = markdown.Render(source); err != nil {
+			rendered = html.EscapeString(source)
+		}
+	}
+
+	if f == ContentFormatPlain {
+		return markdown.Plain(rendered)
+	}
+	return rendered
+}
@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/wordfilter"
+)
+
+var (
+	ErrInvalidFilterRule  = errors.New("invalid filter rule, expected a word or RE2 regex and action reject, mask or hold")
+	ErrFilterRuleNotFound = errors.New("filter rule not found")
+	ErrContentRejected    = errors.New("content rejected by filter")
+	ErrDuplicateContent   = errors.New("you have already posted this recently")
+)
+
+// FilterAction - что делает фильтр с контентом, совпавшим с правилом
+type FilterAction string
+
+const (
+	// FilterActionAllow - контент публикуется как есть
+	FilterActionAllow FilterAction = ""
+	// FilterActionMask заменяет совпадения на звездочки
+	FilterActionMask FilterAction = "mask"
+	// FilterActionHold публикует контент скрытым до решения модератора
+	FilterActionHold FilterAction = "hold"
+	// FilterActionReject отклоняет запись
+	FilterActionReject FilterAction = "reject"
+)
+
+// Valid сообщает, что действие можно назначить правилу
+func (a FilterAction) Valid() bool {
+	return a == FilterActionMask || a == FilterActionHold || a == FilterActionReject
+}
+
+// severity упорядочивает действия: при нескольких совпадениях побеждает самое строгое
+func (a FilterAction) severity() int {
+	switch a {
+	case FilterActionMask:
+		return 1
+	case FilterActionHold:
+		return 2
+	case FilterActionReject:
+		return 3
+	}
+	return 0
+}
+
+// Stricter сообщает, что a строже b
+func (a FilterAction) Stricter(b FilterAction) bool {
+	return a.severity() > b.severity()
+}
+
+// FilterRule - правило фильтра, которое ведут администраторы
+type FilterRule struct {
+	ID        int64        `json:"id" db:"id" example:"1"`
+	Pattern   string       `json:"pattern" db:"pattern" example:"casino"`
+	IsRegex   bool         `json:"is_regex" db:"is_regex" example:"false"`
+	Action    FilterAction `json:"action" db:"action" example:"hold"`
+	CreatedBy int64        `json:"created_by" db:"created_by" example:"1"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// FilterRuleInput - новое правило фильтра
+type FilterRuleInput struct {
+	Pattern string       `json:"pattern" binding:"required" example:"casino"`
+	IsRegex bool         `json:"is_regex" example:"false"`
+	Action  FilterAction `json:"action" binding:"required" example:"hold"`
+}
+
+// Normalize обрезает пробелы и проверяет действие и шаблон (слово или регулярное выражение RE2)
+func (in *FilterRuleInput) Normalize() error {
+	in.Pattern = strings.TrimSpace(in.Pattern)
+	in.Action = FilterAction(strings.ToLower(strings.TrimSpace(string(in.Action))))
+	if !in.Action.Valid() {
+		return ErrInvalidFilterRule
+	}
+	if _, err := wordfilter.Compile(in.Pattern, in.IsRegex); err != nil {
+		return ErrInvalidFilterRule
+	}
+	return nil
+}
+
+// ContentKind - где опубликован проверяемый текст
+type ContentKind string
+
+const (
+	ContentKindPost    ContentKind = "post"
+	ContentKindComment ContentKind = "comment"
+	ContentKindMessage ContentKind = "message"
+)
+
+// ContentCheck - текст, который проверяется перед сохранением.
+// Title заполняется только для постов и маскируется вместе с Text.
+type ContentCheck struct {
+	UserID int64       `json:"user_id" binding:"required" example:"5"`
+	Kind   ContentKind `json:"kind" binding:"required" example:"message"`
+	Title  string      `json:"title,omitempty" example:""`
+	Text   string      `json:"text" example:"Hello"`
+	// Edit - правка существующей записи; такие тексты не проверяются на дубликаты
+	Edit bool `json:"edit,omitempty" example:"false"`
+}
+
+// FullText - весь текст записи для поиска дубликатов и классификатора
+func (c ContentCheck) FullText() string {
+	if c.Title == "" {
+		return c.Text
+	}
+	return c.Title + "\n" + c.Text
+}
+
+// Причины решений фильтра, которые не связаны с правилами
+const (
+	FilterReasonDuplicate   = "duplicate content"
+	FilterReasonMassPosting = "same content posted by several users"
+	FilterReasonLinks       = "too many links for a new account"
+	FilterReasonClassifier  = "looks like spam"
+)
+
+// FilterVerdict - решение фильтра. Title и Text содержат текст после маскирования.
+type FilterVerdict struct {
+	Action FilterAction `json:"action" example:"mask"`
+	Title  string       `json:"title,omitempty" example:""`
+	Text   string       `json:"text" example:"Hello ****"`
+	Reason string       `json:"reason,omitempty" example:"banned word"`
+}
+
+// Escalate применяет action, если оно строже текущего, и запоминает причину
+func (v *FilterVerdict) Escalate(action FilterAction, reason string) {
+	if action.Stricter(v.Action) {
+		v.Action = action
+		v.Reason = reason
+	}
+}
+
+// Err возвращает ошибку для отклоненной записи
+func (v *FilterVerdict) Err() error {
+	if v.Action != FilterActionReject {
+		return nil
+	}
+	if v.Reason == FilterReasonDuplicate {
+		return ErrDuplicateContent
+	}
+	if v.Reason == "" {
+		return ErrContentRejected
+	}
+	return fmt.Errorf("%w: %s", ErrContentRejected, v.Reason)
+}
+
+// DuplicateStats - сколько раз тот же текст недавно публиковали автор и другие пользователи
+type DuplicateStats struct {
+	Own    int `db:"own"`
+	Others int `db:"others"`
+}
+
+// SpamSample - пример для обучения классификатора
+type SpamSample struct {
+	Content string `db:"content"`
+	IsSpam  bool   `db:"is_spam"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterRuleInput_Normalize(t *testing.T) {
+	in := FilterRuleInput{Pattern: "  casino ", Action: " Hold "}
+	assert.NoError(t, in.Normalize())
+	assert.Equal(t, "casino", in.Pattern)
+	assert.Equal(t, FilterActionHold, in.Action)
+
+	assert.ErrorIs(t, (&FilterRuleInput{Pattern: "casino", Action: "ban"}).Normalize(), ErrInvalidFilterRule)
+	assert.ErrorIs(t, (&FilterRuleInput{Pattern: "  ", Action: FilterActionMask}).Normalize(), ErrInvalidFilterRule)
+	assert.ErrorIs(t, (&FilterRuleInput{Pattern: "a(b", IsRegex: true, Action: FilterActionReject}).Normalize(), ErrInvalidFilterRule)
+}
+
+func TestFilterVerdict_Escalate(t *testing.T) {
+	v := FilterVerdict{}
+	v.Escalate(FilterActionMask, "banned word")
+	v.Escalate(FilterActionHold, FilterReasonLinks)
+	v.Escalate(FilterActionMask, "another word")
+	assert.Equal(t, FilterActionHold, v.Action)
+	assert.Equal(t, FilterReasonLinks, v.Reason)
+	assert.NoError(t, v.Err())
+
+	v.Escalate(FilterActionReject, "banned word")
+	assert.EqualError(t, v.Err(), "content rejected by filter: banned word")
+	assert.ErrorIs(t, (&FilterVerdict{Action: FilterActionReject, Reason: FilterReasonDuplicate}).Err(), ErrDuplicateContent)
+}
+
+func TestContentCheck_FullText(t *testing.T) {
+	assert.Equal(t, "text", ContentCheck{Text: "text"}.FullText())
+	assert.Equal(t, "Title\ntext", ContentCheck{Title: "Title", Text: "text"}.FullText())
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseContentFormat(t *testing.T) {
+	f, err := ParseContentFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, ContentFormatMarkdown, f)
+
+	f, err = ParseContentFormat("plain")
+	assert.NoError(t, err)
+	assert.Equal(t, ContentFormatPlain, f)
+
+	_, err = ParseContentFormat("rtf")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestContentFormat_Apply(t *testing.T) {
+	// Записи без сохраненного HTML отрисовываются при чтении
+	assert.Equal(t, "<p><em>hi</em></p>\n", ContentFormatHTML.Apply("*hi*", ""))
+	assert.Equal(t, "stored", ContentFormatHTML.Apply("*hi*", "stored"))
+	assert.Equal(t, "hi", ContentFormatPlain.Apply("*hi*", "<p><em>hi</em></p>\n"))
+	assert.Equal(t, "*hi*", ContentFormatMarkdown.Apply("*hi*", "<p><em>hi</em></p>\n"))
+}
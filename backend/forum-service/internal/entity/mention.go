@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// MentionSource - где было оставлено упоминание
+type MentionSource string
+
+const (
+	MentionSourcePost    MentionSource = "post"
+	MentionSourceComment MentionSource = "comment"
+)
+
+// Mention - запись об упоминании пользователя (@username) в посте или комментарии.
+// PostID для поста совпадает с SourceID, для комментария - пост, к которому он оставлен.
+type Mention struct {
+	ID                int64         `json:"id" db:"id" example:"1"`
+	SourceType        MentionSource `json:"source_type" db:"source_type" example:"comment"`
+	SourceID          int64         `json:"source_id" db:"source_id" example:"10"`
+	PostID            int64         `json:"post_id" db:"post_id" example:"1"`
+	AuthorID          int64         `json:"author_id" db:"author_id" example:"1"`
+	MentionedUserID   int64         `json:"mentioned_user_id" db:"mentioned_user_id" example:"2"`
+	MentionedUsername string        `json:"mentioned_username" db:"-" example:"alice"`
+	CreatedAt         time.Time     `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
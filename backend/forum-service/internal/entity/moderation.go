@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPostLocked      = errors.New("post is locked, new comments are not allowed")
+	ErrEmptyFlagUpdate = errors.New("nothing to update, expected pinned, locked or announcement")
+)
+
+// PostFlags - состояние поста, которым управляют модераторы.
+// Объявления и закрепленные посты выводятся в начале ленты, к закрытым нельзя комментировать.
+type PostFlags struct {
+	Pinned       bool `json:"pinned" db:"pinned" example:"false"`
+	Locked       bool `json:"locked" db:"locked" example:"false"`
+	Announcement bool `json:"announcement" db:"announcement" example:"false"`
+}
+
+// PostFlagsUpdate - изменение флагов поста; nil оставляет флаг без изменений
+type PostFlagsUpdate struct {
+	Pinned       *bool `json:"pinned,omitempty" example:"true"`
+	Locked       *bool `json:"locked,omitempty" example:"true"`
+	Announcement *bool `json:"announcement,omitempty" example:"false"`
+}
+
+// IsEmpty сообщает, что в запросе не указан ни один флаг
+func (u PostFlagsUpdate) IsEmpty() bool {
+	return u.Pinned == nil && u.Locked == nil && u.Announcement == nil
+}
+
+// Apply применяет изменение к flags и возвращает действия для журнала модерации.
+// Флаги, значение которых не меняется, в журнал не попадают.
+func (u PostFlagsUpdate) Apply(flags *PostFlags) []ModerationAction {
+	var actions []ModerationAction
+	set := func(flag *bool, value *bool, on, off ModerationAction) {
+		if value == nil || *flag == *value {
+			return
+		}
+		*flag = *value
+		if *value {
+			actions = append(actions, on)
+		} else {
+			actions = append(actions, off)
+		}
+	}
+
+	set(&flags.Pinned, u.Pinned, ModerationActionPin, ModerationActionUnpin)
+	set(&flags.Locked, u.Locked, ModerationActionLock, ModerationActionUnlock)
+	set(&flags.Announcement, u.Announcement, ModerationActionAnnounce, ModerationActionUnannounce)
+	return actions
+}
+
+// ModerationAction - тип записи журнала модерации
+type ModerationAction string
+
+const (
+	ModerationActionPin        ModerationAction = "pin"
+	ModerationActionUnpin      ModerationAction = "unpin"
+	ModerationActionLock       ModerationAction = "lock"
+	ModerationActionUnlock     ModerationAction = "unlock"
+	ModerationActionAnnounce   ModerationAction = "announce"
+	ModerationActionUnannounce ModerationAction = "unannounce"
+
+	// Решения по жалобам пользователей
+	ModerationActionDismissReports ModerationAction = "dismiss_reports"
+	ModerationActionDeleteContent  ModerationAction = "delete_content"
+	ModerationActionWarnUser       ModerationAction = "warn_user"
+	ModerationActionSuspendUser    ModerationAction = "suspend_user"
+)
+
+// ModerationTarget - тип объекта, над которым выполнено действие
+type ModerationTarget string
+
+const (
+	ModerationTargetPost    ModerationTarget = "post"
+	ModerationTargetComment ModerationTarget = "comment"
+	ModerationTargetMessage ModerationTarget = "message"
+	ModerationTargetUser    ModerationTarget = "user"
+)
+
+// ModerationLogEntry - запись журнала модерации
+type ModerationLogEntry struct {
+	ID         int64            `json:"id" db:"id" example:"1"`
+	ActorID    int64            `json:"actor_id" db:"actor_id" example:"2"`
+	Action     ModerationAction `json:"action" db:"action" example:"pin"`
+	TargetType ModerationTarget `json:"target_type" db:"target_type" example:"post"`
+	TargetID   int64            `json:"target_id" db:"target_id" example:"10"`
+	Details    string           `json:"details,omitempty" db:"details" example:""`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ModerationLogFilter задает выборку журнала; пустые поля не ограничивают выборку
+type ModerationLogFilter struct {
+	TargetType ModerationTarget
+	TargetID   int64
+	ActorID    int64
+	Limit      int
+	Offset     int
+}
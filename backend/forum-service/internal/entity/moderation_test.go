@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostFlagsUpdate_Apply(t *testing.T) {
+	on, off := true, false
+	flags := PostFlags{Locked: true}
+
+	actions := PostFlagsUpdate{Pinned: &on, Locked: &on, Announcement: &off}.Apply(&flags)
+	assert.Equal(t, []ModerationAction{ModerationActionPin}, actions)
+	assert.Equal(t, PostFlags{Pinned: true, Locked: true}, flags)
+
+	actions = PostFlagsUpdate{Locked: &off, Announcement: &on}.Apply(&flags)
+	assert.Equal(t, []ModerationAction{ModerationActionUnlock, ModerationActionAnnounce}, actions)
+	assert.Equal(t, PostFlags{Pinned: true, Announcement: true}, flags)
+
+	assert.True(t, PostFlagsUpdate{}.IsEmpty())
+	assert.False(t, PostFlagsUpdate{Locked: &off}.IsEmpty())
+}
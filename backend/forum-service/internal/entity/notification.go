@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// NotificationType - событие, о котором уведомляется пользователь
+type NotificationType string
+
+const (
+	NotificationReplyPost    NotificationType = "reply_post"
+	NotificationReplyComment NotificationType = "reply_comment"
+	NotificationMention      NotificationType = "mention"
+	NotificationReaction     NotificationType = "reaction"
+	// NotificationWarning - предупреждение модератора по жалобе на пост или комментарий
+	NotificationWarning NotificationType = "warning"
+)
+
+// NotificationTarget - тип объекта, к которому относится уведомление
+type NotificationTarget string
+
+const (
+	NotificationTargetPost    NotificationTarget = "post"
+	NotificationTargetComment NotificationTarget = "comment"
+)
+
+// Notification - запись во входящих пользователя RecipientID о действии ActorID.
+// PostID позволяет открыть обсуждение, даже если целью является комментарий.
+type Notification struct {
+	ID          int64              `json:"id" db:"id" example:"1"`
+	RecipientID int64              `json:"recipient_id" db:"recipient_id" example:"2"`
+	Type        NotificationType   `json:"type" db:"type" example:"reply_post"`
+	ActorID     int64              `json:"actor_id" db:"actor_id" example:"1"`
+	TargetType  NotificationTarget `json:"target_type" db:"target_type" example:"comment"`
+	TargetID    int64              `json:"target_id" db:"target_id" example:"10"`
+	PostID      int64              `json:"post_id" db:"post_id" example:"1"`
+	Read        bool               `json:"read" db:"is_read" example:"false"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NotificationList - страница входящих вместе с общим числом непрочитанных
+type NotificationList struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int            `json:"unread_count" example:"3"`
+}
+
+// NotificationFilter задает выборку входящих
+type NotificationFilter struct {
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
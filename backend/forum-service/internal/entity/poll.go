@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MinPollOptions      = 2
+	MaxPollOptions      = 10
+	MaxPollQuestion     = 300
+	MaxPollOptionLength = 200
+)
+
+var (
+	ErrInvalidPoll        = errors.New("invalid poll, question and 2 to 10 distinct options are required")
+	ErrPollTooLong        = errors.New("poll question or option is too long")
+	ErrInvalidPollClosing = errors.New("poll closing time must be in the future")
+	ErrPollClosed         = errors.New("poll is closed")
+	ErrAlreadyVoted       = errors.New("you have already voted in this poll")
+	ErrInvalidPollVote    = errors.New("invalid poll vote, choose one of the poll options")
+)
+
+// PollInput - опрос, который автор прикладывает к создаваемому посту
+type PollInput struct {
+	Question  string     `json:"question" example:"Which Go version do you use?"`
+	Options   []string   `json:"options" example:"1.23,1.24"`
+	Multiple  bool       `json:"multiple" example:"false"`
+	Anonymous bool       `json:"anonymous" example:"true"`
+	ClosesAt  *time.Time `json:"closes_at,omitempty" example:"2025-01-01T00:00:00Z"`
+}
+
+// Normalize обрезает пробелы и проверяет опрос. Пустые варианты и повторы считаются ошибкой,
+// чтобы результаты не расходились с тем, что видел автор.
+func (in *PollInput) Normalize(now time.Time) error {
+	in.Question = strings.TrimSpace(in.Question)
+	if in.Question == "" || len(in.Options) < MinPollOptions || len(in.Options) > MaxPollOptions {
+		return ErrInvalidPoll
+	}
+	if utf8.RuneCountInString(in.Question) > MaxPollQuestion {
+		return ErrPollTooLong
+	}
+
+	seen := make(map[string]bool, len(in.Options))
+	for i, option := range in.Options {
+		option = strings.TrimSpace(option)
+		key := strings.ToLower(option)
+		if option == "" || seen[key] {
+			return ErrInvalidPoll
+		}
+		if utf8.RuneCountInString(option) > MaxPollOptionLength {
+			return ErrPollTooLong
+		}
+		seen[key] = true
+		in.Options[i] = option
+	}
+
+	if in.ClosesAt != nil && !in.ClosesAt.After(now) {
+		return ErrInvalidPollClosing
+	}
+	return nil
+}
+
+// Poll - опрос поста с текущими результатами
+type Poll struct {
+	ID        int64      `json:"id" db:"id" example:"1"`
+	PostID    int64      `json:"post_id" db:"post_id" example:"10"`
+	Question  string     `json:"question" db:"question" example:"Which Go version do you use?"`
+	Multiple  bool       `json:"multiple" db:"multiple" example:"false"`
+	Anonymous bool       `json:"anonymous" db:"anonymous" example:"true"`
+	ClosesAt  *time.Time `json:"closes_at,omitempty" db:"closes_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	// Closed вычисляется при выдаче: опрос закрыт вручную или истек срок
+	Closed      bool         `json:"closed" db:"-" example:"false"`
+	TotalVoters int          `json:"total_voters" db:"total_voters" example:"42"`
+	Options     []PollOption `json:"options" db:"-"`
+	// MyVotes - варианты, выбранные текущим пользователем; nil для гостей
+	MyVotes []int64 `json:"my_votes,omitempty" db:"-"`
+}
+
+// PollOption - вариант ответа. Voters заполняется только в открытых (неанонимных) опросах.
+type PollOption struct {
+	ID       int64   `json:"id" db:"id" example:"3"`
+	PollID   int64   `json:"-" db:"poll_id"`
+	Position int     `json:"position" db:"position" example:"0"`
+	Text     string  `json:"text" db:"text" example:"1.24"`
+	Votes    int     `json:"votes" db:"votes" example:"30"`
+	Voters   []int64 `json:"voters,omitempty" db:"-"`
+}
+
+// IsClosed сообщает, принимает ли опрос голоса в момент now
+func (p *Poll) IsClosed(now time.Time) bool {
+	return p.ClosedAt != nil || (p.ClosesAt != nil && !p.ClosesAt.After(now))
+}
+
+// ValidateVote проверяет выбор пользователя и возвращает ID вариантов без повторов
+func (p *Poll) ValidateVote(optionIDs []int64) ([]int64, error) {
+	valid := make(map[int64]bool, len(p.Options))
+	for _, option := range p.Options {
+		valid[option.ID] = true
+	}
+
+	seen := make(map[int64]bool, len(optionIDs))
+	choice := make([]int64, 0, len(optionIDs))
+	for _, id := range optionIDs {
+		if !valid[id] {
+			return nil, ErrInvalidPollVote
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		choice = append(choice, id)
+	}
+
+	if len(choice) == 0 || (!p.Multiple && len(choice) > 1) {
+		return nil, ErrInvalidPollVote
+	}
+	return choice, nil
+}
+
+// PollTopic - тема WebSocket, на которую подписываются клиенты, следящие за опросом поста
+func PollTopic(postID int64) string {
+	return "poll:" + strconv.FormatInt(postID, 10)
+}
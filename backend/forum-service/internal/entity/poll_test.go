@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollInput_Normalize(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		in      PollInput
+		wantErr error
+	}{
+		{name: "Valid", in: PollInput{Question: " Go or Rust? ", Options: []string{" Go ", "Rust"}, ClosesAt: &future}},
+		{name: "Empty question", in: PollInput{Question: " ", Options: []string{"a", "b"}}, wantErr: ErrInvalidPoll},
+		{name: "One option", in: PollInput{Question: "q", Options: []string{"a"}}, wantErr: ErrInvalidPoll},
+		{name: "Too many options", in: PollInput{Question: "q", Options: strings.Split("a,b,c,d,e,f,g,h,i,j,k", ",")}, wantErr: ErrInvalidPoll},
+		{name: "Duplicate options", in: PollInput{Question: "q", Options: []string{"Yes", " yes"}}, wantErr: ErrInvalidPoll},
+		{name: "Blank option", in: PollInput{Question: "q", Options: []string{"a", " "}}, wantErr: ErrInvalidPoll},
+		{name: "Long option", in: PollInput{Question: "q", Options: []string{"a", strings.Repeat("б", MaxPollOptionLength+1)}}, wantErr: ErrPollTooLong},
+		{name: "Closing in the past", in: PollInput{Question: "q", Options: []string{"a", "b"}, ClosesAt: &past}, wantErr: ErrInvalidPollClosing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.in.Normalize(now)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "Go or Rust?", tt.in.Question)
+			assert.Equal(t, []string{"Go", "Rust"}, tt.in.Options)
+		})
+	}
+}
+
+func TestPoll_ValidateVote(t *testing.T) {
+	poll := &Poll{Options: []PollOption{{ID: 1}, {ID: 2}, {ID: 3}}}
+
+	choice, err := poll.ValidateVote([]int64{2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, choice)
+
+	_, err = poll.ValidateVote([]int64{1, 2})
+	assert.ErrorIs(t, err, ErrInvalidPollVote)
+	_, err = poll.ValidateVote([]int64{4})
+	assert.ErrorIs(t, err, ErrInvalidPollVote)
+	_, err = poll.ValidateVote(nil)
+	assert.ErrorIs(t, err, ErrInvalidPollVote)
+
+	poll.Multiple = true
+	choice, err = poll.ValidateVote([]int64{3, 1, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 1}, choice)
+}
+
+func TestPoll_IsClosed(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Second)
+	future := now.Add(time.Hour)
+
+	assert.False(t, (&Poll{}).IsClosed(now))
+	assert.False(t, (&Poll{ClosesAt: &future}).IsClosed(now))
+	assert.True(t, (&Poll{ClosesAt: &past}).IsClosed(now))
+	assert.True(t, (&Poll{ClosedAt: &past, ClosesAt: &future}).IsClosed(now))
+	assert.Equal(t, "poll:12", PollTopic(12))
+}
@@ -1,12 +1,105 @@
 package entity
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidSort   = errors.New("invalid sort, expected one of: new, hot, top, controversial")
+	ErrInvalidPeriod = errors.New("invalid period, expected one of: day, week, month, all")
+)
 
 type Post struct {
-	ID        int64     `json:"id" db:"id" example:"123"`
-	Title     string    `json:"title" db:"title" example:"My Post Title"`
-	Content   string    `json:"content" db:"content" example:"Post content text"`
-	AuthorID  int64     `json:"author_id" db:"author_id" example:"456"`
-	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2023-01-01T00:00:00Z"`
+	ID           int64     `json:"id" db:"id" example:"123"`
+	Title        string    `json:"title" db:"title" example:"My Post Title"`
+	Content      string    `json:"content" db:"content" example:"Post content text"`
+	ContentHTML  string    `json:"-" db:"content_html"`
+	AuthorID     int64     `json:"author_id" db:"author_id" example:"456"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at" example:"2023-01-01T00:00:00Z"`
+	Upvotes      int       `json:"upvotes" db:"upvotes" example:"10"`
+	Downvotes    int       `json:"downvotes" db:"downvotes" example:"2"`
+	CommentCount int       `json:"comment_count" db:"comment_count" example:"5"`
+	Score        int       `json:"score" db:"score" example:"8"`
+	// PostFlags - закрепление, закрытие и статус объявления; меняются модераторами
+	PostFlags
+	// Tags - slug тегов поста; хранятся в post_tags и загружаются отдельно
+	Tags []string `json:"tags,omitempty" db:"-" example:"golang,sql"`
+	// Attachments - приложенные файлы; загружаются отдельно
+	Attachments []Attachment `json:"attachments,omitempty" db:"-"`
+	// Poll - опрос поста; заполняется при создании поста и в GET /posts/{id}/poll
+	Poll *Poll `json:"poll,omitempty" db:"-"`
+	// Held - пост отложен фильтром контента и не виден в ленте до решения модератора
+	Held bool `json:"held,omitempty" db:"held" example:"false"`
+}
+
+// PostSort определяет порядок выдачи ленты постов
+type PostSort string
+
+const (
+	PostSortNew           PostSort = "new"
+	PostSortHot           PostSort = "hot"
+	PostSortTop           PostSort = "top"
+	PostSortControversial PostSort = "controversial"
+)
+
+// PostPeriod ограничивает ленты top/controversial по времени создания поста
+type PostPeriod string
+
+const (
+	PostPeriodDay   PostPeriod = "day"
+	PostPeriodWeek  PostPeriod = "week"
+	PostPeriodMonth PostPeriod = "month"
+	PostPeriodAll   PostPeriod = "all"
+)
+
+// Duration возвращает длину периода; для "all" возвращается 0
+func (p PostPeriod) Duration() time.Duration {
+	switch p {
+	case PostPeriodDay:
+		return 24 * time.Hour
+	case PostPeriodWeek:
+		return 7 * 24 * time.Hour
+	case PostPeriodMonth:
+		return 30 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+type PostFilter struct {
+	Sort   PostSort
+	Period PostPeriod
+	// Tag - slug тега или его синонима; пустое значение не ограничивает ленту
+	Tag string
+}
+
+// ParsePostFilter разбирает параметры sort и period из запроса.
+// Пустые значения заменяются на sort=new и period=all.
+func ParsePostFilter(sort, period string) (PostFilter, error) {
+	filter := PostFilter{Sort: PostSortNew, Period: PostPeriodAll}
+
+	switch PostSort(sort) {
+	case "":
+	case PostSortNew, PostSortHot, PostSortTop, PostSortControversial:
+		filter.Sort = PostSort(sort)
+	default:
+		return filter, ErrInvalidSort
+	}
+
+	switch PostPeriod(period) {
+	case "":
+	case PostPeriodDay, PostPeriodWeek, PostPeriodMonth, PostPeriodAll:
+		filter.Period = PostPeriod(period)
+	default:
+		return filter, ErrInvalidPeriod
+	}
+
+	return filter, nil
+}
+
+// ApplyFormat заменяет Content представлением в запрошенном формате
+func (p *Post) ApplyFormat(format ContentFormat) {
+	p.Content = format.Apply(p.Content, p.ContentHTML)
 }
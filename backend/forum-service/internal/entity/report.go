@@ -0,0 +1,270 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxReportDetails = 1000
+	// DefaultSuspendHours - срок блокировки автора, если модератор его не указал
+	DefaultSuspendHours = 24
+	// MaxSuspendHours - самая долгая блокировка, которую можно выдать из очереди жалоб (один год)
+	MaxSuspendHours = 24 * 365
+)
+
+var (
+	ErrInvalidReportTarget  = errors.New("invalid report target, expected post, comment, message or user")
+	ErrInvalidReportReason  = errors.New("invalid report reason, expected spam, abuse, harassment, off_topic, illegal or other")
+	ErrReportTooLong        = errors.New("report details are too long")
+	ErrReportOwnContent     = errors.New("you cannot report your own content")
+	ErrAlreadyReported      = errors.New("you have already reported this")
+	ErrInvalidReportStatus  = errors.New("invalid report status, expected open, resolved or dismissed")
+	ErrInvalidReportAction  = errors.New("invalid report action, expected dismiss, delete, warn or suspend")
+	ErrNoOpenReports        = errors.New("there are no open reports for this target")
+	ErrReportTargetNotFound = errors.New("report target not found")
+)
+
+// ReportTarget - тип объекта жалобы
+type ReportTarget string
+
+const (
+	ReportTargetPost    ReportTarget = "post"
+	ReportTargetComment ReportTarget = "comment"
+	ReportTargetMessage ReportTarget = "message"
+	ReportTargetUser    ReportTarget = "user"
+)
+
+// Valid сообщает, что на объекты такого типа можно жаловаться
+func (t ReportTarget) Valid() bool {
+	switch t {
+	case ReportTargetPost, ReportTargetComment, ReportTargetMessage, ReportTargetUser:
+		return true
+	}
+	return false
+}
+
+// ReportReason - причина жалобы
+type ReportReason string
+
+const (
+	ReportReasonSpam       ReportReason = "spam"
+	ReportReasonAbuse      ReportReason = "abuse"
+	ReportReasonHarassment ReportReason = "harassment"
+	ReportReasonOffTopic   ReportReason = "off_topic"
+	ReportReasonIllegal    ReportReason = "illegal"
+	ReportReasonOther      ReportReason = "other"
+)
+
+func (r ReportReason) Valid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonAbuse, ReportReasonHarassment, ReportReasonOffTopic, ReportReasonIllegal, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus - состояние жалобы в очереди модерации
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+func (s ReportStatus) Valid() bool {
+	return s == ReportStatusOpen || s == ReportStatusResolved || s == ReportStatusDismissed
+}
+
+// ReportAction - решение модератора по жалобам на объект
+type ReportAction string
+
+const (
+	// ReportActionDismiss отклоняет жалобы без последствий для автора
+	ReportActionDismiss ReportAction = "dismiss"
+	// ReportActionDelete удаляет пост, комментарий или сообщение чата
+	ReportActionDelete ReportAction = "delete"
+	// ReportActionWarn отправляет автору предупреждение
+	ReportActionWarn ReportAction = "warn"
+	// ReportActionSuspend блокирует автора через auth-service
+	ReportActionSuspend ReportAction = "suspend"
+)
+
+func (a ReportAction) Valid() bool {
+	switch a {
+	case ReportActionDismiss, ReportActionDelete, ReportActionWarn, ReportActionSuspend:
+		return true
+	}
+	return false
+}
+
+// ModerationAction возвращает тип записи журнала модерации для решения
+func (a ReportAction) ModerationAction() ModerationAction {
+	switch a {
+	case ReportActionDelete:
+		return ModerationActionDeleteContent
+	case ReportActionWarn:
+		return ModerationActionWarnUser
+	case ReportActionSuspend:
+		return ModerationActionSuspendUser
+	}
+	return ModerationActionDismissReports
+}
+
+// Status - в каком состоянии остаются жалобы после решения
+func (a ReportAction) Status() ReportStatus {
+	if a == ReportActionDismiss {
+		return ReportStatusDismissed
+	}
+	return ReportStatusResolved
+}
+
+// ReportInput - жалоба, которую отправляет пользователь
+type ReportInput struct {
+	TargetType ReportTarget `json:"target_type" binding:"required" example:"post"`
+	TargetID   int64        `json:"target_id" binding:"required" example:"10"`
+	Reason     ReportReason `json:"reason" binding:"required" example:"spam"`
+	Details    string       `json:"details" example:"Links to a casino in every paragraph"`
+}
+
+// Normalize обрезает пробелы и проверяет тип объекта, причину и длину пояснения
+func (in *ReportInput) Normalize() error {
+	in.Details = strings.TrimSpace(in.Details)
+	if !in.TargetType.Valid() || in.TargetID <= 0 {
+		return ErrInvalidReportTarget
+	}
+	if !in.Reason.Valid() {
+		return ErrInvalidReportReason
+	}
+	if utf8.RuneCountInString(in.Details) > MaxReportDetails {
+		return ErrReportTooLong
+	}
+	return nil
+}
+
+// Report - жалоба пользователя
+type Report struct {
+	ID         int64        `json:"id" db:"id" example:"1"`
+	ReporterID int64        `json:"reporter_id" db:"reporter_id" example:"2"`
+	TargetType ReportTarget `json:"target_type" db:"target_type" example:"post"`
+	TargetID   int64        `json:"target_id" db:"target_id" example:"10"`
+	// TargetAuthorID - автор объекта жалобы (для жалоб на пользователя - сам пользователь)
+	TargetAuthorID int64        `json:"target_author_id" db:"target_author_id" example:"5"`
+	Reason         ReportReason `json:"reason" db:"reason" example:"spam"`
+	Details        string       `json:"details,omitempty" db:"details" example:""`
+	Status         ReportStatus `json:"status" db:"status" example:"open"`
+	Resolution     ReportAction `json:"resolution,omitempty" db:"resolution" example:""`
+	ResolutionNote string       `json:"resolution_note,omitempty" db:"resolution_note" example:""`
+	ResolvedBy     *int64       `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt     *time.Time   `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ReportGroup - жалобы на один объект, как их видит модератор в очереди
+type ReportGroup struct {
+	TargetType     ReportTarget         `json:"target_type" example:"post"`
+	TargetID       int64                `json:"target_id" example:"10"`
+	TargetAuthorID int64                `json:"target_author_id" example:"5"`
+	Count          int                  `json:"count" example:"3"`
+	Reasons        map[ReportReason]int `json:"reasons"`
+	LastReportedAt time.Time            `json:"last_reported_at" example:"2023-01-01T00:00:00Z"`
+	Reports        []Report             `json:"reports"`
+}
+
+// GroupReports объединяет жалобы по объекту, сохраняя порядок первого появления объекта в reports
+func GroupReports(reports []Report) []ReportGroup {
+	groups := []ReportGroup{}
+	index := make(map[ReportTarget]map[int64]int)
+	for _, report := range reports {
+		byID, ok := index[report.TargetType]
+		if !ok {
+			byID = make(map[int64]int)
+			index[report.TargetType] = byID
+		}
+		i, ok := byID[report.TargetID]
+		if !ok {
+			i = len(groups)
+			byID[report.TargetID] = i
+			groups = append(groups, ReportGroup{
+				TargetType:     report.TargetType,
+				TargetID:       report.TargetID,
+				TargetAuthorID: report.TargetAuthorID,
+				Reasons:        make(map[ReportReason]int),
+			})
+		}
+
+		group := &groups[i]
+		group.Count++
+		group.Reasons[report.Reason]++
+		if report.CreatedAt.After(group.LastReportedAt) {
+			group.LastReportedAt = report.CreatedAt
+		}
+		group.Reports = append(group.Reports, report)
+	}
+	return groups
+}
+
+// ReportFilter задает выборку очереди; пустой TargetType не ограничивает выборку
+type ReportFilter struct {
+	Status     ReportStatus
+	TargetType ReportTarget
+	Limit      int
+	Offset     int
+}
+
+// ReportResolution - решение модератора по всем открытым жалобам на объект
+type ReportResolution struct {
+	Action ReportAction `json:"action" binding:"required" example:"suspend"`
+	Note   string       `json:"note" example:"Repeated spam"`
+	// SuspendHours - срок блокировки для action=suspend; 0 - DefaultSuspendHours
+	SuspendHours int `json:"suspend_hours" example:"72"`
+}
+
+// Normalize проверяет решение и подставляет срок блокировки по умолчанию
+func (r *ReportResolution) Normalize() error {
+	r.Note = strings.TrimSpace(r.Note)
+	if !r.Action.Valid() {
+		return ErrInvalidReportAction
+	}
+	if utf8.RuneCountInString(r.Note) > MaxReportDetails {
+		return ErrReportTooLong
+	}
+	if r.Action != ReportActionSuspend {
+		r.SuspendHours = 0
+		return nil
+	}
+	if r.SuspendHours <= 0 {
+		r.SuspendHours = DefaultSuspendHours
+	}
+	if r.SuspendHours > MaxSuspendHours {
+		r.SuspendHours = MaxSuspendHours
+	}
+	return nil
+}
+
+// Details - текст записи журнала модерации: срок блокировки и комментарий модератора
+func (r ReportResolution) Details() string {
+	if r.Action != ReportActionSuspend {
+		return r.Note
+	}
+	details := fmt.Sprintf("suspended for %dh", r.SuspendHours)
+	if r.Note != "" {
+		details += ": " + r.Note
+	}
+	return details
+}
+
+// Warning - предупреждение, которое модератор выносит автору по жалобам.
+// PostID заполняется для постов и комментариев, чтобы предупреждение попало во входящие.
+type Warning struct {
+	UserID      int64        `json:"user_id" example:"5"`
+	ModeratorID int64        `json:"moderator_id" example:"2"`
+	TargetType  ReportTarget `json:"target_type" example:"comment"`
+	TargetID    int64        `json:"target_id" example:"10"`
+	PostID      int64        `json:"post_id,omitempty" example:"1"`
+	Note        string       `json:"note,omitempty" example:"Please keep it civil"`
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportInput_Normalize(t *testing.T) {
+	in := ReportInput{TargetType: ReportTargetComment, TargetID: 4, Reason: ReportReasonSpam, Details: "  casino links  "}
+	assert.NoError(t, in.Normalize())
+	assert.Equal(t, "casino links", in.Details)
+
+	assert.ErrorIs(t, (&ReportInput{TargetType: "thread", TargetID: 1, Reason: ReportReasonSpam}).Normalize(), ErrInvalidReportTarget)
+	assert.ErrorIs(t, (&ReportInput{TargetType: ReportTargetPost, Reason: ReportReasonSpam}).Normalize(), ErrInvalidReportTarget)
+	assert.ErrorIs(t, (&ReportInput{TargetType: ReportTargetPost, TargetID: 1, Reason: "boring"}).Normalize(), ErrInvalidReportReason)
+	long := ReportInput{TargetType: ReportTargetPost, TargetID: 1, Reason: ReportReasonOther, Details: strings.Repeat("a", MaxReportDetails+1)}
+	assert.ErrorIs(t, long.Normalize(), ErrReportTooLong)
+}
+
+func TestReportResolution_Normalize(t *testing.T) {
+	r := ReportResolution{Action: ReportActionSuspend, Note: " spam "}
+	assert.NoError(t, r.Normalize())
+	assert.Equal(t, DefaultSuspendHours, r.SuspendHours)
+	assert.Equal(t, "suspended for 24h: spam", r.Details())
+
+	r = ReportResolution{Action: ReportActionSuspend, SuspendHours: MaxSuspendHours * 2}
+	assert.NoError(t, r.Normalize())
+	assert.Equal(t, MaxSuspendHours, r.SuspendHours)
+
+	r = ReportResolution{Action: ReportActionWarn, SuspendHours: 10, Note: "be nice"}
+	assert.NoError(t, r.Normalize())
+	assert.Zero(t, r.SuspendHours)
+	assert.Equal(t, "be nice", r.Details())
+
+	assert.ErrorIs(t, (&ReportResolution{Action: "ban"}).Normalize(), ErrInvalidReportAction)
+	assert.Equal(t, ReportStatusDismissed, ReportActionDismiss.Status())
+	assert.Equal(t, ReportStatusResolved, ReportActionDelete.Status())
+	assert.Equal(t, ModerationActionSuspendUser, ReportActionSuspend.ModerationAction())
+}
+
+func TestGroupReports(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	groups := GroupReports([]Report{
+		{ID: 1, TargetType: ReportTargetPost, TargetID: 3, TargetAuthorID: 9, Reason: ReportReasonSpam, CreatedAt: t0},
+		{ID: 2, TargetType: ReportTargetComment, TargetID: 3, TargetAuthorID: 8, Reason: ReportReasonAbuse, CreatedAt: t0},
+		{ID: 3, TargetType: ReportTargetPost, TargetID: 3, TargetAuthorID: 9, Reason: ReportReasonSpam, CreatedAt: t0.Add(time.Hour)},
+	})
+
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, ReportTargetPost, groups[0].TargetType)
+		assert.Equal(t, 2, groups[0].Count)
+		assert.Equal(t, map[ReportReason]int{ReportReasonSpam: 2}, groups[0].Reasons)
+		assert.Equal(t, t0.Add(time.Hour), groups[0].LastReportedAt)
+		assert.Equal(t, int64(8), groups[1].TargetAuthorID)
+	}
+	assert.Equal(t, []ReportGroup{}, GroupReports(nil))
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/diff"
+)
+
+// Revision - снимок поста или комментария до очередного редактирования.
+// Number нумерует правки объекта начиная с 1 в порядке их появления.
+type Revision struct {
+	ID        int64     `json:"id" db:"id" example:"1"`
+	Number    int       `json:"number" db:"number" example:"1"`
+	EditorID  int64     `json:"editor_id" db:"editor_id" example:"1"`
+	Title     string    `json:"title,omitempty" db:"title" example:"Old title"`
+	Content   string    `json:"content" db:"content" example:"Old content"`
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// RevisionDiff - построчная разница между двумя версиями.
+// To = 0 означает текущую версию объекта.
+type RevisionDiff struct {
+	From    int         `json:"from" example:"1"`
+	To      int         `json:"to" example:"0"`
+	Title   []diff.Line `json:"title,omitempty"`
+	Content []diff.Line `json:"content"`
+}
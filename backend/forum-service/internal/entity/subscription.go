@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidDigestFrequency = errors.New("invalid frequency, expected one of: off, daily, weekly")
+
+// Subscription - подписка пользователя на новые комментарии к посту
+type Subscription struct {
+	ID        int64     `json:"id" db:"id" example:"1"`
+	UserID    int64     `json:"user_id" db:"user_id" example:"2"`
+	PostID    int64     `json:"post_id" db:"post_id" example:"1"`
+	PostTitle string    `json:"post_title" db:"post_title" example:"My Post Title"`
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// DigestFrequency - как часто пользователь получает дайджест
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// ParseDigestFrequency проверяет значение частоты из запроса
+func ParseDigestFrequency(s string) (DigestFrequency, error) {
+	switch f := DigestFrequency(s); f {
+	case DigestOff, DigestDaily, DigestWeekly:
+		return f, nil
+	default:
+		return "", ErrInvalidDigestFrequency
+	}
+}
+
+// Period возвращает интервал между дайджестами; для "off" возвращается 0
+func (f DigestFrequency) Period() time.Duration {
+	switch f {
+	case DigestDaily:
+		return 24 * time.Hour
+	case DigestWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// DigestSettings - адрес и частота дайджеста пользователя.
+// UnsubscribeToken позволяет отписаться по ссылке из письма без входа, SentUntil - время создания
+// последнего комментария, уже попавшего в дайджест.
+type DigestSettings struct {
+	UserID           int64           `json:"user_id" db:"user_id" example:"2"`
+	Email            string          `json:"email" db:"email" example:"alice@example.com"`
+	Frequency        DigestFrequency `json:"frequency" db:"frequency" example:"daily"`
+	UnsubscribeToken string          `json:"-" db:"unsubscribe_token"`
+	LastSentAt       *time.Time      `json:"last_sent_at,omitempty" db:"last_sent_at"`
+	SentUntil        *time.Time      `json:"-" db:"sent_until"`
+	CreatedAt        time.Time       `json:"-" db:"created_at"`
+}
+
+// DigestComment - новый комментарий к посту, на который подписан получатель дайджеста
+type DigestComment struct {
+	ID         int64     `db:"id"`
+	PostID     int64     `db:"post_id"`
+	PostTitle  string    `db:"post_title"`
+	AuthorName string    `db:"author_name"`
+	Content    string    `db:"content"`
+	CreatedAt  time.Time `db:"created_at"`
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MaxTagsPerPost = 5
+	MaxTagLength   = 32
+)
+
+var (
+	ErrInvalidTag  = errors.New("invalid tag, expected letters, digits and dashes")
+	ErrTagTooLong  = errors.New("tag is too long, at most 32 characters allowed")
+	ErrTooManyTags = errors.New("too many tags, at most 5 allowed per post")
+)
+
+// Tag - тег постов; PostCount - число опубликованных постов с этим тегом
+type Tag struct {
+	ID        int64  `json:"id" db:"id" example:"1"`
+	Slug      string `json:"slug" db:"slug" example:"golang"`
+	PostCount int    `json:"post_count" db:"post_count" example:"12"`
+	// Synonyms заполняется только при запросе одного тега
+	Synonyms []string `json:"synonyms,omitempty" db:"-" example:"go"`
+}
+
+// NormalizeTag приводит тег к slug: нижний регистр, пробелы и подчеркивания заменяются
+// дефисами, прочие символы кроме букв и цифр отбрасываются, повторные дефисы схлопываются.
+func NormalizeTag(tag string) (string, error) {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(tag)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case r == '-' || r == '_' || unicode.IsSpace(r):
+			if !dash && b.Len() > 0 {
+				b.WriteRune('-')
+				dash = true
+			}
+		}
+	}
+
+	slug := strings.TrimRight(b.String(), "-")
+	if slug == "" {
+		return "", ErrInvalidTag
+	}
+	if utf8.RuneCountInString(slug) > MaxTagLength {
+		return "", ErrTagTooLong
+	}
+	return slug, nil
+}
+
+// NormalizeTags нормализует теги поста и убирает повторы с сохранением порядка
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	slugs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		slug, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		slugs = append(slugs, slug)
+	}
+	if len(slugs) > MaxTagsPerPost {
+		return nil, ErrTooManyTags
+	}
+	return slugs, nil
+}
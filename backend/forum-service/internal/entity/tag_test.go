@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{in: "Go", want: "go"},
+		{in: "  Machine  Learning ", want: "machine-learning"},
+		{in: "c++", want: "c"},
+		{in: "snake_case--tag-", want: "snake-case-tag"},
+		{in: "Базы Данных", want: "базы-данных"},
+		{in: "--", wantErr: ErrInvalidTag},
+		{in: strings.Repeat("a", MaxTagLength+1), wantErr: ErrTagTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := NormalizeTag(tt.in)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	slugs, err := NormalizeTags([]string{"Go", "go", "SQL"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "sql"}, slugs)
+
+	_, err = NormalizeTags([]string{"a", "b", "c", "d", "e", "f"})
+	assert.ErrorIs(t, err, ErrTooManyTags)
+
+	_, err = NormalizeTags([]string{"ok", "!!!"})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
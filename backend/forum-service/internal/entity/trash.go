@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// DeletedPlaceholder заменяет текст и автора удаленного комментария в ветке обсуждения
+const DeletedPlaceholder = "[deleted]"
+
+// TrashKind - тип объекта в корзине
+type TrashKind string
+
+const (
+	TrashKindPost    TrashKind = "post"
+	TrashKindComment TrashKind = "comment"
+)
+
+// TrashItem - мягко удаленный пост или комментарий, который еще можно восстановить
+type TrashItem struct {
+	Kind      TrashKind `json:"kind" db:"kind" example:"post"`
+	ID        int64     `json:"id" db:"id" example:"1"`
+	PostID    int64     `json:"post_id" db:"post_id" example:"1"`
+	AuthorID  int64     `json:"author_id" db:"author_id" example:"1"`
+	Title     string    `json:"title,omitempty" db:"title" example:"My Post Title"`
+	Content   string    `json:"content" db:"content" example:"Post content text"`
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at" example:"2023-01-01T00:00:00Z"`
+	DeletedBy int64     `json:"deleted_by" db:"deleted_by" example:"1"`
+	Reason    string    `json:"reason,omitempty" db:"delete_reason" example:"spam"`
+	// PurgeAt - момент, после которого объект будет удален окончательно
+	PurgeAt time.Time `json:"purge_at" db:"-" example:"2023-01-31T00:00:00Z"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+// PostScore содержит денормализованные счетчики поста после голосования
+type PostScore struct {
+	PostID       int64 `json:"post_id" db:"id" example:"1"`
+	Upvotes      int   `json:"upvotes" db:"upvotes" example:"10"`
+	Downvotes    int   `json:"downvotes" db:"downvotes" example:"2"`
+	CommentCount int   `json:"comment_count" db:"comment_count" example:"5"`
+	Score        int   `json:"score" db:"score" example:"8"`
+	UserVote     int   `json:"user_vote" db:"-" example:"1"`
+}
+
+type VoteRequest struct {
+	Value *int `json:"value" binding:"required" example:"1"`
+}
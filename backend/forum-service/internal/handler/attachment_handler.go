@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/blobstore"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// attachmentFormField - имя поля multipart-формы с файлом
+const attachmentFormField = "file"
+
+type AttachmentHandler struct {
+	uc     usecase.AttachmentUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewAttachmentHandler(uc usecase.AttachmentUsecaseInterface, logger *logger.Logger) *AttachmentHandler {
+	return &AttachmentHandler{uc: uc, logger: logger}
+}
+
+// UploadAttachment godoc
+// @Summary Upload a file
+// @Description Upload an image or a document as multipart/form-data. The type is detected from the content; images are re-encoded without EXIF metadata and get a thumbnail. Pass the returned ID in attachment_ids when creating a post or comment, or in a chat message
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param file formData file true "File to upload"
+// @Success 201 {object} entity.Attachment
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 413 {object} entity.ErrorResponse
+// @Failure 415 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	// Форма читается потоком: файл не попадает во временные файлы, а лишнее
+	// сверх лимита размера не читается вовсе
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Expected multipart/form-data"})
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid multipart body"})
+			return
+		}
+		if part.FormName() != attachmentFormField {
+			part.Close()
+			continue
+		}
+
+		attachment, err := h.uc.Upload(c.Request.Context(), token, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			respondError(c, h.logger, attachmentErrors, err, "Failed to upload file")
+			return
+		}
+		c.JSON(http.StatusCreated, attachment)
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Field \"file\" is required"})
+}
+
+// GetAttachment godoc
+// @Summary Download a file
+// @Description Images are shown inline, other files are downloaded with the original name. Files of published posts and comments are public; other files are served only to the owner, moderators and members of the chat room the file was sent to, and respond with 404 to everyone else
+// @Tags attachments
+// @Produce octet-stream
+// @Param Authorization header string false "Bearer token"
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/attachments/{id} [get]
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	h.serve(c, false)
+}
+
+// GetThumbnail godoc
+// @Summary Download an image thumbnail
+// @Description Access rules are the same as for the file itself
+// @Tags attachments
+// @Produce image/jpeg,image/png
+// @Param Authorization header string false "Bearer token"
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/attachments/{id}/thumbnail [get]
+func (h *AttachmentHandler) GetThumbnail(c *gin.Context) {
+	h.serve(c, true)
+}
+
+// DeleteAttachment godoc
+// @Summary Delete a file
+// @Description Delete an uploaded file (owner or moderator). It disappears from the post or comment it was attached to; a chat message keeps the attachment ID, and the file then responds with 404
+// @Tags attachments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/attachments/{id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteAttachment(c.Request.Context(), token, id); err != nil {
+		respondError(c, h.logger, attachmentErrors, err, "Failed to delete file")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
+
+// ClaimChatAttachments godoc
+// @Summary Attach files to a chat message
+// @Description Internal endpoint used by the chat service before saving a message. Checks that all files belong to the sender and are not used yet, and marks them as used in chat
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param X-Internal-Token header string true "Shared internal token"
+// @Param request body object{owner_id=int,room_id=int,attachment_ids=[]int} true "Message author, chat room and attachment IDs"
+// @Success 200 {object} object{attachment_ids=[]int}
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /internal/attachments/claim [post]
+func (h *AttachmentHandler) ClaimChatAttachments(c *gin.Context) {
+	var request struct {
+		OwnerID       int64   `json:"owner_id" binding:"required"`
+		RoomID        int64   `json:"room_id" binding:"required"`
+		AttachmentIDs []int64 `json:"attachment_ids"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	ids, err := h.uc.ClaimForChat(c.Request.Context(), request.OwnerID, request.RoomID, request.AttachmentIDs)
+	if isAttachmentError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		respondError(c, h.logger, attachmentErrors, err, "Failed to attach files")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachment_ids": ids})
+}
+
+func (h *AttachmentHandler) serve(c *gin.Context, thumbnail bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	attachment, content, err := h.uc.Open(c.Request.Context(), token, id, thumbnail)
+	if err != nil {
+		respondError(c, h.logger, attachmentErrors, err, "Failed to read file")
+		return
+	}
+	defer content.Close()
+
+	contentType, size := attachment.ContentType, attachment.Size
+	if thumbnail {
+		contentType, size = attachment.ThumbnailContentType(), -1
+	} else if contentType == "text/plain" {
+		contentType = "text/plain; charset=utf-8"
+	}
+
+	// Только изображения показываются в браузере; остальное скачивается, чтобы
+	// загруженный файл не выполнялся в контексте сайта
+	disposition := "attachment"
+	if attachment.IsImage() {
+		disposition = "inline"
+	}
+
+	c.DataFromReader(http.StatusOK, size, contentType, content, map[string]string{
+		"Content-Disposition":     mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options":  "nosniff",
+		"Content-Security-Policy": "default-src 'none'; sandbox",
+		// Содержимое вложения с данным ID не меняется, но доступ к нему может закрыться,
+		// поэтому общие кэши его не сохраняют
+		"Cache-Control": "private, max-age=31536000, immutable",
+	})
+}
+
+// attachmentErrors - ответы AttachmentHandler на ошибки usecase
+var attachmentErrors = []errorResponse{
+	{entity.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge, ""},
+	{entity.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, ""},
+	{repository.ErrAttachmentNotFound, http.StatusNotFound, "Attachment not found"},
+	{blobstore.ErrNotFound, http.StatusNotFound, "Attachment not found"},
+}
+
+// isAttachmentError сообщает, что пост или комментарий отклонен из-за списка вложений
+func isAttachmentError(err error) bool {
+	return errors.Is(err, entity.ErrTooManyAttachments) || errors.Is(err, entity.ErrInvalidAttachment)
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAttachmentUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentUsecase) Upload(ctx context.Context, token, filename string, r io.Reader) (*entity.Attachment, error) {
+	data, _ := io.ReadAll(r)
+	args := m.Called(ctx, token, filename, string(data))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentUsecase) Open(ctx context.Context, token string, id int64, thumbnail bool) (*entity.Attachment, io.ReadCloser, error) {
+	args := m.Called(ctx, token, id, thumbnail)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*entity.Attachment), io.NopCloser(strings.NewReader(args.String(1))), args.Error(2)
+}
+
+func (m *MockAttachmentUsecase) DeleteAttachment(ctx context.Context, token string, id int64) error {
+	args := m.Called(ctx, token, id)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentUsecase) ClaimForChat(ctx context.Context, ownerID, roomID int64, ids []int64) ([]int64, error) {
+	args := m.Called(ctx, ownerID, roomID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func setupAttachmentRouter(uc *MockAttachmentUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewAttachmentHandler(uc, log)
+
+	router := gin.New()
+	router.POST("/attachments", h.UploadAttachment)
+	router.GET("/attachments/:id", h.GetAttachment)
+	router.GET("/attachments/:id/thumbnail", h.GetThumbnail)
+	router.DELETE("/attachments/:id", h.DeleteAttachment)
+	router.POST("/internal/attachments/claim", RequireInternalToken("secret"), h.ClaimChatAttachments)
+	return router
+}
+
+func multipartBody(t *testing.T, field, filename, content string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	assert.NoError(t, w.WriteField("comment", "ignored"))
+	part, err := w.CreateFormFile(field, filename)
+	assert.NoError(t, err)
+	part.Write([]byte(content))
+	assert.NoError(t, w.Close())
+	return &body, w.FormDataContentType()
+}
+
+func TestAttachmentHandler_Upload(t *testing.T) {
+	tests := []struct {
+		name           string
+		field          string
+		setup          func(uc *MockAttachmentUsecase)
+		expectedStatus int
+	}{
+		{
+			name:  "Success",
+			field: "file",
+			setup: func(uc *MockAttachmentUsecase) {
+				uc.On("Upload", mock.Anything, "valid-token", "notes.txt", "hello").
+					Return(&entity.Attachment{ID: 5, Filename: "notes.txt", URL: "/api/v1/attachments/5"}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:  "Too large",
+			field: "file",
+			setup: func(uc *MockAttachmentUsecase) {
+				uc.On("Upload", mock.Anything, "valid-token", "notes.txt", "hello").
+					Return(nil, entity.ErrAttachmentTooLarge).Once()
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:  "Unsupported type",
+			field: "file",
+			setup: func(uc *MockAttachmentUsecase) {
+				uc.On("Upload", mock.Anything, "valid-token", "notes.txt", "hello").
+					Return(nil, entity.ErrUnsupportedMediaType).Once()
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "Missing file field",
+			field:          "document",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockAttachmentUsecase)
+			if tt.setup != nil {
+				tt.setup(uc)
+			}
+			router := setupAttachmentRouter(uc)
+
+			body, contentType := multipartBody(t, tt.field, "notes.txt", "hello")
+			req := httptest.NewRequest("POST", "/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+
+	// JSON вместо формы
+	router := setupAttachmentRouter(new(MockAttachmentUsecase))
+	req := httptest.NewRequest("POST", "/attachments", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAttachmentHandler_Serve(t *testing.T) {
+	uc := new(MockAttachmentUsecase)
+	router := setupAttachmentRouter(uc)
+
+	photo := &entity.Attachment{ID: 5, Filename: "фото.jpg", ContentType: "image/jpeg", Size: 4, ThumbnailKey: "k_thumb"}
+	uc.On("Open", mock.Anything, "", int64(5), false).Return(photo, "jpeg", nil).Once()
+	uc.On("Open", mock.Anything, "", int64(5), true).Return(photo, "thumb", nil).Once()
+	uc.On("Open", mock.Anything, "", int64(6), false).
+		Return(&entity.Attachment{ID: 6, Filename: "notes.txt", ContentType: "text/plain", Size: 5}, "hello", nil).Once()
+	uc.On("Open", mock.Anything, "", int64(7), false).Return(nil, "", repository.ErrAttachmentNotFound).Once()
+	// Файл сообщения в приватной комнате: участнику отдается, остальным - 404
+	uc.On("Open", mock.Anything, "member-token", int64(8), false).Return(photo, "jpeg", nil).Once()
+	uc.On("Open", mock.Anything, "outsider-token", int64(8), false).Return(nil, "", repository.ErrAttachmentNotFound).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/attachments/5", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "jpeg", w.Body.String())
+	assert.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "inline; filename*=utf-8''%D1%84%D0%BE%D1%82%D0%BE.jpg", w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "private, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/attachments/5/thumbnail", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "thumb", w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/attachments/6", nil))
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=notes.txt", w.Header().Get("Content-Disposition"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/attachments/7", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	for token, status := range map[string]int{"member-token": http.StatusOK, "outsider-token": http.StatusNotFound} {
+		req := httptest.NewRequest("GET", "/attachments/8", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/attachments/abc", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestAttachmentHandler_Delete(t *testing.T) {
+	uc := new(MockAttachmentUsecase)
+	router := setupAttachmentRouter(uc)
+
+	uc.On("DeleteAttachment", mock.Anything, "valid-token", int64(5)).Return(nil).Once()
+	uc.On("DeleteAttachment", mock.Anything, "valid-token", int64(6)).Return(usecase.ErrForbidden).Once()
+
+	for id, status := range map[string]int{"5": http.StatusOK, "6": http.StatusForbidden} {
+		req := httptest.NewRequest("DELETE", "/attachments/"+id, nil)
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code)
+	}
+
+	uc.AssertExpectations(t)
+}
+
+func TestAttachmentHandler_ClaimChatAttachments(t *testing.T) {
+	uc := new(MockAttachmentUsecase)
+	router := setupAttachmentRouter(uc)
+
+	uc.On("ClaimForChat", mock.Anything, int64(7), int64(3), []int64{5, 5}).Return([]int64{5}, nil).Once()
+	uc.On("ClaimForChat", mock.Anything, int64(7), int64(3), []int64{6}).Return(nil, entity.ErrInvalidAttachment).Once()
+
+	call := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/internal/attachments/claim", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(InternalTokenHeader, token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call("secret", `{"owner_id":7,"room_id":3,"attachment_ids":[5,5]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"attachment_ids":[5]}`, w.Body.String())
+	assert.Equal(t, http.StatusBadRequest, call("secret", `{"owner_id":7,"room_id":3,"attachment_ids":[6]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call("secret", `{"room_id":3,"attachment_ids":[6]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call("secret", `{"owner_id":7,"attachment_ids":[6]}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, call("wrong", `{"owner_id":7,"room_id":3,"attachment_ids":[5]}`).Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestCreatePost_InvalidAttachment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+
+	postUC := new(MockPostUsecase)
+	router := gin.New()
+	router.POST("/posts", NewPostHandler(postUC, log).CreatePost)
+
+	postUC.On("CreatePost", mock.Anything, "valid-token", "Title", "Content", []string(nil), []int64{9}, (*entity.PollInput)(nil)).
+		Return(nil, entity.ErrInvalidAttachment).Once()
+
+	req := httptest.NewRequest("POST", "/posts", strings.NewReader(`{"title":"Title","content":"Content","attachment_ids":[9]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	postUC.AssertExpectations(t)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalTokenHeader - заголовок с секретом для внутренних запросов между сервисами
+const InternalTokenHeader = "X-Internal-Token"
+
+// bearerToken извлекает токен из заголовка Authorization.
+// Если заголовок отсутствует, отвечает 401 и возвращает false.
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return "", false
+	}
+	return strings.TrimPrefix(authHeader, "Bearer "), true
+}
+
+// RequireInternalToken закрывает группу /internal общим секретом сервисов.
+// С пустым token отклоняются все запросы.
+func RequireInternalToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := c.GetHeader(InternalTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid internal token"})
+			return
+		}
+		c.Next()
+	}
+}
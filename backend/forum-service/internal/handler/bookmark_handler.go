@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookmarkHandler struct {
+	uc     usecase.BookmarkUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewBookmarkHandler(uc usecase.BookmarkUsecaseInterface, logger *logger.Logger) *BookmarkHandler {
+	return &BookmarkHandler{uc: uc, logger: logger}
+}
+
+type bookmarkRequest struct {
+	Folder string   `json:"folder" example:"read later"`
+	Tags   []string `json:"tags" example:"go,tips"`
+}
+
+// BookmarkPost godoc
+// @Summary Bookmark a post
+// @Description Save a post for later. Calling it again moves the bookmark to another folder and replaces its tags
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param request body bookmarkRequest false "Folder and tags"
+// @Success 200 {object} entity.Bookmark
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/bookmark [post]
+func (h *BookmarkHandler) BookmarkPost(c *gin.Context) {
+	h.add(c, entity.BookmarkTargetPost, "Invalid post ID")
+}
+
+// UnbookmarkPost godoc
+// @Summary Remove a post bookmark
+// @Tags bookmarks
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/bookmark [delete]
+func (h *BookmarkHandler) UnbookmarkPost(c *gin.Context) {
+	h.remove(c, entity.BookmarkTargetPost, "Invalid post ID")
+}
+
+// BookmarkComment godoc
+// @Summary Bookmark a comment
+// @Description Save a comment for later. Calling it again moves the bookmark to another folder and replaces its tags
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Comment ID"
+// @Param request body bookmarkRequest false "Folder and tags"
+// @Success 200 {object} entity.Bookmark
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/comments/{id}/bookmark [post]
+func (h *BookmarkHandler) BookmarkComment(c *gin.Context) {
+	h.add(c, entity.BookmarkTargetComment, "Invalid comment ID")
+}
+
+// UnbookmarkComment godoc
+// @Summary Remove a comment bookmark
+// @Tags bookmarks
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Comment ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/comments/{id}/bookmark [delete]
+func (h *BookmarkHandler) UnbookmarkComment(c *gin.Context) {
+	h.remove(c, entity.BookmarkTargetComment, "Invalid comment ID")
+}
+
+// GetBookmarks godoc
+// @Summary Get bookmarks
+// @Description Get the current user's bookmarks, newest first, optionally filtered by folder and tag
+// @Tags bookmarks
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param folder query string false "Folder name"
+// @Param tag query string false "Tag"
+// @Param limit query int false "Page size" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} entity.BookmarkList
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/me/bookmarks [get]
+func (h *BookmarkHandler) GetBookmarks(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	filter := entity.BookmarkFilter{Folder: c.Query("folder"), Tag: c.Query("tag")}
+	var err error
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+	}
+
+	list, err := h.uc.GetBookmarks(c.Request.Context(), token, filter)
+	if err != nil {
+		respondError(c, h.logger, bookmarkErrors, err, "Failed to get bookmarks")
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetFolders godoc
+// @Summary Get bookmark folders
+// @Description Get the current user's bookmark folders with the number of bookmarks in each. Bookmarks without a folder are counted under an empty name
+// @Tags bookmarks
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} map[string][]entity.BookmarkFolder
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/me/bookmarks/folders [get]
+func (h *BookmarkHandler) GetFolders(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	folders, err := h.uc.GetFolders(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, h.logger, bookmarkErrors, err, "Failed to get bookmark folders")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"folders": folders})
+}
+
+func (h *BookmarkHandler) add(c *gin.Context, target entity.BookmarkTarget, invalidID string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	// Тело необязательно: без него закладка попадает в корень без тегов
+	var request bookmarkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+
+	bookmark, err := h.uc.AddBookmark(c.Request.Context(), token, target, id, request.Folder, request.Tags)
+	if err != nil {
+		respondError(c, h.logger, bookmarkErrors, err, "Failed to save bookmark")
+		return
+	}
+
+	c.JSON(http.StatusOK, bookmark)
+}
+
+func (h *BookmarkHandler) remove(c *gin.Context, target entity.BookmarkTarget, invalidID string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.RemoveBookmark(c.Request.Context(), token, target, id); err != nil {
+		respondError(c, h.logger, bookmarkErrors, err, "Failed to remove bookmark")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bookmark removed successfully"})
+}
+
+// bookmarkErrors - ответы BookmarkHandler на ошибки usecase
+var bookmarkErrors = []errorResponse{
+	{repository.ErrPostNotFound, http.StatusNotFound, "Post not found"},
+	{repository.ErrCommentNotFound, http.StatusNotFound, "Comment not found"},
+	{repository.ErrBookmarkNotFound, http.StatusNotFound, "Bookmark not found"},
+	{entity.ErrTooManyBookmarkTags, http.StatusBadRequest, ""},
+	{entity.ErrBookmarkTagTooLong, http.StatusBadRequest, ""},
+	{entity.ErrBookmarkFolderLong, http.StatusBadRequest, ""},
+}
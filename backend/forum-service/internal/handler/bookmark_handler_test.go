@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBookmarkUsecase struct {
+	mock.Mock
+}
+
+func (m *MockBookmarkUsecase) AddBookmark(ctx context.Context, token string, target entity.BookmarkTarget, id int64, folder string, tags []string) (*entity.Bookmark, error) {
+	args := m.Called(ctx, token, target, id, folder, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Bookmark), args.Error(1)
+}
+
+func (m *MockBookmarkUsecase) RemoveBookmark(ctx context.Context, token string, target entity.BookmarkTarget, id int64) error {
+	args := m.Called(ctx, token, target, id)
+	return args.Error(0)
+}
+
+func (m *MockBookmarkUsecase) GetBookmarks(ctx context.Context, token string, filter entity.BookmarkFilter) (*entity.BookmarkList, error) {
+	args := m.Called(ctx, token, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.BookmarkList), args.Error(1)
+}
+
+func (m *MockBookmarkUsecase) GetFolders(ctx context.Context, token string) ([]entity.BookmarkFolder, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.BookmarkFolder), args.Error(1)
+}
+
+func (m *MockBookmarkUsecase) BookmarkedPostIDs(ctx context.Context, token string, postIDs []int64) (map[int64]bool, error) {
+	args := m.Called(ctx, token, postIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]bool), args.Error(1)
+}
+
+func setupBookmarkRouter(uc *MockBookmarkUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewBookmarkHandler(uc, log)
+
+	router := gin.New()
+	router.POST("/posts/:id/bookmark", h.BookmarkPost)
+	router.DELETE("/posts/:id/bookmark", h.UnbookmarkPost)
+	router.POST("/comments/:id/bookmark", h.BookmarkComment)
+	router.DELETE("/comments/:id/bookmark", h.UnbookmarkComment)
+	router.GET("/me/bookmarks", h.GetBookmarks)
+	router.GET("/me/bookmarks/folders", h.GetFolders)
+	return router
+}
+
+func TestBookmarkHandler_Add(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		setup          func(uc *MockBookmarkUsecase)
+		expectedStatus int
+	}{
+		{
+			name: "Post without body",
+			url:  "/posts/1/bookmark",
+			setup: func(uc *MockBookmarkUsecase) {
+				uc.On("AddBookmark", mock.Anything, "valid-token", entity.BookmarkTargetPost, int64(1), "", []string(nil)).
+					Return(&entity.Bookmark{ID: 5, PostID: 1}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Comment with folder and tags",
+			url:  "/comments/10/bookmark",
+			body: `{"folder":"later","tags":["go"]}`,
+			setup: func(uc *MockBookmarkUsecase) {
+				uc.On("AddBookmark", mock.Anything, "valid-token", entity.BookmarkTargetComment, int64(10), "later", []string{"go"}).
+					Return(&entity.Bookmark{ID: 6, PostID: 1}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Post not found",
+			url:  "/posts/2/bookmark",
+			setup: func(uc *MockBookmarkUsecase) {
+				uc.On("AddBookmark", mock.Anything, "valid-token", entity.BookmarkTargetPost, int64(2), "", []string(nil)).
+					Return(nil, repository.ErrPostNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Too many tags",
+			url:  "/posts/1/bookmark",
+			body: `{"tags":["a","b"]}`,
+			setup: func(uc *MockBookmarkUsecase) {
+				uc.On("AddBookmark", mock.Anything, "valid-token", entity.BookmarkTargetPost, int64(1), "", []string{"a", "b"}).
+					Return(nil, entity.ErrTooManyBookmarkTags).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid body",
+			url:            "/posts/1/bookmark",
+			body:           `{"tags":"go"}`,
+			setup:          func(uc *MockBookmarkUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid ID",
+			url:            "/comments/abc/bookmark",
+			setup:          func(uc *MockBookmarkUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockBookmarkUsecase)
+			tt.setup(uc)
+			router := setupBookmarkRouter(uc)
+
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer valid-token")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBookmarkHandler_Remove(t *testing.T) {
+	uc := new(MockBookmarkUsecase)
+	router := setupBookmarkRouter(uc)
+
+	uc.On("RemoveBookmark", mock.Anything, "valid-token", entity.BookmarkTargetPost, int64(1)).Return(nil).Once()
+	uc.On("RemoveBookmark", mock.Anything, "valid-token", entity.BookmarkTargetComment, int64(10)).
+		Return(repository.ErrBookmarkNotFound).Once()
+
+	req := httptest.NewRequest("DELETE", "/posts/1/bookmark", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("DELETE", "/comments/10/bookmark", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_GetBookmarks(t *testing.T) {
+	uc := new(MockBookmarkUsecase)
+	router := setupBookmarkRouter(uc)
+
+	uc.On("GetBookmarks", mock.Anything, "valid-token", entity.BookmarkFilter{Folder: "later", Tag: "go", Limit: 5, Offset: 10}).
+		Return(&entity.BookmarkList{Bookmarks: []entity.Bookmark{{ID: 1, PostID: 3, Folder: "later", Tags: []string{"go"}}}, Total: 11}, nil).Once()
+	uc.On("GetFolders", mock.Anything, "valid-token").
+		Return([]entity.BookmarkFolder{{Name: "later", Count: 1}}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/me/bookmarks?folder=later&tag=go&limit=5&offset=10", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list entity.BookmarkList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 11, list.Total)
+	assert.Equal(t, []string{"go"}, []string(list.Bookmarks[0].Tags))
+
+	req = httptest.NewRequest("GET", "/me/bookmarks/folders", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"folders":[{"name":"later","count":1}]}`, w.Body.String())
+
+	req = httptest.NewRequest("GET", "/me/bookmarks?offset=x", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestGetPosts_Bookmarked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+
+	postUC := new(MockPostUsecase)
+	bookmarks := new(MockBookmarkUsecase)
+	handler := NewPostHandler(postUC, log)
+	handler.Bookmarks = bookmarks
+
+	router := gin.New()
+	router.GET("/posts", handler.GetPosts)
+
+	posts := []*entity.Post{{ID: 1, AuthorID: 1}, {ID: 2, AuthorID: 1}}
+	postUC.On("GetPosts", mock.Anything, mock.Anything).Return(posts, map[int]string{1: "user1"}, nil)
+	bookmarks.On("BookmarkedPostIDs", mock.Anything, "valid-token", []int64{1, 2}).Return(map[int64]bool{2: true}, nil).Once()
+	bookmarks.On("BookmarkedPostIDs", mock.Anything, "expired", []int64{1, 2}).Return(nil, usecase.ErrInvalidToken).Once()
+
+	get := func(token string) []map[string]interface{} {
+		req := httptest.NewRequest("GET", "/posts", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	data := get("valid-token")
+	assert.Equal(t, false, data[0]["bookmarked"])
+	assert.Equal(t, true, data[1]["bookmarked"])
+
+	// Гостям и при недействительном токене флаг не выводится
+	for _, token := range []string{"", "expired"} {
+		data = get(token)
+		_, ok := data[0]["bookmarked"]
+		assert.False(t, ok)
+	}
+
+	bookmarks.AssertExpectations(t)
+}
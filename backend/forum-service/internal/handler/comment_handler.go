@@ -12,6 +12,7 @@ import (
 	pb "github.com/jaliks17/ffffforum/backend/proto"
 
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -38,6 +39,7 @@ func NewCommentHandler(commentUC usecase.CommentUseCaseInterface) *CommentHandle
 // @Success 201 {object} entity.Comment
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /api/v1/posts/{id}/comments [post]
 func (h *CommentHandler) CreateComment(c *gin.Context) {
@@ -63,7 +65,9 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	}
 
 	var request struct {
-		Content string `json:"content" binding:"required"`
+		Content       string  `json:"content" binding:"required"`
+		ParentID      *int64  `json:"parent_id"`
+		AttachmentIDs []int64 `json:"attachment_ids"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
@@ -75,10 +79,12 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	})
 
 	comment := entity.Comment{
-		Content:    request.Content,
-		AuthorID:   authResponse.UserId,
-		AuthorName: "Unknown",
-		PostID:     postID,
+		Content:       request.Content,
+		AuthorID:      authResponse.UserId,
+		AuthorName:    "Unknown",
+		PostID:        postID,
+		ParentID:      request.ParentID,
+		AttachmentIDs: request.AttachmentIDs,
 	}
 
 	if err == nil && userResponse != nil && userResponse.User != nil {
@@ -86,18 +92,40 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	}
 
 	if err := h.commentUC.CreateComment(c.Request.Context(), &comment); err != nil {
+		if errors.Is(err, usecase.ErrInvalidParent) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent comment"})
+			return
+		}
+		if isAttachmentError(err) || isFilterError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, entity.ErrPostLocked) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Post is locked"})
+			return
+		}
 		log.Printf("Error creating comment: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"id":          comment.ID,
 		"content":     comment.Content,
 		"author_id":   comment.AuthorID,
 		"post_id":     comment.PostID,
 		"author_name": comment.AuthorName,
-	})
+	}
+	if comment.ParentID != nil {
+		response["parent_id"] = *comment.ParentID
+	}
+	if len(comment.Attachments) > 0 {
+		response["attachments"] = comment.Attachments
+	}
+	if comment.Held {
+		response["held"] = true
+	}
+	c.JSON(http.StatusCreated, response)
 }
 
 // GetCommentsByPostID godoc
@@ -107,6 +135,7 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
+// @Param format query string false "Content representation" Enums(markdown, html, plain) default(markdown)
 // @Success 200 {object} []entity.Comment
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
@@ -119,6 +148,12 @@ func (h *CommentHandler) GetCommentsByPostID(c *gin.Context) {
 		return
 	}
 
+	format, err := entity.ParseContentFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	comments, err := h.commentUC.GetCommentsByPostID(c.Request.Context(), postID)
 	if err != nil {
 		log.Printf("Error getting comments: %v", err)
@@ -129,6 +164,10 @@ func (h *CommentHandler) GetCommentsByPostID(c *gin.Context) {
 		return
 	}
 
+	for i := range comments {
+		comments[i].ApplyFormat(format)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"comments": comments,
 	})
@@ -136,13 +175,14 @@ func (h *CommentHandler) GetCommentsByPostID(c *gin.Context) {
 
 // DeleteComment godoc
 // @Summary Delete a comment
-// @Description Delete a comment by ID (only author or admin can delete)
+// @Description Move a comment to the trash (only author or moderator can delete). The thread keeps a "[deleted]" placeholder
 // @Tags comments
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "Comment ID"
+// @Param reason query string false "Deletion reason"
 // @Success 200 {object} entity.SuccessResponse
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 401 {object} entity.ErrorResponse
@@ -172,9 +212,9 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 		return
 	}
 
-	if err := h.commentUC.DeleteComment(c.Request.Context(), commentID, authResponse.UserId); err != nil {
+	if err := h.commentUC.DeleteComment(c.Request.Context(), commentID, authResponse.UserId, authResponse.UserRole, c.Query("reason")); err != nil {
 		switch {
-		case errors.Is(err, usecase.ErrCommentNotFound):
+		case errors.Is(err, repository.ErrCommentNotFound), errors.Is(err, usecase.ErrCommentNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
 		case errors.Is(err, usecase.ErrForbidden):
 			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to delete this comment"})
@@ -187,6 +227,72 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
 }
 
+// UpdateComment godoc
+// @Summary Edit a comment
+// @Description Edit a comment. Authors can edit within the configured edit window, moderators and admins at any time
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Comment ID"
+// @Param request body object true "New comment content"
+// @Success 200 {object} entity.Comment
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/comments/{id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+
+	authResponse, err := h.commentUC.GetAuthClient().ValidateToken(c.Request.Context(), &pb.ValidateTokenRequest{
+		Token: token,
+	})
+	if err != nil || authResponse == nil || !authResponse.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return
+	}
+
+	var request struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	comment, err := h.commentUC.EditComment(c.Request.Context(), commentID, authResponse.UserId, authResponse.UserRole, request.Content)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCommentNotFound), errors.Is(err, usecase.ErrCommentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		case errors.Is(err, usecase.ErrEditWindowExpired):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Edit window has expired"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to edit this comment"})
+		default:
+			log.Printf("Error updating comment: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
 // Create godoc
 // @Summary Create a new comment
 // @Description Create a new comment for a post
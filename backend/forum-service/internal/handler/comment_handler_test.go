@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
 
 	pb "github.com/jaliks17/ffffforum/backend/proto"
@@ -97,6 +98,22 @@ func (m *MockAuthServiceClient) ValidateSession(ctx context.Context, in *pb.Vali
 	return args.Get(0).(*pb.ValidateSessionResponse), args.Error(1)
 }
 
+func (m *MockAuthServiceClient) GetUsersByUsernames(ctx context.Context, in *pb.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*pb.GetUsersByUsernamesResponse, error) {
+	args := m.Called(ctx, in, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.GetUsersByUsernamesResponse), args.Error(1)
+}
+
+func (m *MockAuthServiceClient) SuspendUser(ctx context.Context, in *pb.SuspendUserRequest, opts ...grpc.CallOption) (*pb.SuspendUserResponse, error) {
+	args := m.Called(ctx, in, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pb.SuspendUserResponse), args.Error(1)
+}
+
 type MockCommentUseCase struct {
 	mock.Mock
 	usecase.CommentUseCase
@@ -115,11 +132,19 @@ func (m *MockCommentUseCase) GetComment(ctx context.Context, id int64) (*entity.
 	return args.Get(0).(*entity.Comment), args.Error(1)
 }
 
-func (m *MockCommentUseCase) DeleteComment(ctx context.Context, id int64, userID int64) error {
-	args := m.Called(ctx, id, userID)
+func (m *MockCommentUseCase) DeleteComment(ctx context.Context, id int64, userID int64, role string, reason string) error {
+	args := m.Called(ctx, id, userID, role, reason)
 	return args.Error(0)
 }
 
+func (m *MockCommentUseCase) EditComment(ctx context.Context, id int64, userID int64, role string, content string) (*entity.Comment, error) {
+	args := m.Called(ctx, id, userID, role, content)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Comment), args.Error(1)
+}
+
 func (m *MockCommentUseCase) GetCommentsByPostID(ctx context.Context, postID int64) ([]entity.Comment, error) {
 	args := m.Called(ctx, postID)
 	return args.Get(0).([]entity.Comment), args.Error(1)
@@ -229,7 +254,7 @@ func TestCommentHandler_DeleteComment(t *testing.T) {
 
 			// Mock DeleteComment call only if auth is expected to succeed
 			if tt.authHeader != "" && tt.mockAuthResp != nil && tt.mockAuthResp.Valid {
-				mockUC.On("DeleteComment", mock.Anything, mock.Anything, tt.mockAuthResp.UserId).Return(tt.mockDeleteErr)
+				mockUC.On("DeleteComment", mock.Anything, mock.Anything, tt.mockAuthResp.UserId, tt.mockAuthResp.UserRole, "").Return(tt.mockDeleteErr)
 			}
 
 			// Execute
@@ -566,8 +591,8 @@ func TestCommentHandler_GetCommentsByPostID(t *testing.T) {
 			expectedStatus: 200,
 			expectedBody: map[string]interface{}{
 				"comments": []interface{}{
-					map[string]interface{}{"id": float64(1), "post_id": float64(1), "content": "Comment 1", "author_id": float64(1), "author_name": "user1", "CreatedAt": "0001-01-01T00:00:00Z"},
-					map[string]interface{}{"id": float64(2), "post_id": float64(1), "content": "Comment 2", "author_id": float64(2), "author_name": "user2", "CreatedAt": "0001-01-01T00:00:00Z"},
+					map[string]interface{}{"id": float64(1), "post_id": float64(1), "content": "Comment 1", "author_id": float64(1), "author_name": "user1", "created_at": "0001-01-01T00:00:00Z"},
+					map[string]interface{}{"id": float64(2), "post_id": float64(1), "content": "Comment 2", "author_id": float64(2), "author_name": "user2", "created_at": "0001-01-01T00:00:00Z"},
 				},
 			},
 		},
@@ -637,4 +662,143 @@ func TestCommentHandler_GetCommentsByPostID(t *testing.T) {
 			mockUC.AssertExpectations(t)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestCommentHandler_UpdateComment(t *testing.T) {
+	tests := []struct {
+		name           string
+		commentID      string
+		authHeader     string
+		body           string
+		mockAuthResp   *pb.ValidateSessionResponse
+		mockComment    *entity.Comment
+		mockEditErr    error
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:       "successful edit",
+			commentID:  "1",
+			authHeader: "Bearer valid-token",
+			body:       `{"content":"Edited"}`,
+			mockAuthResp: &pb.ValidateSessionResponse{
+				Valid:    true,
+				UserId:   1,
+				UserRole: "user",
+			},
+			mockComment:    &entity.Comment{ID: 1, PostID: 1, AuthorID: 1, Content: "Edited", AuthorName: "user1"},
+			expectedStatus: 200,
+		},
+		{
+			name:           "missing auth header",
+			commentID:      "1",
+			body:           `{"content":"Edited"}`,
+			expectedStatus: 401,
+			expectedBody: map[string]interface{}{
+				"error": "Authorization header is required",
+			},
+		},
+		{
+			name:       "empty content",
+			commentID:  "1",
+			authHeader: "Bearer valid-token",
+			body:       `{}`,
+			mockAuthResp: &pb.ValidateSessionResponse{
+				Valid:  true,
+				UserId: 1,
+			},
+			expectedStatus: 400,
+			expectedBody: map[string]interface{}{
+				"error": "Invalid request body",
+			},
+		},
+		{
+			name:       "edit window expired",
+			commentID:  "1",
+			authHeader: "Bearer valid-token",
+			body:       `{"content":"Edited"}`,
+			mockAuthResp: &pb.ValidateSessionResponse{
+				Valid:    true,
+				UserId:   1,
+				UserRole: "user",
+			},
+			mockEditErr:    usecase.ErrEditWindowExpired,
+			expectedStatus: 403,
+			expectedBody: map[string]interface{}{
+				"error": "Edit window has expired",
+			},
+		},
+		{
+			name:       "not the author",
+			commentID:  "1",
+			authHeader: "Bearer valid-token",
+			body:       `{"content":"Edited"}`,
+			mockAuthResp: &pb.ValidateSessionResponse{
+				Valid:    true,
+				UserId:   2,
+				UserRole: "user",
+			},
+			mockEditErr:    usecase.ErrForbidden,
+			expectedStatus: 403,
+			expectedBody: map[string]interface{}{
+				"error": "You do not have permission to edit this comment",
+			},
+		},
+		{
+			name:       "comment not found",
+			commentID:  "999",
+			authHeader: "Bearer valid-token",
+			body:       `{"content":"Edited"}`,
+			mockAuthResp: &pb.ValidateSessionResponse{
+				Valid:    true,
+				UserId:   1,
+				UserRole: "moderator",
+			},
+			mockEditErr:    repository.ErrCommentNotFound,
+			expectedStatus: 404,
+			expectedBody: map[string]interface{}{
+				"error": "Comment not found",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("PUT", "/comments/"+tt.commentID, bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.authHeader != "" {
+				c.Request.Header.Set("Authorization", tt.authHeader)
+			}
+			c.Params = []gin.Param{{Key: "id", Value: tt.commentID}}
+
+			mockUC := new(MockCommentUseCase)
+			h := NewCommentHandler(mockUC)
+
+			mockAuthClient := new(MockAuthServiceClient)
+			mockUC.On("GetAuthClient").Return(mockAuthClient).Maybe()
+			if tt.authHeader != "" {
+				mockAuthClient.On("ValidateToken", mock.Anything, mock.Anything, mock.Anything).Return(tt.mockAuthResp, nil).Once()
+			}
+
+			if tt.mockComment != nil || tt.mockEditErr != nil {
+				commentID, _ := strconv.ParseInt(tt.commentID, 10, 64)
+				mockUC.On("EditComment", mock.Anything, commentID, tt.mockAuthResp.UserId, tt.mockAuthResp.UserRole, "Edited").
+					Return(tt.mockComment, tt.mockEditErr).Once()
+			}
+
+			h.UpdateComment(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				expectedJSON, _ := json.Marshal(tt.expectedBody)
+				assert.JSONEq(t, string(expectedJSON), w.Body.String())
+			}
+
+			mockUC.AssertExpectations(t)
+			mockAuthClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ContentFilterHandler struct {
+	uc     usecase.ContentFilterUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewContentFilterHandler(uc usecase.ContentFilterUsecaseInterface, logger *logger.Logger) *ContentFilterHandler {
+	return &ContentFilterHandler{uc: uc, logger: logger}
+}
+
+// GetRules godoc
+// @Summary List content filter rules
+// @Description List banned words and regular expressions with their actions (admins only)
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} map[string][]entity.FilterRule
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/admin/filter/rules [get]
+func (h *ContentFilterHandler) GetRules(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	rules, err := h.uc.GetRules(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, h.logger, filterErrors, err, "Failed to get filter rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// CreateRule godoc
+// @Summary Add a content filter rule
+// @Description Add a banned word (matched as a whole word, case-insensitive) or an RE2 regular expression. Action reject refuses the write, mask replaces matches with asterisks, hold publishes the content hidden until a moderator reviews it (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param request body entity.FilterRuleInput true "Rule"
+// @Success 201 {object} entity.FilterRule
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/admin/filter/rules [post]
+func (h *ContentFilterHandler) CreateRule(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request entity.FilterRuleInput
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	rule, err := h.uc.CreateRule(c.Request.Context(), token, request)
+	if err != nil {
+		respondError(c, h.logger, filterErrors, err, "Failed to create filter rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// DeleteRule godoc
+// @Summary Delete a content filter rule
+// @Description Delete a content filter rule (admins only)
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Rule ID"
+// @Success 204
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/admin/filter/rules/{id} [delete]
+func (h *ContentFilterHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteRule(c.Request.Context(), token, id); err != nil {
+		respondError(c, h.logger, filterErrors, err, "Failed to delete filter rule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CheckContent godoc
+// @Summary Check text with the content filter
+// @Description Internal endpoint used by the chat service to run a message through the content filter before saving it. Returns the verdict and the masked text
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param X-Internal-Token header string true "Shared internal token"
+// @Param request body entity.ContentCheck true "Text to check"
+// @Success 200 {object} entity.FilterVerdict
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /internal/content/check [post]
+func (h *ContentFilterHandler) CheckContent(c *gin.Context) {
+	var request entity.ContentCheck
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	verdict, err := h.uc.Check(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, h.logger, filterErrors, err, "Failed to check content")
+		return
+	}
+
+	c.JSON(http.StatusOK, verdict)
+}
+
+// RecordContent godoc
+// @Summary Record published text for duplicate detection
+// @Description Internal endpoint the chat service calls after it saves a message, so that later copies of the text are caught as duplicates
+// @Tags internal
+// @Accept json
+// @Param X-Internal-Token header string true "Shared internal token"
+// @Param request body entity.ContentCheck true "Saved text"
+// @Success 204
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Router /internal/content/record [post]
+func (h *ContentFilterHandler) RecordContent(c *gin.Context) {
+	var request entity.ContentCheck
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	h.uc.Record(c.Request.Context(), request)
+	c.Status(http.StatusNoContent)
+}
+
+// filterErrors - ответы ContentFilterHandler на ошибки usecase
+var filterErrors = []errorResponse{
+	{entity.ErrInvalidFilterRule, http.StatusBadRequest, ""},
+	{entity.ErrFilterRuleNotFound, http.StatusNotFound, ""},
+}
+
+// isFilterError сообщает, что фильтр контента отклонил запись
+func isFilterError(err error) bool {
+	return errors.Is(err, entity.ErrContentRejected) || errors.Is(err, entity.ErrDuplicateContent)
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockContentFilterUsecase struct {
+	mock.Mock
+}
+
+func (m *MockContentFilterUsecase) GetRules(ctx context.Context, token string) ([]entity.FilterRule, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.FilterRule), args.Error(1)
+}
+
+func (m *MockContentFilterUsecase) CreateRule(ctx context.Context, token string, in entity.FilterRuleInput) (*entity.FilterRule, error) {
+	args := m.Called(ctx, token, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FilterRule), args.Error(1)
+}
+
+func (m *MockContentFilterUsecase) DeleteRule(ctx context.Context, token string, id int64) error {
+	args := m.Called(ctx, token, id)
+	return args.Error(0)
+}
+
+func (m *MockContentFilterUsecase) Check(ctx context.Context, in entity.ContentCheck) (*entity.FilterVerdict, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FilterVerdict), args.Error(1)
+}
+
+func (m *MockContentFilterUsecase) Record(ctx context.Context, in entity.ContentCheck) {
+	m.Called(ctx, in)
+}
+
+func setupContentFilterRouter(uc *MockContentFilterUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewContentFilterHandler(uc, log)
+
+	router := gin.New()
+	router.GET("/admin/filter/rules", h.GetRules)
+	router.POST("/admin/filter/rules", h.CreateRule)
+	router.DELETE("/admin/filter/rules/:id", h.DeleteRule)
+	internal := router.Group("/internal", RequireInternalToken("secret"))
+	internal.POST("/content/check", h.CheckContent)
+	internal.POST("/content/record", h.RecordContent)
+	return router
+}
+
+func TestContentFilterHandler_Rules(t *testing.T) {
+	input := entity.FilterRuleInput{Pattern: "casino", Action: entity.FilterActionHold}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setup          func(uc *MockContentFilterUsecase)
+		expectedStatus int
+	}{
+		{
+			name: "List", method: "GET", path: "/admin/filter/rules",
+			setup: func(uc *MockContentFilterUsecase) {
+				uc.On("GetRules", mock.Anything, "valid-token").Return([]entity.FilterRule{{ID: 1}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "List as moderator", method: "GET", path: "/admin/filter/rules",
+			setup: func(uc *MockContentFilterUsecase) {
+				uc.On("GetRules", mock.Anything, "valid-token").Return(nil, usecase.ErrForbidden).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "Create", method: "POST", path: "/admin/filter/rules", body: `{"pattern":"casino","action":"hold"}`,
+			setup: func(uc *MockContentFilterUsecase) {
+				uc.On("CreateRule", mock.Anything, "valid-token", input).Return(&entity.FilterRule{ID: 2, Pattern: "casino", Action: entity.FilterActionHold}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "Create invalid rule", method: "POST", path: "/admin/filter/rules", body: `{"pattern":"casino","action":"hold"}`,
+			setup: func(uc *MockContentFilterUsecase) {
+				uc.On("CreateRule", mock.Anything, "valid-token", input).Return(nil, entity.ErrInvalidFilterRule).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Delete", method: "DELETE", path: "/admin/filter/rules/2",
+			setup: func(uc *MockContentFilterUsecase) {
+				uc.On("DeleteRule", mock.Anything, "valid-token", int64(2)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Delete missing", method: "DELETE", path: "/admin/filter/rules/9",
+			setup: func(uc *MockContentFilterUsecase) {
+				uc.On("DeleteRule", mock.Anything, "valid-token", int64(9)).Return(entity.ErrFilterRuleNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Delete invalid id", method: "DELETE", path: "/admin/filter/rules/abc",
+			setup:          func(uc *MockContentFilterUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockContentFilterUsecase)
+			tt.setup(uc)
+			router := setupContentFilterRouter(uc)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer valid-token")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContentFilterHandler_CheckContent(t *testing.T) {
+	uc := new(MockContentFilterUsecase)
+	router := setupContentFilterRouter(uc)
+
+	check := entity.ContentCheck{UserID: 5, Kind: entity.ContentKindMessage, Text: "darn"}
+	uc.On("Check", mock.Anything, check).
+		Return(&entity.FilterVerdict{Action: entity.FilterActionMask, Text: "****", Reason: "banned word"}, nil).Once()
+
+	req := httptest.NewRequest("POST", "/internal/content/check", strings.NewReader(`{"user_id":5,"kind":"message","text":"darn"}`))
+	req.Header.Set(InternalTokenHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var verdict entity.FilterVerdict
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &verdict))
+	assert.Equal(t, "****", verdict.Text)
+
+	req = httptest.NewRequest("POST", "/internal/content/check", strings.NewReader(`{"user_id":5,"kind":"message","text":"darn"}`))
+	req.Header.Set(InternalTokenHeader, "wrong")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestContentFilterHandler_RecordContent(t *testing.T) {
+	uc := new(MockContentFilterUsecase)
+	router := setupContentFilterRouter(uc)
+
+	uc.On("Record", mock.Anything, entity.ContentCheck{UserID: 5, Kind: entity.ContentKindMessage, Text: "join my channel"}).Once()
+
+	req := httptest.NewRequest("POST", "/internal/content/record", strings.NewReader(`{"user_id":5,"kind":"message","text":"join my channel"}`))
+	req.Header.Set(InternalTokenHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = httptest.NewRequest("POST", "/internal/content/record", strings.NewReader(`{"user_id":5,"kind":"message","text":"join my channel"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	uc.AssertExpectations(t)
+}
@@ -56,4 +56,14 @@ func (m *MockAuthClient) SignUp(ctx context.Context, in *pb.SignUpRequest, opts
 func (m *MockAuthClient) ValidateSession(ctx context.Context, in *pb.ValidateSessionRequest, opts ...grpc.CallOption) (*pb.ValidateSessionResponse, error) {
 	args := m.Called(ctx, in, opts)
 	return args.Get(0).(*pb.ValidateSessionResponse), args.Error(1)
+}
+
+func (m *MockAuthClient) GetUsersByUsernames(ctx context.Context, in *pb.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*pb.GetUsersByUsernamesResponse, error) {
+	args := m.Called(ctx, in, opts)
+	return args.Get(0).(*pb.GetUsersByUsernamesResponse), args.Error(1)
+}
+
+func (m *MockAuthClient) SuspendUser(ctx context.Context, in *pb.SuspendUserRequest, opts ...grpc.CallOption) (*pb.SuspendUserResponse, error) {
+	args := m.Called(ctx, in, opts)
+	return args.Get(0).(*pb.SuspendUserResponse), args.Error(1)
 } 
\ No newline at end of file
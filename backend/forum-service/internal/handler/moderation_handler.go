@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	uc     usecase.ModerationUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewModerationHandler(uc usecase.ModerationUsecaseInterface, logger *logger.Logger) *ModerationHandler {
+	return &ModerationHandler{uc: uc, logger: logger}
+}
+
+// SetPostFlags godoc
+// @Summary Pin, lock or announce a post
+// @Description Change post flags. Pinned posts and announcements go first in feeds, locked posts reject new comments. Pin and lock require a moderator, announcements require an admin. Omitted flags stay unchanged; every change is written to the moderation log
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param request body entity.PostFlagsUpdate true "Flags to change"
+// @Success 200 {object} entity.PostFlags
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/flags [patch]
+func (h *ModerationHandler) SetPostFlags(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request entity.PostFlagsUpdate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	flags, err := h.uc.SetPostFlags(c.Request.Context(), token, postID, request)
+	if err != nil {
+		respondError(c, h.logger, moderationErrors, err, "Failed to update post flags")
+		return
+	}
+
+	c.JSON(http.StatusOK, flags)
+}
+
+// GetModerationLog godoc
+// @Summary Moderation log
+// @Description List moderator actions, newest first (moderators only)
+// @Tags moderation
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param target_type query string false "Target type, e.g. post"
+// @Param target_id query int false "Target ID"
+// @Param actor_id query int false "Moderator ID"
+// @Param limit query int false "Page size" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string][]entity.ModerationLogEntry
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/mod/log [get]
+func (h *ModerationHandler) GetModerationLog(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	filter := entity.ModerationLogFilter{TargetType: entity.ModerationTarget(c.Query("target_type"))}
+	params := []struct {
+		name string
+		dst  *int64
+	}{
+		{"target_id", &filter.TargetID},
+		{"actor_id", &filter.ActorID},
+	}
+	for _, p := range params {
+		if v := c.Query(p.name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + p.name})
+				return
+			}
+			*p.dst = n
+		}
+	}
+	var err error
+	if filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "0")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	if filter.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	entries, err := h.uc.GetModerationLog(c.Request.Context(), token, filter)
+	if err != nil {
+		respondError(c, h.logger, moderationErrors, err, "Failed to get moderation log")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// moderationErrors - ответы ModerationHandler на ошибки usecase
+var moderationErrors = []errorResponse{
+	{entity.ErrEmptyFlagUpdate, http.StatusBadRequest, ""},
+	{repository.ErrPostNotFound, http.StatusNotFound, "Post not found"},
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockModerationUsecase struct {
+	mock.Mock
+}
+
+func (m *MockModerationUsecase) SetPostFlags(ctx context.Context, token string, postID int64, update entity.PostFlagsUpdate) (*entity.PostFlags, error) {
+	args := m.Called(ctx, token, postID, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostFlags), args.Error(1)
+}
+
+func (m *MockModerationUsecase) GetModerationLog(ctx context.Context, token string, filter entity.ModerationLogFilter) ([]entity.ModerationLogEntry, error) {
+	args := m.Called(ctx, token, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ModerationLogEntry), args.Error(1)
+}
+
+func setupModerationRouter(uc *MockModerationUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewModerationHandler(uc, log)
+
+	router := gin.New()
+	router.PATCH("/posts/:id/flags", h.SetPostFlags)
+	router.GET("/mod/log", h.GetModerationLog)
+	return router
+}
+
+func TestModerationHandler_SetPostFlags(t *testing.T) {
+	on := true
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(uc *MockModerationUsecase)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: `{"pinned":true}`,
+			setup: func(uc *MockModerationUsecase) {
+				uc.On("SetPostFlags", mock.Anything, "valid-token", int64(3), entity.PostFlagsUpdate{Pinned: &on}).
+					Return(&entity.PostFlags{Pinned: true}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Forbidden",
+			body: `{"announcement":true}`,
+			setup: func(uc *MockModerationUsecase) {
+				uc.On("SetPostFlags", mock.Anything, "valid-token", int64(3), entity.PostFlagsUpdate{Announcement: &on}).
+					Return(nil, usecase.ErrForbidden).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "Empty update",
+			body: `{}`,
+			setup: func(uc *MockModerationUsecase) {
+				uc.On("SetPostFlags", mock.Anything, "valid-token", int64(3), entity.PostFlagsUpdate{}).
+					Return(nil, entity.ErrEmptyFlagUpdate).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Post not found",
+			body: `{"locked":true}`,
+			setup: func(uc *MockModerationUsecase) {
+				uc.On("SetPostFlags", mock.Anything, "valid-token", int64(3), entity.PostFlagsUpdate{Locked: &on}).
+					Return(nil, repository.ErrPostNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockModerationUsecase)
+			tt.setup(uc)
+			router := setupModerationRouter(uc)
+
+			req := httptest.NewRequest("PATCH", "/posts/3/flags", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer valid-token")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerationHandler_GetModerationLog(t *testing.T) {
+	uc := new(MockModerationUsecase)
+	router := setupModerationRouter(uc)
+
+	filter := entity.ModerationLogFilter{TargetType: entity.ModerationTargetPost, TargetID: 3, Limit: 10}
+	uc.On("GetModerationLog", mock.Anything, "valid-token", filter).
+		Return([]entity.ModerationLogEntry{{ID: 1, Action: entity.ModerationActionPin}}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/mod/log?target_type=post&target_id=3&limit=10", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"action":"pin"`)
+
+	req = httptest.NewRequest("GET", "/mod/log?actor_id=abc", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	uc.AssertExpectations(t)
+}
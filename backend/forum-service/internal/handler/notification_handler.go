@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	uc     usecase.NotificationUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewNotificationHandler(uc usecase.NotificationUsecaseInterface, logger *logger.Logger) *NotificationHandler {
+	return &NotificationHandler{uc: uc, logger: logger}
+}
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Description Get the current user's notifications, newest first, together with the unread count
+// @Tags notifications
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Page size" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} entity.NotificationList
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var filter entity.NotificationFilter
+	var err error
+	if v := c.Query("unread"); v != "" {
+		if filter.UnreadOnly, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unread value"})
+			return
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+	}
+
+	list, err := h.uc.GetNotifications(c.Request.Context(), token, filter)
+	if err != nil {
+		respondError(c, h.logger, notificationErrors, err, "Failed to get notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// MarkRead godoc
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Notification ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.MarkRead(c.Request.Context(), token, id); err != nil {
+		respondError(c, h.logger, notificationErrors, err, "Failed to mark notification as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} map[string]int64 "updated"
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.uc.MarkAllRead(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, h.logger, notificationErrors, err, "Failed to mark notifications as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// notificationErrors - ответы NotificationHandler на ошибки usecase
+var notificationErrors = []errorResponse{
+	{repository.ErrNotificationNotFound, http.StatusNotFound, "Notification not found"},
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationUsecase struct {
+	mock.Mock
+}
+
+func (m *MockNotificationUsecase) GetNotifications(ctx context.Context, token string, filter entity.NotificationFilter) (*entity.NotificationList, error) {
+	args := m.Called(ctx, token, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.NotificationList), args.Error(1)
+}
+
+func (m *MockNotificationUsecase) MarkRead(ctx context.Context, token string, id int64) error {
+	args := m.Called(ctx, token, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationUsecase) MarkAllRead(ctx context.Context, token string) (int64, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func setupNotificationRouter(uc *MockNotificationUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewNotificationHandler(uc, log)
+
+	router := gin.New()
+	router.GET("/notifications", h.GetNotifications)
+	router.POST("/notifications/read-all", h.MarkAllRead)
+	router.POST("/notifications/:id/read", h.MarkRead)
+	return router
+}
+
+func TestNotificationHandler_GetNotifications(t *testing.T) {
+	uc := new(MockNotificationUsecase)
+	router := setupNotificationRouter(uc)
+
+	uc.On("GetNotifications", mock.Anything, "valid-token", entity.NotificationFilter{UnreadOnly: true, Limit: 5, Offset: 10}).
+		Return(&entity.NotificationList{
+			Notifications: []entity.Notification{{ID: 1, Type: entity.NotificationMention}},
+			UnreadCount:   1,
+		}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/notifications?unread=true&limit=5&offset=10", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"type":"mention"`)
+	assert.Contains(t, w.Body.String(), `"unread_count":1`)
+
+	req = httptest.NewRequest("GET", "/notifications?limit=abc", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/notifications", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestNotificationHandler_MarkRead(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(uc *MockNotificationUsecase)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			url:  "/notifications/3/read",
+			setup: func(uc *MockNotificationUsecase) {
+				uc.On("MarkRead", mock.Anything, "valid-token", int64(3)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Not found",
+			url:  "/notifications/4/read",
+			setup: func(uc *MockNotificationUsecase) {
+				uc.On("MarkRead", mock.Anything, "valid-token", int64(4)).Return(repository.ErrNotificationNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Invalid token",
+			url:  "/notifications/5/read",
+			setup: func(uc *MockNotificationUsecase) {
+				uc.On("MarkRead", mock.Anything, "valid-token", int64(5)).Return(usecase.ErrInvalidToken).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid ID",
+			url:            "/notifications/abc/read",
+			setup:          func(uc *MockNotificationUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockNotificationUsecase)
+			tt.setup(uc)
+			router := setupNotificationRouter(uc)
+
+			req := httptest.NewRequest("POST", tt.url, nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationHandler_MarkAllRead(t *testing.T) {
+	uc := new(MockNotificationUsecase)
+	router := setupNotificationRouter(uc)
+
+	uc.On("MarkAllRead", mock.Anything, "valid-token").Return(int64(2), nil).Once()
+
+	req := httptest.NewRequest("POST", "/notifications/read-all", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"updated":2}`, w.Body.String())
+	uc.AssertExpectations(t)
+}
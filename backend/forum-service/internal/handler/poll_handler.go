@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PollHandler struct {
+	uc     usecase.PollUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewPollHandler(uc usecase.PollUsecaseInterface, logger *logger.Logger) *PollHandler {
+	return &PollHandler{uc: uc, logger: logger}
+}
+
+type pollVoteRequest struct {
+	OptionIDs []int64 `json:"option_ids" binding:"required" example:"3"`
+}
+
+// GetPoll godoc
+// @Summary Get poll results
+// @Description Get the post poll with live results. Public polls list voter IDs per option. With a token the response includes the options chosen by the user. Subscribe to the "poll:{id}" topic over chat WebSocket to receive updates
+// @Tags polls
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param Authorization header string false "Bearer token; adds my_votes"
+// @Success 200 {object} entity.Poll
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/poll [get]
+func (h *PollHandler) GetPoll(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	poll, err := h.uc.GetPoll(c.Request.Context(), token, postID)
+	if err != nil {
+		respondError(c, h.logger, pollErrors, err, "Failed to get poll")
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
+
+// VotePoll godoc
+// @Summary Vote in a poll
+// @Description Vote for one option (or several in a multiple choice poll). Each user votes once and cannot change the choice
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param request body pollVoteRequest true "Chosen options"
+// @Success 200 {object} entity.Poll
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/poll/vote [post]
+func (h *PollHandler) VotePoll(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request pollVoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	poll, err := h.uc.Vote(c.Request.Context(), token, postID, request.OptionIDs)
+	if err != nil {
+		respondError(c, h.logger, pollErrors, err, "Failed to vote")
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
+
+// ClosePoll godoc
+// @Summary Close a poll
+// @Description Stop accepting votes before the closing time (post author or moderators only)
+// @Tags polls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Success 200 {object} entity.Poll
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/poll/close [post]
+func (h *PollHandler) ClosePoll(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	poll, err := h.uc.ClosePoll(c.Request.Context(), token, postID)
+	if err != nil {
+		respondError(c, h.logger, pollErrors, err, "Failed to close poll")
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
+
+// pollErrors - ответы PollHandler на ошибки usecase
+var pollErrors = []errorResponse{
+	{entity.ErrInvalidPollVote, http.StatusBadRequest, ""},
+	{entity.ErrPollClosed, http.StatusConflict, ""},
+	{entity.ErrAlreadyVoted, http.StatusConflict, ""},
+	{repository.ErrPollNotFound, http.StatusNotFound, "Poll not found"},
+	{repository.ErrPostNotFound, http.StatusNotFound, "Post not found"},
+}
+
+// isPollError сообщает, что пост отклонен из-за неверно заполненного опроса
+func isPollError(err error) bool {
+	return errors.Is(err, entity.ErrInvalidPoll) ||
+		errors.Is(err, entity.ErrPollTooLong) ||
+		errors.Is(err, entity.ErrInvalidPollClosing)
+}
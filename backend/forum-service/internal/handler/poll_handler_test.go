@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPollUsecase struct {
+	mock.Mock
+}
+
+func (m *MockPollUsecase) GetPoll(ctx context.Context, token string, postID int64) (*entity.Poll, error) {
+	args := m.Called(ctx, token, postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Poll), args.Error(1)
+}
+
+func (m *MockPollUsecase) Vote(ctx context.Context, token string, postID int64, optionIDs []int64) (*entity.Poll, error) {
+	args := m.Called(ctx, token, postID, optionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Poll), args.Error(1)
+}
+
+func (m *MockPollUsecase) ClosePoll(ctx context.Context, token string, postID int64) (*entity.Poll, error) {
+	args := m.Called(ctx, token, postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Poll), args.Error(1)
+}
+
+func setupPollRouter(uc *MockPollUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewPollHandler(uc, log)
+
+	router := gin.New()
+	router.GET("/posts/:id/poll", h.GetPoll)
+	router.POST("/posts/:id/poll/vote", h.VotePoll)
+	router.POST("/posts/:id/poll/close", h.ClosePoll)
+	return router
+}
+
+func TestPollHandler_GetPoll(t *testing.T) {
+	uc := new(MockPollUsecase)
+	router := setupPollRouter(uc)
+
+	poll := &entity.Poll{ID: 5, PostID: 3, Question: "Go or Rust?", Options: []entity.PollOption{{ID: 11, Text: "Go", Votes: 2}}}
+	uc.On("GetPoll", mock.Anything, "", int64(3)).Return(poll, nil).Once()
+	uc.On("GetPoll", mock.Anything, "valid-token", int64(3)).Return(poll, nil).Once()
+	uc.On("GetPoll", mock.Anything, "", int64(4)).Return(nil, repository.ErrPollNotFound).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/posts/3/poll", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got entity.Poll
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, 2, got.Options[0].Votes)
+
+	req := httptest.NewRequest("GET", "/posts/3/poll", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/posts/4/poll", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestPollHandler_Vote(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(uc *MockPollUsecase)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: `{"option_ids":[11]}`,
+			setup: func(uc *MockPollUsecase) {
+				uc.On("Vote", mock.Anything, "valid-token", int64(3), []int64{11}).
+					Return(&entity.Poll{ID: 5, TotalVoters: 1, MyVotes: []int64{11}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Already voted",
+			body: `{"option_ids":[11]}`,
+			setup: func(uc *MockPollUsecase) {
+				uc.On("Vote", mock.Anything, "valid-token", int64(3), []int64{11}).
+					Return(nil, entity.ErrAlreadyVoted).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Closed",
+			body: `{"option_ids":[11]}`,
+			setup: func(uc *MockPollUsecase) {
+				uc.On("Vote", mock.Anything, "valid-token", int64(3), []int64{11}).
+					Return(nil, entity.ErrPollClosed).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Invalid option",
+			body: `{"option_ids":[99]}`,
+			setup: func(uc *MockPollUsecase) {
+				uc.On("Vote", mock.Anything, "valid-token", int64(3), []int64{99}).
+					Return(nil, entity.ErrInvalidPollVote).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid body",
+			body:           `{}`,
+			setup:          func(uc *MockPollUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockPollUsecase)
+			tt.setup(uc)
+			router := setupPollRouter(uc)
+
+			req := httptest.NewRequest("POST", "/posts/3/poll/vote", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer valid-token")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPollHandler_Close(t *testing.T) {
+	uc := new(MockPollUsecase)
+	router := setupPollRouter(uc)
+
+	uc.On("ClosePoll", mock.Anything, "valid-token", int64(3)).Return(&entity.Poll{ID: 5, Closed: true}, nil).Once()
+	uc.On("ClosePoll", mock.Anything, "valid-token", int64(4)).Return(nil, usecase.ErrForbidden).Once()
+
+	for url, status := range map[string]int{"/posts/3/poll/close": http.StatusOK, "/posts/4/poll/close": http.StatusForbidden} {
+		req := httptest.NewRequest("POST", url, nil)
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/posts/3/poll/close", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestCreatePost_WithPoll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+
+	postUC := new(MockPostUsecase)
+	router := gin.New()
+	router.POST("/posts", NewPostHandler(postUC, log).CreatePost)
+
+	pollInput := &entity.PollInput{Question: "Go or Rust?", Options: []string{"Go", "Rust"}, Multiple: true}
+	postUC.On("CreatePost", mock.Anything, "valid-token", "Title", "Content", []string(nil), []int64(nil), pollInput).
+		Return(&entity.Post{ID: 3, Poll: &entity.Poll{ID: 5}}, nil).Once()
+	postUC.On("CreatePost", mock.Anything, "valid-token", "Title", "Content", []string(nil), []int64(nil), &entity.PollInput{Question: "q"}).
+		Return(nil, entity.ErrInvalidPoll).Once()
+
+	send := func(body string) int {
+		req := httptest.NewRequest("POST", "/posts", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, send(`{"title":"Title","content":"Content","poll":{"question":"Go or Rust?","options":["Go","Rust"],"multiple":true}}`))
+	assert.Equal(t, http.StatusBadRequest, send(`{"title":"Title","content":"Content","poll":{"question":"q"}}`))
+	postUC.AssertExpectations(t)
+}
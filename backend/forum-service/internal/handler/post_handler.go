@@ -8,6 +8,7 @@ import (
 	"time"
 
 	_ "github.com/jaliks17/ffffforum/backend/forum-service/docs"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
 	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
@@ -19,6 +20,8 @@ import (
 type PostHandler struct {
 	uc     usecase.PostUsecaseInterface
 	logger *logger.Logger
+	// Bookmarks добавляет в ленту флаг bookmarked для авторизованного пользователя; nil - флаг не выводится
+	Bookmarks usecase.BookmarkChecker
 }
 
 func NewPostHandler(uc usecase.PostUsecaseInterface, logger *logger.Logger) *PostHandler {
@@ -50,8 +53,11 @@ func (h *PostHandler) CreatePost(ctx *gin.Context) {
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 
 	var request struct {
-		Title   string `json:"title" binding:"required"`
-		Content string `json:"content" binding:"required"`
+		Title         string            `json:"title" binding:"required"`
+		Content       string            `json:"content" binding:"required"`
+		Tags          []string          `json:"tags"`
+		AttachmentIDs []int64           `json:"attachment_ids"`
+		Poll          *entity.PollInput `json:"poll"`
 	}
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -59,8 +65,12 @@ func (h *PostHandler) CreatePost(ctx *gin.Context) {
 		return
 	}
 
-	post, err := h.uc.CreatePost(ctx.Request.Context(), token, request.Title, request.Content)
+	post, err := h.uc.CreatePost(ctx.Request.Context(), token, request.Title, request.Content, request.Tags, request.AttachmentIDs, request.Poll)
 	if err != nil {
+		if isTagError(err) || isAttachmentError(err) || isPollError(err) || isFilterError(err) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to create post", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
 		return
@@ -81,11 +91,34 @@ func (h *PostHandler) CreatePost(ctx *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Posts per page" default(10)
+// @Param sort query string false "Feed order" Enums(new, hot, top, controversial) default(new)
+// @Param period query string false "Only posts created within the period" Enums(day, week, month, all) default(all)
+// @Param tag query string false "Only posts with the tag or one of its synonyms"
+// @Param format query string false "Content representation" Enums(markdown, html, plain) default(markdown)
+// @Param Authorization header string false "Bearer token; adds the bookmarked flag to each post"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /api/v1/posts [get]
 func (h *PostHandler) GetPosts(c *gin.Context) {
-	posts, authorNames, err := h.uc.GetPosts(c.Request.Context())
+	filter, err := entity.ParsePostFilter(c.Query("sort"), c.Query("period"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if tag := c.Query("tag"); tag != "" {
+		if filter.Tag, err = entity.NormalizeTag(tag); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	format, err := entity.ParseContentFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	posts, authorNames, err := h.uc.GetPosts(c.Request.Context(), filter)
 	if err != nil {
 		h.logger.Error("Failed to get posts", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -95,16 +128,32 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 		return
 	}
 
+	bookmarked := h.bookmarkedPosts(c, posts)
+
 	response := make([]gin.H, 0, len(posts))
 	for _, post := range posts {
-		response = append(response, gin.H{
-			"id":          post.ID,
-			"title":       post.Title,
-			"content":     post.Content,
-			"author_id":   post.AuthorID,
-			"author_name": authorNames[int(post.AuthorID)],
-			"created_at":  post.CreatedAt.Format(time.RFC3339),
-		})
+		post.ApplyFormat(format)
+		item := gin.H{
+			"id":            post.ID,
+			"title":         post.Title,
+			"content":       post.Content,
+			"author_id":     post.AuthorID,
+			"author_name":   authorNames[int(post.AuthorID)],
+			"created_at":    post.CreatedAt.Format(time.RFC3339),
+			"upvotes":       post.Upvotes,
+			"downvotes":     post.Downvotes,
+			"comment_count": post.CommentCount,
+			"score":         post.Score,
+			"pinned":        post.Pinned,
+			"locked":        post.Locked,
+			"announcement":  post.Announcement,
+			"tags":          post.Tags,
+			"attachments":   post.Attachments,
+		}
+		if bookmarked != nil {
+			item["bookmarked"] = bookmarked[post.ID]
+		}
+		response = append(response, item)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -112,15 +161,39 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 	})
 }
 
+// bookmarkedPosts возвращает закладки текущего пользователя среди постов ленты.
+// Лента публичная, поэтому без токена или при ошибке проверки возвращается nil и флаг не выводится.
+func (h *PostHandler) bookmarkedPosts(c *gin.Context, posts []*entity.Post) map[int64]bool {
+	authHeader := c.GetHeader("Authorization")
+	if h.Bookmarks == nil || authHeader == "" {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	bookmarked, err := h.Bookmarks.BookmarkedPostIDs(c.Request.Context(), strings.TrimPrefix(authHeader, "Bearer "), ids)
+	if err != nil {
+		if !errors.Is(err, usecase.ErrInvalidToken) {
+			h.logger.Error("Failed to check bookmarks", err)
+		}
+		return nil
+	}
+	return bookmarked
+}
+
 // DeletePost godoc
 // @Summary Delete a post
-// @Description Delete a forum post by ID (only author or admin can delete)
+// @Description Move a forum post to the trash (only author or moderator can delete). It can be restored until the retention period expires
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "Post ID"
+// @Param reason query string false "Deletion reason"
 // @Success 200 {object} entity.SuccessResponse
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 401 {object} entity.ErrorResponse
@@ -148,7 +221,7 @@ func (h *PostHandler) DeletePost(ctx *gin.Context) {
 		zap.String("token", token),
 	)
 
-	if err := h.uc.DeletePost(ctx.Request.Context(), token, postID); err != nil {
+	if err := h.uc.DeletePost(ctx.Request.Context(), token, postID, ctx.Query("reason")); err != nil {
 		h.logger.Error("Failed to delete post", err)
 
 		switch {
@@ -209,6 +282,8 @@ func (h *PostHandler) UpdatePost(ctx *gin.Context) {
 	updatedPost, err := h.uc.UpdatePost(ctx.Request.Context(), token, postID, request.Title, request.Content)
 	if err != nil {
 		switch {
+		case isFilterError(err):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case errors.Is(err, repository.ErrPostNotFound):
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
 		case errors.Is(err, repository.ErrPermissionDenied):
@@ -37,8 +37,8 @@ func (m *MockCommentRepository) GetCommentsByPostID(ctx context.Context, postID
 	return args.Get(0).([]entity.Comment), args.Error(1)
 }
 
-func (m *MockCommentRepository) DeleteComment(ctx context.Context, id int64) error {
-	args := m.Called(ctx, id)
+func (m *MockCommentRepository) DeleteComment(ctx context.Context, id, deletedBy int64, reason string) error {
+	args := m.Called(ctx, id, deletedBy, reason)
 	return args.Error(0)
 }
 
@@ -50,6 +50,22 @@ func (m *MockCommentRepository) GetCommentByID(ctx context.Context, id int64) (*
 	return args.Get(0).(*entity.Comment), args.Error(1)
 }
 
+func (m *MockCommentRepository) GetCommentForModeration(ctx context.Context, id int64) (*entity.Comment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) UpdateComment(ctx context.Context, id, editorID int64, content, contentHTML string, held bool) (*entity.Comment, error) {
+	args := m.Called(ctx, id, editorID, content, contentHTML, held)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Comment), args.Error(1)
+}
+
 type MockPostRepository struct {
 	mock.Mock
 }
@@ -62,26 +78,34 @@ func (m *MockPostRepository) GetPostByID(ctx context.Context, id int64) (*entity
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
-func (m *MockPostRepository) CreatePost(ctx context.Context, post *entity.Post) (int64, error) {
+func (m *MockPostRepository) GetPostForModeration(ctx context.Context, id int64) (*entity.Post, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockPostRepository) CreatePost(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
 	args := m.Called(ctx, post)
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockPostRepository) GetPosts(ctx context.Context) ([]*entity.Post, error) {
-	args := m.Called(ctx)
+func (m *MockPostRepository) GetPosts(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*entity.Post), args.Error(1)
 }
 
-func (m *MockPostRepository) DeletePost(ctx context.Context, id, authorID int64, role string) error {
-	args := m.Called(ctx, id, authorID, role)
+func (m *MockPostRepository) DeletePost(ctx context.Context, id, authorID int64, role, reason string) error {
+	args := m.Called(ctx, id, authorID, role, reason)
 	return args.Error(0)
 }
 
-func (m *MockPostRepository) UpdatePost(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
-	args := m.Called(ctx, id, authorID, role, title, content)
+func (m *MockPostRepository) UpdatePost(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error) {
+	args := m.Called(ctx, id, authorID, role, title, content, contentHTML, held)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -92,16 +116,16 @@ type MockPostUsecase struct {
 	mock.Mock
 }
 
-func (m *MockPostUsecase) CreatePost(ctx context.Context, token, title, content string) (*entity.Post, error) {
-	args := m.Called(ctx, token, title, content)
+func (m *MockPostUsecase) CreatePost(ctx context.Context, token, title, content string, tags []string, attachmentIDs []int64, poll *entity.PollInput) (*entity.Post, error) {
+	args := m.Called(ctx, token, title, content, tags, attachmentIDs, poll)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Post), args.Error(1)
 }
 
-func (m *MockPostUsecase) GetPosts(ctx context.Context) ([]*entity.Post, map[int]string, error) {
-	args := m.Called(ctx)
+func (m *MockPostUsecase) GetPosts(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, map[int]string, error) {
+	args := m.Called(ctx, filter)
 	// Handle nil return for posts and authorNames to avoid panic during type assertion
 	posts, ok := args.Get(0).([]*entity.Post)
 	if !ok && args.Get(0) != nil {
@@ -116,8 +140,8 @@ func (m *MockPostUsecase) GetPosts(ctx context.Context) ([]*entity.Post, map[int
 	return posts, authorNames, args.Error(2)
 }
 
-func (m *MockPostUsecase) DeletePost(ctx context.Context, token string, postID int64) error {
-	args := m.Called(ctx, token, postID)
+func (m *MockPostUsecase) DeletePost(ctx context.Context, token string, postID int64, reason string) error {
+	args := m.Called(ctx, token, postID, reason)
 	return args.Error(0)
 }
 
@@ -155,10 +179,11 @@ func TestCreateComment_Success(t *testing.T) {
 		Return(&pb.GetUserProfileResponse{User: &pb.User{Username: "alice"}}, nil)
 
 	expectedComment := &entity.Comment{
-		Content:    "test comment",
-		AuthorID:   42,
-		PostID:     1,
-		AuthorName: "alice",
+		Content:     "test comment",
+		ContentHTML: "<p>test comment</p>\n",
+		AuthorID:    42,
+		PostID:      1,
+		AuthorName:  "alice",
 	}
 	commentRepo.On("CreateComment", mock.Anything, expectedComment).Return(nil)
 
@@ -210,7 +235,7 @@ func TestGetPosts_Success(t *testing.T) {
 		2: "user2",
 	}
 
-	mockUsecase.On("GetPosts", mock.Anything).Return(posts, authorNames, nil).Once()
+	mockUsecase.On("GetPosts", mock.Anything, mock.Anything).Return(posts, authorNames, nil).Once()
 
 	req, _ := http.NewRequest("GET", "/posts", nil)
 	w := httptest.NewRecorder()
@@ -245,6 +270,112 @@ func TestGetPosts_Success(t *testing.T) {
 	mockUsecase.AssertExpectations(t)
 }
 
+func TestGetPosts_InvalidSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUsecase := new(MockPostUsecase)
+	logger, err := logger.NewLogger("info")
+	assert.NoError(t, err)
+	handler := NewPostHandler(mockUsecase, logger)
+
+	router := gin.Default()
+	router.GET("/posts", handler.GetPosts)
+
+	req, _ := http.NewRequest("GET", "/posts?sort=random", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUsecase.AssertNotCalled(t, "GetPosts", mock.Anything, mock.Anything)
+}
+
+func TestGetPosts_TopWeek(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUsecase := new(MockPostUsecase)
+	logger, err := logger.NewLogger("info")
+	assert.NoError(t, err)
+	handler := NewPostHandler(mockUsecase, logger)
+
+	router := gin.Default()
+	router.GET("/posts", handler.GetPosts)
+
+	filter := entity.PostFilter{Sort: entity.PostSortTop, Period: entity.PostPeriodWeek}
+	mockUsecase.On("GetPosts", mock.Anything, filter).
+		Return([]*entity.Post{{ID: 1, AuthorID: 1, Score: 3, Upvotes: 4, Downvotes: 1}}, map[int]string{1: "user1"}, nil).Once()
+
+	req, _ := http.NewRequest("GET", "/posts?sort=top&period=week", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	post := response["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, float64(3), post["score"])
+	assert.Equal(t, float64(4), post["upvotes"])
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetPosts_Format(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query   string
+		content string
+	}{
+		{query: "", content: "**bold**"},
+		{query: "?format=markdown", content: "**bold**"},
+		{query: "?format=html", content: "<p><strong>bold</strong></p>\n"},
+		{query: "?format=plain", content: "bold"},
+	}
+
+	for _, tt := range tests {
+		t.Run("format"+tt.query, func(t *testing.T) {
+			mockUsecase := new(MockPostUsecase)
+			logger, err := logger.NewLogger("info")
+			assert.NoError(t, err)
+			handler := NewPostHandler(mockUsecase, logger)
+
+			router := gin.Default()
+			router.GET("/posts", handler.GetPosts)
+
+			posts := []*entity.Post{{ID: 1, AuthorID: 1, Content: "**bold**", ContentHTML: "<p><strong>bold</strong></p>\n"}}
+			mockUsecase.On("GetPosts", mock.Anything, mock.Anything).Return(posts, map[int]string{1: "user1"}, nil).Once()
+
+			req, _ := http.NewRequest("GET", "/posts"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			post := response["data"].([]interface{})[0].(map[string]interface{})
+			assert.Equal(t, tt.content, post["content"])
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("invalid format", func(t *testing.T) {
+		mockUsecase := new(MockPostUsecase)
+		logger, err := logger.NewLogger("info")
+		assert.NoError(t, err)
+		handler := NewPostHandler(mockUsecase, logger)
+
+		router := gin.Default()
+		router.GET("/posts", handler.GetPosts)
+
+		req, _ := http.NewRequest("GET", "/posts?format=pdf", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUsecase.AssertNotCalled(t, "GetPosts", mock.Anything, mock.Anything)
+	})
+}
+
 func TestGetPosts_Error(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -256,7 +387,7 @@ func TestGetPosts_Error(t *testing.T) {
 	router := gin.Default()
 	router.GET("/posts", handler.GetPosts)
 
-	mockUsecase.On("GetPosts", mock.Anything).Return(nil, nil, errors.New("database error")).Once()
+	mockUsecase.On("GetPosts", mock.Anything, mock.Anything).Return(nil, nil, errors.New("database error")).Once()
 
 	req, _ := http.NewRequest("GET", "/posts", nil)
 	w := httptest.NewRecorder()
@@ -284,7 +415,7 @@ func TestDeletePost_Success(t *testing.T) {
 	router := gin.Default()
 	router.DELETE("/posts/:id", handler.DeletePost)
 
-	mockUsecase.On("DeletePost", mock.Anything, "valid-token", int64(1)).Return(nil).Once()
+	mockUsecase.On("DeletePost", mock.Anything, "valid-token", int64(1), "").Return(nil).Once()
 
 	req, _ := http.NewRequest("DELETE", "/posts/1", nil)
 	req.Header.Set("Authorization", "Bearer valid-token")
@@ -359,7 +490,7 @@ func TestDeletePost_NotFound(t *testing.T) {
 	router := gin.Default()
 	router.DELETE("/posts/:id", handler.DeletePost)
 
-	mockUsecase.On("DeletePost", mock.Anything, "valid-token", int64(1)).Return(repository.ErrPostNotFound).Once()
+	mockUsecase.On("DeletePost", mock.Anything, "valid-token", int64(1), "").Return(repository.ErrPostNotFound).Once()
 
 	req, _ := http.NewRequest("DELETE", "/posts/1", nil)
 	req.Header.Set("Authorization", "Bearer valid-token")
@@ -387,7 +518,7 @@ func TestDeletePost_PermissionDenied(t *testing.T) {
 	router := gin.Default()
 	router.DELETE("/posts/:id", handler.DeletePost)
 
-	mockUsecase.On("DeletePost", mock.Anything, "valid-token", int64(1)).Return(repository.ErrPermissionDenied).Once()
+	mockUsecase.On("DeletePost", mock.Anything, "valid-token", int64(1), "").Return(repository.ErrPermissionDenied).Once()
 
 	req, _ := http.NewRequest("DELETE", "/posts/1", nil)
 	req.Header.Set("Authorization", "Bearer valid-token")
@@ -638,12 +769,19 @@ func TestCreatePost_Success(t *testing.T) {
 				"id":      float64(0),
 				"message": "Post created successfully",
 				"post": map[string]interface{}{
-					"id":         float64(0),
-					"title":      "Test Post",
-					"content":    "This is a test post",
-					"author_id":  float64(0),
-					"created_at": "0001-01-01T00:00:00Z",
-					"updated_at": "0001-01-01T00:00:00Z",
+					"id":            float64(0),
+					"title":         "Test Post",
+					"content":       "This is a test post",
+					"author_id":     float64(0),
+					"created_at":    "0001-01-01T00:00:00Z",
+					"updated_at":    "0001-01-01T00:00:00Z",
+					"upvotes":       float64(0),
+					"downvotes":     float64(0),
+					"comment_count": float64(0),
+					"score":         float64(0),
+					"pinned":        false,
+					"locked":        false,
+					"announcement":  false,
 				},
 			},
 		},
@@ -700,7 +838,7 @@ func TestCreatePost_Success(t *testing.T) {
 					Title:   "Test Post",
 					Content: "This is a test post",
 				}
-				mockUsecase.On("CreatePost", mock.Anything, strings.TrimPrefix(tt.authHeader, "Bearer "), expectedPost.Title, expectedPost.Content).Return(expectedPost, tt.mockCreateErr).Once()
+				mockUsecase.On("CreatePost", mock.Anything, strings.TrimPrefix(tt.authHeader, "Bearer "), expectedPost.Title, expectedPost.Content, mock.Anything, mock.Anything, mock.Anything).Return(expectedPost, tt.mockCreateErr).Once()
 			}
 
 			// Execute
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	uc     usecase.ReportUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewReportHandler(uc usecase.ReportUsecaseInterface, logger *logger.Logger) *ReportHandler {
+	return &ReportHandler{uc: uc, logger: logger}
+}
+
+// CreateReport godoc
+// @Summary Report content or a user
+// @Description Report a post, comment, chat message or user to moderators. A user can have only one open report per target
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param request body entity.ReportInput true "Report"
+// @Success 201 {object} entity.Report
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/reports [post]
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request entity.ReportInput
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	report, err := h.uc.CreateReport(c.Request.Context(), token, request)
+	if err != nil {
+		respondError(c, h.logger, reportErrors, err, "Failed to create report")
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GetReports godoc
+// @Summary Moderation queue
+// @Description List reports grouped by target (moderators only). Open reports are ordered by report count; status=resolved and status=dismissed show resolution history
+// @Tags moderation
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param status query string false "open, resolved or dismissed" default(open)
+// @Param target_type query string false "post, comment, message or user"
+// @Param limit query int false "Targets per page" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string][]entity.ReportGroup
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/mod/reports [get]
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	filter := entity.ReportFilter{
+		Status:     entity.ReportStatus(c.Query("status")),
+		TargetType: entity.ReportTarget(c.Query("target_type")),
+	}
+	var err error
+	if filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "0")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	if filter.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	groups, err := h.uc.GetReports(c.Request.Context(), token, filter)
+	if err != nil {
+		respondError(c, h.logger, reportErrors, err, "Failed to get reports")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": groups})
+}
+
+// ResolveReports godoc
+// @Summary Resolve reports on a target
+// @Description Apply a moderator decision to all open reports on a target: dismiss, delete the content, warn the author or suspend the author via auth-service. The decision is written to the moderation log
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param type path string true "post, comment, message or user"
+// @Param id path int true "Target ID"
+// @Param request body entity.ReportResolution true "Decision"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/mod/reports/{type}/{id}/resolve [post]
+func (h *ReportHandler) ResolveReports(c *gin.Context) {
+	targetID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request entity.ReportResolution
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	resolved, err := h.uc.ResolveReports(c.Request.Context(), token, entity.ReportTarget(c.Param("type")), targetID, request)
+	if err != nil {
+		respondError(c, h.logger, reportErrors, err, "Failed to resolve reports")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resolved": resolved})
+}
+
+// reportErrors - ответы ReportHandler на ошибки usecase
+var reportErrors = []errorResponse{
+	{entity.ErrInvalidReportTarget, http.StatusBadRequest, ""},
+	{entity.ErrInvalidReportReason, http.StatusBadRequest, ""},
+	{entity.ErrInvalidReportStatus, http.StatusBadRequest, ""},
+	{entity.ErrInvalidReportAction, http.StatusBadRequest, ""},
+	{entity.ErrReportTooLong, http.StatusBadRequest, ""},
+	{entity.ErrReportOwnContent, http.StatusBadRequest, ""},
+	{entity.ErrAlreadyReported, http.StatusConflict, ""},
+	{entity.ErrReportTargetNotFound, http.StatusNotFound, ""},
+	{entity.ErrNoOpenReports, http.StatusNotFound, ""},
+}
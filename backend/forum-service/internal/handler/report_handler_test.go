@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportUsecase struct {
+	mock.Mock
+}
+
+func (m *MockReportUsecase) CreateReport(ctx context.Context, token string, in entity.ReportInput) (*entity.Report, error) {
+	args := m.Called(ctx, token, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Report), args.Error(1)
+}
+
+func (m *MockReportUsecase) GetReports(ctx context.Context, token string, filter entity.ReportFilter) ([]entity.ReportGroup, error) {
+	args := m.Called(ctx, token, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.ReportGroup), args.Error(1)
+}
+
+func (m *MockReportUsecase) ResolveReports(ctx context.Context, token string, targetType entity.ReportTarget, targetID int64, resolution entity.ReportResolution) (int64, error) {
+	args := m.Called(ctx, token, targetType, targetID, resolution)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func setupReportRouter(uc *MockReportUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewReportHandler(uc, log)
+
+	router := gin.New()
+	router.POST("/reports", h.CreateReport)
+	router.GET("/mod/reports", h.GetReports)
+	router.POST("/mod/reports/:type/:id/resolve", h.ResolveReports)
+	return router
+}
+
+func TestReportHandler_CreateReport(t *testing.T) {
+	input := entity.ReportInput{TargetType: entity.ReportTargetPost, TargetID: 3, Reason: entity.ReportReasonSpam}
+
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(uc *MockReportUsecase)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: `{"target_type":"post","target_id":3,"reason":"spam"}`,
+			setup: func(uc *MockReportUsecase) {
+				uc.On("CreateReport", mock.Anything, "valid-token", input).
+					Return(&entity.Report{ID: 1, Status: entity.ReportStatusOpen}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "Already reported",
+			body: `{"target_type":"post","target_id":3,"reason":"spam"}`,
+			setup: func(uc *MockReportUsecase) {
+				uc.On("CreateReport", mock.Anything, "valid-token", input).Return(nil, entity.ErrAlreadyReported).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Target not found",
+			body: `{"target_type":"post","target_id":3,"reason":"spam"}`,
+			setup: func(uc *MockReportUsecase) {
+				uc.On("CreateReport", mock.Anything, "valid-token", input).Return(nil, entity.ErrReportTargetNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Own content",
+			body: `{"target_type":"post","target_id":3,"reason":"spam"}`,
+			setup: func(uc *MockReportUsecase) {
+				uc.On("CreateReport", mock.Anything, "valid-token", input).Return(nil, entity.ErrReportOwnContent).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid body",
+			body:           `{"target_type":"post"}`,
+			setup:          func(uc *MockReportUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockReportUsecase)
+			tt.setup(uc)
+			router := setupReportRouter(uc)
+
+			req := httptest.NewRequest("POST", "/reports", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer valid-token")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReportHandler_GetReports(t *testing.T) {
+	uc := new(MockReportUsecase)
+	router := setupReportRouter(uc)
+
+	groups := []entity.ReportGroup{{TargetType: entity.ReportTargetPost, TargetID: 3, Count: 2}}
+	uc.On("GetReports", mock.Anything, "valid-token", entity.ReportFilter{Status: entity.ReportStatusResolved, TargetType: entity.ReportTargetPost, Limit: 10}).
+		Return(groups, nil).Once()
+	uc.On("GetReports", mock.Anything, "user-token", entity.ReportFilter{}).Return(nil, usecase.ErrForbidden).Once()
+
+	req := httptest.NewRequest("GET", "/mod/reports?status=resolved&target_type=post&limit=10", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got map[string][]entity.ReportGroup
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, 2, got["groups"][0].Count)
+
+	req = httptest.NewRequest("GET", "/mod/reports", nil)
+	req.Header.Set("Authorization", "Bearer user-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req = httptest.NewRequest("GET", "/mod/reports?limit=abc", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestReportHandler_ResolveReports(t *testing.T) {
+	uc := new(MockReportUsecase)
+	router := setupReportRouter(uc)
+
+	suspend := entity.ReportResolution{Action: entity.ReportActionSuspend, SuspendHours: 48}
+	uc.On("ResolveReports", mock.Anything, "valid-token", entity.ReportTargetMessage, int64(6), suspend).Return(int64(3), nil).Once()
+	uc.On("ResolveReports", mock.Anything, "valid-token", entity.ReportTargetPost, int64(3), entity.ReportResolution{Action: entity.ReportActionDismiss}).
+		Return(int64(0), entity.ErrNoOpenReports).Once()
+
+	send := func(url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", url, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer valid-token")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("/mod/reports/message/6/resolve", `{"action":"suspend","suspend_hours":48}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"resolved":3}`, w.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, send("/mod/reports/post/3/resolve", `{"action":"dismiss"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("/mod/reports/post/abc/resolve", `{"action":"dismiss"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("/mod/reports/post/3/resolve", `{}`).Code)
+
+	uc.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorResponse задает ответ на ошибку usecase. Пустой message - клиент получает текст самой ошибки.
+type errorResponse struct {
+	err     error
+	status  int
+	message string
+}
+
+// authErrors - ответы на ошибки токена и прав, общие для всех обработчиков
+var authErrors = []errorResponse{
+	{usecase.ErrInvalidToken, http.StatusUnauthorized, "Invalid token"},
+	{usecase.ErrForbidden, http.StatusForbidden, "You don't have permission"},
+}
+
+// respondError отвечает по первой подходящей записи из table, затем из authErrors.
+// Остальные ошибки логируются, а клиент получает 500 с message.
+func respondError(c *gin.Context, log *logger.Logger, table []errorResponse, err error, message string) {
+	for _, responses := range [][]errorResponse{table, authErrors} {
+		for _, r := range responses {
+			if !errors.Is(err, r.err) {
+				continue
+			}
+			text := r.message
+			if text == "" {
+				text = err.Error()
+			}
+			c.JSON(r.status, gin.H{"error": text})
+			return
+		}
+	}
+
+	log.Error(message, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RevisionHandler struct {
+	uc     usecase.RevisionUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewRevisionHandler(uc usecase.RevisionUsecaseInterface, logger *logger.Logger) *RevisionHandler {
+	return &RevisionHandler{uc: uc, logger: logger}
+}
+
+// GetPostRevisions godoc
+// @Summary Get post edit history
+// @Description Get previous versions of a post, oldest first
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {array} entity.Revision
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/revisions [get]
+func (h *RevisionHandler) GetPostRevisions(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	revisions, err := h.uc.GetPostRevisions(c.Request.Context(), postID)
+	if err != nil {
+		respondError(c, h.logger, revisionErrors, err, "Failed to get revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// DiffPostRevisions godoc
+// @Summary Diff two post revisions
+// @Description Line diff of title and content between two revisions; omit "to" to compare with the current version
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param from query int true "Base revision number"
+// @Param to query int false "Target revision number"
+// @Success 200 {object} entity.RevisionDiff
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffPostRevisions(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	from, to, ok := parseDiffRange(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.uc.DiffPostRevisions(c.Request.Context(), postID, from, to)
+	if err != nil {
+		respondError(c, h.logger, revisionErrors, err, "Failed to diff revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RestorePostRevision godoc
+// @Summary Restore a post revision
+// @Description Replace post title and content with a previous revision (admin only)
+// @Tags posts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} entity.Post
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/revisions/{revision}/restore [post]
+func (h *RevisionHandler) RestorePostRevision(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	post, err := h.uc.RestorePostRevision(c.Request.Context(), token, postID, number)
+	if err != nil {
+		respondError(c, h.logger, revisionErrors, err, "Failed to restore revision")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Revision restored successfully",
+		"post":    post,
+	})
+}
+
+// GetCommentRevisions godoc
+// @Summary Get comment edit history
+// @Description Get previous versions of a comment, oldest first
+// @Tags comments
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Success 200 {array} entity.Revision
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/comments/{id}/revisions [get]
+func (h *RevisionHandler) GetCommentRevisions(c *gin.Context) {
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	revisions, err := h.uc.GetCommentRevisions(c.Request.Context(), commentID)
+	if err != nil {
+		respondError(c, h.logger, revisionErrors, err, "Failed to get revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// DiffCommentRevisions godoc
+// @Summary Diff two comment revisions
+// @Description Line diff of content between two revisions; omit "to" to compare with the current version
+// @Tags comments
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param from query int true "Base revision number"
+// @Param to query int false "Target revision number"
+// @Success 200 {object} entity.RevisionDiff
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/comments/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffCommentRevisions(c *gin.Context) {
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	from, to, ok := parseDiffRange(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.uc.DiffCommentRevisions(c.Request.Context(), commentID, from, to)
+	if err != nil {
+		respondError(c, h.logger, revisionErrors, err, "Failed to diff revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RestoreCommentRevision godoc
+// @Summary Restore a comment revision
+// @Description Replace comment content with a previous revision (admin only)
+// @Tags comments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Comment ID"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} entity.Comment
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/comments/{id}/revisions/{revision}/restore [post]
+func (h *RevisionHandler) RestoreCommentRevision(c *gin.Context) {
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	comment, err := h.uc.RestoreCommentRevision(c.Request.Context(), token, commentID, number)
+	if err != nil {
+		respondError(c, h.logger, revisionErrors, err, "Failed to restore revision")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Revision restored successfully",
+		"comment": comment,
+	})
+}
+
+// parseDiffRange читает номера ревизий from (обязательный) и to (0 - текущая версия)
+func parseDiffRange(c *gin.Context) (int, int, bool) {
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' revision number"})
+		return 0, 0, false
+	}
+
+	to := 0
+	if raw := c.Query("to"); raw != "" {
+		to, err = strconv.Atoi(raw)
+		if err != nil || to < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' revision number"})
+			return 0, 0, false
+		}
+	}
+	return from, to, true
+}
+
+// revisionErrors - ответы RevisionHandler на ошибки usecase
+var revisionErrors = []errorResponse{
+	{repository.ErrPostNotFound, http.StatusNotFound, "Post not found"},
+	{repository.ErrCommentNotFound, http.StatusNotFound, "Comment not found"},
+	{repository.ErrRevisionNotFound, http.StatusNotFound, "Revision not found"},
+	{usecase.ErrForbidden, http.StatusForbidden, "Only administrators can restore revisions"},
+}
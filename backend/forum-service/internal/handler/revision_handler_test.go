@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/diff"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRevisionUsecase struct {
+	mock.Mock
+}
+
+func (m *MockRevisionUsecase) GetPostRevisions(ctx context.Context, postID int64) ([]entity.Revision, error) {
+	args := m.Called(ctx, postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Revision), args.Error(1)
+}
+
+func (m *MockRevisionUsecase) DiffPostRevisions(ctx context.Context, postID int64, from, to int) (*entity.RevisionDiff, error) {
+	args := m.Called(ctx, postID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RevisionDiff), args.Error(1)
+}
+
+func (m *MockRevisionUsecase) RestorePostRevision(ctx context.Context, token string, postID int64, number int) (*entity.Post, error) {
+	args := m.Called(ctx, token, postID, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Post), args.Error(1)
+}
+
+func (m *MockRevisionUsecase) GetCommentRevisions(ctx context.Context, commentID int64) ([]entity.Revision, error) {
+	args := m.Called(ctx, commentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Revision), args.Error(1)
+}
+
+func (m *MockRevisionUsecase) DiffCommentRevisions(ctx context.Context, commentID int64, from, to int) (*entity.RevisionDiff, error) {
+	args := m.Called(ctx, commentID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RevisionDiff), args.Error(1)
+}
+
+func (m *MockRevisionUsecase) RestoreCommentRevision(ctx context.Context, token string, commentID int64, number int) (*entity.Comment, error) {
+	args := m.Called(ctx, token, commentID, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Comment), args.Error(1)
+}
+
+func setupRevisionRouter(uc *MockRevisionUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewRevisionHandler(uc, log)
+
+	router := gin.New()
+	router.GET("/posts/:id/revisions", h.GetPostRevisions)
+	router.GET("/posts/:id/revisions/diff", h.DiffPostRevisions)
+	router.POST("/posts/:id/revisions/:revision/restore", h.RestorePostRevision)
+	router.GET("/comments/:id/revisions", h.GetCommentRevisions)
+	return router
+}
+
+func TestRevisionHandler_GetPostRevisions(t *testing.T) {
+	uc := new(MockRevisionUsecase)
+	router := setupRevisionRouter(uc)
+
+	uc.On("GetPostRevisions", mock.Anything, int64(1)).
+		Return([]entity.Revision{{ID: 1, Number: 1, Title: "Old", Content: "Old content"}}, nil).Once()
+	uc.On("GetPostRevisions", mock.Anything, int64(2)).Return(nil, repository.ErrPostNotFound).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/posts/1/revisions", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string][]entity.Revision
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response["revisions"], 1)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/posts/2/revisions", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestRevisionHandler_DiffPostRevisions(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(uc *MockRevisionUsecase)
+		expectedStatus int
+	}{
+		{
+			name: "Against current version",
+			url:  "/posts/1/revisions/diff?from=1",
+			setup: func(uc *MockRevisionUsecase) {
+				uc.On("DiffPostRevisions", mock.Anything, int64(1), 1, 0).
+					Return(&entity.RevisionDiff{From: 1, Content: []diff.Line{{Op: diff.OpInsert, Text: "new"}}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Between revisions",
+			url:  "/posts/1/revisions/diff?from=1&to=2",
+			setup: func(uc *MockRevisionUsecase) {
+				uc.On("DiffPostRevisions", mock.Anything, int64(1), 1, 2).Return(&entity.RevisionDiff{From: 1, To: 2}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing from",
+			url:            "/posts/1/revisions/diff",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unknown revision",
+			url:  "/posts/1/revisions/diff?from=7",
+			setup: func(uc *MockRevisionUsecase) {
+				uc.On("DiffPostRevisions", mock.Anything, int64(1), 7, 0).Return(nil, repository.ErrRevisionNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockRevisionUsecase)
+			if tt.setup != nil {
+				tt.setup(uc)
+			}
+			router := setupRevisionRouter(uc)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevisionHandler_RestorePostRevision(t *testing.T) {
+	tests := []struct {
+		name           string
+		authHeader     string
+		setup          func(uc *MockRevisionUsecase)
+		expectedStatus int
+	}{
+		{
+			name:       "Admin",
+			authHeader: "Bearer admin-token",
+			setup: func(uc *MockRevisionUsecase) {
+				uc.On("RestorePostRevision", mock.Anything, "admin-token", int64(1), 2).
+					Return(&entity.Post{ID: 1, Title: "Old"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "Not an admin",
+			authHeader: "Bearer user-token",
+			setup: func(uc *MockRevisionUsecase) {
+				uc.On("RestorePostRevision", mock.Anything, "user-token", int64(1), 2).Return(nil, usecase.ErrForbidden).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Missing auth header",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockRevisionUsecase)
+			if tt.setup != nil {
+				tt.setup(uc)
+			}
+			router := setupRevisionRouter(uc)
+
+			req := httptest.NewRequest("POST", "/posts/1/revisions/2/restore", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevisionHandler_GetCommentRevisions(t *testing.T) {
+	uc := new(MockRevisionUsecase)
+	router := setupRevisionRouter(uc)
+
+	uc.On("GetCommentRevisions", mock.Anything, int64(3)).Return(nil, repository.ErrCommentNotFound).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/comments/3/revisions", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	uc.AssertExpectations(t)
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionHandler struct {
+	uc     usecase.SubscriptionUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewSubscriptionHandler(uc usecase.SubscriptionUsecaseInterface, logger *logger.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{uc: uc, logger: logger}
+}
+
+// Subscribe godoc
+// @Summary Follow a post
+// @Description Subscribe to new comments on a post. They are delivered in the email digest
+// @Tags subscriptions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/subscribe [post]
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Subscribe(c.Request.Context(), token, postID); err != nil {
+		respondError(c, h.logger, subscriptionErrors, err, "Failed to subscribe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Subscribed successfully"})
+}
+
+// Unsubscribe godoc
+// @Summary Unfollow a post
+// @Tags subscriptions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/subscribe [delete]
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Unsubscribe(c.Request.Context(), token, postID); err != nil {
+		respondError(c, h.logger, subscriptionErrors, err, "Failed to unsubscribe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed successfully"})
+}
+
+// GetSubscriptions godoc
+// @Summary Get followed posts
+// @Tags subscriptions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} map[string][]entity.Subscription
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/me/subscriptions [get]
+func (h *SubscriptionHandler) GetSubscriptions(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.uc.GetSubscriptions(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, h.logger, subscriptionErrors, err, "Failed to get subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subscriptions})
+}
+
+// GetDigestSettings godoc
+// @Summary Get digest settings
+// @Description Get the email address and frequency of the comment digest. Without saved settings the digest is off
+// @Tags subscriptions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} entity.DigestSettings
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/me/digest [get]
+func (h *SubscriptionHandler) GetDigestSettings(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.uc.GetDigestSettings(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, h.logger, subscriptionErrors, err, "Failed to get digest settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateDigestSettings godoc
+// @Summary Update digest settings
+// @Description Set the email address and frequency (off, daily, weekly) of the comment digest
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param request body object{email=string,frequency=string} true "Digest settings"
+// @Success 200 {object} entity.DigestSettings
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/me/digest [put]
+func (h *SubscriptionHandler) UpdateDigestSettings(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Email     string `json:"email" binding:"required"`
+		Frequency string `json:"frequency" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	settings, err := h.uc.UpdateDigestSettings(c.Request.Context(), token, request.Email, entity.DigestFrequency(request.Frequency))
+	if err != nil {
+		respondError(c, h.logger, subscriptionErrors, err, "Failed to update digest settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UnsubscribeDigest godoc
+// @Summary One-click digest unsubscribe
+// @Description Turn the digest off using the token from the email link. Accepts GET for the link and POST for RFC 8058 one-click unsubscribe
+// @Tags subscriptions
+// @Produce json
+// @Param token query string true "Unsubscribe token"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/digest/unsubscribe [get]
+// @Router /api/v1/digest/unsubscribe [post]
+func (h *SubscriptionHandler) UnsubscribeDigest(c *gin.Context) {
+	if err := h.uc.UnsubscribeByToken(c.Request.Context(), c.Query("token")); err != nil {
+		respondError(c, h.logger, subscriptionErrors, err, "Failed to unsubscribe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "You have been unsubscribed from the digest"})
+}
+
+// subscriptionErrors - ответы SubscriptionHandler на ошибки usecase
+var subscriptionErrors = []errorResponse{
+	{repository.ErrPostNotFound, http.StatusNotFound, "Post not found"},
+	{repository.ErrSubscriptionNotFound, http.StatusNotFound, "Subscription not found"},
+	{repository.ErrDigestSettingsNotFound, http.StatusNotFound, "Invalid unsubscribe link"},
+	{usecase.ErrInvalidEmail, http.StatusBadRequest, ""},
+	{entity.ErrInvalidDigestFrequency, http.StatusBadRequest, ""},
+}
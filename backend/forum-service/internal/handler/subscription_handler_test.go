@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSubscriptionUsecase struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionUsecase) Subscribe(ctx context.Context, token string, postID int64) error {
+	args := m.Called(ctx, token, postID)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionUsecase) Unsubscribe(ctx context.Context, token string, postID int64) error {
+	args := m.Called(ctx, token, postID)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionUsecase) GetSubscriptions(ctx context.Context, token string) ([]entity.Subscription, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionUsecase) GetDigestSettings(ctx context.Context, token string) (*entity.DigestSettings, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.DigestSettings), args.Error(1)
+}
+
+func (m *MockSubscriptionUsecase) UpdateDigestSettings(ctx context.Context, token, email string, frequency entity.DigestFrequency) (*entity.DigestSettings, error) {
+	args := m.Called(ctx, token, email, frequency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.DigestSettings), args.Error(1)
+}
+
+func (m *MockSubscriptionUsecase) UnsubscribeByToken(ctx context.Context, unsubscribeToken string) error {
+	args := m.Called(ctx, unsubscribeToken)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionUsecase) SendDigests(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func setupSubscriptionRouter(uc *MockSubscriptionUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewSubscriptionHandler(uc, log)
+
+	router := gin.New()
+	router.POST("/posts/:id/subscribe", h.Subscribe)
+	router.DELETE("/posts/:id/subscribe", h.Unsubscribe)
+	router.GET("/me/subscriptions", h.GetSubscriptions)
+	router.GET("/me/digest", h.GetDigestSettings)
+	router.PUT("/me/digest", h.UpdateDigestSettings)
+	router.GET("/digest/unsubscribe", h.UnsubscribeDigest)
+	router.POST("/digest/unsubscribe", h.UnsubscribeDigest)
+	return router
+}
+
+func TestSubscriptionHandler_Subscribe(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		setup          func(uc *MockSubscriptionUsecase)
+		expectedStatus int
+	}{
+		{
+			name:   "Subscribe",
+			method: "POST",
+			url:    "/posts/1/subscribe",
+			setup: func(uc *MockSubscriptionUsecase) {
+				uc.On("Subscribe", mock.Anything, "valid-token", int64(1)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Post not found",
+			method: "POST",
+			url:    "/posts/2/subscribe",
+			setup: func(uc *MockSubscriptionUsecase) {
+				uc.On("Subscribe", mock.Anything, "valid-token", int64(2)).Return(repository.ErrPostNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Unsubscribe",
+			method: "DELETE",
+			url:    "/posts/1/subscribe",
+			setup: func(uc *MockSubscriptionUsecase) {
+				uc.On("Unsubscribe", mock.Anything, "valid-token", int64(1)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Not subscribed",
+			method: "DELETE",
+			url:    "/posts/3/subscribe",
+			setup: func(uc *MockSubscriptionUsecase) {
+				uc.On("Unsubscribe", mock.Anything, "valid-token", int64(3)).Return(repository.ErrSubscriptionNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid ID",
+			method:         "POST",
+			url:            "/posts/abc/subscribe",
+			setup:          func(uc *MockSubscriptionUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockSubscriptionUsecase)
+			tt.setup(uc)
+			router := setupSubscriptionRouter(uc)
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscriptionHandler_GetSubscriptions(t *testing.T) {
+	uc := new(MockSubscriptionUsecase)
+	router := setupSubscriptionRouter(uc)
+
+	uc.On("GetSubscriptions", mock.Anything, "valid-token").
+		Return([]entity.Subscription{{ID: 1, PostID: 3, PostTitle: "Title"}}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/me/subscriptions", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"post_title":"Title"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/me/subscriptions", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestSubscriptionHandler_UpdateDigestSettings(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(uc *MockSubscriptionUsecase)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: `{"email":"a@example.com","frequency":"weekly"}`,
+			setup: func(uc *MockSubscriptionUsecase) {
+				uc.On("UpdateDigestSettings", mock.Anything, "valid-token", "a@example.com", entity.DigestWeekly).
+					Return(&entity.DigestSettings{UserID: 1, Email: "a@example.com", Frequency: entity.DigestWeekly, UnsubscribeToken: "secret"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Invalid frequency",
+			body: `{"email":"a@example.com","frequency":"hourly"}`,
+			setup: func(uc *MockSubscriptionUsecase) {
+				uc.On("UpdateDigestSettings", mock.Anything, "valid-token", "a@example.com", entity.DigestFrequency("hourly")).
+					Return(nil, entity.ErrInvalidDigestFrequency).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid email",
+			body: `{"email":"nope","frequency":"daily"}`,
+			setup: func(uc *MockSubscriptionUsecase) {
+				uc.On("UpdateDigestSettings", mock.Anything, "valid-token", "nope", entity.DigestDaily).
+					Return(nil, usecase.ErrInvalidEmail).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing fields",
+			body:           `{}`,
+			setup:          func(uc *MockSubscriptionUsecase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockSubscriptionUsecase)
+			tt.setup(uc)
+			router := setupSubscriptionRouter(uc)
+
+			req := httptest.NewRequest("PUT", "/me/digest", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer valid-token")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			// Токен отписки не раскрывается в API
+			assert.NotContains(t, w.Body.String(), "secret")
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscriptionHandler_UnsubscribeDigest(t *testing.T) {
+	uc := new(MockSubscriptionUsecase)
+	router := setupSubscriptionRouter(uc)
+
+	uc.On("UnsubscribeByToken", mock.Anything, "tok").Return(nil).Twice()
+	uc.On("UnsubscribeByToken", mock.Anything, "bad").Return(repository.ErrDigestSettingsNotFound).Once()
+
+	// Ссылка из письма и one-click отписка почтового клиента работают без авторизации
+	for _, method := range []string{"GET", "POST"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/digest/unsubscribe?token=tok", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/digest/unsubscribe?token=bad", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	uc.AssertExpectations(t)
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	uc     usecase.TagUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewTagHandler(uc usecase.TagUsecaseInterface, logger *logger.Logger) *TagHandler {
+	return &TagHandler{uc: uc, logger: logger}
+}
+
+type postTagsRequest struct {
+	Tags []string `json:"tags" example:"go,databases"`
+}
+
+type synonymRequest struct {
+	Synonym string `json:"synonym" binding:"required" example:"golang"`
+}
+
+type mergeTagsRequest struct {
+	Into string `json:"into" binding:"required" example:"go"`
+}
+
+// SearchTags godoc
+// @Summary Autocomplete tags
+// @Description Find tags whose slug or synonym starts with the prefix, most used first. Without a prefix returns the most used tags
+// @Tags tags
+// @Produce json
+// @Param prefix query string false "Tag prefix"
+// @Param limit query int false "Maximum number of tags" default(10)
+// @Success 200 {object} map[string][]entity.Tag
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/tags [get]
+func (h *TagHandler) SearchTags(c *gin.Context) {
+	var limit int
+	if v := c.Query("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+
+	tags, err := h.uc.SearchTags(c.Request.Context(), c.Query("prefix"), limit)
+	if err != nil {
+		respondError(c, h.logger, tagErrors, err, "Failed to search tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// GetTag godoc
+// @Summary Get a tag
+// @Description Get a tag by slug or synonym with its usage count and synonyms
+// @Tags tags
+// @Produce json
+// @Param slug path string true "Tag slug or synonym"
+// @Success 200 {object} entity.Tag
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/tags/{slug} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	tag, err := h.uc.GetTag(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		respondError(c, h.logger, tagErrors, err, "Failed to get tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// SetPostTags godoc
+// @Summary Replace post tags
+// @Description Replace the tags of a post (author or moderator). Synonyms are replaced with their main tags
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param request body postTagsRequest true "New tags"
+// @Success 200 {object} map[string][]string
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/tags [put]
+func (h *TagHandler) SetPostTags(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request postTagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tags, err := h.uc.SetPostTags(c.Request.Context(), token, postID, request.Tags)
+	if err != nil {
+		respondError(c, h.logger, tagErrors, err, "Failed to update post tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// AddSynonym godoc
+// @Summary Add a tag synonym
+// @Description Make another spelling resolve to the tag (moderators only). Posts tagged with the synonym get the main tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param slug path string true "Tag slug"
+// @Param request body synonymRequest true "Synonym"
+// @Success 201 {object} entity.Tag
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/tags/{slug}/synonyms [post]
+func (h *TagHandler) AddSynonym(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request synonymRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tag, err := h.uc.AddSynonym(c.Request.Context(), token, c.Param("slug"), request.Synonym)
+	if err != nil {
+		respondError(c, h.logger, tagErrors, err, "Failed to add tag synonym")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// DeleteSynonym godoc
+// @Summary Remove a tag synonym
+// @Tags tags
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param slug path string true "Tag slug"
+// @Param synonym path string true "Synonym"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/tags/{slug}/synonyms/{synonym} [delete]
+func (h *TagHandler) DeleteSynonym(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteSynonym(c.Request.Context(), token, c.Param("synonym")); err != nil {
+		respondError(c, h.logger, tagErrors, err, "Failed to remove tag synonym")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Synonym removed successfully"})
+}
+
+// MergeTags godoc
+// @Summary Merge tags
+// @Description Move all posts and synonyms of the tag to another tag and keep the old slug as a synonym (moderators only)
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param slug path string true "Tag to merge"
+// @Param request body mergeTagsRequest true "Target tag"
+// @Success 200 {object} entity.Tag
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/tags/{slug}/merge [post]
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request mergeTagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tag, err := h.uc.MergeTags(c.Request.Context(), token, c.Param("slug"), request.Into)
+	if err != nil {
+		respondError(c, h.logger, tagErrors, err, "Failed to merge tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// tagErrors - ответы TagHandler на ошибки usecase
+var tagErrors = []errorResponse{
+	{entity.ErrInvalidTag, http.StatusBadRequest, ""},
+	{entity.ErrTagTooLong, http.StatusBadRequest, ""},
+	{entity.ErrTooManyTags, http.StatusBadRequest, ""},
+	{repository.ErrTagNotFound, http.StatusNotFound, "Tag not found"},
+	{repository.ErrPostNotFound, http.StatusNotFound, "Post not found"},
+	{repository.ErrTagConflict, http.StatusConflict, ""},
+}
+
+// isTagError сообщает, что запрос отклонен из-за неверных тегов
+func isTagError(err error) bool {
+	return errors.Is(err, entity.ErrInvalidTag) ||
+		errors.Is(err, entity.ErrTagTooLong) ||
+		errors.Is(err, entity.ErrTooManyTags)
+}
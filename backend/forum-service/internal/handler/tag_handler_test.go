@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTagUsecase struct {
+	mock.Mock
+}
+
+func (m *MockTagUsecase) SearchTags(ctx context.Context, prefix string, limit int) ([]entity.Tag, error) {
+	args := m.Called(ctx, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Tag), args.Error(1)
+}
+
+func (m *MockTagUsecase) GetTag(ctx context.Context, slug string) (*entity.Tag, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagUsecase) SetPostTags(ctx context.Context, token string, postID int64, tags []string) ([]string, error) {
+	args := m.Called(ctx, token, postID, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTagUsecase) AddSynonym(ctx context.Context, token, slug, synonym string) (*entity.Tag, error) {
+	args := m.Called(ctx, token, slug, synonym)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagUsecase) DeleteSynonym(ctx context.Context, token, synonym string) error {
+	args := m.Called(ctx, token, synonym)
+	return args.Error(0)
+}
+
+func (m *MockTagUsecase) MergeTags(ctx context.Context, token, source, target string) (*entity.Tag, error) {
+	args := m.Called(ctx, token, source, target)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func setupTagRouter(uc *MockTagUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewTagHandler(uc, log)
+
+	router := gin.New()
+	router.GET("/tags", h.SearchTags)
+	router.GET("/tags/:slug", h.GetTag)
+	router.PUT("/posts/:id/tags", h.SetPostTags)
+	router.POST("/tags/:slug/synonyms", h.AddSynonym)
+	router.DELETE("/tags/:slug/synonyms/:synonym", h.DeleteSynonym)
+	router.POST("/tags/:slug/merge", h.MergeTags)
+	return router
+}
+
+func TestTagHandler_SearchTags(t *testing.T) {
+	uc := new(MockTagUsecase)
+	router := setupTagRouter(uc)
+
+	uc.On("SearchTags", mock.Anything, "go", 5).
+		Return([]entity.Tag{{ID: 1, Slug: "go", PostCount: 12}}, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tags?prefix=go&limit=5", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"tags":[{"id":1,"slug":"go","post_count":12}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tags?limit=many", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	uc.On("GetTag", mock.Anything, "nope").Return(nil, repository.ErrTagNotFound).Once()
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/tags/nope", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestTagHandler_Manage(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		auth           bool
+		setup          func(uc *MockTagUsecase)
+		expectedStatus int
+	}{
+		{
+			name:   "Set post tags",
+			method: "PUT",
+			url:    "/posts/1/tags",
+			body:   `{"tags":["Go","sql"]}`,
+			auth:   true,
+			setup: func(uc *MockTagUsecase) {
+				uc.On("SetPostTags", mock.Anything, "valid-token", int64(1), []string{"Go", "sql"}).
+					Return([]string{"go", "sql"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Set too many tags",
+			method: "PUT",
+			url:    "/posts/1/tags",
+			body:   `{"tags":["a"]}`,
+			auth:   true,
+			setup: func(uc *MockTagUsecase) {
+				uc.On("SetPostTags", mock.Anything, "valid-token", int64(1), []string{"a"}).
+					Return(nil, entity.ErrTooManyTags).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Set tags of another user's post",
+			method: "PUT",
+			url:    "/posts/2/tags",
+			body:   `{"tags":["a"]}`,
+			auth:   true,
+			setup: func(uc *MockTagUsecase) {
+				uc.On("SetPostTags", mock.Anything, "valid-token", int64(2), []string{"a"}).
+					Return(nil, usecase.ErrForbidden).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Set tags without token",
+			method:         "PUT",
+			url:            "/posts/1/tags",
+			body:           `{"tags":["a"]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Add synonym",
+			method: "POST",
+			url:    "/tags/go/synonyms",
+			body:   `{"synonym":"golang"}`,
+			auth:   true,
+			setup: func(uc *MockTagUsecase) {
+				uc.On("AddSynonym", mock.Anything, "valid-token", "go", "golang").
+					Return(&entity.Tag{ID: 1, Slug: "go", Synonyms: []string{"golang"}}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Add taken synonym",
+			method: "POST",
+			url:    "/tags/go/synonyms",
+			body:   `{"synonym":"sql"}`,
+			auth:   true,
+			setup: func(uc *MockTagUsecase) {
+				uc.On("AddSynonym", mock.Anything, "valid-token", "go", "sql").
+					Return(nil, repository.ErrTagConflict).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Add synonym without body",
+			method:         "POST",
+			url:            "/tags/go/synonyms",
+			auth:           true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Delete synonym",
+			method: "DELETE",
+			url:    "/tags/go/synonyms/golang",
+			auth:   true,
+			setup: func(uc *MockTagUsecase) {
+				uc.On("DeleteSynonym", mock.Anything, "valid-token", "golang").Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Merge",
+			method: "POST",
+			url:    "/tags/golang/merge",
+			body:   `{"into":"go"}`,
+			auth:   true,
+			setup: func(uc *MockTagUsecase) {
+				uc.On("MergeTags", mock.Anything, "valid-token", "golang", "go").
+					Return(&entity.Tag{ID: 1, Slug: "go"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Merge by regular user",
+			method: "POST",
+			url:    "/tags/golang/merge",
+			body:   `{"into":"go"}`,
+			auth:   true,
+			setup: func(uc *MockTagUsecase) {
+				uc.On("MergeTags", mock.Anything, "valid-token", "golang", "go").
+					Return(nil, usecase.ErrForbidden).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockTagUsecase)
+			if tt.setup != nil {
+				tt.setup(uc)
+			}
+			router := setupTagRouter(uc)
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.auth {
+				req.Header.Set("Authorization", "Bearer valid-token")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPosts_TagFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+
+	postUC := new(MockPostUsecase)
+	router := gin.New()
+	router.GET("/posts", NewPostHandler(postUC, log).GetPosts)
+
+	posts := []*entity.Post{{ID: 1, AuthorID: 1, Tags: []string{"go", "sql"}}}
+	postUC.On("GetPosts", mock.Anything, mock.MatchedBy(func(filter entity.PostFilter) bool {
+		return filter.Tag == "c-sharp"
+	})).Return(posts, map[int]string{1: "user1"}, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/posts?tag=C%20Sharp", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, []interface{}{"go", "sql"}, response.Data[0]["tags"])
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/posts?tag=%23%23", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	postUC.AssertExpectations(t)
+}
+
+func TestCreatePost_InvalidTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+
+	postUC := new(MockPostUsecase)
+	router := gin.New()
+	router.POST("/posts", NewPostHandler(postUC, log).CreatePost)
+
+	postUC.On("CreatePost", mock.Anything, "valid-token", "Title", "Content", []string{"a", "b", "c", "d", "e", "f"}, []int64(nil), (*entity.PollInput)(nil)).
+		Return(nil, entity.ErrTooManyTags).Once()
+
+	req := httptest.NewRequest("POST", "/posts", strings.NewReader(`{"title":"Title","content":"Content","tags":["a","b","c","d","e","f"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	postUC.AssertExpectations(t)
+}
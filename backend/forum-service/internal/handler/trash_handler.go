@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	uc     usecase.TrashUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewTrashHandler(uc usecase.TrashUsecaseInterface, logger *logger.Logger) *TrashHandler {
+	return &TrashHandler{uc: uc, logger: logger}
+}
+
+// GetTrash godoc
+// @Summary Get trash
+// @Description Get deleted posts and comments that can still be restored. Moderators see everything, other users only their own content
+// @Tags trash
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} map[string][]entity.TrashItem
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/trash [get]
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.uc.GetTrash(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, h.logger, trashErrors, err, "Failed to get trash")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// RestorePost godoc
+// @Summary Restore a post
+// @Description Restore a deleted post from the trash within the retention period
+// @Tags trash
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 410 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/restore [post]
+func (h *TrashHandler) RestorePost(c *gin.Context) {
+	h.restore(c, entity.TrashKindPost, "Invalid post ID", "Post restored successfully")
+}
+
+// RestoreComment godoc
+// @Summary Restore a comment
+// @Description Restore a deleted comment from the trash within the retention period
+// @Tags trash
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Comment ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 410 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/comments/{id}/restore [post]
+func (h *TrashHandler) RestoreComment(c *gin.Context) {
+	h.restore(c, entity.TrashKindComment, "Invalid comment ID", "Comment restored successfully")
+}
+
+func (h *TrashHandler) restore(c *gin.Context, kind entity.TrashKind, invalidID, message string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Restore(c.Request.Context(), token, kind, id); err != nil {
+		respondError(c, h.logger, trashErrors, err, "Failed to restore")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// trashErrors - ответы TrashHandler на ошибки usecase
+var trashErrors = []errorResponse{
+	{repository.ErrTrashItemNotFound, http.StatusNotFound, "Item not found in trash"},
+	{usecase.ErrRetentionExpired, http.StatusGone, "Retention period has expired"},
+	{usecase.ErrForbidden, http.StatusForbidden, "You do not have permission to restore this item"},
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTrashUsecase struct {
+	mock.Mock
+}
+
+func (m *MockTrashUsecase) GetTrash(ctx context.Context, token string) ([]entity.TrashItem, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.TrashItem), args.Error(1)
+}
+
+func (m *MockTrashUsecase) Restore(ctx context.Context, token string, kind entity.TrashKind, id int64) error {
+	args := m.Called(ctx, token, kind, id)
+	return args.Error(0)
+}
+
+func (m *MockTrashUsecase) PurgeExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func setupTrashRouter(uc *MockTrashUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("info")
+	h := NewTrashHandler(uc, log)
+
+	router := gin.New()
+	router.GET("/trash", h.GetTrash)
+	router.POST("/posts/:id/restore", h.RestorePost)
+	router.POST("/comments/:id/restore", h.RestoreComment)
+	return router
+}
+
+func TestTrashHandler_GetTrash(t *testing.T) {
+	uc := new(MockTrashUsecase)
+	router := setupTrashRouter(uc)
+
+	uc.On("GetTrash", mock.Anything, "valid-token").
+		Return([]entity.TrashItem{{Kind: entity.TrashKindPost, ID: 1}}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/trash", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"kind":"post"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/trash", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	uc.AssertExpectations(t)
+}
+
+func TestTrashHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(uc *MockTrashUsecase)
+		expectedStatus int
+	}{
+		{
+			name: "Post restored",
+			url:  "/posts/1/restore",
+			setup: func(uc *MockTrashUsecase) {
+				uc.On("Restore", mock.Anything, "valid-token", entity.TrashKindPost, int64(1)).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Comment not in trash",
+			url:  "/comments/2/restore",
+			setup: func(uc *MockTrashUsecase) {
+				uc.On("Restore", mock.Anything, "valid-token", entity.TrashKindComment, int64(2)).Return(repository.ErrTrashItemNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Retention expired",
+			url:  "/posts/3/restore",
+			setup: func(uc *MockTrashUsecase) {
+				uc.On("Restore", mock.Anything, "valid-token", entity.TrashKindPost, int64(3)).Return(usecase.ErrRetentionExpired).Once()
+			},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name: "Forbidden",
+			url:  "/comments/4/restore",
+			setup: func(uc *MockTrashUsecase) {
+				uc.On("Restore", mock.Anything, "valid-token", entity.TrashKindComment, int64(4)).Return(usecase.ErrForbidden).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Invalid ID",
+			url:            "/posts/abc/restore",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockTrashUsecase)
+			if tt.setup != nil {
+				tt.setup(uc)
+			}
+			router := setupTrashRouter(uc)
+
+			req := httptest.NewRequest("POST", tt.url, nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VoteHandler struct {
+	uc     usecase.VoteUsecaseInterface
+	logger *logger.Logger
+}
+
+func NewVoteHandler(uc usecase.VoteUsecaseInterface, logger *logger.Logger) *VoteHandler {
+	return &VoteHandler{uc: uc, logger: logger}
+}
+
+// VotePost godoc
+// @Summary Vote for a post
+// @Description Upvote (1), downvote (-1) or remove a vote (0) from a post
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param request body entity.VoteRequest true "Vote value"
+// @Success 200 {object} entity.PostScore
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /api/v1/posts/{id}/vote [post]
+func (h *VoteHandler) VotePost(c *gin.Context) {
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	token, ok := bearerToken(c)
+	if !ok {
+		return
+	}
+
+	var request entity.VoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	score, err := h.uc.VotePost(c.Request.Context(), token, postID, *request.Value)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidVote):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrInvalidToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		case errors.Is(err, repository.ErrPostNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		default:
+			h.logger.Error("Failed to vote for post", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to vote for post"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, score)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/usecase"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockVoteUsecase struct {
+	mock.Mock
+}
+
+func (m *MockVoteUsecase) VotePost(ctx context.Context, token string, postID int64, value int) (*entity.PostScore, error) {
+	args := m.Called(ctx, token, postID, value)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PostScore), args.Error(1)
+}
+
+func (m *MockVoteUsecase) RefreshScores(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestVoteHandler_VotePost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		postID         string
+		authHeader     string
+		body           string
+		setup          func(uc *MockVoteUsecase)
+		expectedStatus int
+	}{
+		{
+			name:       "Success",
+			postID:     "1",
+			authHeader: "Bearer token",
+			body:       `{"value": 1}`,
+			setup: func(uc *MockVoteUsecase) {
+				uc.On("VotePost", mock.Anything, "token", int64(1), 1).
+					Return(&entity.PostScore{PostID: 1, Upvotes: 1, Score: 1, UserVote: 1}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "Remove vote",
+			postID:     "1",
+			authHeader: "Bearer token",
+			body:       `{"value": 0}`,
+			setup: func(uc *MockVoteUsecase) {
+				uc.On("VotePost", mock.Anything, "token", int64(1), 0).
+					Return(&entity.PostScore{PostID: 1}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing value",
+			postID:         "1",
+			authHeader:     "Bearer token",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing auth header",
+			postID:         "1",
+			body:           `{"value": 1}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Invalid value",
+			postID:     "1",
+			authHeader: "Bearer token",
+			body:       `{"value": 3}`,
+			setup: func(uc *MockVoteUsecase) {
+				uc.On("VotePost", mock.Anything, "token", int64(1), 3).Return(nil, usecase.ErrInvalidVote).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Post not found",
+			postID:     "2",
+			authHeader: "Bearer token",
+			body:       `{"value": -1}`,
+			setup: func(uc *MockVoteUsecase) {
+				uc.On("VotePost", mock.Anything, "token", int64(2), -1).Return(nil, repository.ErrPostNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := new(MockVoteUsecase)
+			if tt.setup != nil {
+				tt.setup(uc)
+			}
+			log, _ := logger.NewLogger("info")
+			h := NewVoteHandler(uc, log)
+
+			router := gin.New()
+			router.POST("/posts/:id/vote", h.VotePost)
+
+			req, _ := http.NewRequest("POST", "/posts/"+tt.postID+"/vote", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			uc.AssertExpectations(t)
+		})
+	}
+}
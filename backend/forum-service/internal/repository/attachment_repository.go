@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, a *entity.Attachment) error
+	GetAttachmentByID(ctx context.Context, id int64) (*entity.Attachment, error)
+	// CountUnattached считает среди ids вложения владельца, еще не привязанные к посту, комментарию или сообщению чата
+	CountUnattached(ctx context.Context, ownerID int64, ids []int64) (int, error)
+	LinkAttachments(ctx context.Context, ownerID int64, target entity.AttachmentTarget, targetID int64, ids []int64) (int64, error)
+	ClaimForChat(ctx context.Context, ownerID, roomID int64, ids []int64) (bool, error)
+	// IsPublished сообщает, что вложение привязано к опубликованному посту или комментарию:
+	// не удаленному и не отложенному фильтром, как и пост комментария
+	IsPublished(ctx context.Context, id int64) (bool, error)
+	GetAttachmentsByTargets(ctx context.Context, target entity.AttachmentTarget, targetIDs []int64) ([]entity.Attachment, error)
+	DeleteAttachment(ctx context.Context, id int64) error
+}
+
+type attachmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewAttachmentRepository(db *sqlx.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+const attachmentColumns = `id, owner_id, post_id, comment_id, chat_room_id, filename, content_type, size,
+		width, height, storage_key, thumbnail_key, created_at`
+
+func (r *attachmentRepository) CreateAttachment(ctx context.Context, a *entity.Attachment) error {
+	query := `
+		INSERT INTO attachments (owner_id, filename, content_type, size, width, height, storage_key, thumbnail_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	return r.db.QueryRowContext(ctx, query,
+		a.OwnerID,
+		a.Filename,
+		a.ContentType,
+		a.Size,
+		a.Width,
+		a.Height,
+		a.StorageKey,
+		a.ThumbnailKey,
+	).Scan(&a.ID, &a.CreatedAt)
+}
+
+func (r *attachmentRepository) GetAttachmentByID(ctx context.Context, id int64) (*entity.Attachment, error) {
+	var a entity.Attachment
+	err := r.db.GetContext(ctx, &a, `SELECT `+attachmentColumns+` FROM attachments WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *attachmentRepository) CountUnattached(ctx context.Context, ownerID int64, ids []int64) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM attachments
+		WHERE id = ANY($1) AND owner_id = $2 AND post_id IS NULL AND comment_id IS NULL AND chat_room_id IS NULL`,
+		pq.Array(ids), ownerID)
+	return count, err
+}
+
+// LinkAttachments привязывает свободные вложения владельца к посту или комментарию.
+// Чужие и уже использованные вложения пропускаются; возвращается число привязанных.
+func (r *attachmentRepository) LinkAttachments(
+	ctx context.Context,
+	ownerID int64,
+	target entity.AttachmentTarget,
+	targetID int64,
+	ids []int64,
+) (int64, error) {
+	column := "post_id"
+	if target == entity.AttachmentTargetComment {
+		column = "comment_id"
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE attachments SET `+column+` = $1
+		WHERE id = ANY($2) AND owner_id = $3 AND post_id IS NULL AND comment_id IS NULL AND chat_room_id IS NULL`,
+		targetID, pq.Array(ids), ownerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ClaimForChat отмечает вложения владельца как прикрепленные к сообщению в комнате roomID. Вложения
+// занимаются, только если свободны все ids; иначе ничего не меняется и возвращается false.
+func (r *attachmentRepository) ClaimForChat(ctx context.Context, ownerID, roomID int64, ids []int64) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE attachments SET chat_room_id = $3
+		WHERE id = ANY($1) AND owner_id = $2 AND post_id IS NULL AND comment_id IS NULL AND chat_room_id IS NULL`,
+		pq.Array(ids), ownerID, roomID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows != int64(len(ids)) {
+		return false, nil
+	}
+	return true, tx.Commit()
+}
+
+func (r *attachmentRepository) IsPublished(ctx context.Context, id int64) (bool, error) {
+	var published bool
+	err := r.db.GetContext(ctx, &published, `
+		SELECT EXISTS (
+			SELECT 1 FROM attachments a
+			LEFT JOIN comments c ON c.id = a.comment_id AND c.deleted_at IS NULL AND NOT c.held
+			JOIN posts p ON p.id = COALESCE(a.post_id, c.post_id)
+			WHERE a.id = $1 AND p.deleted_at IS NULL AND NOT p.held
+		)`, id)
+	return published, err
+}
+
+// GetAttachmentsByTargets загружает вложения нескольких постов или комментариев в порядке загрузки
+func (r *attachmentRepository) GetAttachmentsByTargets(ctx context.Context, target entity.AttachmentTarget, targetIDs []int64) ([]entity.Attachment, error) {
+	column := "post_id"
+	if target == entity.AttachmentTargetComment {
+		column = "comment_id"
+	}
+
+	attachments := []entity.Attachment{}
+	err := r.db.SelectContext(ctx, &attachments,
+		`SELECT `+attachmentColumns+` FROM attachments WHERE `+column+` = ANY($1) ORDER BY id`,
+		pq.Array(targetIDs))
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepository) DeleteAttachment(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var attachmentRowColumns = []string{"id", "owner_id", "post_id", "comment_id", "chat_room_id", "filename", "content_type", "size",
+	"width", "height", "storage_key", "thumbnail_key", "created_at"}
+
+func TestCreateAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachmentRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO attachments \(owner_id, filename, content_type, size, width, height, storage_key, thumbnail_key\)`).
+		WithArgs(int64(2), "photo.jpg", "image/jpeg", int64(1024), 640, 480, "attachments/k", "attachments/k_thumb").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, now))
+
+	a := &entity.Attachment{OwnerID: 2, Filename: "photo.jpg", ContentType: "image/jpeg", Size: 1024,
+		Width: 640, Height: 480, StorageKey: "attachments/k", ThumbnailKey: "attachments/k_thumb"}
+	assert.NoError(t, repo.CreateAttachment(context.Background(), a))
+	assert.Equal(t, int64(7), a.ID)
+	assert.Equal(t, now, a.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAttachmentByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachmentRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`FROM attachments WHERE id = \$1`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows(attachmentRowColumns).
+			AddRow(7, 2, 1, nil, nil, "photo.jpg", "image/jpeg", 1024, 640, 480, "attachments/k", "", time.Now()))
+
+	a, err := repo.GetAttachmentByID(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "attachments/k", a.StorageKey)
+	if assert.NotNil(t, a.PostID) {
+		assert.Equal(t, int64(1), *a.PostID)
+	}
+	assert.Nil(t, a.CommentID)
+
+	mock.ExpectQuery(`FROM attachments WHERE id = \$1`).
+		WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows(attachmentRowColumns))
+
+	_, err = repo.GetAttachmentByID(context.Background(), 8)
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLinkAttachments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachmentRepository(sqlx.NewDb(db, "sqlmock"))
+	ids := []int64{7, 8}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM attachments\s+WHERE id = ANY\(\$1\) AND owner_id = \$2 AND post_id IS NULL AND comment_id IS NULL AND chat_room_id IS NULL`).
+		WithArgs(pq.Array(ids), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	count, err := repo.CountUnattached(context.Background(), 2, ids)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	mock.ExpectExec(`UPDATE attachments SET comment_id = \$1\s+WHERE id = ANY\(\$2\) AND owner_id = \$3 AND post_id IS NULL AND comment_id IS NULL AND chat_room_id IS NULL`).
+		WithArgs(int64(10), pq.Array(ids), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	linked, err := repo.LinkAttachments(context.Background(), 2, entity.AttachmentTargetComment, 10, ids)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), linked)
+
+	mock.ExpectExec(`UPDATE attachments SET post_id = \$1`).
+		WithArgs(int64(1), pq.Array(ids), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	linked, err = repo.LinkAttachments(context.Background(), 2, entity.AttachmentTargetPost, 1, ids)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), linked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimForChat(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachmentRepository(sqlx.NewDb(db, "sqlmock"))
+	ids := []int64{7, 8}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE attachments SET chat_room_id = \$3\s+WHERE id = ANY\(\$1\) AND owner_id = \$2 AND post_id IS NULL AND comment_id IS NULL AND chat_room_id IS NULL`).
+		WithArgs(pq.Array(ids), int64(2), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	claimed, err := repo.ClaimForChat(context.Background(), 2, 3, ids)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	// Одно из вложений уже занято - остальные не отмечаются
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE attachments SET chat_room_id = \$3`).
+		WithArgs(pq.Array(ids), int64(2), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	claimed, err = repo.ClaimForChat(context.Background(), 2, 3, ids)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsPublished(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachmentRepository(sqlx.NewDb(db, "sqlmock"))
+
+	for id, published := range map[int64]bool{7: true, 8: false} {
+		mock.ExpectQuery(`LEFT JOIN comments c ON c.id = a.comment_id AND c.deleted_at IS NULL AND NOT c.held\s+` +
+			`JOIN posts p ON p.id = COALESCE\(a.post_id, c.post_id\)\s+` +
+			`WHERE a.id = \$1 AND p.deleted_at IS NULL AND NOT p.held`).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(published))
+
+		got, err := repo.IsPublished(context.Background(), id)
+		assert.NoError(t, err)
+		assert.Equal(t, published, got)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAttachmentsByTargets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachmentRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`FROM attachments WHERE post_id = ANY\(\$1\) ORDER BY id`).
+		WithArgs(pq.Array([]int64{1, 2})).
+		WillReturnRows(sqlmock.NewRows(attachmentRowColumns).
+			AddRow(7, 2, 1, nil, nil, "a.png", "image/png", 10, 1, 1, "k1", "k1_thumb", time.Now()).
+			AddRow(8, 2, 2, nil, nil, "b.pdf", "application/pdf", 20, 0, 0, "k2", "", time.Now()))
+
+	got, err := repo.GetAttachmentsByTargets(context.Background(), entity.AttachmentTargetPost, []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	mock.ExpectExec(`DELETE FROM attachments WHERE id = \$1`).
+		WithArgs(int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteAttachment(context.Background(), 9), ErrAttachmentNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
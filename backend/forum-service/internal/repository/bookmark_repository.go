@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var ErrBookmarkNotFound = errors.New("bookmark not found")
+
+type BookmarkRepository interface {
+	SaveBookmark(ctx context.Context, b *entity.Bookmark) error
+	DeleteBookmark(ctx context.Context, userID, postID int64, commentID *int64) error
+	GetBookmarks(ctx context.Context, userID int64, filter entity.BookmarkFilter) ([]entity.Bookmark, int, error)
+	GetFolders(ctx context.Context, userID int64) ([]entity.BookmarkFolder, error)
+	GetBookmarkedPostIDs(ctx context.Context, userID int64, postIDs []int64) ([]int64, error)
+}
+
+type bookmarkRepository struct {
+	db *sqlx.DB
+}
+
+func NewBookmarkRepository(db *sqlx.DB) BookmarkRepository {
+	return &bookmarkRepository{db: db}
+}
+
+// SaveBookmark создает закладку или обновляет папку и теги существующей.
+// Заполняет ID и CreatedAt.
+func (r *bookmarkRepository) SaveBookmark(ctx context.Context, b *entity.Bookmark) error {
+	conflict := `(user_id, post_id) WHERE comment_id IS NULL`
+	if b.CommentID != nil {
+		conflict = `(user_id, comment_id) WHERE comment_id IS NOT NULL`
+	}
+
+	query := `
+		INSERT INTO bookmarks (user_id, post_id, comment_id, folder, tags)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ` + conflict + ` DO UPDATE
+		SET folder = EXCLUDED.folder, tags = EXCLUDED.tags
+		RETURNING id, created_at`
+
+	if b.Tags == nil {
+		b.Tags = pq.StringArray{}
+	}
+	return r.db.QueryRowContext(ctx, query,
+		b.UserID,
+		b.PostID,
+		b.CommentID,
+		b.Folder,
+		b.Tags,
+	).Scan(&b.ID, &b.CreatedAt)
+}
+
+// DeleteBookmark удаляет закладку на пост (commentID = nil) или на комментарий
+func (r *bookmarkRepository) DeleteBookmark(ctx context.Context, userID, postID int64, commentID *int64) error {
+	query := `DELETE FROM bookmarks WHERE user_id = $1 AND post_id = $2 AND comment_id IS NULL`
+	args := []interface{}{userID, postID}
+	if commentID != nil {
+		query = `DELETE FROM bookmarks WHERE user_id = $1 AND comment_id = $2`
+		args = []interface{}{userID, *commentID}
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// bookmarkFilterCondition отбирает закладки пользователя по папке и тегу и скрывает объекты
+// в корзине и отложенные фильтром
+const bookmarkFilterCondition = `
+		WHERE b.user_id = $1
+			AND ($2 = '' OR b.folder = $2)
+			AND ($3 = '' OR $3 = ANY(b.tags))
+			AND p.deleted_at IS NULL AND NOT p.held
+			AND (b.comment_id IS NULL OR (c.deleted_at IS NULL AND NOT c.held))`
+
+// GetBookmarks возвращает страницу закладок, новые первыми, и общее число закладок по фильтру
+func (r *bookmarkRepository) GetBookmarks(ctx context.Context, userID int64, filter entity.BookmarkFilter) ([]entity.Bookmark, int, error) {
+	from := `
+		FROM bookmarks b
+		JOIN posts p ON p.id = b.post_id
+		LEFT JOIN comments c ON c.id = b.comment_id` + bookmarkFilterCondition
+
+	query := `
+		SELECT b.id, b.user_id, b.post_id, b.comment_id, b.folder, b.tags, b.created_at,
+			p.title AS post_title, COALESCE(c.content, '') AS comment_content` + from + `
+		ORDER BY b.id DESC
+		LIMIT $4 OFFSET $5`
+
+	bookmarks := []entity.Bookmark{}
+	if err := r.db.SelectContext(ctx, &bookmarks, query, userID, filter.Folder, filter.Tag, filter.Limit, filter.Offset); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*)`+from, userID, filter.Folder, filter.Tag); err != nil {
+		return nil, 0, err
+	}
+	return bookmarks, total, nil
+}
+
+// GetFolders возвращает папки пользователя; закладки без папки имеют пустое имя
+func (r *bookmarkRepository) GetFolders(ctx context.Context, userID int64) ([]entity.BookmarkFolder, error) {
+	query := `
+		SELECT folder, COUNT(*) AS count
+		FROM bookmarks
+		WHERE user_id = $1
+		GROUP BY folder
+		ORDER BY folder`
+
+	folders := []entity.BookmarkFolder{}
+	if err := r.db.SelectContext(ctx, &folders, query, userID); err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+// GetBookmarkedPostIDs возвращает те из postIDs, которые пользователь сохранил в закладки
+func (r *bookmarkRepository) GetBookmarkedPostIDs(ctx context.Context, userID int64, postIDs []int64) ([]int64, error) {
+	query := `
+		SELECT post_id
+		FROM bookmarks
+		WHERE user_id = $1 AND comment_id IS NULL AND post_id = ANY($2)`
+
+	ids := []int64{}
+	if err := r.db.SelectContext(ctx, &ids, query, userID, pq.Array(postIDs)); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
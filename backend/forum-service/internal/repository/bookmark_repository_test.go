@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveBookmark(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewBookmarkRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	t.Run("Post", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO bookmarks(.|\n)*ON CONFLICT \(user_id, post_id\) WHERE comment_id IS NULL DO UPDATE`).
+			WithArgs(int64(2), int64(3), nil, "later", pq.StringArray{}).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, now))
+
+		b := &entity.Bookmark{UserID: 2, PostID: 3, Folder: "later"}
+		assert.NoError(t, repo.SaveBookmark(context.Background(), b))
+		assert.Equal(t, int64(1), b.ID)
+		assert.Equal(t, now, b.CreatedAt)
+	})
+
+	t.Run("Comment", func(t *testing.T) {
+		commentID := int64(10)
+		mock.ExpectQuery(`INSERT INTO bookmarks(.|\n)*ON CONFLICT \(user_id, comment_id\) WHERE comment_id IS NOT NULL DO UPDATE`).
+			WithArgs(int64(2), int64(3), &commentID, "", pq.StringArray{"go"}).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, now))
+
+		b := &entity.Bookmark{UserID: 2, PostID: 3, CommentID: &commentID, Tags: pq.StringArray{"go"}}
+		assert.NoError(t, repo.SaveBookmark(context.Background(), b))
+		assert.Equal(t, int64(2), b.ID)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBookmark(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewBookmarkRepository(sqlx.NewDb(db, "sqlmock"))
+	commentID := int64(10)
+
+	mock.ExpectExec(`DELETE FROM bookmarks WHERE user_id = \$1 AND post_id = \$2 AND comment_id IS NULL`).
+		WithArgs(int64(2), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteBookmark(context.Background(), 2, 3, nil))
+
+	mock.ExpectExec(`DELETE FROM bookmarks WHERE user_id = \$1 AND comment_id = \$2`).
+		WithArgs(int64(2), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteBookmark(context.Background(), 2, 3, &commentID), ErrBookmarkNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBookmarks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewBookmarkRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+	filter := entity.BookmarkFilter{Folder: "later", Tag: "go", Limit: 20, Offset: 0}
+
+	mock.ExpectQuery(`SELECT b.id(.|\n)*LEFT JOIN comments c(.|\n)*\$3 = ANY\(b.tags\)(.|\n)*LIMIT \$4 OFFSET \$5`).
+		WithArgs(int64(2), "later", "go", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "post_id", "comment_id", "folder", "tags", "created_at", "post_title", "comment_content"}).
+			AddRow(1, 2, 3, nil, "later", "{go,tips}", now, "Title", ""))
+	mock.ExpectQuery(`SELECT COUNT\(\*\)(.|\n)*FROM bookmarks b`).
+		WithArgs(int64(2), "later", "go").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	bookmarks, total, err := repo.GetBookmarks(context.Background(), 2, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, total)
+	if assert.Len(t, bookmarks, 1) {
+		assert.Equal(t, pq.StringArray{"go", "tips"}, bookmarks[0].Tags)
+		assert.Equal(t, "Title", bookmarks[0].PostTitle)
+		assert.Nil(t, bookmarks[0].CommentID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBookmarkedPostIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewBookmarkRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT post_id(.|\n)*post_id = ANY\(\$2\)`).
+		WithArgs(int64(2), pq.Array([]int64{1, 2, 3})).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id"}).AddRow(1).AddRow(3))
+
+	ids, err := repo.GetBookmarkedPostIDs(context.Background(), 2, []int64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBookmarkFolders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewBookmarkRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT folder, COUNT\(\*\) AS count(.|\n)*GROUP BY folder`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"folder", "count"}).AddRow("", 2).AddRow("later", 1))
+
+	folders, err := repo.GetFolders(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.BookmarkFolder{{Name: "", Count: 2}, {Name: "later", Count: 1}}, folders)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
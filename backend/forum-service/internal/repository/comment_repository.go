@@ -14,7 +14,9 @@ type CommentRepository interface {
 	CreateComment(ctx context.Context, comment *entity.Comment) error
 	GetCommentsByPostID(ctx context.Context, postID int64) ([]entity.Comment, error)
 	GetCommentByID(ctx context.Context, id int64) (*entity.Comment, error)
-	DeleteComment(ctx context.Context, id int64) error
+	GetCommentForModeration(ctx context.Context, id int64) (*entity.Comment, error)
+	DeleteComment(ctx context.Context, id, deletedBy int64, reason string) error
+	UpdateComment(ctx context.Context, id, editorID int64, content, contentHTML string, held bool) (*entity.Comment, error)
 }
 
 type CommentRepo struct {
@@ -26,13 +28,16 @@ func NewCommentRepository(db *sqlx.DB) CommentRepository {
 }
 
 func (r *CommentRepo) CreateComment(ctx context.Context, comment *entity.Comment) error {
-	query := `INSERT INTO comments (content, author_id, post_id, author_name) 
-        VALUES ($1, $2, $3, $4) RETURNING id`
+	query := `INSERT INTO comments (content, author_id, post_id, author_name, content_html, parent_id, held) 
+        VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
 	return r.db.QueryRowContext(ctx, query,
 		comment.Content,
 		comment.AuthorID,
 		comment.PostID,
 		comment.AuthorName,
+		comment.ContentHTML,
+		comment.ParentID,
+		comment.Held,
 	).Scan(&comment.ID)
 }
 
@@ -41,11 +46,16 @@ func (r *CommentRepo) GetCommentsByPostID(ctx context.Context, postID int64) ([]
         SELECT 
             id,
             content,
+            content_html,
             author_id,
             post_id,
-            author_name
+            parent_id,
+            author_name,
+            created_at,
+            edited_at,
+            deleted_at
         FROM comments 
-        WHERE post_id = $1
+        WHERE post_id = $1 AND NOT held
         ORDER BY id DESC`
 
 	var comments []entity.Comment
@@ -61,8 +71,26 @@ func (r *CommentRepo) GetCommentsByPostID(ctx context.Context, postID int64) ([]
 
 func (r *CommentRepo) GetCommentByID(ctx context.Context, id int64) (*entity.Comment, error) {
 	query := `
-		SELECT id, content, author_id, post_id, author_name
+		SELECT id, content, content_html, author_id, post_id, parent_id, author_name, created_at, edited_at, deleted_at
 		FROM comments 
+		WHERE id = $1 AND NOT held`
+
+	var comment entity.Comment
+	err := r.db.GetContext(ctx, &comment, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetCommentForModeration возвращает комментарий, в том числе отложенный фильтром, для решений модератора
+func (r *CommentRepo) GetCommentForModeration(ctx context.Context, id int64) (*entity.Comment, error) {
+	query := `
+		SELECT id, content, content_html, author_id, post_id, parent_id, author_name, created_at, edited_at, deleted_at, held
+		FROM comments
 		WHERE id = $1`
 
 	var comment entity.Comment
@@ -76,9 +104,13 @@ func (r *CommentRepo) GetCommentByID(ctx context.Context, id int64) (*entity.Com
 	return &comment, nil
 }
 
-func (r *CommentRepo) DeleteComment(ctx context.Context, id int64) error {
-	query := `DELETE FROM comments WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+// DeleteComment помещает комментарий в корзину; в ветке на его месте остается заглушка
+func (r *CommentRepo) DeleteComment(ctx context.Context, id, deletedBy int64, reason string) error {
+	query := `
+		UPDATE comments
+		SET deleted_at = NOW(), deleted_by = $2, delete_reason = $3
+		WHERE id = $1 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id, deletedBy, reason)
 	if err != nil {
 		return err
 	}
@@ -92,4 +124,35 @@ func (r *CommentRepo) DeleteComment(ctx context.Context, id int64) error {
 	return nil
 }
 
+// UpdateComment меняет текст комментария, сохраняя предыдущую версию в comment_revisions.
+// Проверка прав выполняется на уровне usecase. held скрывает правку, отложенную фильтром,
+// в том же запросе; снять held правкой нельзя.
+func (r *CommentRepo) UpdateComment(ctx context.Context, id, editorID int64, content, contentHTML string, held bool) (*entity.Comment, error) {
+	query := `
+		WITH prev AS (
+			SELECT id, content
+			FROM comments
+			WHERE id = $2 AND deleted_at IS NULL
+			FOR UPDATE
+		), rev AS (
+			INSERT INTO comment_revisions (comment_id, editor_id, content)
+			SELECT id, $3, content FROM prev
+		)
+		UPDATE comments c
+		SET content = $1, content_html = $4, held = c.held OR $5, updated_at = NOW(), edited_at = NOW()
+		FROM prev
+		WHERE c.id = prev.id
+		RETURNING c.id, c.content, c.content_html, c.author_id, c.post_id, c.parent_id, c.author_name, c.created_at, c.edited_at, c.held`
+
+	var comment entity.Comment
+	err := r.db.GetContext(ctx, &comment, query, content, id, editorID, contentHTML, held)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
 var ErrCommentNotFound = errors.New("comment not found")
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
 
@@ -40,7 +41,7 @@ func TestCreateComment(t *testing.T) {
 			},
 			mock: func() {
 				mock.ExpectQuery(`INSERT INTO comments`).
-					WithArgs("Test comment", int64(1), int64(1), "testuser").
+					WithArgs("Test comment", int64(1), int64(1), "testuser", "", nil, false).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 			},
 			wantID: 1,
@@ -55,7 +56,7 @@ func TestCreateComment(t *testing.T) {
 			},
 			mock: func() {
 				mock.ExpectQuery(`INSERT INTO comments`).
-					WithArgs("", int64(1), int64(1), "testuser").
+					WithArgs("", int64(1), int64(1), "testuser", "", nil, false).
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: true,
@@ -258,7 +259,7 @@ func TestDeleteComment(t *testing.T) {
 			name: "Success",
 			commentID: 1,
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM comments`).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE comments SET deleted_at = NOW\(\)`).WithArgs(int64(1), int64(7), "spam").WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			wantErr: nil,
 		},
@@ -266,7 +267,7 @@ func TestDeleteComment(t *testing.T) {
 			name: "Not Found",
 			commentID: 2,
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM comments`).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`UPDATE comments SET deleted_at = NOW\(\)`).WithArgs(int64(2), int64(7), "spam").WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 			wantErr: ErrCommentNotFound,
 		},
@@ -274,7 +275,7 @@ func TestDeleteComment(t *testing.T) {
 			name: "Database Error",
 			commentID: 3,
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM comments`).WithArgs(int64(3)).WillReturnError(sql.ErrConnDone)
+				mock.ExpectExec(`UPDATE comments SET deleted_at = NOW\(\)`).WithArgs(int64(3), int64(7), "spam").WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: sql.ErrConnDone,
 		},
@@ -284,7 +285,7 @@ func TestDeleteComment(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := repo.DeleteComment(context.Background(), tt.commentID)
+			err := repo.DeleteComment(context.Background(), tt.commentID, 7, "spam")
 
 			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("DeleteComment() error = %v, wantErr %v", err, tt.wantErr)
@@ -292,4 +293,42 @@ func TestDeleteComment(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestUpdateComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewCommentRepository(sqlx.NewDb(db, "sqlmock"))
+
+	t.Run("Success", func(t *testing.T) {
+		createdAt := time.Now().Add(-time.Minute)
+		editedAt := time.Now()
+		mock.ExpectQuery(`INSERT INTO comment_revisions(.|\n)*edited_at = NOW\(\)`).
+			WithArgs("Edited", int64(1), int64(2), "<p>Edited</p>\n", false).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "content", "author_id", "post_id", "author_name", "created_at", "edited_at"}).
+				AddRow(1, "Edited", 2, 3, "testuser", createdAt, editedAt))
+
+		got, err := repo.UpdateComment(context.Background(), 1, 2, "Edited", "<p>Edited</p>\n", false)
+		assert.NoError(t, err)
+		assert.Equal(t, "Edited", got.Content)
+		assert.Equal(t, int64(3), got.PostID)
+		assert.Equal(t, createdAt, got.CreatedAt)
+		if assert.NotNil(t, got.EditedAt) {
+			assert.Equal(t, editedAt, *got.EditedAt)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO comment_revisions`).
+			WithArgs("Edited", int64(5), int64(2), "<p>Edited</p>\n", false).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.UpdateComment(context.Background(), 5, 2, "Edited", "<p>Edited</p>\n", false)
+		assert.True(t, errors.Is(err, ErrCommentNotFound))
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ContentFilterRepository interface {
+	GetFilterRules(ctx context.Context) ([]entity.FilterRule, error)
+	CreateFilterRule(ctx context.Context, rule *entity.FilterRule) error
+	DeleteFilterRule(ctx context.Context, id int64) error
+	GetDuplicateStats(ctx context.Context, userID int64, fingerprint string, window time.Duration) (*entity.DuplicateStats, error)
+	RecordContent(ctx context.Context, userID int64, kind entity.ContentKind, fingerprint string) error
+	PurgeFingerprints(ctx context.Context, before time.Time) (int64, error)
+	HoldContent(ctx context.Context, targetType entity.ReportTarget, targetID, authorID int64, reason string) error
+	SaveSpamSample(ctx context.Context, content string, isSpam bool) error
+	GetSpamSamples(ctx context.Context, limit int) ([]entity.SpamSample, error)
+}
+
+type contentFilterRepository struct {
+	db *sqlx.DB
+}
+
+func NewContentFilterRepository(db *sqlx.DB) ContentFilterRepository {
+	return &contentFilterRepository{db: db}
+}
+
+func (r *contentFilterRepository) GetFilterRules(ctx context.Context) ([]entity.FilterRule, error) {
+	rules := []entity.FilterRule{}
+	err := r.db.SelectContext(ctx, &rules, `
+		SELECT id, pattern, is_regex, action, created_by, created_at
+		FROM content_filter_rules
+		ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateFilterRule сохраняет правило и заполняет ID и CreatedAt
+func (r *contentFilterRepository) CreateFilterRule(ctx context.Context, rule *entity.FilterRule) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO content_filter_rules (pattern, is_regex, action, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		rule.Pattern, rule.IsRegex, rule.Action, rule.CreatedBy,
+	).Scan(&rule.ID, &rule.CreatedAt)
+}
+
+func (r *contentFilterRepository) DeleteFilterRule(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM content_filter_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return entity.ErrFilterRuleNotFound
+	}
+	return nil
+}
+
+// GetDuplicateStats возвращает, сколько раз за window текст с тем же отпечатком
+// публиковали автор и другие пользователи
+func (r *contentFilterRepository) GetDuplicateStats(ctx context.Context, userID int64, fingerprint string, window time.Duration) (*entity.DuplicateStats, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE user_id = $1) AS own,
+			COUNT(DISTINCT user_id) FILTER (WHERE user_id <> $1) AS others
+		FROM content_fingerprints
+		WHERE fingerprint = $2 AND created_at > $3`
+
+	var stats entity.DuplicateStats
+	err := r.db.GetContext(ctx, &stats, query, userID, fingerprint, time.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// RecordContent запоминает отпечаток опубликованного текста
+func (r *contentFilterRepository) RecordContent(ctx context.Context, userID int64, kind entity.ContentKind, fingerprint string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO content_fingerprints (user_id, kind, fingerprint) VALUES ($1, $2, $3)`,
+		userID, kind, fingerprint)
+	return err
+}
+
+// PurgeFingerprints удаляет отпечатки старше before
+func (r *contentFilterRepository) PurgeFingerprints(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM content_fingerprints WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// heldTables - таблицы контента, который фильтр может отложить
+var heldTables = map[entity.ReportTarget]string{
+	entity.ReportTargetPost:    "posts",
+	entity.ReportTargetComment: "comments",
+}
+
+// HoldContent ставит отложенный пост или комментарий в очередь модерации системной
+// жалобой (reporter_id = 0) с причиной фильтра. Сам контент к этому моменту уже
+// сохранен скрытым: held выставляется в том же запросе, что создает или правит запись.
+func (r *contentFilterRepository) HoldContent(ctx context.Context, targetType entity.ReportTarget, targetID, authorID int64, reason string) error {
+	if _, ok := heldTables[targetType]; !ok {
+		return entity.ErrInvalidReportTarget
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO reports (reporter_id, target_type, target_id, target_author_id, reason, details)
+		VALUES (0, $1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING`,
+		targetType, targetID, authorID, entity.ReportReasonSpam, "content filter: "+reason)
+	return err
+}
+
+func (r *contentFilterRepository) SaveSpamSample(ctx context.Context, content string, isSpam bool) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO spam_samples (content, is_spam) VALUES ($1, $2)`, content, isSpam)
+	return err
+}
+
+// GetSpamSamples возвращает последние limit примеров для обучения классификатора
+func (r *contentFilterRepository) GetSpamSamples(ctx context.Context, limit int) ([]entity.SpamSample, error) {
+	samples := []entity.SpamSample{}
+	err := r.db.SelectContext(ctx, &samples, `
+		SELECT content, is_spam
+		FROM spam_samples
+		ORDER BY id DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterRules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewContentFilterRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, pattern, is_regex, action, created_by, created_at\s+FROM content_filter_rules\s+ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pattern", "is_regex", "action", "created_by", "created_at"}).
+			AddRow(1, "casino", false, "hold", 2, now))
+	rules, err := repo.GetFilterRules(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.FilterRule{{ID: 1, Pattern: "casino", Action: entity.FilterActionHold, CreatedBy: 2, CreatedAt: now}}, rules)
+
+	mock.ExpectQuery(`INSERT INTO content_filter_rules \(pattern, is_regex, action, created_by\)`).
+		WithArgs(`buy\s+now`, true, entity.FilterActionReject, int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, now))
+	rule := &entity.FilterRule{Pattern: `buy\s+now`, IsRegex: true, Action: entity.FilterActionReject, CreatedBy: 2}
+	assert.NoError(t, repo.CreateFilterRule(context.Background(), rule))
+	assert.Equal(t, int64(2), rule.ID)
+
+	mock.ExpectExec(`DELETE FROM content_filter_rules WHERE id = \$1`).
+		WithArgs(int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteFilterRule(context.Background(), 9), entity.ErrFilterRuleNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDuplicateStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewContentFilterRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT\s+COUNT\(\*\) FILTER \(WHERE user_id = \$1\) AS own,(.|\n)*FROM content_fingerprints\s+WHERE fingerprint = \$2 AND created_at > \$3`).
+		WithArgs(int64(5), "abc", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"own", "others"}).AddRow(0, 2))
+
+	stats, err := repo.GetDuplicateStats(context.Background(), 5, "abc", 10*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.DuplicateStats{Own: 0, Others: 2}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordContent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewContentFilterRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`INSERT INTO content_fingerprints \(user_id, kind, fingerprint\) VALUES \(\$1, \$2, \$3\)`).
+		WithArgs(int64(5), entity.ContentKindComment, "abc").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.RecordContent(context.Background(), 5, entity.ContentKindComment, "abc"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHoldContent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewContentFilterRepository(sqlx.NewDb(db, "sqlmock"))
+
+	t.Run("Comment", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO reports \(reporter_id, target_type, target_id, target_author_id, reason, details\)\s+VALUES \(0, \$1, \$2, \$3, \$4, \$5\)\s+ON CONFLICT DO NOTHING`).
+			WithArgs(entity.ReportTargetComment, int64(4), int64(8), entity.ReportReasonSpam, "content filter: looks like spam").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		assert.NoError(t, repo.HoldContent(context.Background(), entity.ReportTargetComment, 4, 8, entity.FilterReasonClassifier))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unsupported target", func(t *testing.T) {
+		err := repo.HoldContent(context.Background(), entity.ReportTargetMessage, 6, 8, "banned word")
+		assert.ErrorIs(t, err, entity.ErrInvalidReportTarget)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSpamSamples(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewContentFilterRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`INSERT INTO spam_samples \(content, is_spam\) VALUES \(\$1, \$2\)`).
+		WithArgs("buy now", true).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveSpamSample(context.Background(), "buy now", true))
+
+	mock.ExpectQuery(`SELECT content, is_spam\s+FROM spam_samples\s+ORDER BY id DESC\s+LIMIT \$1`).
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows([]string{"content", "is_spam"}).AddRow("buy now", true).AddRow("hello", false))
+	samples, err := repo.GetSpamSamples(context.Background(), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.SpamSample{{Content: "buy now", IsSpam: true}, {Content: "hello", IsSpam: false}}, samples)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
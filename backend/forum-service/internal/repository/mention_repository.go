@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type MentionRepository interface {
+	CreateMentions(ctx context.Context, mentions []entity.Mention) ([]entity.Mention, error)
+}
+
+type mentionRepository struct {
+	db *sqlx.DB
+}
+
+func NewMentionRepository(db *sqlx.DB) MentionRepository {
+	return &mentionRepository{db: db}
+}
+
+// CreateMentions сохраняет упоминания и возвращает только новые записи:
+// повторное упоминание того же пользователя в том же объекте пропускается.
+func (r *mentionRepository) CreateMentions(ctx context.Context, mentions []entity.Mention) ([]entity.Mention, error) {
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		INSERT INTO mentions (source_type, source_id, post_id, author_id, mentioned_user_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (source_type, source_id, mentioned_user_id) DO NOTHING
+		RETURNING id, created_at`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	created := make([]entity.Mention, 0, len(mentions))
+	for _, m := range mentions {
+		err := tx.QueryRowContext(ctx, query,
+			m.SourceType,
+			m.SourceID,
+			m.PostID,
+			m.AuthorID,
+			m.MentionedUserID,
+		).Scan(&m.ID, &m.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, m)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
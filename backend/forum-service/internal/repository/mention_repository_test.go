@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMentions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewMentionRepository(sqlx.NewDb(db, "sqlmock"))
+	mentions := []entity.Mention{
+		{SourceType: entity.MentionSourceComment, SourceID: 10, PostID: 1, AuthorID: 1, MentionedUserID: 2},
+		{SourceType: entity.MentionSourceComment, SourceID: 10, PostID: 1, AuthorID: 1, MentionedUserID: 3},
+	}
+
+	t.Run("Duplicates are skipped", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO mentions`).
+			WithArgs(entity.MentionSourceComment, int64(10), int64(1), int64(1), int64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, now))
+		mock.ExpectQuery(`INSERT INTO mentions`).
+			WithArgs(entity.MentionSourceComment, int64(10), int64(1), int64(1), int64(3)).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectCommit()
+
+		got, err := repo.CreateMentions(context.Background(), mentions)
+		assert.NoError(t, err)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, int64(5), got[0].ID)
+			assert.Equal(t, int64(2), got[0].MentionedUserID)
+			assert.Equal(t, now, got[0].CreatedAt)
+		}
+	})
+
+	t.Run("Database Error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO mentions`).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err := repo.CreateMentions(context.Background(), mentions)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		got, err := repo.CreateMentions(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ModerationRepository interface {
+	SetPostFlags(ctx context.Context, postID, actorID int64, update entity.PostFlagsUpdate) (*entity.PostFlags, error)
+	GetModerationLog(ctx context.Context, filter entity.ModerationLogFilter) ([]entity.ModerationLogEntry, error)
+}
+
+type moderationRepository struct {
+	db *sqlx.DB
+}
+
+func NewModerationRepository(db *sqlx.DB) ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+// SetPostFlags меняет флаги поста и в той же транзакции записывает изменения в журнал модерации
+func (r *moderationRepository) SetPostFlags(ctx context.Context, postID, actorID int64, update entity.PostFlagsUpdate) (*entity.PostFlags, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var flags entity.PostFlags
+	err = tx.GetContext(ctx, &flags, `
+		SELECT pinned, locked, announcement
+		FROM posts
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE`, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	actions := update.Apply(&flags)
+	if len(actions) == 0 {
+		return &flags, nil
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE posts SET pinned = $2, locked = $3, announcement = $4 WHERE id = $1`,
+		postID, flags.Pinned, flags.Locked, flags.Announcement); err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO moderation_log (actor_id, action, target_type, target_id) VALUES ($1, $2, $3, $4)`,
+			actorID, action, entity.ModerationTargetPost, postID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &flags, nil
+}
+
+// GetModerationLog возвращает записи журнала, новые первыми
+func (r *moderationRepository) GetModerationLog(ctx context.Context, filter entity.ModerationLogFilter) ([]entity.ModerationLogEntry, error) {
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, details, created_at
+		FROM moderation_log
+		WHERE ($1 = '' OR target_type = $1)
+			AND ($2 = 0 OR target_id = $2)
+			AND ($3 = 0 OR actor_id = $3)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4 OFFSET $5`
+
+	entries := []entity.ModerationLogEntry{}
+	err := r.db.SelectContext(ctx, &entries, query,
+		filter.TargetType, filter.TargetID, filter.ActorID, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
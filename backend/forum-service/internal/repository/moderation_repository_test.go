@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPostFlags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewModerationRepository(sqlx.NewDb(db, "sqlmock"))
+	on := true
+
+	t.Run("Changes are logged", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT pinned, locked, announcement\s+FROM posts\s+WHERE id = \$1 AND deleted_at IS NULL\s+FOR UPDATE`).
+			WithArgs(int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"pinned", "locked", "announcement"}).AddRow(false, true, false))
+		mock.ExpectExec(`UPDATE posts SET pinned = \$2, locked = \$3, announcement = \$4 WHERE id = \$1`).
+			WithArgs(int64(3), true, true, false).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO moderation_log \(actor_id, action, target_type, target_id\)`).
+			WithArgs(int64(7), entity.ModerationActionPin, entity.ModerationTargetPost, int64(3)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		flags, err := repo.SetPostFlags(context.Background(), 3, 7, entity.PostFlagsUpdate{Pinned: &on, Locked: &on})
+		assert.NoError(t, err)
+		assert.Equal(t, &entity.PostFlags{Pinned: true, Locked: true}, flags)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unchanged flags are not written", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT pinned, locked, announcement`).
+			WithArgs(int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"pinned", "locked", "announcement"}).AddRow(true, false, false))
+		mock.ExpectRollback()
+
+		flags, err := repo.SetPostFlags(context.Background(), 3, 7, entity.PostFlagsUpdate{Pinned: &on})
+		assert.NoError(t, err)
+		assert.True(t, flags.Pinned)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Post not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT pinned, locked, announcement`).
+			WithArgs(int64(4)).
+			WillReturnRows(sqlmock.NewRows([]string{"pinned", "locked", "announcement"}))
+		mock.ExpectRollback()
+
+		_, err := repo.SetPostFlags(context.Background(), 4, 7, entity.PostFlagsUpdate{Pinned: &on})
+		assert.ErrorIs(t, err, ErrPostNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetModerationLog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewModerationRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM moderation_log\s+WHERE \(\$1 = '' OR target_type = \$1\)(.|\n)*ORDER BY created_at DESC, id DESC\s+LIMIT \$4 OFFSET \$5`).
+		WithArgs(entity.ModerationTargetPost, int64(3), int64(0), 50, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "actor_id", "action", "target_type", "target_id", "details", "created_at"}).
+			AddRow(2, 7, "lock", "post", 3, "", now).
+			AddRow(1, 7, "pin", "post", 3, "", now))
+
+	entries, err := repo.GetModerationLog(context.Background(), entity.ModerationLogFilter{
+		TargetType: entity.ModerationTargetPost,
+		TargetID:   3,
+		Limit:      50,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, entity.ModerationActionLock, entries[0].Action)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
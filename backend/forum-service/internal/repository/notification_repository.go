@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, n *entity.Notification) (bool, error)
+	GetNotifications(ctx context.Context, recipientID int64, filter entity.NotificationFilter) ([]entity.Notification, error)
+	CountUnread(ctx context.Context, recipientID int64) (int, error)
+	MarkRead(ctx context.Context, recipientID, id int64) error
+	MarkAllRead(ctx context.Context, recipientID int64) (int64, error)
+}
+
+type notificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// CreateNotification сохраняет уведомление и заполняет ID и CreatedAt.
+// Если у получателя уже есть такое же непрочитанное уведомление, новое не создается
+// и возвращается false - так повторные реакции не засоряют входящие.
+func (r *notificationRepository) CreateNotification(ctx context.Context, n *entity.Notification) (bool, error) {
+	query := `
+		INSERT INTO notifications (recipient_id, type, actor_id, target_type, target_id, post_id)
+		SELECT $1, $2, $3, $4, $5, $6
+		WHERE NOT EXISTS (
+			SELECT 1 FROM notifications
+			WHERE recipient_id = $1 AND type = $2 AND actor_id = $3
+				AND target_type = $4 AND target_id = $5 AND NOT is_read
+		)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		n.RecipientID,
+		n.Type,
+		n.ActorID,
+		n.TargetType,
+		n.TargetID,
+		n.PostID,
+	).Scan(&n.ID, &n.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *notificationRepository) GetNotifications(ctx context.Context, recipientID int64, filter entity.NotificationFilter) ([]entity.Notification, error) {
+	query := `
+		SELECT id, recipient_id, type, actor_id, target_type, target_id, post_id, is_read, created_at
+		FROM notifications
+		WHERE recipient_id = $1 AND (NOT $2 OR NOT is_read)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4`
+
+	notifications := []entity.Notification{}
+	if err := r.db.SelectContext(ctx, &notifications, query, recipientID, filter.UnreadOnly, filter.Limit, filter.Offset); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, recipientID int64) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count,
+		`SELECT COUNT(*) FROM notifications WHERE recipient_id = $1 AND NOT is_read`, recipientID)
+	return count, err
+}
+
+// MarkRead отмечает уведомление прочитанным; чужие уведомления считаются отсутствующими
+func (r *notificationRepository) MarkRead(ctx context.Context, recipientID, id int64) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET is_read = TRUE WHERE id = $1 AND recipient_id = $2`, id, recipientID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, recipientID int64) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET is_read = TRUE WHERE recipient_id = $1 AND NOT is_read`, recipientID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
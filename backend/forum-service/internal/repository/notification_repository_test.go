@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateNotification(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewNotificationRepository(sqlx.NewDb(db, "sqlmock"))
+	args := []driver.Value{int64(2), entity.NotificationReplyPost, int64(1), entity.NotificationTargetComment, int64(10), int64(3)}
+
+	t.Run("Created", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(`INSERT INTO notifications(.|\n)*WHERE NOT EXISTS`).
+			WithArgs(args...).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, now))
+
+		n := &entity.Notification{RecipientID: 2, Type: entity.NotificationReplyPost, ActorID: 1,
+			TargetType: entity.NotificationTargetComment, TargetID: 10, PostID: 3}
+		created, err := repo.CreateNotification(context.Background(), n)
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, int64(5), n.ID)
+		assert.Equal(t, now, n.CreatedAt)
+	})
+
+	t.Run("Duplicate unread", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO notifications`).WithArgs(args...).WillReturnError(sql.ErrNoRows)
+
+		n := &entity.Notification{RecipientID: 2, Type: entity.NotificationReplyPost, ActorID: 1,
+			TargetType: entity.NotificationTargetComment, TargetID: 10, PostID: 3}
+		created, err := repo.CreateNotification(context.Background(), n)
+		assert.NoError(t, err)
+		assert.False(t, created)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNotifications(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewNotificationRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`FROM notifications\s+WHERE recipient_id = \$1 AND \(NOT \$2 OR NOT is_read\)\s+ORDER BY id DESC\s+LIMIT \$3 OFFSET \$4`).
+		WithArgs(int64(2), true, 20, 40).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipient_id", "type", "actor_id", "target_type", "target_id", "post_id", "is_read", "created_at"}).
+			AddRow(5, 2, "mention", 1, "post", 3, 3, false, time.Now()))
+
+	got, err := repo.GetNotifications(context.Background(), 2, entity.NotificationFilter{UnreadOnly: true, Limit: 20, Offset: 40})
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, entity.NotificationMention, got[0].Type)
+		assert.False(t, got[0].Read)
+	}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM notifications WHERE recipient_id = \$1 AND NOT is_read`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	count, err := repo.CountUnread(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkNotificationsRead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewNotificationRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`UPDATE notifications SET is_read = TRUE WHERE id = \$1 AND recipient_id = \$2`).
+		WithArgs(int64(5), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.MarkRead(context.Background(), 2, 5))
+
+	mock.ExpectExec(`UPDATE notifications SET is_read = TRUE WHERE id = \$1`).
+		WithArgs(int64(6), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.MarkRead(context.Background(), 2, 6), ErrNotificationNotFound)
+
+	mock.ExpectExec(`UPDATE notifications SET is_read = TRUE WHERE recipient_id = \$1 AND NOT is_read`).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	updated, err := repo.MarkAllRead(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), updated)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrPollNotFound = errors.New("poll not found")
+
+type PollRepository interface {
+	GetPollByPostID(ctx context.Context, postID int64) (*entity.Poll, error)
+	GetVoters(ctx context.Context, pollID int64) (map[int64][]int64, error)
+	GetUserVotes(ctx context.Context, pollID, userID int64) ([]int64, error)
+	Vote(ctx context.Context, pollID, userID int64, optionIDs []int64) error
+	ClosePoll(ctx context.Context, pollID int64, at time.Time) error
+	CloseExpiredPolls(ctx context.Context, now time.Time) ([]int64, error)
+}
+
+type pollRepository struct {
+	db *sqlx.DB
+}
+
+func NewPollRepository(db *sqlx.DB) PollRepository {
+	return &pollRepository{db: db}
+}
+
+// insertPoll сохраняет опрос поста и варианты ответа в транзакции tx
+func insertPoll(ctx context.Context, tx *sqlx.Tx, postID int64, in *entity.PollInput) (*entity.Poll, error) {
+	poll := &entity.Poll{
+		PostID:    postID,
+		Question:  in.Question,
+		Multiple:  in.Multiple,
+		Anonymous: in.Anonymous,
+		ClosesAt:  in.ClosesAt,
+	}
+	err := tx.QueryRowxContext(ctx, `
+		INSERT INTO polls (post_id, question, multiple, anonymous, closes_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		postID, in.Question, in.Multiple, in.Anonymous, in.ClosesAt,
+	).Scan(&poll.ID, &poll.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	poll.Options = make([]entity.PollOption, 0, len(in.Options))
+	for i, text := range in.Options {
+		option := entity.PollOption{PollID: poll.ID, Position: i, Text: text}
+		err := tx.QueryRowxContext(ctx,
+			`INSERT INTO poll_options (poll_id, position, text) VALUES ($1, $2, $3) RETURNING id`,
+			poll.ID, i, text,
+		).Scan(&option.ID)
+		if err != nil {
+			return nil, err
+		}
+		poll.Options = append(poll.Options, option)
+	}
+	return poll, nil
+}
+
+// GetPollByPostID возвращает опрос поста с числом голосов по каждому варианту
+func (r *pollRepository) GetPollByPostID(ctx context.Context, postID int64) (*entity.Poll, error) {
+	var poll entity.Poll
+	err := r.db.GetContext(ctx, &poll, `
+		SELECT p.id, p.post_id, p.question, p.multiple, p.anonymous, p.closes_at, p.closed_at, p.created_at,
+			(SELECT COUNT(*) FROM poll_voters v WHERE v.poll_id = p.id) AS total_voters
+		FROM polls p
+		WHERE p.post_id = $1`, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPollNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &poll.Options, `
+		SELECT o.id, o.poll_id, o.position, o.text,
+			(SELECT COUNT(*) FROM poll_votes v WHERE v.option_id = o.id) AS votes
+		FROM poll_options o
+		WHERE o.poll_id = $1
+		ORDER BY o.position`, poll.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// GetVoters возвращает ID проголосовавших по вариантам ответа в порядке голосования
+func (r *pollRepository) GetVoters(ctx context.Context, pollID int64) (map[int64][]int64, error) {
+	var rows []struct {
+		OptionID int64 `db:"option_id"`
+		UserID   int64 `db:"user_id"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT v.option_id, v.user_id
+		FROM poll_votes v
+		JOIN poll_voters pv ON pv.poll_id = v.poll_id AND pv.user_id = v.user_id
+		WHERE v.poll_id = $1
+		ORDER BY pv.created_at, v.user_id`, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	voters := make(map[int64][]int64)
+	for _, row := range rows {
+		voters[row.OptionID] = append(voters[row.OptionID], row.UserID)
+	}
+	return voters, nil
+}
+
+func (r *pollRepository) GetUserVotes(ctx context.Context, pollID, userID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.SelectContext(ctx, &ids,
+		`SELECT option_id FROM poll_votes WHERE poll_id = $1 AND user_id = $2 ORDER BY option_id`, pollID, userID)
+	return ids, err
+}
+
+// Vote сохраняет голос пользователя. Повторный голос возвращает ErrAlreadyVoted.
+func (r *pollRepository) Vote(ctx context.Context, pollID, userID int64, optionIDs []int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO poll_voters (poll_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, pollID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return entity.ErrAlreadyVoted
+	}
+
+	for _, optionID := range optionIDs {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO poll_votes (poll_id, option_id, user_id) VALUES ($1, $2, $3)`,
+			pollID, optionID, userID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ClosePoll закрывает опрос досрочно; уже закрытый опрос не меняется
+func (r *pollRepository) ClosePoll(ctx context.Context, pollID int64, at time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE polls SET closed_at = $2 WHERE id = $1 AND closed_at IS NULL`, pollID, at)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return entity.ErrPollClosed
+	}
+	return nil
+}
+
+// CloseExpiredPolls отмечает закрытыми опросы с истекшим сроком и возвращает ID их постов
+func (r *pollRepository) CloseExpiredPolls(ctx context.Context, now time.Time) ([]int64, error) {
+	var postIDs []int64
+	err := r.db.SelectContext(ctx, &postIDs, `
+		UPDATE polls SET closed_at = closes_at
+		WHERE closed_at IS NULL AND closes_at <= $1
+		RETURNING post_id`, now)
+	return postIDs, err
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePost_WithPoll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	// Пост и опрос создаются в одной транзакции
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO posts`).
+		WithArgs("Title", "Content", int64(1), now, "", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(`INSERT INTO polls \(post_id, question, multiple, anonymous, closes_at\)`).
+		WithArgs(int64(3), "Go or Rust?", true, false, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, now))
+	mock.ExpectQuery(`INSERT INTO poll_options \(poll_id, position, text\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
+		WithArgs(int64(5), 0, "Go").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectQuery(`INSERT INTO poll_options`).
+		WithArgs(int64(5), 1, "Rust").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectCommit()
+
+	post := &entity.Post{Title: "Title", Content: "Content", AuthorID: 1, CreatedAt: now}
+	id, err := repo.CreatePost(context.Background(), post, &entity.PollInput{
+		Question: "Go or Rust?",
+		Options:  []string{"Go", "Rust"},
+		Multiple: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), id)
+	poll := post.Poll
+	assert.Equal(t, int64(5), poll.ID)
+	assert.Equal(t, now, poll.CreatedAt)
+	assert.Equal(t, []entity.PollOption{
+		{ID: 11, PollID: 5, Position: 0, Text: "Go"},
+		{ID: 12, PollID: 5, Position: 1, Text: "Rust"},
+	}, poll.Options)
+
+	// Ошибка опроса откатывает пост
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO posts`).
+		WithArgs("Title", "Content", int64(1), now, "", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery(`INSERT INTO polls`).
+		WithArgs(int64(4), "Go or Rust?", false, false, nil).
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	_, err = repo.CreatePost(context.Background(), &entity.Post{Title: "Title", Content: "Content", AuthorID: 1, CreatedAt: now},
+		&entity.PollInput{Question: "Go or Rust?", Options: []string{"Go", "Rust"}})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPollByPostID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPollRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM polls p\s+WHERE p.post_id = \$1`).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "question", "multiple", "anonymous", "closes_at", "closed_at", "created_at", "total_voters"}).
+			AddRow(5, 3, "Go or Rust?", false, true, nil, nil, now, 3))
+	mock.ExpectQuery(`FROM poll_options o\s+WHERE o.poll_id = \$1\s+ORDER BY o.position`).
+		WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "poll_id", "position", "text", "votes"}).
+			AddRow(11, 5, 0, "Go", 2).
+			AddRow(12, 5, 1, "Rust", 1))
+
+	poll, err := repo.GetPollByPostID(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, poll.TotalVoters)
+	assert.Len(t, poll.Options, 2)
+	assert.Equal(t, 2, poll.Options[0].Votes)
+
+	mock.ExpectQuery(`FROM polls p`).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = repo.GetPollByPostID(context.Background(), 4)
+	assert.ErrorIs(t, err, ErrPollNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPollVote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPollRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO poll_voters \(poll_id, user_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+		WithArgs(int64(5), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO poll_votes \(poll_id, option_id, user_id\)`).
+		WithArgs(int64(5), int64(11), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO poll_votes`).
+		WithArgs(int64(5), int64(12), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.Vote(context.Background(), 5, 7, []int64{11, 12}))
+
+	// Повторный голос того же пользователя
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO poll_voters`).
+		WithArgs(int64(5), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.Vote(context.Background(), 5, 7, []int64{11}), entity.ErrAlreadyVoted)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetVoters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPollRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`FROM poll_votes v\s+JOIN poll_voters pv`).
+		WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"option_id", "user_id"}).
+			AddRow(11, 7).
+			AddRow(12, 7).
+			AddRow(11, 8))
+
+	voters, err := repo.GetVoters(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64][]int64{11: {7, 8}, 12: {7}}, voters)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClosePolls(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPollRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	mock.ExpectExec(`UPDATE polls SET closed_at = \$2 WHERE id = \$1 AND closed_at IS NULL`).
+		WithArgs(int64(5), now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.ClosePoll(context.Background(), 5, now))
+
+	mock.ExpectExec(`UPDATE polls SET closed_at`).
+		WithArgs(int64(5), now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.ClosePoll(context.Background(), 5, now), entity.ErrPollClosed)
+
+	mock.ExpectQuery(`UPDATE polls SET closed_at = closes_at\s+WHERE closed_at IS NULL AND closes_at <= \$1\s+RETURNING post_id`).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id"}).AddRow(3).AddRow(9))
+	postIDs, err := repo.CloseExpiredPolls(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 9}, postIDs)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
 
@@ -16,11 +18,12 @@ var (
 )
 
 type PostRepository interface {
-	CreatePost(ctx context.Context, post *entity.Post) (int64, error)
-	GetPosts(ctx context.Context) ([]*entity.Post, error)
+	CreatePost(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error)
+	GetPosts(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, error)
 	GetPostByID(ctx context.Context, id int64) (*entity.Post, error)
-	DeletePost(ctx context.Context, id, authorID int64, role string) error
-	UpdatePost(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error)
+	GetPostForModeration(ctx context.Context, id int64) (*entity.Post, error)
+	DeletePost(ctx context.Context, id, authorID int64, role, reason string) error
+	UpdatePost(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error)
 }
 
 type postRepository struct {
@@ -31,36 +34,109 @@ func NewPostRepository(db *sqlx.DB) PostRepository {
 	return &postRepository{db: db}
 }
 
-func (r *postRepository) CreatePost(ctx context.Context, post *entity.Post) (int64, error) {
+// CreatePost сохраняет пост вместе с тегами post.Tags и опросом poll в одной транзакции;
+// в post.Tags возвращаются основные slug, в post.Poll - созданный опрос. Рейтинг hot
+// считается сразу, чтобы новый пост не оказался в конце ленты до ближайшего пересчета RefreshScores.
+func (r *postRepository) CreatePost(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
 	query := `
-		INSERT INTO posts (title, content, author_id, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO posts (title, content, author_id, created_at, content_html, held, hot_score)
+		VALUES ($1, $2, $3, $4, $5, $6, ` + hotScoreExpr("0", "$4::timestamptz") + `)
 		RETURNING id`
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
 	var id int64
-	err := r.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		post.Title,
 		post.Content,
 		post.AuthorID,
 		post.CreatedAt,
+		post.ContentHTML,
+		post.Held,
 	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(post.Tags) > 0 {
+		if post.Tags, err = insertPostTags(ctx, tx, id, post.Tags); err != nil {
+			return 0, err
+		}
+	}
+	if poll != nil {
+		if post.Poll, err = insertPoll(ctx, tx, id, poll); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
 
-	return id, err
+// postOrderBy задает сортировку ленты для каждого режима выдачи
+var postOrderBy = map[entity.PostSort]string{
+	entity.PostSortNew: "created_at DESC",
+	entity.PostSortHot: "hot_score DESC, created_at DESC",
+	entity.PostSortTop: "score DESC, created_at DESC",
+	entity.PostSortControversial: `
+			CASE WHEN upvotes = 0 OR downvotes = 0 THEN 0
+			ELSE POWER(upvotes + downvotes, LEAST(upvotes, downvotes)::float / GREATEST(upvotes, downvotes))
+			END DESC, created_at DESC`,
 }
 
-func (r *postRepository) GetPosts(ctx context.Context) ([]*entity.Post, error) {
+func (r *postRepository) GetPosts(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, error) {
 	query := `
 		SELECT 
 			id,
 			title,
 			content,
+			content_html,
 			author_id,
-			created_at
+			created_at,
+			upvotes,
+			downvotes,
+			comment_count,
+			score,
+			pinned,
+			locked,
+			announcement
 		FROM posts
-		ORDER BY created_at DESC`
+		WHERE deleted_at IS NULL AND NOT held`
+
+	var args []interface{}
+	// Закрепленные посты и объявления остаются в ленте независимо от периода
+	if period := filter.Period.Duration(); period > 0 {
+		query += `
+		AND (created_at >= $1 OR pinned OR announcement)`
+		args = append(args, time.Now().Add(-period))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		query += fmt.Sprintf(`
+		AND id IN (
+			SELECT pt.post_id
+			FROM post_tags pt
+			JOIN tags t ON t.id = pt.tag_id
+			LEFT JOIN tag_synonyms s ON s.tag_id = t.id
+			WHERE t.slug = $%[1]d OR s.slug = $%[1]d)`, len(args))
+	}
+
+	orderBy, ok := postOrderBy[filter.Sort]
+	if !ok {
+		orderBy = postOrderBy[entity.PostSortNew]
+	}
+	// Объявления выводятся первыми, за ними закрепленные посты, затем остальные в порядке sort
+	query += `
+		ORDER BY announcement DESC, pinned DESC, ` + orderBy
 
 	var posts []*entity.Post
-	err := r.db.SelectContext(ctx, &posts, query)
+	err := r.db.SelectContext(ctx, &posts, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return []*entity.Post{}, nil
@@ -76,10 +152,14 @@ func (r *postRepository) GetPostByID(ctx context.Context, id int64) (*entity.Pos
 			id,
 			title,
 			content,
+			content_html,
 			author_id,
-			created_at
+			created_at,
+			pinned,
+			locked,
+			announcement
 		FROM posts
-		WHERE id = $1`
+		WHERE id = $1 AND deleted_at IS NULL AND NOT held`
 
 	var post entity.Post
 	err := r.db.GetContext(ctx, &post, query, id)
@@ -92,13 +172,36 @@ func (r *postRepository) GetPostByID(ctx context.Context, id int64) (*entity.Pos
 	return &post, nil
 }
 
-func (r *postRepository) DeletePost(ctx context.Context, id, authorID int64, role string) error {
+// GetPostForModeration возвращает пост вместе с отложенным фильтром контентом, чтобы модератор мог его оценить
+func (r *postRepository) GetPostForModeration(ctx context.Context, id int64) (*entity.Post, error) {
 	query := `
-		DELETE FROM posts 
+		SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement, held
+		FROM posts
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	var post entity.Post
+	err := r.db.GetContext(ctx, &post, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	return &post, nil
+}
+
+// DeletePost помещает пост в корзину: строка остается в таблице вместе с комментариями,
+// пока ее не удалит фоновая очистка. authorID - идентификатор удаляющего пользователя;
+// чужой пост могут удалить роли admin и moderator (см. isModerator в usecase).
+func (r *postRepository) DeletePost(ctx context.Context, id, authorID int64, role, reason string) error {
+	query := `
+		UPDATE posts
+		SET deleted_at = NOW(), deleted_by = $2, delete_reason = $4
 		WHERE id = $1 
-		AND (author_id = $2 OR $3 = 'admin')`
+		AND deleted_at IS NULL
+		AND (author_id = $2 OR $3 IN ('admin', 'moderator'))`
 
-	result, err := r.db.ExecContext(ctx, query, id, authorID, role)
+	result, err := r.db.ExecContext(ctx, query, id, authorID, role, reason)
 	if err != nil {
 		return err
 	}
@@ -115,12 +218,25 @@ func (r *postRepository) DeletePost(ctx context.Context, id, authorID int64, rol
 	return nil
 }
 
-func (r *postRepository) UpdatePost(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+// UpdatePost обновляет пост и в том же запросе сохраняет предыдущую версию в post_revisions.
+// authorID - идентификатор редактирующего пользователя, contentHTML - отрисованный content.
+// held скрывает правку, отложенную фильтром, в том же запросе; снять held правкой нельзя.
+func (r *postRepository) UpdatePost(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error) {
 	query := `
-		UPDATE posts
-		SET title = $1, content = $2
-		WHERE id = $3 AND (author_id = $4 OR $5 = 'admin')
-		RETURNING id, title, content, author_id, created_at`
+		WITH prev AS (
+			SELECT id, title, content
+			FROM posts
+			WHERE id = $3 AND deleted_at IS NULL AND (author_id = $4 OR $5 = 'admin')
+			FOR UPDATE
+		), rev AS (
+			INSERT INTO post_revisions (post_id, editor_id, title, content)
+			SELECT id, $4, title, content FROM prev
+		)
+		UPDATE posts p
+		SET title = $1, content = $2, content_html = $6, held = p.held OR $7, updated_at = NOW()
+		FROM prev
+		WHERE p.id = prev.id
+		RETURNING p.id, p.title, p.content, p.content_html, p.author_id, p.created_at, p.updated_at, p.held`
 
 	var post entity.Post
 	err := r.db.QueryRowContext(ctx, query,
@@ -129,12 +245,17 @@ func (r *postRepository) UpdatePost(ctx context.Context, id, authorID int64, rol
 		id,
 		authorID,
 		role,
+		contentHTML,
+		held,
 	).Scan(
 		&post.ID,
 		&post.Title,
 		&post.Content,
+		&post.ContentHTML,
 		&post.AuthorID,
 		&post.CreatedAt,
+		&post.UpdatedAt,
+		&post.Held,
 	)
 
 	if err != nil {
@@ -43,12 +43,59 @@ func TestCreatePost(t *testing.T) {
 				CreatedAt: now,
 			},
 			mock: func() {
-				mock.ExpectQuery(`INSERT INTO posts`).
-					WithArgs("Test Post", "Test Content", int64(1), now).
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO posts \(.*, hot_score\)\s+VALUES \(.*EXTRACT\(EPOCH FROM \$4::timestamptz\) / 45000\)`).
+					WithArgs("Test Post", "Test Content", int64(1), now, "", false).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectCommit()
 			},
 			want: 1,
 		},
+		{
+			name: "With Tags",
+			post: &entity.Post{
+				Title:     "Tagged",
+				Content:   "Content",
+				AuthorID:  1,
+				CreatedAt: now,
+				Tags:      []string{"go"},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO posts`).
+					WithArgs("Tagged", "Content", int64(1), now, "", false).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(`FROM tag_synonyms s JOIN tags t`).
+					WithArgs("go").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}).AddRow(1, "golang"))
+				mock.ExpectExec(`INSERT INTO post_tags \(post_id, tag_id\) VALUES \(\$1, \$2\)`).
+					WithArgs(int64(2), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: 2,
+		},
+		{
+			name: "Tag Error Rolls Back Post",
+			post: &entity.Post{
+				Title:     "Tagged",
+				Content:   "Content",
+				AuthorID:  1,
+				CreatedAt: now,
+				Tags:      []string{"go"},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO posts`).
+					WithArgs("Tagged", "Content", int64(1), now, "", false).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectQuery(`FROM tag_synonyms s JOIN tags t`).
+					WithArgs("go").
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
 		{
 			name: "Empty Fields",
 			post: &entity.Post{
@@ -58,9 +105,11 @@ func TestCreatePost(t *testing.T) {
 				CreatedAt: now,
 			},
 			mock: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery(`INSERT INTO posts`).
-					WithArgs("", "", int64(1), now).
+					WithArgs("", "", int64(1), now, "", false).
 					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -70,7 +119,7 @@ func TestCreatePost(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repo.CreatePost(context.Background(), tt.post)
+			got, err := repo.CreatePost(context.Background(), tt.post, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreatePost() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -78,6 +127,7 @@ func TestCreatePost(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("CreatePost() got = %v, want %v", got, tt.want)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -147,7 +197,7 @@ func TestGetPosts(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repo.GetPosts(context.Background())
+			got, err := repo.GetPosts(context.Background(), entity.PostFilter{Sort: entity.PostSortNew, Period: entity.PostPeriodAll})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetPosts() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -168,6 +218,51 @@ func TestGetPosts(t *testing.T) {
 	}
 }
 
+func TestGetPostsSorted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostRepository(sqlx.NewDb(db, "sqlmock"))
+
+	tests := []struct {
+		name    string
+		filter  entity.PostFilter
+		orderBy string
+		args    int
+	}{
+		{name: "Hot", filter: entity.PostFilter{Sort: entity.PostSortHot, Period: entity.PostPeriodAll}, orderBy: `ORDER BY announcement DESC, pinned DESC, hot_score DESC`},
+		{name: "Top week", filter: entity.PostFilter{Sort: entity.PostSortTop, Period: entity.PostPeriodWeek}, orderBy: `WHERE deleted_at IS NULL AND NOT held\s+AND \(created_at >= \$1 OR pinned OR announcement\)\s+ORDER BY announcement DESC, pinned DESC, score DESC`, args: 1},
+		{name: "Controversial", filter: entity.PostFilter{Sort: entity.PostSortControversial, Period: entity.PostPeriodAll}, orderBy: `ORDER BY announcement DESC, pinned DESC,\s+CASE WHEN upvotes = 0 OR downvotes = 0`},
+		{name: "Tag", filter: entity.PostFilter{Sort: entity.PostSortNew, Period: entity.PostPeriodAll, Tag: "golang"}, orderBy: `FROM post_tags pt(.|\n)*WHERE t.slug = \$1 OR s.slug = \$1\)\s+ORDER BY announcement DESC, pinned DESC, created_at DESC`, args: 1},
+		{name: "Tag within period", filter: entity.PostFilter{Sort: entity.PostSortTop, Period: entity.PostPeriodDay, Tag: "golang"}, orderBy: `created_at >= \$1(.|\n)*WHERE t.slug = \$2 OR s.slug = \$2\)`, args: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at", "upvotes", "downvotes", "comment_count", "score", "pinned", "locked", "announcement"}).
+				AddRow(1, "Post 1", "Content 1", 1, time.Now(), 5, 1, 2, 4, true, false, false)
+			expect := mock.ExpectQuery(tt.orderBy)
+			switch tt.args {
+			case 1:
+				expect.WithArgs(sqlmock.AnyArg())
+			case 2:
+				expect.WithArgs(sqlmock.AnyArg(), tt.filter.Tag)
+			}
+			expect.WillReturnRows(rows)
+
+			got, err := repo.GetPosts(context.Background(), tt.filter)
+			assert.NoError(t, err)
+			assert.Len(t, got, 1)
+			assert.Equal(t, 4, got[0].Score)
+			assert.True(t, got[0].Pinned)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestDeletePost(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -192,8 +287,8 @@ func TestDeletePost(t *testing.T) {
 			authorID: 1,
 			role:     "user",
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM posts`).
-					WithArgs(int64(1), int64(1), "user").
+				mock.ExpectExec(`UPDATE posts SET deleted_at = NOW\(\)`).
+					WithArgs(int64(1), int64(1), "user", "").
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
@@ -203,8 +298,19 @@ func TestDeletePost(t *testing.T) {
 			authorID: 2,
 			role:     "admin",
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM posts`).
-					WithArgs(int64(1), int64(2), "admin").
+				mock.ExpectExec(`UPDATE posts SET deleted_at = NOW\(\)`).
+					WithArgs(int64(1), int64(2), "admin", "").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:     "Success - Moderator",
+			postID:   1,
+			authorID: 3,
+			role:     "moderator",
+			mock: func() {
+				mock.ExpectExec(`UPDATE posts SET deleted_at = NOW\(\)`).
+					WithArgs(int64(1), int64(3), "moderator", "").
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 		},
@@ -214,8 +320,8 @@ func TestDeletePost(t *testing.T) {
 			authorID: 1,
 			role:     "user",
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM posts`).
-					WithArgs(int64(2), int64(1), "user").
+				mock.ExpectExec(`UPDATE posts SET deleted_at = NOW\(\)`).
+					WithArgs(int64(2), int64(1), "user", "").
 					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 			wantErr: true,
@@ -226,7 +332,7 @@ func TestDeletePost(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := repo.DeletePost(context.Background(), tt.postID, tt.authorID, tt.role)
+			err := repo.DeletePost(context.Background(), tt.postID, tt.authorID, tt.role, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeletePost() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -305,6 +411,26 @@ func TestGetPostByID(t *testing.T) {
 	}
 }
 
+func TestGetPostForModeration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement, held\s+FROM posts\s+WHERE id = \$1 AND deleted_at IS NULL$`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "held"}).AddRow(1, "Cheap", "buy now", 8, true))
+
+	post, err := repo.GetPostForModeration(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.True(t, post.Held)
+	assert.Equal(t, "buy now", post.Content)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpdatePost(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -324,6 +450,7 @@ func TestUpdatePost(t *testing.T) {
 		role     string
 		title    string
 		content  string
+		held     bool
 		mock     func()
 		want     *entity.Post
 		wantErr  error
@@ -336,18 +463,20 @@ func TestUpdatePost(t *testing.T) {
 			title:    "Updated Title",
 			content:  "Updated Content",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
-					AddRow(1, "Updated Title", "Updated Content", 1, now)
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "created_at", "updated_at", "held"}).
+					AddRow(1, "Updated Title", "Updated Content", "<p>Updated Content</p>\n", 1, now, now, false)
 				mock.ExpectQuery(`UPDATE posts`).
-					WithArgs("Updated Title", "Updated Content", int64(1), int64(1), "user").
+					WithArgs("Updated Title", "Updated Content", int64(1), int64(1), "user", "<p>Updated Content</p>\n", false).
 					WillReturnRows(rows)
 			},
 			want: &entity.Post{
-				ID:        1,
-				Title:     "Updated Title",
-				Content:   "Updated Content",
-				AuthorID:  1,
-				CreatedAt: now,
+				ID:          1,
+				Title:       "Updated Title",
+				Content:     "Updated Content",
+				ContentHTML: "<p>Updated Content</p>\n",
+				AuthorID:    1,
+				CreatedAt:   now,
+				UpdatedAt:   now,
 			},
 		},
 		{
@@ -358,18 +487,46 @@ func TestUpdatePost(t *testing.T) {
 			title:    "Updated Title",
 			content:  "Updated Content",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
-					AddRow(1, "Updated Title", "Updated Content", 1, now)
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "created_at", "updated_at", "held"}).
+					AddRow(1, "Updated Title", "Updated Content", "<p>Updated Content</p>\n", 1, now, now, false)
 				mock.ExpectQuery(`UPDATE posts`).
-					WithArgs("Updated Title", "Updated Content", int64(1), int64(2), "admin").
+					WithArgs("Updated Title", "Updated Content", int64(1), int64(2), "admin", "<p>Updated Content</p>\n", false).
 					WillReturnRows(rows)
 			},
 			want: &entity.Post{
-				ID:        1,
-				Title:     "Updated Title",
-				Content:   "Updated Content",
-				AuthorID:  1,
-				CreatedAt: now,
+				ID:          1,
+				Title:       "Updated Title",
+				Content:     "Updated Content",
+				ContentHTML: "<p>Updated Content</p>\n",
+				AuthorID:    1,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			},
+		},
+		{
+			name:     "Success - Held Edit",
+			postID:   1,
+			authorID: 1,
+			role:     "user",
+			title:    "Updated Title",
+			content:  "Updated Content",
+			held:     true,
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "created_at", "updated_at", "held"}).
+					AddRow(1, "Updated Title", "Updated Content", "<p>Updated Content</p>\n", 1, now, now, true)
+				mock.ExpectQuery(`UPDATE posts p\s+SET title = \$1, content = \$2, content_html = \$6, held = p.held OR \$7`).
+					WithArgs("Updated Title", "Updated Content", int64(1), int64(1), "user", "<p>Updated Content</p>\n", true).
+					WillReturnRows(rows)
+			},
+			want: &entity.Post{
+				ID:          1,
+				Title:       "Updated Title",
+				Content:     "Updated Content",
+				ContentHTML: "<p>Updated Content</p>\n",
+				AuthorID:    1,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+				Held:        true,
 			},
 		},
 		{
@@ -381,7 +538,7 @@ func TestUpdatePost(t *testing.T) {
 			content:  "Updated Content",
 			mock: func() {
 				mock.ExpectQuery(`UPDATE posts`).
-					WithArgs("Updated Title", "Updated Content", int64(2), int64(1), "user").
+					WithArgs("Updated Title", "Updated Content", int64(2), int64(1), "user", "<p>Updated Content</p>\n", false).
 					WillReturnError(sql.ErrNoRows)
 			},
 			wantErr: ErrPostNotFound,
@@ -395,7 +552,7 @@ func TestUpdatePost(t *testing.T) {
 			content:  "Updated Content",
 			mock: func() {
 				mock.ExpectQuery(`UPDATE posts`).
-					WithArgs("Updated Title", "Updated Content", int64(3), int64(1), "user").
+					WithArgs("Updated Title", "Updated Content", int64(3), int64(1), "user", "<p>Updated Content</p>\n", false).
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: sql.ErrConnDone,
@@ -406,7 +563,7 @@ func TestUpdatePost(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := repo.UpdatePost(context.Background(), tt.postID, tt.authorID, tt.role, tt.title, tt.content)
+			got, err := repo.UpdatePost(context.Background(), tt.postID, tt.authorID, tt.role, tt.title, tt.content, "<p>"+tt.content+"</p>\n", tt.held)
 			if err != tt.wantErr {
 				if !errors.Is(err, tt.wantErr) {
 					t.Errorf("UpdatePost() error = %v, wantErr %v", err, tt.wantErr)
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ReportRepository interface {
+	CreateReport(ctx context.Context, report *entity.Report) error
+	GetReportGroups(ctx context.Context, filter entity.ReportFilter) ([]entity.ReportGroup, error)
+	ClaimReports(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) ([]entity.Report, error)
+	ReopenReports(ctx context.Context, ids []int64) error
+	CompleteResolution(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) error
+}
+
+type reportRepository struct {
+	db *sqlx.DB
+}
+
+func NewReportRepository(db *sqlx.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+const reportColumns = `id, reporter_id, target_type, target_id, target_author_id, reason, details,
+		status, resolution, resolution_note, resolved_by, resolved_at, created_at`
+
+// CreateReport сохраняет жалобу и заполняет ID, Status и CreatedAt.
+// Повторная жалоба того же пользователя на объект, пока первая открыта, дает entity.ErrAlreadyReported.
+func (r *reportRepository) CreateReport(ctx context.Context, report *entity.Report) error {
+	query := `
+		INSERT INTO reports (reporter_id, target_type, target_id, target_author_id, reason, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+		RETURNING id, status, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		report.ReporterID,
+		report.TargetType,
+		report.TargetID,
+		report.TargetAuthorID,
+		report.Reason,
+		report.Details,
+	).Scan(&report.ID, &report.Status, &report.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrAlreadyReported
+	}
+	return err
+}
+
+// GetReportGroups возвращает страницу очереди, сгруппированную по объектам. Открытые жалобы
+// упорядочены по числу жалоб на объект, история (resolved, dismissed) - по времени решения.
+func (r *reportRepository) GetReportGroups(ctx context.Context, filter entity.ReportFilter) ([]entity.ReportGroup, error) {
+	query := `
+		WITH targets AS (
+			SELECT target_type, target_id,
+				COUNT(*) AS cnt,
+				MAX(COALESCE(resolved_at, created_at)) AS last_at
+			FROM reports
+			WHERE status = $1 AND ($2 = '' OR target_type = $2)
+			GROUP BY target_type, target_id
+			ORDER BY CASE WHEN $1 = 'open' THEN COUNT(*) ELSE 0 END DESC, last_at DESC, target_type, target_id
+			LIMIT $3 OFFSET $4
+		)
+		SELECT r.id, r.reporter_id, r.target_type, r.target_id, r.target_author_id, r.reason, r.details,
+			r.status, r.resolution, r.resolution_note, r.resolved_by, r.resolved_at, r.created_at
+		FROM reports r
+		JOIN targets t ON t.target_type = r.target_type AND t.target_id = r.target_id
+		WHERE r.status = $1
+		ORDER BY CASE WHEN $1 = 'open' THEN t.cnt ELSE 0 END DESC, t.last_at DESC,
+			r.target_type, r.target_id, r.created_at DESC`
+
+	var reports []entity.Report
+	err := r.db.SelectContext(ctx, &reports, query,
+		filter.Status, filter.TargetType, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return entity.GroupReports(reports), nil
+}
+
+// ClaimReports атомарно закрывает открытые жалобы на объект решением модератора и возвращает их.
+// Из двух одновременных решений по одному объекту жалобы достаются только одному;
+// второе получает entity.ErrNoOpenReports.
+func (r *reportRepository) ClaimReports(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) ([]entity.Report, error) {
+	query := `
+		UPDATE reports
+		SET status = $3, resolution = $4, resolution_note = $5, resolved_by = $6, resolved_at = NOW()
+		WHERE target_type = $1 AND target_id = $2 AND status = 'open'
+		RETURNING ` + reportColumns
+
+	reports := []entity.Report{}
+	err := r.db.SelectContext(ctx, &reports, query,
+		targetType, targetID, resolution.Action.Status(), resolution.Action, resolution.Note, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, entity.ErrNoOpenReports
+	}
+	return reports, nil
+}
+
+// ReopenReports возвращает в очередь жалобы, решение по которым не удалось выполнить
+func (r *reportRepository) ReopenReports(ctx context.Context, ids []int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE reports
+		SET status = 'open', resolution = '', resolution_note = '', resolved_by = NULL, resolved_at = NULL
+		WHERE id = ANY($1)`,
+		pq.Array(ids))
+	return err
+}
+
+// CompleteResolution записывает выполненное решение в журнал модерации. Отклоненные жалобы
+// в той же транзакции снимают скрытие, наложенное фильтром контента.
+func (r *reportRepository) CompleteResolution(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if table, ok := heldTables[targetType]; ok && resolution.Action == entity.ReportActionDismiss {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET held = FALSE WHERE id = $1 AND held`, table), targetID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO moderation_log (actor_id, action, target_type, target_id, details) VALUES ($1, $2, $3, $4, $5)`,
+		actorID, resolution.Action.ModerationAction(), entity.ModerationTarget(targetType), targetID, resolution.Details()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var reportRowColumns = []string{"id", "reporter_id", "target_type", "target_id", "target_author_id", "reason", "details",
+	"status", "resolution", "resolution_note", "resolved_by", "resolved_at", "created_at"}
+
+func TestCreateReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewReportRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+	query := `INSERT INTO reports \(reporter_id, target_type, target_id, target_author_id, reason, details\)(.|\n)*ON CONFLICT DO NOTHING\s+RETURNING id, status, created_at`
+
+	t.Run("Created", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(int64(5), entity.ReportTargetPost, int64(3), int64(9), entity.ReportReasonSpam, "casino").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(1, "open", now))
+
+		report := &entity.Report{ReporterID: 5, TargetType: entity.ReportTargetPost, TargetID: 3, TargetAuthorID: 9, Reason: entity.ReportReasonSpam, Details: "casino"}
+		assert.NoError(t, repo.CreateReport(context.Background(), report))
+		assert.Equal(t, int64(1), report.ID)
+		assert.Equal(t, entity.ReportStatusOpen, report.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Already reported", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}))
+
+		report := &entity.Report{ReporterID: 5, TargetType: entity.ReportTargetPost, TargetID: 3, TargetAuthorID: 9, Reason: entity.ReportReasonSpam}
+		assert.ErrorIs(t, repo.CreateReport(context.Background(), report), entity.ErrAlreadyReported)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetReportGroups(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewReportRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	mock.ExpectQuery(`WITH targets AS \((.|\n)*GROUP BY target_type, target_id(.|\n)*LIMIT \$3 OFFSET \$4\s+\)(.|\n)*JOIN targets t`).
+		WithArgs(entity.ReportStatusOpen, entity.ReportTarget(""), 20, 0).
+		WillReturnRows(sqlmock.NewRows(reportRowColumns).
+			AddRow(3, 6, "post", 3, 9, "spam", "", "open", "", "", nil, nil, now).
+			AddRow(1, 5, "post", 3, 9, "abuse", "", "open", "", "", nil, nil, now.Add(-time.Hour)).
+			AddRow(2, 5, "message", 6, 8, "spam", "", "open", "", "", nil, nil, now))
+
+	groups, err := repo.GetReportGroups(context.Background(), entity.ReportFilter{Status: entity.ReportStatusOpen, Limit: 20})
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, 2, groups[0].Count)
+		assert.Equal(t, entity.ReportTargetMessage, groups[1].TargetType)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimReports(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewReportRepository(sqlx.NewDb(db, "sqlmock"))
+	resolution := entity.ReportResolution{Action: entity.ReportActionSuspend, Note: "flood", SuspendHours: 48}
+	query := `UPDATE reports\s+SET status = \$3, resolution = \$4, resolution_note = \$5, resolved_by = \$6, resolved_at = NOW\(\)\s+WHERE target_type = \$1 AND target_id = \$2 AND status = 'open'\s+RETURNING id`
+	now := time.Now()
+
+	t.Run("Claimed", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(entity.ReportTargetMessage, int64(6), entity.ReportStatusResolved, entity.ReportActionSuspend, "flood", int64(7)).
+			WillReturnRows(sqlmock.NewRows(reportRowColumns).
+				AddRow(1, 5, "message", 6, 8, "spam", "", "resolved", "suspend", "flood", 7, now, now).
+				AddRow(2, 6, "message", 6, 8, "abuse", "", "resolved", "suspend", "flood", 7, now, now))
+
+		reports, err := repo.ClaimReports(context.Background(), entity.ReportTargetMessage, 6, 7, resolution)
+		assert.NoError(t, err)
+		if assert.Len(t, reports, 2) {
+			assert.Equal(t, int64(8), reports[0].TargetAuthorID)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Nothing open", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows(reportRowColumns))
+
+		_, err := repo.ClaimReports(context.Background(), entity.ReportTargetMessage, 6, 7, resolution)
+		assert.ErrorIs(t, err, entity.ErrNoOpenReports)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReopenReports(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewReportRepository(sqlx.NewDb(db, "sqlmock"))
+	ids := []int64{1, 2}
+
+	mock.ExpectExec(`UPDATE reports\s+SET status = 'open', resolution = '', resolution_note = '', resolved_by = NULL, resolved_at = NULL\s+WHERE id = ANY\(\$1\)`).
+		WithArgs(pq.Array(ids)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, repo.ReopenReports(context.Background(), ids))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCompleteResolution(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewReportRepository(sqlx.NewDb(db, "sqlmock"))
+
+	t.Run("Logged", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO moderation_log \(actor_id, action, target_type, target_id, details\)`).
+			WithArgs(int64(7), entity.ModerationActionSuspendUser, entity.ModerationTargetMessage, int64(6), "suspended for 48h: flood").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.CompleteResolution(context.Background(), entity.ReportTargetMessage, 6, 7,
+			entity.ReportResolution{Action: entity.ReportActionSuspend, Note: "flood", SuspendHours: 48})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Dismiss releases held post", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE posts SET held = FALSE WHERE id = \$1 AND held`).
+			WithArgs(int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO moderation_log`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.CompleteResolution(context.Background(), entity.ReportTargetPost, 3, 7, entity.ReportResolution{Action: entity.ReportActionDismiss})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+type RevisionRepository interface {
+	GetPostRevisions(ctx context.Context, postID int64) ([]entity.Revision, error)
+	GetPostRevision(ctx context.Context, postID int64, number int) (*entity.Revision, error)
+	GetCommentRevisions(ctx context.Context, commentID int64) ([]entity.Revision, error)
+	GetCommentRevision(ctx context.Context, commentID int64, number int) (*entity.Revision, error)
+}
+
+type revisionRepository struct {
+	db *sqlx.DB
+}
+
+func NewRevisionRepository(db *sqlx.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+const postRevisionsQuery = `
+	SELECT
+		id,
+		ROW_NUMBER() OVER (ORDER BY id) AS number,
+		editor_id,
+		title,
+		content,
+		created_at
+	FROM post_revisions
+	WHERE post_id = $1`
+
+const commentRevisionsQuery = `
+	SELECT
+		id,
+		ROW_NUMBER() OVER (ORDER BY id) AS number,
+		editor_id,
+		'' AS title,
+		content,
+		created_at
+	FROM comment_revisions
+	WHERE comment_id = $1`
+
+func (r *revisionRepository) GetPostRevisions(ctx context.Context, postID int64) ([]entity.Revision, error) {
+	return r.list(ctx, postRevisionsQuery, postID)
+}
+
+func (r *revisionRepository) GetPostRevision(ctx context.Context, postID int64, number int) (*entity.Revision, error) {
+	return r.get(ctx, postRevisionsQuery, postID, number)
+}
+
+func (r *revisionRepository) GetCommentRevisions(ctx context.Context, commentID int64) ([]entity.Revision, error) {
+	return r.list(ctx, commentRevisionsQuery, commentID)
+}
+
+func (r *revisionRepository) GetCommentRevision(ctx context.Context, commentID int64, number int) (*entity.Revision, error) {
+	return r.get(ctx, commentRevisionsQuery, commentID, number)
+}
+
+func (r *revisionRepository) list(ctx context.Context, base string, targetID int64) ([]entity.Revision, error) {
+	revisions := []entity.Revision{}
+	if err := r.db.SelectContext(ctx, &revisions, base+` ORDER BY id`, targetID); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *revisionRepository) get(ctx context.Context, base string, targetID int64, number int) (*entity.Revision, error) {
+	query := `SELECT * FROM (` + base + `) r WHERE r.number = $2`
+
+	var revision entity.Revision
+	err := r.db.GetContext(ctx, &revision, query, targetID, number)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var revisionColumns = []string{"id", "number", "editor_id", "title", "content", "created_at"}
+
+func TestGetPostRevisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewRevisionRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM post_revisions\s+WHERE post_id = \$1 ORDER BY id`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(revisionColumns).
+			AddRow(10, 1, 1, "First", "Original", now).
+			AddRow(11, 2, 2, "Second", "Edited", now))
+
+	got, err := repo.GetPostRevisions(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Revision{
+		{ID: 10, Number: 1, EditorID: 1, Title: "First", Content: "Original", CreatedAt: now},
+		{ID: 11, Number: 2, EditorID: 2, Title: "Second", Content: "Edited", CreatedAt: now},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCommentRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewRevisionRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`FROM comment_revisions\s+WHERE comment_id = \$1\) r WHERE r.number = \$2`).
+			WithArgs(int64(3), 1).
+			WillReturnRows(sqlmock.NewRows(revisionColumns).AddRow(20, 1, 4, "", "Old comment", now))
+
+		got, err := repo.GetCommentRevision(context.Background(), 3, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, &entity.Revision{ID: 20, Number: 1, EditorID: 4, Content: "Old comment", CreatedAt: now}, got)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mock.ExpectQuery(`FROM comment_revisions`).
+			WithArgs(int64(3), 9).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetCommentRevision(context.Background(), 3, 9)
+		assert.ErrorIs(t, err, ErrRevisionNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
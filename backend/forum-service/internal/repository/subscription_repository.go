@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrSubscriptionNotFound   = errors.New("subscription not found")
+	ErrDigestSettingsNotFound = errors.New("digest settings not found")
+)
+
+// MaxDigestComments ограничивает число комментариев в одном дайджесте
+const MaxDigestComments = 200
+
+type SubscriptionRepository interface {
+	Subscribe(ctx context.Context, userID, postID int64) error
+	Unsubscribe(ctx context.Context, userID, postID int64) error
+	GetSubscriptions(ctx context.Context, userID int64) ([]entity.Subscription, error)
+
+	GetDigestSettings(ctx context.Context, userID int64) (*entity.DigestSettings, error)
+	SaveDigestSettings(ctx context.Context, settings *entity.DigestSettings) error
+	DisableDigestByToken(ctx context.Context, token string) error
+	GetDueDigests(ctx context.Context, now time.Time) ([]entity.DigestSettings, error)
+	GetDigestComments(ctx context.Context, userID int64, since time.Time) ([]entity.DigestComment, error)
+	MarkDigestSent(ctx context.Context, userID int64, sentAt, sentUntil time.Time) error
+}
+
+type subscriptionRepository struct {
+	db *sqlx.DB
+}
+
+func NewSubscriptionRepository(db *sqlx.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+// Subscribe подписывает пользователя на пост; повторная подписка не является ошибкой
+func (r *subscriptionRepository) Subscribe(ctx context.Context, userID, postID int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO subscriptions (user_id, post_id) VALUES ($1, $2) ON CONFLICT (user_id, post_id) DO NOTHING`,
+		userID, postID)
+	return err
+}
+
+func (r *subscriptionRepository) Unsubscribe(ctx context.Context, userID, postID int64) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM subscriptions WHERE user_id = $1 AND post_id = $2`, userID, postID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// GetSubscriptions возвращает подписки пользователя; посты в корзине не показываются
+func (r *subscriptionRepository) GetSubscriptions(ctx context.Context, userID int64) ([]entity.Subscription, error) {
+	query := `
+		SELECT s.id, s.user_id, s.post_id, p.title AS post_title, s.created_at
+		FROM subscriptions s
+		JOIN posts p ON p.id = s.post_id
+		WHERE s.user_id = $1 AND p.deleted_at IS NULL
+		ORDER BY s.id DESC`
+
+	subscriptions := []entity.Subscription{}
+	if err := r.db.SelectContext(ctx, &subscriptions, query, userID); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *subscriptionRepository) GetDigestSettings(ctx context.Context, userID int64) (*entity.DigestSettings, error) {
+	query := `
+		SELECT user_id, email, frequency, unsubscribe_token, last_sent_at, sent_until, created_at
+		FROM digest_settings
+		WHERE user_id = $1`
+
+	var settings entity.DigestSettings
+	if err := r.db.GetContext(ctx, &settings, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDigestSettingsNotFound
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveDigestSettings создает или обновляет настройки. Токен отписки задается только при создании,
+// чтобы ссылки из уже отправленных писем продолжали работать; в settings возвращается действующий токен.
+func (r *subscriptionRepository) SaveDigestSettings(ctx context.Context, settings *entity.DigestSettings) error {
+	query := `
+		INSERT INTO digest_settings (user_id, email, frequency, unsubscribe_token)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = EXCLUDED.email, frequency = EXCLUDED.frequency, updated_at = CURRENT_TIMESTAMP
+		RETURNING unsubscribe_token, last_sent_at, created_at`
+
+	return r.db.QueryRowContext(ctx, query,
+		settings.UserID,
+		settings.Email,
+		settings.Frequency,
+		settings.UnsubscribeToken,
+	).Scan(&settings.UnsubscribeToken, &settings.LastSentAt, &settings.CreatedAt)
+}
+
+// DisableDigestByToken выключает дайджест по токену из ссылки отписки
+func (r *subscriptionRepository) DisableDigestByToken(ctx context.Context, token string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE digest_settings SET frequency = 'off', updated_at = CURRENT_TIMESTAMP WHERE unsubscribe_token = $1`,
+		token)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrDigestSettingsNotFound
+	}
+	return nil
+}
+
+// GetDueDigests возвращает пользователей, которым пора отправить дайджест
+func (r *subscriptionRepository) GetDueDigests(ctx context.Context, now time.Time) ([]entity.DigestSettings, error) {
+	query := `
+		SELECT user_id, email, frequency, unsubscribe_token, last_sent_at, sent_until, created_at
+		FROM digest_settings
+		WHERE frequency <> 'off' AND (
+			last_sent_at IS NULL
+			OR (frequency = 'daily' AND last_sent_at <= $1 - INTERVAL '1 day')
+			OR (frequency = 'weekly' AND last_sent_at <= $1 - INTERVAL '7 days')
+		)
+		ORDER BY user_id`
+
+	settings := []entity.DigestSettings{}
+	if err := r.db.SelectContext(ctx, &settings, query, now); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetDigestComments возвращает чужие комментарии к постам из подписок пользователя,
+// созданные после since и после оформления подписки, в порядке создания. Если выборка
+// упирается в MaxDigestComments, хвост с одинаковым created_at отбрасывается: следующий
+// дайджест начнется с created_at последнего комментария и иначе пропустил бы его соседей.
+func (r *subscriptionRepository) GetDigestComments(ctx context.Context, userID int64, since time.Time) ([]entity.DigestComment, error) {
+	query := `
+		SELECT c.id, c.post_id, p.title AS post_title, c.author_name, c.content, c.created_at
+		FROM subscriptions s
+		JOIN posts p ON p.id = s.post_id
+		JOIN comments c ON c.post_id = s.post_id
+		WHERE s.user_id = $1
+			AND c.author_id <> $1
+			AND c.created_at > GREATEST($2, s.created_at)
+			AND c.deleted_at IS NULL AND NOT c.held
+			AND p.deleted_at IS NULL AND NOT p.held
+		ORDER BY c.created_at, c.id
+		LIMIT $3`
+
+	comments := []entity.DigestComment{}
+	if err := r.db.SelectContext(ctx, &comments, query, userID, since, MaxDigestComments); err != nil {
+		return nil, err
+	}
+	if len(comments) == MaxDigestComments {
+		last := comments[len(comments)-1].CreatedAt
+		n := len(comments)
+		for n > 0 && comments[n-1].CreatedAt.Equal(last) {
+			n--
+		}
+		if n > 0 {
+			comments = comments[:n]
+		}
+	}
+	return comments, nil
+}
+
+// MarkDigestSent запоминает время отправки и границу sentUntil, после которой
+// начнется следующий дайджест
+func (r *subscriptionRepository) MarkDigestSent(ctx context.Context, userID int64, sentAt, sentUntil time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE digest_settings SET last_sent_at = $2, sent_until = $3 WHERE user_id = $1`, userID, sentAt, sentUntil)
+	return err
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribe(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSubscriptionRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`INSERT INTO subscriptions .* ON CONFLICT \(user_id, post_id\) DO NOTHING`).
+		WithArgs(int64(2), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, repo.Subscribe(context.Background(), 2, 3))
+
+	mock.ExpectExec(`DELETE FROM subscriptions`).
+		WithArgs(int64(2), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Unsubscribe(context.Background(), 2, 3))
+
+	mock.ExpectExec(`DELETE FROM subscriptions`).
+		WithArgs(int64(2), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Unsubscribe(context.Background(), 2, 4), ErrSubscriptionNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSubscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSubscriptionRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT s.id, s.user_id, s.post_id, p.title AS post_title(.|\n)*p.deleted_at IS NULL`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "post_id", "post_title", "created_at"}).
+			AddRow(1, 2, 3, "Title", now))
+
+	subs, err := repo.GetSubscriptions(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Subscription{{ID: 1, UserID: 2, PostID: 3, PostTitle: "Title", CreatedAt: now}}, subs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveDigestSettings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSubscriptionRepository(sqlx.NewDb(db, "sqlmock"))
+	now := time.Now()
+
+	// Существующий токен сохраняется при обновлении
+	mock.ExpectQuery(`INSERT INTO digest_settings(.|\n)*ON CONFLICT \(user_id\) DO UPDATE`).
+		WithArgs(int64(2), "a@example.com", entity.DigestWeekly, "new-token").
+		WillReturnRows(sqlmock.NewRows([]string{"unsubscribe_token", "last_sent_at", "created_at"}).
+			AddRow("old-token", nil, now))
+
+	settings := &entity.DigestSettings{UserID: 2, Email: "a@example.com", Frequency: entity.DigestWeekly, UnsubscribeToken: "new-token"}
+	assert.NoError(t, repo.SaveDigestSettings(context.Background(), settings))
+	assert.Equal(t, "old-token", settings.UnsubscribeToken)
+	assert.Nil(t, settings.LastSentAt)
+	assert.Equal(t, now, settings.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDigestSettings_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSubscriptionRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`FROM digest_settings`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	_, err = repo.GetDigestSettings(context.Background(), 2)
+	assert.ErrorIs(t, err, ErrDigestSettingsNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisableDigestByToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSubscriptionRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`UPDATE digest_settings SET frequency = 'off'`).
+		WithArgs("token").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DisableDigestByToken(context.Background(), "token"))
+
+	mock.ExpectExec(`UPDATE digest_settings SET frequency = 'off'`).
+		WithArgs("unknown").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DisableDigestByToken(context.Background(), "unknown"), ErrDigestSettingsNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDigestComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSubscriptionRepository(sqlx.NewDb(db, "sqlmock"))
+	since := time.Now().Add(-24 * time.Hour)
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM subscriptions s(.|\n)*c.author_id <> \$1(.|\n)*GREATEST\(\$2, s.created_at\)(.|\n)*LIMIT \$3`).
+		WithArgs(int64(2), since, MaxDigestComments).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "post_title", "author_name", "content", "created_at"}).
+			AddRow(10, 3, "Title", "bob", "Nice post", now))
+
+	comments, err := repo.GetDigestComments(context.Background(), 2, since)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.DigestComment{{ID: 10, PostID: 3, PostTitle: "Title", AuthorName: "bob", Content: "Nice post", CreatedAt: now}}, comments)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDigestComments_LimitDropsTiedTail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSubscriptionRepository(sqlx.NewDb(db, "sqlmock"))
+	since := time.Now().Add(-24 * time.Hour)
+	rows := sqlmock.NewRows([]string{"id", "post_id", "post_title", "author_name", "content", "created_at"})
+	for i := 0; i < MaxDigestComments; i++ {
+		// Два последних комментария созданы одновременно
+		at := since.Add(time.Duration(min(i, MaxDigestComments-2)+1) * time.Second)
+		rows.AddRow(i+1, 3, "Title", "bob", "text", at)
+	}
+	mock.ExpectQuery(`ORDER BY c.created_at, c.id\s+LIMIT \$3`).
+		WithArgs(int64(2), since, MaxDigestComments).
+		WillReturnRows(rows)
+
+	comments, err := repo.GetDigestComments(context.Background(), 2, since)
+	assert.NoError(t, err)
+	// Соседи последнего комментария попадут в следующий дайджест целиком
+	assert.Len(t, comments, MaxDigestComments-2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkDigestSent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewSubscriptionRepository(sqlx.NewDb(db, "sqlmock"))
+	sentAt := time.Now()
+	sentUntil := sentAt.Add(-time.Hour)
+
+	mock.ExpectExec(`UPDATE digest_settings SET last_sent_at = \$2, sent_until = \$3 WHERE user_id = \$1`).
+		WithArgs(int64(2), sentAt, sentUntil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkDigestSent(context.Background(), 2, sentAt, sentUntil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagConflict = errors.New("tag or synonym with this slug already exists")
+)
+
+type TagRepository interface {
+	SetPostTags(ctx context.Context, postID int64, slugs []string) ([]string, error)
+	GetTagsByPostIDs(ctx context.Context, postIDs []int64) (map[int64][]string, error)
+	SearchTags(ctx context.Context, prefix string, limit int) ([]entity.Tag, error)
+	GetTagBySlug(ctx context.Context, slug string) (*entity.Tag, error)
+	GetSynonyms(ctx context.Context, tagID int64) ([]string, error)
+	AddSynonym(ctx context.Context, tagID int64, synonym string) error
+	DeleteSynonym(ctx context.Context, synonym string) error
+	MergeTags(ctx context.Context, sourceID, targetID int64) error
+}
+
+type tagRepository struct {
+	db *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// SetPostTags заменяет теги поста. Синонимы заменяются основными тегами, новые теги создаются.
+// Возвращает итоговые slug в порядке slugs без повторов.
+func (r *tagRepository) SetPostTags(ctx context.Context, postID int64, slugs []string) ([]string, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE post_id = $1`, postID); err != nil {
+		return nil, err
+	}
+
+	result, err := insertPostTags(ctx, tx, postID, slugs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// insertPostTags привязывает теги к посту в транзакции tx и возвращает основные slug.
+// Синонимы заменяются основными тегами, новые теги создаются.
+func insertPostTags(ctx context.Context, tx *sqlx.Tx, postID int64, slugs []string) ([]string, error) {
+	seen := make(map[int64]bool, len(slugs))
+	result := make([]string, 0, len(slugs))
+	for _, slug := range slugs {
+		var tag entity.Tag
+		err := tx.GetContext(ctx, &tag,
+			`SELECT t.id, t.slug FROM tag_synonyms s JOIN tags t ON t.id = s.tag_id WHERE s.slug = $1`, slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = tx.GetContext(ctx, &tag, `
+				INSERT INTO tags (slug) VALUES ($1)
+				ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+				RETURNING id, slug`, slug)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[tag.ID] {
+			continue
+		}
+		seen[tag.ID] = true
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO post_tags (post_id, tag_id) VALUES ($1, $2)`, postID, tag.ID); err != nil {
+			return nil, err
+		}
+		result = append(result, tag.Slug)
+	}
+	return result, nil
+}
+
+func (r *tagRepository) GetTagsByPostIDs(ctx context.Context, postIDs []int64) (map[int64][]string, error) {
+	query := `
+		SELECT pt.post_id, t.slug
+		FROM post_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.post_id = ANY($1)
+		ORDER BY pt.post_id, t.slug`
+
+	var rows []struct {
+		PostID int64  `db:"post_id"`
+		Slug   string `db:"slug"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(postIDs)); err != nil {
+		return nil, err
+	}
+
+	tags := make(map[int64][]string)
+	for _, row := range rows {
+		tags[row.PostID] = append(tags[row.PostID], row.Slug)
+	}
+	return tags, nil
+}
+
+// tagPostCount считает только видимые посты: не попавшие в корзину и не отложенные фильтром
+const tagPostCount = `
+			(SELECT COUNT(*) FROM post_tags pt JOIN posts p ON p.id = pt.post_id
+			WHERE pt.tag_id = t.id AND p.deleted_at IS NULL AND NOT p.held) AS post_count`
+
+// SearchTags ищет теги по началу slug или синонима, популярные первыми.
+// Пустой prefix возвращает самые используемые теги.
+func (r *tagRepository) SearchTags(ctx context.Context, prefix string, limit int) ([]entity.Tag, error) {
+	query := `
+		SELECT t.id, t.slug,` + tagPostCount + `
+		FROM tags t
+		WHERE $1 = ''
+			OR t.slug LIKE $1 || '%'
+			OR EXISTS (SELECT 1 FROM tag_synonyms s WHERE s.tag_id = t.id AND s.slug LIKE $1 || '%')
+		ORDER BY post_count DESC, t.slug
+		LIMIT $2`
+
+	tags := []entity.Tag{}
+	if err := r.db.SelectContext(ctx, &tags, query, prefix, limit); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetTagBySlug находит тег по slug или по синониму
+func (r *tagRepository) GetTagBySlug(ctx context.Context, slug string) (*entity.Tag, error) {
+	query := `
+		SELECT t.id, t.slug,` + tagPostCount + `
+		FROM tags t
+		WHERE t.slug = $1 OR t.id = (SELECT tag_id FROM tag_synonyms WHERE slug = $1)`
+
+	var tag entity.Tag
+	if err := r.db.GetContext(ctx, &tag, query, slug); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) GetSynonyms(ctx context.Context, tagID int64) ([]string, error) {
+	synonyms := []string{}
+	err := r.db.SelectContext(ctx, &synonyms,
+		`SELECT slug FROM tag_synonyms WHERE tag_id = $1 ORDER BY slug`, tagID)
+	return synonyms, err
+}
+
+// AddSynonym делает synonym другим написанием тега. Slug, уже занятый тегом или синонимом,
+// не перезаписывается: существующий тег нужно объединить через MergeTags.
+func (r *tagRepository) AddSynonym(ctx context.Context, tagID int64, synonym string) error {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO tag_synonyms (slug, tag_id)
+		SELECT $1, $2
+		WHERE NOT EXISTS (SELECT 1 FROM tags WHERE slug = $1)
+		ON CONFLICT (slug) DO NOTHING`, synonym, tagID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTagConflict
+	}
+	return nil
+}
+
+func (r *tagRepository) DeleteSynonym(ctx context.Context, synonym string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tag_synonyms WHERE slug = $1`, synonym)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// MergeTags переносит посты и синонимы тега sourceID на targetID и удаляет исходный тег.
+// Его slug становится синонимом, чтобы старые ссылки и фильтры продолжали работать.
+func (r *tagRepository) MergeTags(ctx context.Context, sourceID, targetID int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`INSERT INTO post_tags (post_id, tag_id)
+		SELECT post_id, $2 FROM post_tags WHERE tag_id = $1
+		ON CONFLICT DO NOTHING`,
+		`UPDATE tag_synonyms SET tag_id = $2 WHERE tag_id = $1`,
+		`INSERT INTO tag_synonyms (slug, tag_id) SELECT slug, $2 FROM tags WHERE id = $1`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, sourceID, targetID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, sourceID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTagNotFound
+	}
+
+	return tx.Commit()
+}
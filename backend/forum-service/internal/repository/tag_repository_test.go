@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPostTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTagRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM post_tags WHERE post_id = \$1`).
+		WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// "golang" - синоним тега "go"
+	mock.ExpectQuery(`FROM tag_synonyms s JOIN tags t ON t.id = s.tag_id WHERE s.slug = \$1`).
+		WithArgs("golang").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}).AddRow(1, "go"))
+	mock.ExpectExec(`INSERT INTO post_tags \(post_id, tag_id\) VALUES \(\$1, \$2\)`).
+		WithArgs(int64(3), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// "go" указан повторно напрямую - второй раз не добавляется
+	mock.ExpectQuery(`FROM tag_synonyms s JOIN tags t`).
+		WithArgs("go").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}))
+	mock.ExpectQuery(`INSERT INTO tags \(slug\) VALUES \(\$1\)\s+ON CONFLICT \(slug\) DO UPDATE`).
+		WithArgs("go").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}).AddRow(1, "go"))
+
+	// новый тег создается
+	mock.ExpectQuery(`FROM tag_synonyms s JOIN tags t`).
+		WithArgs("sql").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}))
+	mock.ExpectQuery(`INSERT INTO tags \(slug\)`).
+		WithArgs("sql").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "slug"}).AddRow(7, "sql"))
+	mock.ExpectExec(`INSERT INTO post_tags`).
+		WithArgs(int64(3), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	got, err := repo.SetPostTags(context.Background(), 3, []string{"golang", "go", "sql"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "sql"}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTagsByPostIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTagRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`FROM post_tags pt\s+JOIN tags t ON t.id = pt.tag_id\s+WHERE pt.post_id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]int64{1, 2})).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "slug"}).
+			AddRow(1, "go").
+			AddRow(1, "sql").
+			AddRow(2, "go"))
+
+	got, err := repo.GetTagsByPostIDs(context.Background(), []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64][]string{1: {"go", "sql"}, 2: {"go"}}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTagRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`FROM tags t\s+WHERE \$1 = ''(.|\n)*tag_synonyms s(.|\n)*ORDER BY post_count DESC, t.slug\s+LIMIT \$2`).
+		WithArgs("go", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "slug", "post_count"}).
+			AddRow(1, "go", 12).
+			AddRow(4, "google", 1))
+
+	got, err := repo.SearchTags(context.Background(), "go", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "go", got[0].Slug)
+		assert.Equal(t, 12, got[0].PostCount)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTagBySlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTagRepository(sqlx.NewDb(db, "sqlmock"))
+
+	t.Run("By synonym", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE t.slug = \$1 OR t.id = \(SELECT tag_id FROM tag_synonyms WHERE slug = \$1\)`).
+			WithArgs("golang").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "slug", "post_count"}).AddRow(1, "go", 3))
+
+		tag, err := repo.GetTagBySlug(context.Background(), "golang")
+		assert.NoError(t, err)
+		assert.Equal(t, "go", tag.Slug)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mock.ExpectQuery(`FROM tags t`).
+			WithArgs("nope").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "slug", "post_count"}))
+
+		_, err := repo.GetTagBySlug(context.Background(), "nope")
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagSynonyms(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTagRepository(sqlx.NewDb(db, "sqlmock"))
+
+	t.Run("Add", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO tag_synonyms \(slug, tag_id\)(.|\n)*WHERE NOT EXISTS \(SELECT 1 FROM tags WHERE slug = \$1\)\s+ON CONFLICT \(slug\) DO NOTHING`).
+			WithArgs("golang", int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.AddSynonym(context.Background(), 1, "golang"))
+	})
+
+	t.Run("Add taken slug", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO tag_synonyms`).
+			WithArgs("sql", int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.AddSynonym(context.Background(), 1, "sql"), ErrTagConflict)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT slug FROM tag_synonyms WHERE tag_id = \$1 ORDER BY slug`).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("go-lang").AddRow("golang"))
+
+		got, err := repo.GetSynonyms(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"go-lang", "golang"}, got)
+	})
+
+	t.Run("Delete missing", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM tag_synonyms WHERE slug = \$1`).
+			WithArgs("golang").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.DeleteSynonym(context.Background(), "golang"), ErrTagNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMergeTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTagRepository(sqlx.NewDb(db, "sqlmock"))
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO post_tags \(post_id, tag_id\)\s+SELECT post_id, \$2 FROM post_tags WHERE tag_id = \$1\s+ON CONFLICT DO NOTHING`).
+			WithArgs(int64(2), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectExec(`UPDATE tag_synonyms SET tag_id = \$2 WHERE tag_id = \$1`).
+			WithArgs(int64(2), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO tag_synonyms \(slug, tag_id\) SELECT slug, \$2 FROM tags WHERE id = \$1`).
+			WithArgs(int64(2), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM tags WHERE id = \$1`).
+			WithArgs(int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.MergeTags(context.Background(), 2, 1))
+	})
+
+	t.Run("Source missing", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO post_tags`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`UPDATE tag_synonyms`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO tag_synonyms`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM tags`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.MergeTags(context.Background(), 2, 1), ErrTagNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrTrashItemNotFound = errors.New("item not found in trash")
+
+type TrashRepository interface {
+	GetTrash(ctx context.Context, authorID int64, all bool, since time.Time) ([]entity.TrashItem, error)
+	GetTrashItem(ctx context.Context, kind entity.TrashKind, id int64) (*entity.TrashItem, error)
+	Restore(ctx context.Context, kind entity.TrashKind, id int64) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+type trashRepository struct {
+	db *sqlx.DB
+}
+
+func NewTrashRepository(db *sqlx.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+// trashSelect приводит удаленные посты и комментарии к общему виду entity.TrashItem
+var trashSelect = map[entity.TrashKind]string{
+	entity.TrashKindPost: `
+		SELECT 'post' AS kind, id, id AS post_id, author_id, title, content,
+			deleted_at, COALESCE(deleted_by, 0) AS deleted_by, delete_reason
+		FROM posts
+		WHERE deleted_at IS NOT NULL`,
+	entity.TrashKindComment: `
+		SELECT 'comment' AS kind, id, post_id, author_id, '' AS title, content,
+			deleted_at, COALESCE(deleted_by, 0) AS deleted_by, delete_reason
+		FROM comments
+		WHERE deleted_at IS NOT NULL`,
+}
+
+var trashTables = map[entity.TrashKind]string{
+	entity.TrashKindPost:    "posts",
+	entity.TrashKindComment: "comments",
+}
+
+// GetTrash возвращает объекты, удаленные не раньше since.
+// При all = false выдаются только объекты автора authorID.
+func (r *trashRepository) GetTrash(ctx context.Context, authorID int64, all bool, since time.Time) ([]entity.TrashItem, error) {
+	query := `
+		SELECT * FROM (` + trashSelect[entity.TrashKindPost] + `
+		UNION ALL` + trashSelect[entity.TrashKindComment] + `
+		) t
+		WHERE t.deleted_at >= $1 AND ($2 OR t.author_id = $3)
+		ORDER BY t.deleted_at DESC`
+
+	items := []entity.TrashItem{}
+	if err := r.db.SelectContext(ctx, &items, query, since, all, authorID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *trashRepository) GetTrashItem(ctx context.Context, kind entity.TrashKind, id int64) (*entity.TrashItem, error) {
+	base, ok := trashSelect[kind]
+	if !ok {
+		return nil, ErrTrashItemNotFound
+	}
+
+	var item entity.TrashItem
+	err := r.db.GetContext(ctx, &item, base+` AND id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTrashItemNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Restore возвращает объект из корзины. Проверка прав и срока хранения выполняется на уровне usecase.
+func (r *trashRepository) Restore(ctx context.Context, kind entity.TrashKind, id int64) error {
+	table, ok := trashTables[kind]
+	if !ok {
+		return ErrTrashItemNotFound
+	}
+
+	query := `
+		UPDATE ` + table + `
+		SET deleted_at = NULL, deleted_by = NULL, delete_reason = ''
+		WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrTrashItemNotFound
+	}
+	return nil
+}
+
+// Purge окончательно удаляет объекты, попавшие в корзину раньше before.
+// Вместе с постом каскадно удаляются его комментарии и история правок.
+func (r *trashRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var purged int64
+	for _, query := range []string{
+		`DELETE FROM comments WHERE deleted_at < $1`,
+		`DELETE FROM posts WHERE deleted_at < $1`,
+	} {
+		result, err := tx.ExecContext(ctx, query, before)
+		if err != nil {
+			return 0, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		purged += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var trashColumns = []string{"kind", "id", "post_id", "author_id", "title", "content", "deleted_at", "deleted_by", "delete_reason"}
+
+func TestGetTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTrashRepository(sqlx.NewDb(db, "sqlmock"))
+	since := time.Now().Add(-time.Hour)
+	deletedAt := time.Now()
+
+	mock.ExpectQuery(`FROM posts\s+WHERE deleted_at IS NOT NULL\s+UNION ALL(.|\n)*FROM comments`).
+		WithArgs(since, false, int64(1)).
+		WillReturnRows(sqlmock.NewRows(trashColumns).
+			AddRow("post", 1, 1, 1, "Title", "Content", deletedAt, 1, "").
+			AddRow("comment", 5, 2, 1, "", "Comment", deletedAt, 3, "spam"))
+
+	items, err := repo.GetTrash(context.Background(), 1, false, since)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.TrashItem{
+		{Kind: entity.TrashKindPost, ID: 1, PostID: 1, AuthorID: 1, Title: "Title", Content: "Content", DeletedAt: deletedAt, DeletedBy: 1},
+		{Kind: entity.TrashKindComment, ID: 5, PostID: 2, AuthorID: 1, Content: "Comment", DeletedAt: deletedAt, DeletedBy: 3, Reason: "spam"},
+	}, items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTrashItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTrashRepository(sqlx.NewDb(db, "sqlmock"))
+
+	t.Run("Found", func(t *testing.T) {
+		mock.ExpectQuery(`FROM comments\s+WHERE deleted_at IS NOT NULL AND id = \$1`).
+			WithArgs(int64(5)).
+			WillReturnRows(sqlmock.NewRows(trashColumns).
+				AddRow("comment", 5, 2, 1, "", "Comment", time.Now(), 1, ""))
+
+		item, err := repo.GetTrashItem(context.Background(), entity.TrashKindComment, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), item.PostID)
+	})
+
+	t.Run("Not in trash", func(t *testing.T) {
+		mock.ExpectQuery(`FROM posts\s+WHERE deleted_at IS NOT NULL AND id = \$1`).
+			WithArgs(int64(7)).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetTrashItem(context.Background(), entity.TrashKindPost, 7)
+		assert.ErrorIs(t, err, ErrTrashItemNotFound)
+	})
+
+	t.Run("Unknown kind", func(t *testing.T) {
+		_, err := repo.GetTrashItem(context.Background(), entity.TrashKind("topic"), 1)
+		assert.ErrorIs(t, err, ErrTrashItemNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTrashRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`UPDATE posts\s+SET deleted_at = NULL`).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Restore(context.Background(), entity.TrashKindPost, 1))
+
+	mock.ExpectExec(`UPDATE comments\s+SET deleted_at = NULL`).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Restore(context.Background(), entity.TrashKindComment, 2), ErrTrashItemNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewTrashRepository(sqlx.NewDb(db, "sqlmock"))
+	before := time.Now().Add(-24 * time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM comments WHERE deleted_at < \$1`).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`DELETE FROM posts WHERE deleted_at < \$1`).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	purged, err := repo.Purge(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
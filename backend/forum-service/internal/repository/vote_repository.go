@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// hotScoreExpr строит формулу "горячести": логарифм активности
+// (голоса плюс комментарии) и линейный вклад времени создания,
+// так что более новые посты со временем вытесняют старые.
+func hotScoreExpr(activity, createdAt string) string {
+	return fmt.Sprintf(
+		"SIGN(%[1]s) * LOG(GREATEST(ABS(%[1]s), 1)) + EXTRACT(EPOCH FROM %[2]s) / 45000",
+		activity, createdAt,
+	)
+}
+
+type VoteRepository interface {
+	SetVote(ctx context.Context, postID, userID int64, value int) (*entity.PostScore, error)
+	RefreshScores(ctx context.Context) (int64, error)
+}
+
+type voteRepository struct {
+	db *sqlx.DB
+}
+
+func NewVoteRepository(db *sqlx.DB) VoteRepository {
+	return &voteRepository{db: db}
+}
+
+// SetVote сохраняет голос пользователя (value = 0 снимает голос) и в той же
+// транзакции пересчитывает счетчики и рейтинг поста.
+func (r *voteRepository) SetVote(ctx context.Context, postID, userID int64, value int) (*entity.PostScore, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM posts WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, postID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+
+	if value == 0 {
+		_, err = tx.ExecContext(ctx,
+			`DELETE FROM post_votes WHERE post_id = $1 AND user_id = $2`,
+			postID, userID)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO post_votes (post_id, user_id, value)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (post_id, user_id) DO UPDATE SET value = EXCLUDED.value`,
+			postID, userID, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE posts p
+		SET upvotes = v.up,
+			downvotes = v.down,
+			score = v.up - v.down,
+			hot_score = ` + hotScoreExpr("v.up - v.down + p.comment_count", "p.created_at") + `
+		FROM (
+			SELECT
+				COUNT(*) FILTER (WHERE value = 1) AS up,
+				COUNT(*) FILTER (WHERE value = -1) AS down
+			FROM post_votes
+			WHERE post_id = $1
+		) v
+		WHERE p.id = $1
+		RETURNING p.id, p.upvotes, p.downvotes, p.comment_count, p.score`
+
+	var score entity.PostScore
+	if err := tx.GetContext(ctx, &score, query, postID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	score.UserVote = value
+	return &score, nil
+}
+
+// RefreshScores сверяет денормализованные счетчики всех постов с таблицами
+// голосов и комментариев и пересчитывает рейтинг. Возвращает число обновленных постов.
+func (r *voteRepository) RefreshScores(ctx context.Context) (int64, error) {
+	query := `
+		WITH v AS (
+			SELECT
+				post_id,
+				COUNT(*) FILTER (WHERE value = 1) AS up,
+				COUNT(*) FILTER (WHERE value = -1) AS down
+			FROM post_votes
+			GROUP BY post_id
+		), c AS (
+			SELECT post_id, COUNT(*) AS cnt
+			FROM comments
+			WHERE deleted_at IS NULL
+			GROUP BY post_id
+		), s AS (
+			SELECT
+				p.id,
+				COALESCE(v.up, 0) AS up,
+				COALESCE(v.down, 0) AS down,
+				COALESCE(c.cnt, 0) AS cnt
+			FROM posts p
+			LEFT JOIN v ON v.post_id = p.id
+			LEFT JOIN c ON c.post_id = p.id
+		)
+		UPDATE posts p
+		SET upvotes = s.up,
+			downvotes = s.down,
+			comment_count = s.cnt,
+			score = s.up - s.down,
+			hot_score = ` + hotScoreExpr("s.up - s.down + s.cnt", "p.created_at") + `
+		FROM s
+		WHERE p.id = s.id`
+
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetVote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewVoteRepository(sqlx.NewDb(db, "sqlmock"))
+	scoreColumns := []string{"id", "upvotes", "downvotes", "comment_count", "score"}
+
+	tests := []struct {
+		name    string
+		value   int
+		mock    func()
+		want    *entity.PostScore
+		wantErr error
+	}{
+		{
+			name:  "Upvote",
+			value: 1,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM posts WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(`INSERT INTO post_votes`).
+					WithArgs(int64(1), int64(2), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`UPDATE posts p`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(scoreColumns).AddRow(1, 3, 1, 4, 2))
+				mock.ExpectCommit()
+			},
+			want: &entity.PostScore{PostID: 1, Upvotes: 3, Downvotes: 1, CommentCount: 4, Score: 2, UserVote: 1},
+		},
+		{
+			name:  "Remove vote",
+			value: 0,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM posts WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(`DELETE FROM post_votes`).
+					WithArgs(int64(1), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`UPDATE posts p`).
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(scoreColumns).AddRow(1, 2, 1, 4, 1))
+				mock.ExpectCommit()
+			},
+			want: &entity.PostScore{PostID: 1, Upvotes: 2, Downvotes: 1, CommentCount: 4, Score: 1},
+		},
+		{
+			name:  "Post not found",
+			value: -1,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM posts WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).
+					WithArgs(int64(1)).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := repo.SetVote(context.Background(), 1, 2, tt.value)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshScores(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewVoteRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`UPDATE posts p`).WillReturnResult(sqlmock.NewResult(0, 7))
+
+	updated, err := repo.RefreshScores(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,360 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/blobstore"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/media"
+)
+
+const (
+	// DefaultMaxAttachmentSize - предельный размер загружаемого файла
+	DefaultMaxAttachmentSize = 10 << 20
+	// ThumbnailSize - большая сторона миниатюры в пикселях
+	ThumbnailSize = 320
+)
+
+// AttachmentLinker проверяет и привязывает загруженные файлы к постам и комментариям
+type AttachmentLinker interface {
+	// CheckAttachments проверяет до публикации, что все вложения принадлежат автору и свободны
+	CheckAttachments(ctx context.Context, ownerID int64, ids []int64) error
+	LinkAttachments(ctx context.Context, ownerID int64, target entity.AttachmentTarget, targetID int64, ids []int64) ([]entity.Attachment, error)
+	AttachmentsFor(ctx context.Context, target entity.AttachmentTarget, targetIDs []int64) (map[int64][]entity.Attachment, error)
+}
+
+// ChatRooms проверяет членство в комнатах чата
+type ChatRooms interface {
+	IsRoomMember(ctx context.Context, roomID, userID int64) (bool, error)
+}
+
+type AttachmentUsecaseInterface interface {
+	Upload(ctx context.Context, token, filename string, r io.Reader) (*entity.Attachment, error)
+	// Open возвращает содержимое файла или его миниатюры; вызывающий закрывает reader.
+	// token может быть пустым: без него доступны только файлы опубликованных постов и комментариев.
+	Open(ctx context.Context, token string, id int64, thumbnail bool) (*entity.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, token string, id int64) error
+	// ClaimForChat занимает вложения владельца для сообщения в комнате roomID и возвращает их ID без повторов
+	ClaimForChat(ctx context.Context, ownerID, roomID int64, ids []int64) ([]int64, error)
+}
+
+type AttachmentUsecase struct {
+	attachmentRepo repository.AttachmentRepository
+	store          blobstore.BlobStore
+	authClient     pb.AuthServiceClient
+	logger         *logger.Logger
+	// MaxSize ограничивает размер загружаемого файла в байтах
+	MaxSize int64
+	// Rooms - комнаты чата; nil - вложения сообщений чата доступны только владельцу и модераторам
+	Rooms ChatRooms
+}
+
+func NewAttachmentUsecase(
+	attachmentRepo repository.AttachmentRepository,
+	store blobstore.BlobStore,
+	authClient pb.AuthServiceClient,
+	logger *logger.Logger,
+) *AttachmentUsecase {
+	return &AttachmentUsecase{
+		attachmentRepo: attachmentRepo,
+		store:          store,
+		authClient:     authClient,
+		logger:         logger,
+		MaxSize:        DefaultMaxAttachmentSize,
+	}
+}
+
+// Upload сохраняет файл. Тип определяется по содержимому, а не по имени или заголовку клиента.
+// Изображения перекодируются без метаданных (EXIF, геотеги), для них строится миниатюра.
+func (uc *AttachmentUsecase) Upload(ctx context.Context, token, filename string, r io.Reader) (*entity.Attachment, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, uc.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > uc.MaxSize {
+		return nil, entity.ErrAttachmentTooLarge
+	}
+
+	contentType := media.DetectContentType(data)
+	if !entity.AllowedAttachmentTypes[contentType] {
+		return nil, entity.ErrUnsupportedMediaType
+	}
+
+	key, err := newStorageKey(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	attachment := &entity.Attachment{
+		OwnerID:     session.UserId,
+		Filename:    entity.CleanAttachmentFilename(filename),
+		ContentType: contentType,
+		StorageKey:  key,
+	}
+
+	var thumbnail []byte
+	var thumbnailType string
+	if media.IsImage(contentType) {
+		img, err := media.Sanitize(data, contentType)
+		if err != nil {
+			return nil, entity.ErrUnsupportedMediaType
+		}
+		data = img.Data
+		attachment.Width, attachment.Height = img.Width, img.Height
+
+		if thumbnail, thumbnailType, err = img.Thumbnail(ThumbnailSize, contentType); err != nil {
+			return nil, err
+		}
+		attachment.ThumbnailKey = attachment.StorageKey + "_thumb"
+	}
+	attachment.Size = int64(len(data))
+
+	if err := uc.store.Put(ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, err
+	}
+	if thumbnail != nil {
+		if err := uc.store.Put(ctx, attachment.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), thumbnailType); err != nil {
+			uc.removeBlobs(ctx, attachment)
+			return nil, err
+		}
+	}
+
+	if err := uc.attachmentRepo.CreateAttachment(ctx, attachment); err != nil {
+		uc.removeBlobs(ctx, attachment)
+		return nil, err
+	}
+
+	attachment.SetURLs()
+	return attachment, nil
+}
+
+func (uc *AttachmentUsecase) GetAttachment(ctx context.Context, id int64) (*entity.Attachment, error) {
+	attachment, err := uc.attachmentRepo.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	attachment.SetURLs()
+	return attachment, nil
+}
+
+func (uc *AttachmentUsecase) Open(ctx context.Context, token string, id int64, thumbnail bool) (*entity.Attachment, io.ReadCloser, error) {
+	attachment, err := uc.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := uc.checkReadAccess(ctx, token, attachment); err != nil {
+		return nil, nil, err
+	}
+
+	key := attachment.StorageKey
+	if thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, nil, repository.ErrAttachmentNotFound
+		}
+		key = attachment.ThumbnailKey
+	}
+
+	content, err := uc.store.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// checkReadAccess разрешает читать файл опубликованного поста или комментария всем, а остальные
+// файлы - владельцу, модераторам и участникам комнаты чата, куда файл отправлен. Для прочих
+// файл не существует, чтобы по последовательным ID нельзя было узнать о чужих вложениях.
+func (uc *AttachmentUsecase) checkReadAccess(ctx context.Context, token string, a *entity.Attachment) error {
+	published, err := uc.attachmentRepo.IsPublished(ctx, a.ID)
+	if err != nil || published {
+		return err
+	}
+	if token == "" {
+		return repository.ErrAttachmentNotFound
+	}
+
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return err
+	}
+	if a.OwnerID == session.UserId || isModerator(session.UserRole) {
+		return nil
+	}
+	if a.ChatRoomID != nil && uc.Rooms != nil {
+		member, err := uc.Rooms.IsRoomMember(ctx, *a.ChatRoomID, session.UserId)
+		if err != nil {
+			return err
+		}
+		if member {
+			return nil
+		}
+	}
+	return repository.ErrAttachmentNotFound
+}
+
+// DeleteAttachment удаляет файл. Удалить может владелец, а также модератор.
+func (uc *AttachmentUsecase) DeleteAttachment(ctx context.Context, token string, id int64) error {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := uc.attachmentRepo.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if attachment.OwnerID != session.UserId && !isModerator(session.UserRole) {
+		return ErrForbidden
+	}
+
+	if err := uc.attachmentRepo.DeleteAttachment(ctx, id); err != nil {
+		return err
+	}
+	uc.removeBlobs(ctx, attachment)
+	return nil
+}
+
+// CheckAttachments реализует AttachmentLinker
+func (uc *AttachmentUsecase) CheckAttachments(ctx context.Context, ownerID int64, ids []int64) error {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return nil
+	}
+	if len(ids) > entity.MaxAttachmentsPerTarget {
+		return entity.ErrTooManyAttachments
+	}
+
+	count, err := uc.attachmentRepo.CountUnattached(ctx, ownerID, ids)
+	if err != nil {
+		return err
+	}
+	if count != len(ids) {
+		return entity.ErrInvalidAttachment
+	}
+	return nil
+}
+
+// ClaimForChat проверяет, что вложения принадлежат автору сообщения и свободны, и отмечает их
+// использованными, чтобы их нельзя было повторно приложить к посту, комментарию или сообщению.
+// Комната roomID запоминается: файлы сообщения видят только ее участники.
+func (uc *AttachmentUsecase) ClaimForChat(ctx context.Context, ownerID, roomID int64, ids []int64) ([]int64, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	if len(ids) > entity.MaxAttachmentsPerTarget {
+		return nil, entity.ErrTooManyAttachments
+	}
+
+	claimed, err := uc.attachmentRepo.ClaimForChat(ctx, ownerID, roomID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, entity.ErrInvalidAttachment
+	}
+	return ids, nil
+}
+
+// LinkAttachments реализует AttachmentLinker. Вложения, занятые после CheckAttachments
+// параллельным запросом, пропускаются.
+func (uc *AttachmentUsecase) LinkAttachments(
+	ctx context.Context,
+	ownerID int64,
+	target entity.AttachmentTarget,
+	targetID int64,
+	ids []int64,
+) ([]entity.Attachment, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if _, err := uc.attachmentRepo.LinkAttachments(ctx, ownerID, target, targetID, ids); err != nil {
+		return nil, err
+	}
+	attachments, err := uc.AttachmentsFor(ctx, target, []int64{targetID})
+	if err != nil {
+		return nil, err
+	}
+	return attachments[targetID], nil
+}
+
+// AttachmentsFor реализует AttachmentLinker
+func (uc *AttachmentUsecase) AttachmentsFor(ctx context.Context, target entity.AttachmentTarget, targetIDs []int64) (map[int64][]entity.Attachment, error) {
+	result := make(map[int64][]entity.Attachment)
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+
+	attachments, err := uc.attachmentRepo.GetAttachmentsByTargets(ctx, target, targetIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attachments {
+		a.SetURLs()
+		id := a.PostID
+		if target == entity.AttachmentTargetComment {
+			id = a.CommentID
+		}
+		if id != nil {
+			result[*id] = append(result[*id], a)
+		}
+	}
+	return result, nil
+}
+
+// removeBlobs удаляет содержимое вложения из хранилища. Ошибка только логируется:
+// запись уже удалена или не создана, и объект останется недостижимым.
+func (uc *AttachmentUsecase) removeBlobs(ctx context.Context, a *entity.Attachment) {
+	for _, key := range []string{a.StorageKey, a.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := uc.store.Delete(ctx, key); err != nil {
+			uc.logError("Failed to delete attachment blob", err)
+		}
+	}
+}
+
+func (uc *AttachmentUsecase) logError(msg string, err error) {
+	if uc.logger != nil {
+		uc.logger.Error(msg, err)
+	}
+}
+
+// newStorageKey возвращает случайный ключ, сгруппированный по месяцу загрузки.
+// Имя файла в ключ не попадает, чтобы пользователь не мог повлиять на путь в хранилище.
+func newStorageKey(now time.Time) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "attachments/" + now.UTC().Format("2006/01") + "/" + hex.EncodeToString(b), nil
+}
+
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
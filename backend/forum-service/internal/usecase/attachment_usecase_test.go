@@ -0,0 +1,477 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/blobstore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type MockAttachmentRepository struct {
+	CreateAttachmentFunc        func(ctx context.Context, a *entity.Attachment) error
+	GetAttachmentByIDFunc       func(ctx context.Context, id int64) (*entity.Attachment, error)
+	CountUnattachedFunc         func(ctx context.Context, ownerID int64, ids []int64) (int, error)
+	LinkAttachmentsFunc         func(ctx context.Context, ownerID int64, target entity.AttachmentTarget, targetID int64, ids []int64) (int64, error)
+	GetAttachmentsByTargetsFunc func(ctx context.Context, target entity.AttachmentTarget, targetIDs []int64) ([]entity.Attachment, error)
+	DeleteAttachmentFunc        func(ctx context.Context, id int64) error
+	ClaimForChatFunc            func(ctx context.Context, ownerID, roomID int64, ids []int64) (bool, error)
+	IsPublishedFunc             func(ctx context.Context, id int64) (bool, error)
+}
+
+func (m *MockAttachmentRepository) CreateAttachment(ctx context.Context, a *entity.Attachment) error {
+	return m.CreateAttachmentFunc(ctx, a)
+}
+
+func (m *MockAttachmentRepository) GetAttachmentByID(ctx context.Context, id int64) (*entity.Attachment, error) {
+	return m.GetAttachmentByIDFunc(ctx, id)
+}
+
+func (m *MockAttachmentRepository) CountUnattached(ctx context.Context, ownerID int64, ids []int64) (int, error) {
+	return m.CountUnattachedFunc(ctx, ownerID, ids)
+}
+
+func (m *MockAttachmentRepository) LinkAttachments(ctx context.Context, ownerID int64, target entity.AttachmentTarget, targetID int64, ids []int64) (int64, error) {
+	return m.LinkAttachmentsFunc(ctx, ownerID, target, targetID, ids)
+}
+
+func (m *MockAttachmentRepository) GetAttachmentsByTargets(ctx context.Context, target entity.AttachmentTarget, targetIDs []int64) ([]entity.Attachment, error) {
+	return m.GetAttachmentsByTargetsFunc(ctx, target, targetIDs)
+}
+
+func (m *MockAttachmentRepository) DeleteAttachment(ctx context.Context, id int64) error {
+	return m.DeleteAttachmentFunc(ctx, id)
+}
+
+func (m *MockAttachmentRepository) ClaimForChat(ctx context.Context, ownerID, roomID int64, ids []int64) (bool, error) {
+	return m.ClaimForChatFunc(ctx, ownerID, roomID, ids)
+}
+
+func (m *MockAttachmentRepository) IsPublished(ctx context.Context, id int64) (bool, error) {
+	return m.IsPublishedFunc(ctx, id)
+}
+
+// MockChatRooms - участники комнат чата по ID комнаты
+type MockChatRooms map[int64][]int64
+
+func (m MockChatRooms) IsRoomMember(ctx context.Context, roomID, userID int64) (bool, error) {
+	for _, id := range m[roomID] {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memoryStore - BlobStore в памяти
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	putErr  error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{objects: map[string][]byte{}}
+}
+
+func (s *memoryStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if s.putErr != nil {
+		return s.putErr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = data
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, blobstore.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+func testJPEG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	// Комментарий JPEG - такие же метаданные, как EXIF, и должен пропасть
+	data := buf.Bytes()
+	comment := []byte{0xFF, 0xFE, 0x00, 0x0C}
+	comment = append(comment, "secret-gps"...)
+	return append(append([]byte{0xFF, 0xD8}, comment...), data[2:]...)
+}
+
+func TestAttachmentUsecase_Upload(t *testing.T) {
+	tests := []struct {
+		name       string
+		filename   string
+		data       func(t *testing.T) []byte
+		wantErr    error
+		wantType   string
+		wantThumb  bool
+		wantName   string
+		wantPixels [2]int
+	}{
+		{
+			name:       "Photo",
+			filename:   "photo.jpg",
+			data:       func(t *testing.T) []byte { return testJPEG(t, 800, 400) },
+			wantType:   "image/jpeg",
+			wantThumb:  true,
+			wantName:   "photo.jpg",
+			wantPixels: [2]int{800, 400},
+		},
+		{
+			name:     "Text with misleading name",
+			filename: "../notes.png",
+			data:     func(t *testing.T) []byte { return []byte("plain notes") },
+			wantType: "text/plain",
+			wantName: "notes.png",
+		},
+		{
+			name:     "HTML",
+			filename: "page.txt",
+			data:     func(t *testing.T) []byte { return []byte("<html><script>alert(1)</script></html>") },
+			wantErr:  entity.ErrUnsupportedMediaType,
+		},
+		{
+			name:     "Too large",
+			filename: "big.txt",
+			data:     func(t *testing.T) []byte { return bytes.Repeat([]byte("a"), 101) },
+			wantErr:  entity.ErrAttachmentTooLarge,
+		},
+		{
+			name:     "Corrupted image",
+			filename: "broken.jpg",
+			data:     func(t *testing.T) []byte { return testJPEG(t, 10, 10)[:60] },
+			wantErr:  entity.ErrUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *entity.Attachment
+			repo := &MockAttachmentRepository{
+				CreateAttachmentFunc: func(ctx context.Context, a *entity.Attachment) error {
+					saved = a
+					a.ID = 5
+					return nil
+				},
+			}
+			store := newMemoryStore()
+			uc := NewAttachmentUsecase(repo, store, sessionAuth(7, "user"), nil)
+			data := tt.data(t)
+			if tt.name == "Too large" {
+				uc.MaxSize = 100
+			}
+
+			a, err := uc.Upload(context.Background(), "token", tt.filename, bytes.NewReader(data))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				assert.Empty(t, store.objects)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), a.OwnerID)
+			assert.Equal(t, tt.wantType, a.ContentType)
+			assert.Equal(t, tt.wantName, a.Filename)
+			assert.Equal(t, "/api/v1/attachments/5", a.URL)
+			assert.True(t, strings.HasPrefix(a.StorageKey, "attachments/"))
+			assert.Equal(t, tt.wantPixels, [2]int{a.Width, a.Height})
+
+			stored := store.objects[a.StorageKey]
+			assert.Equal(t, a.Size, int64(len(stored)))
+			assert.False(t, bytes.Contains(stored, []byte("secret-gps")))
+			if tt.wantThumb {
+				thumb, err := jpeg.DecodeConfig(bytes.NewReader(store.objects[a.ThumbnailKey]))
+				require.NoError(t, err)
+				assert.Equal(t, ThumbnailSize, thumb.Width)
+				assert.NotEmpty(t, a.ThumbnailURL)
+			} else {
+				assert.Empty(t, a.ThumbnailKey)
+				assert.Len(t, store.objects, 1)
+			}
+		})
+	}
+}
+
+func TestAttachmentUsecase_Upload_RepositoryError(t *testing.T) {
+	repo := &MockAttachmentRepository{
+		CreateAttachmentFunc: func(ctx context.Context, a *entity.Attachment) error {
+			return assert.AnError
+		},
+	}
+	store := newMemoryStore()
+	uc := NewAttachmentUsecase(repo, store, sessionAuth(7, "user"), nil)
+
+	_, err := uc.Upload(context.Background(), "token", "photo.jpg", bytes.NewReader(testJPEG(t, 50, 50)))
+	assert.ErrorIs(t, err, assert.AnError)
+	// Файлы без записи в базе удаляются
+	assert.Empty(t, store.objects)
+}
+
+func TestAttachmentUsecase_OpenAndDelete(t *testing.T) {
+	attachment := &entity.Attachment{ID: 5, OwnerID: 7, ContentType: "application/pdf", StorageKey: "attachments/k"}
+	var deleted bool
+	repo := &MockAttachmentRepository{
+		GetAttachmentByIDFunc: func(ctx context.Context, id int64) (*entity.Attachment, error) {
+			if id != 5 {
+				return nil, repository.ErrAttachmentNotFound
+			}
+			a := *attachment
+			return &a, nil
+		},
+		DeleteAttachmentFunc: func(ctx context.Context, id int64) error {
+			deleted = true
+			return nil
+		},
+		IsPublishedFunc: func(ctx context.Context, id int64) (bool, error) {
+			return true, nil
+		},
+	}
+	store := newMemoryStore()
+	store.objects["attachments/k"] = []byte("%PDF-1.7")
+
+	uc := NewAttachmentUsecase(repo, store, sessionAuth(8, "user"), nil)
+	a, content, err := uc.Open(context.Background(), "", 5, false)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	content.Close()
+	assert.Equal(t, "%PDF-1.7", string(data))
+	assert.Equal(t, "/api/v1/attachments/5", a.URL)
+
+	// У PDF нет миниатюры
+	_, _, err = uc.Open(context.Background(), "", 5, true)
+	assert.ErrorIs(t, err, repository.ErrAttachmentNotFound)
+
+	assert.ErrorIs(t, uc.DeleteAttachment(context.Background(), "token", 5), ErrForbidden)
+	assert.False(t, deleted)
+
+	uc = NewAttachmentUsecase(repo, store, sessionAuth(8, "moderator"), nil)
+	assert.NoError(t, uc.DeleteAttachment(context.Background(), "token", 5))
+	assert.True(t, deleted)
+	assert.Empty(t, store.objects)
+}
+
+func TestAttachmentUsecase_OpenAccess(t *testing.T) {
+	roomID := int64(3)
+	attachments := map[int64]*entity.Attachment{
+		// Файл опубликованного поста
+		1: {ID: 1, OwnerID: 7, StorageKey: "attachments/1"},
+		// Файл удаленного или отложенного поста
+		2: {ID: 2, OwnerID: 7, StorageKey: "attachments/2"},
+		// Файл сообщения в приватной комнате
+		3: {ID: 3, OwnerID: 7, ChatRoomID: &roomID, StorageKey: "attachments/3"},
+	}
+	repo := &MockAttachmentRepository{
+		GetAttachmentByIDFunc: func(ctx context.Context, id int64) (*entity.Attachment, error) {
+			a := *attachments[id]
+			return &a, nil
+		},
+		IsPublishedFunc: func(ctx context.Context, id int64) (bool, error) {
+			return id == 1, nil
+		},
+	}
+	store := newMemoryStore()
+	for _, a := range attachments {
+		store.objects[a.StorageKey] = []byte("data")
+	}
+	rooms := MockChatRooms{roomID: {7, 8}}
+
+	tests := []struct {
+		name    string
+		token   string
+		auth    *MockAuthServiceClient
+		id      int64
+		wantErr error
+	}{
+		{name: "Published file without token", id: 1},
+		{name: "Hidden post file without token", id: 2, wantErr: repository.ErrAttachmentNotFound},
+		{name: "Hidden post file of another user", token: "token", auth: sessionAuth(9, "user"), id: 2, wantErr: repository.ErrAttachmentNotFound},
+		{name: "Owner", token: "token", auth: sessionAuth(7, "user"), id: 2},
+		{name: "Moderator", token: "token", auth: sessionAuth(9, "moderator"), id: 2},
+		{name: "Room member", token: "token", auth: sessionAuth(8, "user"), id: 3},
+		{name: "Not a room member", token: "token", auth: sessionAuth(9, "user"), id: 3, wantErr: repository.ErrAttachmentNotFound},
+		{name: "Room file without token", id: 3, wantErr: repository.ErrAttachmentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewAttachmentUsecase(repo, store, tt.auth, nil)
+			uc.Rooms = rooms
+
+			_, content, err := uc.Open(context.Background(), tt.token, tt.id, false)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			content.Close()
+		})
+	}
+}
+
+func TestAttachmentUsecase_CheckAttachments(t *testing.T) {
+	repo := &MockAttachmentRepository{
+		CountUnattachedFunc: func(ctx context.Context, ownerID int64, ids []int64) (int, error) {
+			count := 0
+			for _, id := range ids {
+				if id < 100 {
+					count++
+				}
+			}
+			return count, nil
+		},
+	}
+	uc := NewAttachmentUsecase(repo, nil, nil, nil)
+
+	assert.NoError(t, uc.CheckAttachments(context.Background(), 7, nil))
+	assert.NoError(t, uc.CheckAttachments(context.Background(), 7, []int64{1, 2, 2}))
+	assert.ErrorIs(t, uc.CheckAttachments(context.Background(), 7, []int64{1, 100}), entity.ErrInvalidAttachment)
+	assert.ErrorIs(t, uc.CheckAttachments(context.Background(), 7, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}), entity.ErrTooManyAttachments)
+}
+
+func TestAttachmentUsecase_ClaimForChat(t *testing.T) {
+	repo := &MockAttachmentRepository{
+		ClaimForChatFunc: func(ctx context.Context, ownerID, roomID int64, ids []int64) (bool, error) {
+			assert.Equal(t, int64(7), ownerID)
+			assert.Equal(t, int64(3), roomID)
+			for _, id := range ids {
+				if id >= 100 {
+					return false, nil
+				}
+			}
+			return true, nil
+		},
+	}
+	uc := NewAttachmentUsecase(repo, nil, nil, nil)
+
+	ids, err := uc.ClaimForChat(context.Background(), 7, 3, []int64{1, 2, 2, -1})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids)
+
+	ids, err = uc.ClaimForChat(context.Background(), 7, 3, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	_, err = uc.ClaimForChat(context.Background(), 7, 3, []int64{1, 100})
+	assert.ErrorIs(t, err, entity.ErrInvalidAttachment)
+	_, err = uc.ClaimForChat(context.Background(), 7, 3, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11})
+	assert.ErrorIs(t, err, entity.ErrTooManyAttachments)
+}
+
+func TestPostAndCommentAttachments(t *testing.T) {
+	postID, commentID := int64(3), int64(10)
+	var linked []entity.AttachmentTarget
+	attachmentRepo := &MockAttachmentRepository{
+		CountUnattachedFunc: func(ctx context.Context, ownerID int64, ids []int64) (int, error) {
+			return len(ids), nil
+		},
+		LinkAttachmentsFunc: func(ctx context.Context, ownerID int64, target entity.AttachmentTarget, targetID int64, ids []int64) (int64, error) {
+			assert.Equal(t, int64(7), ownerID)
+			assert.Equal(t, []int64{5}, ids)
+			linked = append(linked, target)
+			return 1, nil
+		},
+		GetAttachmentsByTargetsFunc: func(ctx context.Context, target entity.AttachmentTarget, targetIDs []int64) ([]entity.Attachment, error) {
+			if target == entity.AttachmentTargetComment {
+				return []entity.Attachment{{ID: 5, CommentID: &commentID}}, nil
+			}
+			return []entity.Attachment{{ID: 5, PostID: &postID}}, nil
+		},
+	}
+	auth := sessionAuth(7, "user")
+	auth.GetUserProfileFunc = func(ctx context.Context, in *pb.GetUserProfileRequest, opts ...grpc.CallOption) (*pb.GetUserProfileResponse, error) {
+		return &pb.GetUserProfileResponse{User: &pb.User{Id: in.UserId, Username: "user"}}, nil
+	}
+	attachments := NewAttachmentUsecase(attachmentRepo, newMemoryStore(), auth, nil)
+
+	postRepo := &MockPostRepository{
+		CreatePostFunc: func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
+			return postID, nil
+		},
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id, AuthorID: 1}, nil
+		},
+	}
+	postUC := NewPostUsecase(postRepo, auth, nil)
+	postUC.Attachments = attachments
+
+	post, err := postUC.CreatePost(context.Background(), "token", "Title", "Content", nil, []int64{5}, nil)
+	require.NoError(t, err)
+	if assert.Len(t, post.Attachments, 1) {
+		assert.Equal(t, "/api/v1/attachments/5", post.Attachments[0].URL)
+	}
+
+	deletedAt := time.Now()
+	commentRepo := &MockCommentRepository{
+		CreateCommentFunc: func(ctx context.Context, comment *entity.Comment) error {
+			comment.ID = commentID
+			return nil
+		},
+		GetCommentsByPostIDFunc: func(ctx context.Context, postID int64) ([]entity.Comment, error) {
+			return []entity.Comment{{ID: commentID, AuthorID: 7}, {ID: 11, AuthorID: 7, DeletedAt: &deletedAt}}, nil
+		},
+	}
+	commentUC := NewCommentUseCase(commentRepo, postRepo, auth)
+	commentUC.Attachments = attachments
+
+	comment := &entity.Comment{PostID: postID, AuthorID: 7, Content: "see file", AttachmentIDs: []int64{5}}
+	require.NoError(t, commentUC.CreateComment(context.Background(), comment))
+	assert.Len(t, comment.Attachments, 1)
+	assert.Equal(t, []entity.AttachmentTarget{entity.AttachmentTargetPost, entity.AttachmentTargetComment}, linked)
+
+	comments, err := commentUC.GetCommentsByPostID(context.Background(), postID)
+	require.NoError(t, err)
+	assert.Len(t, comments[0].Attachments, 1)
+	assert.Empty(t, comments[1].Attachments)
+
+	// Чужое или уже использованное вложение не дает создать пост
+	attachmentRepo.CountUnattachedFunc = func(ctx context.Context, ownerID int64, ids []int64) (int, error) {
+		return 0, nil
+	}
+	created := false
+	postRepo.CreatePostFunc = func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
+		created = true
+		return postID, nil
+	}
+	_, err = postUC.CreatePost(context.Background(), "token", "Title", "Content", nil, []int64{6}, nil)
+	assert.ErrorIs(t, err, entity.ErrInvalidAttachment)
+	assert.False(t, created)
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// validateToken проверяет токен через Auth Service и возвращает данные сессии
+func validateToken(ctx context.Context, authClient pb.AuthServiceClient, token string) (*pb.ValidateSessionResponse, error) {
+	resp, err := authClient.ValidateToken(ctx, &pb.ValidateTokenRequest{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || !resp.Valid {
+		return nil, ErrInvalidToken
+	}
+	return resp, nil
+}
+
+// isAdmin сообщает, может ли пользователь с данной ролью выполнять административные действия
+func isAdmin(role string) bool {
+	return role == "admin"
+}
+
+// isModerator сообщает, может ли пользователь модерировать чужой контент в обход ограничений автора
+func isModerator(role string) bool {
+	return role == "moderator" || isAdmin(role)
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+)
+
+const (
+	DefaultBookmarksLimit = 20
+	MaxBookmarksLimit     = 100
+)
+
+// BookmarkChecker отмечает посты ленты, сохраненные текущим пользователем
+type BookmarkChecker interface {
+	BookmarkedPostIDs(ctx context.Context, token string, postIDs []int64) (map[int64]bool, error)
+}
+
+type BookmarkUsecaseInterface interface {
+	BookmarkChecker
+	AddBookmark(ctx context.Context, token string, target entity.BookmarkTarget, id int64, folder string, tags []string) (*entity.Bookmark, error)
+	RemoveBookmark(ctx context.Context, token string, target entity.BookmarkTarget, id int64) error
+	GetBookmarks(ctx context.Context, token string, filter entity.BookmarkFilter) (*entity.BookmarkList, error)
+	GetFolders(ctx context.Context, token string) ([]entity.BookmarkFolder, error)
+}
+
+type BookmarkUsecase struct {
+	bookmarkRepo repository.BookmarkRepository
+	postRepo     repository.PostRepository
+	commentRepo  repository.CommentRepository
+	authClient   pb.AuthServiceClient
+}
+
+func NewBookmarkUsecase(
+	bookmarkRepo repository.BookmarkRepository,
+	postRepo repository.PostRepository,
+	commentRepo repository.CommentRepository,
+	authClient pb.AuthServiceClient,
+) *BookmarkUsecase {
+	return &BookmarkUsecase{
+		bookmarkRepo: bookmarkRepo,
+		postRepo:     postRepo,
+		commentRepo:  commentRepo,
+		authClient:   authClient,
+	}
+}
+
+// AddBookmark сохраняет пост или комментарий в закладки. Повторный вызов
+// переносит закладку в другую папку и заменяет ее теги.
+func (uc *BookmarkUsecase) AddBookmark(
+	ctx context.Context,
+	token string,
+	target entity.BookmarkTarget,
+	id int64,
+	folder string,
+	tags []string,
+) (*entity.Bookmark, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err = entity.NormalizeBookmarkFolder(folder)
+	if err != nil {
+		return nil, err
+	}
+	tags, err = entity.NormalizeBookmarkTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark := &entity.Bookmark{UserID: session.UserId, Folder: folder, Tags: tags}
+	if target == entity.BookmarkTargetComment {
+		comment, err := uc.commentRepo.GetCommentByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if comment.DeletedAt != nil {
+			return nil, repository.ErrCommentNotFound
+		}
+		bookmark.PostID = comment.PostID
+		bookmark.CommentID = &comment.ID
+	} else {
+		post, err := uc.postRepo.GetPostByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		bookmark.PostID = post.ID
+	}
+
+	if err := uc.bookmarkRepo.SaveBookmark(ctx, bookmark); err != nil {
+		return nil, err
+	}
+	return bookmark, nil
+}
+
+// RemoveBookmark удаляет закладку; удалить можно и закладку на объект, уже попавший в корзину
+func (uc *BookmarkUsecase) RemoveBookmark(ctx context.Context, token string, target entity.BookmarkTarget, id int64) error {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return err
+	}
+
+	if target == entity.BookmarkTargetComment {
+		comment, err := uc.commentRepo.GetCommentByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return uc.bookmarkRepo.DeleteBookmark(ctx, session.UserId, comment.PostID, &comment.ID)
+	}
+	return uc.bookmarkRepo.DeleteBookmark(ctx, session.UserId, id, nil)
+}
+
+func (uc *BookmarkUsecase) GetBookmarks(ctx context.Context, token string, filter entity.BookmarkFilter) (*entity.BookmarkList, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultBookmarksLimit
+	}
+	if filter.Limit > MaxBookmarksLimit {
+		filter.Limit = MaxBookmarksLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	bookmarks, total, err := uc.bookmarkRepo.GetBookmarks(ctx, session.UserId, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.BookmarkList{Bookmarks: bookmarks, Total: total}, nil
+}
+
+func (uc *BookmarkUsecase) GetFolders(ctx context.Context, token string) ([]entity.BookmarkFolder, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+	return uc.bookmarkRepo.GetFolders(ctx, session.UserId)
+}
+
+// BookmarkedPostIDs реализует BookmarkChecker
+func (uc *BookmarkUsecase) BookmarkedPostIDs(ctx context.Context, token string, postIDs []int64) (map[int64]bool, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64]bool, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	ids, err := uc.bookmarkRepo.GetBookmarkedPostIDs(ctx, session.UserId, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockBookmarkRepository struct {
+	SaveBookmarkFunc         func(ctx context.Context, b *entity.Bookmark) error
+	DeleteBookmarkFunc       func(ctx context.Context, userID, postID int64, commentID *int64) error
+	GetBookmarksFunc         func(ctx context.Context, userID int64, filter entity.BookmarkFilter) ([]entity.Bookmark, int, error)
+	GetFoldersFunc           func(ctx context.Context, userID int64) ([]entity.BookmarkFolder, error)
+	GetBookmarkedPostIDsFunc func(ctx context.Context, userID int64, postIDs []int64) ([]int64, error)
+}
+
+func (m *MockBookmarkRepository) SaveBookmark(ctx context.Context, b *entity.Bookmark) error {
+	return m.SaveBookmarkFunc(ctx, b)
+}
+
+func (m *MockBookmarkRepository) DeleteBookmark(ctx context.Context, userID, postID int64, commentID *int64) error {
+	return m.DeleteBookmarkFunc(ctx, userID, postID, commentID)
+}
+
+func (m *MockBookmarkRepository) GetBookmarks(ctx context.Context, userID int64, filter entity.BookmarkFilter) ([]entity.Bookmark, int, error) {
+	return m.GetBookmarksFunc(ctx, userID, filter)
+}
+
+func (m *MockBookmarkRepository) GetFolders(ctx context.Context, userID int64) ([]entity.BookmarkFolder, error) {
+	return m.GetFoldersFunc(ctx, userID)
+}
+
+func (m *MockBookmarkRepository) GetBookmarkedPostIDs(ctx context.Context, userID int64, postIDs []int64) ([]int64, error) {
+	return m.GetBookmarkedPostIDsFunc(ctx, userID, postIDs)
+}
+
+func TestBookmarkUsecase_AddBookmark(t *testing.T) {
+	deletedAt := time.Now()
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			if id != 1 {
+				return nil, repository.ErrPostNotFound
+			}
+			return &entity.Post{ID: 1}, nil
+		},
+	}
+	commentRepo := &MockCommentRepository{
+		GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+			switch id {
+			case 10:
+				return &entity.Comment{ID: 10, PostID: 1}, nil
+			case 11:
+				return &entity.Comment{ID: 11, PostID: 1, DeletedAt: &deletedAt}, nil
+			}
+			return nil, repository.ErrCommentNotFound
+		},
+	}
+
+	tests := []struct {
+		name          string
+		target        entity.BookmarkTarget
+		id            int64
+		tags          []string
+		wantErr       error
+		wantCommentID bool
+	}{
+		{name: "Post", target: entity.BookmarkTargetPost, id: 1, tags: []string{"Go", "go"}},
+		{name: "Comment", target: entity.BookmarkTargetComment, id: 10, wantCommentID: true},
+		{name: "Missing post", target: entity.BookmarkTargetPost, id: 2, wantErr: repository.ErrPostNotFound},
+		{name: "Deleted comment", target: entity.BookmarkTargetComment, id: 11, wantErr: repository.ErrCommentNotFound},
+		{name: "Too many tags", target: entity.BookmarkTargetPost, id: 1,
+			tags: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}, wantErr: entity.ErrTooManyBookmarkTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *entity.Bookmark
+			repo := &MockBookmarkRepository{
+				SaveBookmarkFunc: func(ctx context.Context, b *entity.Bookmark) error {
+					saved = b
+					b.ID = 5
+					return nil
+				},
+			}
+			uc := NewBookmarkUsecase(repo, postRepo, commentRepo, sessionAuth(7, "user"))
+
+			bookmark, err := uc.AddBookmark(context.Background(), "token", tt.target, tt.id, " later ", tt.tags)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(5), bookmark.ID)
+			assert.Equal(t, int64(7), saved.UserID)
+			assert.Equal(t, int64(1), saved.PostID)
+			assert.Equal(t, "later", saved.Folder)
+			assert.Equal(t, tt.wantCommentID, saved.CommentID != nil)
+			if tt.tags != nil {
+				assert.Equal(t, []string{"go"}, []string(saved.Tags))
+			}
+		})
+	}
+}
+
+func TestBookmarkUsecase_RemoveBookmark(t *testing.T) {
+	var calls []string
+	repo := &MockBookmarkRepository{
+		DeleteBookmarkFunc: func(ctx context.Context, userID, postID int64, commentID *int64) error {
+			if commentID != nil {
+				calls = append(calls, "comment")
+				assert.Equal(t, int64(10), *commentID)
+			} else {
+				calls = append(calls, "post")
+			}
+			assert.Equal(t, int64(1), postID)
+			return nil
+		},
+	}
+	commentRepo := &MockCommentRepository{
+		GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+			return &entity.Comment{ID: id, PostID: 1}, nil
+		},
+	}
+	uc := NewBookmarkUsecase(repo, nil, commentRepo, sessionAuth(7, "user"))
+
+	assert.NoError(t, uc.RemoveBookmark(context.Background(), "token", entity.BookmarkTargetPost, 1))
+	assert.NoError(t, uc.RemoveBookmark(context.Background(), "token", entity.BookmarkTargetComment, 10))
+	assert.Equal(t, []string{"post", "comment"}, calls)
+}
+
+func TestBookmarkUsecase_GetBookmarks(t *testing.T) {
+	repo := &MockBookmarkRepository{
+		GetBookmarksFunc: func(ctx context.Context, userID int64, filter entity.BookmarkFilter) ([]entity.Bookmark, int, error) {
+			assert.Equal(t, int64(7), userID)
+			assert.Equal(t, entity.BookmarkFilter{Folder: "later", Limit: DefaultBookmarksLimit}, filter)
+			return []entity.Bookmark{{ID: 1}}, 3, nil
+		},
+	}
+	uc := NewBookmarkUsecase(repo, nil, nil, sessionAuth(7, "user"))
+
+	list, err := uc.GetBookmarks(context.Background(), "token", entity.BookmarkFilter{Folder: "later", Offset: -1})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.Total)
+	assert.Len(t, list.Bookmarks, 1)
+}
+
+func TestBookmarkUsecase_BookmarkedPostIDs(t *testing.T) {
+	repo := &MockBookmarkRepository{
+		GetBookmarkedPostIDsFunc: func(ctx context.Context, userID int64, postIDs []int64) ([]int64, error) {
+			return []int64{2}, nil
+		},
+	}
+	uc := NewBookmarkUsecase(repo, nil, nil, sessionAuth(7, "user"))
+
+	marks, err := uc.BookmarkedPostIDs(context.Background(), "token", []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]bool{2: true}, marks)
+
+	// Пустая лента не требует запроса
+	marks, err = uc.BookmarkedPostIDs(context.Background(), "token", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, marks)
+}
@@ -3,22 +3,30 @@ package usecase
 import (
 	"context"
 	"errors"
+	"time"
 
 	pb "github.com/jaliks17/ffffforum/backend/proto"
 
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/markdown"
 )
 
 var (
-	ErrCommentNotFound = errors.New("comment not found")
-	ErrForbidden      = errors.New("forbidden")
+	ErrCommentNotFound   = errors.New("comment not found")
+	ErrForbidden         = errors.New("forbidden")
+	ErrEditWindowExpired = errors.New("edit window expired")
+	ErrInvalidParent     = errors.New("parent comment belongs to another post or was deleted")
 )
 
+// DefaultCommentEditWindow - время после публикации, в течение которого автор может править комментарий
+const DefaultCommentEditWindow = 15 * time.Minute
+
 type CommentUseCaseInterface interface {
 	CreateComment(ctx context.Context, comment *entity.Comment) error
 	GetComment(ctx context.Context, id int64) (*entity.Comment, error)
-	DeleteComment(ctx context.Context, id int64, userID int64) error
+	DeleteComment(ctx context.Context, id int64, userID int64, role string, reason string) error
+	EditComment(ctx context.Context, id int64, userID int64, role string, content string) (*entity.Comment, error)
 	GetCommentsByPostID(ctx context.Context, postID int64) ([]entity.Comment, error)
 	GetAuthClient() pb.AuthServiceClient
 }
@@ -27,6 +35,16 @@ type CommentUseCase struct {
 	CommentRepo repository.CommentRepository
 	PostRepo    repository.PostRepository
 	AuthClient  pb.AuthServiceClient
+	// EditWindow ограничивает правку комментария автором; 0 - без ограничения
+	EditWindow time.Duration
+	// Mentions обрабатывает @упоминания в новых комментариях; nil - упоминания не отслеживаются
+	Mentions MentionProcessor
+	// Notifications уведомляет об ответах на пост и комментарий; nil - без уведомлений
+	Notifications CommentNotifier
+	// Attachments привязывает загруженные файлы к комментариям; nil - вложения игнорируются
+	Attachments AttachmentLinker
+	// Filter проверяет текст комментариев фильтром контента; nil - контент не проверяется
+	Filter ContentChecker
 }
 
 func NewCommentUseCase(
@@ -38,15 +56,34 @@ func NewCommentUseCase(
 		CommentRepo: commentRepo,
 		PostRepo:    postRepo,
 		AuthClient:  authClient,
+		EditWindow:  DefaultCommentEditWindow,
 	}
 }
 
 func (uc *CommentUseCase) CreateComment(ctx context.Context, comment *entity.Comment) error {
 
-	_, err := uc.PostRepo.GetPostByID(ctx, comment.PostID)
+	post, err := uc.PostRepo.GetPostByID(ctx, comment.PostID)
 	if err != nil {
 		return err
 	}
+	if post.Locked {
+		return entity.ErrPostLocked
+	}
+
+	// Ответить можно только на живой комментарий того же поста
+	var parent *entity.Comment
+	if comment.ParentID != nil {
+		parent, err = uc.CommentRepo.GetCommentByID(ctx, *comment.ParentID)
+		if errors.Is(err, repository.ErrCommentNotFound) {
+			return ErrInvalidParent
+		}
+		if err != nil {
+			return err
+		}
+		if parent.PostID != comment.PostID || parent.DeletedAt != nil {
+			return ErrInvalidParent
+		}
+	}
 
 	userResp, err := uc.AuthClient.GetUserProfile(ctx, &pb.GetUserProfileRequest{UserId: comment.AuthorID})
 	if err != nil || userResp == nil || userResp.User == nil {
@@ -54,7 +91,58 @@ func (uc *CommentUseCase) CreateComment(ctx context.Context, comment *entity.Com
 	}
 
 	comment.AuthorName = userResp.User.Username
-	return uc.CommentRepo.CreateComment(ctx, comment)
+
+	var verdict *entity.FilterVerdict
+	check := entity.ContentCheck{UserID: comment.AuthorID, Kind: entity.ContentKindComment, Text: comment.Content}
+	if uc.Filter != nil {
+		if verdict, err = uc.Filter.Check(ctx, check); err != nil {
+			return err
+		}
+		if err := verdict.Err(); err != nil {
+			return err
+		}
+		comment.Content = verdict.Text
+		// Отложенный фильтром комментарий сразу сохраняется скрытым
+		comment.Held = verdict.Action == entity.FilterActionHold
+	}
+	if comment.ContentHTML, err = markdown.Render(comment.Content); err != nil {
+		return err
+	}
+	if uc.Attachments != nil {
+		if err := uc.Attachments.CheckAttachments(ctx, comment.AuthorID, comment.AttachmentIDs); err != nil {
+			return err
+		}
+	}
+	if err := uc.CommentRepo.CreateComment(ctx, comment); err != nil {
+		return err
+	}
+	if uc.Filter != nil {
+		uc.Filter.Record(ctx, check)
+		if comment.Held {
+			if err := uc.Filter.Hold(ctx, entity.ReportTargetComment, comment.ID, comment.AuthorID, verdict.Reason); err != nil {
+				return err
+			}
+		}
+	}
+	if uc.Attachments != nil && len(comment.AttachmentIDs) > 0 {
+		comment.Attachments, err = uc.Attachments.LinkAttachments(ctx, comment.AuthorID, entity.AttachmentTargetComment, comment.ID, comment.AttachmentIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Комментарий уже сохранен, поэтому ошибка упоминаний его не отменяет (ее логирует MentionUsecase).
+	// Об отложенном фильтром комментарии никто не уведомляется до решения модератора.
+	if comment.Held {
+		return nil
+	}
+	if uc.Mentions != nil {
+		_, _ = uc.Mentions.ProcessMentions(ctx, entity.MentionSourceComment, comment.ID, comment.PostID, comment.AuthorID, comment.Content)
+	}
+	if uc.Notifications != nil && post != nil {
+		uc.Notifications.NotifyComment(ctx, post, comment, parent)
+	}
+	return nil
 }
 
 func (uc *CommentUseCase) GetCommentsByPostID(ctx context.Context, postID int64) ([]entity.Comment, error) {
@@ -71,6 +159,10 @@ func (uc *CommentUseCase) GetCommentsByPostID(ctx context.Context, postID int64)
 
 	// Fetch author names for each comment
 	for i := range comments {
+		if comments[i].DeletedAt != nil {
+			comments[i].Redact()
+			continue
+		}
 		userResp, err := uc.AuthClient.GetUserProfile(ctx, &pb.GetUserProfileRequest{UserId: comments[i].AuthorID})
 		if err != nil || userResp == nil || userResp.User == nil {
 			comments[i].AuthorName = "Unknown"
@@ -79,20 +171,92 @@ func (uc *CommentUseCase) GetCommentsByPostID(ctx context.Context, postID int64)
 		comments[i].AuthorName = userResp.User.Username
 	}
 
+	if uc.Attachments != nil && len(comments) > 0 {
+		ids := make([]int64, 0, len(comments))
+		for _, c := range comments {
+			if c.DeletedAt == nil {
+				ids = append(ids, c.ID)
+			}
+		}
+		attachments, err := uc.Attachments.AttachmentsFor(ctx, entity.AttachmentTargetComment, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i := range comments {
+			comments[i].Attachments = attachments[comments[i].ID]
+		}
+	}
+
 	return comments, nil
 }
 
-func (uc *CommentUseCase) DeleteComment(ctx context.Context, id int64, userID int64) error {
+// DeleteComment перемещает комментарий в корзину. Удалить может автор, а также модератор.
+func (uc *CommentUseCase) DeleteComment(ctx context.Context, id int64, userID int64, role string, reason string) error {
 	comment, err := uc.CommentRepo.GetCommentByID(ctx, id)
 	if err != nil {
 		return err
 	}
+	if comment.DeletedAt != nil {
+		return repository.ErrCommentNotFound
+	}
 
-	if comment.AuthorID != userID {
+	if comment.AuthorID != userID && !isModerator(role) {
 		return ErrForbidden
 	}
 
-	return uc.CommentRepo.DeleteComment(ctx, id)
+	return uc.CommentRepo.DeleteComment(ctx, id, userID, reason)
+}
+
+// EditComment меняет текст комментария. Автор может править только в пределах EditWindow,
+// модераторы и администраторы - любой комментарий без ограничения по времени.
+func (uc *CommentUseCase) EditComment(ctx context.Context, id int64, userID int64, role string, content string) (*entity.Comment, error) {
+	comment, err := uc.CommentRepo.GetCommentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt != nil {
+		return nil, repository.ErrCommentNotFound
+	}
+
+	if !isModerator(role) {
+		if comment.AuthorID != userID {
+			return nil, ErrForbidden
+		}
+		if uc.EditWindow > 0 && time.Since(comment.CreatedAt) > uc.EditWindow {
+			return nil, ErrEditWindowExpired
+		}
+	}
+
+	// Правка проверяется фильтром так же, как новый комментарий; отложенная правка
+	// сохраняется сразу скрытой
+	var verdict *entity.FilterVerdict
+	held := false
+	if uc.Filter != nil {
+		if verdict, err = uc.Filter.Check(ctx, entity.ContentCheck{UserID: userID, Kind: entity.ContentKindComment, Text: content, Edit: true}); err != nil {
+			return nil, err
+		}
+		if err := verdict.Err(); err != nil {
+			return nil, err
+		}
+		content = verdict.Text
+		held = verdict.Action == entity.FilterActionHold
+	}
+
+	rendered, err := markdown.Render(content)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.CommentRepo.UpdateComment(ctx, id, userID, content, rendered, held)
+	if err != nil {
+		return nil, err
+	}
+	if held {
+		if err := uc.Filter.Hold(ctx, entity.ReportTargetComment, id, updated.AuthorID, verdict.Reason); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
 }
 
 func (uc *CommentUseCase) GetAuthClient() pb.AuthServiceClient {
@@ -100,5 +264,10 @@ func (uc *CommentUseCase) GetAuthClient() pb.AuthServiceClient {
 }
 
 func (uc *CommentUseCase) GetComment(ctx context.Context, id int64) (*entity.Comment, error) {
-	return uc.CommentRepo.GetCommentByID(ctx, id)
+	comment, err := uc.CommentRepo.GetCommentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	comment.Redact()
+	return comment, nil
 }
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	pb "github.com/jaliks17/ffffforum/backend/proto"
 
@@ -18,8 +19,9 @@ import (
 type MockCommentRepository struct {
 	CreateCommentFunc       func(ctx context.Context, comment *entity.Comment) error
 	GetCommentsByPostIDFunc func(ctx context.Context, postID int64) ([]entity.Comment, error)
-	DeleteCommentFunc       func(ctx context.Context, id int64) error
+	DeleteCommentFunc       func(ctx context.Context, id, deletedBy int64, reason string) error
 	GetCommentByIDFunc      func(ctx context.Context, id int64) (*entity.Comment, error)
+	UpdateCommentFunc       func(ctx context.Context, id, editorID int64, content, contentHTML string, held bool) (*entity.Comment, error)
 }
 
 func (m *MockCommentRepository) CreateComment(ctx context.Context, comment *entity.Comment) error {
@@ -30,8 +32,8 @@ func (m *MockCommentRepository) GetCommentsByPostID(ctx context.Context, postID
 	return m.GetCommentsByPostIDFunc(ctx, postID)
 }
 
-func (m *MockCommentRepository) DeleteComment(ctx context.Context, id int64) error {
-	return m.DeleteCommentFunc(ctx, id)
+func (m *MockCommentRepository) DeleteComment(ctx context.Context, id, deletedBy int64, reason string) error {
+	return m.DeleteCommentFunc(ctx, id, deletedBy, reason)
 }
 
 func (m *MockCommentRepository) GetCommentByID(ctx context.Context, id int64) (*entity.Comment, error) {
@@ -41,6 +43,18 @@ func (m *MockCommentRepository) GetCommentByID(ctx context.Context, id int64) (*
 	return nil, nil
 }
 
+// GetCommentForModeration отвечает так же, как GetCommentByID
+func (m *MockCommentRepository) GetCommentForModeration(ctx context.Context, id int64) (*entity.Comment, error) {
+	return m.GetCommentByID(ctx, id)
+}
+
+func (m *MockCommentRepository) UpdateComment(ctx context.Context, id, editorID int64, content, contentHTML string, held bool) (*entity.Comment, error) {
+	if m.UpdateCommentFunc != nil {
+		return m.UpdateCommentFunc(ctx, id, editorID, content, contentHTML, held)
+	}
+	return nil, nil
+}
+
 func TestCommentUseCase_CreateComment(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -106,6 +120,29 @@ func TestCommentUseCase_CreateComment(t *testing.T) {
 			wantErr:     true,
 			expectedErr: repository.ErrPostNotFound,
 		},
+		{
+			name: "Post is locked",
+			comment: &entity.Comment{
+				PostID:   1,
+				Content:  "Test comment",
+				AuthorID: 1,
+			},
+			mockPost: func() *MockPostRepository {
+				return &MockPostRepository{
+					GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+						return &entity.Post{ID: id, PostFlags: entity.PostFlags{Locked: true}}, nil
+					},
+				}
+			},
+			mockComment: func() *MockCommentRepository {
+				return &MockCommentRepository{}
+			},
+			mockAuth: func() *MockAuthServiceClient {
+				return &MockAuthServiceClient{}
+			},
+			wantErr:     true,
+			expectedErr: entity.ErrPostLocked,
+		},
 	}
 
 	for _, tt := range tests {
@@ -290,7 +327,7 @@ func TestCommentUseCase_DeleteComment(t *testing.T) {
 					GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
 						return &entity.Comment{ID: id, AuthorID: 1}, nil
 					},
-					DeleteCommentFunc: func(ctx context.Context, id int64) error {
+					DeleteCommentFunc: func(ctx context.Context, id, deletedBy int64, reason string) error {
 						return nil
 					},
 				}
@@ -345,7 +382,7 @@ func TestCommentUseCase_DeleteComment(t *testing.T) {
 					GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
 						return &entity.Comment{ID: id, AuthorID: 1}, nil
 					},
-					DeleteCommentFunc: func(ctx context.Context, id int64) error {
+					DeleteCommentFunc: func(ctx context.Context, id, deletedBy int64, reason string) error {
 						return errors.New("delete error")
 					},
 				}
@@ -359,9 +396,227 @@ func TestCommentUseCase_DeleteComment(t *testing.T) {
 			mockComment := tt.mockComment()
 			uc := NewCommentUseCase(mockComment, nil, nil)
 
-			err := uc.DeleteComment(context.Background(), tt.commentID, tt.userID)
+			err := uc.DeleteComment(context.Background(), tt.commentID, tt.userID, "user", "")
 
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestCommentUseCase_EditComment(t *testing.T) {
+	fresh := &entity.Comment{ID: 1, AuthorID: 1, CreatedAt: time.Now().Add(-time.Minute)}
+	stale := &entity.Comment{ID: 1, AuthorID: 1, CreatedAt: time.Now().Add(-time.Hour)}
+
+	tests := []struct {
+		name    string
+		comment *entity.Comment
+		userID  int64
+		role    string
+		wantErr error
+	}{
+		{
+			name:    "Author within window",
+			comment: fresh,
+			userID:  1,
+			role:    "user",
+		},
+		{
+			name:    "Author after window",
+			comment: stale,
+			userID:  1,
+			role:    "user",
+			wantErr: ErrEditWindowExpired,
+		},
+		{
+			name:    "Not author",
+			comment: fresh,
+			userID:  2,
+			role:    "user",
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "Moderator after window",
+			comment: stale,
+			userID:  3,
+			role:    "moderator",
+		},
+		{
+			name:    "Admin after window",
+			comment: stale,
+			userID:  4,
+			role:    "admin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var editorID int64
+			mockComment := &MockCommentRepository{
+				GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+					return tt.comment, nil
+				},
+				UpdateCommentFunc: func(ctx context.Context, id, editor int64, content, contentHTML string, held bool) (*entity.Comment, error) {
+					editorID = editor
+					return &entity.Comment{ID: id, AuthorID: tt.comment.AuthorID, Content: content}, nil
+				},
+			}
+			uc := NewCommentUseCase(mockComment, nil, nil)
+
+			got, err := uc.EditComment(context.Background(), 1, tt.userID, tt.role, "Edited")
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, "Edited", got.Content)
+				assert.Equal(t, tt.userID, editorID)
+			}
+		})
+	}
+
+	t.Run("Unlimited window", func(t *testing.T) {
+		mockComment := &MockCommentRepository{
+			GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+				return stale, nil
+			},
+			UpdateCommentFunc: func(ctx context.Context, id, editor int64, content, contentHTML string, held bool) (*entity.Comment, error) {
+				return &entity.Comment{ID: id, Content: content}, nil
+			},
+		}
+		uc := NewCommentUseCase(mockComment, nil, nil)
+		uc.EditWindow = 0
+
+		_, err := uc.EditComment(context.Background(), 1, 1, "user", "Edited")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockComment := &MockCommentRepository{
+			GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+				return nil, repository.ErrCommentNotFound
+			},
+		}
+		uc := NewCommentUseCase(mockComment, nil, nil)
+
+		_, err := uc.EditComment(context.Background(), 1, 1, "user", "Edited")
+		assert.ErrorIs(t, err, repository.ErrCommentNotFound)
+	})
+}
+
+func TestCommentUseCase_DeleteComment_Moderation(t *testing.T) {
+	deletedAt := time.Now()
+
+	t.Run("Moderator deletes someone else's comment", func(t *testing.T) {
+		var gotBy int64
+		var gotReason string
+		mockComment := &MockCommentRepository{
+			GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+				return &entity.Comment{ID: id, AuthorID: 1}, nil
+			},
+			DeleteCommentFunc: func(ctx context.Context, id, deletedBy int64, reason string) error {
+				gotBy, gotReason = deletedBy, reason
+				return nil
+			},
+		}
+		uc := NewCommentUseCase(mockComment, nil, nil)
+
+		err := uc.DeleteComment(context.Background(), 1, 9, "moderator", "spam")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9), gotBy)
+		assert.Equal(t, "spam", gotReason)
+	})
+
+	t.Run("Already deleted", func(t *testing.T) {
+		mockComment := &MockCommentRepository{
+			GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+				return &entity.Comment{ID: id, AuthorID: 1, DeletedAt: &deletedAt}, nil
+			},
+		}
+		uc := NewCommentUseCase(mockComment, nil, nil)
+
+		err := uc.DeleteComment(context.Background(), 1, 1, "user", "")
+		assert.ErrorIs(t, err, repository.ErrCommentNotFound)
+	})
+}
+
+func TestCommentUseCase_GetCommentsByPostID_DeletedPlaceholder(t *testing.T) {
+	deletedAt := time.Now()
+	mockComment := &MockCommentRepository{
+		GetCommentsByPostIDFunc: func(ctx context.Context, postID int64) ([]entity.Comment, error) {
+			return []entity.Comment{
+				{ID: 1, AuthorID: 1, PostID: postID, Content: "Visible"},
+				{ID: 2, AuthorID: 2, PostID: postID, Content: "Removed", DeletedAt: &deletedAt},
+			}, nil
+		},
+	}
+	mockPost := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id}, nil
+		},
+	}
+	mockAuth := &MockAuthServiceClient{
+		GetUserProfileFunc: func(ctx context.Context, in *pb.GetUserProfileRequest, opts ...grpc.CallOption) (*pb.GetUserProfileResponse, error) {
+			return &pb.GetUserProfileResponse{User: &pb.User{Id: in.UserId, Username: "user"}}, nil
+		},
+	}
+	uc := NewCommentUseCase(mockComment, mockPost, mockAuth)
+
+	comments, err := uc.GetCommentsByPostID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "user", comments[0].AuthorName)
+	assert.True(t, comments[1].Deleted)
+	assert.Equal(t, entity.DeletedPlaceholder, comments[1].Content)
+	assert.Equal(t, entity.DeletedPlaceholder, comments[1].AuthorName)
+	assert.Zero(t, comments[1].AuthorID)
+}
+
+func TestCommentUseCase_CreateComment_Reply(t *testing.T) {
+	parentID := int64(9)
+	auth := &MockAuthServiceClient{
+		GetUserProfileFunc: func(ctx context.Context, in *pb.GetUserProfileRequest, opts ...grpc.CallOption) (*pb.GetUserProfileResponse, error) {
+			return &pb.GetUserProfileResponse{User: &pb.User{Username: "testuser"}}, nil
+		},
+	}
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id, AuthorID: 1}, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		parent  *entity.Comment
+		wantErr error
+	}{
+		{name: "Success", parent: &entity.Comment{ID: parentID, PostID: 1, AuthorID: 4}},
+		{name: "Parent from another post", parent: &entity.Comment{ID: parentID, PostID: 2, AuthorID: 4}, wantErr: ErrInvalidParent},
+		{name: "Deleted parent", parent: &entity.Comment{ID: parentID, PostID: 1, AuthorID: 4, DeletedAt: &time.Time{}}, wantErr: ErrInvalidParent},
+		{name: "Missing parent", wantErr: ErrInvalidParent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored []entity.Notification
+			uc := NewCommentUseCase(&MockCommentRepository{
+				GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+					if tt.parent == nil {
+						return nil, repository.ErrCommentNotFound
+					}
+					return tt.parent, nil
+				},
+				CreateCommentFunc: func(ctx context.Context, comment *entity.Comment) error {
+					comment.ID = 10
+					return nil
+				},
+			}, postRepo, auth)
+			uc.Notifications = NewNotificationUsecase(storingNotificationRepository(&stored), postRepo, auth, nil, nil)
+
+			err := uc.CreateComment(context.Background(), &entity.Comment{PostID: 1, AuthorID: 2, ParentID: &parentID, Content: "reply"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, stored)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, stored, 2)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/wordfilter"
+)
+
+const (
+	// DefaultFilterRulesTTL - как долго правила фильтра кешируются между запросами к базе.
+	// Изменения через этот экземпляр сервиса применяются сразу, через другие - в пределах TTL.
+	DefaultFilterRulesTTL = time.Minute
+	// DefaultNewAccountAge - аккаунт младше этого возраста считается новым
+	DefaultNewAccountAge = 72 * time.Hour
+	// DefaultNewAccountMaxLinks - сколько ссылок можно опубликовать в одной записи с нового аккаунта
+	DefaultNewAccountMaxLinks = 2
+	// DefaultDuplicateWindow - в пределах этого окна повтор текста считается дубликатом
+	DefaultDuplicateWindow = 10 * time.Minute
+	// DefaultMassPostingUsers - сколько разных пользователей должны опубликовать одинаковый текст, чтобы он ушел на проверку
+	DefaultMassPostingUsers = 3
+	// MinDuplicateLength - короткие ответы ("спасибо", "+1") на дубликаты не проверяются
+	MinDuplicateLength = 20
+	// DefaultSpamThreshold - вероятность спама, начиная с которой классификатор откладывает контент
+	DefaultSpamThreshold = 0.95
+	// MaxSpamSamples - сколько последних примеров загружается в классификатор при запуске
+	MaxSpamSamples = 5000
+)
+
+// SpamClassifier оценивает вероятность спама. Реализация по умолчанию - spam.NaiveBayes.
+type SpamClassifier interface {
+	Train(text string, isSpam bool)
+	// SpamProbability возвращает ok=false, пока модели не хватает примеров
+	SpamProbability(text string) (float64, bool)
+}
+
+// ContentChecker проверяет текст перед сохранением, запоминает сохраненный текст для поиска
+// дубликатов и ставит отложенный контент в очередь модерации
+type ContentChecker interface {
+	Check(ctx context.Context, in entity.ContentCheck) (*entity.FilterVerdict, error)
+	Record(ctx context.Context, in entity.ContentCheck)
+	Hold(ctx context.Context, targetType entity.ReportTarget, targetID, authorID int64, reason string) error
+}
+
+// SpamLearner обучает классификатор на решениях модераторов
+type SpamLearner interface {
+	Learn(ctx context.Context, text string, isSpam bool)
+}
+
+type ContentFilterUsecaseInterface interface {
+	GetRules(ctx context.Context, token string) ([]entity.FilterRule, error)
+	CreateRule(ctx context.Context, token string, in entity.FilterRuleInput) (*entity.FilterRule, error)
+	DeleteRule(ctx context.Context, token string, id int64) error
+	Check(ctx context.Context, in entity.ContentCheck) (*entity.FilterVerdict, error)
+	Record(ctx context.Context, in entity.ContentCheck)
+}
+
+type compiledRule struct {
+	rule    entity.FilterRule
+	pattern *wordfilter.Pattern
+}
+
+type ContentFilterUsecase struct {
+	repo       repository.ContentFilterRepository
+	authClient pb.AuthServiceClient
+	logger     *logger.Logger
+	now        func() time.Time
+
+	mu       sync.Mutex
+	rules    []compiledRule
+	loadedAt time.Time
+
+	// Classifier откладывает контент, похожий на спам; nil - классификатор выключен
+	Classifier SpamClassifier
+	// RulesTTL - время жизни кеша правил
+	RulesTTL time.Duration
+	// NewAccountAge и NewAccountMaxLinks ограничивают ссылки от новых аккаунтов; MaxLinks < 0 - без ограничения
+	NewAccountAge      time.Duration
+	NewAccountMaxLinks int
+	// DuplicateWindow - окно поиска дубликатов; 0 - дубликаты не ищутся
+	DuplicateWindow time.Duration
+	// MassPostingUsers - порог одинаковых записей от разных пользователей
+	MassPostingUsers int
+	// SpamThreshold - порог вероятности спама для классификатора
+	SpamThreshold float64
+}
+
+func NewContentFilterUsecase(repo repository.ContentFilterRepository, authClient pb.AuthServiceClient, logger *logger.Logger) *ContentFilterUsecase {
+	return &ContentFilterUsecase{
+		repo:               repo,
+		authClient:         authClient,
+		logger:             logger,
+		now:                time.Now,
+		RulesTTL:           DefaultFilterRulesTTL,
+		NewAccountAge:      DefaultNewAccountAge,
+		NewAccountMaxLinks: DefaultNewAccountMaxLinks,
+		DuplicateWindow:    DefaultDuplicateWindow,
+		MassPostingUsers:   DefaultMassPostingUsers,
+		SpamThreshold:      DefaultSpamThreshold,
+	}
+}
+
+// GetRules возвращает правила фильтра. Доступно только администраторам.
+func (uc *ContentFilterUsecase) GetRules(ctx context.Context, token string) ([]entity.FilterRule, error) {
+	if err := uc.requireAdmin(ctx, token); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetFilterRules(ctx)
+}
+
+// CreateRule добавляет правило. Доступно только администраторам.
+func (uc *ContentFilterUsecase) CreateRule(ctx context.Context, token string, in entity.FilterRuleInput) (*entity.FilterRule, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin(session.UserRole) {
+		return nil, ErrForbidden
+	}
+	if err := in.Normalize(); err != nil {
+		return nil, err
+	}
+
+	rule := &entity.FilterRule{
+		Pattern:   in.Pattern,
+		IsRegex:   in.IsRegex,
+		Action:    in.Action,
+		CreatedBy: session.UserId,
+	}
+	if err := uc.repo.CreateFilterRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	uc.invalidateRules()
+	return rule, nil
+}
+
+// DeleteRule удаляет правило. Доступно только администраторам.
+func (uc *ContentFilterUsecase) DeleteRule(ctx context.Context, token string, id int64) error {
+	if err := uc.requireAdmin(ctx, token); err != nil {
+		return err
+	}
+	if err := uc.repo.DeleteFilterRule(ctx, id); err != nil {
+		return err
+	}
+	uc.invalidateRules()
+	return nil
+}
+
+func (uc *ContentFilterUsecase) requireAdmin(ctx context.Context, token string) error {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return err
+	}
+	if !isAdmin(session.UserRole) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// Check прогоняет текст через правила, ограничение ссылок для новых аккаунтов, поиск дубликатов
+// и классификатор. Побеждает самое строгое действие; маскирование применяется в любом случае.
+// Сбои вспомогательных проверок только логируются, чтобы фильтр не блокировал публикацию.
+func (uc *ContentFilterUsecase) Check(ctx context.Context, in entity.ContentCheck) (*entity.FilterVerdict, error) {
+	rules, err := uc.loadRules(ctx)
+	if err != nil {
+		uc.logError("Failed to load filter rules", err)
+	}
+
+	verdict := &entity.FilterVerdict{Title: in.Title, Text: in.Text}
+	for _, r := range rules {
+		titleSpans, textSpans := r.pattern.Find(verdict.Title), r.pattern.Find(verdict.Text)
+		if len(titleSpans) == 0 && len(textSpans) == 0 {
+			continue
+		}
+		verdict.Escalate(r.rule.Action, "banned word")
+		if r.rule.Action == entity.FilterActionMask {
+			verdict.Title = wordfilter.Mask(verdict.Title, titleSpans)
+			verdict.Text = wordfilter.Mask(verdict.Text, textSpans)
+		}
+	}
+	if verdict.Action == entity.FilterActionReject {
+		return verdict, nil
+	}
+
+	text := in.FullText()
+	if uc.NewAccountMaxLinks >= 0 && wordfilter.CountLinks(text) > uc.NewAccountMaxLinks && uc.isNewAccount(ctx, in.UserID) {
+		verdict.Escalate(entity.FilterActionHold, entity.FilterReasonLinks)
+	}
+
+	if uc.tracksDuplicates(in) {
+		stats, err := uc.repo.GetDuplicateStats(ctx, in.UserID, wordfilter.Fingerprint(text), uc.DuplicateWindow)
+		switch {
+		case err != nil:
+			uc.logError("Failed to check duplicate content", err)
+		case stats.Own > 0:
+			verdict.Escalate(entity.FilterActionReject, entity.FilterReasonDuplicate)
+			return verdict, nil
+		case uc.MassPostingUsers > 0 && stats.Others+1 >= uc.MassPostingUsers:
+			verdict.Escalate(entity.FilterActionHold, entity.FilterReasonMassPosting)
+		}
+	}
+
+	if uc.Classifier != nil {
+		if p, ok := uc.Classifier.SpamProbability(text); ok && p >= uc.SpamThreshold {
+			verdict.Escalate(entity.FilterActionHold, entity.FilterReasonClassifier)
+		}
+	}
+	return verdict, nil
+}
+
+// Record запоминает отпечаток сохраненного текста для поиска дубликатов. Вызывается после
+// записи, чтобы отклоненный или несохраненный текст не считался опубликованным.
+func (uc *ContentFilterUsecase) Record(ctx context.Context, in entity.ContentCheck) {
+	if !uc.tracksDuplicates(in) {
+		return
+	}
+	if err := uc.repo.RecordContent(ctx, in.UserID, in.Kind, wordfilter.Fingerprint(in.FullText())); err != nil {
+		uc.logError("Failed to record content fingerprint", err)
+	}
+}
+
+// tracksDuplicates сообщает, что текст проверяется на дубликаты: правки и короткие ответы не проверяются
+func (uc *ContentFilterUsecase) tracksDuplicates(in entity.ContentCheck) bool {
+	return uc.DuplicateWindow > 0 && !in.Edit && utf8.RuneCountInString(in.FullText()) >= MinDuplicateLength
+}
+
+// isNewAccount сообщает, что аккаунт зарегистрирован меньше NewAccountAge назад.
+// Если профиль недоступен, аккаунт считается новым: ограничение ссылок важнее удобства.
+func (uc *ContentFilterUsecase) isNewAccount(ctx context.Context, userID int64) bool {
+	resp, err := uc.authClient.GetUserProfile(ctx, &pb.GetUserProfileRequest{UserId: userID})
+	if err != nil || resp.GetUser().GetCreatedAt() == nil {
+		return true
+	}
+	return uc.now().Sub(resp.User.CreatedAt.AsTime()) < uc.NewAccountAge
+}
+
+// Hold ставит сохраненный скрытым пост или комментарий в очередь модерации. Без жалобы
+// модератор не узнает о скрытом контенте, поэтому ошибка возвращается вызывающему.
+func (uc *ContentFilterUsecase) Hold(ctx context.Context, targetType entity.ReportTarget, targetID, authorID int64, reason string) error {
+	return uc.repo.HoldContent(ctx, targetType, targetID, authorID, reason)
+}
+
+// Learn сохраняет пример и дообучает классификатор
+func (uc *ContentFilterUsecase) Learn(ctx context.Context, text string, isSpam bool) {
+	if uc.Classifier == nil || text == "" {
+		return
+	}
+	if err := uc.repo.SaveSpamSample(ctx, text, isSpam); err != nil {
+		uc.logError("Failed to save spam sample", err)
+		return
+	}
+	uc.Classifier.Train(text, isSpam)
+}
+
+// LoadModel обучает классификатор на сохраненных примерах. Вызывается при запуске.
+func (uc *ContentFilterUsecase) LoadModel(ctx context.Context) (int, error) {
+	if uc.Classifier == nil {
+		return 0, nil
+	}
+	samples, err := uc.repo.GetSpamSamples(ctx, MaxSpamSamples)
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range samples {
+		uc.Classifier.Train(s.Content, s.IsSpam)
+	}
+	return len(samples), nil
+}
+
+// PurgeFingerprints удаляет отпечатки, вышедшие за окно поиска дубликатов
+func (uc *ContentFilterUsecase) PurgeFingerprints(ctx context.Context) (int64, error) {
+	return uc.repo.PurgeFingerprints(ctx, uc.now().Add(-uc.DuplicateWindow))
+}
+
+// loadRules возвращает скомпилированные правила из кеша, перечитывая их раз в RulesTTL.
+// При ошибке чтения возвращаются прежние правила. Правило, которое не компилируется, пропускается.
+func (uc *ContentFilterUsecase) loadRules(ctx context.Context) ([]compiledRule, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if !uc.loadedAt.IsZero() && uc.now().Sub(uc.loadedAt) < uc.RulesTTL {
+		return uc.rules, nil
+	}
+
+	rules, err := uc.repo.GetFilterRules(ctx)
+	if err != nil {
+		return uc.rules, err
+	}
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := wordfilter.Compile(rule.Pattern, rule.IsRegex)
+		if err != nil {
+			uc.logError("Skipping invalid filter rule", err)
+			continue
+		}
+		compiled = append(compiled, compiledRule{rule: rule, pattern: pattern})
+	}
+	uc.rules = compiled
+	uc.loadedAt = uc.now()
+	return uc.rules, nil
+}
+
+func (uc *ContentFilterUsecase) invalidateRules() {
+	uc.mu.Lock()
+	uc.loadedAt = time.Time{}
+	uc.mu.Unlock()
+}
+
+func (uc *ContentFilterUsecase) logError(msg string, err error) {
+	if uc.logger != nil {
+		uc.logger.Error(msg, err)
+	}
+}
@@ -0,0 +1,614 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MockContentFilterRepository struct {
+	GetFilterRulesFunc    func(ctx context.Context) ([]entity.FilterRule, error)
+	CreateFilterRuleFunc  func(ctx context.Context, rule *entity.FilterRule) error
+	DeleteFilterRuleFunc  func(ctx context.Context, id int64) error
+	GetDuplicateStatsFunc func(ctx context.Context, userID int64, fingerprint string, window time.Duration) (*entity.DuplicateStats, error)
+	RecordContentFunc     func(ctx context.Context, userID int64, kind entity.ContentKind, fingerprint string) error
+	PurgeFingerprintsFunc func(ctx context.Context, before time.Time) (int64, error)
+	HoldContentFunc       func(ctx context.Context, targetType entity.ReportTarget, targetID, authorID int64, reason string) error
+	SaveSpamSampleFunc    func(ctx context.Context, content string, isSpam bool) error
+	GetSpamSamplesFunc    func(ctx context.Context, limit int) ([]entity.SpamSample, error)
+}
+
+func (m *MockContentFilterRepository) GetFilterRules(ctx context.Context) ([]entity.FilterRule, error) {
+	if m.GetFilterRulesFunc != nil {
+		return m.GetFilterRulesFunc(ctx)
+	}
+	return []entity.FilterRule{}, nil
+}
+
+func (m *MockContentFilterRepository) CreateFilterRule(ctx context.Context, rule *entity.FilterRule) error {
+	if m.CreateFilterRuleFunc != nil {
+		return m.CreateFilterRuleFunc(ctx, rule)
+	}
+	return nil
+}
+
+func (m *MockContentFilterRepository) DeleteFilterRule(ctx context.Context, id int64) error {
+	if m.DeleteFilterRuleFunc != nil {
+		return m.DeleteFilterRuleFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockContentFilterRepository) GetDuplicateStats(ctx context.Context, userID int64, fingerprint string, window time.Duration) (*entity.DuplicateStats, error) {
+	if m.GetDuplicateStatsFunc != nil {
+		return m.GetDuplicateStatsFunc(ctx, userID, fingerprint, window)
+	}
+	return &entity.DuplicateStats{}, nil
+}
+
+func (m *MockContentFilterRepository) RecordContent(ctx context.Context, userID int64, kind entity.ContentKind, fingerprint string) error {
+	if m.RecordContentFunc != nil {
+		return m.RecordContentFunc(ctx, userID, kind, fingerprint)
+	}
+	return nil
+}
+
+func (m *MockContentFilterRepository) PurgeFingerprints(ctx context.Context, before time.Time) (int64, error) {
+	if m.PurgeFingerprintsFunc != nil {
+		return m.PurgeFingerprintsFunc(ctx, before)
+	}
+	return 0, nil
+}
+
+func (m *MockContentFilterRepository) HoldContent(ctx context.Context, targetType entity.ReportTarget, targetID, authorID int64, reason string) error {
+	if m.HoldContentFunc != nil {
+		return m.HoldContentFunc(ctx, targetType, targetID, authorID, reason)
+	}
+	return nil
+}
+
+func (m *MockContentFilterRepository) SaveSpamSample(ctx context.Context, content string, isSpam bool) error {
+	if m.SaveSpamSampleFunc != nil {
+		return m.SaveSpamSampleFunc(ctx, content, isSpam)
+	}
+	return nil
+}
+
+func (m *MockContentFilterRepository) GetSpamSamples(ctx context.Context, limit int) ([]entity.SpamSample, error) {
+	if m.GetSpamSamplesFunc != nil {
+		return m.GetSpamSamplesFunc(ctx, limit)
+	}
+	return []entity.SpamSample{}, nil
+}
+
+// fixedClassifier всегда возвращает заданную вероятность и запоминает примеры
+type fixedClassifier struct {
+	p       float64
+	trained []entity.SpamSample
+}
+
+func (c *fixedClassifier) Train(text string, isSpam bool) {
+	c.trained = append(c.trained, entity.SpamSample{Content: text, IsSpam: isSpam})
+}
+
+func (c *fixedClassifier) SpamProbability(text string) (float64, bool) {
+	return c.p, c.p > 0
+}
+
+// profileAuth отдает профиль, зарегистрированный в created
+func profileAuth(created time.Time) *MockAuthServiceClient {
+	return &MockAuthServiceClient{
+		GetUserProfileFunc: func(ctx context.Context, in *pb.GetUserProfileRequest, opts ...grpc.CallOption) (*pb.GetUserProfileResponse, error) {
+			return &pb.GetUserProfileResponse{User: &pb.User{Id: in.UserId, Username: "user", CreatedAt: timestamppb.New(created)}}, nil
+		},
+	}
+}
+
+func TestContentFilterUsecase_Check(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rules := []entity.FilterRule{
+		{ID: 1, Pattern: "darn", Action: entity.FilterActionMask},
+		{ID: 2, Pattern: "casino", Action: entity.FilterActionHold},
+		{ID: 3, Pattern: `buy\s+followers`, IsRegex: true, Action: entity.FilterActionReject},
+	}
+	longText := "This is a perfectly ordinary message of decent length"
+
+	tests := []struct {
+		name       string
+		in         entity.ContentCheck
+		created    time.Time
+		stats      entity.DuplicateStats
+		spam       float64
+		wantAction entity.FilterAction
+		wantReason string
+		wantTitle  string
+		wantText   string
+		wantErr    error
+	}{
+		{
+			name:       "Clean text",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindPost, Title: "Hello", Text: longText},
+			wantAction: entity.FilterActionAllow, wantTitle: "Hello", wantText: longText,
+		},
+		{
+			name:       "Masked word in title and text",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindPost, Title: "Darn it", Text: "darn, darnation"},
+			wantAction: entity.FilterActionMask, wantReason: "banned word", wantTitle: "**** it", wantText: "****, darnation",
+		},
+		{
+			name:       "Hold rule keeps masking",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: "darn casino"},
+			wantAction: entity.FilterActionHold, wantReason: "banned word", wantText: "**** casino",
+		},
+		{
+			name:       "Regex reject",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindMessage, Text: "Buy   followers here"},
+			wantAction: entity.FilterActionReject, wantReason: "banned word", wantText: "Buy   followers here",
+			wantErr: entity.ErrContentRejected,
+		},
+		{
+			name:       "Links from new account",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: "https://a.com http://b.com www.c.com"},
+			created:    now.Add(-time.Hour),
+			wantAction: entity.FilterActionHold, wantReason: entity.FilterReasonLinks, wantText: "https://a.com http://b.com www.c.com",
+		},
+		{
+			name:       "Links from old account",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: "https://a.com http://b.com www.c.com"},
+			created:    now.Add(-30 * 24 * time.Hour),
+			wantAction: entity.FilterActionAllow, wantText: "https://a.com http://b.com www.c.com",
+		},
+		{
+			name:       "Own duplicate",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: longText},
+			stats:      entity.DuplicateStats{Own: 1},
+			wantAction: entity.FilterActionReject, wantReason: entity.FilterReasonDuplicate, wantText: longText,
+			wantErr: entity.ErrDuplicateContent,
+		},
+		{
+			name:       "Edit is not a duplicate",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindPost, Title: "Hello", Text: longText, Edit: true},
+			stats:      entity.DuplicateStats{Own: 1},
+			wantAction: entity.FilterActionAllow, wantTitle: "Hello", wantText: longText,
+		},
+		{
+			name:       "Mass posting",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: longText},
+			stats:      entity.DuplicateStats{Others: 2},
+			wantAction: entity.FilterActionHold, wantReason: entity.FilterReasonMassPosting, wantText: longText,
+		},
+		{
+			name:       "Classifier",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: longText},
+			spam:       0.99,
+			wantAction: entity.FilterActionHold, wantReason: entity.FilterReasonClassifier, wantText: longText,
+		},
+		{
+			name:       "Classifier below threshold",
+			in:         entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: longText},
+			spam:       0.5,
+			wantAction: entity.FilterActionAllow, wantText: longText,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockContentFilterRepository{
+				GetFilterRulesFunc: func(ctx context.Context) ([]entity.FilterRule, error) {
+					return rules, nil
+				},
+				GetDuplicateStatsFunc: func(ctx context.Context, userID int64, fingerprint string, window time.Duration) (*entity.DuplicateStats, error) {
+					assert.Equal(t, tt.in.UserID, userID)
+					assert.Equal(t, DefaultDuplicateWindow, window)
+					stats := tt.stats
+					return &stats, nil
+				},
+				RecordContentFunc: func(ctx context.Context, userID int64, kind entity.ContentKind, fingerprint string) error {
+					t.Error("Check must not record content")
+					return nil
+				},
+			}
+			created := tt.created
+			if created.IsZero() {
+				created = now.Add(-365 * 24 * time.Hour)
+			}
+			uc := NewContentFilterUsecase(repo, profileAuth(created), nil)
+			uc.now = func() time.Time { return now }
+			uc.Classifier = &fixedClassifier{p: tt.spam}
+
+			verdict, err := uc.Check(context.Background(), tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAction, verdict.Action)
+			assert.Equal(t, tt.wantReason, verdict.Reason)
+			assert.Equal(t, tt.wantTitle, verdict.Title)
+			assert.Equal(t, tt.wantText, verdict.Text)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, verdict.Err(), tt.wantErr)
+			} else {
+				assert.NoError(t, verdict.Err())
+			}
+		})
+	}
+}
+
+func TestContentFilterUsecase_Check_FailOpen(t *testing.T) {
+	repo := &MockContentFilterRepository{
+		GetFilterRulesFunc: func(ctx context.Context) ([]entity.FilterRule, error) {
+			return nil, errors.New("db down")
+		},
+		GetDuplicateStatsFunc: func(ctx context.Context, userID int64, fingerprint string, window time.Duration) (*entity.DuplicateStats, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	uc := NewContentFilterUsecase(repo, profileAuth(time.Now().Add(-365*24*time.Hour)), nil)
+
+	verdict, err := uc.Check(context.Background(), entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: "A message that is long enough to fingerprint"})
+	require.NoError(t, err)
+	assert.Equal(t, entity.FilterActionAllow, verdict.Action)
+}
+
+func TestContentFilterUsecase_Record(t *testing.T) {
+	longText := "A message that is long enough to fingerprint"
+
+	tests := []struct {
+		name string
+		in   entity.ContentCheck
+		want bool
+	}{
+		{name: "New comment", in: entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: longText}, want: true},
+		{name: "Edit", in: entity.ContentCheck{UserID: 1, Kind: entity.ContentKindPost, Title: "Title", Text: longText, Edit: true}},
+		{name: "Short reply", in: entity.ContentCheck{UserID: 1, Kind: entity.ContentKindMessage, Text: "+1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorded := false
+			repo := &MockContentFilterRepository{
+				RecordContentFunc: func(ctx context.Context, userID int64, kind entity.ContentKind, fingerprint string) error {
+					recorded = true
+					assert.Equal(t, tt.in.UserID, userID)
+					assert.Equal(t, tt.in.Kind, kind)
+					return nil
+				},
+			}
+			uc := NewContentFilterUsecase(repo, nil, nil)
+
+			uc.Record(context.Background(), tt.in)
+			assert.Equal(t, tt.want, recorded)
+		})
+	}
+}
+
+func TestContentFilterUsecase_RulesCache(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	loads := 0
+	repo := &MockContentFilterRepository{
+		GetFilterRulesFunc: func(ctx context.Context) ([]entity.FilterRule, error) {
+			loads++
+			return []entity.FilterRule{{ID: 1, Pattern: "darn", Action: entity.FilterActionMask}}, nil
+		},
+	}
+	uc := NewContentFilterUsecase(repo, sessionAuth(1, "admin"), nil)
+	uc.now = func() time.Time { return now }
+	uc.DuplicateWindow = 0
+	check := entity.ContentCheck{UserID: 1, Kind: entity.ContentKindComment, Text: "darn"}
+
+	for i := 0; i < 3; i++ {
+		verdict, err := uc.Check(context.Background(), check)
+		require.NoError(t, err)
+		assert.Equal(t, "****", verdict.Text)
+	}
+	assert.Equal(t, 1, loads)
+
+	// Новое правило применяется сразу, не дожидаясь TTL
+	_, err := uc.CreateRule(context.Background(), "token", entity.FilterRuleInput{Pattern: "heck", Action: entity.FilterActionMask})
+	require.NoError(t, err)
+	_, _ = uc.Check(context.Background(), check)
+	assert.Equal(t, 2, loads)
+
+	now = now.Add(DefaultFilterRulesTTL)
+	_, _ = uc.Check(context.Background(), check)
+	assert.Equal(t, 3, loads)
+}
+
+func TestContentFilterUsecase_CreateRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		in      entity.FilterRuleInput
+		wantErr error
+	}{
+		{name: "Word", role: "admin", in: entity.FilterRuleInput{Pattern: " casino ", Action: "HOLD"}},
+		{name: "Regex", role: "admin", in: entity.FilterRuleInput{Pattern: `buy\s+followers`, IsRegex: true, Action: entity.FilterActionReject}},
+		{name: "Invalid regex", role: "admin", in: entity.FilterRuleInput{Pattern: `buy(`, IsRegex: true, Action: entity.FilterActionReject}, wantErr: entity.ErrInvalidFilterRule},
+		{name: "Invalid action", role: "admin", in: entity.FilterRuleInput{Pattern: "casino", Action: "ban"}, wantErr: entity.ErrInvalidFilterRule},
+		{name: "Moderator", role: "moderator", in: entity.FilterRuleInput{Pattern: "casino", Action: entity.FilterActionHold}, wantErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *entity.FilterRule
+			repo := &MockContentFilterRepository{
+				CreateFilterRuleFunc: func(ctx context.Context, rule *entity.FilterRule) error {
+					rule.ID = 4
+					saved = rule
+					return nil
+				},
+			}
+			uc := NewContentFilterUsecase(repo, sessionAuth(2, tt.role), nil)
+
+			rule, err := uc.CreateRule(context.Background(), "token", tt.in)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, saved, rule)
+			assert.Equal(t, int64(2), rule.CreatedBy)
+			assert.NotContains(t, rule.Pattern, " ")
+			assert.True(t, rule.Action.Valid())
+		})
+	}
+}
+
+func TestContentFilterUsecase_DeleteRule(t *testing.T) {
+	repo := &MockContentFilterRepository{
+		DeleteFilterRuleFunc: func(ctx context.Context, id int64) error {
+			if id != 1 {
+				return entity.ErrFilterRuleNotFound
+			}
+			return nil
+		},
+	}
+
+	uc := NewContentFilterUsecase(repo, sessionAuth(2, "admin"), nil)
+	assert.NoError(t, uc.DeleteRule(context.Background(), "token", 1))
+	assert.ErrorIs(t, uc.DeleteRule(context.Background(), "token", 2), entity.ErrFilterRuleNotFound)
+
+	uc = NewContentFilterUsecase(repo, sessionAuth(2, "user"), nil)
+	assert.ErrorIs(t, uc.DeleteRule(context.Background(), "token", 1), ErrForbidden)
+}
+
+func TestContentFilterUsecase_LearnAndLoadModel(t *testing.T) {
+	var saved []entity.SpamSample
+	repo := &MockContentFilterRepository{
+		SaveSpamSampleFunc: func(ctx context.Context, content string, isSpam bool) error {
+			saved = append(saved, entity.SpamSample{Content: content, IsSpam: isSpam})
+			return nil
+		},
+		GetSpamSamplesFunc: func(ctx context.Context, limit int) ([]entity.SpamSample, error) {
+			assert.Equal(t, MaxSpamSamples, limit)
+			return []entity.SpamSample{{Content: "cheap pills", IsSpam: true}, {Content: "nice post", IsSpam: false}}, nil
+		},
+	}
+	classifier := &fixedClassifier{}
+	uc := NewContentFilterUsecase(repo, nil, nil)
+	uc.Classifier = classifier
+
+	n, err := uc.LoadModel(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	uc.Learn(context.Background(), "buy now", true)
+	assert.Equal(t, []entity.SpamSample{{Content: "buy now", IsSpam: true}}, saved)
+	assert.Len(t, classifier.trained, 3)
+
+	// Без классификатора примеры не копятся
+	uc.Classifier = nil
+	uc.Learn(context.Background(), "buy now", true)
+	assert.Len(t, saved, 1)
+}
+
+// recordingChecker - ContentChecker с заданным решением, запоминающий сохраненный и отложенный контент
+type recordingChecker struct {
+	verdict  entity.FilterVerdict
+	checked  []entity.ContentCheck
+	recorded []entity.ContentCheck
+	held     []entity.ReportTarget
+	holdErr  error
+}
+
+func (c *recordingChecker) Check(ctx context.Context, in entity.ContentCheck) (*entity.FilterVerdict, error) {
+	c.checked = append(c.checked, in)
+	verdict := c.verdict
+	if verdict.Text == "" {
+		verdict.Title, verdict.Text = in.Title, in.Text
+	}
+	return &verdict, nil
+}
+
+func (c *recordingChecker) Record(ctx context.Context, in entity.ContentCheck) {
+	c.recorded = append(c.recorded, in)
+}
+
+func (c *recordingChecker) Hold(ctx context.Context, targetType entity.ReportTarget, targetID, authorID int64, reason string) error {
+	c.held = append(c.held, targetType)
+	return c.holdErr
+}
+
+type recordingMentions struct {
+	calls int
+}
+
+func (m *recordingMentions) ProcessMentions(ctx context.Context, source entity.MentionSource, sourceID, postID, authorID int64, text string) ([]entity.Mention, error) {
+	m.calls++
+	return nil, nil
+}
+
+func TestPostAndCommentContentFilter(t *testing.T) {
+	auth := sessionAuth(7, "user")
+	auth.GetUserProfileFunc = func(ctx context.Context, in *pb.GetUserProfileRequest, opts ...grpc.CallOption) (*pb.GetUserProfileResponse, error) {
+		return &pb.GetUserProfileResponse{User: &pb.User{Id: in.UserId, Username: "user"}}, nil
+	}
+	var savedPost *entity.Post
+	var savedHeld bool
+	postRepo := &MockPostRepository{
+		CreatePostFunc: func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
+			savedPost = post
+			savedHeld = post.Held
+			return 3, nil
+		},
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id, AuthorID: 1}, nil
+		},
+		UpdatePostFunc: func(ctx context.Context, postID, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error) {
+			savedHeld = held
+			return &entity.Post{ID: postID, AuthorID: authorID, Title: title, Content: content, Held: held}, nil
+		},
+	}
+	var savedComment *entity.Comment
+	commentRepo := &MockCommentRepository{
+		CreateCommentFunc: func(ctx context.Context, comment *entity.Comment) error {
+			comment.ID = 10
+			savedHeld = comment.Held
+			return nil
+		},
+		GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+			return &entity.Comment{ID: id, AuthorID: 7, PostID: 3, CreatedAt: time.Now()}, nil
+		},
+		UpdateCommentFunc: func(ctx context.Context, id, editorID int64, content, contentHTML string, held bool) (*entity.Comment, error) {
+			savedComment = &entity.Comment{ID: id, AuthorID: 7, Content: content, ContentHTML: contentHTML, Held: held}
+			return savedComment, nil
+		},
+	}
+
+	t.Run("Masked post", func(t *testing.T) {
+		filter := &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionMask, Title: "**** title", Text: "**** text"}}
+		uc := NewPostUsecase(postRepo, auth, nil)
+		uc.Filter = filter
+
+		post, err := uc.CreatePost(context.Background(), "token", "darn title", "darn text", nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "**** title", post.Title)
+		assert.Equal(t, "**** text", savedPost.Content)
+		assert.Contains(t, savedPost.ContentHTML, "**** text")
+		assert.False(t, post.Held)
+		assert.Equal(t, []entity.ContentCheck{{UserID: 7, Kind: entity.ContentKindPost, Title: "darn title", Text: "darn text"}}, filter.checked)
+		assert.Equal(t, filter.checked, filter.recorded)
+	})
+
+	t.Run("Rejected post is not saved", func(t *testing.T) {
+		savedPost = nil
+		filter := &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionReject, Reason: "banned word"}}
+		uc := NewPostUsecase(postRepo, auth, nil)
+		uc.Filter = filter
+
+		_, err := uc.CreatePost(context.Background(), "token", "title", "text", nil, nil, nil)
+		assert.ErrorIs(t, err, entity.ErrContentRejected)
+		assert.Nil(t, savedPost)
+		assert.Empty(t, filter.recorded)
+	})
+
+	t.Run("Held post skips mentions", func(t *testing.T) {
+		filter := &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionHold, Reason: entity.FilterReasonLinks}}
+		mentions := &recordingMentions{}
+		uc := NewPostUsecase(postRepo, auth, nil)
+		uc.Filter = filter
+		uc.Mentions = mentions
+
+		post, err := uc.CreatePost(context.Background(), "token", "title", "hi @alice", nil, nil, nil)
+		require.NoError(t, err)
+		assert.True(t, post.Held)
+		assert.True(t, savedHeld, "held post must be inserted hidden")
+		assert.Equal(t, []entity.ReportTarget{entity.ReportTargetPost}, filter.held)
+		assert.Zero(t, mentions.calls)
+	})
+
+	t.Run("Edited post is checked as edit", func(t *testing.T) {
+		filter := &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionHold, Reason: "banned word"}}
+		uc := NewPostUsecase(postRepo, auth, nil)
+		uc.Filter = filter
+
+		post, err := uc.UpdatePost(context.Background(), "token", 3, "title", "casino")
+		require.NoError(t, err)
+		assert.True(t, post.Held)
+		assert.True(t, filter.checked[0].Edit)
+		assert.Empty(t, filter.recorded)
+		assert.Equal(t, []entity.ReportTarget{entity.ReportTargetPost}, filter.held)
+	})
+
+	t.Run("Edited post fails when hold report fails", func(t *testing.T) {
+		errReport := errors.New("report insert failed")
+		filter := &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionHold, Reason: "banned word"}, holdErr: errReport}
+		uc := NewPostUsecase(postRepo, auth, nil)
+		uc.Filter = filter
+
+		savedHeld = false
+		_, err := uc.UpdatePost(context.Background(), "token", 3, "title", "casino")
+		assert.ErrorIs(t, err, errReport)
+		assert.True(t, savedHeld, "held edit must be hidden by the update itself")
+	})
+
+	t.Run("Rejected comment edit is not saved", func(t *testing.T) {
+		savedComment = nil
+		filter := &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionReject, Reason: "banned word"}}
+		uc := NewCommentUseCase(commentRepo, postRepo, auth)
+		uc.Filter = filter
+
+		_, err := uc.EditComment(context.Background(), 10, 7, "user", "darn")
+		assert.ErrorIs(t, err, entity.ErrContentRejected)
+		assert.Nil(t, savedComment)
+		assert.Empty(t, filter.held)
+	})
+
+	t.Run("Masked comment edit", func(t *testing.T) {
+		filter := &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionMask, Text: "**** text"}}
+		uc := NewCommentUseCase(commentRepo, postRepo, auth)
+		uc.Filter = filter
+
+		comment, err := uc.EditComment(context.Background(), 10, 7, "user", "darn text")
+		require.NoError(t, err)
+		assert.Equal(t, "**** text", comment.Content)
+		assert.Contains(t, savedComment.ContentHTML, "**** text")
+		assert.False(t, comment.Held)
+		assert.Equal(t, []entity.ContentCheck{{UserID: 7, Kind: entity.ContentKindComment, Text: "darn text", Edit: true}}, filter.checked)
+		assert.Empty(t, filter.recorded)
+		assert.Empty(t, filter.held)
+	})
+
+	t.Run("Held comment edit", func(t *testing.T) {
+		filter := &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionHold, Reason: entity.FilterReasonLinks}}
+		uc := NewCommentUseCase(commentRepo, postRepo, auth)
+		uc.Filter = filter
+
+		comment, err := uc.EditComment(context.Background(), 10, 7, "user", "http://a http://b")
+		require.NoError(t, err)
+		assert.True(t, comment.Held, "held edit must be hidden by the update itself")
+		assert.Equal(t, []entity.ReportTarget{entity.ReportTargetComment}, filter.held)
+	})
+
+	t.Run("Held comment skips mentions", func(t *testing.T) {
+		filter := &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionHold, Reason: entity.FilterReasonClassifier}}
+		mentions := &recordingMentions{}
+		uc := NewCommentUseCase(commentRepo, postRepo, auth)
+		uc.Filter = filter
+		uc.Mentions = mentions
+
+		comment := &entity.Comment{PostID: 3, AuthorID: 7, Content: "hi @alice"}
+		require.NoError(t, uc.CreateComment(context.Background(), comment))
+		assert.True(t, comment.Held)
+		assert.True(t, savedHeld, "held comment must be inserted hidden")
+		assert.Len(t, filter.recorded, 1)
+		assert.Equal(t, []entity.ReportTarget{entity.ReportTargetComment}, filter.held)
+		assert.Zero(t, mentions.calls)
+	})
+
+	t.Run("Duplicate comment", func(t *testing.T) {
+		uc := NewCommentUseCase(commentRepo, postRepo, auth)
+		uc.Filter = &recordingChecker{verdict: entity.FilterVerdict{Action: entity.FilterActionReject, Reason: entity.FilterReasonDuplicate}}
+
+		err := uc.CreateComment(context.Background(), &entity.Comment{PostID: 3, AuthorID: 7, Content: "same again"})
+		assert.ErrorIs(t, err, entity.ErrDuplicateContent)
+	})
+}
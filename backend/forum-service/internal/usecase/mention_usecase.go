@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/mention"
+)
+
+// MentionProcessor находит @упоминания в тексте, сохраняет их и уведомляет упомянутых
+type MentionProcessor interface {
+	ProcessMentions(ctx context.Context, source entity.MentionSource, sourceID, postID, authorID int64, content string) ([]entity.Mention, error)
+}
+
+// MentionNotifier получает событие о новых упоминаниях
+type MentionNotifier interface {
+	NotifyMentions(ctx context.Context, mentions []entity.Mention)
+}
+
+type MentionUsecase struct {
+	mentionRepo repository.MentionRepository
+	authClient  pb.AuthServiceClient
+	notifier    MentionNotifier
+	logger      *logger.Logger
+}
+
+func NewMentionUsecase(
+	mentionRepo repository.MentionRepository,
+	authClient pb.AuthServiceClient,
+	notifier MentionNotifier,
+	logger *logger.Logger,
+) *MentionUsecase {
+	return &MentionUsecase{
+		mentionRepo: mentionRepo,
+		authClient:  authClient,
+		notifier:    notifier,
+		logger:      logger,
+	}
+}
+
+// ProcessMentions разрешает имена через Auth Service; несуществующие имена и упоминание
+// самого себя пропускаются. Возвращает только впервые сохраненные упоминания.
+func (uc *MentionUsecase) ProcessMentions(
+	ctx context.Context,
+	source entity.MentionSource,
+	sourceID,
+	postID,
+	authorID int64,
+	content string,
+) ([]entity.Mention, error) {
+	names := mention.Parse(content)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	resp, err := uc.authClient.GetUsersByUsernames(ctx, &pb.GetUsersByUsernamesRequest{Usernames: names})
+	if err != nil {
+		uc.logError("Failed to resolve mentions", err)
+		return nil, err
+	}
+
+	mentions := make([]entity.Mention, 0, len(resp.GetUsers()))
+	for _, user := range resp.GetUsers() {
+		if user.Id == authorID {
+			continue
+		}
+		mentions = append(mentions, entity.Mention{
+			SourceType:        source,
+			SourceID:          sourceID,
+			PostID:            postID,
+			AuthorID:          authorID,
+			MentionedUserID:   user.Id,
+			MentionedUsername: user.Username,
+		})
+	}
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+
+	created, err := uc.mentionRepo.CreateMentions(ctx, mentions)
+	if err != nil {
+		uc.logError("Failed to save mentions", err)
+		return nil, err
+	}
+
+	// Имена в базе не хранятся - переносим их из ответа Auth Service
+	usernames := make(map[int64]string, len(mentions))
+	for _, m := range mentions {
+		usernames[m.MentionedUserID] = m.MentionedUsername
+	}
+	for i := range created {
+		created[i].MentionedUsername = usernames[created[i].MentionedUserID]
+	}
+
+	if uc.notifier != nil && len(created) > 0 {
+		uc.notifier.NotifyMentions(ctx, created)
+	}
+	return created, nil
+}
+
+func (uc *MentionUsecase) logError(msg string, err error) {
+	if uc.logger != nil {
+		uc.logger.Error(msg, err)
+	}
+}
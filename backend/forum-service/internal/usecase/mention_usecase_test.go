@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type MockMentionRepository struct {
+	CreateMentionsFunc func(ctx context.Context, mentions []entity.Mention) ([]entity.Mention, error)
+}
+
+func (m *MockMentionRepository) CreateMentions(ctx context.Context, mentions []entity.Mention) ([]entity.Mention, error) {
+	return m.CreateMentionsFunc(ctx, mentions)
+}
+
+type recordingNotifier struct {
+	mentions []entity.Mention
+}
+
+func (n *recordingNotifier) NotifyMentions(ctx context.Context, mentions []entity.Mention) {
+	n.mentions = append(n.mentions, mentions...)
+}
+
+func usersAuth(users ...*pb.User) *MockAuthServiceClient {
+	return &MockAuthServiceClient{
+		GetUsersByUsernamesFunc: func(ctx context.Context, in *pb.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*pb.GetUsersByUsernamesResponse, error) {
+			return &pb.GetUsersByUsernamesResponse{Users: users}, nil
+		},
+	}
+}
+
+func TestMentionUsecase_ProcessMentions(t *testing.T) {
+	t.Run("Resolved users are stored and notified", func(t *testing.T) {
+		var requested []string
+		auth := &MockAuthServiceClient{
+			GetUsersByUsernamesFunc: func(ctx context.Context, in *pb.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*pb.GetUsersByUsernamesResponse, error) {
+				requested = in.Usernames
+				return &pb.GetUsersByUsernamesResponse{Users: []*pb.User{{Id: 1, Username: "author"}, {Id: 2, Username: "alice"}}}, nil
+			},
+		}
+		repo := &MockMentionRepository{
+			CreateMentionsFunc: func(ctx context.Context, mentions []entity.Mention) ([]entity.Mention, error) {
+				// Упоминание самого себя отбрасывается
+				assert.Len(t, mentions, 1)
+				assert.Equal(t, entity.MentionSourceComment, mentions[0].SourceType)
+				assert.Equal(t, int64(10), mentions[0].SourceID)
+				assert.Equal(t, int64(3), mentions[0].PostID)
+				created := mentions[0]
+				created.ID = 7
+				created.MentionedUsername = ""
+				return []entity.Mention{created}, nil
+			},
+		}
+		notifier := &recordingNotifier{}
+		uc := NewMentionUsecase(repo, auth, notifier, nil)
+
+		got, err := uc.ProcessMentions(context.Background(), entity.MentionSourceComment, 10, 3, 1, "hi @alice and @author, also @ghost")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"alice", "author", "ghost"}, requested)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, int64(2), got[0].MentionedUserID)
+			assert.Equal(t, "alice", got[0].MentionedUsername)
+		}
+		assert.Equal(t, got, notifier.mentions)
+	})
+
+	t.Run("No mentions skips lookup", func(t *testing.T) {
+		auth := &MockAuthServiceClient{
+			GetUsersByUsernamesFunc: func(ctx context.Context, in *pb.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*pb.GetUsersByUsernamesResponse, error) {
+				t.Fatal("auth service must not be called")
+				return nil, nil
+			},
+		}
+		uc := NewMentionUsecase(&MockMentionRepository{}, auth, nil, nil)
+
+		got, err := uc.ProcessMentions(context.Background(), entity.MentionSourcePost, 1, 1, 1, "no mentions here, mail me at a@b.com")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("Already stored mentions are not notified again", func(t *testing.T) {
+		repo := &MockMentionRepository{
+			CreateMentionsFunc: func(ctx context.Context, mentions []entity.Mention) ([]entity.Mention, error) {
+				return []entity.Mention{}, nil
+			},
+		}
+		notifier := &recordingNotifier{}
+		uc := NewMentionUsecase(repo, usersAuth(&pb.User{Id: 2, Username: "alice"}), notifier, nil)
+
+		_, err := uc.ProcessMentions(context.Background(), entity.MentionSourcePost, 1, 1, 1, "@alice")
+		assert.NoError(t, err)
+		assert.Empty(t, notifier.mentions)
+	})
+
+	t.Run("Auth service error", func(t *testing.T) {
+		auth := &MockAuthServiceClient{
+			GetUsersByUsernamesFunc: func(ctx context.Context, in *pb.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*pb.GetUsersByUsernamesResponse, error) {
+				return nil, errors.New("unavailable")
+			},
+		}
+		uc := NewMentionUsecase(&MockMentionRepository{}, auth, nil, nil)
+
+		_, err := uc.ProcessMentions(context.Background(), entity.MentionSourcePost, 1, 1, 1, "@alice")
+		assert.Error(t, err)
+	})
+}
+
+func TestCommentUseCase_CreateComment_Mentions(t *testing.T) {
+	var saved []entity.Mention
+	mentions := NewMentionUsecase(&MockMentionRepository{
+		CreateMentionsFunc: func(ctx context.Context, mentions []entity.Mention) ([]entity.Mention, error) {
+			saved = mentions
+			return mentions, nil
+		},
+	}, usersAuth(&pb.User{Id: 2, Username: "alice"}), nil, nil)
+
+	auth := &MockAuthServiceClient{
+		GetUserProfileFunc: func(ctx context.Context, in *pb.GetUserProfileRequest, opts ...grpc.CallOption) (*pb.GetUserProfileResponse, error) {
+			return &pb.GetUserProfileResponse{User: &pb.User{Username: "testuser"}}, nil
+		},
+	}
+	uc := NewCommentUseCase(&MockCommentRepository{
+		CreateCommentFunc: func(ctx context.Context, comment *entity.Comment) error {
+			comment.ID = 42
+			return nil
+		},
+	}, &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id}, nil
+		},
+	}, auth)
+	uc.Mentions = mentions
+
+	comment := &entity.Comment{PostID: 5, AuthorID: 1, Content: "cc @alice"}
+	assert.NoError(t, uc.CreateComment(context.Background(), comment))
+	assert.Contains(t, comment.ContentHTML, `href="/users/alice"`)
+	if assert.Len(t, saved, 1) {
+		assert.Equal(t, int64(42), saved[0].SourceID)
+		assert.Equal(t, int64(5), saved[0].PostID)
+		assert.Equal(t, int64(2), saved[0].MentionedUserID)
+	}
+}
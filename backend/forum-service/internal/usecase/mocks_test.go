@@ -11,23 +11,23 @@ import (
 )
 
 type MockPostRepository struct {
-	CreatePostFunc  func(ctx context.Context, post *entity.Post) (int64, error)
-	GetPostsFunc    func(ctx context.Context) ([]*entity.Post, error)
+	CreatePostFunc  func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error)
+	GetPostsFunc    func(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, error)
 	GetPostByIDFunc func(ctx context.Context, id int64) (*entity.Post, error)
-	DeletePostFunc  func(ctx context.Context, postID, authorID int64, role string) error
-	UpdatePostFunc  func(ctx context.Context, postID, authorID int64, role, title, content string) (*entity.Post, error)
+	DeletePostFunc  func(ctx context.Context, postID, authorID int64, role, reason string) error
+	UpdatePostFunc  func(ctx context.Context, postID, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error)
 }
 
-func (m *MockPostRepository) CreatePost(ctx context.Context, post *entity.Post) (int64, error) {
+func (m *MockPostRepository) CreatePost(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
 	if m.CreatePostFunc != nil {
-		return m.CreatePostFunc(ctx, post)
+		return m.CreatePostFunc(ctx, post, poll)
 	}
 	return 0, nil
 }
 
-func (m *MockPostRepository) GetPosts(ctx context.Context) ([]*entity.Post, error) {
+func (m *MockPostRepository) GetPosts(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, error) {
 	if m.GetPostsFunc != nil {
-		return m.GetPostsFunc(ctx)
+		return m.GetPostsFunc(ctx, filter)
 	}
 	return nil, nil
 }
@@ -39,16 +39,21 @@ func (m *MockPostRepository) GetPostByID(ctx context.Context, id int64) (*entity
 	return nil, nil
 }
 
-func (m *MockPostRepository) DeletePost(ctx context.Context, postID, authorID int64, role string) error {
+// GetPostForModeration отвечает так же, как GetPostByID
+func (m *MockPostRepository) GetPostForModeration(ctx context.Context, id int64) (*entity.Post, error) {
+	return m.GetPostByID(ctx, id)
+}
+
+func (m *MockPostRepository) DeletePost(ctx context.Context, postID, authorID int64, role, reason string) error {
 	if m.DeletePostFunc != nil {
-		return m.DeletePostFunc(ctx, postID, authorID, role)
+		return m.DeletePostFunc(ctx, postID, authorID, role, reason)
 	}
 	return nil
 }
 
-func (m *MockPostRepository) UpdatePost(ctx context.Context, postID, authorID int64, role, title, content string) (*entity.Post, error) {
+func (m *MockPostRepository) UpdatePost(ctx context.Context, postID, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error) {
 	if m.UpdatePostFunc != nil {
-		return m.UpdatePostFunc(ctx, postID, authorID, role, title, content)
+		return m.UpdatePostFunc(ctx, postID, authorID, role, title, content, contentHTML, held)
 	}
 	return nil, nil
 }
@@ -63,6 +68,8 @@ type MockAuthServiceClient struct {
 	SignInFunc        func(ctx context.Context, in *pb.SignInRequest, opts ...grpc.CallOption) (*pb.SignInResponse, error)
 	SignUpFunc        func(ctx context.Context, in *pb.SignUpRequest, opts ...grpc.CallOption) (*pb.SignUpResponse, error)
 	ValidateSessionFunc func(ctx context.Context, in *pb.ValidateSessionRequest, opts ...grpc.CallOption) (*pb.ValidateSessionResponse, error)
+	GetUsersByUsernamesFunc func(ctx context.Context, in *pb.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*pb.GetUsersByUsernamesResponse, error)
+	SuspendUserFunc         func(ctx context.Context, in *pb.SuspendUserRequest, opts ...grpc.CallOption) (*pb.SuspendUserResponse, error)
 }
 
 func (m *MockAuthServiceClient) ValidateToken(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateSessionResponse, error) {
@@ -126,4 +133,18 @@ func (m *MockAuthServiceClient) ValidateSession(ctx context.Context, in *pb.Vali
 		return m.ValidateSessionFunc(ctx, in, opts...)
 	}
 	return nil, nil
+}
+
+func (m *MockAuthServiceClient) GetUsersByUsernames(ctx context.Context, in *pb.GetUsersByUsernamesRequest, opts ...grpc.CallOption) (*pb.GetUsersByUsernamesResponse, error) {
+	if m.GetUsersByUsernamesFunc != nil {
+		return m.GetUsersByUsernamesFunc(ctx, in, opts...)
+	}
+	return &pb.GetUsersByUsernamesResponse{}, nil
+}
+
+func (m *MockAuthServiceClient) SuspendUser(ctx context.Context, in *pb.SuspendUserRequest, opts ...grpc.CallOption) (*pb.SuspendUserResponse, error) {
+	if m.SuspendUserFunc != nil {
+		return m.SuspendUserFunc(ctx, in, opts...)
+	}
+	return &pb.SuspendUserResponse{UserId: in.UserId, SuspendedUntil: in.Until}, nil
 }
\ No newline at end of file
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+)
+
+const (
+	DefaultModerationLogLimit = 50
+	MaxModerationLogLimit     = 200
+)
+
+type ModerationUsecaseInterface interface {
+	SetPostFlags(ctx context.Context, token string, postID int64, update entity.PostFlagsUpdate) (*entity.PostFlags, error)
+	GetModerationLog(ctx context.Context, token string, filter entity.ModerationLogFilter) ([]entity.ModerationLogEntry, error)
+}
+
+type ModerationUsecase struct {
+	moderationRepo repository.ModerationRepository
+	authClient     pb.AuthServiceClient
+}
+
+func NewModerationUsecase(moderationRepo repository.ModerationRepository, authClient pb.AuthServiceClient) *ModerationUsecase {
+	return &ModerationUsecase{
+		moderationRepo: moderationRepo,
+		authClient:     authClient,
+	}
+}
+
+// SetPostFlags закрепляет и закрывает посты (модераторы) и делает их объявлениями (только администраторы).
+// Каждое фактическое изменение попадает в журнал модерации.
+func (uc *ModerationUsecase) SetPostFlags(ctx context.Context, token string, postID int64, update entity.PostFlagsUpdate) (*entity.PostFlags, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+	if update.IsEmpty() {
+		return nil, entity.ErrEmptyFlagUpdate
+	}
+	if !isModerator(session.UserRole) {
+		return nil, ErrForbidden
+	}
+	// Объявление видно в начале каждой ленты, поэтому его назначают только администраторы
+	if update.Announcement != nil && !isAdmin(session.UserRole) {
+		return nil, ErrForbidden
+	}
+
+	return uc.moderationRepo.SetPostFlags(ctx, postID, session.UserId, update)
+}
+
+// GetModerationLog возвращает журнал модерации. Доступно только модераторам.
+func (uc *ModerationUsecase) GetModerationLog(ctx context.Context, token string, filter entity.ModerationLogFilter) ([]entity.ModerationLogEntry, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+	if !isModerator(session.UserRole) {
+		return nil, ErrForbidden
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultModerationLogLimit
+	}
+	if filter.Limit > MaxModerationLogLimit {
+		filter.Limit = MaxModerationLogLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return uc.moderationRepo.GetModerationLog(ctx, filter)
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockModerationRepository struct {
+	SetPostFlagsFunc     func(ctx context.Context, postID, actorID int64, update entity.PostFlagsUpdate) (*entity.PostFlags, error)
+	GetModerationLogFunc func(ctx context.Context, filter entity.ModerationLogFilter) ([]entity.ModerationLogEntry, error)
+}
+
+func (m *MockModerationRepository) SetPostFlags(ctx context.Context, postID, actorID int64, update entity.PostFlagsUpdate) (*entity.PostFlags, error) {
+	return m.SetPostFlagsFunc(ctx, postID, actorID, update)
+}
+
+func (m *MockModerationRepository) GetModerationLog(ctx context.Context, filter entity.ModerationLogFilter) ([]entity.ModerationLogEntry, error) {
+	return m.GetModerationLogFunc(ctx, filter)
+}
+
+func TestModerationUsecase_SetPostFlags(t *testing.T) {
+	on := true
+	var gotActor int64
+	repo := &MockModerationRepository{
+		SetPostFlagsFunc: func(ctx context.Context, postID, actorID int64, update entity.PostFlagsUpdate) (*entity.PostFlags, error) {
+			gotActor = actorID
+			flags := &entity.PostFlags{}
+			update.Apply(flags)
+			return flags, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		role    string
+		update  entity.PostFlagsUpdate
+		wantErr error
+	}{
+		{name: "Moderator pins", role: "moderator", update: entity.PostFlagsUpdate{Pinned: &on, Locked: &on}},
+		{name: "Admin announces", role: "admin", update: entity.PostFlagsUpdate{Announcement: &on}},
+		{name: "Moderator cannot announce", role: "moderator", update: entity.PostFlagsUpdate{Announcement: &on}, wantErr: ErrForbidden},
+		{name: "User cannot lock", role: "user", update: entity.PostFlagsUpdate{Locked: &on}, wantErr: ErrForbidden},
+		{name: "Empty update", role: "admin", wantErr: entity.ErrEmptyFlagUpdate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewModerationUsecase(repo, sessionAuth(5, tt.role))
+
+			flags, err := uc.SetPostFlags(context.Background(), "token", 1, tt.update)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(5), gotActor)
+			assert.Equal(t, tt.update.Pinned != nil, flags.Pinned)
+			assert.Equal(t, tt.update.Announcement != nil, flags.Announcement)
+		})
+	}
+}
+
+func TestModerationUsecase_GetModerationLog(t *testing.T) {
+	var got entity.ModerationLogFilter
+	repo := &MockModerationRepository{
+		GetModerationLogFunc: func(ctx context.Context, filter entity.ModerationLogFilter) ([]entity.ModerationLogEntry, error) {
+			got = filter
+			return []entity.ModerationLogEntry{}, nil
+		},
+	}
+
+	uc := NewModerationUsecase(repo, sessionAuth(5, "moderator"))
+	_, err := uc.GetModerationLog(context.Background(), "token", entity.ModerationLogFilter{Limit: 1000, Offset: -1})
+	assert.NoError(t, err)
+	assert.Equal(t, MaxModerationLogLimit, got.Limit)
+	assert.Equal(t, 0, got.Offset)
+
+	uc = NewModerationUsecase(repo, sessionAuth(6, "user"))
+	_, err = uc.GetModerationLog(context.Background(), "token", entity.ModerationLogFilter{})
+	assert.ErrorIs(t, err, ErrForbidden)
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+)
+
+const (
+	DefaultNotificationsLimit = 20
+	MaxNotificationsLimit     = 100
+)
+
+// NotificationPusher доставляет событие пользователю, который сейчас в сети
+type NotificationPusher interface {
+	Push(ctx context.Context, userID int64, event interface{}) error
+}
+
+// CommentNotifier уведомляет автора поста и автора родительского комментария об ответе
+type CommentNotifier interface {
+	NotifyComment(ctx context.Context, post *entity.Post, comment *entity.Comment, parent *entity.Comment)
+}
+
+// ReactionNotifier уведомляет автора поста о положительной оценке
+type ReactionNotifier interface {
+	NotifyReaction(ctx context.Context, actorID, postID int64)
+}
+
+type NotificationUsecaseInterface interface {
+	GetNotifications(ctx context.Context, token string, filter entity.NotificationFilter) (*entity.NotificationList, error)
+	MarkRead(ctx context.Context, token string, id int64) error
+	MarkAllRead(ctx context.Context, token string) (int64, error)
+}
+
+// NotificationEvent - кадр, который получает клиент по WebSocket
+type NotificationEvent struct {
+	Type         string              `json:"type"`
+	Notification entity.Notification `json:"notification"`
+	UnreadCount  int                 `json:"unread_count"`
+}
+
+type NotificationUsecase struct {
+	notificationRepo repository.NotificationRepository
+	postRepo         repository.PostRepository
+	authClient       pb.AuthServiceClient
+	pusher           NotificationPusher
+	logger           *logger.Logger
+}
+
+// NewNotificationUsecase создает usecase уведомлений; при pusher = nil уведомления только сохраняются
+func NewNotificationUsecase(
+	notificationRepo repository.NotificationRepository,
+	postRepo repository.PostRepository,
+	authClient pb.AuthServiceClient,
+	pusher NotificationPusher,
+	logger *logger.Logger,
+) *NotificationUsecase {
+	return &NotificationUsecase{
+		notificationRepo: notificationRepo,
+		postRepo:         postRepo,
+		authClient:       authClient,
+		pusher:           pusher,
+		logger:           logger,
+	}
+}
+
+func (uc *NotificationUsecase) GetNotifications(ctx context.Context, token string, filter entity.NotificationFilter) (*entity.NotificationList, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultNotificationsLimit
+	}
+	if filter.Limit > MaxNotificationsLimit {
+		filter.Limit = MaxNotificationsLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	notifications, err := uc.notificationRepo.GetNotifications(ctx, session.UserId, filter)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := uc.notificationRepo.CountUnread(ctx, session.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.NotificationList{Notifications: notifications, UnreadCount: unread}, nil
+}
+
+func (uc *NotificationUsecase) MarkRead(ctx context.Context, token string, id int64) error {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return err
+	}
+	return uc.notificationRepo.MarkRead(ctx, session.UserId, id)
+}
+
+func (uc *NotificationUsecase) MarkAllRead(ctx context.Context, token string) (int64, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return 0, err
+	}
+	return uc.notificationRepo.MarkAllRead(ctx, session.UserId)
+}
+
+// NotifyMentions реализует MentionNotifier
+func (uc *NotificationUsecase) NotifyMentions(ctx context.Context, mentions []entity.Mention) {
+	for _, m := range mentions {
+		target := entity.NotificationTargetPost
+		if m.SourceType == entity.MentionSourceComment {
+			target = entity.NotificationTargetComment
+		}
+		uc.notify(ctx, entity.Notification{
+			RecipientID: m.MentionedUserID,
+			Type:        entity.NotificationMention,
+			ActorID:     m.AuthorID,
+			TargetType:  target,
+			TargetID:    m.SourceID,
+			PostID:      m.PostID,
+		})
+	}
+}
+
+// NotifyComment реализует CommentNotifier. Если автор поста и автор родительского
+// комментария совпадают, он получает одно уведомление - об ответе на комментарий.
+func (uc *NotificationUsecase) NotifyComment(ctx context.Context, post *entity.Post, comment *entity.Comment, parent *entity.Comment) {
+	n := entity.Notification{
+		ActorID:    comment.AuthorID,
+		TargetType: entity.NotificationTargetComment,
+		TargetID:   comment.ID,
+		PostID:     comment.PostID,
+	}
+
+	if parent != nil && parent.DeletedAt == nil {
+		reply := n
+		reply.RecipientID = parent.AuthorID
+		reply.Type = entity.NotificationReplyComment
+		uc.notify(ctx, reply)
+		if parent.AuthorID == post.AuthorID {
+			return
+		}
+	}
+
+	n.RecipientID = post.AuthorID
+	n.Type = entity.NotificationReplyPost
+	uc.notify(ctx, n)
+}
+
+// NotifyReaction реализует ReactionNotifier
+func (uc *NotificationUsecase) NotifyReaction(ctx context.Context, actorID, postID int64) {
+	post, err := uc.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		uc.logError("Failed to load post for reaction notification", err)
+		return
+	}
+
+	uc.notify(ctx, entity.Notification{
+		RecipientID: post.AuthorID,
+		Type:        entity.NotificationReaction,
+		ActorID:     actorID,
+		TargetType:  entity.NotificationTargetPost,
+		TargetID:    post.ID,
+		PostID:      post.ID,
+	})
+}
+
+// WarningEvent - кадр с предупреждением модератора, который получает клиент по WebSocket
+type WarningEvent struct {
+	Type    string         `json:"type"`
+	Warning entity.Warning `json:"warning"`
+}
+
+// NotifyWarning реализует ReportWarner. Предупреждение по посту или комментарию сохраняется
+// во входящих; по сообщению чата или профилю доставляется только пользователю в сети,
+// так как входящие привязаны к посту.
+func (uc *NotificationUsecase) NotifyWarning(ctx context.Context, w entity.Warning) {
+	if w.PostID != 0 {
+		target := entity.NotificationTargetPost
+		if w.TargetType == entity.ReportTargetComment {
+			target = entity.NotificationTargetComment
+		}
+		uc.notify(ctx, entity.Notification{
+			RecipientID: w.UserID,
+			Type:        entity.NotificationWarning,
+			ActorID:     w.ModeratorID,
+			TargetType:  target,
+			TargetID:    w.TargetID,
+			PostID:      w.PostID,
+		})
+		return
+	}
+
+	if uc.pusher == nil {
+		return
+	}
+	if err := uc.pusher.Push(ctx, w.UserID, WarningEvent{Type: "warning", Warning: w}); err != nil {
+		uc.logError("Failed to push warning", err)
+	}
+}
+
+// notify сохраняет уведомление и отправляет его получателю, если тот в сети.
+// Ошибки только логируются: уведомление не должно ломать действие, которое его вызвало.
+func (uc *NotificationUsecase) notify(ctx context.Context, n entity.Notification) {
+	if n.RecipientID == n.ActorID || n.RecipientID == 0 {
+		return
+	}
+
+	created, err := uc.notificationRepo.CreateNotification(ctx, &n)
+	if err != nil {
+		uc.logError("Failed to save notification", err)
+		return
+	}
+	if !created || uc.pusher == nil {
+		return
+	}
+
+	unread, err := uc.notificationRepo.CountUnread(ctx, n.RecipientID)
+	if err != nil {
+		uc.logError("Failed to count unread notifications", err)
+		return
+	}
+
+	event := NotificationEvent{Type: "notification", Notification: n, UnreadCount: unread}
+	if err := uc.pusher.Push(ctx, n.RecipientID, event); err != nil {
+		uc.logError("Failed to push notification", err)
+	}
+}
+
+func (uc *NotificationUsecase) logError(msg string, err error) {
+	if uc.logger != nil {
+		uc.logger.Error(msg, err)
+	}
+}
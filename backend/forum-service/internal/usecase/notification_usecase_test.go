@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockNotificationRepository struct {
+	CreateNotificationFunc func(ctx context.Context, n *entity.Notification) (bool, error)
+	GetNotificationsFunc   func(ctx context.Context, recipientID int64, filter entity.NotificationFilter) ([]entity.Notification, error)
+	CountUnreadFunc        func(ctx context.Context, recipientID int64) (int, error)
+	MarkReadFunc           func(ctx context.Context, recipientID, id int64) error
+	MarkAllReadFunc        func(ctx context.Context, recipientID int64) (int64, error)
+}
+
+func (m *MockNotificationRepository) CreateNotification(ctx context.Context, n *entity.Notification) (bool, error) {
+	return m.CreateNotificationFunc(ctx, n)
+}
+
+func (m *MockNotificationRepository) GetNotifications(ctx context.Context, recipientID int64, filter entity.NotificationFilter) ([]entity.Notification, error) {
+	return m.GetNotificationsFunc(ctx, recipientID, filter)
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, recipientID int64) (int, error) {
+	if m.CountUnreadFunc != nil {
+		return m.CountUnreadFunc(ctx, recipientID)
+	}
+	return 0, nil
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, recipientID, id int64) error {
+	return m.MarkReadFunc(ctx, recipientID, id)
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, recipientID int64) (int64, error) {
+	return m.MarkAllReadFunc(ctx, recipientID)
+}
+
+// storingNotificationRepository запоминает созданные уведомления
+func storingNotificationRepository(stored *[]entity.Notification) *MockNotificationRepository {
+	return &MockNotificationRepository{
+		CreateNotificationFunc: func(ctx context.Context, n *entity.Notification) (bool, error) {
+			n.ID = int64(len(*stored) + 1)
+			*stored = append(*stored, *n)
+			return true, nil
+		},
+	}
+}
+
+type recordingPusher struct {
+	userIDs []int64
+	events  []interface{}
+	err     error
+}
+
+func (p *recordingPusher) Push(ctx context.Context, userID int64, event interface{}) error {
+	p.userIDs = append(p.userIDs, userID)
+	p.events = append(p.events, event)
+	return p.err
+}
+
+func TestNotificationUsecase_NotifyComment(t *testing.T) {
+	post := &entity.Post{ID: 3, AuthorID: 1}
+
+	tests := []struct {
+		name   string
+		parent *entity.Comment
+		author int64
+		want   map[int64]entity.NotificationType
+	}{
+		{
+			name:   "Reply to post",
+			author: 2,
+			want:   map[int64]entity.NotificationType{1: entity.NotificationReplyPost},
+		},
+		{
+			name:   "Reply to someone else's comment",
+			parent: &entity.Comment{ID: 9, AuthorID: 4, PostID: 3},
+			author: 2,
+			want: map[int64]entity.NotificationType{
+				4: entity.NotificationReplyComment,
+				1: entity.NotificationReplyPost,
+			},
+		},
+		{
+			name:   "Post author gets one notification",
+			parent: &entity.Comment{ID: 9, AuthorID: 1, PostID: 3},
+			author: 2,
+			want:   map[int64]entity.NotificationType{1: entity.NotificationReplyComment},
+		},
+		{
+			name:   "Own post is not notified",
+			author: 1,
+			want:   map[int64]entity.NotificationType{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored []entity.Notification
+			pusher := &recordingPusher{}
+			uc := NewNotificationUsecase(storingNotificationRepository(&stored), &MockPostRepository{}, nil, pusher, nil)
+
+			comment := &entity.Comment{ID: 10, PostID: 3, AuthorID: tt.author}
+			uc.NotifyComment(context.Background(), post, comment, tt.parent)
+
+			got := map[int64]entity.NotificationType{}
+			for _, n := range stored {
+				got[n.RecipientID] = n.Type
+				assert.Equal(t, entity.NotificationTargetComment, n.TargetType)
+				assert.Equal(t, int64(10), n.TargetID)
+				assert.Equal(t, tt.author, n.ActorID)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, pusher.userIDs, len(tt.want))
+		})
+	}
+}
+
+func TestNotificationUsecase_NotifyReaction(t *testing.T) {
+	var stored []entity.Notification
+	pusher := &recordingPusher{}
+	repo := storingNotificationRepository(&stored)
+	repo.CountUnreadFunc = func(ctx context.Context, recipientID int64) (int, error) {
+		return 3, nil
+	}
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id, AuthorID: 1}, nil
+		},
+	}
+	uc := NewNotificationUsecase(repo, postRepo, nil, pusher, nil)
+
+	uc.NotifyReaction(context.Background(), 2, 5)
+
+	if assert.Len(t, stored, 1) {
+		assert.Equal(t, entity.NotificationReaction, stored[0].Type)
+		assert.Equal(t, int64(1), stored[0].RecipientID)
+		assert.Equal(t, int64(5), stored[0].PostID)
+	}
+	if assert.Len(t, pusher.events, 1) {
+		event := pusher.events[0].(NotificationEvent)
+		assert.Equal(t, "notification", event.Type)
+		assert.Equal(t, 3, event.UnreadCount)
+	}
+}
+
+func TestNotificationUsecase_DuplicateIsNotPushed(t *testing.T) {
+	pusher := &recordingPusher{}
+	repo := &MockNotificationRepository{
+		CreateNotificationFunc: func(ctx context.Context, n *entity.Notification) (bool, error) {
+			return false, nil
+		},
+	}
+	uc := NewNotificationUsecase(repo, nil, nil, pusher, nil)
+
+	uc.NotifyMentions(context.Background(), []entity.Mention{{SourceType: entity.MentionSourcePost, SourceID: 1, PostID: 1, AuthorID: 1, MentionedUserID: 2}})
+	assert.Empty(t, pusher.userIDs)
+}
+
+func TestNotificationUsecase_NotifyMentions(t *testing.T) {
+	var stored []entity.Notification
+	pusher := &recordingPusher{err: errors.New("chat service is down")}
+	uc := NewNotificationUsecase(storingNotificationRepository(&stored), nil, nil, pusher, nil)
+
+	uc.NotifyMentions(context.Background(), []entity.Mention{
+		{SourceType: entity.MentionSourceComment, SourceID: 10, PostID: 3, AuthorID: 1, MentionedUserID: 2},
+	})
+
+	// Сбой доставки не отменяет сохранение
+	if assert.Len(t, stored, 1) {
+		assert.Equal(t, entity.NotificationMention, stored[0].Type)
+		assert.Equal(t, entity.NotificationTargetComment, stored[0].TargetType)
+		assert.Equal(t, int64(10), stored[0].TargetID)
+	}
+	assert.Equal(t, []int64{2}, pusher.userIDs)
+}
+
+func TestNotificationUsecase_NotifyWarning(t *testing.T) {
+	var stored []entity.Notification
+	pusher := &recordingPusher{}
+	uc := NewNotificationUsecase(storingNotificationRepository(&stored), nil, nil, pusher, nil)
+
+	uc.NotifyWarning(context.Background(), entity.Warning{UserID: 2, ModeratorID: 1, TargetType: entity.ReportTargetComment, TargetID: 10, PostID: 3})
+	if assert.Len(t, stored, 1) {
+		assert.Equal(t, entity.NotificationWarning, stored[0].Type)
+		assert.Equal(t, entity.NotificationTargetComment, stored[0].TargetType)
+	}
+
+	// Предупреждение по сообщению чата не попадает во входящие, только доставляется
+	uc.NotifyWarning(context.Background(), entity.Warning{UserID: 4, ModeratorID: 1, TargetType: entity.ReportTargetMessage, TargetID: 6, Note: "flood"})
+	assert.Len(t, stored, 1)
+	assert.Equal(t, []int64{2, 4}, pusher.userIDs)
+	if assert.IsType(t, WarningEvent{}, pusher.events[1]) {
+		assert.Equal(t, "flood", pusher.events[1].(WarningEvent).Warning.Note)
+	}
+}
+
+func TestNotificationUsecase_GetNotifications(t *testing.T) {
+	repo := &MockNotificationRepository{
+		GetNotificationsFunc: func(ctx context.Context, recipientID int64, filter entity.NotificationFilter) ([]entity.Notification, error) {
+			assert.Equal(t, int64(7), recipientID)
+			assert.Equal(t, MaxNotificationsLimit, filter.Limit)
+			assert.Equal(t, 0, filter.Offset)
+			return []entity.Notification{{ID: 1, RecipientID: 7}}, nil
+		},
+		CountUnreadFunc: func(ctx context.Context, recipientID int64) (int, error) {
+			return 1, nil
+		},
+	}
+	uc := NewNotificationUsecase(repo, nil, sessionAuth(7, "user"), nil, nil)
+
+	list, err := uc.GetNotifications(context.Background(), "token", entity.NotificationFilter{Limit: 1000, Offset: -5})
+	assert.NoError(t, err)
+	assert.Len(t, list.Notifications, 1)
+	assert.Equal(t, 1, list.UnreadCount)
+}
+
+func TestNotificationUsecase_MarkRead(t *testing.T) {
+	repo := &MockNotificationRepository{
+		MarkReadFunc: func(ctx context.Context, recipientID, id int64) error {
+			assert.Equal(t, int64(7), recipientID)
+			assert.Equal(t, int64(3), id)
+			return nil
+		},
+		MarkAllReadFunc: func(ctx context.Context, recipientID int64) (int64, error) {
+			return 2, nil
+		},
+	}
+	uc := NewNotificationUsecase(repo, nil, sessionAuth(7, "user"), nil, nil)
+
+	assert.NoError(t, uc.MarkRead(context.Background(), "token", 3))
+	updated, err := uc.MarkAllRead(context.Background(), "token")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+)
+
+// PollPublisher рассылает событие всем клиентам WebSocket, подписанным на тему
+type PollPublisher interface {
+	Publish(ctx context.Context, topic string, event interface{}) error
+}
+
+// PollResultsEvent отправляется подписчикам темы entity.PollTopic после каждого голоса и закрытия опроса
+type PollResultsEvent struct {
+	Type string       `json:"type"`
+	Poll *entity.Poll `json:"poll"`
+}
+
+type PollUsecaseInterface interface {
+	GetPoll(ctx context.Context, token string, postID int64) (*entity.Poll, error)
+	Vote(ctx context.Context, token string, postID int64, optionIDs []int64) (*entity.Poll, error)
+	ClosePoll(ctx context.Context, token string, postID int64) (*entity.Poll, error)
+}
+
+type PollUsecase struct {
+	pollRepo   repository.PollRepository
+	postRepo   repository.PostRepository
+	authClient pb.AuthServiceClient
+	publisher  PollPublisher
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// NewPollUsecase создает usecase опросов; при publisher = nil результаты не транслируются
+func NewPollUsecase(
+	pollRepo repository.PollRepository,
+	postRepo repository.PostRepository,
+	authClient pb.AuthServiceClient,
+	publisher PollPublisher,
+	logger *logger.Logger,
+) *PollUsecase {
+	return &PollUsecase{
+		pollRepo:   pollRepo,
+		postRepo:   postRepo,
+		authClient: authClient,
+		publisher:  publisher,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// GetPoll возвращает текущие результаты. Гостям (пустой token) не выводится выбор пользователя.
+func (uc *PollUsecase) GetPoll(ctx context.Context, token string, postID int64) (*entity.Poll, error) {
+	var userID int64
+	if token != "" {
+		session, err := validateToken(ctx, uc.authClient, token)
+		if err != nil {
+			return nil, err
+		}
+		userID = session.UserId
+	}
+
+	poll, err := uc.results(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != 0 {
+		if poll.MyVotes, err = uc.pollRepo.GetUserVotes(ctx, poll.ID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return poll, nil
+}
+
+// Vote принимает голос; каждый пользователь голосует один раз, изменить выбор нельзя.
+// В опросе удаленного поста голосовать нельзя.
+func (uc *PollUsecase) Vote(ctx context.Context, token string, postID int64, optionIDs []int64) (*entity.Poll, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.postRepo.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+
+	poll, err := uc.pollRepo.GetPollByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.IsClosed(uc.now()) {
+		return nil, entity.ErrPollClosed
+	}
+	choice, err := poll.ValidateVote(optionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.pollRepo.Vote(ctx, poll.ID, session.UserId, choice); err != nil {
+		return nil, err
+	}
+
+	poll, err = uc.results(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	uc.publish(ctx, poll)
+
+	poll.MyVotes = choice
+	return poll, nil
+}
+
+// ClosePoll закрывает опрос досрочно. Доступно автору поста и модераторам.
+func (uc *PollUsecase) ClosePoll(ctx context.Context, token string, postID int64) (*entity.Poll, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := uc.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != session.UserId && !isModerator(session.UserRole) {
+		return nil, ErrForbidden
+	}
+
+	poll, err := uc.pollRepo.GetPollByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.IsClosed(uc.now()) {
+		return nil, entity.ErrPollClosed
+	}
+	if err := uc.pollRepo.ClosePoll(ctx, poll.ID, uc.now()); err != nil {
+		return nil, err
+	}
+
+	poll, err = uc.results(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	uc.publish(ctx, poll)
+	return poll, nil
+}
+
+// CloseExpiredPolls фиксирует закрытие опросов с истекшим сроком и рассылает итоговые результаты.
+// Вызывается фоновой задачей; возвращает число закрытых опросов.
+func (uc *PollUsecase) CloseExpiredPolls(ctx context.Context) (int, error) {
+	postIDs, err := uc.pollRepo.CloseExpiredPolls(ctx, uc.now())
+	if err != nil {
+		return 0, err
+	}
+	for _, postID := range postIDs {
+		poll, err := uc.results(ctx, postID)
+		if err != nil {
+			uc.logError("Failed to load closed poll", err)
+			continue
+		}
+		uc.publish(ctx, poll)
+	}
+	return len(postIDs), nil
+}
+
+// results загружает опрос с подсчетом голосов; в открытых опросах добавляет списки проголосовавших
+func (uc *PollUsecase) results(ctx context.Context, postID int64) (*entity.Poll, error) {
+	poll, err := uc.pollRepo.GetPollByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	poll.Closed = poll.IsClosed(uc.now())
+
+	if !poll.Anonymous {
+		voters, err := uc.pollRepo.GetVoters(ctx, poll.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range poll.Options {
+			poll.Options[i].Voters = voters[poll.Options[i].ID]
+		}
+	}
+	return poll, nil
+}
+
+// publish рассылает результаты подписчикам; сбой чат-сервиса не отменяет голос
+func (uc *PollUsecase) publish(ctx context.Context, poll *entity.Poll) {
+	if uc.publisher == nil {
+		return
+	}
+	// Рассылается копия: выбор текущего пользователя добавляется в ответ уже после рассылки
+	results := *poll
+	results.MyVotes = nil
+	event := PollResultsEvent{Type: "poll_results", Poll: &results}
+	if err := uc.publisher.Publish(ctx, entity.PollTopic(poll.PostID), event); err != nil {
+		uc.logError("Failed to publish poll results", err)
+	}
+}
+
+func (uc *PollUsecase) logError(msg string, err error) {
+	if uc.logger != nil {
+		uc.logger.Error(msg, err)
+	}
+}
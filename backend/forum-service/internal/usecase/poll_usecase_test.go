@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockPollRepository struct {
+	GetPollByPostIDFunc   func(ctx context.Context, postID int64) (*entity.Poll, error)
+	GetVotersFunc         func(ctx context.Context, pollID int64) (map[int64][]int64, error)
+	GetUserVotesFunc      func(ctx context.Context, pollID, userID int64) ([]int64, error)
+	VoteFunc              func(ctx context.Context, pollID, userID int64, optionIDs []int64) error
+	ClosePollFunc         func(ctx context.Context, pollID int64, at time.Time) error
+	CloseExpiredPollsFunc func(ctx context.Context, now time.Time) ([]int64, error)
+}
+
+func (m *MockPollRepository) GetPollByPostID(ctx context.Context, postID int64) (*entity.Poll, error) {
+	return m.GetPollByPostIDFunc(ctx, postID)
+}
+
+func (m *MockPollRepository) GetVoters(ctx context.Context, pollID int64) (map[int64][]int64, error) {
+	return m.GetVotersFunc(ctx, pollID)
+}
+
+func (m *MockPollRepository) GetUserVotes(ctx context.Context, pollID, userID int64) ([]int64, error) {
+	return m.GetUserVotesFunc(ctx, pollID, userID)
+}
+
+func (m *MockPollRepository) Vote(ctx context.Context, pollID, userID int64, optionIDs []int64) error {
+	return m.VoteFunc(ctx, pollID, userID, optionIDs)
+}
+
+func (m *MockPollRepository) ClosePoll(ctx context.Context, pollID int64, at time.Time) error {
+	return m.ClosePollFunc(ctx, pollID, at)
+}
+
+func (m *MockPollRepository) CloseExpiredPolls(ctx context.Context, now time.Time) ([]int64, error) {
+	return m.CloseExpiredPollsFunc(ctx, now)
+}
+
+type publishedEvent struct {
+	topic string
+	event interface{}
+}
+
+type MockPollPublisher struct {
+	events []publishedEvent
+	err    error
+}
+
+func (m *MockPollPublisher) Publish(ctx context.Context, topic string, event interface{}) error {
+	m.events = append(m.events, publishedEvent{topic: topic, event: event})
+	return m.err
+}
+
+// pollStore эмулирует хранение одного опроса поста 3 с голосами в памяти
+func pollStore(poll entity.Poll) *MockPollRepository {
+	votes := make(map[int64][]int64)
+	return &MockPollRepository{
+		GetPollByPostIDFunc: func(ctx context.Context, postID int64) (*entity.Poll, error) {
+			if postID != poll.PostID {
+				return nil, repository.ErrPollNotFound
+			}
+			result := poll
+			result.Options = make([]entity.PollOption, len(poll.Options))
+			copy(result.Options, poll.Options)
+			result.TotalVoters = len(votes)
+			for i, option := range result.Options {
+				for _, choice := range votes {
+					for _, id := range choice {
+						if id == option.ID {
+							result.Options[i].Votes++
+						}
+					}
+				}
+			}
+			return &result, nil
+		},
+		GetVotersFunc: func(ctx context.Context, pollID int64) (map[int64][]int64, error) {
+			voters := make(map[int64][]int64)
+			for userID, choice := range votes {
+				for _, id := range choice {
+					voters[id] = append(voters[id], userID)
+				}
+			}
+			return voters, nil
+		},
+		GetUserVotesFunc: func(ctx context.Context, pollID, userID int64) ([]int64, error) {
+			return votes[userID], nil
+		},
+		VoteFunc: func(ctx context.Context, pollID, userID int64, optionIDs []int64) error {
+			if _, ok := votes[userID]; ok {
+				return entity.ErrAlreadyVoted
+			}
+			votes[userID] = optionIDs
+			return nil
+		},
+		ClosePollFunc: func(ctx context.Context, pollID int64, at time.Time) error {
+			poll.ClosedAt = &at
+			return nil
+		},
+	}
+}
+
+func testPoll() entity.Poll {
+	return entity.Poll{
+		ID:        5,
+		PostID:    3,
+		Question:  "Go or Rust?",
+		Anonymous: true,
+		Options:   []entity.PollOption{{ID: 11, Text: "Go"}, {ID: 12, Text: "Rust"}},
+	}
+}
+
+func TestPollUsecase_Vote(t *testing.T) {
+	repo := pollStore(testPoll())
+	publisher := &MockPollPublisher{}
+	uc := NewPollUsecase(repo, &MockPostRepository{}, sessionAuth(7, "user"), publisher, nil)
+
+	poll, err := uc.Vote(context.Background(), "token", 3, []int64{12})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, poll.TotalVoters)
+	assert.Equal(t, 1, poll.Options[1].Votes)
+	assert.Equal(t, []int64{12}, poll.MyVotes)
+	// В анонимном опросе проголосовавшие не раскрываются
+	assert.Nil(t, poll.Options[1].Voters)
+
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, "poll:3", publisher.events[0].topic)
+	event := publisher.events[0].event.(PollResultsEvent)
+	assert.Equal(t, "poll_results", event.Type)
+	assert.Equal(t, 1, event.Poll.TotalVoters)
+	// Выбор пользователя не попадает в общую рассылку
+	assert.Nil(t, event.Poll.MyVotes)
+
+	_, err = uc.Vote(context.Background(), "token", 3, []int64{11})
+	assert.ErrorIs(t, err, entity.ErrAlreadyVoted)
+
+	_, err = uc.Vote(context.Background(), "token", 3, []int64{11, 12})
+	assert.ErrorIs(t, err, entity.ErrInvalidPollVote)
+
+	_, err = uc.Vote(context.Background(), "token", 4, []int64{11})
+	assert.ErrorIs(t, err, repository.ErrPollNotFound)
+	assert.Len(t, publisher.events, 1)
+
+	// Пост удален - голос не принимается
+	uc = NewPollUsecase(repo, &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return nil, repository.ErrPostNotFound
+		},
+	}, sessionAuth(8, "user"), publisher, nil)
+	_, err = uc.Vote(context.Background(), "token", 3, []int64{12})
+	assert.ErrorIs(t, err, repository.ErrPostNotFound)
+	assert.Len(t, publisher.events, 1)
+}
+
+func TestPollUsecase_Vote_PublicAndClosed(t *testing.T) {
+	poll := testPoll()
+	poll.Anonymous = false
+	poll.Multiple = true
+	repo := pollStore(poll)
+	publisher := &MockPollPublisher{err: errors.New("chat is down")}
+	uc := NewPollUsecase(repo, &MockPostRepository{}, sessionAuth(7, "user"), publisher, nil)
+
+	// Сбой рассылки не отменяет голос
+	result, err := uc.Vote(context.Background(), "token", 3, []int64{11, 12})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{7}, result.Options[0].Voters)
+	assert.Equal(t, []int64{7}, result.Options[1].Voters)
+
+	closesAt := time.Now().Add(time.Hour)
+	poll.ClosesAt = &closesAt
+	uc = NewPollUsecase(pollStore(poll), &MockPostRepository{}, sessionAuth(7, "user"), nil, nil)
+	uc.now = func() time.Time { return closesAt }
+	_, err = uc.Vote(context.Background(), "token", 3, []int64{11})
+	assert.ErrorIs(t, err, entity.ErrPollClosed)
+}
+
+func TestPollUsecase_GetPoll(t *testing.T) {
+	repo := pollStore(testPoll())
+	auth := sessionAuth(7, "user")
+	uc := NewPollUsecase(repo, &MockPostRepository{}, auth, nil, nil)
+
+	_, err := uc.Vote(context.Background(), "token", 3, []int64{11})
+	assert.NoError(t, err)
+
+	poll, err := uc.GetPoll(context.Background(), "token", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{11}, poll.MyVotes)
+	assert.False(t, poll.Closed)
+
+	guest, err := uc.GetPoll(context.Background(), "", 3)
+	assert.NoError(t, err)
+	assert.Nil(t, guest.MyVotes)
+	assert.Equal(t, 1, guest.Options[0].Votes)
+}
+
+func TestPollUsecase_ClosePoll(t *testing.T) {
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id, AuthorID: 1}, nil
+		},
+	}
+
+	publisher := &MockPollPublisher{}
+	uc := NewPollUsecase(pollStore(testPoll()), postRepo, sessionAuth(7, "user"), publisher, nil)
+	_, err := uc.ClosePoll(context.Background(), "token", 3)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	repo := pollStore(testPoll())
+	uc = NewPollUsecase(repo, postRepo, sessionAuth(1, "user"), publisher, nil)
+	poll, err := uc.ClosePoll(context.Background(), "token", 3)
+	assert.NoError(t, err)
+	assert.True(t, poll.Closed)
+	assert.Len(t, publisher.events, 1)
+
+	_, err = uc.ClosePoll(context.Background(), "token", 3)
+	assert.ErrorIs(t, err, entity.ErrPollClosed)
+
+	// Модератор может закрыть чужой опрос
+	uc = NewPollUsecase(pollStore(testPoll()), postRepo, sessionAuth(9, "moderator"), publisher, nil)
+	_, err = uc.ClosePoll(context.Background(), "token", 3)
+	assert.NoError(t, err)
+}
+
+func TestPollUsecase_CloseExpiredPolls(t *testing.T) {
+	repo := pollStore(testPoll())
+	repo.CloseExpiredPollsFunc = func(ctx context.Context, now time.Time) ([]int64, error) {
+		return []int64{3, 4}, nil
+	}
+	publisher := &MockPollPublisher{}
+	uc := NewPollUsecase(repo, &MockPostRepository{}, sessionAuth(1, "user"), publisher, nil)
+
+	// Опрос поста 4 не загружается - ошибка логируется, остальные рассылаются
+	closed, err := uc.CloseExpiredPolls(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, closed)
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, "poll:3", publisher.events[0].topic)
+}
+
+func TestPostUsecase_CreatePostWithPoll(t *testing.T) {
+	auth := sessionAuth(7, "user")
+	var created *entity.PollInput
+	postRepo := &MockPostRepository{
+		CreatePostFunc: func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
+			created = poll
+			post.Poll = &entity.Poll{ID: 5, PostID: 3, Question: poll.Question}
+			return 3, nil
+		},
+	}
+
+	uc := NewPostUsecase(postRepo, auth, nil)
+
+	post, err := uc.CreatePost(context.Background(), "token", "Title", "Content", nil, nil,
+		&entity.PollInput{Question: " Go or Rust? ", Options: []string{"Go", "Rust"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), post.Poll.ID)
+	assert.Equal(t, "Go or Rust?", created.Question)
+
+	// Неверный опрос отклоняется до создания поста
+	postRepo.CreatePostFunc = func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
+		t.Fatal("post must not be created")
+		return 0, nil
+	}
+	_, err = uc.CreatePost(context.Background(), "token", "Title", "Content", nil, nil,
+		&entity.PollInput{Question: "q", Options: []string{"only"}})
+	assert.ErrorIs(t, err, entity.ErrInvalidPoll)
+}
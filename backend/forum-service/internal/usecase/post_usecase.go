@@ -11,17 +11,28 @@ import (
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
 	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
 	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/markdown"
 )
 
 type PostUsecase struct {
 	postRepo   repository.PostRepository
 	authClient pb.AuthServiceClient
 	logger     *logger.Logger
+	// Mentions обрабатывает @упоминания в новых постах; nil - упоминания не отслеживаются
+	Mentions MentionProcessor
+	// Subscriptions подписывает автора на комментарии к новому посту; nil - автоподписка выключена
+	Subscriptions PostSubscriber
+	// Tags загружает теги постов; nil - теги игнорируются
+	Tags PostTagger
+	// Attachments привязывает загруженные файлы к постам; nil - вложения игнорируются
+	Attachments AttachmentLinker
+	// Filter проверяет текст постов фильтром контента; nil - контент не проверяется
+	Filter ContentChecker
 }
 type PostUsecaseInterface interface {
-	CreatePost(ctx context.Context, token, title, content string) (*entity.Post, error)
-	GetPosts(ctx context.Context) ([]*entity.Post, map[int]string, error)
-	DeletePost(ctx context.Context, token string, postID int64) error
+	CreatePost(ctx context.Context, token, title, content string, tags []string, attachmentIDs []int64, poll *entity.PollInput) (*entity.Post, error)
+	GetPosts(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, map[int]string, error)
+	DeletePost(ctx context.Context, token string, postID int64, reason string) error
 	UpdatePost(ctx context.Context, token string, postID int64, title, content string) (*entity.Post, error)
 }
 
@@ -37,7 +48,7 @@ func NewPostUsecase(
 	}
 }
 
-func (uc *PostUsecase) CreatePost(ctx context.Context, token string, title, content string) (*entity.Post, error) {
+func (uc *PostUsecase) CreatePost(ctx context.Context, token string, title, content string, tags []string, attachmentIDs []int64, poll *entity.PollInput) (*entity.Post, error) {
 
 	validateResp, err := uc.authClient.ValidateToken(ctx, &pb.ValidateTokenRequest{Token: token})
 	if err != nil {
@@ -48,24 +59,82 @@ func (uc *PostUsecase) CreatePost(ctx context.Context, token string, title, cont
 	}
 	userID := validateResp.UserId
 
+	// Теги, вложения и опрос проверяются до публикации, чтобы не создать пост с ошибкой в запросе
+	var slugs []string
+	if uc.Tags != nil {
+		if slugs, err = entity.NormalizeTags(tags); err != nil {
+			return nil, err
+		}
+	}
+	if uc.Attachments != nil {
+		if err := uc.Attachments.CheckAttachments(ctx, userID, attachmentIDs); err != nil {
+			return nil, err
+		}
+	}
+	if poll != nil {
+		if err := poll.Normalize(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	check := entity.ContentCheck{UserID: userID, Kind: entity.ContentKindPost, Title: title, Text: content}
+	verdict, err := uc.filter(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+	title, content = verdict.Title, verdict.Text
+
+	rendered, err := markdown.Render(content)
+	if err != nil {
+		return nil, err
+	}
+
 	post := &entity.Post{
-		Title:     title,
-		Content:   content,
-		AuthorID:  userID,
-		CreatedAt: time.Now(),
+		Title:       title,
+		Content:     content,
+		ContentHTML: rendered,
+		AuthorID:    userID,
+		CreatedAt:   time.Now(),
+		Tags:        slugs,
+		// Отложенный фильтром пост сразу сохраняется скрытым
+		Held: verdict.Action == entity.FilterActionHold,
 	}
 
-	id, err := uc.postRepo.CreatePost(ctx, post)
+	id, err := uc.postRepo.CreatePost(ctx, post, poll)
 	if err != nil {
 		return nil, err
 	}
 
 	post.ID = id
+	if uc.Filter != nil {
+		uc.Filter.Record(ctx, check)
+		if post.Held {
+			if err := uc.Filter.Hold(ctx, entity.ReportTargetPost, post.ID, userID, verdict.Reason); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if uc.Attachments != nil && len(attachmentIDs) > 0 {
+		post.Attachments, err = uc.Attachments.LinkAttachments(ctx, userID, entity.AttachmentTargetPost, post.ID, attachmentIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Пост уже опубликован, поэтому ошибка упоминаний его не отменяет (ее логирует MentionUsecase).
+	// Об отложенном фильтром посте упомянутые не узнают, пока его не одобрит модератор.
+	if uc.Mentions != nil && !post.Held {
+		_, _ = uc.Mentions.ProcessMentions(ctx, entity.MentionSourcePost, post.ID, post.ID, userID, title+"\n"+content)
+	}
+	if uc.Subscriptions != nil {
+		uc.Subscriptions.SubscribeAuthor(ctx, userID, post.ID)
+	}
 	return post, nil
 }
 
-func (uc *PostUsecase) GetPosts(ctx context.Context) ([]*entity.Post, map[int]string, error) {
-	posts, err := uc.postRepo.GetPosts(ctx)
+func (uc *PostUsecase) GetPosts(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, map[int]string, error) {
+	posts, err := uc.postRepo.GetPosts(ctx, filter)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -93,9 +162,34 @@ func (uc *PostUsecase) GetPosts(ctx context.Context) ([]*entity.Post, map[int]st
 		}
 	}
 
+	postIDs := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+	if uc.Tags != nil && len(posts) > 0 {
+		tags, err := uc.Tags.PostTags(ctx, postIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, post := range posts {
+			post.Tags = tags[post.ID]
+		}
+	}
+	if uc.Attachments != nil && len(posts) > 0 {
+		attachments, err := uc.Attachments.AttachmentsFor(ctx, entity.AttachmentTargetPost, postIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, post := range posts {
+			post.Attachments = attachments[post.ID]
+		}
+	}
+
 	return posts, authorNames, nil
 }
-func (uc *PostUsecase) DeletePost(ctx context.Context, token string, postID int64) error {
+// DeletePost перемещает пост в корзину. Удалить может автор, а также модератор.
+// Восстановить пост можно через TrashUsecase.
+func (uc *PostUsecase) DeletePost(ctx context.Context, token string, postID int64, reason string) error {
 	validateResp, err := uc.authClient.ValidateToken(ctx, &pb.ValidateTokenRequest{Token: token})
 	if err != nil {
 		return err
@@ -109,6 +203,7 @@ func (uc *PostUsecase) DeletePost(ctx context.Context, token string, postID int6
 		postID,
 		validateResp.UserId,
 		validateResp.UserRole,
+		reason,
 	)
 
 	if err != nil {
@@ -140,6 +235,19 @@ func (uc *PostUsecase) UpdatePost(
 		return nil, errors.New("invalid token")
 	}
 
+	verdict, err := uc.filter(ctx, entity.ContentCheck{UserID: validateResp.UserId, Kind: entity.ContentKindPost, Title: title, Text: content, Edit: true})
+	if err != nil {
+		return nil, err
+	}
+	title, content = verdict.Title, verdict.Text
+
+	rendered, err := markdown.Render(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Отложенная фильтром правка сохраняется сразу скрытой, как и новый пост
+	held := verdict.Action == entity.FilterActionHold
 	updatedPost, err := uc.postRepo.UpdatePost(
 		ctx,
 		postID,
@@ -147,7 +255,33 @@ func (uc *PostUsecase) UpdatePost(
 		validateResp.UserRole,
 		title,
 		content,
+		rendered,
+		held,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if held {
+		if err := uc.Filter.Hold(ctx, entity.ReportTargetPost, postID, updatedPost.AuthorID, verdict.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	return updatedPost, nil
+}
 
-	return updatedPost, err
+// filter проверяет текст фильтром контента и возвращает ошибку для отклоненной записи.
+// Без фильтра текст пропускается как есть.
+func (uc *PostUsecase) filter(ctx context.Context, in entity.ContentCheck) (*entity.FilterVerdict, error) {
+	if uc.Filter == nil {
+		return &entity.FilterVerdict{Title: in.Title, Text: in.Text}, nil
+	}
+	verdict, err := uc.Filter.Check(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	if err := verdict.Err(); err != nil {
+		return nil, err
+	}
+	return verdict, nil
 }
\ No newline at end of file
@@ -59,7 +59,7 @@ func TestPostUsecase_UpdatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error) {
 						return updatedPost, nil
 					},
 				}
@@ -86,7 +86,7 @@ func TestPostUsecase_UpdatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error) {
 						return updatedPost, nil
 					},
 				}
@@ -111,7 +111,7 @@ func TestPostUsecase_UpdatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error) {
 						return nil, nil
 					},
 				}
@@ -138,7 +138,7 @@ func TestPostUsecase_UpdatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content string) (*entity.Post, error) {
+					UpdatePostFunc: func(ctx context.Context, id, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error) {
 						return nil, sql.ErrNoRows
 					},
 				}
@@ -205,7 +205,7 @@ func TestPostUsecase_DeletePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					DeletePostFunc: func(ctx context.Context, id, authorID int64, role string) error {
+					DeletePostFunc: func(ctx context.Context, id, authorID int64, role, reason string) error {
 						return nil
 					},
 				}
@@ -229,7 +229,34 @@ func TestPostUsecase_DeletePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					DeletePostFunc: func(ctx context.Context, id, authorID int64, role string) error {
+					DeletePostFunc: func(ctx context.Context, id, authorID int64, role, reason string) error {
+						return nil
+					},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Success - Moderator Delete",
+			token:  "valid_token",
+			postID: 1,
+			mockAuth: func() *MockAuthServiceClient {
+				return &MockAuthServiceClient{
+					ValidateTokenFunc: func(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateSessionResponse, error) {
+						return &pb.ValidateSessionResponse{
+							Valid:    true,
+							UserId:   3,
+							UserRole: "moderator",
+						}, nil
+					},
+				}
+			},
+			mockRepo: func() *MockPostRepository {
+				return &MockPostRepository{
+					DeletePostFunc: func(ctx context.Context, id, authorID int64, role, reason string) error {
+						if role != "moderator" {
+							return repository.ErrPostNotFound
+						}
 						return nil
 					},
 				}
@@ -251,7 +278,7 @@ func TestPostUsecase_DeletePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					DeletePostFunc: func(ctx context.Context, id, authorID int64, role string) error {
+					DeletePostFunc: func(ctx context.Context, id, authorID int64, role, reason string) error {
 						return nil
 					},
 				}
@@ -276,7 +303,7 @@ func TestPostUsecase_DeletePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					DeletePostFunc: func(ctx context.Context, id, authorID int64, role string) error {
+					DeletePostFunc: func(ctx context.Context, id, authorID int64, role, reason string) error {
 						return sql.ErrNoRows
 					},
 				}
@@ -301,7 +328,7 @@ func TestPostUsecase_DeletePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					DeletePostFunc: func(ctx context.Context, id, authorID int64, role string) error {
+					DeletePostFunc: func(ctx context.Context, id, authorID int64, role, reason string) error {
 						return repository.ErrPermissionDenied
 					},
 				}
@@ -323,7 +350,7 @@ func TestPostUsecase_DeletePost(t *testing.T) {
 				logger:     logger,
 			}
 
-			err := uc.DeletePost(context.Background(), tt.token, tt.postID)
+			err := uc.DeletePost(context.Background(), tt.token, tt.postID, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeletePost() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -369,7 +396,7 @@ func TestPostUsecase_CreatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					CreatePostFunc: func(ctx context.Context, post *entity.Post) (int64, error) {
+					CreatePostFunc: func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
 						return 1, nil
 					},
 				}
@@ -421,7 +448,7 @@ func TestPostUsecase_CreatePost(t *testing.T) {
 			},
 			mockRepo: func() *MockPostRepository {
 				return &MockPostRepository{
-					CreatePostFunc: func(ctx context.Context, post *entity.Post) (int64, error) {
+					CreatePostFunc: func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
 						return 0, errors.New("create error")
 					},
 				}
@@ -443,7 +470,7 @@ func TestPostUsecase_CreatePost(t *testing.T) {
 				logger:     logger,
 			}
 
-			got, err := uc.CreatePost(context.Background(), tt.token, tt.title, tt.content)
+			got, err := uc.CreatePost(context.Background(), tt.token, tt.title, tt.content, nil, nil, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreatePost() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -568,7 +595,7 @@ func TestPostUsecase_GetPosts(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPostRepository{
-				GetPostsFunc: func(ctx context.Context) ([]*entity.Post, error) {
+				GetPostsFunc: func(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, error) {
 					return tt.mockPosts, tt.mockError
 				},
 			}
@@ -583,7 +610,7 @@ func TestPostUsecase_GetPosts(t *testing.T) {
 				logger:     logger,
 			}
 
-			posts, names, err := uc.GetPosts(context.Background())
+			posts, names, err := uc.GetPosts(context.Background(), entity.PostFilter{})
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
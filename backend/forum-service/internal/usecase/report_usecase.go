@@ -0,0 +1,371 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/chatpush"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+)
+
+const (
+	DefaultReportsLimit = 20
+	MaxReportsLimit     = 100
+)
+
+// ChatModerator дает модерации доступ к сообщениям чат-сервиса
+type ChatModerator interface {
+	GetMessage(ctx context.Context, id int64) (*chatpush.Message, error)
+	DeleteMessage(ctx context.Context, id int64) error
+}
+
+// ReportWarner доставляет автору предупреждение модератора
+type ReportWarner interface {
+	NotifyWarning(ctx context.Context, w entity.Warning)
+}
+
+type ReportUsecaseInterface interface {
+	CreateReport(ctx context.Context, token string, in entity.ReportInput) (*entity.Report, error)
+	GetReports(ctx context.Context, token string, filter entity.ReportFilter) ([]entity.ReportGroup, error)
+	ResolveReports(ctx context.Context, token string, targetType entity.ReportTarget, targetID int64, resolution entity.ReportResolution) (int64, error)
+}
+
+type ReportUsecase struct {
+	reportRepo  repository.ReportRepository
+	postRepo    repository.PostRepository
+	commentRepo repository.CommentRepository
+	authClient  pb.AuthServiceClient
+	logger      *logger.Logger
+	now         func() time.Time
+	// Chat - сообщения чата; nil - жалобы на сообщения чата не принимаются
+	Chat ChatModerator
+	// Warnings - доставка предупреждений; nil - action=warn только закрывает жалобы
+	Warnings ReportWarner
+	// Learning обучает классификатор спама на решениях по жалобам на спам; nil - без обучения
+	Learning SpamLearner
+}
+
+func NewReportUsecase(
+	reportRepo repository.ReportRepository,
+	postRepo repository.PostRepository,
+	commentRepo repository.CommentRepository,
+	authClient pb.AuthServiceClient,
+	logger *logger.Logger,
+) *ReportUsecase {
+	return &ReportUsecase{
+		reportRepo:  reportRepo,
+		postRepo:    postRepo,
+		commentRepo: commentRepo,
+		authClient:  authClient,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// CreateReport принимает жалобу пользователя. Автор объекта определяется здесь же,
+// чтобы модератор мог заблокировать его, даже если объект потом удалят.
+func (uc *ReportUsecase) CreateReport(ctx context.Context, token string, in entity.ReportInput) (*entity.Report, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := in.Normalize(); err != nil {
+		return nil, err
+	}
+
+	authorID, _, err := uc.targetAuthor(ctx, in.TargetType, in.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if authorID == session.UserId {
+		return nil, entity.ErrReportOwnContent
+	}
+
+	report := &entity.Report{
+		ReporterID:     session.UserId,
+		TargetType:     in.TargetType,
+		TargetID:       in.TargetID,
+		TargetAuthorID: authorID,
+		Reason:         in.Reason,
+		Details:        in.Details,
+	}
+	if err := uc.reportRepo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetReports возвращает очередь жалоб, сгруппированную по объектам. Доступно только модераторам.
+// status=resolved и status=dismissed показывают историю решений.
+func (uc *ReportUsecase) GetReports(ctx context.Context, token string, filter entity.ReportFilter) ([]entity.ReportGroup, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+	if !isModerator(session.UserRole) {
+		return nil, ErrForbidden
+	}
+
+	if filter.Status == "" {
+		filter.Status = entity.ReportStatusOpen
+	}
+	if !filter.Status.Valid() {
+		return nil, entity.ErrInvalidReportStatus
+	}
+	if filter.TargetType != "" && !filter.TargetType.Valid() {
+		return nil, entity.ErrInvalidReportTarget
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultReportsLimit
+	}
+	if filter.Limit > MaxReportsLimit {
+		filter.Limit = MaxReportsLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return uc.reportRepo.GetReportGroups(ctx, filter)
+}
+
+// ResolveReports выполняет решение модератора и закрывает все открытые жалобы на объект.
+// Возвращает число закрытых жалоб.
+func (uc *ReportUsecase) ResolveReports(ctx context.Context, token string, targetType entity.ReportTarget, targetID int64, resolution entity.ReportResolution) (int64, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return 0, err
+	}
+	if !isModerator(session.UserRole) {
+		return 0, ErrForbidden
+	}
+	if !targetType.Valid() {
+		return 0, entity.ErrInvalidReportTarget
+	}
+	if err := resolution.Normalize(); err != nil {
+		return 0, err
+	}
+
+	if resolution.Action == entity.ReportActionDelete && targetType == entity.ReportTargetUser {
+		// Профиль пользователя удалить нельзя, для него есть warn и suspend
+		return 0, entity.ErrInvalidReportAction
+	}
+
+	// Жалобы закрываются до выполнения решения: одновременное решение другого модератора
+	// получит ErrNoOpenReports и не удалит объект и не заблокирует автора повторно
+	reports, err := uc.reportRepo.ClaimReports(ctx, targetType, targetID, session.UserId, resolution)
+	if err != nil {
+		return 0, err
+	}
+	authorID := reports[0].TargetAuthorID
+
+	// Текст читается до решения, потому что delete его удалит
+	var sample string
+	if uc.Learning != nil && hasSpamReport(reports) {
+		sample = uc.targetText(ctx, targetType, targetID)
+	}
+
+	switch resolution.Action {
+	case entity.ReportActionDelete:
+		err = uc.deleteTarget(ctx, session.UserId, session.UserRole, targetType, targetID, resolution.Note)
+	case entity.ReportActionWarn:
+		uc.warn(ctx, session.UserId, authorID, targetType, targetID, resolution.Note)
+	case entity.ReportActionSuspend:
+		err = uc.suspend(ctx, token, authorID, resolution)
+	}
+	if err != nil {
+		// Решение не выполнено - жалобы возвращаются в очередь
+		if reopenErr := uc.reportRepo.ReopenReports(ctx, reportIDs(reports)); reopenErr != nil {
+			uc.logError("Failed to reopen reports", reopenErr)
+		}
+		return 0, err
+	}
+
+	if err := uc.reportRepo.CompleteResolution(ctx, targetType, targetID, session.UserId, resolution); err != nil {
+		return 0, err
+	}
+	if sample != "" {
+		uc.Learning.Learn(ctx, sample, resolution.Action != entity.ReportActionDismiss)
+	}
+	return int64(len(reports)), nil
+}
+
+func reportIDs(reports []entity.Report) []int64 {
+	ids := make([]int64, len(reports))
+	for i, r := range reports {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func hasSpamReport(reports []entity.Report) bool {
+	for _, r := range reports {
+		if r.Reason == entity.ReportReasonSpam {
+			return true
+		}
+	}
+	return false
+}
+
+// targetText возвращает текст объекта жалобы для обучения классификатора; "" - текста нет
+func (uc *ReportUsecase) targetText(ctx context.Context, targetType entity.ReportTarget, targetID int64) string {
+	var err error
+	switch targetType {
+	case entity.ReportTargetPost:
+		var post *entity.Post
+		if post, err = uc.postRepo.GetPostForModeration(ctx, targetID); err == nil {
+			return post.Title + "\n" + post.Content
+		}
+	case entity.ReportTargetComment:
+		var comment *entity.Comment
+		if comment, err = uc.commentRepo.GetCommentForModeration(ctx, targetID); err == nil && comment.DeletedAt == nil {
+			return comment.Content
+		}
+	case entity.ReportTargetMessage:
+		if uc.Chat == nil {
+			return ""
+		}
+		var msg *chatpush.Message
+		if msg, err = uc.Chat.GetMessage(ctx, targetID); err == nil {
+			return msg.Message
+		}
+	}
+	if err != nil {
+		uc.logError("Failed to load reported content for spam classifier", err)
+	}
+	return ""
+}
+
+// targetAuthor возвращает автора объекта жалобы и ID поста, к которому он относится (0 для сообщений и пользователей)
+func (uc *ReportUsecase) targetAuthor(ctx context.Context, targetType entity.ReportTarget, targetID int64) (int64, int64, error) {
+	switch targetType {
+	case entity.ReportTargetPost:
+		post, err := uc.postRepo.GetPostByID(ctx, targetID)
+		if errors.Is(err, repository.ErrPostNotFound) {
+			return 0, 0, entity.ErrReportTargetNotFound
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		return post.AuthorID, post.ID, nil
+
+	case entity.ReportTargetComment:
+		comment, err := uc.commentRepo.GetCommentByID(ctx, targetID)
+		if errors.Is(err, repository.ErrCommentNotFound) || (err == nil && comment.DeletedAt != nil) {
+			return 0, 0, entity.ErrReportTargetNotFound
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		return comment.AuthorID, comment.PostID, nil
+
+	case entity.ReportTargetMessage:
+		if uc.Chat == nil {
+			return 0, 0, entity.ErrInvalidReportTarget
+		}
+		msg, err := uc.Chat.GetMessage(ctx, targetID)
+		if errors.Is(err, chatpush.ErrMessageNotFound) {
+			return 0, 0, entity.ErrReportTargetNotFound
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		return msg.UserID, 0, nil
+
+	case entity.ReportTargetUser:
+		resp, err := uc.authClient.GetUserProfile(ctx, &pb.GetUserProfileRequest{UserId: targetID})
+		if status.Code(err) == codes.NotFound || (err == nil && resp.GetUser() == nil) {
+			return 0, 0, entity.ErrReportTargetNotFound
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		return resp.User.Id, 0, nil
+	}
+	return 0, 0, entity.ErrInvalidReportTarget
+}
+
+// deleteTarget удаляет пост, комментарий или сообщение. Уже удаленный объект не считается ошибкой:
+// жалобы на него все равно нужно закрыть.
+func (uc *ReportUsecase) deleteTarget(ctx context.Context, moderatorID int64, role string, targetType entity.ReportTarget, targetID int64, note string) error {
+	var err error
+	switch targetType {
+	case entity.ReportTargetPost:
+		err = uc.postRepo.DeletePost(ctx, targetID, moderatorID, role, note)
+		if errors.Is(err, repository.ErrPostNotFound) {
+			return nil
+		}
+	case entity.ReportTargetComment:
+		err = uc.commentRepo.DeleteComment(ctx, targetID, moderatorID, note)
+		if errors.Is(err, repository.ErrCommentNotFound) {
+			return nil
+		}
+	case entity.ReportTargetMessage:
+		if uc.Chat == nil {
+			return entity.ErrInvalidReportAction
+		}
+		err = uc.Chat.DeleteMessage(ctx, targetID)
+		if errors.Is(err, chatpush.ErrMessageNotFound) {
+			return nil
+		}
+	default:
+		// Профиль пользователя удалить нельзя, для него есть warn и suspend
+		return entity.ErrInvalidReportAction
+	}
+	return err
+}
+
+// warn отправляет предупреждение автору. Ошибки доставки только логируются - решение уже принято.
+func (uc *ReportUsecase) warn(ctx context.Context, moderatorID, authorID int64, targetType entity.ReportTarget, targetID int64, note string) {
+	if uc.Warnings == nil {
+		return
+	}
+
+	warning := entity.Warning{
+		UserID:      authorID,
+		ModeratorID: moderatorID,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Note:        note,
+	}
+	if targetType == entity.ReportTargetPost || targetType == entity.ReportTargetComment {
+		_, postID, err := uc.targetAuthor(ctx, targetType, targetID)
+		if err != nil && !errors.Is(err, entity.ErrReportTargetNotFound) {
+			uc.logError("Failed to load reported content for warning", err)
+		}
+		warning.PostID = postID
+	}
+	uc.Warnings.NotifyWarning(ctx, warning)
+}
+
+// suspend блокирует автора через auth-service от имени модератора
+func (uc *ReportUsecase) suspend(ctx context.Context, token string, authorID int64, resolution entity.ReportResolution) error {
+	until := uc.now().Add(time.Duration(resolution.SuspendHours) * time.Hour)
+	_, err := uc.authClient.SuspendUser(ctx, &pb.SuspendUserRequest{
+		Token:  token,
+		UserId: authorID,
+		Until:  timestamppb.New(until),
+		Reason: resolution.Note,
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.PermissionDenied:
+		return ErrForbidden
+	case codes.NotFound:
+		return entity.ErrReportTargetNotFound
+	}
+	return err
+}
+
+func (uc *ReportUsecase) logError(msg string, err error) {
+	if uc.logger != nil {
+		uc.logger.Error(msg, err)
+	}
+}
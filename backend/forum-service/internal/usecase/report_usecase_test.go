@@ -0,0 +1,415 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/chatpush"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockReportRepository struct {
+	CreateReportFunc       func(ctx context.Context, report *entity.Report) error
+	GetReportGroupsFunc    func(ctx context.Context, filter entity.ReportFilter) ([]entity.ReportGroup, error)
+	ClaimReportsFunc       func(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) ([]entity.Report, error)
+	ReopenReportsFunc      func(ctx context.Context, ids []int64) error
+	CompleteResolutionFunc func(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) error
+}
+
+func (m *MockReportRepository) CreateReport(ctx context.Context, report *entity.Report) error {
+	if m.CreateReportFunc != nil {
+		return m.CreateReportFunc(ctx, report)
+	}
+	return nil
+}
+
+func (m *MockReportRepository) GetReportGroups(ctx context.Context, filter entity.ReportFilter) ([]entity.ReportGroup, error) {
+	if m.GetReportGroupsFunc != nil {
+		return m.GetReportGroupsFunc(ctx, filter)
+	}
+	return []entity.ReportGroup{}, nil
+}
+
+func (m *MockReportRepository) ClaimReports(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) ([]entity.Report, error) {
+	if m.ClaimReportsFunc != nil {
+		return m.ClaimReportsFunc(ctx, targetType, targetID, actorID, resolution)
+	}
+	return nil, entity.ErrNoOpenReports
+}
+
+func (m *MockReportRepository) ReopenReports(ctx context.Context, ids []int64) error {
+	if m.ReopenReportsFunc != nil {
+		return m.ReopenReportsFunc(ctx, ids)
+	}
+	return nil
+}
+
+func (m *MockReportRepository) CompleteResolution(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) error {
+	if m.CompleteResolutionFunc != nil {
+		return m.CompleteResolutionFunc(ctx, targetType, targetID, actorID, resolution)
+	}
+	return nil
+}
+
+type MockChatModerator struct {
+	messages map[int64]*chatpush.Message
+	deleted  []int64
+}
+
+func (m *MockChatModerator) GetMessage(ctx context.Context, id int64) (*chatpush.Message, error) {
+	if msg, ok := m.messages[id]; ok {
+		return msg, nil
+	}
+	return nil, chatpush.ErrMessageNotFound
+}
+
+func (m *MockChatModerator) DeleteMessage(ctx context.Context, id int64) error {
+	if _, ok := m.messages[id]; !ok {
+		return chatpush.ErrMessageNotFound
+	}
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+type recordingWarner struct {
+	warnings []entity.Warning
+}
+
+func (w *recordingWarner) NotifyWarning(ctx context.Context, warning entity.Warning) {
+	w.warnings = append(w.warnings, warning)
+}
+
+func reportTargets() (*MockPostRepository, *MockCommentRepository) {
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			if id != 3 {
+				return nil, repository.ErrPostNotFound
+			}
+			return &entity.Post{ID: 3, AuthorID: 9}, nil
+		},
+	}
+	commentRepo := &MockCommentRepository{
+		GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+			if id != 4 {
+				return nil, repository.ErrCommentNotFound
+			}
+			return &entity.Comment{ID: 4, PostID: 3, AuthorID: 8}, nil
+		},
+	}
+	return postRepo, commentRepo
+}
+
+func TestReportUsecase_CreateReport(t *testing.T) {
+	postRepo, commentRepo := reportTargets()
+
+	tests := []struct {
+		name       string
+		in         entity.ReportInput
+		wantAuthor int64
+		wantErr    error
+	}{
+		{name: "Post", in: entity.ReportInput{TargetType: entity.ReportTargetPost, TargetID: 3, Reason: entity.ReportReasonSpam}, wantAuthor: 9},
+		{name: "Comment", in: entity.ReportInput{TargetType: entity.ReportTargetComment, TargetID: 4, Reason: entity.ReportReasonAbuse}, wantAuthor: 8},
+		{name: "Chat message", in: entity.ReportInput{TargetType: entity.ReportTargetMessage, TargetID: 6, Reason: entity.ReportReasonHarassment}, wantAuthor: 7},
+		{name: "User", in: entity.ReportInput{TargetType: entity.ReportTargetUser, TargetID: 11, Reason: entity.ReportReasonOther}, wantAuthor: 11},
+		{name: "Missing post", in: entity.ReportInput{TargetType: entity.ReportTargetPost, TargetID: 99, Reason: entity.ReportReasonSpam}, wantErr: entity.ErrReportTargetNotFound},
+		{name: "Missing message", in: entity.ReportInput{TargetType: entity.ReportTargetMessage, TargetID: 99, Reason: entity.ReportReasonSpam}, wantErr: entity.ErrReportTargetNotFound},
+		{name: "Missing user", in: entity.ReportInput{TargetType: entity.ReportTargetUser, TargetID: 99, Reason: entity.ReportReasonSpam}, wantErr: entity.ErrReportTargetNotFound},
+		{name: "Own profile", in: entity.ReportInput{TargetType: entity.ReportTargetUser, TargetID: 5, Reason: entity.ReportReasonSpam}, wantErr: entity.ErrReportOwnContent},
+		{name: "Invalid reason", in: entity.ReportInput{TargetType: entity.ReportTargetPost, TargetID: 3, Reason: "boring"}, wantErr: entity.ErrInvalidReportReason},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := sessionAuth(5, "user")
+			auth.GetUserProfileFunc = func(ctx context.Context, in *pb.GetUserProfileRequest, opts ...grpc.CallOption) (*pb.GetUserProfileResponse, error) {
+				if in.UserId == 99 {
+					return nil, status.Error(codes.NotFound, "user not found")
+				}
+				return &pb.GetUserProfileResponse{User: &pb.User{Id: in.UserId}}, nil
+			}
+			var saved *entity.Report
+			reportRepo := &MockReportRepository{
+				CreateReportFunc: func(ctx context.Context, report *entity.Report) error {
+					saved = report
+					report.ID = 1
+					return nil
+				},
+			}
+			uc := NewReportUsecase(reportRepo, postRepo, commentRepo, auth, nil)
+			uc.Chat = &MockChatModerator{messages: map[int64]*chatpush.Message{6: {ID: 6, UserID: 7}}}
+
+			report, err := uc.CreateReport(context.Background(), "token", tt.in)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(5), report.ReporterID)
+			assert.Equal(t, tt.wantAuthor, saved.TargetAuthorID)
+		})
+	}
+}
+
+func TestReportUsecase_GetReports(t *testing.T) {
+	var got entity.ReportFilter
+	reportRepo := &MockReportRepository{
+		GetReportGroupsFunc: func(ctx context.Context, filter entity.ReportFilter) ([]entity.ReportGroup, error) {
+			got = filter
+			return []entity.ReportGroup{}, nil
+		},
+	}
+
+	_, err := NewReportUsecase(reportRepo, nil, nil, sessionAuth(5, "user"), nil).
+		GetReports(context.Background(), "token", entity.ReportFilter{})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	uc := NewReportUsecase(reportRepo, nil, nil, sessionAuth(5, "moderator"), nil)
+	_, err = uc.GetReports(context.Background(), "token", entity.ReportFilter{Limit: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ReportFilter{Status: entity.ReportStatusOpen, Limit: MaxReportsLimit}, got)
+
+	_, err = uc.GetReports(context.Background(), "token", entity.ReportFilter{Status: "closed"})
+	assert.ErrorIs(t, err, entity.ErrInvalidReportStatus)
+}
+
+func TestReportUsecase_ResolveReports(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		role       string
+		target     entity.ReportTarget
+		targetID   int64
+		resolution entity.ReportResolution
+		open       int
+		suspendErr error
+		wantErr    error
+		reopened   bool
+		check      func(t *testing.T, deletedPost, deletedComment int64, chat *MockChatModerator, warner *recordingWarner, suspend *pb.SuspendUserRequest)
+	}{
+		{
+			name: "Dismiss", role: "moderator", target: entity.ReportTargetPost, targetID: 3, open: 2,
+			resolution: entity.ReportResolution{Action: entity.ReportActionDismiss},
+			check: func(t *testing.T, deletedPost, deletedComment int64, chat *MockChatModerator, warner *recordingWarner, suspend *pb.SuspendUserRequest) {
+				assert.Zero(t, deletedPost)
+				assert.Empty(t, warner.warnings)
+				assert.Nil(t, suspend)
+			},
+		},
+		{
+			name: "Delete post", role: "moderator", target: entity.ReportTargetPost, targetID: 3, open: 1,
+			resolution: entity.ReportResolution{Action: entity.ReportActionDelete, Note: "spam"},
+			check: func(t *testing.T, deletedPost, deletedComment int64, chat *MockChatModerator, warner *recordingWarner, suspend *pb.SuspendUserRequest) {
+				assert.Equal(t, int64(3), deletedPost)
+			},
+		},
+		{
+			name: "Delete comment", role: "moderator", target: entity.ReportTargetComment, targetID: 4, open: 1,
+			resolution: entity.ReportResolution{Action: entity.ReportActionDelete},
+			check: func(t *testing.T, deletedPost, deletedComment int64, chat *MockChatModerator, warner *recordingWarner, suspend *pb.SuspendUserRequest) {
+				assert.Equal(t, int64(4), deletedComment)
+			},
+		},
+		{
+			name: "Delete chat message", role: "moderator", target: entity.ReportTargetMessage, targetID: 6, open: 1,
+			resolution: entity.ReportResolution{Action: entity.ReportActionDelete},
+			check: func(t *testing.T, deletedPost, deletedComment int64, chat *MockChatModerator, warner *recordingWarner, suspend *pb.SuspendUserRequest) {
+				assert.Equal(t, []int64{6}, chat.deleted)
+			},
+		},
+		{
+			name: "Cannot delete user", role: "moderator", target: entity.ReportTargetUser, targetID: 8, open: 1,
+			resolution: entity.ReportResolution{Action: entity.ReportActionDelete},
+			wantErr:    entity.ErrInvalidReportAction,
+		},
+		{
+			name: "Warn comment author", role: "moderator", target: entity.ReportTargetComment, targetID: 4, open: 1,
+			resolution: entity.ReportResolution{Action: entity.ReportActionWarn, Note: "keep it civil"},
+			check: func(t *testing.T, deletedPost, deletedComment int64, chat *MockChatModerator, warner *recordingWarner, suspend *pb.SuspendUserRequest) {
+				assert.Equal(t, []entity.Warning{{UserID: 8, ModeratorID: 5, TargetType: entity.ReportTargetComment, TargetID: 4, PostID: 3, Note: "keep it civil"}}, warner.warnings)
+			},
+		},
+		{
+			name: "Suspend author", role: "moderator", target: entity.ReportTargetMessage, targetID: 6, open: 3,
+			resolution: entity.ReportResolution{Action: entity.ReportActionSuspend, SuspendHours: 48, Note: "flood"},
+			check: func(t *testing.T, deletedPost, deletedComment int64, chat *MockChatModerator, warner *recordingWarner, suspend *pb.SuspendUserRequest) {
+				assert.Equal(t, int64(8), suspend.UserId)
+				assert.Equal(t, "token", suspend.Token)
+				assert.Equal(t, "flood", suspend.Reason)
+				assert.Equal(t, now.Add(48*time.Hour), suspend.Until.AsTime())
+			},
+		},
+		{
+			name: "Suspend admin", role: "moderator", target: entity.ReportTargetUser, targetID: 8, open: 1,
+			resolution: entity.ReportResolution{Action: entity.ReportActionSuspend},
+			suspendErr: status.Error(codes.PermissionDenied, "permission denied"),
+			wantErr:    ErrForbidden,
+			reopened:   true,
+		},
+		{
+			name: "No open reports", role: "moderator", target: entity.ReportTargetPost, targetID: 3,
+			resolution: entity.ReportResolution{Action: entity.ReportActionDismiss},
+			wantErr:    entity.ErrNoOpenReports,
+		},
+		{
+			name: "Regular user", role: "user", target: entity.ReportTargetPost, targetID: 3, open: 1,
+			resolution: entity.ReportResolution{Action: entity.ReportActionDismiss},
+			wantErr:    ErrForbidden,
+		},
+		{
+			name: "Invalid action", role: "moderator", target: entity.ReportTargetPost, targetID: 3, open: 1,
+			resolution: entity.ReportResolution{Action: "ban"},
+			wantErr:    entity.ErrInvalidReportAction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deletedPost, deletedComment int64
+			var suspend *pb.SuspendUserRequest
+			postRepo, commentRepo := reportTargets()
+			postRepo.DeletePostFunc = func(ctx context.Context, postID, authorID int64, role, reason string) error {
+				deletedPost = postID
+				assert.Equal(t, int64(5), authorID)
+				assert.Equal(t, tt.role, role)
+				return nil
+			}
+			commentRepo.DeleteCommentFunc = func(ctx context.Context, id, deletedBy int64, reason string) error {
+				deletedComment = id
+				return nil
+			}
+			auth := sessionAuth(5, tt.role)
+			auth.SuspendUserFunc = func(ctx context.Context, in *pb.SuspendUserRequest, opts ...grpc.CallOption) (*pb.SuspendUserResponse, error) {
+				suspend = in
+				if tt.suspendErr != nil {
+					return nil, tt.suspendErr
+				}
+				return &pb.SuspendUserResponse{UserId: in.UserId, SuspendedUntil: in.Until}, nil
+			}
+
+			resolved := false
+			var reopened []int64
+			reportRepo := &MockReportRepository{
+				ClaimReportsFunc: func(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) ([]entity.Report, error) {
+					assert.Equal(t, int64(5), actorID)
+					if tt.open == 0 {
+						return nil, entity.ErrNoOpenReports
+					}
+					reports := []entity.Report{}
+					for i := 0; i < tt.open; i++ {
+						reports = append(reports, entity.Report{ID: int64(i + 1), TargetType: targetType, TargetID: targetID, TargetAuthorID: 8})
+					}
+					return reports, nil
+				},
+				ReopenReportsFunc: func(ctx context.Context, ids []int64) error {
+					reopened = ids
+					return nil
+				},
+				CompleteResolutionFunc: func(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) error {
+					resolved = true
+					assert.Equal(t, int64(5), actorID)
+					return nil
+				},
+			}
+
+			chat := &MockChatModerator{messages: map[int64]*chatpush.Message{6: {ID: 6, UserID: 8}}}
+			warner := &recordingWarner{}
+			uc := NewReportUsecase(reportRepo, postRepo, commentRepo, auth, nil)
+			uc.Chat = chat
+			uc.Warnings = warner
+			uc.now = func() time.Time { return now }
+
+			n, err := uc.ResolveReports(context.Background(), "token", tt.target, tt.targetID, tt.resolution)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, resolved)
+				if tt.reopened {
+					assert.Len(t, reopened, tt.open)
+				} else {
+					assert.Empty(t, reopened)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, resolved)
+			assert.Equal(t, int64(tt.open), n)
+			tt.check(t, deletedPost, deletedComment, chat, warner, suspend)
+		})
+	}
+}
+
+type recordingLearner struct {
+	samples []entity.SpamSample
+}
+
+func (l *recordingLearner) Learn(ctx context.Context, text string, isSpam bool) {
+	l.samples = append(l.samples, entity.SpamSample{Content: text, IsSpam: isSpam})
+}
+
+func TestReportUsecase_ResolveReports_Learning(t *testing.T) {
+	tests := []struct {
+		name   string
+		target entity.ReportTarget
+		id     int64
+		reason entity.ReportReason
+		action entity.ReportAction
+		want   []entity.SpamSample
+	}{
+		{name: "Deleted spam post", target: entity.ReportTargetPost, id: 3, reason: entity.ReportReasonSpam, action: entity.ReportActionDelete,
+			want: []entity.SpamSample{{Content: "Cheap\nbuy now", IsSpam: true}}},
+		{name: "Dismissed spam comment", target: entity.ReportTargetComment, id: 4, reason: entity.ReportReasonSpam, action: entity.ReportActionDismiss,
+			want: []entity.SpamSample{{Content: "great post", IsSpam: false}}},
+		{name: "Suspended chat spammer", target: entity.ReportTargetMessage, id: 6, reason: entity.ReportReasonSpam, action: entity.ReportActionSuspend,
+			want: []entity.SpamSample{{Content: "join my channel", IsSpam: true}}},
+		{name: "Not a spam report", target: entity.ReportTargetPost, id: 3, reason: entity.ReportReasonAbuse, action: entity.ReportActionDelete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postRepo := &MockPostRepository{
+				GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+					return &entity.Post{ID: id, AuthorID: 8, Title: "Cheap", Content: "buy now"}, nil
+				},
+				DeletePostFunc: func(ctx context.Context, postID, authorID int64, role, reason string) error {
+					return nil
+				},
+			}
+			commentRepo := &MockCommentRepository{
+				GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+					return &entity.Comment{ID: id, PostID: 3, AuthorID: 8, Content: "great post"}, nil
+				},
+			}
+			auth := sessionAuth(5, "moderator")
+			auth.SuspendUserFunc = func(ctx context.Context, in *pb.SuspendUserRequest, opts ...grpc.CallOption) (*pb.SuspendUserResponse, error) {
+				return &pb.SuspendUserResponse{UserId: in.UserId, SuspendedUntil: in.Until}, nil
+			}
+			reportRepo := &MockReportRepository{
+				ClaimReportsFunc: func(ctx context.Context, targetType entity.ReportTarget, targetID, actorID int64, resolution entity.ReportResolution) ([]entity.Report, error) {
+					return []entity.Report{
+						{TargetType: targetType, TargetID: targetID, TargetAuthorID: 8, Reason: entity.ReportReasonOther},
+						{TargetType: targetType, TargetID: targetID, TargetAuthorID: 8, Reason: tt.reason},
+					}, nil
+				},
+			}
+			learner := &recordingLearner{}
+			uc := NewReportUsecase(reportRepo, postRepo, commentRepo, auth, nil)
+			uc.Chat = &MockChatModerator{messages: map[int64]*chatpush.Message{6: {ID: 6, UserID: 8, Message: "join my channel"}}}
+			uc.Learning = learner
+
+			_, err := uc.ResolveReports(context.Background(), "token", tt.target, tt.id, entity.ReportResolution{Action: tt.action, SuspendHours: 24})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, learner.samples)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/diff"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/markdown"
+)
+
+type RevisionUsecaseInterface interface {
+	GetPostRevisions(ctx context.Context, postID int64) ([]entity.Revision, error)
+	DiffPostRevisions(ctx context.Context, postID int64, from, to int) (*entity.RevisionDiff, error)
+	RestorePostRevision(ctx context.Context, token string, postID int64, number int) (*entity.Post, error)
+	GetCommentRevisions(ctx context.Context, commentID int64) ([]entity.Revision, error)
+	DiffCommentRevisions(ctx context.Context, commentID int64, from, to int) (*entity.RevisionDiff, error)
+	RestoreCommentRevision(ctx context.Context, token string, commentID int64, number int) (*entity.Comment, error)
+}
+
+type RevisionUsecase struct {
+	revisionRepo repository.RevisionRepository
+	postRepo     repository.PostRepository
+	commentRepo  repository.CommentRepository
+	authClient   pb.AuthServiceClient
+}
+
+func NewRevisionUsecase(
+	revisionRepo repository.RevisionRepository,
+	postRepo repository.PostRepository,
+	commentRepo repository.CommentRepository,
+	authClient pb.AuthServiceClient,
+) *RevisionUsecase {
+	return &RevisionUsecase{
+		revisionRepo: revisionRepo,
+		postRepo:     postRepo,
+		commentRepo:  commentRepo,
+		authClient:   authClient,
+	}
+}
+
+func (uc *RevisionUsecase) GetPostRevisions(ctx context.Context, postID int64) ([]entity.Revision, error) {
+	if _, err := uc.postRepo.GetPostByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	return uc.revisionRepo.GetPostRevisions(ctx, postID)
+}
+
+// DiffPostRevisions сравнивает ревизию from с ревизией to (или с текущей версией поста, если to = 0)
+// Ревизии удаленного или отложенного фильтром поста не отдаются.
+func (uc *RevisionUsecase) DiffPostRevisions(ctx context.Context, postID int64, from, to int) (*entity.RevisionDiff, error) {
+	post, err := uc.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := uc.revisionRepo.GetPostRevision(ctx, postID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var title, content string
+	if to == 0 {
+		title, content = post.Title, post.Content
+	} else {
+		target, err := uc.revisionRepo.GetPostRevision(ctx, postID, to)
+		if err != nil {
+			return nil, err
+		}
+		title, content = target.Title, target.Content
+	}
+
+	return &entity.RevisionDiff{
+		From:    from,
+		To:      to,
+		Title:   diff.Lines(base.Title, title),
+		Content: diff.Lines(base.Content, content),
+	}, nil
+}
+
+// RestorePostRevision возвращает пост к содержимому ревизии. Доступно только администраторам;
+// сама операция восстановления тоже попадает в историю правок.
+func (uc *RevisionUsecase) RestorePostRevision(ctx context.Context, token string, postID int64, number int) (*entity.Post, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin(session.UserRole) {
+		return nil, ErrForbidden
+	}
+
+	revision, err := uc.revisionRepo.GetPostRevision(ctx, postID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := markdown.Render(revision.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.postRepo.UpdatePost(ctx, postID, session.UserId, session.UserRole, revision.Title, revision.Content, rendered, false)
+}
+
+func (uc *RevisionUsecase) GetCommentRevisions(ctx context.Context, commentID int64) ([]entity.Revision, error) {
+	if _, err := uc.liveComment(ctx, commentID); err != nil {
+		return nil, err
+	}
+	return uc.revisionRepo.GetCommentRevisions(ctx, commentID)
+}
+
+func (uc *RevisionUsecase) DiffCommentRevisions(ctx context.Context, commentID int64, from, to int) (*entity.RevisionDiff, error) {
+	comment, err := uc.liveComment(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := uc.revisionRepo.GetCommentRevision(ctx, commentID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var content string
+	if to == 0 {
+		content = comment.Content
+	} else {
+		target, err := uc.revisionRepo.GetCommentRevision(ctx, commentID, to)
+		if err != nil {
+			return nil, err
+		}
+		content = target.Content
+	}
+
+	return &entity.RevisionDiff{
+		From:    from,
+		To:      to,
+		Content: diff.Lines(base.Content, content),
+	}, nil
+}
+
+func (uc *RevisionUsecase) RestoreCommentRevision(ctx context.Context, token string, commentID int64, number int) (*entity.Comment, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin(session.UserRole) {
+		return nil, ErrForbidden
+	}
+
+	revision, err := uc.revisionRepo.GetCommentRevision(ctx, commentID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := markdown.Render(revision.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.commentRepo.UpdateComment(ctx, commentID, session.UserId, revision.Content, rendered, false)
+}
+
+// liveComment возвращает комментарий, если он не удален и не отложен фильтром:
+// историю правок скрытого комментария показывать нельзя
+func (uc *RevisionUsecase) liveComment(ctx context.Context, commentID int64) (*entity.Comment, error) {
+	comment, err := uc.commentRepo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt != nil {
+		return nil, repository.ErrCommentNotFound
+	}
+	return comment, nil
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/diff"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type MockRevisionRepository struct {
+	GetPostRevisionsFunc    func(ctx context.Context, postID int64) ([]entity.Revision, error)
+	GetPostRevisionFunc     func(ctx context.Context, postID int64, number int) (*entity.Revision, error)
+	GetCommentRevisionsFunc func(ctx context.Context, commentID int64) ([]entity.Revision, error)
+	GetCommentRevisionFunc  func(ctx context.Context, commentID int64, number int) (*entity.Revision, error)
+}
+
+func (m *MockRevisionRepository) GetPostRevisions(ctx context.Context, postID int64) ([]entity.Revision, error) {
+	if m.GetPostRevisionsFunc != nil {
+		return m.GetPostRevisionsFunc(ctx, postID)
+	}
+	return nil, nil
+}
+
+func (m *MockRevisionRepository) GetPostRevision(ctx context.Context, postID int64, number int) (*entity.Revision, error) {
+	if m.GetPostRevisionFunc != nil {
+		return m.GetPostRevisionFunc(ctx, postID, number)
+	}
+	return nil, repository.ErrRevisionNotFound
+}
+
+func (m *MockRevisionRepository) GetCommentRevisions(ctx context.Context, commentID int64) ([]entity.Revision, error) {
+	if m.GetCommentRevisionsFunc != nil {
+		return m.GetCommentRevisionsFunc(ctx, commentID)
+	}
+	return nil, nil
+}
+
+func (m *MockRevisionRepository) GetCommentRevision(ctx context.Context, commentID int64, number int) (*entity.Revision, error) {
+	if m.GetCommentRevisionFunc != nil {
+		return m.GetCommentRevisionFunc(ctx, commentID, number)
+	}
+	return nil, repository.ErrRevisionNotFound
+}
+
+func sessionAuth(userID int64, role string) *MockAuthServiceClient {
+	return &MockAuthServiceClient{
+		ValidateTokenFunc: func(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateSessionResponse, error) {
+			return &pb.ValidateSessionResponse{Valid: true, UserId: userID, UserRole: role}, nil
+		},
+	}
+}
+
+func TestRevisionUsecase_DiffPostRevisions(t *testing.T) {
+	revisions := map[int]*entity.Revision{
+		1: {Number: 1, Title: "Title", Content: "one\ntwo"},
+		2: {Number: 2, Title: "Title v2", Content: "one\ntwo\nthree"},
+	}
+	revisionRepo := &MockRevisionRepository{
+		GetPostRevisionFunc: func(ctx context.Context, postID int64, number int) (*entity.Revision, error) {
+			if rev, ok := revisions[number]; ok {
+				return rev, nil
+			}
+			return nil, repository.ErrRevisionNotFound
+		},
+	}
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id, Title: "Title v2", Content: "two\nthree"}, nil
+		},
+	}
+	uc := NewRevisionUsecase(revisionRepo, postRepo, &MockCommentRepository{}, &MockAuthServiceClient{})
+
+	t.Run("Between revisions", func(t *testing.T) {
+		got, err := uc.DiffPostRevisions(context.Background(), 1, 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []diff.Line{{Op: diff.OpDelete, Text: "Title"}, {Op: diff.OpInsert, Text: "Title v2"}}, got.Title)
+		assert.Equal(t, []diff.Line{
+			{Op: diff.OpEqual, Text: "one"},
+			{Op: diff.OpEqual, Text: "two"},
+			{Op: diff.OpInsert, Text: "three"},
+		}, got.Content)
+	})
+
+	t.Run("Against current version", func(t *testing.T) {
+		got, err := uc.DiffPostRevisions(context.Background(), 1, 2, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, got.To)
+		assert.Equal(t, []diff.Line{{Op: diff.OpEqual, Text: "Title v2"}}, got.Title)
+		assert.Equal(t, diff.OpDelete, got.Content[0].Op)
+	})
+
+	t.Run("Unknown revision", func(t *testing.T) {
+		_, err := uc.DiffPostRevisions(context.Background(), 1, 5, 0)
+		assert.ErrorIs(t, err, repository.ErrRevisionNotFound)
+	})
+}
+
+func TestRevisionUsecase_HiddenParents(t *testing.T) {
+	revisionRepo := &MockRevisionRepository{
+		GetPostRevisionFunc: func(ctx context.Context, postID int64, number int) (*entity.Revision, error) {
+			return &entity.Revision{Number: number, Title: "Secret", Content: "secret"}, nil
+		},
+		GetCommentRevisionFunc: func(ctx context.Context, commentID int64, number int) (*entity.Revision, error) {
+			return &entity.Revision{Number: number, Content: "secret"}, nil
+		},
+		GetCommentRevisionsFunc: func(ctx context.Context, commentID int64) ([]entity.Revision, error) {
+			return []entity.Revision{{Number: 1, Content: "secret"}}, nil
+		},
+	}
+	// Репозиторий не находит удаленные и отложенные посты
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return nil, repository.ErrPostNotFound
+		},
+	}
+	deletedAt := time.Now()
+	commentRepo := &MockCommentRepository{
+		GetCommentByIDFunc: func(ctx context.Context, id int64) (*entity.Comment, error) {
+			if id == 1 {
+				return &entity.Comment{ID: id, Content: "secret", DeletedAt: &deletedAt}, nil
+			}
+			// Отложенный фильтром комментарий репозиторий не возвращает
+			return nil, repository.ErrCommentNotFound
+		},
+	}
+	uc := NewRevisionUsecase(revisionRepo, postRepo, commentRepo, &MockAuthServiceClient{})
+
+	t.Run("Deleted or held post", func(t *testing.T) {
+		_, err := uc.DiffPostRevisions(context.Background(), 1, 1, 2)
+		assert.ErrorIs(t, err, repository.ErrPostNotFound)
+	})
+
+	for _, tc := range []struct {
+		name string
+		id   int64
+	}{
+		{"Deleted comment", 1},
+		{"Held comment", 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := uc.DiffCommentRevisions(context.Background(), tc.id, 1, 2)
+			assert.ErrorIs(t, err, repository.ErrCommentNotFound)
+
+			_, err = uc.GetCommentRevisions(context.Background(), tc.id)
+			assert.ErrorIs(t, err, repository.ErrCommentNotFound)
+		})
+	}
+}
+
+func TestRevisionUsecase_RestorePostRevision(t *testing.T) {
+	revisionRepo := &MockRevisionRepository{
+		GetPostRevisionFunc: func(ctx context.Context, postID int64, number int) (*entity.Revision, error) {
+			return &entity.Revision{Number: number, Title: "Old title", Content: "Old content"}, nil
+		},
+	}
+
+	t.Run("Admin restores", func(t *testing.T) {
+		var updatedTitle string
+		postRepo := &MockPostRepository{
+			UpdatePostFunc: func(ctx context.Context, postID, authorID int64, role, title, content, contentHTML string, held bool) (*entity.Post, error) {
+				updatedTitle = title
+				assert.Equal(t, "admin", role)
+				return &entity.Post{ID: postID, Title: title, Content: content}, nil
+			},
+		}
+		uc := NewRevisionUsecase(revisionRepo, postRepo, &MockCommentRepository{}, sessionAuth(9, "admin"))
+
+		post, err := uc.RestorePostRevision(context.Background(), "token", 1, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "Old title", post.Title)
+		assert.Equal(t, "Old title", updatedTitle)
+	})
+
+	t.Run("Regular user is forbidden", func(t *testing.T) {
+		uc := NewRevisionUsecase(revisionRepo, &MockPostRepository{}, &MockCommentRepository{}, sessionAuth(2, "user"))
+
+		_, err := uc.RestorePostRevision(context.Background(), "token", 1, 1)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+}
+
+func TestRevisionUsecase_RestoreCommentRevision(t *testing.T) {
+	revisionRepo := &MockRevisionRepository{
+		GetCommentRevisionFunc: func(ctx context.Context, commentID int64, number int) (*entity.Revision, error) {
+			return &entity.Revision{Number: number, Content: "Old comment"}, nil
+		},
+	}
+	commentRepo := &MockCommentRepository{
+		UpdateCommentFunc: func(ctx context.Context, id, editorID int64, content, contentHTML string, held bool) (*entity.Comment, error) {
+			assert.Equal(t, int64(9), editorID)
+			return &entity.Comment{ID: id, Content: content}, nil
+		},
+	}
+	uc := NewRevisionUsecase(revisionRepo, &MockPostRepository{}, commentRepo, sessionAuth(9, "admin"))
+
+	comment, err := uc.RestoreCommentRevision(context.Background(), "token", 4, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Old comment", comment.Content)
+}
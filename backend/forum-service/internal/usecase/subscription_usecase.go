@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/mailer"
+)
+
+var ErrInvalidEmail = errors.New("invalid email address")
+
+// digestExcerptLength - сколько символов комментария попадает в письмо
+const digestExcerptLength = 200
+
+// PostSubscriber подписывает автора на его новый пост
+type PostSubscriber interface {
+	SubscribeAuthor(ctx context.Context, userID, postID int64)
+}
+
+type SubscriptionUsecaseInterface interface {
+	Subscribe(ctx context.Context, token string, postID int64) error
+	Unsubscribe(ctx context.Context, token string, postID int64) error
+	GetSubscriptions(ctx context.Context, token string) ([]entity.Subscription, error)
+	GetDigestSettings(ctx context.Context, token string) (*entity.DigestSettings, error)
+	UpdateDigestSettings(ctx context.Context, token, email string, frequency entity.DigestFrequency) (*entity.DigestSettings, error)
+	UnsubscribeByToken(ctx context.Context, unsubscribeToken string) error
+	SendDigests(ctx context.Context) (int, error)
+}
+
+type SubscriptionUsecase struct {
+	subscriptionRepo repository.SubscriptionRepository
+	postRepo         repository.PostRepository
+	authClient       pb.AuthServiceClient
+	sender           mailer.Sender
+	// baseURL - внешний адрес форума для ссылок в письмах
+	baseURL string
+	logger  *logger.Logger
+	now     func() time.Time
+}
+
+func NewSubscriptionUsecase(
+	subscriptionRepo repository.SubscriptionRepository,
+	postRepo repository.PostRepository,
+	authClient pb.AuthServiceClient,
+	sender mailer.Sender,
+	baseURL string,
+	logger *logger.Logger,
+) *SubscriptionUsecase {
+	return &SubscriptionUsecase{
+		subscriptionRepo: subscriptionRepo,
+		postRepo:         postRepo,
+		authClient:       authClient,
+		sender:           sender,
+		baseURL:          strings.TrimRight(baseURL, "/"),
+		logger:           logger,
+		now:              time.Now,
+	}
+}
+
+func (uc *SubscriptionUsecase) Subscribe(ctx context.Context, token string, postID int64) error {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return err
+	}
+	if _, err := uc.postRepo.GetPostByID(ctx, postID); err != nil {
+		return err
+	}
+	return uc.subscriptionRepo.Subscribe(ctx, session.UserId, postID)
+}
+
+func (uc *SubscriptionUsecase) Unsubscribe(ctx context.Context, token string, postID int64) error {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return err
+	}
+	return uc.subscriptionRepo.Unsubscribe(ctx, session.UserId, postID)
+}
+
+func (uc *SubscriptionUsecase) GetSubscriptions(ctx context.Context, token string) ([]entity.Subscription, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+	return uc.subscriptionRepo.GetSubscriptions(ctx, session.UserId)
+}
+
+// SubscribeAuthor реализует PostSubscriber. Пост уже создан, поэтому ошибка только логируется.
+func (uc *SubscriptionUsecase) SubscribeAuthor(ctx context.Context, userID, postID int64) {
+	if err := uc.subscriptionRepo.Subscribe(ctx, userID, postID); err != nil {
+		uc.logError("Failed to subscribe author to post", err)
+	}
+}
+
+// GetDigestSettings возвращает настройки дайджеста; если пользователь их не задавал, дайджест выключен
+func (uc *SubscriptionUsecase) GetDigestSettings(ctx context.Context, token string) (*entity.DigestSettings, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := uc.subscriptionRepo.GetDigestSettings(ctx, session.UserId)
+	if errors.Is(err, repository.ErrDigestSettingsNotFound) {
+		return &entity.DigestSettings{UserID: session.UserId, Frequency: entity.DigestOff}, nil
+	}
+	return settings, err
+}
+
+func (uc *SubscriptionUsecase) UpdateDigestSettings(
+	ctx context.Context,
+	token,
+	email string,
+	frequency entity.DigestFrequency,
+) (*entity.DigestSettings, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := entity.ParseDigestFrequency(string(frequency)); err != nil {
+		return nil, err
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return nil, ErrInvalidEmail
+	}
+
+	unsubscribeToken, err := newUnsubscribeToken()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &entity.DigestSettings{
+		UserID:           session.UserId,
+		Email:            addr.Address,
+		Frequency:        frequency,
+		UnsubscribeToken: unsubscribeToken,
+	}
+	if err := uc.subscriptionRepo.SaveDigestSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UnsubscribeByToken выключает дайджест по ссылке из письма, без авторизации
+func (uc *SubscriptionUsecase) UnsubscribeByToken(ctx context.Context, unsubscribeToken string) error {
+	if unsubscribeToken == "" {
+		return repository.ErrDigestSettingsNotFound
+	}
+	return uc.subscriptionRepo.DisableDigestByToken(ctx, unsubscribeToken)
+}
+
+// SendDigests отправляет дайджесты всем пользователям, у которых подошел срок, и возвращает
+// число отправленных писем. Ошибка отправки одному пользователю не останавливает остальных:
+// такой пользователь получит дайджест при следующем запуске.
+func (uc *SubscriptionUsecase) SendDigests(ctx context.Context) (int, error) {
+	now := uc.now()
+	due, err := uc.subscriptionRepo.GetDueDigests(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, settings := range due {
+		since := settings.CreatedAt
+		if settings.SentUntil != nil {
+			since = *settings.SentUntil
+		}
+
+		comments, err := uc.subscriptionRepo.GetDigestComments(ctx, settings.UserID, since)
+		if err != nil {
+			uc.logError("Failed to collect digest comments", err)
+			continue
+		}
+
+		// Граница сдвигается только до последнего отправленного комментария: не вошедшие
+		// в лимит и созданные во время рассылки попадут в следующий дайджест
+		if len(comments) > 0 {
+			since = comments[len(comments)-1].CreatedAt
+			sort.SliceStable(comments, func(i, j int) bool { return comments[i].PostID < comments[j].PostID })
+			if err := uc.sender.Send(ctx, uc.composeDigest(settings, comments)); err != nil {
+				uc.logError("Failed to send digest", err)
+				continue
+			}
+			sent++
+		}
+
+		// Отметка ставится и для пустого дайджеста: расписание считается от текущего момента, граница не меняется
+		if err := uc.subscriptionRepo.MarkDigestSent(ctx, settings.UserID, now, since); err != nil {
+			uc.logError("Failed to mark digest as sent", err)
+		}
+	}
+	return sent, nil
+}
+
+// composeDigest собирает письмо: комментарии идут подряд по постам, внутри поста - в порядке выборки
+func (uc *SubscriptionUsecase) composeDigest(settings entity.DigestSettings, comments []entity.DigestComment) mailer.Message {
+	unsubscribeURL := uc.baseURL + "/api/v1/digest/unsubscribe?token=" + url.QueryEscape(settings.UnsubscribeToken)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "New comments in discussions you follow (%d):\n", len(comments))
+
+	var postID int64
+	for _, c := range comments {
+		if c.PostID != postID {
+			postID = c.PostID
+			fmt.Fprintf(&body, "\n%s\n%s/posts/%d\n", c.PostTitle, uc.baseURL, c.PostID)
+		}
+		fmt.Fprintf(&body, "  - %s: %s\n", c.AuthorName, excerpt(c.Content, digestExcerptLength))
+	}
+
+	fmt.Fprintf(&body, "\nYou receive this %s digest because you follow these posts.\n", settings.Frequency)
+	fmt.Fprintf(&body, "Unsubscribe: %s\n", unsubscribeURL)
+
+	return mailer.Message{
+		To:      settings.Email,
+		Subject: fmt.Sprintf("Forum digest: %d new comments", len(comments)),
+		Body:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}
+}
+
+func (uc *SubscriptionUsecase) logError(msg string, err error) {
+	if uc.logger != nil {
+		uc.logger.Error(msg, err)
+	}
+}
+
+// excerpt обрезает текст до limit символов и сворачивает переводы строк
+func excerpt(s string, limit int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit]) + "…"
+}
+
+func newUnsubscribeToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/mailer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockSubscriptionRepository struct {
+	SubscribeFunc            func(ctx context.Context, userID, postID int64) error
+	UnsubscribeFunc          func(ctx context.Context, userID, postID int64) error
+	GetSubscriptionsFunc     func(ctx context.Context, userID int64) ([]entity.Subscription, error)
+	GetDigestSettingsFunc    func(ctx context.Context, userID int64) (*entity.DigestSettings, error)
+	SaveDigestSettingsFunc   func(ctx context.Context, settings *entity.DigestSettings) error
+	DisableDigestByTokenFunc func(ctx context.Context, token string) error
+	GetDueDigestsFunc        func(ctx context.Context, now time.Time) ([]entity.DigestSettings, error)
+	GetDigestCommentsFunc    func(ctx context.Context, userID int64, since time.Time) ([]entity.DigestComment, error)
+	MarkDigestSentFunc       func(ctx context.Context, userID int64, sentAt, sentUntil time.Time) error
+}
+
+func (m *MockSubscriptionRepository) Subscribe(ctx context.Context, userID, postID int64) error {
+	return m.SubscribeFunc(ctx, userID, postID)
+}
+
+func (m *MockSubscriptionRepository) Unsubscribe(ctx context.Context, userID, postID int64) error {
+	return m.UnsubscribeFunc(ctx, userID, postID)
+}
+
+func (m *MockSubscriptionRepository) GetSubscriptions(ctx context.Context, userID int64) ([]entity.Subscription, error) {
+	return m.GetSubscriptionsFunc(ctx, userID)
+}
+
+func (m *MockSubscriptionRepository) GetDigestSettings(ctx context.Context, userID int64) (*entity.DigestSettings, error) {
+	return m.GetDigestSettingsFunc(ctx, userID)
+}
+
+func (m *MockSubscriptionRepository) SaveDigestSettings(ctx context.Context, settings *entity.DigestSettings) error {
+	return m.SaveDigestSettingsFunc(ctx, settings)
+}
+
+func (m *MockSubscriptionRepository) DisableDigestByToken(ctx context.Context, token string) error {
+	return m.DisableDigestByTokenFunc(ctx, token)
+}
+
+func (m *MockSubscriptionRepository) GetDueDigests(ctx context.Context, now time.Time) ([]entity.DigestSettings, error) {
+	return m.GetDueDigestsFunc(ctx, now)
+}
+
+func (m *MockSubscriptionRepository) GetDigestComments(ctx context.Context, userID int64, since time.Time) ([]entity.DigestComment, error) {
+	return m.GetDigestCommentsFunc(ctx, userID, since)
+}
+
+func (m *MockSubscriptionRepository) MarkDigestSent(ctx context.Context, userID int64, sentAt, sentUntil time.Time) error {
+	return m.MarkDigestSentFunc(ctx, userID, sentAt, sentUntil)
+}
+
+type recordingSender struct {
+	messages []mailer.Message
+	err      error
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg mailer.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func TestSubscriptionUsecase_Subscribe(t *testing.T) {
+	var subscribed []int64
+	repo := &MockSubscriptionRepository{
+		SubscribeFunc: func(ctx context.Context, userID, postID int64) error {
+			assert.Equal(t, int64(7), userID)
+			subscribed = append(subscribed, postID)
+			return nil
+		},
+	}
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			if id != 1 {
+				return nil, repository.ErrPostNotFound
+			}
+			return &entity.Post{ID: 1}, nil
+		},
+	}
+	uc := NewSubscriptionUsecase(repo, postRepo, sessionAuth(7, "user"), nil, "http://forum", nil)
+
+	assert.NoError(t, uc.Subscribe(context.Background(), "token", 1))
+	assert.ErrorIs(t, uc.Subscribe(context.Background(), "token", 2), repository.ErrPostNotFound)
+	assert.Equal(t, []int64{1}, subscribed)
+}
+
+func TestPostUsecase_CreatePost_SubscribesAuthor(t *testing.T) {
+	var subscribed [][2]int64
+	subs := NewSubscriptionUsecase(&MockSubscriptionRepository{
+		SubscribeFunc: func(ctx context.Context, userID, postID int64) error {
+			subscribed = append(subscribed, [2]int64{userID, postID})
+			return errors.New("db error")
+		},
+	}, nil, nil, nil, "", nil)
+
+	uc := NewPostUsecase(&MockPostRepository{
+		CreatePostFunc: func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
+			return 5, nil
+		},
+	}, sessionAuth(3, "user"), nil)
+	uc.Subscriptions = subs
+
+	// Ошибка подписки не отменяет создание поста
+	post, err := uc.CreatePost(context.Background(), "token", "Title", "Content", nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), post.ID)
+	assert.Equal(t, [][2]int64{{3, 5}}, subscribed)
+}
+
+func TestSubscriptionUsecase_UpdateDigestSettings(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		frequency entity.DigestFrequency
+		wantErr   error
+	}{
+		{name: "Success", email: "Alice <alice@example.com>", frequency: entity.DigestWeekly},
+		{name: "Invalid email", email: "not-an-email", frequency: entity.DigestDaily, wantErr: ErrInvalidEmail},
+		{name: "Invalid frequency", email: "alice@example.com", frequency: "hourly", wantErr: entity.ErrInvalidDigestFrequency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *entity.DigestSettings
+			repo := &MockSubscriptionRepository{
+				SaveDigestSettingsFunc: func(ctx context.Context, settings *entity.DigestSettings) error {
+					saved = settings
+					return nil
+				},
+			}
+			uc := NewSubscriptionUsecase(repo, nil, sessionAuth(7, "user"), nil, "", nil)
+
+			settings, err := uc.UpdateDigestSettings(context.Background(), "token", tt.email, tt.frequency)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "alice@example.com", settings.Email)
+			assert.Equal(t, int64(7), settings.UserID)
+			assert.Len(t, settings.UnsubscribeToken, 48)
+		})
+	}
+}
+
+func TestSubscriptionUsecase_GetDigestSettings_Default(t *testing.T) {
+	repo := &MockSubscriptionRepository{
+		GetDigestSettingsFunc: func(ctx context.Context, userID int64) (*entity.DigestSettings, error) {
+			return nil, repository.ErrDigestSettingsNotFound
+		},
+	}
+	uc := NewSubscriptionUsecase(repo, nil, sessionAuth(7, "user"), nil, "", nil)
+
+	settings, err := uc.GetDigestSettings(context.Background(), "token")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.DigestOff, settings.Frequency)
+}
+
+func TestSubscriptionUsecase_SendDigests(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	lastSent := now.Add(-25 * time.Hour)
+	sentUntil := now.Add(-26 * time.Hour)
+	created := now.Add(-48 * time.Hour)
+	lastComment := now.Add(-time.Hour)
+
+	var since = map[int64]time.Time{}
+	var marked []int64
+	var until = map[int64]time.Time{}
+	repo := &MockSubscriptionRepository{
+		GetDueDigestsFunc: func(ctx context.Context, at time.Time) ([]entity.DigestSettings, error) {
+			assert.Equal(t, now, at)
+			return []entity.DigestSettings{
+				{UserID: 1, Email: "one@example.com", Frequency: entity.DigestDaily, UnsubscribeToken: "tok1", LastSentAt: &lastSent, SentUntil: &sentUntil},
+				{UserID: 2, Email: "two@example.com", Frequency: entity.DigestWeekly, UnsubscribeToken: "tok2", CreatedAt: created},
+				{UserID: 3, Email: "three@example.com", Frequency: entity.DigestDaily, UnsubscribeToken: "tok3", CreatedAt: created},
+			}, nil
+		},
+		GetDigestCommentsFunc: func(ctx context.Context, userID int64, from time.Time) ([]entity.DigestComment, error) {
+			since[userID] = from
+			switch userID {
+			case 1:
+				return []entity.DigestComment{
+					{ID: 10, PostID: 3, PostTitle: "First", AuthorName: "bob", Content: "hello\nthere", CreatedAt: now.Add(-3 * time.Hour)},
+					{ID: 12, PostID: 4, PostTitle: "Second", AuthorName: "bob", Content: "again", CreatedAt: now.Add(-2 * time.Hour)},
+					{ID: 13, PostID: 3, PostTitle: "First", AuthorName: "carol", Content: "hi", CreatedAt: lastComment},
+				}, nil
+			case 2:
+				return []entity.DigestComment{}, nil
+			default:
+				return nil, errors.New("db error")
+			}
+		},
+		MarkDigestSentFunc: func(ctx context.Context, userID int64, sentAt, sentUntil time.Time) error {
+			assert.Equal(t, now, sentAt)
+			marked = append(marked, userID)
+			until[userID] = sentUntil
+			return nil
+		},
+	}
+	sender := &recordingSender{}
+	uc := NewSubscriptionUsecase(repo, nil, nil, sender, "http://forum/", nil)
+	uc.now = func() time.Time { return now }
+
+	sent, err := uc.SendDigests(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	assert.Equal(t, sentUntil, since[1])
+	assert.Equal(t, created, since[2])
+	// Пустой дайджест отмечается, сбойный - нет
+	assert.Equal(t, []int64{1, 2}, marked)
+	// Граница - последний отправленный комментарий, для пустого дайджеста она не сдвигается
+	assert.Equal(t, map[int64]time.Time{1: lastComment, 2: created}, until)
+
+	if assert.Len(t, sender.messages, 1) {
+		msg := sender.messages[0]
+		assert.Equal(t, "one@example.com", msg.To)
+		assert.Equal(t, "Forum digest: 3 new comments", msg.Subject)
+		assert.Contains(t, msg.Body, "First\nhttp://forum/posts/3\n  - bob: hello there\n  - carol: hi\n")
+		assert.Contains(t, msg.Body, "Second\nhttp://forum/posts/4\n  - bob: again\n")
+		assert.Contains(t, msg.Body, "http://forum/api/v1/digest/unsubscribe?token=tok1")
+		assert.Equal(t, "<http://forum/api/v1/digest/unsubscribe?token=tok1>", msg.Headers["List-Unsubscribe"])
+		assert.Equal(t, "List-Unsubscribe=One-Click", msg.Headers["List-Unsubscribe-Post"])
+	}
+}
+
+func TestSubscriptionUsecase_SendDigests_SendError(t *testing.T) {
+	marked := false
+	repo := &MockSubscriptionRepository{
+		GetDueDigestsFunc: func(ctx context.Context, at time.Time) ([]entity.DigestSettings, error) {
+			return []entity.DigestSettings{{UserID: 1, Email: "one@example.com", Frequency: entity.DigestDaily}}, nil
+		},
+		GetDigestCommentsFunc: func(ctx context.Context, userID int64, from time.Time) ([]entity.DigestComment, error) {
+			return []entity.DigestComment{{ID: 10, PostID: 3, PostTitle: "First", AuthorName: "bob", Content: "hi"}}, nil
+		},
+		MarkDigestSentFunc: func(ctx context.Context, userID int64, sentAt, sentUntil time.Time) error {
+			marked = true
+			return nil
+		},
+	}
+	uc := NewSubscriptionUsecase(repo, nil, nil, &recordingSender{err: errors.New("smtp down")}, "", nil)
+
+	sent, err := uc.SendDigests(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	// Дайджест будет повторен при следующем запуске
+	assert.False(t, marked)
+}
+
+func TestSubscriptionUsecase_UnsubscribeByToken(t *testing.T) {
+	repo := &MockSubscriptionRepository{
+		DisableDigestByTokenFunc: func(ctx context.Context, token string) error {
+			assert.Equal(t, "tok", token)
+			return nil
+		},
+	}
+	uc := NewSubscriptionUsecase(repo, nil, nil, nil, "", nil)
+
+	assert.NoError(t, uc.UnsubscribeByToken(context.Background(), "tok"))
+	assert.ErrorIs(t, uc.UnsubscribeByToken(context.Background(), ""), repository.ErrDigestSettingsNotFound)
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+)
+
+const (
+	DefaultTagsLimit = 10
+	MaxTagsLimit     = 50
+)
+
+// PostTagger загружает теги постов для ленты
+type PostTagger interface {
+	PostTags(ctx context.Context, postIDs []int64) (map[int64][]string, error)
+}
+
+type TagUsecaseInterface interface {
+	SearchTags(ctx context.Context, prefix string, limit int) ([]entity.Tag, error)
+	GetTag(ctx context.Context, slug string) (*entity.Tag, error)
+	SetPostTags(ctx context.Context, token string, postID int64, tags []string) ([]string, error)
+	AddSynonym(ctx context.Context, token, slug, synonym string) (*entity.Tag, error)
+	DeleteSynonym(ctx context.Context, token, synonym string) error
+	MergeTags(ctx context.Context, token, source, target string) (*entity.Tag, error)
+}
+
+type TagUsecase struct {
+	tagRepo    repository.TagRepository
+	postRepo   repository.PostRepository
+	authClient pb.AuthServiceClient
+}
+
+func NewTagUsecase(
+	tagRepo repository.TagRepository,
+	postRepo repository.PostRepository,
+	authClient pb.AuthServiceClient,
+) *TagUsecase {
+	return &TagUsecase{
+		tagRepo:    tagRepo,
+		postRepo:   postRepo,
+		authClient: authClient,
+	}
+}
+
+// SearchTags подсказывает теги по началу slug или синонима, популярные первыми
+func (uc *TagUsecase) SearchTags(ctx context.Context, prefix string, limit int) ([]entity.Tag, error) {
+	if limit <= 0 {
+		limit = DefaultTagsLimit
+	}
+	if limit > MaxTagsLimit {
+		limit = MaxTagsLimit
+	}
+
+	// Недописанный префикс вроде "c++" нормализуется так же, как сам тег
+	if prefix != "" {
+		normalized, err := entity.NormalizeTag(prefix)
+		if err != nil {
+			return []entity.Tag{}, nil
+		}
+		prefix = normalized
+	}
+
+	return uc.tagRepo.SearchTags(ctx, prefix, limit)
+}
+
+func (uc *TagUsecase) GetTag(ctx context.Context, slug string) (*entity.Tag, error) {
+	slug, err := entity.NormalizeTag(slug)
+	if err != nil {
+		return nil, repository.ErrTagNotFound
+	}
+
+	tag, err := uc.tagRepo.GetTagBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Synonyms, err = uc.tagRepo.GetSynonyms(ctx, tag.ID); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// SetPostTags заменяет теги поста. Менять теги может автор поста и модератор.
+func (uc *TagUsecase) SetPostTags(ctx context.Context, token string, postID int64, tags []string) ([]string, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	slugs, err := entity.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := uc.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != session.UserId && !isModerator(session.UserRole) {
+		return nil, ErrForbidden
+	}
+
+	return uc.tagRepo.SetPostTags(ctx, postID, slugs)
+}
+
+// AddSynonym делает synonym другим написанием тега slug. Доступно только модераторам.
+func (uc *TagUsecase) AddSynonym(ctx context.Context, token, slug, synonym string) (*entity.Tag, error) {
+	if err := uc.requireModerator(ctx, token); err != nil {
+		return nil, err
+	}
+
+	synonym, err := entity.NormalizeTag(synonym)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := uc.GetTag(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.tagRepo.AddSynonym(ctx, tag.ID, synonym); err != nil {
+		return nil, err
+	}
+
+	tag.Synonyms = append(tag.Synonyms, synonym)
+	return tag, nil
+}
+
+func (uc *TagUsecase) DeleteSynonym(ctx context.Context, token, synonym string) error {
+	if err := uc.requireModerator(ctx, token); err != nil {
+		return err
+	}
+
+	synonym, err := entity.NormalizeTag(synonym)
+	if err != nil {
+		return repository.ErrTagNotFound
+	}
+	return uc.tagRepo.DeleteSynonym(ctx, synonym)
+}
+
+// MergeTags объединяет тег source с target: посты и синонимы переходят к target,
+// а source становится его синонимом. Доступно только модераторам.
+func (uc *TagUsecase) MergeTags(ctx context.Context, token, source, target string) (*entity.Tag, error) {
+	if err := uc.requireModerator(ctx, token); err != nil {
+		return nil, err
+	}
+
+	from, err := uc.GetTag(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	into, err := uc.GetTag(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	// source может оказаться синонимом target
+	if from.ID == into.ID {
+		return nil, repository.ErrTagConflict
+	}
+
+	if err := uc.tagRepo.MergeTags(ctx, from.ID, into.ID); err != nil {
+		return nil, err
+	}
+	return uc.GetTag(ctx, into.Slug)
+}
+
+// PostTags реализует PostTagger
+func (uc *TagUsecase) PostTags(ctx context.Context, postIDs []int64) (map[int64][]string, error) {
+	if len(postIDs) == 0 {
+		return map[int64][]string{}, nil
+	}
+	return uc.tagRepo.GetTagsByPostIDs(ctx, postIDs)
+}
+
+func (uc *TagUsecase) requireModerator(ctx context.Context, token string) error {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return err
+	}
+	if !isModerator(session.UserRole) {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type MockTagRepository struct {
+	SetPostTagsFunc      func(ctx context.Context, postID int64, slugs []string) ([]string, error)
+	GetTagsByPostIDsFunc func(ctx context.Context, postIDs []int64) (map[int64][]string, error)
+	SearchTagsFunc       func(ctx context.Context, prefix string, limit int) ([]entity.Tag, error)
+	GetTagBySlugFunc     func(ctx context.Context, slug string) (*entity.Tag, error)
+	GetSynonymsFunc      func(ctx context.Context, tagID int64) ([]string, error)
+	AddSynonymFunc       func(ctx context.Context, tagID int64, synonym string) error
+	DeleteSynonymFunc    func(ctx context.Context, synonym string) error
+	MergeTagsFunc        func(ctx context.Context, sourceID, targetID int64) error
+}
+
+func (m *MockTagRepository) SetPostTags(ctx context.Context, postID int64, slugs []string) ([]string, error) {
+	return m.SetPostTagsFunc(ctx, postID, slugs)
+}
+
+func (m *MockTagRepository) GetTagsByPostIDs(ctx context.Context, postIDs []int64) (map[int64][]string, error) {
+	return m.GetTagsByPostIDsFunc(ctx, postIDs)
+}
+
+func (m *MockTagRepository) SearchTags(ctx context.Context, prefix string, limit int) ([]entity.Tag, error) {
+	return m.SearchTagsFunc(ctx, prefix, limit)
+}
+
+func (m *MockTagRepository) GetTagBySlug(ctx context.Context, slug string) (*entity.Tag, error) {
+	return m.GetTagBySlugFunc(ctx, slug)
+}
+
+func (m *MockTagRepository) GetSynonyms(ctx context.Context, tagID int64) ([]string, error) {
+	if m.GetSynonymsFunc != nil {
+		return m.GetSynonymsFunc(ctx, tagID)
+	}
+	return []string{}, nil
+}
+
+func (m *MockTagRepository) AddSynonym(ctx context.Context, tagID int64, synonym string) error {
+	return m.AddSynonymFunc(ctx, tagID, synonym)
+}
+
+func (m *MockTagRepository) DeleteSynonym(ctx context.Context, synonym string) error {
+	return m.DeleteSynonymFunc(ctx, synonym)
+}
+
+func (m *MockTagRepository) MergeTags(ctx context.Context, sourceID, targetID int64) error {
+	return m.MergeTagsFunc(ctx, sourceID, targetID)
+}
+
+// tagsBySlug эмулирует GetTagBySlug: "golang" - синоним "go"
+func tagsBySlug(ctx context.Context, slug string) (*entity.Tag, error) {
+	switch slug {
+	case "go", "golang":
+		return &entity.Tag{ID: 1, Slug: "go"}, nil
+	case "go-lang":
+		return &entity.Tag{ID: 2, Slug: "go-lang"}, nil
+	}
+	return nil, repository.ErrTagNotFound
+}
+
+func TestTagUsecase_SearchTags(t *testing.T) {
+	var gotPrefix string
+	var gotLimit int
+	repo := &MockTagRepository{
+		SearchTagsFunc: func(ctx context.Context, prefix string, limit int) ([]entity.Tag, error) {
+			gotPrefix, gotLimit = prefix, limit
+			return []entity.Tag{{ID: 1, Slug: "c-sharp"}}, nil
+		},
+	}
+	uc := NewTagUsecase(repo, nil, nil)
+
+	tags, err := uc.SearchTags(context.Background(), "C Sh", 0)
+	assert.NoError(t, err)
+	assert.Len(t, tags, 1)
+	assert.Equal(t, "c-sh", gotPrefix)
+	assert.Equal(t, DefaultTagsLimit, gotLimit)
+
+	_, err = uc.SearchTags(context.Background(), "", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, "", gotPrefix)
+	assert.Equal(t, MaxTagsLimit, gotLimit)
+
+	// Префикс без букв и цифр ничего не находит
+	gotPrefix = "unchanged"
+	tags, err = uc.SearchTags(context.Background(), "##", 5)
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+	assert.Equal(t, "unchanged", gotPrefix)
+}
+
+func TestTagUsecase_SetPostTags(t *testing.T) {
+	postRepo := &MockPostRepository{
+		GetPostByIDFunc: func(ctx context.Context, id int64) (*entity.Post, error) {
+			return &entity.Post{ID: id, AuthorID: 7}, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		userID  int64
+		role    string
+		tags    []string
+		wantErr error
+	}{
+		{name: "Author", userID: 7, role: "user", tags: []string{"Go", "go ", "SQL"}},
+		{name: "Moderator", userID: 8, role: "moderator", tags: []string{"go"}},
+		{name: "Another user", userID: 8, role: "user", tags: []string{"go"}, wantErr: ErrForbidden},
+		{name: "Too many", userID: 7, role: "user", tags: []string{"a", "b", "c", "d", "e", "f"}, wantErr: entity.ErrTooManyTags},
+		{name: "Invalid", userID: 7, role: "user", tags: []string{"!!"}, wantErr: entity.ErrInvalidTag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []string
+			repo := &MockTagRepository{
+				SetPostTagsFunc: func(ctx context.Context, postID int64, slugs []string) ([]string, error) {
+					saved = slugs
+					return slugs, nil
+				},
+			}
+			uc := NewTagUsecase(repo, postRepo, sessionAuth(tt.userID, tt.role))
+
+			got, err := uc.SetPostTags(context.Background(), "token", 1, tt.tags)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, saved, got)
+			if tt.name == "Author" {
+				assert.Equal(t, []string{"go", "sql"}, saved)
+			}
+		})
+	}
+}
+
+func TestTagUsecase_AddSynonym(t *testing.T) {
+	var added string
+	repo := &MockTagRepository{
+		GetTagBySlugFunc: tagsBySlug,
+		AddSynonymFunc: func(ctx context.Context, tagID int64, synonym string) error {
+			assert.Equal(t, int64(1), tagID)
+			added = synonym
+			return nil
+		},
+	}
+
+	uc := NewTagUsecase(repo, nil, sessionAuth(1, "moderator"))
+	tag, err := uc.AddSynonym(context.Background(), "token", "go", "Go Lang")
+	assert.NoError(t, err)
+	assert.Equal(t, "go-lang", added)
+	assert.Equal(t, []string{"go-lang"}, tag.Synonyms)
+
+	_, err = uc.AddSynonym(context.Background(), "token", "rust", "rs")
+	assert.ErrorIs(t, err, repository.ErrTagNotFound)
+
+	uc = NewTagUsecase(repo, nil, sessionAuth(1, "user"))
+	_, err = uc.AddSynonym(context.Background(), "token", "go", "golang")
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestTagUsecase_MergeTags(t *testing.T) {
+	var merged [2]int64
+	repo := &MockTagRepository{
+		GetTagBySlugFunc: tagsBySlug,
+		MergeTagsFunc: func(ctx context.Context, sourceID, targetID int64) error {
+			merged = [2]int64{sourceID, targetID}
+			return nil
+		},
+	}
+	uc := NewTagUsecase(repo, nil, sessionAuth(1, "admin"))
+
+	tag, err := uc.MergeTags(context.Background(), "token", "go-lang", "go")
+	assert.NoError(t, err)
+	assert.Equal(t, [2]int64{2, 1}, merged)
+	assert.Equal(t, "go", tag.Slug)
+
+	// Синоним уже указывает на тот же тег
+	_, err = uc.MergeTags(context.Background(), "token", "golang", "go")
+	assert.ErrorIs(t, err, repository.ErrTagConflict)
+
+	_, err = uc.MergeTags(context.Background(), "token", "go", "rust")
+	assert.ErrorIs(t, err, repository.ErrTagNotFound)
+}
+
+func TestPostUsecase_Tags(t *testing.T) {
+	var created bool
+	postRepo := &MockPostRepository{
+		CreatePostFunc: func(ctx context.Context, post *entity.Post, poll *entity.PollInput) (int64, error) {
+			// Теги сохраняются в транзакции поста, синоним заменяется основным тегом
+			assert.Equal(t, []string{"golang"}, post.Tags)
+			post.Tags = []string{"go"}
+			created = true
+			return 3, nil
+		},
+		GetPostsFunc: func(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, error) {
+			return []*entity.Post{{ID: 3, AuthorID: 7}, {ID: 4, AuthorID: 7}}, nil
+		},
+	}
+	tagRepo := &MockTagRepository{
+		GetTagsByPostIDsFunc: func(ctx context.Context, postIDs []int64) (map[int64][]string, error) {
+			assert.Equal(t, []int64{3, 4}, postIDs)
+			return map[int64][]string{3: {"go"}}, nil
+		},
+	}
+	auth := sessionAuth(7, "user")
+	auth.GetUserProfileFunc = func(ctx context.Context, in *pb.GetUserProfileRequest, opts ...grpc.CallOption) (*pb.GetUserProfileResponse, error) {
+		return &pb.GetUserProfileResponse{User: &pb.User{Id: in.UserId, Username: "user"}}, nil
+	}
+	uc := NewPostUsecase(postRepo, auth, nil)
+	uc.Tags = NewTagUsecase(tagRepo, postRepo, auth)
+
+	post, err := uc.CreatePost(context.Background(), "token", "Title", "Content", []string{"golang"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go"}, post.Tags)
+
+	// Неверные теги отклоняются до создания поста
+	created = false
+	_, err = uc.CreatePost(context.Background(), "token", "Title", "Content", []string{"a", "b", "c", "d", "e", "f"}, nil, nil)
+	assert.ErrorIs(t, err, entity.ErrTooManyTags)
+	assert.False(t, created)
+
+	posts, _, err := uc.GetPosts(context.Background(), entity.PostFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go"}, posts[0].Tags)
+	assert.Nil(t, posts[1].Tags)
+
+	tagRepo.GetTagsByPostIDsFunc = func(ctx context.Context, postIDs []int64) (map[int64][]string, error) {
+		return nil, errors.New("db down")
+	}
+	_, _, err = uc.GetPosts(context.Background(), entity.PostFilter{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+)
+
+var ErrRetentionExpired = errors.New("retention period expired")
+
+// DefaultTrashRetention - срок хранения удаленных постов и комментариев до окончательной очистки
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+type TrashUsecaseInterface interface {
+	GetTrash(ctx context.Context, token string) ([]entity.TrashItem, error)
+	Restore(ctx context.Context, token string, kind entity.TrashKind, id int64) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type TrashUsecase struct {
+	trashRepo  repository.TrashRepository
+	authClient pb.AuthServiceClient
+	retention  time.Duration
+}
+
+func NewTrashUsecase(
+	trashRepo repository.TrashRepository,
+	authClient pb.AuthServiceClient,
+	retention time.Duration,
+) *TrashUsecase {
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	return &TrashUsecase{
+		trashRepo:  trashRepo,
+		authClient: authClient,
+		retention:  retention,
+	}
+}
+
+// GetTrash возвращает корзину пользователя; модераторы видят все удаленные объекты
+func (uc *TrashUsecase) GetTrash(ctx context.Context, token string) ([]entity.TrashItem, error) {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.trashRepo.GetTrash(ctx, session.UserId, isModerator(session.UserRole), time.Now().Add(-uc.retention))
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(uc.retention)
+	}
+	return items, nil
+}
+
+// Restore возвращает объект из корзины до истечения срока хранения.
+// Модератор может восстановить любой объект, автор - только удаленный им самим.
+func (uc *TrashUsecase) Restore(ctx context.Context, token string, kind entity.TrashKind, id int64) error {
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return err
+	}
+
+	item, err := uc.trashRepo.GetTrashItem(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+
+	if !isModerator(session.UserRole) && (item.AuthorID != session.UserId || item.DeletedBy != session.UserId) {
+		return ErrForbidden
+	}
+	if time.Since(item.DeletedAt) > uc.retention {
+		return ErrRetentionExpired
+	}
+
+	return uc.trashRepo.Restore(ctx, kind, id)
+}
+
+// PurgeExpired окончательно удаляет объекты с истекшим сроком хранения
+func (uc *TrashUsecase) PurgeExpired(ctx context.Context) (int64, error) {
+	return uc.trashRepo.Purge(ctx, time.Now().Add(-uc.retention))
+}
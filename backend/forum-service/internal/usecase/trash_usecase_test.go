@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MockTrashRepository struct {
+	GetTrashFunc     func(ctx context.Context, authorID int64, all bool, since time.Time) ([]entity.TrashItem, error)
+	GetTrashItemFunc func(ctx context.Context, kind entity.TrashKind, id int64) (*entity.TrashItem, error)
+	RestoreFunc      func(ctx context.Context, kind entity.TrashKind, id int64) error
+	PurgeFunc        func(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (m *MockTrashRepository) GetTrash(ctx context.Context, authorID int64, all bool, since time.Time) ([]entity.TrashItem, error) {
+	return m.GetTrashFunc(ctx, authorID, all, since)
+}
+
+func (m *MockTrashRepository) GetTrashItem(ctx context.Context, kind entity.TrashKind, id int64) (*entity.TrashItem, error) {
+	if m.GetTrashItemFunc != nil {
+		return m.GetTrashItemFunc(ctx, kind, id)
+	}
+	return nil, repository.ErrTrashItemNotFound
+}
+
+func (m *MockTrashRepository) Restore(ctx context.Context, kind entity.TrashKind, id int64) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, kind, id)
+	}
+	return nil
+}
+
+func (m *MockTrashRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return m.PurgeFunc(ctx, before)
+}
+
+func TestTrashUsecase_GetTrash(t *testing.T) {
+	deletedAt := time.Now().Add(-time.Hour)
+
+	for _, tt := range []struct {
+		role    string
+		wantAll bool
+	}{
+		{role: "user", wantAll: false},
+		{role: "moderator", wantAll: true},
+	} {
+		t.Run(tt.role, func(t *testing.T) {
+			repo := &MockTrashRepository{
+				GetTrashFunc: func(ctx context.Context, authorID int64, all bool, since time.Time) ([]entity.TrashItem, error) {
+					assert.Equal(t, int64(1), authorID)
+					assert.Equal(t, tt.wantAll, all)
+					assert.WithinDuration(t, time.Now().Add(-24*time.Hour), since, time.Minute)
+					return []entity.TrashItem{{Kind: entity.TrashKindPost, ID: 1, DeletedAt: deletedAt}}, nil
+				},
+			}
+			uc := NewTrashUsecase(repo, sessionAuth(1, tt.role), 24*time.Hour)
+
+			items, err := uc.GetTrash(context.Background(), "token")
+			assert.NoError(t, err)
+			assert.Equal(t, deletedAt.Add(24*time.Hour), items[0].PurgeAt)
+		})
+	}
+}
+
+func TestTrashUsecase_Restore(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int64
+		role    string
+		item    entity.TrashItem
+		wantErr error
+	}{
+		{
+			name:   "Author restores own deletion",
+			userID: 1,
+			role:   "user",
+			item:   entity.TrashItem{AuthorID: 1, DeletedBy: 1, DeletedAt: time.Now().Add(-time.Hour)},
+		},
+		{
+			name:    "Author cannot undo moderator deletion",
+			userID:  1,
+			role:    "user",
+			item:    entity.TrashItem{AuthorID: 1, DeletedBy: 9, DeletedAt: time.Now().Add(-time.Hour)},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "Other user",
+			userID:  2,
+			role:    "user",
+			item:    entity.TrashItem{AuthorID: 1, DeletedBy: 1, DeletedAt: time.Now().Add(-time.Hour)},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:   "Moderator",
+			userID: 9,
+			role:   "moderator",
+			item:   entity.TrashItem{AuthorID: 1, DeletedBy: 1, DeletedAt: time.Now().Add(-time.Hour)},
+		},
+		{
+			name:    "Retention expired",
+			userID:  9,
+			role:    "admin",
+			item:    entity.TrashItem{AuthorID: 1, DeletedBy: 1, DeletedAt: time.Now().Add(-48 * time.Hour)},
+			wantErr: ErrRetentionExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restored := false
+			repo := &MockTrashRepository{
+				GetTrashItemFunc: func(ctx context.Context, kind entity.TrashKind, id int64) (*entity.TrashItem, error) {
+					item := tt.item
+					return &item, nil
+				},
+				RestoreFunc: func(ctx context.Context, kind entity.TrashKind, id int64) error {
+					restored = true
+					return nil
+				},
+			}
+			uc := NewTrashUsecase(repo, sessionAuth(tt.userID, tt.role), 24*time.Hour)
+
+			err := uc.Restore(context.Background(), "token", entity.TrashKindComment, 5)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantErr == nil, restored)
+		})
+	}
+}
+
+func TestTrashUsecase_PurgeExpired(t *testing.T) {
+	repo := &MockTrashRepository{
+		PurgeFunc: func(ctx context.Context, before time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now().Add(-DefaultTrashRetention), before, time.Minute)
+			return 3, nil
+		},
+	}
+	uc := NewTrashUsecase(repo, &MockAuthServiceClient{}, 0)
+
+	purged, err := uc.PurgeExpired(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/logger"
+)
+
+var ErrInvalidVote = errors.New("vote value must be -1, 0 or 1")
+
+type VoteUsecaseInterface interface {
+	VotePost(ctx context.Context, token string, postID int64, value int) (*entity.PostScore, error)
+	RefreshScores(ctx context.Context) error
+}
+
+type VoteUsecase struct {
+	voteRepo   repository.VoteRepository
+	authClient pb.AuthServiceClient
+	logger     *logger.Logger
+	// Notifications уведомляет автора о голосах "за"; nil - без уведомлений
+	Notifications ReactionNotifier
+}
+
+func NewVoteUsecase(
+	voteRepo repository.VoteRepository,
+	authClient pb.AuthServiceClient,
+	logger *logger.Logger,
+) *VoteUsecase {
+	return &VoteUsecase{
+		voteRepo:   voteRepo,
+		authClient: authClient,
+		logger:     logger,
+	}
+}
+
+func (uc *VoteUsecase) VotePost(ctx context.Context, token string, postID int64, value int) (*entity.PostScore, error) {
+	if value < -1 || value > 1 {
+		return nil, ErrInvalidVote
+	}
+
+	session, err := validateToken(ctx, uc.authClient, token)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := uc.voteRepo.SetVote(ctx, postID, session.UserId, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == 1 && uc.Notifications != nil {
+		uc.Notifications.NotifyReaction(ctx, session.UserId, postID)
+	}
+	return score, nil
+}
+
+// RefreshScores пересчитывает счетчики и рейтинг всех постов; вызывается фоновой задачей
+func (uc *VoteUsecase) RefreshScores(ctx context.Context) error {
+	updated, err := uc.voteRepo.RefreshScores(ctx)
+	if err != nil {
+		return err
+	}
+	if uc.logger != nil {
+		uc.logger.Debugf("Post scores refreshed for %d posts", updated)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/jaliks17/ffffforum/backend/proto"
+
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/entity"
+	"github.com/jaliks17/ffffforum/backend/forum-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type MockVoteRepository struct {
+	SetVoteFunc       func(ctx context.Context, postID, userID int64, value int) (*entity.PostScore, error)
+	RefreshScoresFunc func(ctx context.Context) (int64, error)
+}
+
+func (m *MockVoteRepository) SetVote(ctx context.Context, postID, userID int64, value int) (*entity.PostScore, error) {
+	if m.SetVoteFunc != nil {
+		return m.SetVoteFunc(ctx, postID, userID, value)
+	}
+	return nil, nil
+}
+
+func (m *MockVoteRepository) RefreshScores(ctx context.Context) (int64, error) {
+	if m.RefreshScoresFunc != nil {
+		return m.RefreshScoresFunc(ctx)
+	}
+	return 0, nil
+}
+
+func TestVoteUsecase_VotePost(t *testing.T) {
+	validAuth := &MockAuthServiceClient{
+		ValidateTokenFunc: func(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateSessionResponse, error) {
+			return &pb.ValidateSessionResponse{Valid: true, UserId: 7, UserRole: "user"}, nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		value       int
+		auth        *MockAuthServiceClient
+		repo        *MockVoteRepository
+		want        *entity.PostScore
+		expectedErr error
+	}{
+		{
+			name:  "Success",
+			value: 1,
+			auth:  validAuth,
+			repo: &MockVoteRepository{
+				SetVoteFunc: func(ctx context.Context, postID, userID int64, value int) (*entity.PostScore, error) {
+					assert.Equal(t, int64(1), postID)
+					assert.Equal(t, int64(7), userID)
+					return &entity.PostScore{PostID: postID, Upvotes: 1, Score: 1, UserVote: value}, nil
+				},
+			},
+			want: &entity.PostScore{PostID: 1, Upvotes: 1, Score: 1, UserVote: 1},
+		},
+		{
+			name:        "Invalid value",
+			value:       5,
+			auth:        validAuth,
+			repo:        &MockVoteRepository{},
+			expectedErr: ErrInvalidVote,
+		},
+		{
+			name:  "Invalid token",
+			value: -1,
+			auth: &MockAuthServiceClient{
+				ValidateTokenFunc: func(ctx context.Context, in *pb.ValidateTokenRequest, opts ...grpc.CallOption) (*pb.ValidateSessionResponse, error) {
+					return &pb.ValidateSessionResponse{Valid: false}, nil
+				},
+			},
+			repo:        &MockVoteRepository{},
+			expectedErr: ErrInvalidToken,
+		},
+		{
+			name:  "Post not found",
+			value: 0,
+			auth:  validAuth,
+			repo: &MockVoteRepository{
+				SetVoteFunc: func(ctx context.Context, postID, userID int64, value int) (*entity.PostScore, error) {
+					return nil, repository.ErrPostNotFound
+				},
+			},
+			expectedErr: repository.ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewVoteUsecase(tt.repo, tt.auth, nil)
+
+			got, err := uc.VotePost(context.Background(), "token", 1, tt.value)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVoteUsecase_RefreshScores(t *testing.T) {
+	uc := NewVoteUsecase(&MockVoteRepository{
+		RefreshScoresFunc: func(ctx context.Context) (int64, error) {
+			return 0, errors.New("database error")
+		},
+	}, &MockAuthServiceClient{}, nil)
+
+	assert.EqualError(t, uc.RefreshScores(context.Background()), "database error")
+}
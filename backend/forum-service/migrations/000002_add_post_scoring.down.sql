@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS post_votes;
+
+DROP INDEX IF EXISTS idx_posts_score;
+DROP INDEX IF EXISTS idx_posts_hot_score;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS hot_score,
+    DROP COLUMN IF EXISTS score,
+    DROP COLUMN IF EXISTS comment_count,
+    DROP COLUMN IF EXISTS downvotes,
+    DROP COLUMN IF EXISTS upvotes;
@@ -0,0 +1,25 @@
+ALTER TABLE posts
+    ADD COLUMN upvotes INT NOT NULL DEFAULT 0,
+    ADD COLUMN downvotes INT NOT NULL DEFAULT 0,
+    ADD COLUMN comment_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN score INT NOT NULL DEFAULT 0,
+    ADD COLUMN hot_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE TABLE post_votes (
+    post_id INT NOT NULL,
+    user_id INT NOT NULL,
+    value SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_posts_hot_score ON posts(hot_score DESC);
+CREATE INDEX idx_posts_score ON posts(score DESC, created_at DESC);
+
+-- Заполняем счетчики для уже существующих постов
+UPDATE posts p
+SET comment_count = (SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id);
+
+UPDATE posts
+SET hot_score = SIGN(comment_count) * LOG(GREATEST(comment_count, 1)) + EXTRACT(EPOCH FROM created_at) / 45000;
@@ -0,0 +1,22 @@
+CREATE TABLE post_revisions (
+    id SERIAL PRIMARY KEY,
+    post_id INT NOT NULL,
+    editor_id INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_revisions_post_id ON post_revisions(post_id, id);
+
+CREATE TABLE comment_revisions (
+    id SERIAL PRIMARY KEY,
+    comment_id INT NOT NULL,
+    editor_id INT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_comment_revisions_comment_id ON comment_revisions(comment_id, id);
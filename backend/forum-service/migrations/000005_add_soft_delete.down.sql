@@ -0,0 +1,16 @@
+-- Удаленные мягко записи при откате удаляются окончательно
+DELETE FROM comments WHERE deleted_at IS NOT NULL;
+DELETE FROM posts WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_comments_deleted_at;
+DROP INDEX IF EXISTS idx_posts_deleted_at;
+
+ALTER TABLE comments
+    DROP COLUMN IF EXISTS delete_reason,
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS delete_reason,
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,13 @@
+ALTER TABLE posts
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deleted_by INT,
+    ADD COLUMN delete_reason TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE comments
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deleted_by INT,
+    ADD COLUMN delete_reason TEXT NOT NULL DEFAULT '';
+
+-- Частичные индексы: корзину просматривает и очищает только фоновая задача и владельцы
+CREATE INDEX idx_posts_deleted_at ON posts(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_comments_deleted_at ON comments(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Отрисованный и очищенный HTML хранится рядом с исходным Markdown.
+-- Для старых записей поле остается пустым и заполняется при следующей правке,
+-- до этого HTML строится при чтении.
+ALTER TABLE posts ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,13 @@
+CREATE TABLE mentions (
+    id SERIAL PRIMARY KEY,
+    source_type VARCHAR(16) NOT NULL,
+    source_id INT NOT NULL,
+    post_id INT NOT NULL,
+    author_id INT NOT NULL,
+    mentioned_user_id INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    UNIQUE (source_type, source_id, mentioned_user_id)
+);
+
+CREATE INDEX idx_mentions_mentioned_user_id ON mentions(mentioned_user_id, created_at DESC);
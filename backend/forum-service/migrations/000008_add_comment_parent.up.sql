@@ -0,0 +1,3 @@
+ALTER TABLE comments ADD COLUMN parent_id INT REFERENCES comments(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_comments_parent_id ON comments(parent_id) WHERE parent_id IS NOT NULL;
@@ -0,0 +1,15 @@
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    recipient_id INT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    actor_id INT NOT NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id INT NOT NULL,
+    post_id INT NOT NULL,
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notifications_recipient_id ON notifications(recipient_id, id DESC);
+CREATE INDEX idx_notifications_unread ON notifications(recipient_id) WHERE NOT is_read;
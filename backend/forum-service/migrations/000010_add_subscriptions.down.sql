@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS digest_settings;
+DROP TABLE IF EXISTS subscriptions;
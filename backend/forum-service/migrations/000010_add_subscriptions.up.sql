@@ -0,0 +1,24 @@
+CREATE TABLE subscriptions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    post_id INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    UNIQUE (user_id, post_id)
+);
+
+CREATE INDEX idx_subscriptions_post_id ON subscriptions(post_id);
+
+-- Настройки дайджеста: адрес хранится здесь, так как Auth Service не знает email пользователей
+CREATE TABLE digest_settings (
+    user_id INT PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    frequency VARCHAR(16) NOT NULL DEFAULT 'daily',
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    -- Время создания последнего комментария, вошедшего в отправленный дайджест
+    sent_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (frequency IN ('off', 'daily', 'weekly'))
+);
@@ -0,0 +1,17 @@
+-- Закладка ссылается на пост, а закладка на комментарий дополнительно хранит comment_id
+CREATE TABLE bookmarks (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    post_id INT NOT NULL,
+    comment_id INT,
+    folder VARCHAR(64) NOT NULL DEFAULT '',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_bookmarks_user_post ON bookmarks(user_id, post_id) WHERE comment_id IS NULL;
+CREATE UNIQUE INDEX idx_bookmarks_user_comment ON bookmarks(user_id, comment_id) WHERE comment_id IS NOT NULL;
+CREATE INDEX idx_bookmarks_user_id ON bookmarks(user_id, id DESC);
+CREATE INDEX idx_bookmarks_tags ON bookmarks USING GIN (tags);
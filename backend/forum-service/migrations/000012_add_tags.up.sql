@@ -0,0 +1,25 @@
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(32) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Синонимы указывают на основной тег; при слиянии тегов старый slug становится синонимом
+CREATE TABLE tag_synonyms (
+    slug VARCHAR(32) PRIMARY KEY,
+    tag_id INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_tag_synonyms_tag_id ON tag_synonyms(tag_id);
+
+CREATE TABLE post_tags (
+    post_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    PRIMARY KEY (post_id, tag_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_tags_tag_id ON post_tags(tag_id);
@@ -0,0 +1,26 @@
+-- Файл загружается до публикации и привязывается к посту или комментарию при их создании.
+-- Содержимое хранится в BlobStore, здесь - только метаданные и ключи объектов.
+CREATE TABLE attachments (
+    id SERIAL PRIMARY KEY,
+    owner_id INT NOT NULL,
+    post_id INT,
+    comment_id INT,
+    -- Комната чата, к сообщению в которой прикреплен файл; сами сообщения хранит chat-service
+    chat_room_id INT,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(127) NOT NULL,
+    size BIGINT NOT NULL,
+    width INT NOT NULL DEFAULT 0,
+    height INT NOT NULL DEFAULT 0,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CHECK (post_id IS NULL OR comment_id IS NULL),
+    CHECK (chat_room_id IS NULL OR (post_id IS NULL AND comment_id IS NULL))
+);
+
+CREATE INDEX idx_attachments_post_id ON attachments(post_id) WHERE post_id IS NOT NULL;
+CREATE INDEX idx_attachments_comment_id ON attachments(comment_id) WHERE comment_id IS NOT NULL;
+CREATE INDEX idx_attachments_owner_id ON attachments(owner_id);
@@ -0,0 +1,43 @@
+-- Опрос прикрепляется к посту при создании; у поста может быть только один опрос
+CREATE TABLE polls (
+    id SERIAL PRIMARY KEY,
+    post_id INT NOT NULL UNIQUE,
+    question VARCHAR(300) NOT NULL,
+    multiple BOOLEAN NOT NULL DEFAULT FALSE,
+    anonymous BOOLEAN NOT NULL DEFAULT TRUE,
+    closes_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_polls_closes_at ON polls(closes_at) WHERE closed_at IS NULL;
+
+CREATE TABLE poll_options (
+    id SERIAL PRIMARY KEY,
+    poll_id INT NOT NULL,
+    position SMALLINT NOT NULL,
+    text VARCHAR(200) NOT NULL,
+    UNIQUE (poll_id, position),
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE
+);
+
+-- poll_voters гарантирует один голос на пользователя, даже если выбрано несколько вариантов
+CREATE TABLE poll_voters (
+    poll_id INT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, user_id),
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE
+);
+
+CREATE TABLE poll_votes (
+    poll_id INT NOT NULL,
+    option_id INT NOT NULL,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (option_id, user_id),
+    FOREIGN KEY (poll_id, user_id) REFERENCES poll_voters(poll_id, user_id) ON DELETE CASCADE,
+    FOREIGN KEY (option_id) REFERENCES poll_options(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_poll_votes_poll_id ON poll_votes(poll_id);
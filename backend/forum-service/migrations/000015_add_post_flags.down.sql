@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS moderation_log;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS announcement,
+    DROP COLUMN IF EXISTS locked,
+    DROP COLUMN IF EXISTS pinned;
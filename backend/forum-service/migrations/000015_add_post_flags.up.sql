@@ -0,0 +1,18 @@
+ALTER TABLE posts
+    ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN locked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN announcement BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Журнал действий модераторов: кто, что и над каким объектом сделал
+CREATE TABLE moderation_log (
+    id SERIAL PRIMARY KEY,
+    actor_id BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id BIGINT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_moderation_log_target ON moderation_log(target_type, target_id);
+CREATE INDEX idx_moderation_log_created_at ON moderation_log(created_at);
@@ -0,0 +1,21 @@
+-- Жалобы пользователей на посты, комментарии, сообщения чата и других пользователей
+CREATE TABLE reports (
+    id SERIAL PRIMARY KEY,
+    reporter_id BIGINT NOT NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id BIGINT NOT NULL,
+    target_author_id BIGINT NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    resolution VARCHAR(16) NOT NULL DEFAULT '',
+    resolution_note TEXT NOT NULL DEFAULT '',
+    resolved_by BIGINT,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Пока жалоба открыта, повторно пожаловаться на тот же объект нельзя
+CREATE UNIQUE INDEX idx_reports_open_unique ON reports(reporter_id, target_type, target_id) WHERE status = 'open';
+CREATE INDEX idx_reports_target ON reports(target_type, target_id);
+CREATE INDEX idx_reports_status_created_at ON reports(status, created_at);
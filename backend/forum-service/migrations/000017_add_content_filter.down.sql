@@ -0,0 +1,6 @@
+ALTER TABLE comments DROP COLUMN IF EXISTS held;
+ALTER TABLE posts DROP COLUMN IF EXISTS held;
+
+DROP TABLE IF EXISTS spam_samples;
+DROP TABLE IF EXISTS content_fingerprints;
+DROP TABLE IF EXISTS content_filter_rules;
@@ -0,0 +1,33 @@
+-- Правила фильтра контента: запрещенные слова и регулярные выражения
+CREATE TABLE content_filter_rules (
+    id SERIAL PRIMARY KEY,
+    pattern VARCHAR(200) NOT NULL,
+    is_regex BOOLEAN NOT NULL DEFAULT FALSE,
+    action VARCHAR(16) NOT NULL,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Отпечатки недавнего контента для поиска дубликатов; старые строки удаляет фоновая задача
+CREATE TABLE content_fingerprints (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_content_fingerprints_fingerprint ON content_fingerprints(fingerprint, created_at);
+CREATE INDEX idx_content_fingerprints_created_at ON content_fingerprints(created_at);
+
+-- Примеры для классификатора спама, собранные из решений модераторов
+CREATE TABLE spam_samples (
+    id SERIAL PRIMARY KEY,
+    content TEXT NOT NULL,
+    is_spam BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Контент, отложенный фильтром, скрыт из ленты и списка комментариев до решения модератора
+ALTER TABLE posts ADD COLUMN held BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD COLUMN held BOOLEAN NOT NULL DEFAULT FALSE;
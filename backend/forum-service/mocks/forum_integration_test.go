@@ -97,14 +97,16 @@ func TestForumService_Integrated(t *testing.T) {
 
 		t.Run("Create and get post", func(t *testing.T) {
 			now := time.Now()
-			createQuery := `INSERT INTO posts (title, content, author_id, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
-			getQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
+			createQuery := `INSERT INTO posts (title, content, author_id, created_at, content_html, held, hot_score) VALUES ($1, $2, $3, $4, $5, $6, SIGN(0) * LOG(GREATEST(ABS(0), 1)) + EXTRACT(EPOCH FROM $4::timestamptz) / 45000) RETURNING id`
+			getQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`
 
+			deps.mock.ExpectBegin()
 			deps.mock.ExpectQuery(createQuery).
-				WithArgs("Test Post", "Test Content", int64(1), sqlmock.AnyArg()).
+				WithArgs("Test Post", "Test Content", int64(1), sqlmock.AnyArg(), "<p>Test Content</p>\n", false).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			deps.mock.ExpectCommit()
 
-			post, err := deps.postUC.CreatePost(context.Background(), "valid_token", "Test Post", "Test Content")
+			post, err := deps.postUC.CreatePost(context.Background(), "valid_token", "Test Post", "Test Content", nil, nil, nil)
 			require.NoError(t, err)
 			assert.Equal(t, int64(1), post.ID)
 
@@ -119,7 +121,7 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Get posts list", func(t *testing.T) {
-			query := `SELECT id, title, content, author_id, created_at FROM posts ORDER BY created_at DESC`
+			query := `SELECT id, title, content, content_html, author_id, created_at, upvotes, downvotes, comment_count, score, pinned, locked, announcement FROM posts WHERE deleted_at IS NULL AND NOT held ORDER BY announcement DESC, pinned DESC, created_at DESC`
 			now := time.Now()
 
 			deps.mock.ExpectQuery(query).
@@ -127,15 +129,15 @@ func TestForumService_Integrated(t *testing.T) {
 					AddRow(1, "First Post", "First Content", int64(1), now).
 					AddRow(2, "Second Post", "Second Content", int64(2), now.Add(-time.Hour)))
 
-			posts, authorNames, err := deps.postUC.GetPosts(context.Background())
+			posts, authorNames, err := deps.postUC.GetPosts(context.Background(), entity.PostFilter{})
 			require.NoError(t, err)
 			assert.Len(t, posts, 2)
 			assert.Equal(t, "testuser", authorNames[1])
 		})
 
 		t.Run("Create comment", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `INSERT INTO comments (content, author_id, post_id, author_name) VALUES ($1, $2, $3, $4) RETURNING id`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`
+			commentQuery := `INSERT INTO comments (content, author_id, post_id, author_name, content_html, parent_id, held) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -143,7 +145,7 @@ func TestForumService_Integrated(t *testing.T) {
 					AddRow(1, "Test Post", "Test Content", int64(1), time.Now()))
 
 			deps.mock.ExpectQuery(commentQuery).
-				WithArgs("Test Comment", int64(1), int64(1), "testuser").
+				WithArgs("Test Comment", int64(1), int64(1), "testuser", "<p>Test Comment</p>\n", nil, false).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 			comment := &entity.Comment{
@@ -158,8 +160,8 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Get comments", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`
+			commentQuery := `SELECT id, content, content_html, author_id, post_id, parent_id, author_name, created_at, edited_at, deleted_at FROM comments WHERE post_id = $1 AND NOT held ORDER BY id DESC`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -177,12 +179,12 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Update post", func(t *testing.T) {
-			query := `UPDATE posts SET title = $1, content = $2 WHERE id = $3 AND (author_id = $4 OR $5 = 'admin') RETURNING id, title, content, author_id, created_at`
+			query := `WITH prev AS ( SELECT id, title, content FROM posts WHERE id = $3 AND deleted_at IS NULL AND (author_id = $4 OR $5 = 'admin') FOR UPDATE ), rev AS ( INSERT INTO post_revisions (post_id, editor_id, title, content) SELECT id, $4, title, content FROM prev ) UPDATE posts p SET title = $1, content = $2, content_html = $6, held = p.held OR $7, updated_at = NOW() FROM prev WHERE p.id = prev.id RETURNING p.id, p.title, p.content, p.content_html, p.author_id, p.created_at, p.updated_at, p.held`
 
 			deps.mock.ExpectQuery(query).
-				WithArgs("Updated Title", "Updated Content", int64(1), int64(1), "user").
-				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
-					AddRow(1, "Updated Title", "Updated Content", int64(1), time.Now()))
+				WithArgs("Updated Title", "Updated Content", int64(1), int64(1), "user", "<p>Updated Content</p>\n", false).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "created_at", "updated_at", "held"}).
+					AddRow(1, "Updated Title", "Updated Content", "<p>Updated Content</p>\n", int64(1), time.Now(), time.Now(), false))
 
 			post, err := deps.postUC.UpdatePost(context.Background(), "valid_token", 1, "Updated Title", "Updated Content")
 			require.NoError(t, err)
@@ -190,13 +192,13 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Delete post", func(t *testing.T) {
-			query := `DELETE FROM posts WHERE id = $1 AND (author_id = $2 OR $3 = 'admin')`
+			query := `UPDATE posts SET deleted_at = NOW(), deleted_by = $2, delete_reason = $4 WHERE id = $1 AND deleted_at IS NULL AND (author_id = $2 OR $3 IN ('admin', 'moderator'))`
 
 			deps.mock.ExpectExec(query).
-				WithArgs(int64(1), int64(1), "user").
+				WithArgs(int64(1), int64(1), "user", "").
 				WillReturnResult(sqlmock.NewResult(0, 1))
 
-			err := deps.postUC.DeletePost(context.Background(), "valid_token", 1)
+			err := deps.postUC.DeletePost(context.Background(), "valid_token", 1, "")
 			require.NoError(t, err)
 		})
 
@@ -208,28 +210,30 @@ func TestForumService_Integrated(t *testing.T) {
 		defer deps.db.Close()
 
 		t.Run("Create post database error", func(t *testing.T) {
-			query := `INSERT INTO posts (title, content, author_id, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
+			query := `INSERT INTO posts (title, content, author_id, created_at, content_html, held, hot_score) VALUES ($1, $2, $3, $4, $5, $6, SIGN(0) * LOG(GREATEST(ABS(0), 1)) + EXTRACT(EPOCH FROM $4::timestamptz) / 45000) RETURNING id`
 
+			deps.mock.ExpectBegin()
 			deps.mock.ExpectQuery(query).
-				WithArgs("Bad Post", "Bad Content", int64(1), sqlmock.AnyArg()).
+				WithArgs("Bad Post", "Bad Content", int64(1), sqlmock.AnyArg(), "<p>Bad Content</p>\n", false).
 				WillReturnError(errors.New("database error"))
+			deps.mock.ExpectRollback()
 
-			_, err := deps.postUC.CreatePost(context.Background(), "valid_token", "Bad Post", "Bad Content")
+			_, err := deps.postUC.CreatePost(context.Background(), "valid_token", "Bad Post", "Bad Content", nil, nil, nil)
 			require.Error(t, err)
 		})
 
 		t.Run("Get posts list error", func(t *testing.T) {
-			query := `SELECT id, title, content, author_id, created_at FROM posts ORDER BY created_at DESC`
+			query := `SELECT id, title, content, content_html, author_id, created_at, upvotes, downvotes, comment_count, score, pinned, locked, announcement FROM posts WHERE deleted_at IS NULL AND NOT held ORDER BY announcement DESC, pinned DESC, created_at DESC`
 
 			deps.mock.ExpectQuery(query).
 				WillReturnError(errors.New("database error"))
 
-			_, _, err := deps.postUC.GetPosts(context.Background())
+			_, _, err := deps.postUC.GetPosts(context.Background(), entity.PostFilter{})
 			require.Error(t, err)
 		})
 
 		t.Run("Create comment for non-existent post", func(t *testing.T) {
-			query := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
+			query := `SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`
 
 			deps.mock.ExpectQuery(query).
 				WithArgs(int64(999)).
@@ -247,10 +251,10 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Update non-existent post", func(t *testing.T) {
-			query := `UPDATE posts SET title = $1, content = $2 WHERE id = $3 AND (author_id = $4 OR $5 = 'admin') RETURNING id, title, content, author_id, created_at`
+			query := `WITH prev AS ( SELECT id, title, content FROM posts WHERE id = $3 AND deleted_at IS NULL AND (author_id = $4 OR $5 = 'admin') FOR UPDATE ), rev AS ( INSERT INTO post_revisions (post_id, editor_id, title, content) SELECT id, $4, title, content FROM prev ) UPDATE posts p SET title = $1, content = $2, content_html = $6, held = p.held OR $7, updated_at = NOW() FROM prev WHERE p.id = prev.id RETURNING p.id, p.title, p.content, p.content_html, p.author_id, p.created_at, p.updated_at, p.held`
 
 			deps.mock.ExpectQuery(query).
-				WithArgs("New Title", "New Content", int64(999), int64(1), "user").
+				WithArgs("New Title", "New Content", int64(999), int64(1), "user", "<p>New Content</p>\n", false).
 				WillReturnError(sql.ErrNoRows)
 
 			_, err := deps.postUC.UpdatePost(context.Background(), "valid_token", 999, "New Title", "New Content")
@@ -259,13 +263,13 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Delete non-existent post", func(t *testing.T) {
-			query := `DELETE FROM posts WHERE id = $1 AND (author_id = $2 OR $3 = 'admin')`
+			query := `UPDATE posts SET deleted_at = NOW(), deleted_by = $2, delete_reason = $4 WHERE id = $1 AND deleted_at IS NULL AND (author_id = $2 OR $3 IN ('admin', 'moderator'))`
 
 			deps.mock.ExpectExec(query).
-				WithArgs(int64(999), int64(1), "user").
+				WithArgs(int64(999), int64(1), "user", "").
 				WillReturnResult(sqlmock.NewResult(0, 0))
 
-			err := deps.postUC.DeletePost(context.Background(), "valid_token", 999)
+			err := deps.postUC.DeletePost(context.Background(), "valid_token", 999, "")
 			require.Error(t, err)
 			assert.True(t, errors.Is(err, repository.ErrPostNotFound))
 		})
@@ -279,7 +283,7 @@ func TestForumService_Integrated(t *testing.T) {
 
 			errorPostUC := usecase.NewPostUsecase(deps.postRepo, errorAuthClient, nil)
 
-			_, err := errorPostUC.CreatePost(context.Background(), "invalid_token", "Test", "Content")
+			_, err := errorPostUC.CreatePost(context.Background(), "invalid_token", "Test", "Content", nil, nil, nil)
 			require.Error(t, err)
 		})
 
@@ -291,20 +295,20 @@ func TestForumService_Integrated(t *testing.T) {
 		defer deps.db.Close()
 
 		t.Run("Empty posts list", func(t *testing.T) {
-			query := `SELECT id, title, content, author_id, created_at FROM posts ORDER BY created_at DESC`
+			query := `SELECT id, title, content, content_html, author_id, created_at, upvotes, downvotes, comment_count, score, pinned, locked, announcement FROM posts WHERE deleted_at IS NULL AND NOT held ORDER BY announcement DESC, pinned DESC, created_at DESC`
 
 			deps.mock.ExpectQuery(query).
 				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}))
 
-			posts, authorNames, err := deps.postUC.GetPosts(context.Background())
+			posts, authorNames, err := deps.postUC.GetPosts(context.Background(), entity.PostFilter{})
 			require.NoError(t, err)
 			assert.Empty(t, posts)
 			assert.Empty(t, authorNames)
 		})
 
 		t.Run("Create comment database error", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `INSERT INTO comments (content, author_id, post_id, author_name) VALUES ($1, $2, $3, $4) RETURNING id`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`
+			commentQuery := `INSERT INTO comments (content, author_id, post_id, author_name, content_html, parent_id, held) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -312,7 +316,7 @@ func TestForumService_Integrated(t *testing.T) {
 					AddRow(1, "Test Post", "Test Content", int64(1), time.Now()))
 
 			deps.mock.ExpectQuery(commentQuery).
-				WithArgs("Bad Comment", int64(1), int64(1), "testuser").
+				WithArgs("Bad Comment", int64(1), int64(1), "testuser", "<p>Bad Comment</p>\n", nil, false).
 				WillReturnError(errors.New("database error"))
 
 			comment := &entity.Comment{
@@ -339,12 +343,12 @@ func TestForumService_Integrated(t *testing.T) {
 
 			postUC := usecase.NewPostUsecase(deps.postRepo, authClient, nil)
 
-			query := `UPDATE posts SET title = $1, content = $2 WHERE id = $3 AND (author_id = $4 OR $5 = 'admin') RETURNING id, title, content, author_id, created_at`
+			query := `WITH prev AS ( SELECT id, title, content FROM posts WHERE id = $3 AND deleted_at IS NULL AND (author_id = $4 OR $5 = 'admin') FOR UPDATE ), rev AS ( INSERT INTO post_revisions (post_id, editor_id, title, content) SELECT id, $4, title, content FROM prev ) UPDATE posts p SET title = $1, content = $2, content_html = $6, held = p.held OR $7, updated_at = NOW() FROM prev WHERE p.id = prev.id RETURNING p.id, p.title, p.content, p.content_html, p.author_id, p.created_at, p.updated_at, p.held`
 
 			deps.mock.ExpectQuery(query).
-				WithArgs("Admin Updated", "Admin Content", int64(1), int64(2), "admin").
-				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
-					AddRow(1, "Admin Updated", "Admin Content", int64(1), time.Now()))
+				WithArgs("Admin Updated", "Admin Content", int64(1), int64(2), "admin", "<p>Admin Content</p>\n", false).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_html", "author_id", "created_at", "updated_at", "held"}).
+					AddRow(1, "Admin Updated", "Admin Content", "<p>Admin Content</p>\n", int64(1), time.Now(), time.Now(), false))
 
 			_, err := postUC.UpdatePost(context.Background(), "admin_token", 1, "Admin Updated", "Admin Content")
 			require.NoError(t, err)
@@ -366,7 +370,7 @@ func TestForumService_Integrated(t *testing.T) {
 
 			commentUC := usecase.NewCommentUseCase(deps.commentRepo, deps.postRepo, authClient)
 
-			deps.mock.ExpectQuery(`SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`).
+			deps.mock.ExpectQuery(`SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`).
 				WithArgs(int64(1)).
 				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "created_at"}).
 					AddRow(1, "Test Post", "Test Content", int64(1), time.Now()))
@@ -392,7 +396,7 @@ func TestForumService_Integrated(t *testing.T) {
 
 			postUC := usecase.NewPostUsecase(deps.postRepo, authClient, nil)
 
-			_, err := postUC.CreatePost(context.Background(), "invalid_token", "Test", "Content")
+			_, err := postUC.CreatePost(context.Background(), "invalid_token", "Test", "Content", nil, nil, nil)
 			require.Error(t, err)
 			assert.Contains(t, err.Error(), "invalid token")
 		})
@@ -411,10 +415,10 @@ func TestForumService_Integrated(t *testing.T) {
 
 			postUC := usecase.NewPostUsecase(deps.postRepo, authClient, nil)
 
-			query := `UPDATE posts SET title = $1, content = $2 WHERE id = $3 AND (author_id = $4 OR $5 = 'admin') RETURNING id, title, content, author_id, created_at`
+			query := `WITH prev AS ( SELECT id, title, content FROM posts WHERE id = $3 AND deleted_at IS NULL AND (author_id = $4 OR $5 = 'admin') FOR UPDATE ), rev AS ( INSERT INTO post_revisions (post_id, editor_id, title, content) SELECT id, $4, title, content FROM prev ) UPDATE posts p SET title = $1, content = $2, content_html = $6, held = p.held OR $7, updated_at = NOW() FROM prev WHERE p.id = prev.id RETURNING p.id, p.title, p.content, p.content_html, p.author_id, p.created_at, p.updated_at, p.held`
 
 			deps.mock.ExpectQuery(query).
-				WithArgs("New Title", "New Content", int64(1), int64(2), "user").
+				WithArgs("New Title", "New Content", int64(1), int64(2), "user", "<p>New Content</p>\n", false).
 				WillReturnError(repository.ErrPermissionDenied)
 
 			_, err := postUC.UpdatePost(context.Background(), "valid_token", 1, "New Title", "New Content")
@@ -422,8 +426,8 @@ func TestForumService_Integrated(t *testing.T) {
 			assert.True(t, errors.Is(err, repository.ErrPermissionDenied))
 		})
 		t.Run("Get comments database error", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`
+			commentQuery := `SELECT id, content, content_html, author_id, post_id, parent_id, author_name, created_at, edited_at, deleted_at FROM comments WHERE post_id = $1 AND NOT held ORDER BY id DESC`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -439,8 +443,8 @@ func TestForumService_Integrated(t *testing.T) {
 		})
 
 		t.Run("Empty comments list", func(t *testing.T) {
-			postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-			commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+			postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`
+			commentQuery := `SELECT id, content, content_html, author_id, post_id, parent_id, author_name, created_at, edited_at, deleted_at FROM comments WHERE post_id = $1 AND NOT held ORDER BY id DESC`
 
 			deps.mock.ExpectQuery(postQuery).
 				WithArgs(int64(1)).
@@ -593,7 +597,7 @@ func TestPostHandler(t *testing.T) {
 
 	t.Run("GetPosts database error", func(t *testing.T) {
 		mockUC := &mockPostUseCase{
-			getPostsFunc: func(ctx context.Context) ([]*entity.Post, map[int]string, error) {
+			getPostsFunc: func(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, map[int]string, error) {
 				return nil, nil, errors.New("database error")
 			},
 		}
@@ -775,7 +779,7 @@ func TestPostHandler(t *testing.T) {
 	})
 	t.Run("GetPosts success", func(t *testing.T) {
 		mockUC := &mockPostUseCase{
-			getPostsFunc: func(ctx context.Context) ([]*entity.Post, map[int]string, error) {
+			getPostsFunc: func(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, map[int]string, error) {
 				return []*entity.Post{
 					{
 						ID:        1,
@@ -930,8 +934,8 @@ func TestCommentHandler(t *testing.T) {
 		deps := setupTest(t)
 		defer deps.db.Close()
 
-		postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-		commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+		postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`
+		commentQuery := `SELECT id, content, content_html, author_id, post_id, parent_id, author_name, created_at, edited_at, deleted_at FROM comments WHERE post_id = $1 AND NOT held ORDER BY id DESC`
 
 		deps.mock.ExpectQuery(postQuery).
 			WithArgs(int64(1)).
@@ -959,8 +963,8 @@ func TestCommentHandler(t *testing.T) {
 		deps := setupTest(t)
 		defer deps.db.Close()
 
-		postQuery := `SELECT id, title, content, author_id, created_at FROM posts WHERE id = $1`
-		commentQuery := `SELECT id, content, author_id, post_id, author_name FROM comments WHERE post_id = $1 ORDER BY id DESC`
+		postQuery := `SELECT id, title, content, content_html, author_id, created_at, pinned, locked, announcement FROM posts WHERE id = $1 AND deleted_at IS NULL AND NOT held`
+		commentQuery := `SELECT id, content, content_html, author_id, post_id, parent_id, author_name, created_at, edited_at, deleted_at FROM comments WHERE post_id = $1 AND NOT held ORDER BY id DESC`
 
 		deps.mock.ExpectQuery(postQuery).
 			WithArgs(int64(1)).
@@ -994,20 +998,20 @@ func TestCommentHandler(t *testing.T) {
 type mockPostUseCase struct {
 	usecase.PostUsecaseInterface
 	createFunc   func(context.Context, string, string, string) (*entity.Post, error)
-	getPostsFunc func(context.Context) ([]*entity.Post, map[int]string, error)
+	getPostsFunc func(context.Context, entity.PostFilter) ([]*entity.Post, map[int]string, error)
 	deleteFunc   func(context.Context, string, int64) error
 	updateFunc   func(context.Context, string, int64, string, string) (*entity.Post, error)
 }
 
-func (m *mockPostUseCase) CreatePost(ctx context.Context, token, title, content string) (*entity.Post, error) {
+func (m *mockPostUseCase) CreatePost(ctx context.Context, token, title, content string, tags []string, attachmentIDs []int64, poll *entity.PollInput) (*entity.Post, error) {
 	return m.createFunc(ctx, token, title, content)
 }
 
-func (m *mockPostUseCase) GetPosts(ctx context.Context) ([]*entity.Post, map[int]string, error) {
-	return m.getPostsFunc(ctx)
+func (m *mockPostUseCase) GetPosts(ctx context.Context, filter entity.PostFilter) ([]*entity.Post, map[int]string, error) {
+	return m.getPostsFunc(ctx, filter)
 }
 
-func (m *mockPostUseCase) DeletePost(ctx context.Context, token string, postID int64) error {
+func (m *mockPostUseCase) DeletePost(ctx context.Context, token string, postID int64, reason string) error {
 	return m.deleteFunc(ctx, token, postID)
 }
 
@@ -1021,6 +1025,7 @@ type mockCommentUseCase struct {
 	getCommentsFunc     func(ctx context.Context, postID int64) ([]entity.Comment, error)
 	deleteCommentFunc   func(ctx context.Context, id int64) error
 	getCommentByIDFunc  func(ctx context.Context, id int64) (*entity.Comment, error)
+	updateCommentFunc   func(ctx context.Context, id, editorID int64, content string) (*entity.Comment, error)
 }
 
 func (m *mockCommentUseCase) CreateComment(ctx context.Context, comment *entity.Comment) error {
@@ -1037,7 +1042,7 @@ func (m *mockCommentUseCase) GetCommentsByPostID(ctx context.Context, postID int
 	return nil, nil
 }
 
-func (m *mockCommentUseCase) DeleteComment(ctx context.Context, commentID int64) error {
+func (m *mockCommentUseCase) DeleteComment(ctx context.Context, commentID, deletedBy int64, reason string) error {
 	if m.deleteCommentFunc != nil {
 		return m.deleteCommentFunc(ctx, commentID)
 	}
@@ -1049,4 +1054,15 @@ func (m *mockCommentUseCase) GetCommentByID(ctx context.Context, id int64) (*ent
 		return m.getCommentByIDFunc(ctx, id)
 	}
 	return nil, nil
+}
+
+func (m *mockCommentUseCase) GetCommentForModeration(ctx context.Context, id int64) (*entity.Comment, error) {
+	return m.GetCommentByID(ctx, id)
+}
+
+func (m *mockCommentUseCase) UpdateComment(ctx context.Context, id, editorID int64, content, contentHTML string, held bool) (*entity.Comment, error) {
+	if m.updateCommentFunc != nil {
+		return m.updateCommentFunc(ctx, id, editorID, content)
+	}
+	return nil, nil
 }
\ No newline at end of file
@@ -0,0 +1,36 @@
+// Package blobstore хранит содержимое вложений: на локальном диске или в S3-совместимом хранилище.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore хранит объекты по ключу вида "attachments/2024/05/abc.jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get возвращает содержимое объекта или ErrNotFound; вызывающий закрывает reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет объект; удаление отсутствующего объекта не считается ошибкой
+	Delete(ctx context.Context, key string) error
+}
+
+// validateKey запрещает пустые сегменты и выход за пределы хранилища через ".."
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Package chatpush доставляет события пользователям, подключенным к WebSocket чат-сервиса,
+// и дает модерации доступ к сообщениям чата.
+package chatpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TokenHeader - заголовок с общим секретом сервисов, который проверяет чат-сервис
+const TokenHeader = "X-Internal-Token"
+
+// Client отправляет события во внутренние endpoint-ы чат-сервиса
+type Client struct {
+	url        string
+	token      string
+	httpClient *http.Client
+	// PublishURL - endpoint рассылки подписчикам темы (/internal/publish); пустой - Publish недоступен
+	PublishURL string
+	// MessagesURL - базовый адрес /internal/messages; пустой - сообщения чата недоступны модерации
+	MessagesURL string
+	// RoomsURL - базовый адрес /internal/rooms; пустой - членство в комнатах не проверяется
+	RoomsURL string
+}
+
+// ErrMessageNotFound возвращается, если чат-сервис не нашел сообщение
+var ErrMessageNotFound = errors.New("chat message not found")
+
+func NewClient(url, token string) *Client {
+	return &Client{
+		url:        url,
+		token:      token,
+		httpClient: &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+type pushRequest struct {
+	UserID int64       `json:"user_id"`
+	Event  interface{} `json:"event"`
+}
+
+// Push передает событие пользователю userID. Если пользователь не в сети, чат-сервис
+// просто отбрасывает событие - это не ошибка.
+func (c *Client) Push(ctx context.Context, userID int64, event interface{}) error {
+	return c.post(ctx, c.url, pushRequest{UserID: userID, Event: event})
+}
+
+type publishRequest struct {
+	Topic string      `json:"topic"`
+	Event interface{} `json:"event"`
+}
+
+// Publish рассылает событие всем клиентам WebSocket, подписанным на topic.
+// Отсутствие подписчиков не считается ошибкой.
+func (c *Client) Publish(ctx context.Context, topic string, event interface{}) error {
+	if c.PublishURL == "" {
+		return errors.New("chat publish url is not configured")
+	}
+	return c.post(ctx, c.PublishURL, publishRequest{Topic: topic, Event: event})
+}
+
+// Message - сообщение чата в том виде, в каком его отдает /internal/messages/{id}
+type Message struct {
+	ID       int64  `json:"id"`
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Message  string `json:"message"`
+}
+
+// GetMessage возвращает сообщение чата по id
+func (c *Client) GetMessage(ctx context.Context, id int64) (*Message, error) {
+	var msg Message
+	if err := c.message(ctx, http.MethodGet, id, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// DeleteMessage удаляет сообщение чата
+func (c *Client) DeleteMessage(ctx context.Context, id int64) error {
+	return c.message(ctx, http.MethodDelete, id, nil)
+}
+
+// IsRoomMember сообщает, может ли пользователь userID читать комнату roomID
+func (c *Client) IsRoomMember(ctx context.Context, roomID, userID int64) (bool, error) {
+	if c.RoomsURL == "" {
+		return false, errors.New("chat rooms url is not configured")
+	}
+	status, err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%d/members/%d", c.RoomsURL, roomID, userID), nil, nil)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusForbidden, http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("chat request failed: status %d", status)
+}
+
+func (c *Client) message(ctx context.Context, method string, id int64, out interface{}) error {
+	if c.MessagesURL == "" {
+		return errors.New("chat messages url is not configured")
+	}
+	status, err := c.do(ctx, method, fmt.Sprintf("%s/%d", c.MessagesURL, id), nil, out)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return ErrMessageNotFound
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("chat request failed: status %d", status)
+	}
+	return nil
+}
+
+func (c *Client) post(ctx context.Context, url string, payload interface{}) error {
+	status, err := c.do(ctx, http.MethodPost, url, payload, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("chat request failed: status %d", status)
+	}
+	return nil
+}
+
+// do выполняет запрос к чат-сервису и при статусе 200 декодирует ответ в out
+func (c *Client) do(ctx context.Context, method, url string, payload, out interface{}) (int, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set(TokenHeader, c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK && out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, err
+		}
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,126 @@
+package chatpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Push(t *testing.T) {
+	var got struct {
+		UserID int64             `json:"user_id"`
+		Event  map[string]string `json:"event"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get(TokenHeader))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, "secret").Push(context.Background(), 7, map[string]string{"type": "notification"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), got.UserID)
+	assert.Equal(t, "notification", got.Event["type"])
+}
+
+func TestClient_Push_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, "wrong").Push(context.Background(), 7, nil)
+	assert.Error(t, err)
+}
+
+func TestClient_Publish(t *testing.T) {
+	var got struct {
+		Topic string            `json:"topic"`
+		Event map[string]string `json:"event"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/internal/publish", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get(TokenHeader))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/internal/push", "secret")
+	assert.Error(t, client.Publish(context.Background(), "poll:3", nil))
+
+	client.PublishURL = server.URL + "/internal/publish"
+	assert.NoError(t, client.Publish(context.Background(), "poll:3", map[string]string{"type": "poll_results"}))
+	assert.Equal(t, "poll:3", got.Topic)
+	assert.Equal(t, "poll_results", got.Event["type"])
+}
+
+func TestClient_Messages(t *testing.T) {
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get(TokenHeader))
+		switch {
+		case r.URL.Path != "/internal/messages/5":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"id":5,"user_id":9,"username":"bob","message":"spam"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/internal/push", "secret")
+	_, err := client.GetMessage(context.Background(), 5)
+	assert.Error(t, err)
+
+	client.MessagesURL = server.URL + "/internal/messages"
+	msg, err := client.GetMessage(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), msg.UserID)
+
+	_, err = client.GetMessage(context.Background(), 6)
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+
+	assert.NoError(t, client.DeleteMessage(context.Background(), 5))
+	assert.True(t, deleted)
+	assert.ErrorIs(t, client.DeleteMessage(context.Background(), 6), ErrMessageNotFound)
+}
+
+func TestClient_IsRoomMember(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get(TokenHeader))
+		switch r.URL.Path {
+		case "/internal/rooms/3/members/7":
+			w.WriteHeader(http.StatusOK)
+		case "/internal/rooms/3/members/8":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/internal/push", "secret")
+	_, err := client.IsRoomMember(context.Background(), 3, 7)
+	assert.Error(t, err)
+
+	client.RoomsURL = server.URL + "/internal/rooms"
+	member, err := client.IsRoomMember(context.Background(), 3, 7)
+	assert.NoError(t, err)
+	assert.True(t, member)
+
+	member, err = client.IsRoomMember(context.Background(), 3, 8)
+	assert.NoError(t, err)
+	assert.False(t, member)
+
+	_, err = client.IsRoomMember(context.Background(), 4, 7)
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package diff
+
+import "strings"
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Line - одна строка построчного диффа
+type Line struct {
+	Op   Op     `json:"op" example:"insert"`
+	Text string `json:"text" example:"new line"`
+}
+
+// MaxCells ограничивает таблицу НОП: число изменившихся строк a, умноженное на число
+// изменившихся строк b (общие начало и конец не считаются). Если изменения больше, они
+// выводятся одним блоком удаления старых строк и вставки новых - такой дифф хуже читается,
+// зато строится за линейное время и память на любых входных данных.
+const MaxCells = 1 << 20
+
+// Lines строит построчный дифф между a и b на основе наибольшей общей подпоследовательности
+func Lines(a, b string) []Line {
+	x := splitLines(a)
+	y := splitLines(b)
+
+	// Совпадающие начало и конец в поиске НОП не участвуют
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, max(len(x), len(y)))
+	for _, line := range x[:prefix] {
+		result = append(result, Line{Op: OpEqual, Text: line})
+	}
+	result = appendChanges(result, x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])
+	for _, line := range x[len(x)-suffix:] {
+		result = append(result, Line{Op: OpEqual, Text: line})
+	}
+	return result
+}
+
+// appendChanges добавляет к result дифф между x и y
+func appendChanges(result []Line, x, y []string) []Line {
+	if int64(len(x))*int64(len(y)) > MaxCells {
+		for _, line := range x {
+			result = append(result, Line{Op: OpDelete, Text: line})
+		}
+		for _, line := range y {
+			result = append(result, Line{Op: OpInsert, Text: line})
+		}
+		return result
+	}
+
+	// lcs[i*w+j] - длина НОП для x[i:] и y[j:]
+	w := len(y) + 1
+	lcs := make([]int32, (len(x)+1)*w)
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i*w+j] = lcs[(i+1)*w+j+1] + 1
+			} else {
+				lcs[i*w+j] = max(lcs[(i+1)*w+j], lcs[i*w+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			result = append(result, Line{Op: OpEqual, Text: x[i]})
+			i++
+			j++
+		case lcs[(i+1)*w+j] >= lcs[i*w+j+1]:
+			result = append(result, Line{Op: OpDelete, Text: x[i]})
+			i++
+		default:
+			result = append(result, Line{Op: OpInsert, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		result = append(result, Line{Op: OpDelete, Text: x[i]})
+	}
+	for ; j < len(y); j++ {
+		result = append(result, Line{Op: OpInsert, Text: y[j]})
+	}
+	return result
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
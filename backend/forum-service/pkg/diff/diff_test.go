@@ -0,0 +1,73 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Line
+	}{
+		{
+			name: "Equal",
+			a:    "one\ntwo",
+			b:    "one\ntwo",
+			want: []Line{{OpEqual, "one"}, {OpEqual, "two"}},
+		},
+		{
+			name: "Replace middle line",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			want: []Line{{OpEqual, "one"}, {OpDelete, "two"}, {OpInsert, "2"}, {OpEqual, "three"}},
+		},
+		{
+			name: "Append",
+			a:    "one",
+			b:    "one\ntwo",
+			want: []Line{{OpEqual, "one"}, {OpInsert, "two"}},
+		},
+		{
+			name: "From empty",
+			a:    "",
+			b:    "one",
+			want: []Line{{OpInsert, "one"}},
+		},
+		{
+			name: "CRLF is normalized",
+			a:    "one\r\ntwo",
+			b:    "one\ntwo",
+			want: []Line{{OpEqual, "one"}, {OpEqual, "two"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Lines(tt.a, tt.b))
+		})
+	}
+}
+
+func TestLines_LargeInput(t *testing.T) {
+	// Изменившиеся блоки больше MaxCells выводятся целиком, общие начало и конец сохраняются
+	var a, b []string
+	for i := 0; i < 2000; i++ {
+		a = append(a, fmt.Sprintf("old %d", i))
+		b = append(b, fmt.Sprintf("new %d", i))
+	}
+	old := "header\n" + strings.Join(a, "\n") + "\nfooter"
+	updated := "header\n" + strings.Join(b, "\n") + "\nfooter"
+
+	got := Lines(old, updated)
+	assert.Len(t, got, 4002)
+	assert.Equal(t, Line{OpEqual, "header"}, got[0])
+	assert.Equal(t, Line{OpDelete, "old 0"}, got[1])
+	assert.Equal(t, Line{OpDelete, "old 1999"}, got[2000])
+	assert.Equal(t, Line{OpInsert, "new 0"}, got[2001])
+	assert.Equal(t, Line{OpEqual, "footer"}, got[4001])
+}
@@ -0,0 +1,111 @@
+// Package mailer отправляет письма через подключаемые транспорты: SMTP или файлы на диске.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Message - письмо в виде простого текста
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// Headers - дополнительные заголовки, например List-Unsubscribe
+	Headers map[string]string
+}
+
+// Sender отправляет письмо
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Build собирает письмо в формате RFC 5322
+func Build(from string, msg Message, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+
+	keys := make([]string, 0, len(msg.Headers))
+	for k := range msg.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, msg.Headers[k])
+	}
+
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+// SMTPSender отправляет письма через SMTP-сервер; при пустом Username аутентификация не используется
+type SMTPSender struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	return &SMTPSender{
+		Addr:     host + ":" + port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		host := s.Addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	return smtp.SendMail(s.Addr, auth, s.From, []string{msg.To}, Build(s.From, msg, time.Now()))
+}
+
+// FileSender сохраняет письма в каталог в виде .eml файлов - для локальной разработки и тестов
+type FileSender struct {
+	Dir  string
+	From string
+	seq  uint64
+}
+
+func NewFileSender(dir, from string) *FileSender {
+	return &FileSender{Dir: dir, From: from}
+}
+
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%04d.eml", now.Format("20060102T150405.000000000"), atomic.AddUint64(&s.seq, 1))
+	return os.WriteFile(filepath.Join(s.Dir, name), Build(s.From, msg, now), 0o644)
+}
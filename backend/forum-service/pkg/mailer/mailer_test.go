@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	raw := string(Build("forum@example.com", Message{
+		To:      "alice@example.com",
+		Subject: "Новые комментарии",
+		Body:    "line 1\nline 2",
+		Headers: map[string]string{"List-Unsubscribe": "<https://example.com/u>"},
+	}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	assert.Contains(t, raw, "From: forum@example.com\r\n")
+	assert.Contains(t, raw, "To: alice@example.com\r\n")
+	assert.Contains(t, raw, "Subject: =?utf-8?q?")
+	assert.Contains(t, raw, "List-Unsubscribe: <https://example.com/u>\r\n")
+	assert.True(t, strings.HasSuffix(raw, "\r\n\r\nline 1\r\nline 2"))
+}
+
+func TestFileSender_Send(t *testing.T) {
+	dir := t.TempDir()
+	sender := NewFileSender(dir, "forum@example.com")
+
+	assert.NoError(t, sender.Send(context.Background(), Message{To: "a@example.com", Subject: "one", Body: "1"}))
+	assert.NoError(t, sender.Send(context.Background(), Message{To: "b@example.com", Subject: "two", Body: "2"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	data, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "To: a@example.com")
+}
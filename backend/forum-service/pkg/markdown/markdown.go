@@ -0,0 +1,64 @@
+// Package markdown преобразует пользовательский Markdown в безопасный HTML и простой текст.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	// md разбирает CommonMark с таблицами, зачеркиванием, автоссылками и @упоминаниями.
+	// Сырой HTML из исходника не пропускается (goldmark экранирует его по умолчанию).
+	md = goldmark.New(
+		goldmark.WithExtensions(
+			// Выравнивание колонок через атрибут align: inline-стили политика не пропускает
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.Linkify,
+			mentionExtension{},
+		),
+	)
+
+	// policy - белый список тегов и атрибутов для отрисованного HTML
+	policy = newPolicy()
+
+	// textPolicy удаляет всю разметку, оставляя текст
+	textPolicy = bluemonday.StrictPolicy()
+
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	p.AllowURLSchemes("http", "https", "mailto")
+	// Язык блока кода (```go) goldmark передает как class="language-go"
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^mention$`)).OnElements("a")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	return p
+}
+
+// Render отрисовывает Markdown в HTML, прошедший санитизацию
+func Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
+
+// Plain возвращает текст отрисованного HTML без разметки
+func Plain(renderedHTML string) string {
+	text := html.UnescapeString(textPolicy.Sanitize(renderedHTML))
+	text = blankLines.ReplaceAllString(strings.TrimSpace(text), "\n\n")
+	return text
+}
@@ -0,0 +1,90 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Emphasis",
+			source:   "**bold** and _italic_",
+			contains: []string{"<strong>bold</strong>", "<em>italic</em>"},
+		},
+		{
+			name:     "Table",
+			source:   "| a | b |\n|---|:-:|\n| 1 | 2 |",
+			contains: []string{"<table>", "<th>a</th>", `<td align="center">2</td>`},
+		},
+		{
+			name:     "Code fence",
+			source:   "```go\nfmt.Println(\"<hi>\")\n```",
+			contains: []string{`<code class="language-go">`, "&lt;hi&gt;"},
+		},
+		{
+			name:     "Raw script is not rendered",
+			source:   "hello <script>alert(1)</script>",
+			excludes: []string{"<script"},
+		},
+		{
+			name:     "Links get rel nofollow",
+			source:   "[site](https://example.com)",
+			contains: []string{`href="https://example.com"`, "nofollow"},
+		},
+		{
+			name:     "Javascript links are dropped",
+			source:   "[x](javascript:alert(1))",
+			excludes: []string{"javascript:"},
+		},
+		{
+			name:     "Event handlers are dropped",
+			source:   `<img src="x" onerror="alert(1)">`,
+			excludes: []string{"onerror"},
+		},
+		{
+			name:     "Mention becomes profile link",
+			source:   "thanks @alice_1!",
+			contains: []string{`<a href="/users/alice_1" class="mention"`, ">@alice_1</a>!"},
+		},
+		{
+			name:     "Email is not a mention",
+			source:   "mail bob@example.com",
+			excludes: []string{"/users/"},
+		},
+		{
+			name:     "Mention in code stays text",
+			source:   "`@alice`",
+			contains: []string{"<code>@alice</code>"},
+			excludes: []string{"/users/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			require.NoError(t, err)
+			for _, s := range tt.contains {
+				assert.Contains(t, got, s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, strings.ToLower(got), s)
+			}
+		})
+	}
+}
+
+func TestPlain(t *testing.T) {
+	rendered, err := Render("# Title\n\nSome **bold** text & [a link](https://example.com)")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Title\nSome bold text & a link", Plain(rendered))
+}
@@ -0,0 +1,49 @@
+package markdown
+
+import (
+	"github.com/jaliks17/ffffforum/backend/forum-service/pkg/mention"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// mentionExtension отрисовывает @username ссылкой на профиль пользователя
+type mentionExtension struct{}
+
+func (mentionExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(mentionParser{}, 999),
+	))
+}
+
+type mentionParser struct{}
+
+func (mentionParser) Trigger() []byte {
+	return []byte{'@'}
+}
+
+func (mentionParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	// Как и в mention.Parse: user@example.com и @@name упоминаниями не считаются
+	if prev := block.PrecendingCharacter(); prev < 0x80 && (mention.IsUsernameChar(byte(prev)) || prev == '@') {
+		return nil
+	}
+
+	line, segment := block.PeekLine()
+	n := 1
+	for n < len(line) && mention.IsUsernameChar(line[n]) {
+		n++
+	}
+	if n-1 < 3 {
+		return nil
+	}
+	block.Advance(n)
+
+	link := ast.NewLink()
+	link.Destination = []byte(mention.ProfileURL(string(line[1:n])))
+	link.SetAttributeString("class", []byte("mention"))
+	link.AppendChild(link, ast.NewTextSegment(text.NewSegment(segment.Start, segment.Start+n)))
+	return link
+}
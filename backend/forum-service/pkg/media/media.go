@@ -0,0 +1,145 @@
+// Package media определяет тип загруженных файлов по содержимому, очищает
+// изображения от метаданных и строит миниатюры. Используется только стандартная библиотека.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"mime"
+	"net/http"
+)
+
+var ErrUnsupportedImage = errors.New("unsupported or corrupted image")
+
+const (
+	// JPEGQuality - качество перекодирования фотографий и миниатюр
+	JPEGQuality = 88
+	// MaxPixels защищает от "бомб" - маленьких файлов, которые распаковываются в гигабайты
+	MaxPixels = 50_000_000
+)
+
+// DetectContentType определяет MIME-тип по первым байтам файла, не доверяя имени
+// и заголовку клиента. Возвращает тип без параметров, например "text/plain".
+func DetectContentType(data []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// IsImage сообщает, умеет ли пакет обрабатывать изображение этого типа
+func IsImage(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// Image - очищенное изображение и его размеры
+type Image struct {
+	Data   []byte
+	Width  int
+	Height int
+	image  image.Image
+}
+
+// Sanitize перекодирует изображение, отбрасывая EXIF, XMP, комментарии и прочие метаданные.
+// Поворот из EXIF Orientation применяется к пикселям, чтобы фото с телефона не легло набок.
+// GIF не содержит EXIF и сохраняется как есть, чтобы не потерять анимацию.
+func Sanitize(data []byte, contentType string) (*Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrUnsupportedImage
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	var buf bytes.Buffer
+	switch contentType {
+	case "image/jpeg":
+		img = applyOrientation(img, jpegOrientation(data))
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEGQuality})
+	case "image/png":
+		err = png.Encode(&buf, img)
+	case "image/gif":
+		buf.Write(data)
+	default:
+		return nil, ErrUnsupportedImage
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	return &Image{Data: buf.Bytes(), Width: bounds.Dx(), Height: bounds.Dy(), image: img}, nil
+}
+
+// Thumbnail уменьшает изображение так, чтобы большая сторона не превышала maxSide,
+// и кодирует его: фотографии - в JPEG, остальное - в PNG с сохранением прозрачности.
+// Возвращает данные и их MIME-тип.
+func (img *Image) Thumbnail(maxSide int, contentType string) ([]byte, string, error) {
+	thumb := resize(img.image, maxSide)
+
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&buf, thumb); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// resize уменьшает изображение усреднением пикселей каждой области (box filter).
+// Изображения меньше maxSide не увеличиваются.
+func resize(src image.Image, maxSide int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return src
+	}
+
+	dw, dh := maxSide, maxSide
+	if w > h {
+		dh = max(1, h*maxSide/w)
+	} else {
+		dw = max(1, w*maxSide/h)
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := b.Min.Y+y*h/dh, b.Min.Y+(y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := b.Min.X+x*w/dw, b.Min.X+(x+1)*w/dw
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			// Цвета RGBA() предумножены на альфу; NRGBA хранит их без умножения
+			if a == 0 {
+				continue
+			}
+			dst.Pix[i+0] = uint8(r * 0xff / a)
+			dst.Pix[i+1] = uint8(g * 0xff / a)
+			dst.Pix[i+2] = uint8(bl * 0xff / a)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
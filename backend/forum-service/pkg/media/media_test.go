@@ -0,0 +1,115 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	return img
+}
+
+// withExif вставляет после SOI сегмент APP1 с тегом Orientation и посторонними данными
+func withExif(t *testing.T, jpg []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	binary.Write(&tiff, binary.BigEndian, uint16(42))
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{0x0112, 3})
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{orientation, 0})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+	tiff.WriteString("GPS 55.7558N 37.6173E")
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	require.Equal(t, []byte{0xFF, 0xD8}, jpg[:2])
+	return append(append([]byte{0xFF, 0xD8}, segment...), jpg[2:]...)
+}
+
+func TestDetectContentType(t *testing.T) {
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, testImage(2, 2)))
+
+	assert.Equal(t, "image/png", DetectContentType(pngData.Bytes()))
+	assert.Equal(t, "application/pdf", DetectContentType([]byte("%PDF-1.7\n")))
+	assert.Equal(t, "text/plain", DetectContentType([]byte("just text")))
+	// HTML под видом картинки определяется как HTML
+	assert.Equal(t, "text/html", DetectContentType([]byte("<html><script>alert(1)</script>")))
+	assert.True(t, IsImage("image/gif"))
+	assert.False(t, IsImage("image/svg+xml"))
+}
+
+func TestSanitize_JPEG(t *testing.T) {
+	var jpg bytes.Buffer
+	require.NoError(t, jpeg.Encode(&jpg, testImage(40, 20), nil))
+	data := withExif(t, jpg.Bytes(), 6)
+	assert.Equal(t, 6, jpegOrientation(data))
+
+	img, err := Sanitize(data, "image/jpeg")
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(img.Data, []byte("Exif")))
+	assert.False(t, bytes.Contains(img.Data, []byte("GPS")))
+	// Orientation 6 - поворот на 90 градусов: 40x20 становится 20x40
+	assert.Equal(t, 20, img.Width)
+	assert.Equal(t, 40, img.Height)
+	assert.Equal(t, 1, jpegOrientation(img.Data))
+}
+
+func TestSanitize_PNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(400, 200)))
+
+	img, err := Sanitize(buf.Bytes(), "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, 400, img.Width)
+
+	thumb, contentType, err := img.Thumbnail(100, "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	decoded, err := png.Decode(bytes.NewReader(thumb))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), decoded.Bounds())
+
+	// Маленькие изображения не увеличиваются
+	thumb, _, err = img.Thumbnail(1000, "image/png")
+	require.NoError(t, err)
+	decoded, _ = png.Decode(bytes.NewReader(thumb))
+	assert.Equal(t, 400, decoded.Bounds().Dx())
+
+	_, err = Sanitize([]byte("\x89PNG\r\n\x1a\nbroken"), "image/png")
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+}
+
+func TestApplyOrientation(t *testing.T) {
+	src := testImage(3, 2)
+	corner := src.At(0, 0)
+
+	// Где окажется левый верхний пиксель после каждого преобразования
+	want := map[int]image.Point{
+		2: {2, 0}, 3: {2, 1}, 4: {0, 1},
+		5: {0, 0}, 6: {1, 0}, 7: {1, 2}, 8: {0, 2},
+	}
+	for orientation, p := range want {
+		got := applyOrientation(src, orientation)
+		assert.Equal(t, corner, got.At(p.X, p.Y), "orientation %d", orientation)
+	}
+}
@@ -0,0 +1,50 @@
+// Package mention извлекает @упоминания пользователей из текста.
+package mention
+
+import (
+	"regexp"
+)
+
+// MaxPerText ограничивает число упоминаний, которые учитываются в одном тексте
+const MaxPerText = 20
+
+var (
+	// pattern совпадает с usernameRegex в auth-service (^[a-zA-Z0-9_]{3,}$).
+	// Перед @ не должно быть символа имени, иначе это адрес почты (user@example.com).
+	pattern = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_@])@([a-zA-Z0-9_]{3,})`)
+
+	// Код не отрисовывается ссылками, поэтому упоминания внутри него не считаются
+	codeFence  = regexp.MustCompile("(?s)```.*?(```|$)")
+	inlineCode = regexp.MustCompile("`[^`\n]*`")
+)
+
+// Parse возвращает уникальные имена упомянутых пользователей в порядке появления
+func Parse(text string) []string {
+	text = codeFence.ReplaceAllString(text, "")
+	text = inlineCode.ReplaceAllString(text, "")
+
+	var names []string
+	seen := make(map[string]struct{})
+	for _, m := range pattern.FindAllStringSubmatch(text, -1) {
+		name := m[1]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+		if len(names) == MaxPerText {
+			break
+		}
+	}
+	return names
+}
+
+// IsUsernameChar сообщает, может ли символ входить в имя пользователя
+func IsUsernameChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// ProfileURL - ссылка на профиль, которой отрисовывается упоминание
+func ProfileURL(username string) string {
+	return "/users/" + username
+}
@@ -0,0 +1,38 @@
+package mention
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "Single", text: "hi @alice!", want: []string{"alice"}},
+		{name: "Start of text", text: "@bob_2 look", want: []string{"bob_2"}},
+		{name: "Duplicates keep order", text: "@carol and @alice, @carol", want: []string{"carol", "alice"}},
+		{name: "Too short", text: "@ab", want: nil},
+		{name: "Email is not a mention", text: "write to user@example.com", want: nil},
+		{name: "Double at", text: "@@alice", want: nil},
+		{name: "Inline code", text: "run `@alice` or ping @bob", want: []string{"bob"}},
+		{name: "Code fence", text: "```\n@alice\n```\n@bob", want: []string{"bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.text))
+		})
+	}
+}
+
+func TestParse_Limit(t *testing.T) {
+	text := ""
+	for i := 0; i < MaxPerText+5; i++ {
+		text += " @user" + string(rune('a'+i))
+	}
+	assert.Len(t, Parse(text), MaxPerText)
+}
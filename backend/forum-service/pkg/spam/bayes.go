@@ -0,0 +1,91 @@
+// Package spam содержит локальный наивный байесовский классификатор спама,
+// который обучается на решениях модераторов.
+package spam
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MinSamples - сколько примеров каждого класса нужно, прежде чем модель начнет давать оценки
+const MinSamples = 10
+
+// NaiveBayes - мультиномиальный наивный байесовский классификатор со сглаживанием Лапласа.
+// Безопасен для одновременного использования.
+type NaiveBayes struct {
+	mu     sync.RWMutex
+	words  [2]map[string]int
+	totals [2]int
+	docs   [2]int
+	vocab  map[string]struct{}
+}
+
+const (
+	ham = iota
+	spam
+)
+
+func NewNaiveBayes() *NaiveBayes {
+	return &NaiveBayes{
+		words: [2]map[string]int{make(map[string]int), make(map[string]int)},
+		vocab: make(map[string]struct{}),
+	}
+}
+
+// Train добавляет пример: isSpam=true для спама, false для нормального текста
+func (nb *NaiveBayes) Train(text string, isSpam bool) {
+	class := ham
+	if isSpam {
+		class = spam
+	}
+
+	nb.mu.Lock()
+	defer nb.mu.Unlock()
+	nb.docs[class]++
+	for _, token := range tokenize(text) {
+		nb.words[class][token]++
+		nb.totals[class]++
+		nb.vocab[token] = struct{}{}
+	}
+}
+
+// SpamProbability возвращает вероятность того, что text - спам.
+// ok=false, пока модель не получила MinSamples примеров каждого класса.
+func (nb *NaiveBayes) SpamProbability(text string) (float64, bool) {
+	nb.mu.RLock()
+	defer nb.mu.RUnlock()
+	if nb.docs[ham] < MinSamples || nb.docs[spam] < MinSamples {
+		return 0, false
+	}
+
+	total := float64(nb.docs[ham] + nb.docs[spam])
+	vocab := float64(len(nb.vocab))
+	var logp [2]float64
+	for class := range logp {
+		logp[class] = math.Log(float64(nb.docs[class]) / total)
+		denominator := float64(nb.totals[class]) + vocab
+		for _, token := range tokenize(text) {
+			logp[class] += math.Log((float64(nb.words[class][token]) + 1) / denominator)
+		}
+	}
+
+	// exp(spam) / (exp(spam) + exp(ham)) без переполнения на длинных текстах
+	return 1 / (1 + math.Exp(logp[ham]-logp[spam])), true
+}
+
+// tokenize разбивает текст на слова в нижнем регистре; слова короче двух символов отбрасываются
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := fields[:0]
+	for _, f := range fields {
+		if utf8.RuneCountInString(f) >= 2 {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
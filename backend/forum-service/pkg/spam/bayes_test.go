@@ -0,0 +1,30 @@
+package spam
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaiveBayes(t *testing.T) {
+	nb := NewNaiveBayes()
+	_, ok := nb.SpamProbability("anything")
+	assert.False(t, ok)
+
+	for i := 0; i < MinSamples; i++ {
+		nb.Train(fmt.Sprintf("cheap casino bonus, win money now %d", i), true)
+		nb.Train(fmt.Sprintf("how do I configure the go module cache %d", i), false)
+	}
+
+	p, ok := nb.SpamProbability("Win a casino BONUS today")
+	assert.True(t, ok)
+	assert.Greater(t, p, 0.9)
+
+	p, _ = nb.SpamProbability("configure go module")
+	assert.Less(t, p, 0.1)
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"привет", "go", "42"}, tokenize("Привет, Go! a 42"))
+}
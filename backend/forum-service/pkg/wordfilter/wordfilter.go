@@ -0,0 +1,113 @@
+// Package wordfilter ищет запрещенные слова и выражения в тексте, маскирует их
+// и вычисляет признаки спама: число ссылок и отпечаток текста для поиска дубликатов.
+package wordfilter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxPatternLength ограничивает длину слова или регулярного выражения в правиле
+const MaxPatternLength = 200
+
+var ErrInvalidPattern = errors.New("invalid filter pattern")
+
+// Pattern - скомпилированное правило фильтра. Слово (или фраза) совпадает только целиком
+// и без учета регистра, регулярное выражение - в любом месте текста.
+type Pattern struct {
+	re   *regexp.Regexp
+	word bool
+}
+
+// Compile компилирует слово или регулярное выражение (синтаксис RE2) без учета регистра
+func Compile(pattern string, isRegex bool) (*Pattern, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || utf8.RuneCountInString(pattern) > MaxPatternLength {
+		return nil, ErrInvalidPattern
+	}
+	if !isRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, ErrInvalidPattern
+	}
+	return &Pattern{re: re, word: !isRegex}, nil
+}
+
+// Find возвращает байтовые границы совпадений [start, end]
+func (p *Pattern) Find(text string) [][]int {
+	matches := p.re.FindAllStringIndex(text, -1)
+	if !p.word {
+		return matches
+	}
+
+	// RE2 не поддерживает \b для кириллицы, поэтому границы слова проверяются вручную
+	spans := matches[:0]
+	for _, m := range matches {
+		if m[0] == m[1] {
+			continue
+		}
+		before, _ := utf8.DecodeLastRuneInString(text[:m[0]])
+		after, _ := utf8.DecodeRuneInString(text[m[1]:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+		spans = append(spans, m)
+	}
+	return spans
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// Mask заменяет каждую букву в spans на '*'. Пересекающиеся границы допустимы.
+func Mask(text string, spans [][]int) string {
+	if len(spans) == 0 {
+		return text
+	}
+	masked := make([]bool, len(text))
+	for _, s := range spans {
+		for i := s[0]; i < s[1]; i++ {
+			masked[i] = true
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for i, r := range text {
+		if masked[i] && !unicode.IsSpace(r) {
+			b.WriteByte('*')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// linkPattern находит адреса со схемой и без нее (example.com/path, www.example.com)
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>()]+|\b[a-z0-9][a-z0-9-]*(?:\.[a-z0-9-]+)*\.(?:com|net|org|ru|io|info|biz|xyz|top|me|co)\b(?:/[^\s<>()]*)?`)
+
+// CountLinks возвращает число ссылок в тексте
+func CountLinks(text string) int {
+	return len(linkPattern.FindAllStringIndex(text, -1))
+}
+
+// Fingerprint - отпечаток текста для поиска дубликатов: регистр, пунктуация и пробелы
+// не учитываются, поэтому мелкие правки не обходят проверку.
+func Fingerprint(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,44 @@
+package wordfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPattern_Find(t *testing.T) {
+	word, err := Compile("спам", false)
+	assert.NoError(t, err)
+	assert.Len(t, word.Find("Это СПАМ, и спамер не пройдет"), 1)
+	assert.Empty(t, word.Find("антиспам"))
+
+	phrase, err := Compile("free money", false)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{4, 14}}, phrase.Find("Get FREE MONEY now"))
+
+	re, err := Compile(`casino\d*`, true)
+	assert.NoError(t, err)
+	assert.Len(t, re.Find("casino777 and megacasino"), 2)
+
+	_, err = Compile("(", true)
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+	_, err = Compile("  ", false)
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
+
+func TestMask(t *testing.T) {
+	word, _ := Compile("дурак", false)
+	text := "Сам ты дурак!"
+	assert.Equal(t, "Сам ты *****!", Mask(text, word.Find(text)))
+	assert.Equal(t, "clean", Mask("clean", nil))
+}
+
+func TestCountLinks(t *testing.T) {
+	assert.Equal(t, 3, CountLinks("see https://a.io/x, www.b.org and shop.example.com/buy"))
+	assert.Equal(t, 0, CountLinks("version 1.24 is out"))
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Equal(t, Fingerprint("Buy cheap pills!"), Fingerprint("buy   CHEAP pills"))
+	assert.NotEqual(t, Fingerprint("buy cheap pills"), Fingerprint("buy cheap bills"))
+}
@@ -906,6 +906,216 @@ func (x *ValidateSessionResponse) GetUserRole() string {
 	return ""
 }
 
+type GetUsersByUsernamesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Usernames     []string               `protobuf:"bytes,1,rep,name=usernames,proto3" json:"usernames,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersByUsernamesRequest) Reset() {
+	*x = GetUsersByUsernamesRequest{}
+	mi := &file_auth_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersByUsernamesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersByUsernamesRequest) ProtoMessage() {}
+
+func (x *GetUsersByUsernamesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersByUsernamesRequest.ProtoReflect.Descriptor instead.
+func (*GetUsersByUsernamesRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetUsersByUsernamesRequest) GetUsernames() []string {
+	if x != nil {
+		return x.Usernames
+	}
+	return nil
+}
+
+type GetUsersByUsernamesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersByUsernamesResponse) Reset() {
+	*x = GetUsersByUsernamesResponse{}
+	mi := &file_auth_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersByUsernamesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersByUsernamesResponse) ProtoMessage() {}
+
+func (x *GetUsersByUsernamesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersByUsernamesResponse.ProtoReflect.Descriptor instead.
+func (*GetUsersByUsernamesResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetUsersByUsernamesResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+// SuspendUser блокирует пользователя до until; token - сессия модератора.
+// Пустой или прошедший until снимает блокировку.
+type SuspendUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuspendUserRequest) Reset() {
+	*x = SuspendUserRequest{}
+	mi := &file_auth_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuspendUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuspendUserRequest) ProtoMessage() {}
+
+func (x *SuspendUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuspendUserRequest.ProtoReflect.Descriptor instead.
+func (*SuspendUserRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SuspendUserRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *SuspendUserRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SuspendUserRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *SuspendUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SuspendUserResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SuspendedUntil *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=suspended_until,json=suspendedUntil,proto3" json:"suspended_until,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SuspendUserResponse) Reset() {
+	*x = SuspendUserResponse{}
+	mi := &file_auth_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuspendUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuspendUserResponse) ProtoMessage() {}
+
+func (x *SuspendUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuspendUserResponse.ProtoReflect.Descriptor instead.
+func (*SuspendUserResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SuspendUserResponse) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SuspendUserResponse) GetSuspendedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SuspendedUntil
+	}
+	return nil
+}
+
 var File_auth_proto protoreflect.FileDescriptor
 
 const file_auth_proto_rawDesc = "" +
@@ -968,7 +1178,20 @@ const file_auth_proto_rawDesc = "" +
 	"\x17ValidateSessionResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x1b\n" +
-	"\tuser_role\x18\x03 \x01(\tR\buserRole2\xbf\x04\n" +
+	"\tuser_role\x18\x03 \x01(\tR\buserRole\":\n" +
+	"\x1aGetUsersByUsernamesRequest\x12\x1c\n" +
+	"\tusernames\x18\x01 \x03(\tR\tusernames\"?\n" +
+	"\x1bGetUsersByUsernamesResponse\x12 \n" +
+	"\x05users\x18\x01 \x03(\v2\n" +
+	".auth.UserR\x05users\"\x8d\x01\n" +
+	"\x12SuspendUserRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x120\n" +
+	"\x05until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"s\n" +
+	"\x13SuspendUserResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12C\n" +
+	"\x0fsuspended_until\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0esuspendedUntil2\xdf\x05\n" +
 	"\vAuthService\x125\n" +
 	"\bRegister\x12\x15.auth.RegisterRequest\x1a\x12.auth.UserResponse\x120\n" +
 	"\x05Login\x12\x12.auth.LoginRequest\x1a\x13.auth.TokenResponse\x12J\n" +
@@ -978,7 +1201,9 @@ const file_auth_proto_rawDesc = "" +
 	"\x0eGetUserProfile\x12\x1b.auth.GetUserProfileRequest\x1a\x1c.auth.GetUserProfileResponse\x123\n" +
 	"\x06SignIn\x12\x13.auth.SignInRequest\x1a\x14.auth.SignInResponse\x123\n" +
 	"\x06SignUp\x12\x13.auth.SignUpRequest\x1a\x14.auth.SignUpResponse\x12N\n" +
-	"\x0fValidateSession\x12\x1c.auth.ValidateSessionRequest\x1a\x1d.auth.ValidateSessionResponseB\x0fZ\rbackend/protob\x06proto3"
+	"\x0fValidateSession\x12\x1c.auth.ValidateSessionRequest\x1a\x1d.auth.ValidateSessionResponse\x12Z\n" +
+	"\x13GetUsersByUsernames\x12 .auth.GetUsersByUsernamesRequest\x1a!.auth.GetUsersByUsernamesResponse\x12B\n" +
+	"\vSuspendUser\x12\x18.auth.SuspendUserRequest\x1a\x19.auth.SuspendUserResponseB\x0fZ\rbackend/protob\x06proto3"
 
 var (
 	file_auth_proto_rawDescOnce sync.Once
@@ -992,53 +1217,64 @@ func file_auth_proto_rawDescGZIP() []byte {
 	return file_auth_proto_rawDescData
 }
 
-var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_auth_proto_goTypes = []any{
-	(*RegisterRequest)(nil),         // 0: auth.RegisterRequest
-	(*LoginRequest)(nil),            // 1: auth.LoginRequest
-	(*ValidateTokenRequest)(nil),    // 2: auth.ValidateTokenRequest
-	(*RefreshTokenRequest)(nil),     // 3: auth.RefreshTokenRequest
-	(*LogoutRequest)(nil),           // 4: auth.LogoutRequest
-	(*UserResponse)(nil),            // 5: auth.UserResponse
-	(*TokenResponse)(nil),           // 6: auth.TokenResponse
-	(*SuccessResponse)(nil),         // 7: auth.SuccessResponse
-	(*User)(nil),                    // 8: auth.User
-	(*GetUserProfileRequest)(nil),   // 9: auth.GetUserProfileRequest
-	(*GetUserProfileResponse)(nil),  // 10: auth.GetUserProfileResponse
-	(*SignInRequest)(nil),           // 11: auth.SignInRequest
-	(*SignInResponse)(nil),          // 12: auth.SignInResponse
-	(*SignUpRequest)(nil),           // 13: auth.SignUpRequest
-	(*SignUpResponse)(nil),          // 14: auth.SignUpResponse
-	(*ValidateSessionRequest)(nil),  // 15: auth.ValidateSessionRequest
-	(*ValidateSessionResponse)(nil), // 16: auth.ValidateSessionResponse
-	(*timestamppb.Timestamp)(nil),   // 17: google.protobuf.Timestamp
+	(*RegisterRequest)(nil),             // 0: auth.RegisterRequest
+	(*LoginRequest)(nil),                // 1: auth.LoginRequest
+	(*ValidateTokenRequest)(nil),        // 2: auth.ValidateTokenRequest
+	(*RefreshTokenRequest)(nil),         // 3: auth.RefreshTokenRequest
+	(*LogoutRequest)(nil),               // 4: auth.LogoutRequest
+	(*UserResponse)(nil),                // 5: auth.UserResponse
+	(*TokenResponse)(nil),               // 6: auth.TokenResponse
+	(*SuccessResponse)(nil),             // 7: auth.SuccessResponse
+	(*User)(nil),                        // 8: auth.User
+	(*GetUserProfileRequest)(nil),       // 9: auth.GetUserProfileRequest
+	(*GetUserProfileResponse)(nil),      // 10: auth.GetUserProfileResponse
+	(*SignInRequest)(nil),               // 11: auth.SignInRequest
+	(*SignInResponse)(nil),              // 12: auth.SignInResponse
+	(*SignUpRequest)(nil),               // 13: auth.SignUpRequest
+	(*SignUpResponse)(nil),              // 14: auth.SignUpResponse
+	(*ValidateSessionRequest)(nil),      // 15: auth.ValidateSessionRequest
+	(*ValidateSessionResponse)(nil),     // 16: auth.ValidateSessionResponse
+	(*GetUsersByUsernamesRequest)(nil),  // 17: auth.GetUsersByUsernamesRequest
+	(*GetUsersByUsernamesResponse)(nil), // 18: auth.GetUsersByUsernamesResponse
+	(*SuspendUserRequest)(nil),          // 19: auth.SuspendUserRequest
+	(*SuspendUserResponse)(nil),         // 20: auth.SuspendUserResponse
+	(*timestamppb.Timestamp)(nil),       // 21: google.protobuf.Timestamp
 }
 var file_auth_proto_depIdxs = []int32{
-	17, // 0: auth.User.created_at:type_name -> google.protobuf.Timestamp
+	21, // 0: auth.User.created_at:type_name -> google.protobuf.Timestamp
 	8,  // 1: auth.GetUserProfileResponse.user:type_name -> auth.User
-	0,  // 2: auth.AuthService.Register:input_type -> auth.RegisterRequest
-	1,  // 3: auth.AuthService.Login:input_type -> auth.LoginRequest
-	2,  // 4: auth.AuthService.ValidateToken:input_type -> auth.ValidateTokenRequest
-	3,  // 5: auth.AuthService.RefreshToken:input_type -> auth.RefreshTokenRequest
-	4,  // 6: auth.AuthService.Logout:input_type -> auth.LogoutRequest
-	9,  // 7: auth.AuthService.GetUserProfile:input_type -> auth.GetUserProfileRequest
-	11, // 8: auth.AuthService.SignIn:input_type -> auth.SignInRequest
-	13, // 9: auth.AuthService.SignUp:input_type -> auth.SignUpRequest
-	15, // 10: auth.AuthService.ValidateSession:input_type -> auth.ValidateSessionRequest
-	5,  // 11: auth.AuthService.Register:output_type -> auth.UserResponse
-	6,  // 12: auth.AuthService.Login:output_type -> auth.TokenResponse
-	16, // 13: auth.AuthService.ValidateToken:output_type -> auth.ValidateSessionResponse
-	6,  // 14: auth.AuthService.RefreshToken:output_type -> auth.TokenResponse
-	7,  // 15: auth.AuthService.Logout:output_type -> auth.SuccessResponse
-	10, // 16: auth.AuthService.GetUserProfile:output_type -> auth.GetUserProfileResponse
-	12, // 17: auth.AuthService.SignIn:output_type -> auth.SignInResponse
-	14, // 18: auth.AuthService.SignUp:output_type -> auth.SignUpResponse
-	16, // 19: auth.AuthService.ValidateSession:output_type -> auth.ValidateSessionResponse
-	11, // [11:20] is the sub-list for method output_type
-	2,  // [2:11] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	8,  // 2: auth.GetUsersByUsernamesResponse.users:type_name -> auth.User
+	21, // 3: auth.SuspendUserRequest.until:type_name -> google.protobuf.Timestamp
+	21, // 4: auth.SuspendUserResponse.suspended_until:type_name -> google.protobuf.Timestamp
+	0,  // 5: auth.AuthService.Register:input_type -> auth.RegisterRequest
+	1,  // 6: auth.AuthService.Login:input_type -> auth.LoginRequest
+	2,  // 7: auth.AuthService.ValidateToken:input_type -> auth.ValidateTokenRequest
+	3,  // 8: auth.AuthService.RefreshToken:input_type -> auth.RefreshTokenRequest
+	4,  // 9: auth.AuthService.Logout:input_type -> auth.LogoutRequest
+	9,  // 10: auth.AuthService.GetUserProfile:input_type -> auth.GetUserProfileRequest
+	11, // 11: auth.AuthService.SignIn:input_type -> auth.SignInRequest
+	13, // 12: auth.AuthService.SignUp:input_type -> auth.SignUpRequest
+	15, // 13: auth.AuthService.ValidateSession:input_type -> auth.ValidateSessionRequest
+	17, // 14: auth.AuthService.GetUsersByUsernames:input_type -> auth.GetUsersByUsernamesRequest
+	19, // 15: auth.AuthService.SuspendUser:input_type -> auth.SuspendUserRequest
+	5,  // 16: auth.AuthService.Register:output_type -> auth.UserResponse
+	6,  // 17: auth.AuthService.Login:output_type -> auth.TokenResponse
+	16, // 18: auth.AuthService.ValidateToken:output_type -> auth.ValidateSessionResponse
+	6,  // 19: auth.AuthService.RefreshToken:output_type -> auth.TokenResponse
+	7,  // 20: auth.AuthService.Logout:output_type -> auth.SuccessResponse
+	10, // 21: auth.AuthService.GetUserProfile:output_type -> auth.GetUserProfileResponse
+	12, // 22: auth.AuthService.SignIn:output_type -> auth.SignInResponse
+	14, // 23: auth.AuthService.SignUp:output_type -> auth.SignUpResponse
+	16, // 24: auth.AuthService.ValidateSession:output_type -> auth.ValidateSessionResponse
+	18, // 25: auth.AuthService.GetUsersByUsernames:output_type -> auth.GetUsersByUsernamesResponse
+	20, // 26: auth.AuthService.SuspendUser:output_type -> auth.SuspendUserResponse
+	16, // [16:27] is the sub-list for method output_type
+	5,  // [5:16] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_auth_proto_init() }
@@ -1052,7 +1288,7 @@ func file_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_proto_rawDesc), len(file_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -108,18 +108,16 @@ const useWebSocket = (webSocketUrl, apiUrl, token) => {
 
                 // Обработка различных кодов закрытия
                 if (event.code === 1000) {
-                    if (event.reason === 'Connection replaced by new client') {
-                        console.log('useWebSocket Effect: Соединение заменено новым клиентом, пробуем переподключиться...');
-                        // Небольшая задержка перед переподключением
-                        setTimeout(() => {
-                            connect();
-                        }, 100);
-                        return;
-                    }
                     console.log('useWebSocket Effect: Соединение закрыто нормально.');
                     return;
                 }
 
+                // Сервер закрывает лишние соединения, если открыто слишком много вкладок
+                if (event.code === 1008) {
+                    console.log('useWebSocket Effect: Превышен лимит соединений, переподключение не выполняется.');
+                    return;
+                }
+
                 if (event.code === 4001 || event.code === 4002 || event.code === 1006) {
                     console.log('useWebSocket Effect: Соединение закрыто из-за проблем с аутентификацией или аномального закрытия.');
                     return;